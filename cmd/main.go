@@ -1,12 +1,22 @@
 package main
 
 import (
+	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/internal/bootstrap"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"os"
 )
 
 func main() {
+	// Ejecutar el comando de migraciones sin iniciar el servidor
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := bootstrap.RunMigrateCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Crear e inicializar la aplicación
 	app := bootstrap.NewApplication()
 	if err := app.Initialize(); err != nil {
@@ -3,7 +3,10 @@ package setup
 import (
 	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/config/drivers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
 	"github.com/go-co-op/gocron"
 	"time"
 
@@ -19,7 +22,7 @@ type JobConfig struct {
 	Environment string
 }
 
-func SetupJobs(contingencyService contingency.ContingencyManager, ambientCode string, connection *drivers.DbConnection) error {
+func SetupJobs(contingencyService contingency.ContingencyManager, webhookService webhook.WebhookManager, notificationService notification.NotificationManager, archiveService archive.ArchiveManager, ambientCode string, connection *drivers.DbConnection) error {
 	scheduler := gocron.NewScheduler(time.UTC)
 	job := jobs.NewRetransmissionJob(contingencyService, connection)
 
@@ -49,6 +52,27 @@ func SetupJobs(contingencyService contingency.ContingencyManager, ambientCode st
 		return err
 	}
 
+	if err := ScheduleWebhookDeliveryJob(scheduler, jobs.NewWebhookDeliveryJob(webhookService)); err != nil {
+		logs.Error("Failed to setup webhook delivery job", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return err
+	}
+
+	if err := ScheduleNotificationJobs(scheduler, jobs.NewNotificationScanJob(notificationService), jobs.NewNotificationDeliveryJob(notificationService)); err != nil {
+		logs.Error("Failed to setup notification jobs", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return err
+	}
+
+	if err := ScheduleArchiveCleanupJob(scheduler, jobs.NewArchiveCleanupJob(archiveService)); err != nil {
+		logs.Error("Failed to setup archive cleanup job", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return err
+	}
+
 	logs.Info("Jobs scheduled successfully", map[string]interface{}{
 		"environment": jobConfig.Environment,
 		"startTime":   jobConfig.StartTime,
@@ -75,3 +99,38 @@ func ScheduleContingencyJob(scheduler *gocron.Scheduler, job *jobs.Retransmissio
 
 	return nil
 }
+
+// ScheduleWebhookDeliveryJob programa el reintento de las entregas de webhooks pendientes cada minuto, sin importar el
+// ambiente porque los reintentos no dependen de la disponibilidad de Hacienda
+func ScheduleWebhookDeliveryJob(scheduler *gocron.Scheduler, job *jobs.WebhookDeliveryJob) error {
+	_, err := scheduler.Every(1).Minutes().Do(job.Execute)
+	if err != nil {
+		return fmt.Errorf("failed to schedule webhook delivery job: %w", err)
+	}
+
+	return nil
+}
+
+// ScheduleNotificationJobs programa la revisión de las alertas que dependen del tiempo cada hora y el reintento de las
+// notificaciones pendientes cada minuto
+func ScheduleNotificationJobs(scheduler *gocron.Scheduler, scanJob *jobs.NotificationScanJob, deliveryJob *jobs.NotificationDeliveryJob) error {
+	if _, err := scheduler.Every(1).Hours().Do(scanJob.Execute); err != nil {
+		return fmt.Errorf("failed to schedule notification scan job: %w", err)
+	}
+
+	if _, err := scheduler.Every(1).Minutes().Do(deliveryJob.Execute); err != nil {
+		return fmt.Errorf("failed to schedule notification delivery job: %w", err)
+	}
+
+	return nil
+}
+
+// ScheduleArchiveCleanupJob programa cada hora la eliminación de los archivos de exportación que superaron su tiempo
+// de retención
+func ScheduleArchiveCleanupJob(scheduler *gocron.Scheduler, job *jobs.ArchiveCleanupJob) error {
+	if _, err := scheduler.Every(1).Hours().Do(job.Execute); err != nil {
+		return fmt.Errorf("failed to schedule archive cleanup job: %w", err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	errPackage "github.com/MarlonG1/api-facturacion-sv/config/error"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"gorm.io/gorm"
+	gormTracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 type DriverConfig interface {
@@ -39,6 +40,13 @@ func (d *DbConnection) Open() error {
 		return d.Err
 	}
 
+	// Crear un span por cada consulta, las consultas se registran sin sus valores para no exponer datos de los contribuyentes
+	if err := d.Db.Use(gormTracing.NewPlugin(gormTracing.WithoutMetrics(), gormTracing.WithoutQueryVariables())); err != nil {
+		logs.Warn("Failed to register database tracing plugin", map[string]interface{}{
+			"Database error": err.Error(),
+		})
+	}
+
 	logs.Info("Database connection has been set successfully", map[string]interface{}{
 		"Database type:": d.Driver.GetDriverName(),
 		"Database host":  d.Driver.GetHost(),
@@ -0,0 +1,37 @@
+package drivers
+
+import (
+	"fmt"
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// SqliteDriver utiliza un archivo SQLite como base de datos, DB_DATABASE contiene la ruta del archivo. El driver está
+// escrito en Go puro por lo que no requiere CGO ni un servidor de base de datos
+type SqliteDriver struct{}
+
+func NewSqliteDriver() *SqliteDriver {
+	return &SqliteDriver{}
+}
+
+func (s *SqliteDriver) GetDSN() gorm.Dialector {
+	return sqlite.Open(s.GetStringConnection())
+}
+
+// GetStringConnection habilita las llaves foráneas y el modo WAL para lecturas concurrentes. Las transacciones toman
+// el bloqueo de escritura al iniciar (_txlock=immediate) ya que SQLite no admite FOR UPDATE, y las escrituras
+// concurrentes esperan hasta 5 segundos en lugar de fallar
+func (s *SqliteDriver) GetStringConnection() string {
+	return fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate",
+		config.Database.Name,
+	)
+}
+
+func (s *SqliteDriver) GetHost() string {
+	return config.Database.Name
+}
+
+func (s *SqliteDriver) GetDriverName() string {
+	return "SQLite"
+}
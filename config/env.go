@@ -1,10 +1,12 @@
 package config
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	errPackage "github.com/MarlonG1/api-facturacion-sv/config/error"
 	"github.com/spf13/viper"
+	"net"
 	"reflect"
 	"regexp"
 	"strings"
@@ -110,9 +112,65 @@ var (
 	AvailableDatabaseDrivers = map[string]bool{
 		"mysql":    true,
 		"postgres": true,
+		"sqlite":   true,
 	}
+
+	// AvailableMailTransports contiene los transportes de correo soportados.
+	// "smtp" envía los correos a un servidor SMTP, "outbox" los escribe como archivos .eml en un directorio local (útil en pruebas).
+	AvailableMailTransports = map[string]bool{
+		"smtp":   true,
+		"outbox": true,
+	}
+
+	// AvailableMailEncryptions contiene los tipos de cifrado soportados para la conexión SMTP.
+	AvailableMailEncryptions = map[string]bool{
+		"none":     true,
+		"starttls": true,
+		"tls":      true,
+	}
+)
+
+// DefaultArchivePath directorio, relativo a la raíz del proyecto, donde se almacenan los archivos de exportación de DTE
+// si no se configura ARCHIVE_PATH
+const DefaultArchivePath = "/pkg/shared/archives/"
+
+// DefaultArchiveRetentionDays días que se conservan los archivos de exportación si no se configura ARCHIVE_RETENTION_DAYS
+const DefaultArchiveRetentionDays = 7
+
+// DefaultRefreshLifetime días de vida de los refresh tokens si no se configura REFRESH_TOKEN_LIFETIME_DAYS
+const DefaultRefreshLifetime = 30
+
+// Valores por defecto de los límites de solicitudes si no se configuran
+const (
+	DefaultLoginMaxAttemptsPerKey = 5
+	DefaultLoginMaxAttemptsPerIP  = 20
+	DefaultLoginAttemptWindow     = 900
+	DefaultLoginLockout           = 900
+	DefaultDTERequestsPerMinute   = 120
+)
+
+// Valores por defecto del tracing si no se configuran
+const (
+	DefaultTracingServiceName = "api-facturacion-sv"
+	DefaultTracingSampleRatio = 1.0
+)
+
+// Valores por defecto de las verificaciones de salud si no se configuran
+const (
+	DefaultHealthCheckTimeout = 3
+	DefaultHealthCacheTTL     = 15
+)
+
+// Formatos de los logs, se configuran con LOG_FORMAT
+const (
+	LogFormatText    = "text"
+	LogFormatJSON    = "json"
+	DefaultLogFormat = LogFormatText
 )
 
+// testingVaultMasterKeys llave maestra del vault de credenciales utilizada únicamente en el entorno de pruebas
+const testingVaultMasterKeys = "v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
 var EnvConfig *envConfig
 var Server *server
 var Database *database
@@ -120,6 +178,14 @@ var Redis *redis
 var Log *log
 var Signer *signer
 var MHPaths *mhPaths
+var Mail *mail
+var Archive *archive
+var Vault *vault
+var RateLimit *rateLimit
+var TLS *tls
+var Metrics *metrics
+var Tracing *tracing
+var Health *health
 
 // InitEnvTesting inicializa la configuración del entorno de pruebas
 func InitEnvTesting() {
@@ -130,12 +196,32 @@ func InitEnvTesting() {
 	Log = &EnvConfig.Log
 	Signer = &EnvConfig.Signer
 	MHPaths = &EnvConfig.MHPaths
+	Mail = &EnvConfig.Mail
+	Archive = &EnvConfig.Archive
+	Vault = &EnvConfig.Vault
+	RateLimit = &EnvConfig.RateLimit
+	TLS = &EnvConfig.TLS
+	Metrics = &EnvConfig.Metrics
+	Tracing = &EnvConfig.Tracing
+	Health = &EnvConfig.Health
 
 	// Configurar a modo de prueba
 	Server.AmbientCode = "00"
-	Log.Path = "/pkg/shared/logs/"
+	Log.Path = "" // Las pruebas solo escriben los logs en la consola
 	Server.Debug = true
 	Server.AppLang = "en"
+	Archive.Path = DefaultArchivePath
+	Archive.RetentionDays = DefaultArchiveRetentionDays
+	Server.RefreshLifetime = DefaultRefreshLifetime
+	Vault.MasterKeys = testingVaultMasterKeys
+	Vault.ActiveKey = "v1"
+	RateLimit.LoginMaxAttemptsPerKey = DefaultLoginMaxAttemptsPerKey
+	RateLimit.LoginMaxAttemptsPerIP = DefaultLoginMaxAttemptsPerIP
+	RateLimit.LoginAttemptWindow = DefaultLoginAttemptWindow
+	RateLimit.LoginLockout = DefaultLoginLockout
+	RateLimit.DTERequestsPerMinute = DefaultDTERequestsPerMinute
+	Health.CheckTimeout = DefaultHealthCheckTimeout
+	Health.CacheTTL = DefaultHealthCacheTTL
 }
 
 // InitEnvConfig inicializa la configuración del archivo .env
@@ -177,6 +263,14 @@ func InitEnvConfig(rootPath string) error {
 	Log = &EnvConfig.Log
 	Signer = &EnvConfig.Signer
 	MHPaths = &EnvConfig.MHPaths
+	Mail = &EnvConfig.Mail
+	Archive = &EnvConfig.Archive
+	Vault = &EnvConfig.Vault
+	RateLimit = &EnvConfig.RateLimit
+	TLS = &EnvConfig.TLS
+	Metrics = &EnvConfig.Metrics
+	Tracing = &EnvConfig.Tracing
+	Health = &EnvConfig.Health
 
 	return nil
 }
@@ -207,6 +301,34 @@ func ValidateConfig() error {
 		return err
 	}
 
+	if err := validateMailFields(); err != nil {
+		return err
+	}
+
+	if err := validateArchiveFields(); err != nil {
+		return err
+	}
+
+	if err := validateVaultFields(); err != nil {
+		return err
+	}
+
+	if err := validateRateLimitFields(); err != nil {
+		return err
+	}
+
+	if err := validateTLSFields(); err != nil {
+		return err
+	}
+
+	if err := validateTracingFields(); err != nil {
+		return err
+	}
+
+	if err := validateHealthFields(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -218,8 +340,9 @@ func validateServerFields() error {
 		"RUNMIGRATION":     true,
 	}
 	v := reflect.ValueOf(EnvConfig.Server)
+	ex := []string{"ADMINAPIKEY", "TRUSTEDPROXIES"}
 
-	if err := validateEnvVariables(v, bt, nil); err != nil {
+	if err := validateEnvVariables(v, bt, ex); err != nil {
 		return err
 	}
 
@@ -231,6 +354,17 @@ func validateServerFields() error {
 		return fmt.Errorf("MH_MAX_BATCH_SIZE must be between 1 and 100")
 	}
 
+	if EnvConfig.Server.RefreshLifetime < 0 {
+		return fmt.Errorf("REFRESH_TOKEN_LIFETIME_DAYS must be a positive number")
+	}
+	if EnvConfig.Server.RefreshLifetime == 0 {
+		EnvConfig.Server.RefreshLifetime = DefaultRefreshLifetime
+	}
+
+	if _, err := ParseTrustedProxies(EnvConfig.Server.TrustedProxies); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -238,6 +372,12 @@ func validateServerFields() error {
 func validateDatabaseFields() error {
 	v := reflect.ValueOf(EnvConfig.Database)
 
+	// SQLite solo requiere la ruta del archivo de la base de datos en DB_DATABASE
+	if EnvConfig.Database.Driver == "sqlite" {
+		ex := []string{"HOST", "PORT", "USER", "PASSWORD", "CHARSET"}
+		return validateEnvVariables(v, nil, ex)
+	}
+
 	if err := validateEnvVariables(v, nil, nil); err != nil {
 		return err
 	}
@@ -284,10 +424,18 @@ func validateLogFields() error {
 	}
 	v := reflect.ValueOf(EnvConfig.Log)
 
-	if err := validateEnvVariables(v, bt, nil); err != nil {
+	if err := validateEnvVariables(v, bt, []string{"FORMAT"}); err != nil {
 		return err
 	}
 
+	switch EnvConfig.Log.Format {
+	case "":
+		EnvConfig.Log.Format = DefaultLogFormat
+	case LogFormatText, LogFormatJSON:
+	default:
+		return fmt.Errorf("LOG_FORMAT must be text or json")
+	}
+
 	return nil
 }
 
@@ -331,6 +479,245 @@ func validateSignerFields() error {
 	return nil
 }
 
+// validateMailFields valida los campos de la estructura Mail, solo se validan si el envío de correos está habilitado
+func validateMailFields() error {
+	if !EnvConfig.Mail.Enabled {
+		return nil
+	}
+
+	bt := map[string]bool{
+		"ENABLED":  true,
+		"AUTOSEND": true,
+	}
+	ex := []string{"HOST", "PORT", "USERNAME", "PASSWORD", "ENCRYPTION", "FROMNAME", "OUTBOXPATH"}
+	v := reflect.ValueOf(EnvConfig.Mail)
+
+	if err := validateEnvVariables(v, bt, ex); err != nil {
+		return err
+	}
+
+	if !AvailableMailTransports[EnvConfig.Mail.Transport] {
+		return fmt.Errorf("MAIL_TRANSPORT must be a valid transport")
+	}
+
+	if EnvConfig.Mail.Transport == "outbox" {
+		if EnvConfig.Mail.OutboxPath == "" {
+			return fmt.Errorf("MAIL_OUTBOX_PATH is required")
+		}
+		return nil
+	}
+
+	if !matchPattern(HostPattern, EnvConfig.Mail.Host) {
+		return fmt.Errorf("MAIL_HOST must be a valid host")
+	}
+
+	if !matchPattern(PortPattern, EnvConfig.Mail.Port) {
+		return fmt.Errorf("MAIL_PORT must be a valid port")
+	}
+
+	if EnvConfig.Mail.Encryption != "" && !AvailableMailEncryptions[EnvConfig.Mail.Encryption] {
+		return fmt.Errorf("MAIL_ENCRYPTION must be a valid encryption")
+	}
+
+	return nil
+}
+
+// validateArchiveFields establece el directorio y la retención por defecto de los archivos de exportación si no se
+// configuraron
+func validateArchiveFields() error {
+	if EnvConfig.Archive.Path == "" {
+		EnvConfig.Archive.Path = DefaultArchivePath
+	}
+
+	if EnvConfig.Archive.RetentionDays < 0 {
+		return fmt.Errorf("ARCHIVE_RETENTION_DAYS must be a positive number")
+	}
+	if EnvConfig.Archive.RetentionDays == 0 {
+		EnvConfig.Archive.RetentionDays = DefaultArchiveRetentionDays
+	}
+
+	return nil
+}
+
+// validateVaultFields valida las llaves maestras del vault de credenciales y establece la versión activa
+func validateVaultFields() error {
+	if EnvConfig.Vault.MasterKeys == "" {
+		return fmt.Errorf("VAULT_MASTER_KEYS is required")
+	}
+
+	keys, order, err := ParseVaultMasterKeys(EnvConfig.Vault.MasterKeys)
+	if err != nil {
+		return err
+	}
+
+	if EnvConfig.Vault.ActiveKey == "" {
+		EnvConfig.Vault.ActiveKey = order[len(order)-1]
+	}
+
+	if _, ok := keys[EnvConfig.Vault.ActiveKey]; !ok {
+		return fmt.Errorf("VAULT_ACTIVE_KEY must be one of the versions defined in VAULT_MASTER_KEYS")
+	}
+
+	return nil
+}
+
+// validateRateLimitFields valida los límites de solicitudes y establece los valores por defecto de los no configurados
+func validateRateLimitFields() error {
+	fields := []struct {
+		name         string
+		value        *int
+		defaultValue int
+	}{
+		{"LOGIN_MAX_ATTEMPTS_PER_KEY", &EnvConfig.RateLimit.LoginMaxAttemptsPerKey, DefaultLoginMaxAttemptsPerKey},
+		{"LOGIN_MAX_ATTEMPTS_PER_IP", &EnvConfig.RateLimit.LoginMaxAttemptsPerIP, DefaultLoginMaxAttemptsPerIP},
+		{"LOGIN_ATTEMPT_WINDOW_SECONDS", &EnvConfig.RateLimit.LoginAttemptWindow, DefaultLoginAttemptWindow},
+		{"LOGIN_LOCKOUT_SECONDS", &EnvConfig.RateLimit.LoginLockout, DefaultLoginLockout},
+		{"DTE_REQUESTS_PER_MINUTE", &EnvConfig.RateLimit.DTERequestsPerMinute, DefaultDTERequestsPerMinute},
+	}
+
+	for _, field := range fields {
+		if *field.value < 0 {
+			return fmt.Errorf("%s must be a positive number", field.name)
+		}
+		if *field.value == 0 {
+			*field.value = field.defaultValue
+		}
+	}
+
+	return nil
+}
+
+// validateTLSFields valida que el certificado y la llave del servidor se configuren juntos, la CA de clientes solo
+// puede configurarse si el servidor utiliza TLS
+func validateTLSFields() error {
+	if (EnvConfig.TLS.CertFile == "") != (EnvConfig.TLS.KeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be configured together")
+	}
+
+	if EnvConfig.TLS.ClientCAFile != "" && EnvConfig.TLS.CertFile == "" {
+		return fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	return nil
+}
+
+// validateTracingFields valida el endpoint del exportador de trazas y establece los valores por defecto de los no
+// configurados
+func validateTracingFields() error {
+	if EnvConfig.Tracing.Endpoint != "" && !matchPattern(URLPattern, EnvConfig.Tracing.Endpoint) {
+		return fmt.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT must be a valid URL")
+	}
+
+	if EnvConfig.Tracing.SampleRatio < 0 || EnvConfig.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if EnvConfig.Tracing.SampleRatio == 0 {
+		EnvConfig.Tracing.SampleRatio = DefaultTracingSampleRatio
+	}
+
+	if EnvConfig.Tracing.ServiceName == "" {
+		EnvConfig.Tracing.ServiceName = DefaultTracingServiceName
+	}
+
+	return nil
+}
+
+// validateHealthFields valida los tiempos de las verificaciones de salud y establece los valores por defecto de los no
+// configurados
+func validateHealthFields() error {
+	fields := []struct {
+		name         string
+		value        *int
+		defaultValue int
+	}{
+		{"HEALTH_CHECK_TIMEOUT_SECONDS", &EnvConfig.Health.CheckTimeout, DefaultHealthCheckTimeout},
+		{"HEALTH_CACHE_SECONDS", &EnvConfig.Health.CacheTTL, DefaultHealthCacheTTL},
+	}
+
+	for _, field := range fields {
+		if *field.value < 0 {
+			return fmt.Errorf("%s must be a positive number", field.name)
+		}
+		if *field.value == 0 {
+			*field.value = field.defaultValue
+		}
+	}
+
+	return nil
+}
+
+// ParseVaultMasterKeys interpreta las llaves maestras del vault con el formato "v1:<llave base64>,v2:<llave base64>".
+// Retorna las llaves por versión y las versiones en el orden en que fueron configuradas, cada llave debe ser de 32 bytes
+func ParseVaultMasterKeys(raw string) (map[string][]byte, []string, error) {
+	keys := make(map[string][]byte)
+	order := make([]string, 0)
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		version, encoded, found := strings.Cut(entry, ":")
+		version = strings.TrimSpace(version)
+		if !found || version == "" {
+			return nil, nil, fmt.Errorf("VAULT_MASTER_KEYS entries must have the format version:key")
+		}
+
+		if _, exists := keys[version]; exists {
+			return nil, nil, fmt.Errorf("VAULT_MASTER_KEYS contains the version %s more than once", version)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			return nil, nil, fmt.Errorf("VAULT_MASTER_KEYS version %s must be a base64 encoded 32 bytes key", version)
+		}
+
+		keys[version] = key
+		order = append(order, version)
+	}
+
+	if len(order) == 0 {
+		return nil, nil, fmt.Errorf("VAULT_MASTER_KEYS is required")
+	}
+
+	return keys, order, nil
+}
+
+// ParseTrustedProxies interpreta los proxies de confianza con el formato "10.0.0.1,172.16.0.0/12". Las IPs sin prefijo
+// se interpretan como un rango de una sola dirección
+func ParseTrustedProxies(raw string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0)
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("TRUSTED_PROXIES entry %s must be a valid IP or CIDR range", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES entry %s must be a valid IP or CIDR range", entry)
+		}
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
+
 // validateEnvVariables valida que los campos de la estructura sean requeridos y del tipo correcto
 func validateEnvVariables(v reflect.Value, bt map[string]bool, exceptions []string) error {
 	t := v.Type()
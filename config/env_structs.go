@@ -2,15 +2,24 @@ package config
 
 // envConfig es una estructura que contiene la configuración del archivo .env
 type envConfig struct {
-	Server   server
-	Database database
-	Redis    redis
-	Log      log
-	Signer   signer
-	MHPaths  mhPaths
+	Server    server
+	Database  database
+	Redis     redis
+	Log       log
+	Signer    signer
+	MHPaths   mhPaths
+	Mail      mail
+	Archive   archive
+	Vault     vault
+	RateLimit rateLimit
+	TLS       tls
+	Metrics   metrics
+	Tracing   tracing
+	Health    health
 }
 
-// server es una estructura que contiene la configuración del servidor
+// server es una estructura que contiene la configuración del servidor. TrustedProxies son las IPs o rangos CIDR,
+// separados por comas, de los proxies de los que se aceptan las cabeceras X-Forwarded-For y X-Real-IP
 type server struct {
 	Port             string `map-structure:"SERVER_PORT"`
 	MaxBatchSize     int    `map-structure:"MH_MAX_BATCH_SIZE"`
@@ -19,8 +28,11 @@ type server struct {
 	Debug            bool   `map-structure:"DEBUG"`
 	RunMigration     bool   `map-structure:"RUN_MIGRATION"`
 	AdminEmail       string `map-structure:"ADMIN_EMAIL"`
+	AdminAPIKey      string `map-structure:"ADMIN_API_KEY"`
 	ForceContingency bool   `map-structure:"FORCE_CONTINGENCY"`
 	AppLang          string `map-structure:"APP_LANG"`
+	RefreshLifetime  int    `map-structure:"REFRESH_TOKEN_LIFETIME_DAYS"`
+	TrustedProxies   string `map-structure:"TRUSTED_PROXIES"`
 }
 
 // database es una estructura que contiene la configuración de la base de datos
@@ -46,6 +58,7 @@ type log struct {
 	Level       string `map-structure:"LOG_LEVEL"`
 	Path        string `map-structure:"LOG_PATH"`
 	FileLogging bool   `map-structure:"LOG_FILE_LOGGING"`
+	Format      string `map-structure:"LOG_FORMAT"`
 }
 
 // signer es una estructura que contiene la configuración del firmante
@@ -64,3 +77,74 @@ type mhPaths struct {
 	ContingencyURL          string `map-structure:"MH_CONTINGENCY_URL"`
 	NullifyURL              string `map-structure:"MH_NULLIFY_URL"`
 }
+
+// mail es una estructura que contiene la configuración del envío de correos electrónicos
+type mail struct {
+	Enabled    bool   `map-structure:"MAIL_ENABLED"`
+	AutoSend   bool   `map-structure:"MAIL_AUTO_SEND"`
+	Transport  string `map-structure:"MAIL_TRANSPORT"`
+	Host       string `map-structure:"MAIL_HOST"`
+	Port       string `map-structure:"MAIL_PORT"`
+	Username   string `map-structure:"MAIL_USERNAME"`
+	Password   string `map-structure:"MAIL_PASSWORD"`
+	Encryption string `map-structure:"MAIL_ENCRYPTION"`
+	From       string `map-structure:"MAIL_FROM"`
+	FromName   string `map-structure:"MAIL_FROM_NAME"`
+	OutboxPath string `map-structure:"MAIL_OUTBOX_PATH"`
+}
+
+// archive es una estructura que contiene la configuración de los archivos de exportación de DTE, con varias instancias
+// Path debe ser un volumen compartido por todas
+type archive struct {
+	Path          string `map-structure:"ARCHIVE_PATH"`
+	RetentionDays int    `map-structure:"ARCHIVE_RETENTION_DAYS"`
+}
+
+// vault es una estructura que contiene las llaves maestras con las que se cifran las credenciales de Hacienda almacenadas.
+// MasterKeys tiene el formato "v1:<llave base64>,v2:<llave base64>" y ActiveKey indica la versión con la que se cifra,
+// si no se indica se utiliza la última versión configurada
+type vault struct {
+	MasterKeys string `map-structure:"VAULT_MASTER_KEYS"`
+	ActiveKey  string `map-structure:"VAULT_ACTIVE_KEY"`
+}
+
+// rateLimit es una estructura que contiene los límites de solicitudes. Los inicios de sesión fallidos se cuentan en una
+// ventana deslizante por API key y por IP, al superar el máximo se bloquea el inicio de sesión durante LOGIN_LOCKOUT_SECONDS.
+// DTE_REQUESTS_PER_MINUTE es la cuota por sucursal en las rutas de DTE cuando la sucursal no define una propia
+type rateLimit struct {
+	LoginMaxAttemptsPerKey int `map-structure:"LOGIN_MAX_ATTEMPTS_PER_KEY"`
+	LoginMaxAttemptsPerIP  int `map-structure:"LOGIN_MAX_ATTEMPTS_PER_IP"`
+	LoginAttemptWindow     int `map-structure:"LOGIN_ATTEMPT_WINDOW_SECONDS"`
+	LoginLockout           int `map-structure:"LOGIN_LOCKOUT_SECONDS"`
+	DTERequestsPerMinute   int `map-structure:"DTE_REQUESTS_PER_MINUTE"`
+}
+
+// tls es una estructura que contiene los certificados del servidor. Si se configura TLS_CLIENT_CA_FILE el servidor
+// solicita certificado de cliente y verifica los certificados presentados con esa CA, necesario para la autenticación mTLS
+type tls struct {
+	CertFile     string `map-structure:"TLS_CERT_FILE"`
+	KeyFile      string `map-structure:"TLS_KEY_FILE"`
+	ClientCAFile string `map-structure:"TLS_CLIENT_CA_FILE"`
+}
+
+// metrics es una estructura que contiene la configuración del endpoint /metrics de Prometheus. Si se configura
+// METRICS_TOKEN el endpoint exige el encabezado "Authorization: Bearer <token>", si no se configura es público
+type metrics struct {
+	Token string `map-structure:"METRICS_TOKEN"`
+}
+
+// tracing es una estructura que contiene la configuración del exportador de trazas de OpenTelemetry. Si no se configura
+// OTEL_EXPORTER_OTLP_ENDPOINT (por ejemplo http://localhost:4318 para un collector local) el tracing queda deshabilitado.
+// OTEL_TRACES_SAMPLE_RATIO es la proporción de trazas registradas, entre 0 y 1
+type tracing struct {
+	Endpoint    string  `map-structure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string  `map-structure:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `map-structure:"OTEL_TRACES_SAMPLE_RATIO"`
+}
+
+// health es una estructura que contiene la configuración de las verificaciones de salud. Cada componente se verifica con
+// un tiempo máximo de HEALTH_CHECK_TIMEOUT_SECONDS y el resultado se reutiliza durante HEALTH_CACHE_SECONDS
+type health struct {
+	CheckTimeout int `map-structure:"HEALTH_CHECK_TIMEOUT_SECONDS"`
+	CacheTTL     int `map-structure:"HEALTH_CACHE_SECONDS"`
+}
@@ -10,4 +10,6 @@ var (
 	ErrEnvFileNotFound           = errors.New("env file not found")
 	ErrFailedToLoadEnv           = errors.New("failed to load env file")
 	ErrUnrecognizedDriver        = errors.New("unrecognized database driver")
+	ErrDirtySchema               = errors.New("database schema is dirty")
+	ErrSchemaVersionMismatch     = errors.New("unexpected database schema version")
 )
@@ -0,0 +1,26 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity"
+	activityModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+)
+
+type ActivityUseCase struct {
+	activityManager activity.ActivityManager
+}
+
+func NewActivityUseCase(activityManager activity.ActivityManager) *ActivityUseCase {
+	return &ActivityUseCase{
+		activityManager: activityManager,
+	}
+}
+
+// Stream retorna los eventos de la sucursal autenticada hasta que se cancele el contexto, reanudando desde
+// lastEventID si se indica
+func (u *ActivityUseCase) Stream(ctx context.Context, lastEventID string) (<-chan activityModels.Event, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.activityManager.Subscribe(ctx, claims.BranchID, lastEventID)
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+const (
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+)
+
+// validActorTypes tipos de actor aceptados en el filtro actor_type
+var validActorTypes = map[string]bool{
+	models.ActorUser:      true,
+	models.ActorAPIKey:    true,
+	models.ActorOperator:  true,
+	models.ActorAdmin:     true,
+	models.ActorSystem:    true,
+	models.ActorAnonymous: true,
+}
+
+type AuditUseCase struct {
+	auditManager audit.AuditManager
+}
+
+func NewAuditUseCase(auditManager audit.AuditManager) *AuditUseCase {
+	return &AuditUseCase{
+		auditManager: auditManager,
+	}
+}
+
+// GetEvents obtiene una página de eventos de auditoría que cumplen con los filtros de la solicitud
+func (u *AuditUseCase) GetEvents(ctx context.Context, r *http.Request) (*models.AuditListResponse, error) {
+	// 1. Parsear los parámetros de consulta
+	filters, err := ParseAuditFilters(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Obtener los eventos
+	return u.auditManager.GetEvents(ctx, filters)
+}
+
+// ExportEvents recorre todos los eventos que cumplen con los filtros de la solicitud, sin paginación, invocando fn por cada evento
+func (u *AuditUseCase) ExportEvents(ctx context.Context, r *http.Request, fn func(*models.AuditEvent) error) error {
+	// 1. Parsear los parámetros de consulta, la paginación no aplica en la exportación
+	filters, err := ParseAuditFilters(r)
+	if err != nil {
+		return err
+	}
+	filters.Page = 0
+	filters.PageSize = 0
+
+	// 2. Recorrer los eventos
+	return u.auditManager.ExportEvents(ctx, filters, fn)
+}
+
+// ParseAuditFilters obtiene los filtros de búsqueda de eventos de auditoría de los parámetros de la solicitud
+func ParseAuditFilters(r *http.Request) (*models.AuditFilters, error) {
+	query := r.URL.Query()
+	filters := &models.AuditFilters{
+		EventType: strings.ToUpper(strings.TrimSpace(query.Get("event_type"))),
+		RequestID: strings.TrimSpace(query.Get("request_id")),
+		Page:      1,
+		PageSize:  defaultAuditPageSize,
+	}
+
+	// 1. Usuario y sucursal
+	if userID := query.Get("user_id"); userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil || id == 0 {
+			return nil, shared_error.NewFormattedGeneralServiceError("AuditUseCase", "ParseAuditFilters", "InvalidQueryParam", "user_id", "a positive number")
+		}
+		filters.UserID = uint(id)
+	}
+
+	if branchID := query.Get("branch_id"); branchID != "" {
+		id, err := strconv.ParseUint(branchID, 10, 64)
+		if err != nil || id == 0 {
+			return nil, shared_error.NewFormattedGeneralServiceError("AuditUseCase", "ParseAuditFilters", "InvalidQueryParam", "branch_id", "a positive number")
+		}
+		filters.BranchID = uint(id)
+	}
+
+	// 2. Tipo de actor
+	if actorType := strings.ToUpper(strings.TrimSpace(query.Get("actor_type"))); actorType != "" {
+		if !validActorTypes[actorType] {
+			return nil, shared_error.NewFormattedGeneralServiceError("AuditUseCase", "ParseAuditFilters", "InvalidQueryParam", "actor_type", "'user', 'api_key', 'operator', 'admin', 'system', 'anonymous'")
+		}
+		filters.ActorType = actorType
+	}
+
+	// 3. Fechas
+	if startDate := query.Get("startDate"); startDate != "" {
+		parsed, err := time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			return nil, shared_error.NewFormattedGeneralServiceError("AuditUseCase", "ParseAuditFilters", "InvalidQueryParam", "startDate", "RFC3339")
+		}
+		filters.StartDate = &parsed
+	}
+
+	if endDate := query.Get("endDate"); endDate != "" {
+		parsed, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			return nil, shared_error.NewFormattedGeneralServiceError("AuditUseCase", "ParseAuditFilters", "InvalidQueryParam", "endDate", "RFC3339")
+		}
+		filters.EndDate = &parsed
+	}
+
+	if filters.StartDate != nil && filters.EndDate != nil && filters.StartDate.After(*filters.EndDate) {
+		return nil, shared_error.NewFormattedGeneralServiceError("AuditUseCase", "ParseAuditFilters", "InvalidQueryParam", "startDate", "before endDate")
+	}
+
+	// 4. Paginación
+	if page, err := strconv.Atoi(query.Get("page")); err == nil && page > 0 {
+		filters.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(query.Get("page_size")); err == nil && pageSize > 0 {
+		filters.PageSize = pageSize
+		if pageSize > maxAuditPageSize {
+			filters.PageSize = maxAuditPageSize
+		}
+	}
+
+	return filters, nil
+}
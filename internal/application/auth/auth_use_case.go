@@ -22,46 +22,115 @@ func NewAuthUseCase(authManager auth.AuthManager, cryptManager ports.CryptManage
 	}
 }
 
-func (a *AuthUseCase) Login(ctx context.Context, credentials *models.AuthCredentials) (string, error) {
+func (a *AuthUseCase) Login(ctx context.Context, credentials *models.AuthCredentials) (*models.TokenResponse, error) {
 	// 1. Validar las credenciales obtenidas del request
 	if err := credentials.Validate(); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 2. Autenticar al usuario
-	token, err := a.authManager.Login(ctx, credentials)
+	tokens, err := a.authManager.Login(ctx, credentials)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Refresh emite un nuevo access token a partir de un refresh token, el refresh token utilizado se reemplaza por uno nuevo
+func (a *AuthUseCase) Refresh(ctx context.Context, req *models.RefreshRequest) (*models.TokenResponse, error) {
+	// 1. Validar la solicitud
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Renovar la sesión
+	return a.authManager.Refresh(ctx, req.RefreshToken)
+}
+
+// Logout cierra la sesión del token autenticado
+func (a *AuthUseCase) Logout(ctx context.Context) error {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	token := ctx.Value("token").(string)
+
+	return a.authManager.Logout(ctx, token, claims)
+}
+
+// StoreHaciendaCredentials reemplaza las credenciales de Hacienda del usuario autenticado, los usuarios que autentican
+// cada solicitud con firma HMAC o certificado de cliente registran sus credenciales únicamente por este medio
+func (a *AuthUseCase) StoreHaciendaCredentials(ctx context.Context, creds *models.HaciendaCredentials) error {
+	// 1. Validar las credenciales
+	if err := creds.Validate(); err != nil {
+		return err
 	}
 
-	return token, nil
+	// 2. Almacenar las credenciales del usuario autenticado
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return a.authManager.StoreHaciendaCredentials(ctx, claims, creds)
 }
 
-func (a *AuthUseCase) Register(ctx context.Context, user *user.User) ([]user.ListBranchesResponse, error) {
+// Register registra una solicitud de alta de un usuario con sus sucursales, el usuario no puede iniciar sesión
+// hasta que un administrador apruebe el registro y se generen los API secrets de sus sucursales
+func (a *AuthUseCase) Register(ctx context.Context, newUser *user.User) (*user.RegistrationResponse, error) {
 	// 1. Validar los datos del usuario
-	if err := user.Validate(); err != nil {
+	if err := newUser.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Rechazar los registros con un NIT o NRC ya registrado o pendiente de aprobación
+	if err := a.checkRegistrationConflicts(ctx, newUser); err != nil {
 		return nil, err
 	}
 
-	// 2.Generar todas las API KEYS y API SECRETS necesarios para el usuario
-	keys, secrets, err := a.cryptManager.GenerateBulkAPIKeys(len(user.BranchOffices))
+	// 3. Generar las API KEYS de las sucursales del usuario
+	keys, _, err := a.cryptManager.GenerateBulkAPIKeys(len(newUser.BranchOffices))
 	if err != nil {
-		logs.Error("Failed to generate bulk API keys and secrets", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to generate bulk API keys", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, shared_error.NewFormattedGeneralServiceError("AuthUseCase", "Register", "FailedToCreateUser")
 	}
+	newUser.SetBranchesKeys(keys)
 
-	// 3. Asignar las API KEYS y API SECRETS a las sucursales del usuario
-	user.SetBranchesKeysAndSecrets(keys, secrets)
-
-	//4. Crear el usuario en la base de datos
-	if err = a.authManager.Create(ctx, user); err != nil {
-		logs.Error("Failed to create user", map[string]interface{}{
+	// 4. Crear el usuario pendiente de aprobación en la base de datos
+	newUser.Status = false
+	newUser.RegistrationStatus = user.RegistrationPending
+	if err = a.authManager.Create(ctx, newUser); err != nil {
+		logs.ErrorContext(ctx, "Failed to create user", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, shared_error.NewFormattedGeneralServiceWithError("AuthUseCase", "Register", err, "FailedToCreateUser")
 	}
 
-	return user.ListBranches(), nil
+	logs.InfoContext(ctx, "User registration pending approval", map[string]interface{}{
+		"userID": newUser.ID,
+	})
+
+	return &user.RegistrationResponse{
+		NIT:      newUser.NIT,
+		Status:   newUser.RegistrationStatus,
+		Branches: len(newUser.BranchOffices),
+	}, nil
+}
+
+// checkRegistrationConflicts verifica que el NIT y NRC del usuario no pertenezcan a otro registro
+func (a *AuthUseCase) checkRegistrationConflicts(ctx context.Context, newUser *user.User) error {
+	conflicts, err := a.authManager.GetRegistrationConflicts(ctx, newUser.NIT, newUser.NRC)
+	if err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("AuthUseCase", "Register", err, "FailedToCreateUser")
+	}
+
+	for _, conflict := range conflicts {
+		if conflict.NIT == newUser.NIT && conflict.RegistrationStatus == user.RegistrationPending {
+			return shared_error.NewFormattedGeneralServiceError("AuthUseCase", "Register", "RegistrationAlreadyPending", newUser.NIT)
+		}
+		if conflict.NIT == newUser.NIT {
+			return shared_error.NewFormattedGeneralServiceError("AuthUseCase", "Register", "NITAlreadyRegistered", newUser.NIT)
+		}
+		if conflict.NRC == newUser.NRC {
+			return shared_error.NewFormattedGeneralServiceError("AuthUseCase", "Register", "NRCAlreadyRegistered", newUser.NRC)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,432 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/error"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+// defaultRateLimit valor de rate_limit_per_minute con el que una sucursal vuelve a utilizar la cuota por defecto
+const defaultRateLimit = -1
+
+type BranchUseCase struct {
+	authManager     auth.AuthManager
+	cryptManager    ports.CryptManager
+	credentialVault vault.CredentialVault
+}
+
+func NewBranchUseCase(authManager auth.AuthManager, cryptManager ports.CryptManager, credentialVault vault.CredentialVault) *BranchUseCase {
+	return &BranchUseCase{
+		authManager:     authManager,
+		cryptManager:    cryptManager,
+		credentialVault: credentialVault,
+	}
+}
+
+// ListBranches obtiene las sucursales del usuario autenticado
+func (u *BranchUseCase) ListBranches(ctx context.Context) ([]user.BranchOfficeResponse, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	branches, err := u.authManager.GetBranchOffices(ctx, claims.ClientID)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "ListBranches", err, "FailedToGetBranches")
+	}
+
+	response := make([]user.BranchOfficeResponse, len(branches))
+	for i := range branches {
+		response[i] = branches[i].ToResponse()
+	}
+
+	return response, nil
+}
+
+// CreateBranch crea una sucursal para el usuario autenticado y retorna sus llaves de acceso. certIdentities contiene el
+// subject y los SAN del certificado de cliente presentado en la solicitud
+func (u *BranchUseCase) CreateBranch(ctx context.Context, branch *user.BranchOffice, certIdentities []string) (*user.BranchCredentialsResponse, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Validar la sucursal y el certificado de cliente asignado, el usuario ya posee una casa matriz registrada
+	if err := branch.Validate(); err != nil {
+		return nil, err
+	}
+
+	if branch.EstablishmentType == constants.CasaMatriz {
+		return nil, dte_errors.NewFormattedValidationError(errPackage.ErrMoreThanOneBranchMatrix)
+	}
+
+	if err := checkCertOwnership("CreateBranch", branch.ClientCertSubject, certIdentities); err != nil {
+		return nil, err
+	}
+
+	// 2. Generar las llaves de acceso de la sucursal
+	apiKey, apiSecret, err := u.generateCredentials(ctx)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "CreateBranch", "FailedToCreateBranch")
+	}
+
+	hashedSecret, signingSecret, err := sealAPISecret(ctx, u.cryptManager, u.credentialVault, apiSecret)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "CreateBranch", err, "FailedToCreateBranch")
+	}
+
+	// 3. Almacenar solo el hash y la copia cifrada del API secret, el valor original se retorna una única vez
+	branch.APIKey = apiKey
+	branch.APISecret = hashedSecret
+	branch.APISigningSecret = &signingSecret
+	branch.IsActive = true
+
+	// 4. Crear la sucursal
+	if err = u.authManager.CreateBranchOffice(ctx, claims.ClientID, branch); err != nil {
+		logs.ErrorContext(ctx, "Failed to create branch office", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "CreateBranch", err, "FailedToCreateBranch")
+	}
+
+	return &user.BranchCredentialsResponse{
+		ID:        branch.ID,
+		APIKey:    branch.APIKey,
+		APISecret: apiSecret,
+	}, nil
+}
+
+// UpdateBranch actualiza los datos de una sucursal del usuario autenticado, los campos no enviados conservan su valor.
+// Un client_cert_subject vacío retira el certificado asignado y un rate_limit_per_minute de -1 restablece la cuota por
+// defecto. certIdentities contiene el subject y los SAN del certificado de cliente presentado en la solicitud
+func (u *BranchUseCase) UpdateBranch(ctx context.Context, id string, changes *user.BranchOffice, certIdentities []string) (*user.BranchOfficeResponse, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Obtener la sucursal a actualizar
+	branch, err := u.getOwnedBranch(ctx, claims.ClientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Aplicar los cambios sobre la sucursal actual
+	isMatrix := branch.EstablishmentType == constants.CasaMatriz
+	if changes.EstablishmentType != "" && changes.EstablishmentType != branch.EstablishmentType {
+		if isMatrix {
+			return nil, dte_errors.NewFormattedValidationError(errPackage.ErrBranchMatrixTypeChange)
+		}
+		if changes.EstablishmentType == constants.CasaMatriz {
+			return nil, dte_errors.NewFormattedValidationError(errPackage.ErrMoreThanOneBranchMatrix)
+		}
+		branch.EstablishmentType = changes.EstablishmentType
+	}
+
+	if changes.EstablishmentCode != nil {
+		branch.EstablishmentCode = changes.EstablishmentCode
+	}
+	if changes.EstablishmentCodeMH != nil {
+		branch.EstablishmentCodeMH = changes.EstablishmentCodeMH
+	}
+	if changes.POSCode != nil {
+		branch.POSCode = changes.POSCode
+	}
+	if changes.POSCodeMH != nil {
+		branch.POSCodeMH = changes.POSCodeMH
+	}
+	if changes.Email != nil {
+		branch.Email = changes.Email
+	}
+	if changes.Phone != nil {
+		branch.Phone = changes.Phone
+	}
+	if changes.Address != nil {
+		branch.Address = changes.Address
+	}
+	if changes.RateLimitPerMinute != nil {
+		branch.RateLimitPerMinute = changes.RateLimitPerMinute
+		if *changes.RateLimitPerMinute == defaultRateLimit {
+			branch.RateLimitPerMinute = nil
+		}
+	}
+	if changes.ClientCertSubject != nil {
+		if *changes.ClientCertSubject == "" {
+			branch.ClientCertSubject = nil
+		} else if branch.ClientCertSubject == nil || *branch.ClientCertSubject != *changes.ClientCertSubject {
+			if err = checkCertOwnership("UpdateBranch", changes.ClientCertSubject, certIdentities); err != nil {
+				return nil, err
+			}
+			branch.ClientCertSubject = changes.ClientCertSubject
+		}
+	}
+
+	// 3. Validar la sucursal resultante
+	if err = branch.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 4. Actualizar la sucursal, las llaves y el estado solo cambian por sus operaciones dedicadas
+	update := *branch
+	update.APIKey = ""
+	update.APISecret = ""
+	if err = u.authManager.UpdateBranchOffice(ctx, claims.ClientID, &update); err != nil {
+		logs.ErrorContext(ctx, "Failed to update branch office", map[string]interface{}{
+			"branchID": branch.ID,
+			"error":    err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "UpdateBranch", err, "FailedToUpdateBranch", id)
+	}
+
+	response := branch.ToResponse()
+	return &response, nil
+}
+
+// DeactivateBranch desactiva una sucursal del usuario autenticado, la casa matriz no puede desactivarse
+func (u *BranchUseCase) DeactivateBranch(ctx context.Context, id string) error {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Obtener la sucursal a desactivar
+	branch, err := u.getOwnedBranch(ctx, claims.ClientID, id)
+	if err != nil {
+		return err
+	}
+
+	if branch.EstablishmentType == constants.CasaMatriz {
+		return dte_errors.NewFormattedValidationError(errPackage.ErrBranchMatrixDeactivation)
+	}
+
+	// 2. Desactivar la sucursal e invalidar sus tokens
+	if err = u.authManager.DeactivateBranchOffice(ctx, claims.ClientID, branch.ID); err != nil {
+		logs.ErrorContext(ctx, "Failed to deactivate branch office", map[string]interface{}{
+			"branchID": branch.ID,
+			"error":    err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "DeactivateBranch", err, "FailedToUpdateBranch", id)
+	}
+
+	return nil
+}
+
+// RotateBranchKeys genera nuevas llaves de acceso para una sucursal activa del usuario autenticado,
+// los tokens emitidos con las llaves anteriores dejan de ser válidos
+func (u *BranchUseCase) RotateBranchKeys(ctx context.Context, id string) (*user.BranchCredentialsResponse, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Obtener la sucursal, solo las sucursales activas pueden rotar sus llaves
+	branch, err := u.getOwnedBranch(ctx, claims.ClientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !branch.IsActive {
+		return nil, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "RotateBranchKeys", "BranchNotActive", id)
+	}
+
+	// 2. Generar las nuevas llaves
+	apiKey, apiSecret, err := u.generateCredentials(ctx)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "RotateBranchKeys", "FailedToRotateBranchKeys", id)
+	}
+
+	hashedSecret, signingSecret, err := sealAPISecret(ctx, u.cryptManager, u.credentialVault, apiSecret)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "RotateBranchKeys", err, "FailedToRotateBranchKeys", id)
+	}
+
+	// 3. Reemplazar las llaves almacenando el hash y la copia cifrada del API secret e invalidar los tokens anteriores
+	if err = u.authManager.RotateBranchCredentials(ctx, claims.ClientID, branch.ID, apiKey, hashedSecret, signingSecret); err != nil {
+		logs.ErrorContext(ctx, "Failed to rotate branch office keys", map[string]interface{}{
+			"branchID": branch.ID,
+			"error":    err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "RotateBranchKeys", err, "FailedToRotateBranchKeys", id)
+	}
+
+	return &user.BranchCredentialsResponse{
+		ID:        branch.ID,
+		APIKey:    apiKey,
+		APISecret: apiSecret,
+	}, nil
+}
+
+// ListAPIKeys obtiene las llaves de acceso adicionales de una sucursal del usuario autenticado
+func (u *BranchUseCase) ListAPIKeys(ctx context.Context, id string) ([]user.BranchAPIKey, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	branch, err := u.getOwnedBranch(ctx, claims.ClientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := u.authManager.GetBranchAPIKeys(ctx, branch.ID)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "ListAPIKeys", err, "FailedToGetAPIKeys", id)
+	}
+
+	return keys, nil
+}
+
+// CreateAPIKey crea una llave de acceso adicional con scopes limitados para una sucursal activa del usuario autenticado.
+// certIdentities contiene el subject y los SAN del certificado de cliente presentado en la solicitud
+func (u *BranchUseCase) CreateAPIKey(ctx context.Context, id string, key *user.BranchAPIKey, certIdentities []string) (*user.BranchAPIKeyCredentialsResponse, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Validar la llave y el certificado de cliente asignado
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := checkCertOwnership("CreateAPIKey", key.ClientCertSubject, certIdentities); err != nil {
+		return nil, err
+	}
+
+	// 2. Obtener la sucursal, solo las sucursales activas pueden crear llaves
+	branch, err := u.getOwnedBranch(ctx, claims.ClientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !branch.IsActive {
+		return nil, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "CreateAPIKey", "BranchNotActive", id)
+	}
+
+	// 3. Generar las llaves de acceso, solo se almacena el hash y la copia cifrada del API secret
+	apiKey, apiSecret, err := u.generateCredentials(ctx)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "CreateAPIKey", "FailedToCreateAPIKey", id)
+	}
+
+	hashedSecret, signingSecret, err := sealAPISecret(ctx, u.cryptManager, u.credentialVault, apiSecret)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "CreateAPIKey", err, "FailedToCreateAPIKey", id)
+	}
+
+	key.BranchID = branch.ID
+	key.APIKey = apiKey
+	key.APISecret = hashedSecret
+	key.APISigningSecret = &signingSecret
+	key.IsActive = true
+
+	// 4. Crear la llave
+	if err = u.authManager.CreateBranchAPIKey(ctx, key); err != nil {
+		logs.ErrorContext(ctx, "Failed to create branch API key", map[string]interface{}{
+			"branchID": branch.ID,
+			"error":    err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "CreateAPIKey", err, "FailedToCreateAPIKey", id)
+	}
+
+	return &user.BranchAPIKeyCredentialsResponse{
+		ID:                key.ID,
+		BranchID:          key.BranchID,
+		Name:              key.Name,
+		APIKey:            key.APIKey,
+		APISecret:         apiSecret,
+		Scopes:            key.Scopes,
+		ClientCertSubject: key.ClientCertSubject,
+	}, nil
+}
+
+// RevokeAPIKey desactiva una llave de acceso adicional de una sucursal del usuario autenticado,
+// los tokens emitidos con ella dejan de ser válidos
+func (u *BranchUseCase) RevokeAPIKey(ctx context.Context, id, keyID string) error {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Obtener la sucursal y validar el ID de la llave
+	branch, err := u.getOwnedBranch(ctx, claims.ClientID, id)
+	if err != nil {
+		return err
+	}
+
+	parsedKeyID, err := strconv.ParseUint(keyID, 10, 64)
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "keyId", "number", keyID)
+	}
+
+	// 2. Desactivar la llave e invalidar sus tokens
+	if err = u.authManager.RevokeBranchAPIKey(ctx, branch.ID, uint(parsedKeyID)); err != nil {
+		logs.ErrorContext(ctx, "Failed to revoke branch API key", map[string]interface{}{
+			"branchID": branch.ID,
+			"keyID":    parsedKeyID,
+			"error":    err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "RevokeAPIKey", err, "APIKeyNotFound", keyID)
+	}
+
+	return nil
+}
+
+// getOwnedBranch obtiene una sucursal del usuario por su ID
+func (u *BranchUseCase) getOwnedBranch(ctx context.Context, userID uint, id string) (*user.BranchOffice, error) {
+	branchID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	branches, err := u.authManager.GetBranchOffices(ctx, userID)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("BranchUseCase", "GetBranch", err, "FailedToGetBranches")
+	}
+
+	for i := range branches {
+		if branches[i].ID == uint(branchID) {
+			return &branches[i], nil
+		}
+	}
+
+	return nil, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "GetBranch", "BranchNotFound", id)
+}
+
+// checkCertOwnership verifica que la solicitud presente el certificado de cliente que se asigna. Sin esta verificación
+// un contribuyente podría asignarse el subject de un certificado ajeno emitido por la misma CA y autenticarse con él
+// o impedir que su dueño lo utilice
+func checkCertOwnership(operation string, subject *string, certIdentities []string) error {
+	if subject == nil {
+		return nil
+	}
+
+	for _, identity := range certIdentities {
+		if identity == *subject {
+			return nil
+		}
+	}
+
+	return shared_error.NewFormattedGeneralServiceError("BranchUseCase", operation, "ClientCertNotPresented", *subject)
+}
+
+// generateCredentials genera un API key y un API secret para una sucursal
+func (u *BranchUseCase) generateCredentials(ctx context.Context) (string, string, error) {
+	apiKey, err := u.cryptManager.GenerateAPIKey()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to generate API key", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return "", "", err
+	}
+
+	apiSecret, err := u.cryptManager.GenerateAPISecret()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to generate API secret", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return "", "", err
+	}
+
+	return apiKey, apiSecret, nil
+}
+
+// sealAPISecret retorna el hash del API secret, con el que se verifica el inicio de sesión, y el API secret cifrado con
+// el vault, con el que se verifican las solicitudes firmadas con HMAC. El hash no sirve como llave de firma
+func sealAPISecret(ctx context.Context, cryptManager ports.CryptManager, credentialVault vault.CredentialVault, apiSecret string) (string, string, error) {
+	signingSecret, err := credentialVault.SealSecret(apiSecret)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to seal API secret", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return "", "", err
+	}
+
+	return cryptManager.HashAPISecret(apiSecret), signingSecret, nil
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+type RegistrationUseCase struct {
+	authManager     auth.AuthManager
+	cryptManager    ports.CryptManager
+	credentialVault vault.CredentialVault
+}
+
+func NewRegistrationUseCase(authManager auth.AuthManager, cryptManager ports.CryptManager, credentialVault vault.CredentialVault) *RegistrationUseCase {
+	return &RegistrationUseCase{
+		authManager:     authManager,
+		cryptManager:    cryptManager,
+		credentialVault: credentialVault,
+	}
+}
+
+// ListPending obtiene las solicitudes de registro pendientes de aprobación
+func (u *RegistrationUseCase) ListPending(ctx context.Context) ([]user.RegistrationSummary, error) {
+	registrations, err := u.authManager.GetPendingRegistrations(ctx)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("RegistrationUseCase", "ListPending", err, "FailedToGetRegistrations")
+	}
+
+	summaries := make([]user.RegistrationSummary, len(registrations))
+	for i := range registrations {
+		summaries[i] = registrations[i].ToRegistrationSummary()
+	}
+
+	return summaries, nil
+}
+
+// Approve aprueba una solicitud de registro y genera las llaves de sus sucursales. Los API secrets se retornan
+// una única vez, en la base de datos solo se almacena su hash y su copia cifrada con el vault.
+func (u *RegistrationUseCase) Approve(ctx context.Context, id string) ([]user.ListBranchesResponse, error) {
+	// 1. Obtener la solicitud pendiente
+	registration, err := u.getPendingRegistration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Generar las llaves de las sucursales
+	keys, secrets, err := u.cryptManager.GenerateBulkAPIKeys(len(registration.BranchOffices))
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to generate bulk API keys and secrets", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("RegistrationUseCase", "Approve", "FailedToApproveRegistration", id)
+	}
+
+	hashedSecrets := make([]string, len(secrets))
+	signingSecrets := make([]string, len(secrets))
+	for i, secret := range secrets {
+		if hashedSecrets[i], signingSecrets[i], err = sealAPISecret(ctx, u.cryptManager, u.credentialVault, secret); err != nil {
+			return nil, shared_error.NewFormattedGeneralServiceWithError("RegistrationUseCase", "Approve", err, "FailedToApproveRegistration", id)
+		}
+	}
+
+	// 3. Activar el usuario almacenando solo el hash y la copia cifrada de los API secrets
+	registration.SetBranchesKeysAndSecrets(keys, hashedSecrets)
+	registration.SetBranchesSigningSecrets(signingSecrets)
+	if err = u.authManager.ApproveRegistration(ctx, registration.ID, registration.BranchOffices); err != nil {
+		logs.ErrorContext(ctx, "Failed to approve registration", map[string]interface{}{
+			"userID": registration.ID,
+			"error":  err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("RegistrationUseCase", "Approve", err, "FailedToApproveRegistration", id)
+	}
+
+	logs.InfoContext(ctx, "User registration approved", map[string]interface{}{
+		"userID": registration.ID,
+	})
+
+	// 4. Retornar las llaves con los API secrets originales
+	registration.SetBranchesKeysAndSecrets(keys, secrets)
+	return registration.ListBranches(), nil
+}
+
+// Reject rechaza una solicitud de registro, la solicitud se elimina para permitir un nuevo registro del mismo NIT
+func (u *RegistrationUseCase) Reject(ctx context.Context, id string) error {
+	// 1. Obtener la solicitud pendiente
+	registration, err := u.getPendingRegistration(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 2. Eliminar la solicitud
+	if err = u.authManager.RejectRegistration(ctx, registration.ID); err != nil {
+		logs.ErrorContext(ctx, "Failed to reject registration", map[string]interface{}{
+			"userID": registration.ID,
+			"error":  err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("RegistrationUseCase", "Reject", err, "FailedToRejectRegistration", id)
+	}
+
+	logs.InfoContext(ctx, "User registration rejected", map[string]interface{}{
+		"userID": registration.ID,
+	})
+
+	return nil
+}
+
+// getPendingRegistration obtiene una solicitud de registro por su ID y verifica que siga pendiente
+func (u *RegistrationUseCase) getPendingRegistration(ctx context.Context, id string) (*user.User, error) {
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	registration, err := u.authManager.GetRegistration(ctx, uint(userID))
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("RegistrationUseCase", "GetRegistration", err, "RegistrationNotFound", id)
+	}
+
+	if registration.RegistrationStatus != user.RegistrationPending {
+		return nil, shared_error.NewFormattedGeneralServiceError("RegistrationUseCase", "GetRegistration", "RegistrationNotPending", id)
+	}
+
+	return registration, nil
+}
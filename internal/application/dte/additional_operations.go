@@ -38,7 +38,7 @@ func (o *DTEOperations) GetCreditNoteOperations(dteService dte_documents.DTEMana
 					mhModel,
 				)
 				if err != nil {
-					logs.Warn("Failed to generate balance transaction", map[string]interface{}{"error": err.Error()})
+					logs.WarnContext(ctx, "Failed to generate balance transaction", map[string]interface{}{"error": err.Error()})
 					return err
 				}
 			}
@@ -8,7 +8,11 @@ import (
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
+	healthConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/health/constants"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -21,20 +25,33 @@ const (
 type BaseTransmitter struct {
 	transmitter ports.DTETransmitter
 	signer      ports.SignerManager
+	monitor     health.DependencyMonitor
 }
 
-func NewBaseTransmitter(transmitter ports.DTETransmitter, signer ports.SignerManager) ports.BaseTransmitter {
+// NewBaseTransmitter crea una instancia de BaseTransmitter. El monitor de dependencias es opcional, si se indica la
+// transmisión no se intenta mientras el firmador o Hacienda estén caídos
+func NewBaseTransmitter(transmitter ports.DTETransmitter, signer ports.SignerManager, monitor health.DependencyMonitor) ports.BaseTransmitter {
 	return &BaseTransmitter{
 		transmitter: transmitter,
 		signer:      signer,
+		monitor:     monitor,
 	}
 }
 
 // RetryTransmission maneja la lógica de reintentos y verificación
-func (bt *BaseTransmitter) RetryTransmission(ctx context.Context, document interface{}, token string, nit string) (*models.TransmitResult, error) {
+func (bt *BaseTransmitter) RetryTransmission(ctx context.Context, document interface{}, token string, nit string) (_ *models.TransmitResult, err error) {
+	ctx, span := tracing.Start(ctx, "BaseTransmitter.RetryTransmission")
+	defer func() { tracing.End(span, err) }()
+
+	// 0. En modo degradado el documento no se firma ni se transmite, el error permite enviarlo a contingencia sin esperar
+	// el timeout de cada intento
+	if err = bt.checkDependencies(ctx); err != nil {
+		return nil, err
+	}
+
 	jsonData, err := json.Marshal(document)
 	if err != nil {
-		logs.Error("Failed to marshal document for signing", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to marshal document for signing", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, err
@@ -42,55 +59,76 @@ func (bt *BaseTransmitter) RetryTransmission(ctx context.Context, document inter
 
 	signedDoc, err := bt.signer.SignDTE(ctx, jsonData, nit)
 	if err != nil {
-		logs.Error("Failed to sign document", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to sign document", map[string]interface{}{
 			"error": err.Error(),
 			"nit":   nit,
 		})
 		return nil, err
 	}
 
-	logs.Info("First attempt to transmit document")
+	logs.InfoContext(ctx, "First attempt to transmit document")
 	// 1. Primer intento de transmisión
 	result, err := bt.transmitter.Transmit(ctx, document, signedDoc, token)
 	if err == nil && result.Status == ReceivedStatus {
-		logs.Info("Document received on first attempt")
+		logs.InfoContext(ctx, "Document received on first attempt")
 		return result, nil
 	}
 
 	if err != nil {
-		logs.Error("Failed to transmit document", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to transmit document", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return result, err
 	}
 
-	logs.Info("Check status of document")
+	logs.InfoContext(ctx, "Check status of document")
 	// 2. Verificar estado actual
 	statusResult, err := bt.CheckStatus(ctx, document, nit)
 	if err == nil && statusResult.Status == ReceivedStatus {
-		logs.Info("Document already received")
+		logs.InfoContext(ctx, "Document already received")
+		statusResult.SignedDocument = signedDoc
 		return statusResult, nil
 	}
 
 	// 3. Aplicar política de reintentos
-	logs.Info("Starting retries because document was not received")
+	logs.InfoContext(ctx, "Starting retries because document was not received")
 	retryCount := 0
 	for retryCount < MaxRetries {
-		logs.Info(fmt.Sprintf("Retry %d of %d", retryCount+1, MaxRetries), nil)
+		logs.InfoContext(ctx, fmt.Sprintf("Retry %d of %d", retryCount+1, MaxRetries), nil)
 		result, err = bt.transmitter.Transmit(ctx, document, signedDoc, token)
 		if err == nil && result.Status == ReceivedStatus {
-			logs.Info("Document received on retry")
+			logs.InfoContext(ctx, "Document received on retry")
 			return result, nil
 		}
 
 		retryCount++
+		_, waitSpan := tracing.Start(ctx, "BaseTransmitter.RetryWait", attribute.Int("retry.attempt", retryCount))
 		time.Sleep(MaxTimeout * time.Second)
+		waitSpan.End()
 	}
 
-	logs.Info("Document was not received")
+	logs.InfoContext(ctx, "Document was not received")
 	return result, err
 }
 
+// checkDependencies verifica con la última verificación de salud que el firmador y Hacienda estén disponibles
+func (bt *BaseTransmitter) checkDependencies(ctx context.Context) error {
+	if bt.monitor == nil {
+		return nil
+	}
+
+	for _, component := range []string{healthConstants.ComponentSigner, healthConstants.ComponentHacienda} {
+		if !bt.monitor.IsAvailable(component) {
+			logs.WarnContext(ctx, "Skipping transmission, dependency unavailable", map[string]interface{}{
+				"component": component,
+			})
+			return health.NewDependencyUnavailableError(component)
+		}
+	}
+
+	return nil
+}
+
 func (bt *BaseTransmitter) CheckStatus(ctx context.Context, document interface{}, nit string) (*models.TransmitResult, error) {
 	return bt.transmitter.CheckDocumentStatus(ctx, document, nit)
 }
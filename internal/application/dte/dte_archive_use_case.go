@@ -0,0 +1,77 @@
+package dte
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
+	archiveModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+	authConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+type DTEArchiveUseCase struct {
+	archiveManager archive.ArchiveManager
+}
+
+func NewDTEArchiveUseCase(archiveManager archive.ArchiveManager) *DTEArchiveUseCase {
+	return &DTEArchiveUseCase{
+		archiveManager: archiveManager,
+	}
+}
+
+// CreateArchive registra la generación del archivo de los DTE de un período para la sucursal o el NIT autenticado
+func (u *DTEArchiveUseCase) CreateArchive(ctx context.Context, req *structs.CreateArchiveRequest) (*archiveModels.ArchiveJob, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 1. Parsear el período en la zona horaria del sistema, la fecha de fin incluye todo el día
+	location := utils.TimeNow().Location()
+	startDate, err := time.ParseInLocation("2006-01-02", req.StartDate, location)
+	if err != nil {
+		return nil, dte_errors.NewValidationError("InvalidFormat", "start_date", "YYYY-MM-DD", req.StartDate)
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", req.EndDate, location)
+	if err != nil {
+		return nil, dte_errors.NewValidationError("InvalidFormat", "end_date", "YYYY-MM-DD", req.EndDate)
+	}
+	endDate = endDate.Add(24*time.Hour - time.Second)
+
+	scope := req.Scope
+	if scope == "" {
+		scope = archiveModels.ArchiveScopeBranch
+	}
+
+	// 1.1 Los archivos de todas las sucursales del NIT requieren acceso a todas las sucursales
+	if scope == archiveModels.ArchiveScopeNIT && !claims.HasScope(authConstants.ScopeDTEReadAllBranches) {
+		return nil, shared_error.NewFormattedGeneralServiceError("DTEArchiveUseCase", "CreateArchive", "InsufficientScope", authConstants.ScopeDTEReadAllBranches)
+	}
+
+	// 2. Registrar el trabajo de exportación
+	return u.archiveManager.CreateJob(ctx, &archiveModels.ArchiveJob{
+		BranchID:   claims.BranchID,
+		ClientID:   claims.ClientID,
+		NIT:        claims.NIT,
+		Scope:      scope,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		IncludePDF: req.IncludePDF,
+	})
+}
+
+// GetArchive obtiene el estado de un trabajo de exportación de la sucursal autenticada
+func (u *DTEArchiveUseCase) GetArchive(ctx context.Context, id string) (*archiveModels.ArchiveJob, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.archiveManager.GetJob(ctx, claims.BranchID, id)
+}
+
+// DownloadArchive abre el archivo ZIP de un trabajo completado de la sucursal autenticada
+func (u *DTEArchiveUseCase) DownloadArchive(ctx context.Context, id string) (io.ReadCloser, *archiveModels.ArchiveJob, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.archiveManager.OpenArchive(ctx, claims.BranchID, id)
+}
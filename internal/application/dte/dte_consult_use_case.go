@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	authConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
@@ -53,26 +54,58 @@ func (u *DTEConsultUseCase) GetAllDTEs(ctx context.Context, r *http.Request) (*d
 	return response, nil
 }
 
+// ExportDTEs recorre todos los DTEs que cumplen con los filtros de la solicitud, sin paginación, invocando fn por cada documento
+func (u *DTEConsultUseCase) ExportDTEs(ctx context.Context, r *http.Request, fn func(*dte.DTEExportRecord) error) error {
+	// 1. Parsear los parámetros de consulta, la paginación no aplica en la exportación
+	filters, err := parseDTEFilters(r)
+	if err != nil {
+		return err
+	}
+	filters.Page = 0
+	filters.PageSize = 0
+
+	// 2. Recorrer los documentos
+	return u.dteService.ExportDTEs(ctx, filters, fn)
+}
+
 func parseDTEFilters(r *http.Request) (*dte.DTEFilters, error) {
-	filters := &dte.DTEFilters{
-		IncludeAll: r.URL.Query().Get("all") == "true",
+	filters, err := ParseDTEQueryFilters(r)
+	if err != nil {
+		return nil, err
 	}
 
-	// 1. Si se incluyen todos los documentos no se establece el ID de la sucursal
-	if !filters.IncludeAll {
-		filters.BranchID = r.Context().Value("claims").(*models.AuthClaims).BranchID
+	// 1. Si se incluyen todos los documentos se consultan todas las sucursales del contribuyente, requiere acceso a
+	// todas las sucursales
+	claims := r.Context().Value("claims").(*models.AuthClaims)
+	if filters.IncludeAll && !claims.HasScope(authConstants.ScopeDTEReadAllBranches) {
+		return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "parseDTEFilters", "InsufficientScope", authConstants.ScopeDTEReadAllBranches)
+	}
+	if filters.IncludeAll {
+		filters.ClientID = claims.ClientID
+	} else {
+		filters.BranchID = claims.BranchID
+	}
+
+	return filters, nil
+}
+
+// ParseDTEQueryFilters obtiene los filtros de consulta de DTEs de los parámetros de la request, no establece la
+// sucursal ni el contribuyente a consultar, eso le corresponde a cada caso de uso según el tipo de token
+func ParseDTEQueryFilters(r *http.Request) (*dte.DTEFilters, error) {
+	filters := &dte.DTEFilters{
+		IncludeAll: r.URL.Query().Get("all") == "true",
 	}
 
-	// 2. Establecer los filtros de la request a la estructura de filtros
+	// 1. Establecer los filtros de la request a la estructura de filtros
 	startDateStr := r.URL.Query().Get("startDate")
 	endDateStr := r.URL.Query().Get("endDate")
 
-	// 2.1 Fechas
+	// 1.1 Fechas
 	var startDate, endDate *time.Time
 	if startDateStr != "" {
 		parsedStartDate, err := time.Parse(time.RFC3339, startDateStr)
 		if err != nil {
-			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "parseDTEFilters", "InvalidQueryParam", "startDate", "0000-00-00")
+			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "startDate", "0000-00-00")
 		}
 		startDate = &parsedStartDate
 	}
@@ -80,21 +113,21 @@ func parseDTEFilters(r *http.Request) (*dte.DTEFilters, error) {
 	if endDateStr != "" {
 		parsedEndDate, err := time.Parse(time.RFC3339, endDateStr)
 		if err != nil {
-			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "parseDTEFilters", "InvalidQueryParam", "endDate", "0000-00-00")
+			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "endDate", "0000-00-00")
 		}
 		endDate = &parsedEndDate
 	}
 
-	// 2.2 Status
+	// 1.2 Status
 	if status := r.URL.Query().Get("status"); status != "" {
 		if !constants.ValidReceiverDocumentStates[strings.ToUpper(status)] {
-			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "parseDTEFilters", "InvalidQueryParam", "status", "'received', 'invalidated', 'rejected'", nil)
+			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "status", "'received', 'invalidated', 'rejected'", nil)
 		} else {
 			filters.Status = strings.ToUpper(status)
 		}
 	}
 
-	// 2.3 Paginación
+	// 1.3 Paginación
 	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
 		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
 			filters.Page = page
@@ -105,16 +138,16 @@ func parseDTEFilters(r *http.Request) (*dte.DTEFilters, error) {
 		filters.Page = 1 // Default
 	}
 
-	// 2.4 Transmisión
+	// 1.4 Transmisión
 	if transmission := r.URL.Query().Get("transmission"); transmission != "" {
 		if !constants.ValidTransmissionTypes[strings.ToUpper(transmission)] {
-			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "parseDTEFilters", "InvalidQueryParam", "transmission", "'normal', 'contingency'", nil)
+			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "transmission", "'normal', 'contingency'", nil)
 		} else {
 			filters.Transmission = strings.ToUpper(transmission)
 		}
 	}
 
-	// 2.5 Tamaño de página
+	// 1.5 Tamaño de página
 	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
 		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
 			filters.PageSize = pageSize
@@ -125,10 +158,10 @@ func parseDTEFilters(r *http.Request) (*dte.DTEFilters, error) {
 		filters.PageSize = 5 // Default
 	}
 
-	// 2.6 Tipo de DTE
+	// 1.6 Tipo de DTE
 	if dteType := r.URL.Query().Get("type"); dteType != "" {
 		if !constants.ValidDTETypes[dteType] {
-			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "parseDTEFilters", "InvalidQueryParam", "type", "01-15")
+			return nil, shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "type", "01-15")
 		} else {
 			filters.DTEType = dteType
 		}
@@ -137,5 +170,63 @@ func parseDTEFilters(r *http.Request) (*dte.DTEFilters, error) {
 	filters.StartDate = startDate
 	filters.EndDate = endDate
 
+	// 2. Filtros de búsqueda sobre el contenido del DTE
+	if err := parseDTESearchFilters(r, filters); err != nil {
+		return nil, err
+	}
+
 	return filters, nil
 }
+
+func parseDTESearchFilters(r *http.Request, filters *dte.DTEFilters) error {
+	query := r.URL.Query()
+
+	// 1. Receptor, número de control y sello de recepción
+	filters.ReceiverNIT = strings.ReplaceAll(strings.TrimSpace(query.Get("receiverNit")), "-", "")
+	filters.ReceiverNRC = strings.ReplaceAll(strings.TrimSpace(query.Get("receiverNrc")), "-", "")
+	filters.ReceiverName = strings.TrimSpace(query.Get("receiverName"))
+	filters.ControlNumber = strings.TrimSpace(query.Get("controlNumber"))
+	filters.ReceptionStamp = strings.TrimSpace(query.Get("receptionStamp"))
+
+	// 2. Ítems del documento
+	filters.ItemCode = strings.TrimSpace(query.Get("itemCode"))
+	filters.ItemDescription = strings.TrimSpace(query.Get("itemDescription"))
+
+	// 3. Rango de montos
+	if minAmountStr := query.Get("minAmount"); minAmountStr != "" {
+		minAmount, err := strconv.ParseFloat(minAmountStr, 64)
+		if err != nil || minAmount < 0 {
+			return shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "minAmount", "a positive number")
+		}
+		filters.MinAmount = &minAmount
+	}
+
+	if maxAmountStr := query.Get("maxAmount"); maxAmountStr != "" {
+		maxAmount, err := strconv.ParseFloat(maxAmountStr, 64)
+		if err != nil || maxAmount < 0 {
+			return shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "maxAmount", "a positive number")
+		}
+		filters.MaxAmount = &maxAmount
+	}
+
+	if filters.MinAmount != nil && filters.MaxAmount != nil && *filters.MinAmount > *filters.MaxAmount {
+		return shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "minAmount", "less than or equal to maxAmount")
+	}
+
+	// 4. Ordenamiento
+	if sortBy := strings.ToLower(query.Get("sortBy")); sortBy != "" {
+		if !dte.ValidSortFields[sortBy] {
+			return shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "sortBy", "'date', 'amount', 'control_number', 'receiver_name'")
+		}
+		filters.SortBy = sortBy
+	}
+
+	if sortOrder := strings.ToLower(query.Get("sortOrder")); sortOrder != "" {
+		if sortOrder != dte.SortAsc && sortOrder != dte.SortDesc {
+			return shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", "sortOrder", "'asc', 'desc'")
+		}
+		filters.SortOrder = sortOrder
+	}
+
+	return nil
+}
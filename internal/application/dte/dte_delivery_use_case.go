@@ -0,0 +1,38 @@
+package dte
+
+import (
+	"context"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	deliveryModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+)
+
+type DTEDeliveryUseCase struct {
+	deliveryManager delivery.DeliveryManager
+}
+
+func NewDTEDeliveryUseCase(deliveryManager delivery.DeliveryManager) *DTEDeliveryUseCase {
+	return &DTEDeliveryUseCase{
+		deliveryManager: deliveryManager,
+	}
+}
+
+// ResendDTE reenvía un DTE de la sucursal autenticada por correo electrónico
+func (u *DTEDeliveryUseCase) ResendDTE(ctx context.Context, generationCode string, req *structs.ResendDTERequest) (*deliveryModels.DeliveryResult, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	var recipients []string
+	if req != nil {
+		recipients = req.Recipients
+	}
+
+	return u.deliveryManager.DeliverDTE(ctx, claims.BranchID, generationCode, recipients)
+}
+
+// GetDeliveries obtiene el historial de envíos de un DTE de la sucursal autenticada
+func (u *DTEDeliveryUseCase) GetDeliveries(ctx context.Context, generationCode string) ([]deliveryModels.Delivery, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.deliveryManager.GetDeliveries(ctx, claims.BranchID, generationCode)
+}
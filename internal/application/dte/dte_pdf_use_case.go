@@ -0,0 +1,100 @@
+package dte
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
+	pdfModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+type DTEPDFUseCase struct {
+	dteService dte_documents.DTEManager
+	pdfManager pdf.PDFManager
+}
+
+func NewDTEPDFUseCase(dteService dte_documents.DTEManager, pdfManager pdf.PDFManager) *DTEPDFUseCase {
+	return &DTEPDFUseCase{
+		dteService: dteService,
+		pdfManager: pdfManager,
+	}
+}
+
+// GeneratePDF genera la representación gráfica de un DTE, retorna el contenido del PDF y el nombre del archivo
+func (u *DTEPDFUseCase) GeneratePDF(ctx context.Context, generationCode, receiptType string) ([]byte, string, error) {
+	// 1. Obtener los claims del contexto
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 2. Validar el tipo de comprobante solicitado
+	if receiptType == "" {
+		receiptType = pdfModels.ReceiptDocument
+	}
+	receiptType = strings.ToLower(receiptType)
+	if receiptType != pdfModels.ReceiptDocument && receiptType != pdfModels.ReceiptInvalidation {
+		return nil, "", shared_error.NewFormattedGeneralServiceError("DTEPDFUseCase", "GeneratePDF", "InvalidReceiptType", receiptType)
+	}
+
+	// 3. Obtener el DTE por su código de generación
+	document, err := u.dteService.GetByGenerationCode(ctx, claims.BranchID, generationCode)
+	if err != nil {
+		return nil, "", shared_error.NewFormattedGeneralServiceWithError("DTEPDFUseCase", "GeneratePDF", err, "FailedToGetDTE", generationCode)
+	}
+
+	// 4. Generar el PDF con la configuración de marca de la sucursal
+	return u.pdfManager.GenerateFromDocument(ctx, document, receiptType)
+}
+
+// GetBranding obtiene la configuración de marca de la sucursal autenticada
+func (u *DTEPDFUseCase) GetBranding(ctx context.Context) (*pdfModels.Branding, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.pdfManager.GetBranding(ctx, claims.BranchID)
+}
+
+// UpdateBranding actualiza la configuración de marca de la sucursal autenticada
+func (u *DTEPDFUseCase) UpdateBranding(ctx context.Context, req *structs.UpdateBrandingRequest) (*pdfModels.Branding, error) {
+	// 1. Obtener los claims del contexto
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	// 2. Obtener la configuración actual para conservar los campos no enviados
+	branding, err := u.pdfManager.GetBranding(ctx, claims.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Aplicar los cambios de la solicitud
+	if req.PrimaryColor != "" {
+		branding.PrimaryColor = req.PrimaryColor
+	}
+	if req.FooterText != "" {
+		branding.FooterText = req.FooterText
+	}
+
+	if req.RemoveLogo {
+		branding.Logo = nil
+		branding.LogoFormat = ""
+	} else if req.Logo != nil {
+		if req.LogoFormat == "" {
+			return nil, dte_errors.NewValidationError("RequiredField", "logo_format")
+		}
+
+		logo, err := base64.StdEncoding.DecodeString(*req.Logo)
+		if err != nil {
+			return nil, dte_errors.NewValidationError("InvalidFormat", "logo", "base64", "invalid encoding")
+		}
+		branding.Logo = logo
+		branding.LogoFormat = req.LogoFormat
+	}
+
+	// 4. Validar y guardar la configuración
+	if err = u.pdfManager.SaveBranding(ctx, branding); err != nil {
+		return nil, err
+	}
+
+	return branding, nil
+}
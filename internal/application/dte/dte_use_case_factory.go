@@ -2,10 +2,13 @@ package dte
 
 import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/invalidation"
 	domainPort "github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper"
 )
 
@@ -14,6 +17,9 @@ type DTEUseCaseFactory struct {
 	authService       auth.AuthManager
 	dteService        dte_documents.DTEManager
 	transmitter       ports.BaseTransmitter
+	deliveryManager   delivery.DeliveryManager
+	auditManager      audit.AuditManager
+	webhookManager    webhook.WebhookManager
 	mapperFactory     *mapper.MapperFactory
 	operationsFactory *DTEOperations
 }
@@ -23,11 +29,17 @@ func NewDTEUseCaseFactory(
 	authService auth.AuthManager,
 	dteService dte_documents.DTEManager,
 	transmitter ports.BaseTransmitter,
+	deliveryManager delivery.DeliveryManager,
+	auditManager audit.AuditManager,
+	webhookManager webhook.WebhookManager,
 ) *DTEUseCaseFactory {
 	return &DTEUseCaseFactory{
 		authService:       authService,
 		dteService:        dteService,
 		transmitter:       transmitter,
+		deliveryManager:   deliveryManager,
+		auditManager:      auditManager,
+		webhookManager:    webhookManager,
 		mapperFactory:     mapper.NewMapperFactory(),
 		operationsFactory: NewDTEOperations(),
 	}
@@ -43,6 +55,9 @@ func (f *DTEUseCaseFactory) CreateInvoiceUseCase(invoiceService domainPort.DTESe
 		f.mapperFactory.CreateInvoiceMapperAdapter(),
 		f.mapperFactory.GetInvoiceResponseMapper(),
 		f.operationsFactory.GetNoOperation(),
+		f.deliveryManager,
+		f.auditManager,
+		f.webhookManager,
 	)
 }
 
@@ -56,6 +71,9 @@ func (f *DTEUseCaseFactory) CreateCCFUseCase(ccfService domainPort.DTEService) *
 		f.mapperFactory.CreateCCFMapperAdapter(),
 		f.mapperFactory.GetCCFResponseMapper(),
 		f.operationsFactory.GetNoOperation(),
+		f.deliveryManager,
+		f.auditManager,
+		f.webhookManager,
 	)
 }
 
@@ -69,6 +87,9 @@ func (f *DTEUseCaseFactory) CreateCreditNoteUseCase(creditNoteService domainPort
 		f.mapperFactory.CreateCreditNoteMapperAdapter(),
 		f.mapperFactory.GetCreditNoteResponseMapper(),
 		f.operationsFactory.GetCreditNoteOperations(f.dteService),
+		f.deliveryManager,
+		f.auditManager,
+		f.webhookManager,
 	)
 }
 
@@ -82,6 +103,9 @@ func (f *DTEUseCaseFactory) CreateRetentionUseCase(retentionService domainPort.D
 		f.mapperFactory.CreateRetentionMapperAdapter(),
 		f.mapperFactory.GetRetentionResponseMapper(),
 		f.operationsFactory.GetNoOperation(),
+		f.deliveryManager,
+		f.auditManager,
+		f.webhookManager,
 	)
 }
 
@@ -93,5 +117,7 @@ func (f *DTEUseCaseFactory) CreateInvalidationUseCase(
 		invalidationManager,
 		f.authService,
 		f.transmitter,
+		f.auditManager,
+		f.webhookManager,
 	)
 }
@@ -2,17 +2,27 @@ package dte
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	appPorts "github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	auditModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
 	transmissionPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	transmitterModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter/hacienda_error"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
@@ -25,6 +35,9 @@ type GenericDTEUseCase struct {
 	mapper         mapper.DTEMapper
 	responseMapper mapper.ResponseMapperFunc
 	additionalOps  AdditionalOperationsFunc
+	delivery       delivery.DeliveryManager
+	audit          audit.AuditManager
+	webhooks       webhook.WebhookManager
 }
 
 // NewGenericDTEUseCase crea una nueva instancia de GenericDTEUseCase
@@ -36,6 +49,9 @@ func NewGenericDTEUseCase(
 	mapper mapper.DTEMapper,
 	responseMapper mapper.ResponseMapperFunc,
 	additionalOps AdditionalOperationsFunc,
+	deliveryManager delivery.DeliveryManager,
+	auditManager audit.AuditManager,
+	webhookManager webhook.WebhookManager,
 ) *GenericDTEUseCase {
 	return &GenericDTEUseCase{
 		authService:    authService,
@@ -45,11 +61,23 @@ func NewGenericDTEUseCase(
 		mapper:         mapper,
 		responseMapper: responseMapper,
 		additionalOps:  additionalOps,
+		delivery:       deliveryManager,
+		audit:          auditManager,
+		webhooks:       webhookManager,
 	}
 }
 
-// Create procesa cualquier tipo de DTE utilizando un flujo genérico
+// Create procesa cualquier tipo de DTE utilizando un flujo genérico, el procesamiento completo se registra en un span
 func (u *GenericDTEUseCase) Create(ctx context.Context, req interface{}) (interface{}, *response.SuccessOptions, error) {
+	ctx, span := tracing.Start(ctx, "GenericDTEUseCase.Create")
+	mhModel, options, err := u.create(ctx, req)
+	tracing.End(span, err)
+
+	return mhModel, options, err
+}
+
+// create ejecuta el flujo genérico de emisión de un DTE
+func (u *GenericDTEUseCase) create(ctx context.Context, req interface{}) (interface{}, *response.SuccessOptions, error) {
 	// 1. Obtener los claims y el token del contexto
 	claims := ctx.Value("claims").(*models.AuthClaims)
 	token := ctx.Value("token").(string)
@@ -57,21 +85,21 @@ func (u *GenericDTEUseCase) Create(ctx context.Context, req interface{}) (interf
 	// 2. Obtener la información del emisor
 	issuer, err := u.authService.GetIssuer(ctx, claims.BranchID)
 	if err != nil {
-		logs.Error("Error getting issuer information", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error getting issuer information", map[string]interface{}{"error": err.Error()})
 		return nil, nil, err
 	}
 
 	// 3. Mapear a modelo de dominio
 	domainModel, err := u.mapper.MapToDomainModel(req, issuer)
 	if err != nil {
-		logs.Error("Error mapping to domain model", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error mapping to domain model", map[string]interface{}{"error": err.Error()})
 		return nil, nil, err
 	}
 
 	// 4. Crear DTE a nivel de servicio
 	result, err := u.service.Create(ctx, domainModel, claims.BranchID)
 	if err != nil {
-		logs.Error("Error creating DTE at service level", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error creating DTE at service level", map[string]interface{}{"error": err.Error()})
 		return nil, nil, err
 	}
 
@@ -81,7 +109,7 @@ func (u *GenericDTEUseCase) Create(ctx context.Context, req interface{}) (interf
 	// 6. Extraer el código de generación
 	generationCode, err := extractGenerationCode(mhModel)
 	if err != nil {
-		logs.Error("Error extracting generation code", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error extracting generation code", map[string]interface{}{"error": err.Error()})
 		return nil, nil, err
 	}
 
@@ -95,7 +123,8 @@ func (u *GenericDTEUseCase) Create(ctx context.Context, req interface{}) (interf
 	// 8. Comenzar la transmisión del documento
 	transmitResult, err := u.transmitter.RetryTransmission(ctx, mhModel, token, claims.NIT)
 	if err != nil {
-		logs.Error("Error transmitting document", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error transmitting document", map[string]interface{}{"error": err.Error()})
+		u.emitRejected(ctx, claims, mhModel, err)
 		return mhModel, options, err
 	}
 	options.ReceptionStamp = transmitResult.ReceptionStamp
@@ -103,22 +132,115 @@ func (u *GenericDTEUseCase) Create(ctx context.Context, req interface{}) (interf
 	// 9. Guardar el documento en la base de datos
 	err = u.dteService.Create(ctx, mhModel, constants.TransmissionNormal, constants.DocumentReceived, transmitResult.ReceptionStamp)
 	if err != nil {
-		logs.Error("Error saving document in database", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error saving document in database", map[string]interface{}{"error": err.Error()})
 		return mhModel, options, err
 	}
 
-	// 10. Ejecutar operaciones adicionales específicas (si las hay)
+	// 10. Guardar el JWS firmado y la respuesta de Hacienda, el documento ya fue recibido por lo que un error no detiene el flujo
+	saveTransmissionArtifacts(ctx, u.dteService, generationCode, transmitResult)
+
+	// 11. Registrar la emisión en la auditoría y notificarla a las suscripciones de webhooks
+	u.recordIssued(ctx, claims, mhModel, transmitResult.ReceptionStamp)
+	u.emitEvent(ctx, webhookModels.EventDTEIssued, claims, mhModel, map[string]interface{}{
+		"reception_stamp": utils.PointerToString(transmitResult.ReceptionStamp),
+	})
+
+	// 12. Ejecutar operaciones adicionales específicas (si las hay)
 	if u.additionalOps != nil {
 		err = u.additionalOps(ctx, result, claims.BranchID, mhModel)
 		if err != nil {
-			logs.Error("Error executing additional operations", map[string]interface{}{"error": err.Error()})
+			logs.ErrorContext(ctx, "Error executing additional operations", map[string]interface{}{"error": err.Error()})
 			return mhModel, options, err
 		}
 	}
 
+	// 13. Programar el envío del DTE al receptor por correo electrónico
+	if u.delivery != nil {
+		u.delivery.DeliverDTEAsync(ctx, claims.BranchID, generationCode)
+	}
+
 	return mhModel, options, nil
 }
 
+// recordIssued registra la emisión de un DTE en la auditoría junto con el digest del documento transmitido
+func (u *GenericDTEUseCase) recordIssued(ctx context.Context, claims *models.AuthClaims, mhModel interface{}, receptionStamp *string) {
+	if u.audit == nil {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"reception_stamp": utils.PointerToString(receptionStamp),
+	}
+	if info, err := utils.ExtractAuxiliarIdentification(mhModel); err == nil {
+		payload["generation_code"] = info.Identification.GenerationCode
+		payload["control_number"] = info.Identification.ControlNumber
+		payload["dte_type"] = info.Identification.DTEType
+	}
+	if document, err := json.Marshal(mhModel); err == nil {
+		payload["document_digest"] = auditModels.PayloadDigest(document)
+	}
+
+	u.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventDTEIssued,
+		UserID:    claims.ClientID,
+		BranchID:  claims.BranchID,
+		Payload:   payload,
+	})
+}
+
+// emitRejected notifica a las suscripciones de webhooks el rechazo de un DTE por parte de Hacienda, los demás errores
+// de transmisión no se notifican porque el documento puede almacenarse en contingencia
+func (u *GenericDTEUseCase) emitRejected(ctx context.Context, claims *models.AuthClaims, mhModel interface{}, err error) {
+	var haciendaErr *hacienda_error.HaciendaResponseError
+	if !errors.As(err, &haciendaErr) || haciendaErr.Status != "RECHAZADO" {
+		return
+	}
+
+	u.emitEvent(ctx, webhookModels.EventDTERejected, claims, mhModel, map[string]interface{}{
+		"code":         haciendaErr.Code,
+		"description":  haciendaErr.Description,
+		"observations": haciendaErr.Observations,
+	})
+}
+
+// emitEvent notifica un evento de un DTE a las suscripciones de webhooks del contribuyente
+func (u *GenericDTEUseCase) emitEvent(ctx context.Context, eventType string, claims *models.AuthClaims, mhModel interface{}, data map[string]interface{}) {
+	if u.webhooks == nil {
+		return
+	}
+
+	if info, err := utils.ExtractAuxiliarIdentification(mhModel); err == nil {
+		data["generation_code"] = info.Identification.GenerationCode
+		data["control_number"] = info.Identification.ControlNumber
+		data["dte_type"] = info.Identification.DTEType
+	}
+
+	u.webhooks.Emit(ctx, &webhookModels.Event{
+		Type:     eventType,
+		BranchID: claims.BranchID,
+		Data:     data,
+	})
+}
+
+// saveTransmissionArtifacts guarda los artefactos de la transmisión de un DTE registrando un aviso si no se pudieron guardar
+func saveTransmissionArtifacts(ctx context.Context, dteService transmissionPorts.DTEManager, generationCode string, result *transmitterModels.TransmitResult) {
+	signedDocument := utils.ToStringPointer(result.SignedDocument)
+
+	var mhResponse *string
+	if result.Response != nil {
+		if response, err := json.Marshal(result.Response); err == nil {
+			mhResponse = utils.ToStringPointer(string(response))
+		}
+	}
+
+	if err := dteService.SaveArtifacts(ctx, generationCode, signedDocument, mhResponse); err != nil {
+		logs.WarnContext(ctx, "Failed to save transmission artifacts", map[string]interface{}{
+			"generationCode": generationCode,
+			"error":          err.Error(),
+		})
+	}
+}
+
 // extractGenerationCode extrae el código de generación usando reflexión
 func extractGenerationCode(mhModel interface{}) (string, error) {
 	extractor, err := utils.ExtractAuxiliarIdentification(mhModel)
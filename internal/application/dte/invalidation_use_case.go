@@ -5,16 +5,21 @@ import (
 	structs2 "github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	auditModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
 	authManager "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
 	dteInterfaces "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/invalidation"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
 type InvalidationUseCase struct {
@@ -23,14 +28,18 @@ type InvalidationUseCase struct {
 	invalidationManager invalidation.InvalidationManager
 	mapper              *request_mapper.InvalidationMapper
 	transmitter         ports.BaseTransmitter
+	audit               audit.AuditManager
+	webhooks            webhook.WebhookManager
 }
 
-func NewInvalidationUseCase(dteManager dteInterfaces.DTEManager, invalidationManager invalidation.InvalidationManager, authManager authManager.AuthManager, transmitter ports.BaseTransmitter) *InvalidationUseCase {
+func NewInvalidationUseCase(dteManager dteInterfaces.DTEManager, invalidationManager invalidation.InvalidationManager, authManager authManager.AuthManager, transmitter ports.BaseTransmitter, auditManager audit.AuditManager, webhookManager webhook.WebhookManager) *InvalidationUseCase {
 	return &InvalidationUseCase{
 		dteManager:          dteManager,
 		invalidationManager: invalidationManager,
 		authManager:         authManager,
 		transmitter:         transmitter,
+		audit:               auditManager,
+		webhooks:            webhookManager,
 		mapper:              request_mapper.NewInvalidationMapper(),
 	}
 }
@@ -76,7 +85,7 @@ func (u *InvalidationUseCase) InvalidateDocument(ctx context.Context, request st
 	// 8. Mapear a modelo de hacienda
 	mhInvalidation := response_mapper.ToMHInvalidation(invalidationDocument)
 	if mhInvalidation == nil {
-		logs.Error("Error mapping invoice to hacienda model", map[string]interface{}{"error": "nil model"})
+		logs.ErrorContext(ctx, "Error mapping invoice to hacienda model", map[string]interface{}{"error": "nil model"})
 		return nil, shared_error.NewFormattedGeneralServiceError("InvalidationUseCase", "InvalidateDocument", "ErrorMapping", "MH model")
 	}
 
@@ -86,18 +95,50 @@ func (u *InvalidationUseCase) InvalidateDocument(ctx context.Context, request st
 		return nil, err
 	}
 	if result.Status != ReceivedStatus {
-		logs.Warn("Error transmitting invalidation", map[string]interface{}{"error": "TransmissionFailed"})
+		logs.WarnContext(ctx, "Error transmitting invalidation", map[string]interface{}{"error": "TransmissionFailed"})
 		return nil, dte_errors.NewDTEErrorSimple("TransmissionFailed")
 	}
 
 	// 10. Invalidar documento original
 	if err := u.invalidationManager.InvalidateDocument(ctx, claims.BranchID, request.GenerationCode); err != nil {
-		logs.Error("Failed to update original DTE status", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to update original DTE status", map[string]interface{}{
 			"error": err.Error(),
 			"code":  request.GenerationCode,
 		})
 		return nil, err
 	}
 
+	// 11. Registrar la invalidación en la auditoría
+	payload := map[string]interface{}{
+		"generation_code":             request.GenerationCode,
+		"replacement_generation_code": utils.PointerToString(request.ReplacementGenerationCode),
+		"reception_stamp":             utils.PointerToString(result.ReceptionStamp),
+	}
+	if request.Reason != nil {
+		payload["reason_type"] = request.Reason.Type
+		payload["reason"] = utils.PointerToString(request.Reason.Reason)
+		payload["responsible_num_doc"] = request.Reason.ResponsibleNumDoc
+		payload["requestor_num_doc"] = request.Reason.RequestorNumDoc
+	}
+	u.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventDTEInvalidated,
+		UserID:    claims.ClientID,
+		BranchID:  claims.BranchID,
+		Payload:   payload,
+	})
+
+	// 12. Notificar la invalidación a las suscripciones de webhooks
+	u.webhooks.Emit(ctx, &webhookModels.Event{
+		Type:     webhookModels.EventDTEInvalidated,
+		BranchID: claims.BranchID,
+		Data: map[string]interface{}{
+			"generation_code":             request.GenerationCode,
+			"dte_type":                    originalDTE.Details.DTEType,
+			"control_number":              originalDTE.Details.ControlNumber,
+			"replacement_generation_code": utils.PointerToString(request.ReplacementGenerationCode),
+			"reception_stamp":             utils.PointerToString(result.ReceptionStamp),
+		},
+	})
+
 	return mhInvalidation, nil
 }
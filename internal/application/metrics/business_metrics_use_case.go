@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	authConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// maxCustomPeriodDays cantidad máxima de días de un período personalizado
+const maxCustomPeriodDays = 366
+
+type BusinessMetricsUseCase struct {
+	metricsManager metrics.BusinessMetricsManager
+}
+
+func NewBusinessMetricsUseCase(metricsManager metrics.BusinessMetricsManager) *BusinessMetricsUseCase {
+	return &BusinessMetricsUseCase{
+		metricsManager: metricsManager,
+	}
+}
+
+// GetBusinessMetrics obtiene las métricas de negocio del período solicitado para la sucursal autenticada o, con
+// all=true, para todas las sucursales del contribuyente
+func (u *BusinessMetricsUseCase) GetBusinessMetrics(ctx context.Context, r *http.Request) (*models.Metrics, error) {
+	// 1. Parsear el período y las sucursales a consultar
+	filters, err := ParseBusinessMetricsFilters(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Obtener las métricas
+	return u.metricsManager.GetBusinessMetrics(ctx, filters)
+}
+
+// ParseBusinessMetricsFilters obtiene el período y las sucursales de las métricas de los parámetros de la solicitud.
+// Los períodos se calculan en la zona horaria del sistema: today desde el inicio del día, month desde el inicio del
+// mes y custom entre start_date y end_date (YYYY-MM-DD), la fecha de fin incluye todo el día
+func ParseBusinessMetricsFilters(r *http.Request) (*models.BusinessMetricsFilters, error) {
+	query := r.URL.Query()
+	claims := r.Context().Value("claims").(*authModels.AuthClaims)
+	now := utils.TimeNow()
+
+	filters := &models.BusinessMetricsFilters{
+		Period: strings.ToLower(strings.TrimSpace(query.Get("period"))),
+	}
+	if filters.Period == "" {
+		filters.Period = models.PeriodToday
+	}
+
+	// 1. Sucursales, todas las sucursales del contribuyente requieren acceso a todas las sucursales
+	if query.Get("all") == "true" {
+		if !claims.HasScope(authConstants.ScopeDTEReadAllBranches) {
+			return nil, shared_error.NewFormattedGeneralServiceError("BusinessMetricsUseCase", "ParseBusinessMetricsFilters", "InsufficientScope", authConstants.ScopeDTEReadAllBranches)
+		}
+		filters.ClientID = claims.ClientID
+	} else {
+		filters.BranchID = claims.BranchID
+	}
+
+	// 2. Período
+	location := now.Location()
+	switch filters.Period {
+	case models.PeriodToday:
+		filters.StartDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+		filters.EndDate = now
+	case models.PeriodMonth:
+		filters.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+		filters.EndDate = now
+	case models.PeriodCustom:
+		startDate, err := time.ParseInLocation("2006-01-02", query.Get("start_date"), location)
+		if err != nil {
+			return nil, shared_error.NewFormattedGeneralServiceError("BusinessMetricsUseCase", "ParseBusinessMetricsFilters", "InvalidQueryParam", "start_date", "YYYY-MM-DD")
+		}
+
+		endDate, err := time.ParseInLocation("2006-01-02", query.Get("end_date"), location)
+		if err != nil {
+			return nil, shared_error.NewFormattedGeneralServiceError("BusinessMetricsUseCase", "ParseBusinessMetricsFilters", "InvalidQueryParam", "end_date", "YYYY-MM-DD")
+		}
+
+		if endDate.Before(startDate) || endDate.Sub(startDate) >= maxCustomPeriodDays*24*time.Hour {
+			return nil, shared_error.NewFormattedGeneralServiceError("BusinessMetricsUseCase", "ParseBusinessMetricsFilters", "InvalidMetricsPeriod",
+				query.Get("start_date"), query.Get("end_date"), maxCustomPeriodDays)
+		}
+
+		filters.StartDate = startDate
+		filters.EndDate = endDate.Add(24*time.Hour - time.Second)
+	default:
+		return nil, shared_error.NewFormattedGeneralServiceError("BusinessMetricsUseCase", "ParseBusinessMetricsFilters", "InvalidQueryParam", "period", "'today', 'month', 'custom'")
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,177 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	notificationModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/notification/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 200
+)
+
+// validNotificationStatuses estados aceptados en el filtro status
+var validNotificationStatuses = map[string]bool{
+	notificationModels.NotificationPending: true,
+	notificationModels.NotificationSent:    true,
+	notificationModels.NotificationFailed:  true,
+}
+
+// notificationChannels canales aceptados en el filtro channel
+var notificationChannels = map[string]string{
+	"email":   notificationModels.ChannelEmail,
+	"webhook": notificationModels.ChannelWebhook,
+}
+
+type NotificationUseCase struct {
+	notificationManager notification.NotificationManager
+}
+
+func NewNotificationUseCase(notificationManager notification.NotificationManager) *NotificationUseCase {
+	return &NotificationUseCase{
+		notificationManager: notificationManager,
+	}
+}
+
+// GetNotifications obtiene las alertas del contribuyente autenticado que cumplen con los filtros de la solicitud
+func (u *NotificationUseCase) GetNotifications(ctx context.Context, r *http.Request) ([]notificationModels.Notification, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	filters, err := ParseNotificationFilters(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.notificationManager.GetNotifications(ctx, claims.ClientID, filters)
+}
+
+// ListRecipients obtiene los destinatarios de las alertas del contribuyente autenticado
+func (u *NotificationUseCase) ListRecipients(ctx context.Context) ([]notificationModels.Recipient, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.notificationManager.ListRecipients(ctx, claims.ClientID, notificationModels.EntityClient)
+}
+
+// CreateRecipient registra un destinatario de las alertas del contribuyente autenticado
+func (u *NotificationUseCase) CreateRecipient(ctx context.Context, req *structs.CreateNotificationRecipientRequest) (*notificationModels.Recipient, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.createRecipient(ctx, claims.ClientID, notificationModels.EntityClient, req)
+}
+
+// DeleteRecipient elimina un destinatario de las alertas del contribuyente autenticado
+func (u *NotificationUseCase) DeleteRecipient(ctx context.Context, id string) error {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.deleteRecipient(ctx, claims.ClientID, notificationModels.EntityClient, id)
+}
+
+// ListAdminRecipients obtiene los destinatarios de la administración, reciben las alertas de todos los contribuyentes
+func (u *NotificationUseCase) ListAdminRecipients(ctx context.Context) ([]notificationModels.Recipient, error) {
+	return u.notificationManager.ListRecipients(ctx, 0, notificationModels.EntityAdmin)
+}
+
+// CreateAdminRecipient registra un destinatario de la administración
+func (u *NotificationUseCase) CreateAdminRecipient(ctx context.Context, req *structs.CreateNotificationRecipientRequest) (*notificationModels.Recipient, error) {
+	return u.createRecipient(ctx, 0, notificationModels.EntityAdmin, req)
+}
+
+// DeleteAdminRecipient elimina un destinatario de la administración
+func (u *NotificationUseCase) DeleteAdminRecipient(ctx context.Context, id string) error {
+	return u.deleteRecipient(ctx, 0, notificationModels.EntityAdmin, id)
+}
+
+// SetCertificateExpiry registra la fecha de vencimiento del certificado de firma del contribuyente autenticado, el
+// certificado vence al final del día indicado
+func (u *NotificationUseCase) SetCertificateExpiry(ctx context.Context, req *structs.SetCertificateExpiryRequest) error {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	var expiresAt *time.Time
+	if value := strings.TrimSpace(req.ExpiresAt); value != "" {
+		date, err := time.ParseInLocation("2006-01-02", value, utils.TimeNow().Location())
+		if err != nil {
+			return dte_errors.NewValidationError("InvalidFormat", "expires_at", "YYYY-MM-DD", req.ExpiresAt)
+		}
+		endOfDay := date.Add(24*time.Hour - time.Second)
+		expiresAt = &endOfDay
+	}
+
+	return u.notificationManager.SetCertificateExpiry(ctx, claims.ClientID, expiresAt)
+}
+
+// createRecipient registra un destinatario de un tipo de entidad, habilitado si la solicitud no indica lo contrario
+func (u *NotificationUseCase) createRecipient(ctx context.Context, userID uint, entityType string, req *structs.CreateNotificationRecipientRequest) (*notificationModels.Recipient, error) {
+	recipient := &notificationModels.Recipient{
+		UserID:      userID,
+		EntityType:  entityType,
+		Email:       strings.TrimSpace(req.Email),
+		EnabledPush: req.EnabledPush == nil || *req.EnabledPush,
+	}
+
+	if err := u.notificationManager.CreateRecipient(ctx, recipient); err != nil {
+		return nil, err
+	}
+
+	return recipient, nil
+}
+
+// deleteRecipient elimina un destinatario de un tipo de entidad
+func (u *NotificationUseCase) deleteRecipient(ctx context.Context, userID uint, entityType, id string) error {
+	recipientID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	return u.notificationManager.DeleteRecipient(ctx, userID, entityType, uint(recipientID))
+}
+
+// ParseNotificationFilters obtiene los filtros de búsqueda de alertas de los parámetros de la solicitud
+func ParseNotificationFilters(r *http.Request) (*notificationModels.NotificationFilters, error) {
+	query := r.URL.Query()
+	filters := &notificationModels.NotificationFilters{
+		Limit: defaultNotificationsLimit,
+	}
+
+	// 1. Tipo de alerta y canal
+	if alert := strings.ToLower(strings.TrimSpace(query.Get("alert"))); alert != "" {
+		if _, ok := notificationModels.Rules[alert]; !ok {
+			return nil, shared_error.NewFormattedGeneralServiceError("NotificationUseCase", "ParseNotificationFilters", "InvalidQueryParam", "alert", "a valid alert type")
+		}
+		filters.Alert = alert
+	}
+
+	if channel := strings.ToLower(strings.TrimSpace(query.Get("channel"))); channel != "" {
+		value, ok := notificationChannels[channel]
+		if !ok {
+			return nil, shared_error.NewFormattedGeneralServiceError("NotificationUseCase", "ParseNotificationFilters", "InvalidQueryParam", "channel", "'email', 'webhook'")
+		}
+		filters.Channel = value
+	}
+
+	// 2. Estado
+	if status := strings.ToUpper(strings.TrimSpace(query.Get("status"))); status != "" {
+		if !validNotificationStatuses[status] {
+			return nil, shared_error.NewFormattedGeneralServiceError("NotificationUseCase", "ParseNotificationFilters", "InvalidQueryParam", "status", "'pending', 'sent', 'failed'")
+		}
+		filters.Status = status
+	}
+
+	// 3. Límite
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > maxNotificationsLimit {
+			return nil, shared_error.NewFormattedGeneralServiceError("NotificationUseCase", "ParseNotificationFilters", "InvalidQueryParam", "limit", "a number between 1 and 200")
+		}
+		filters.Limit = parsed
+	}
+
+	return filters, nil
+}
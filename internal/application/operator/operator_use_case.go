@@ -0,0 +1,209 @@
+package operator
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	dteUseCase "github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+type OperatorUseCase struct {
+	operatorManager operator.OperatorManager
+	dteService      dte_documents.DTEManager
+	cryptManager    ports.CryptManager
+}
+
+func NewOperatorUseCase(operatorManager operator.OperatorManager, dteService dte_documents.DTEManager, cryptManager ports.CryptManager) *OperatorUseCase {
+	return &OperatorUseCase{
+		operatorManager: operatorManager,
+		dteService:      dteService,
+		cryptManager:    cryptManager,
+	}
+}
+
+// Create crea un operador y retorna sus llaves de acceso. El API secret se retorna una única vez, en la base de datos
+// solo se almacena su hash
+func (u *OperatorUseCase) Create(ctx context.Context, req *models.CreateOperatorRequest) (*models.OperatorCredentialsResponse, error) {
+	// 1. Validar la solicitud
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Generar las llaves de acceso del operador
+	apiKey, err := u.cryptManager.GenerateAPIKey()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to generate API key", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorUseCase", "Create", "FailedToCreateOperator")
+	}
+
+	apiSecret, err := u.cryptManager.GenerateAPISecret()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to generate API secret", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorUseCase", "Create", "FailedToCreateOperator")
+	}
+
+	// 3. Crear el operador almacenando solo el hash del API secret
+	op := &models.Operator{
+		Name:      req.Name,
+		Email:     req.Email,
+		APIKey:    apiKey,
+		APISecret: u.cryptManager.HashAPISecret(apiSecret),
+		IsActive:  true,
+	}
+	if err = u.operatorManager.Create(ctx, op); err != nil {
+		return nil, err
+	}
+
+	return &models.OperatorCredentialsResponse{
+		ID:        op.ID,
+		Name:      op.Name,
+		Email:     op.Email,
+		APIKey:    apiKey,
+		APISecret: apiSecret,
+	}, nil
+}
+
+// List obtiene todos los operadores con sus accesos
+func (u *OperatorUseCase) List(ctx context.Context) ([]models.Operator, error) {
+	return u.operatorManager.GetAll(ctx)
+}
+
+// Grant asigna un contribuyente, o una de sus sucursales, al operador indicado
+func (u *OperatorUseCase) Grant(ctx context.Context, id string, req *models.GrantRequest) (*models.OperatorGrant, error) {
+	// 1. Validar la solicitud
+	operatorID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Crear el acceso
+	grant := &models.OperatorGrant{
+		OperatorID: uint(operatorID),
+		UserID:     req.UserID,
+		BranchID:   req.BranchID,
+	}
+	if err = u.operatorManager.Grant(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	return grant, nil
+}
+
+// RevokeGrant elimina un acceso del operador indicado
+func (u *OperatorUseCase) RevokeGrant(ctx context.Context, id, grantID string) error {
+	operatorID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	parsedGrantID, err := strconv.ParseUint(grantID, 10, 64)
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "grantId", "number", grantID)
+	}
+
+	return u.operatorManager.RevokeGrant(ctx, uint(operatorID), uint(parsedGrantID))
+}
+
+// Deactivate desactiva el operador indicado
+func (u *OperatorUseCase) Deactivate(ctx context.Context, id string) error {
+	operatorID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	return u.operatorManager.Deactivate(ctx, uint(operatorID))
+}
+
+// Login autentica a un operador
+func (u *OperatorUseCase) Login(ctx context.Context, credentials *models.OperatorCredentials) (*authModels.TokenResponse, error) {
+	if err := credentials.Validate(); err != nil {
+		return nil, err
+	}
+
+	return u.operatorManager.Login(ctx, credentials)
+}
+
+// ManagedBranches obtiene las sucursales que el operador autenticado puede utilizar como emisor
+func (u *OperatorUseCase) ManagedBranches(ctx context.Context) ([]models.ManagedBranch, error) {
+	claims := ctx.Value("claims").(*authModels.AuthClaims)
+	return u.operatorManager.GetManagedBranches(ctx, claims.OperatorID)
+}
+
+// SwitchIssuer emite un token para que el operador autenticado actúe como una de sus sucursales asignadas
+func (u *OperatorUseCase) SwitchIssuer(ctx context.Context, req *models.SwitchIssuerRequest) (*authModels.TokenResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	claims := ctx.Value("claims").(*authModels.AuthClaims)
+	return u.operatorManager.SwitchIssuer(ctx, claims, req.BranchID)
+}
+
+// ListDocuments obtiene los DTEs de todas las sucursales asignadas al operador autenticado, los parámetros user_id y
+// branch_id permiten limitar la consulta a un contribuyente o a una sucursal
+func (u *OperatorUseCase) ListDocuments(ctx context.Context, r *http.Request) (*dte.DTEListResponse, error) {
+	// 1. Parsear los parámetros de consulta
+	filters, err := dteUseCase.ParseDTEQueryFilters(r)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := parseOptionalID(r, "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	branchID, err := parseOptionalID(r, "branch_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Limitar la consulta a las sucursales asignadas, un slice vacío no retorna documentos
+	branches, err := u.ManagedBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.BranchIDs = make([]uint, 0, len(branches))
+	for _, branch := range branches {
+		if (userID == 0 || branch.UserID == userID) && (branchID == 0 || branch.BranchID == branchID) {
+			filters.BranchIDs = append(filters.BranchIDs, branch.BranchID)
+		}
+	}
+
+	// 3. Obtener los documentos
+	return u.dteService.GetAllDTEs(ctx, filters)
+}
+
+// parseOptionalID obtiene un ID opcional de los parámetros de consulta, cero si no se indica
+func parseOptionalID(r *http.Request, param string) (uint, error) {
+	value := r.URL.Query().Get(param)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, shared_error.NewFormattedGeneralServiceError("OperatorUseCase", "ListDocuments", "InvalidQueryParam", param, "a positive number")
+	}
+
+	return uint(id), nil
+}
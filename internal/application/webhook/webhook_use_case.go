@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+// validDeliveryStatuses estados aceptados en el filtro status
+var validDeliveryStatuses = map[string]bool{
+	webhookModels.DeliveryPending:   true,
+	webhookModels.DeliveryDelivered: true,
+	webhookModels.DeliveryDead:      true,
+}
+
+type WebhookUseCase struct {
+	webhookManager webhook.WebhookManager
+}
+
+func NewWebhookUseCase(webhookManager webhook.WebhookManager) *WebhookUseCase {
+	return &WebhookUseCase{
+		webhookManager: webhookManager,
+	}
+}
+
+// CreateSubscription suscribe una URL a los eventos de los DTE del contribuyente autenticado
+func (u *WebhookUseCase) CreateSubscription(ctx context.Context, req *structs.CreateWebhookSubscriptionRequest) (*webhookModels.SubscriptionCredentialsResponse, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		events = append(events, strings.ToLower(strings.TrimSpace(event)))
+	}
+
+	return u.webhookManager.CreateSubscription(ctx, &webhookModels.Subscription{
+		UserID: claims.ClientID,
+		URL:    strings.TrimSpace(req.URL),
+		Events: events,
+	})
+}
+
+// ListSubscriptions obtiene las suscripciones del contribuyente autenticado
+func (u *WebhookUseCase) ListSubscriptions(ctx context.Context) ([]webhookModels.Subscription, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.webhookManager.ListSubscriptions(ctx, claims.ClientID)
+}
+
+// DeleteSubscription elimina una suscripción del contribuyente autenticado
+func (u *WebhookUseCase) DeleteSubscription(ctx context.Context, id string) error {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	subscriptionID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "id", "number", id)
+	}
+
+	return u.webhookManager.DeleteSubscription(ctx, claims.ClientID, uint(subscriptionID))
+}
+
+// GetDeliveries obtiene las entregas del contribuyente autenticado que cumplen con los filtros de la solicitud
+func (u *WebhookUseCase) GetDeliveries(ctx context.Context, r *http.Request) ([]webhookModels.Delivery, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	filters, err := ParseDeliveryFilters(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.webhookManager.GetDeliveries(ctx, claims.ClientID, filters)
+}
+
+// GetDeadLetters obtiene las entregas del contribuyente autenticado que agotaron sus intentos
+func (u *WebhookUseCase) GetDeadLetters(ctx context.Context, r *http.Request) ([]webhookModels.Delivery, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+
+	filters, err := ParseDeliveryFilters(r)
+	if err != nil {
+		return nil, err
+	}
+	filters.Status = webhookModels.DeliveryDead
+
+	return u.webhookManager.GetDeliveries(ctx, claims.ClientID, filters)
+}
+
+// GetDelivery obtiene una entrega del contribuyente autenticado junto con el registro de sus intentos
+func (u *WebhookUseCase) GetDelivery(ctx context.Context, id string) (*webhookModels.Delivery, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.webhookManager.GetDelivery(ctx, claims.ClientID, id)
+}
+
+// Redeliver programa el reenvío inmediato de una entrega del contribuyente autenticado
+func (u *WebhookUseCase) Redeliver(ctx context.Context, id string) (*webhookModels.Delivery, error) {
+	claims := ctx.Value("claims").(*models.AuthClaims)
+	return u.webhookManager.Redeliver(ctx, claims.ClientID, id)
+}
+
+// ParseDeliveryFilters obtiene los filtros de búsqueda de entregas de los parámetros de la solicitud
+func ParseDeliveryFilters(r *http.Request) (*webhookModels.DeliveryFilters, error) {
+	query := r.URL.Query()
+	filters := &webhookModels.DeliveryFilters{
+		Limit: defaultDeliveriesLimit,
+	}
+
+	// 1. Suscripción
+	if subscriptionID := query.Get("subscription_id"); subscriptionID != "" {
+		id, err := strconv.ParseUint(subscriptionID, 10, 64)
+		if err != nil || id == 0 {
+			return nil, shared_error.NewFormattedGeneralServiceError("WebhookUseCase", "ParseDeliveryFilters", "InvalidQueryParam", "subscription_id", "a positive number")
+		}
+		filters.SubscriptionID = uint(id)
+	}
+
+	// 2. Estado y evento
+	if status := strings.ToUpper(strings.TrimSpace(query.Get("status"))); status != "" {
+		if !validDeliveryStatuses[status] {
+			return nil, shared_error.NewFormattedGeneralServiceError("WebhookUseCase", "ParseDeliveryFilters", "InvalidQueryParam", "status", "'pending', 'delivered', 'dead'")
+		}
+		filters.Status = status
+	}
+
+	if event := strings.ToLower(strings.TrimSpace(query.Get("event"))); event != "" {
+		if !webhookModels.ValidEvents[event] {
+			return nil, shared_error.NewFormattedGeneralServiceError("WebhookUseCase", "ParseDeliveryFilters", "InvalidQueryParam", "event", "a valid webhook event")
+		}
+		filters.EventType = event
+	}
+
+	// 3. Límite
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > maxDeliveriesLimit {
+			return nil, shared_error.NewFormattedGeneralServiceError("WebhookUseCase", "ParseDeliveryFilters", "InvalidQueryParam", "limit", "a number between 1 and 200")
+		}
+		filters.Limit = parsed
+	}
+
+	return filters, nil
+}
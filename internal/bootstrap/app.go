@@ -19,6 +19,7 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/server"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
@@ -27,12 +28,15 @@ type Application struct {
 	server       *server.Server
 	container    *containers.Container
 	dbConnection *drivers.DbConnection
+
+	shutdownTracer func(context.Context) error
 }
 
 // SupportedDrivers contiene la configuración de drivers de base de datos soportados
 var SupportedDrivers = map[string]drivers.DriverConfig{
 	"mysql":    drivers.NewMysqlDriver(),
 	"postgres": drivers.NewPostgresDriver(),
+	"sqlite":   drivers.NewSqliteDriver(),
 }
 
 // NewApplication crea una nueva instancia de la aplicación
@@ -52,12 +56,22 @@ func (app *Application) Initialize() error {
 	}
 
 	// 2. Inicializar el logger
-	err = logs.InitLogger(config.Log.Level, config.Log.Path)
+	err = logs.InitLogger(config.Log.Level, config.Log.Path, config.Log.Format)
 	if err != nil {
 		return fmt.Errorf("error initializing logger: %w", err)
 	}
 	logs.Info("Logger initialized successfully")
 
+	// 2.1 Inicializar el exportador de trazas, se deshabilita si no se configuró un endpoint
+	app.shutdownTracer, err = tracing.InitTracer(context.Background(), config.Tracing.Endpoint, config.Tracing.ServiceName, config.Tracing.SampleRatio)
+	if err != nil {
+		return fmt.Errorf("error initializing tracer: %w", err)
+	}
+	logs.Info("Tracing initialized", map[string]interface{}{
+		"enabled":  config.Tracing.Endpoint != "",
+		"endpoint": config.Tracing.Endpoint,
+	})
+
 	// 3. Inicializar el tiempo global
 	err = utils.TimeInit()
 	if err != nil {
@@ -85,11 +99,17 @@ func (app *Application) Initialize() error {
 		return fmt.Errorf("error initializing container: %w", err)
 	}
 
-	// 7. Inicializar el servidor
+	// 7. Cifrar con la llave maestra activa las credenciales almacenadas con versiones anteriores
+	app.rotateVaultKeys()
+
+	// 7.1 Reprogramar los trabajos de exportación interrumpidos al detenerse la aplicación
+	app.recoverArchiveJobs()
+
+	// 8. Inicializar el servidor
 	app.server = server.Initialize(app.container)
 
-	// 8. Inicializar los jobs
-	err = setup.SetupJobs(app.container.Services().ContingencyManager(), config.Server.AmbientCode, app.dbConnection)
+	// 9. Inicializar los jobs
+	err = setup.SetupJobs(app.container.Services().ContingencyManager(), app.container.Services().WebhookManager(), app.container.Services().NotificationManager(), app.container.Services().ArchiveManager(), config.Server.AmbientCode, app.dbConnection)
 	if err != nil {
 		logs.Error("Failed to setup jobs", map[string]interface{}{"error": err.Error()})
 		return fmt.Errorf("error setting up jobs: %w", err)
@@ -137,6 +157,11 @@ func (app *Application) Start() error {
 			return fmt.Errorf("database connection close error: %w", err)
 		}
 
+		// Enviar las trazas pendientes al collector
+		if err := app.shutdownTracer(ctx); err != nil {
+			logs.Warn("Tracer shutdown error", map[string]interface{}{"error": err.Error()})
+		}
+
 		logs.Info("Shutdown completed", nil)
 	}
 
@@ -145,6 +170,41 @@ func (app *Application) Start() error {
 
 // initDatabaseConfigurations inicializa las configuraciones de la base de datos
 func (app *Application) initDatabaseConfigurations() (*drivers.DbConnection, error) {
+	// 1. Abrir la conexión a la base de datos
+	dbConnection, err := app.openDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Cargar las migraciones del driver
+	migrator, err := database.NewMigrator(dbConnection.Db)
+	if err != nil {
+		logs.Fatal("Failed to load migrations", map[string]interface{}{"error": err.Error()})
+		return nil, err
+	}
+
+	// 3. Aplicar las migraciones pendientes solo si así está definido en la configuración
+	if config.Server.RunMigration {
+		if _, err = migrator.Up(); err != nil {
+			logs.Fatal("Failed to run migrations", map[string]interface{}{"error": err.Error()})
+			return nil, err
+		}
+	}
+
+	// 4. Verificar que la versión del esquema sea la esperada, la aplicación no inicia con un esquema distinto
+	if err = migrator.CheckVersion(); err != nil {
+		logs.Fatal("Unexpected database schema version", map[string]interface{}{
+			"error":           err.Error(),
+			"expectedVersion": migrator.LatestVersion(),
+		})
+		return nil, err
+	}
+
+	return dbConnection, nil
+}
+
+// openDatabase selecciona el driver configurado y abre la conexión a la base de datos
+func (app *Application) openDatabase() (*drivers.DbConnection, error) {
 	// 1. Seleccionar el driver de la base de datos
 	driver := app.selectDatabaseDriver()
 	if driver == nil {
@@ -167,16 +227,32 @@ func (app *Application) initDatabaseConfigurations() (*drivers.DbConnection, err
 		return nil, err
 	}
 
-	// 4. Iniciar migraciones solo si así está definido en la configuración
-	if config.Server.RunMigration {
-		err := database.RunMigrations(dbConnection.Db)
-		if err != nil {
-			logs.Fatal("Failed to run migrations", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
+	return dbConnection, nil
+}
+
+// rotateVaultKeys vuelve a cifrar las llaves de datos del vault de credenciales y los secrets cifrados con la llave
+// maestra activa, un error no detiene la aplicación ya que siguen siendo legibles con su versión anterior
+func (app *Application) rotateVaultKeys() {
+	rotated, err := app.container.Services().CredentialVault().RotateKeys(context.Background())
+	if err != nil {
+		logs.Warn("Failed to rotate credential vault keys", map[string]interface{}{"error": err.Error()})
+		return
 	}
 
-	return dbConnection, nil
+	if rotated > 0 {
+		logs.Info("Credential vault keys rotated", map[string]interface{}{
+			"activeKey": config.Vault.ActiveKey,
+			"rotated":   rotated,
+		})
+	}
+}
+
+// recoverArchiveJobs reprograma los trabajos de exportación que quedaron pendientes o en proceso, un error no detiene la
+// aplicación ya que los trabajos pueden volver a solicitarse
+func (app *Application) recoverArchiveJobs() {
+	if err := app.container.Services().ArchiveManager().RecoverJobs(context.Background()); err != nil {
+		logs.Warn("Failed to recover archive jobs", map[string]interface{}{"error": err.Error()})
+	}
 }
 
 // selectDatabaseDriver selecciona el driver de la base de datos según la configuración del entorno
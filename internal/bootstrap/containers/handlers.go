@@ -11,12 +11,22 @@ type HandlerContainer struct {
 	useCases *UseCaseContainer
 	services *ServicesContainer
 
-	authHandler        *handlers.AuthHandler
-	dteHandler         *handlers.DTEHandler
-	healthHandler      *handlers.HealthHandler
-	testHandler        *handlers.TestHandler
-	metricsHandler     *handlers.MetricsHandler
-	contingencyHandler *helpers.ContingencyHandler
+	authHandler         *handlers.AuthHandler
+	branchHandler       *handlers.BranchHandler
+	registrationHandler *handlers.RegistrationHandler
+	auditHandler        *handlers.AuditHandler
+	operatorHandler     *handlers.OperatorHandler
+	dteHandler          *handlers.DTEHandler
+	healthHandler       *handlers.HealthHandler
+	testHandler         *handlers.TestHandler
+	metricsHandler      *handlers.MetricsHandler
+	pdfHandler          *handlers.PDFHandler
+	deliveryHandler     *handlers.DeliveryHandler
+	archiveHandler      *handlers.ArchiveHandler
+	webhookHandler      *handlers.WebhookHandler
+	activityHandler     *handlers.ActivityHandler
+	notificationHandler *handlers.NotificationHandler
+	contingencyHandler  *helpers.ContingencyHandler
 }
 
 func NewHandlerContainer(useCases *UseCaseContainer, services *ServicesContainer) *HandlerContainer {
@@ -31,7 +41,17 @@ func (c *HandlerContainer) Initialize() {
 	c.healthHandler = handlers.NewHealthHandler(c.services.HealthManager())
 	c.testHandler = handlers.NewTestHandler(c.services.TestManager())
 	c.authHandler = handlers.NewAuthHandler(c.useCases.AuthUseCase())
-	c.metricsHandler = handlers.NewMetricsHandler(c.services.MetricsManager())
+	c.branchHandler = handlers.NewBranchHandler(c.useCases.BranchUseCase())
+	c.registrationHandler = handlers.NewRegistrationHandler(c.useCases.RegistrationUseCase())
+	c.auditHandler = handlers.NewAuditHandler(c.useCases.AuditUseCase())
+	c.operatorHandler = handlers.NewOperatorHandler(c.useCases.OperatorUseCase())
+	c.metricsHandler = handlers.NewMetricsHandler(c.services.MetricsManager(), c.useCases.BusinessMetricsUseCase())
+	c.pdfHandler = handlers.NewPDFHandler(c.useCases.DTEPDFUseCase())
+	c.deliveryHandler = handlers.NewDeliveryHandler(c.useCases.DTEDeliveryUseCase())
+	c.archiveHandler = handlers.NewArchiveHandler(c.useCases.DTEArchiveUseCase())
+	c.webhookHandler = handlers.NewWebhookHandler(c.useCases.WebhookUseCase())
+	c.activityHandler = handlers.NewActivityHandler(c.useCases.ActivityUseCase())
+	c.notificationHandler = handlers.NewNotificationHandler(c.useCases.NotificationUseCase())
 	c.dteHandler = handlers.NewDTEHandler(c.useCases.DTEConsultUseCase(), c.useCases.InvalidationUseCase(),
 		c.initializeGenericCreatorHandler(c.contingencyHandler),
 	)
@@ -73,6 +93,30 @@ func (c *HandlerContainer) initializeGenericCreatorHandler(contingencyHandler *h
 	return genericHandler
 }
 
+func (c *HandlerContainer) WebhookHandler() *handlers.WebhookHandler {
+	return c.webhookHandler
+}
+
+func (c *HandlerContainer) ActivityHandler() *handlers.ActivityHandler {
+	return c.activityHandler
+}
+
+func (c *HandlerContainer) NotificationHandler() *handlers.NotificationHandler {
+	return c.notificationHandler
+}
+
+func (c *HandlerContainer) ArchiveHandler() *handlers.ArchiveHandler {
+	return c.archiveHandler
+}
+
+func (c *HandlerContainer) DeliveryHandler() *handlers.DeliveryHandler {
+	return c.deliveryHandler
+}
+
+func (c *HandlerContainer) PDFHandler() *handlers.PDFHandler {
+	return c.pdfHandler
+}
+
 func (c *HandlerContainer) MetricsHandler() *handlers.MetricsHandler {
 	return c.metricsHandler
 }
@@ -92,3 +136,19 @@ func (c *HandlerContainer) DTEHandler() *handlers.DTEHandler {
 func (c *HandlerContainer) AuthHandler() *handlers.AuthHandler {
 	return c.authHandler
 }
+
+func (c *HandlerContainer) BranchHandler() *handlers.BranchHandler {
+	return c.branchHandler
+}
+
+func (c *HandlerContainer) RegistrationHandler() *handlers.RegistrationHandler {
+	return c.registrationHandler
+}
+
+func (c *HandlerContainer) AuditHandler() *handlers.AuditHandler {
+	return c.auditHandler
+}
+
+func (c *HandlerContainer) OperatorHandler() *handlers.OperatorHandler {
+	return c.operatorHandler
+}
@@ -10,10 +10,18 @@ type MiddlewareContainer struct {
 	connection *drivers.DbConnection
 
 	corsMid    *middleware.CorsMiddleware
+	requestMid *middleware.RequestContextMiddleware
+	langMid    *middleware.LanguageMiddleware
 	authMid    *middleware.AuthMiddleware
+	adminMid   *middleware.AdminMiddleware
+	scopeMid   *middleware.ScopeMiddleware
+	rateMid    *middleware.RateLimitMiddleware
+	loginMid   *middleware.LoginThrottleMiddleware
 	tokenMid   *middleware.TokenExtractor
 	errorMid   *middleware.ErrorMiddleware
 	metricMid  *middleware.MetricsMiddleware
+	promMid    *middleware.PrometheusMiddleware
+	traceMid   *middleware.TracingMiddleware
 	timeoutMid *middleware.TimeoutMiddleware
 	dbMid      *middleware.DBConnectionMiddleware
 }
@@ -31,10 +39,18 @@ func (c *MiddlewareContainer) Initialize() {
 		nil,
 		nil,
 	)
+	c.requestMid = middleware.NewRequestContextMiddleware()
+	c.langMid = middleware.NewLanguageMiddleware()
 	c.tokenMid = middleware.NewTokenExtractor()
 	c.errorMid = middleware.NewErrorMiddleware()
-	c.authMid = middleware.NewAuthMiddleware(c.services.TokenManager())
+	c.authMid = middleware.NewAuthMiddleware(c.services.TokenManager(), c.services.AuthManager())
+	c.adminMid = middleware.NewAdminMiddleware(c.services.TokenManager(), c.services.AuthManager())
+	c.scopeMid = middleware.NewScopeMiddleware()
+	c.rateMid = middleware.NewRateLimitMiddleware(c.services.RateLimiter(), c.services.AuthManager())
+	c.loginMid = middleware.NewLoginThrottleMiddleware(c.services.RateLimiter())
 	c.metricMid = middleware.NewMetricsMiddleware(c.services.CacheManager())
+	c.promMid = middleware.NewPrometheusMiddleware()
+	c.traceMid = middleware.NewTracingMiddleware()
 	c.dbMid = middleware.NewDBConnectionMiddleware(c.connection)
 	c.timeoutMid = middleware.NewTimeoutMiddleware()
 }
@@ -46,6 +62,14 @@ func (c *MiddlewareContainer) DBConnectionMiddleware() *middleware.DBConnectionM
 	return c.dbMid
 }
 
+func (c *MiddlewareContainer) RequestContextMiddleware() *middleware.RequestContextMiddleware {
+	return c.requestMid
+}
+
+func (c *MiddlewareContainer) LanguageMiddleware() *middleware.LanguageMiddleware {
+	return c.langMid
+}
+
 func (c *MiddlewareContainer) CorsMiddleware() *middleware.CorsMiddleware {
 	return c.corsMid
 }
@@ -54,6 +78,22 @@ func (c *MiddlewareContainer) AuthMiddleware() *middleware.AuthMiddleware {
 	return c.authMid
 }
 
+func (c *MiddlewareContainer) AdminMiddleware() *middleware.AdminMiddleware {
+	return c.adminMid
+}
+
+func (c *MiddlewareContainer) ScopeMiddleware() *middleware.ScopeMiddleware {
+	return c.scopeMid
+}
+
+func (c *MiddlewareContainer) RateLimitMiddleware() *middleware.RateLimitMiddleware {
+	return c.rateMid
+}
+
+func (c *MiddlewareContainer) LoginThrottleMiddleware() *middleware.LoginThrottleMiddleware {
+	return c.loginMid
+}
+
 func (c *MiddlewareContainer) TokenExtractor() *middleware.TokenExtractor {
 	return c.tokenMid
 }
@@ -65,3 +105,11 @@ func (c *MiddlewareContainer) ErrorMiddleware() *middleware.ErrorMiddleware {
 func (c *MiddlewareContainer) MetricsMiddleware() *middleware.MetricsMiddleware {
 	return c.metricMid
 }
+
+func (c *MiddlewareContainer) PrometheusMiddleware() *middleware.PrometheusMiddleware {
+	return c.promMid
+}
+
+func (c *MiddlewareContainer) TracingMiddleware() *middleware.TracingMiddleware {
+	return c.traceMid
+}
@@ -2,10 +2,19 @@ package containers
 
 import (
 	"github.com/MarlonG1/api-facturacion-sv/config/drivers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
 	contiPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency"
 	dtePorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/repositories"
 	"gorm.io/gorm"
 )
@@ -19,6 +28,15 @@ type RepositoryContainer struct {
 	failedSequentialNumberRepo ports.FailedSequenceNumberRepositoryPort
 	dteRepo                    dtePorts.DTERepositoryPort
 	contingencyRepo            contiPorts.ContingencyRepositoryPort
+	brandingRepo               pdf.BrandingRepositoryPort
+	deliveryRepo               delivery.DeliveryRepositoryPort
+	archiveRepo                archive.ArchiveRepositoryPort
+	credentialVaultRepo        vault.CredentialVaultRepositoryPort
+	auditRepo                  audit.AuditRepositoryPort
+	operatorRepo               operator.OperatorRepositoryPort
+	webhookRepo                webhook.WebhookRepositoryPort
+	notificationRepo           notification.NotificationRepositoryPort
+	businessMetricsRepo        metrics.BusinessMetricsRepositoryPort
 }
 
 func NewRepositoryContainer(connection *drivers.DbConnection) *RepositoryContainer {
@@ -34,6 +52,51 @@ func (c *RepositoryContainer) Initialize() {
 	c.dteRepo = repositories.NewDTERepository(c.db)
 	c.contingencyRepo = repositories.NewContingencyRepository(c.db)
 	c.failedSequentialNumberRepo = repositories.NewFailedSequenceNumberRepository(c.db)
+	c.brandingRepo = repositories.NewBrandingRepository(c.db)
+	c.deliveryRepo = repositories.NewDeliveryRepository(c.db)
+	c.archiveRepo = repositories.NewArchiveRepository(c.db)
+	c.credentialVaultRepo = repositories.NewCredentialVaultRepository(c.db)
+	c.auditRepo = repositories.NewAuditRepository(c.db)
+	c.operatorRepo = repositories.NewOperatorRepository(c.db)
+	c.webhookRepo = repositories.NewWebhookRepository(c.db)
+	c.notificationRepo = repositories.NewNotificationRepository(c.db)
+	c.businessMetricsRepo = repositories.NewBusinessMetricsRepository(c.db)
+}
+
+func (c *RepositoryContainer) BusinessMetricsRepo() metrics.BusinessMetricsRepositoryPort {
+	return c.businessMetricsRepo
+}
+
+func (c *RepositoryContainer) NotificationRepo() notification.NotificationRepositoryPort {
+	return c.notificationRepo
+}
+
+func (c *RepositoryContainer) WebhookRepo() webhook.WebhookRepositoryPort {
+	return c.webhookRepo
+}
+
+func (c *RepositoryContainer) OperatorRepo() operator.OperatorRepositoryPort {
+	return c.operatorRepo
+}
+
+func (c *RepositoryContainer) AuditRepo() audit.AuditRepositoryPort {
+	return c.auditRepo
+}
+
+func (c *RepositoryContainer) CredentialVaultRepo() vault.CredentialVaultRepositoryPort {
+	return c.credentialVaultRepo
+}
+
+func (c *RepositoryContainer) ArchiveRepo() archive.ArchiveRepositoryPort {
+	return c.archiveRepo
+}
+
+func (c *RepositoryContainer) DeliveryRepo() delivery.DeliveryRepositoryPort {
+	return c.deliveryRepo
+}
+
+func (c *RepositoryContainer) BrandingRepo() pdf.BrandingRepositoryPort {
+	return c.brandingRepo
 }
 
 func (c *RepositoryContainer) FailedSequentialNumberRepo() ports.FailedSequenceNumberRepositoryPort {
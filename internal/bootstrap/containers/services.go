@@ -5,8 +5,12 @@ import (
 
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	appPorts "github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/service/strategies"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/ccf"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/credit_note"
@@ -18,28 +22,43 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/test_endpoint"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	adapterActivity "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/activity"
+	adapterArchive "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/archive"
+	adapterAudit "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/audit"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/cache"
 	adapterContingecy "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/contingency"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	adapterDelivery "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/delivery"
 	adapterHealth "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/health"
 	adapterMetric "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
+	adapterNotification "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/notification"
+	adapterPDF "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/pdf"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/signing"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/signing/signer"
 	adapterTest "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/test_endpoint"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/tokens"
 	adapterTransmitter "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter"
 	batch "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter/batch"
+	adapterWebhook "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/webhook"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
 type ServicesContainer struct {
 	repos *RepositoryContainer
 
 	cacheManager            ports.CacheManager
+	rateLimiter             ports.RateLimiter
 	tokenManager            ports.TokenManager
 	authManager             auth.AuthManager
 	cryptManager            ports.CryptManager
+	credentialVault         vault.CredentialVault
 	transmitterManager      appPorts.DTETransmitter
 	haciendaAuthManager     appPorts.HaciendaAuthManager
 	signerManager           appPorts.SignerManager
@@ -52,6 +71,15 @@ type ServicesContainer struct {
 	healthManager           health.HealthManager
 	testManager             test_endpoint.TestManager
 	metricsManager          metrics.MetricsManager
+	businessMetricsManager  metrics.BusinessMetricsManager
+	pdfManager              pdf.PDFManager
+	deliveryManager         delivery.DeliveryManager
+	archiveManager          archive.ArchiveManager
+	auditManager            audit.AuditManager
+	operatorManager         operator.OperatorManager
+	webhookManager          webhook.WebhookManager
+	activityManager         activity.ActivityManager
+	notificationManager     notification.NotificationManager
 	invoiceManager          ports.DTEService
 	ccfManager              ports.DTEService
 	retentionManager        ports.DTEService
@@ -73,10 +101,31 @@ func (c *ServicesContainer) Initialize() error {
 		return err
 	}
 
+	c.rateLimiter = cache.NewRedisRateLimiter(c.cacheManager.GetRedisClient())
+
+	masterKeys, _, err := config.ParseVaultMasterKeys(config.Vault.MasterKeys)
+	if err != nil {
+		return err
+	}
+	c.credentialVault, err = crypt.NewCredentialVaultService(c.repos.CredentialVaultRepo(), masterKeys, config.Vault.ActiveKey)
+	if err != nil {
+		return err
+	}
+
+	c.auditManager = adapterAudit.NewAuditService(c.repos.AuditRepo())
+	c.activityManager = adapterActivity.NewActivityService(c.cacheManager.GetRedisClient())
+	c.webhookManager = adapterWebhook.NewWebhookService(c.repos.WebhookRepo(), c.cryptManager, c.credentialVault, c.activityManager)
 	c.tokenManager = tokens.NewJWTService(config.Server.JWTSecret, c.cacheManager)
-	c.authManager = strategies.NewAuthService(c.tokenManager, c.repos.AuthRepo(), c.cacheManager)
+	c.authManager = strategies.NewAuthService(c.tokenManager, c.repos.AuthRepo(), c.cacheManager, c.cryptManager, c.credentialVault, c.auditManager)
+	c.operatorManager = operator.NewOperatorService(c.repos.OperatorRepo(), c.tokenManager, c.cacheManager, c.cryptManager, c.credentialVault, c.auditManager)
+	c.healthManager = adapterHealth.NewHealthService(&adapterHealth.HealthServiceConfig{
+		DB:           c.repos.db,
+		Redis:        c.cacheManager.GetRedisClient(),
+		CheckTimeout: time.Duration(config.Health.CheckTimeout) * time.Second,
+		CacheTTL:     time.Duration(config.Health.CacheTTL) * time.Second,
+	})
 	c.signerManager = signer.NewDTESigner(c.repos.AuthRepo())
-	c.haciendaAuthManager = signing.NewHaciendaAuthService(c.cacheManager, c.authManager)
+	c.haciendaAuthManager = signing.NewHaciendaAuthService(c.cacheManager, c.authManager, c.credentialVault)
 	c.transmitterManager = adapterTransmitter.NewMHTransmitter(c.haciendaAuthManager, c.repos.FailedSequentialNumberRepo())
 	c.dteManager = dte_documents.NewDTEService(c.repos.DTERepo())
 	c.sequentialManager = dte_documents.NewSequentialNumberService(c.repos.SequentialNumberRepo(), c.repos.AuthRepo())
@@ -87,9 +136,37 @@ func (c *ServicesContainer) Initialize() error {
 	c.creditNoteManager = credit_note.NewCreditNoteService(c.sequentialManager, c.dteManager)
 	c.testManager = adapterTest.NewTestService(c.repos.db)
 	c.metricsManager = adapterMetric.NewMetricService(c.cacheManager)
-	c.healthManager = adapterHealth.NewHealthService(&adapterHealth.HealthServiceConfig{
-		DB: c.repos.db,
-	})
+	c.businessMetricsManager = adapterMetric.NewBusinessMetricsService(c.repos.BusinessMetricsRepo())
+	c.pdfManager = adapterPDF.NewPDFService(c.repos.BrandingRepo())
+
+	mailTransport := adapterDelivery.NewMailTransportFromConfig()
+	c.deliveryManager = adapterDelivery.NewDeliveryService(
+		c.dteManager,
+		c.pdfManager,
+		c.repos.DeliveryRepo(),
+		mailTransport,
+		config.Mail.From,
+		config.Mail.FromName,
+		config.Mail.AutoSend,
+	)
+	c.notificationManager = adapterNotification.NewNotificationService(
+		c.repos.NotificationRepo(),
+		mailTransport,
+		config.Mail.From,
+		config.Mail.FromName,
+		c.webhookManager,
+		c.cacheManager.GetRedisClient(),
+	)
+	c.webhookManager.AddListener(c.notificationManager)
+	c.webhookManager.AddListener(adapterMetric.NewDTEEventRecorder())
+	adapterMetric.SetContingencyQueueSource(c.repos.ContingencyRepo().CountPending)
+	c.archiveManager = adapterArchive.NewArchiveService(
+		c.dteManager,
+		c.pdfManager,
+		c.repos.ArchiveRepo(),
+		utils.FindProjectRoot()+config.Archive.Path,
+		time.Duration(config.Archive.RetentionDays)*24*time.Hour,
+	)
 
 	transmissionConf := models.NewTransmissionConfig(5*time.Second, 2*time.Minute, 2.0)
 	c.transmitterBatchManager = batch.NewBatchTransmitterService(
@@ -99,13 +176,16 @@ func (c *ServicesContainer) Initialize() error {
 		transmissionConf,
 		&transmitter.RealTimeProvider{},
 		c.repos.connection,
+		c.deliveryManager,
+		c.dteManager,
+		c.webhookManager,
+		c.activityManager,
 	)
 
 	c.contingencyEventManager = adapterContingecy.NewContingencyEventService(
 		c.authManager,
 		c.haciendaAuthManager,
-		c.cacheManager,
-		c.tokenManager,
+		c.credentialVault,
 		c.signerManager,
 		c.repos.ContingencyRepo(),
 		&transmitter.RealTimeProvider{},
@@ -117,13 +197,14 @@ func (c *ServicesContainer) Initialize() error {
 		c.dteManager,
 		c.repos.ContingencyRepo(),
 		c.haciendaAuthManager,
-		c.cacheManager,
-		c.tokenManager,
+		c.credentialVault,
 		c.signerManager,
 		c.transmitterBatchManager,
 		c.contingencyEventManager,
 		&transmitter.RealTimeProvider{},
 		transmissionConf,
+		c.auditManager,
+		c.webhookManager,
 	)
 
 	return nil
@@ -137,10 +218,46 @@ func (c *ServicesContainer) RetentionManager() ports.DTEService {
 	return c.retentionManager
 }
 
+func (c *ServicesContainer) WebhookManager() webhook.WebhookManager {
+	return c.webhookManager
+}
+
+func (c *ServicesContainer) NotificationManager() notification.NotificationManager {
+	return c.notificationManager
+}
+
+func (c *ServicesContainer) ActivityManager() activity.ActivityManager {
+	return c.activityManager
+}
+
+func (c *ServicesContainer) OperatorManager() operator.OperatorManager {
+	return c.operatorManager
+}
+
+func (c *ServicesContainer) AuditManager() audit.AuditManager {
+	return c.auditManager
+}
+
+func (c *ServicesContainer) ArchiveManager() archive.ArchiveManager {
+	return c.archiveManager
+}
+
+func (c *ServicesContainer) DeliveryManager() delivery.DeliveryManager {
+	return c.deliveryManager
+}
+
+func (c *ServicesContainer) PDFManager() pdf.PDFManager {
+	return c.pdfManager
+}
+
 func (c *ServicesContainer) MetricsManager() metrics.MetricsManager {
 	return c.metricsManager
 }
 
+func (c *ServicesContainer) BusinessMetricsManager() metrics.BusinessMetricsManager {
+	return c.businessMetricsManager
+}
+
 func (c *ServicesContainer) HealthManager() health.HealthManager {
 	return c.healthManager
 }
@@ -185,6 +302,10 @@ func (c *ServicesContainer) CacheManager() ports.CacheManager {
 	return c.cacheManager
 }
 
+func (c *ServicesContainer) RateLimiter() ports.RateLimiter {
+	return c.rateLimiter
+}
+
 func (c *ServicesContainer) TokenManager() ports.TokenManager {
 	return c.tokenManager
 }
@@ -193,6 +314,10 @@ func (c *ServicesContainer) AuthManager() auth.AuthManager {
 	return c.authManager
 }
 
+func (c *ServicesContainer) CredentialVault() vault.CredentialVault {
+	return c.credentialVault
+}
+
 func (c *ServicesContainer) CryptManager() ports.CryptManager {
 	return c.cryptManager
 }
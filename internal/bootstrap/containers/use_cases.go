@@ -1,9 +1,15 @@
 package containers
 
 import (
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/activity"
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/audit"
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/operator"
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/webhook"
 )
 
 type UseCaseContainer struct {
@@ -11,8 +17,19 @@ type UseCaseContainer struct {
 
 	// Caso de uso especiales
 	dteConsult          *dte.DTEConsultUseCase
+	dtePDFUseCase       *dte.DTEPDFUseCase
+	dteDeliveryUseCase  *dte.DTEDeliveryUseCase
+	dteArchiveUseCase   *dte.DTEArchiveUseCase
 	invalidationUseCase *dte.InvalidationUseCase
 	authUseCase         *auth.AuthUseCase
+	branchUseCase       *auth.BranchUseCase
+	registrationUseCase *auth.RegistrationUseCase
+	auditUseCase        *audit.AuditUseCase
+	operatorUseCase     *operator.OperatorUseCase
+	webhookUseCase      *webhook.WebhookUseCase
+	activityUseCase     *activity.ActivityUseCase
+	notificationUseCase *notification.NotificationUseCase
+	metricsUseCase      *metrics.BusinessMetricsUseCase
 	baseTransmitter     ports.BaseTransmitter
 	dteUseCaseFactory   *dte.DTEUseCaseFactory
 
@@ -31,14 +48,28 @@ func NewUseCaseContainer(services *ServicesContainer) *UseCaseContainer {
 
 func (c *UseCaseContainer) Initialize() {
 	c.authUseCase = auth.NewAuthUseCase(c.services.AuthManager(), c.services.CryptManager())
-	c.baseTransmitter = dte.NewBaseTransmitter(c.services.TransmitterManager(), c.services.SignerManager())
+	c.branchUseCase = auth.NewBranchUseCase(c.services.AuthManager(), c.services.CryptManager(), c.services.CredentialVault())
+	c.registrationUseCase = auth.NewRegistrationUseCase(c.services.AuthManager(), c.services.CryptManager(), c.services.CredentialVault())
+	c.auditUseCase = audit.NewAuditUseCase(c.services.AuditManager())
+	c.operatorUseCase = operator.NewOperatorUseCase(c.services.OperatorManager(), c.services.DTEManager(), c.services.CryptManager())
+	c.webhookUseCase = webhook.NewWebhookUseCase(c.services.WebhookManager())
+	c.activityUseCase = activity.NewActivityUseCase(c.services.ActivityManager())
+	c.notificationUseCase = notification.NewNotificationUseCase(c.services.NotificationManager())
+	c.metricsUseCase = metrics.NewBusinessMetricsUseCase(c.services.BusinessMetricsManager())
+	c.baseTransmitter = dte.NewBaseTransmitter(c.services.TransmitterManager(), c.services.SignerManager(), c.services.HealthManager())
 	c.dteConsult = dte.NewDTEConsultUseCase(c.services.DTEManager())
+	c.dtePDFUseCase = dte.NewDTEPDFUseCase(c.services.DTEManager(), c.services.PDFManager())
+	c.dteDeliveryUseCase = dte.NewDTEDeliveryUseCase(c.services.DeliveryManager())
+	c.dteArchiveUseCase = dte.NewDTEArchiveUseCase(c.services.ArchiveManager())
 
 	// Inicializar factory de casos de uso
 	c.dteUseCaseFactory = dte.NewDTEUseCaseFactory(
 		c.services.AuthManager(),
 		c.services.DTEManager(),
-		c.baseTransmitter)
+		c.baseTransmitter,
+		c.services.DeliveryManager(),
+		c.services.AuditManager(),
+		c.services.WebhookManager())
 
 	c.invoiceUseCase = c.dteUseCaseFactory.CreateInvoiceUseCase(c.services.InvoiceService())
 	c.ccfUseCase = c.dteUseCaseFactory.CreateCCFUseCase(c.services.CCFService())
@@ -54,6 +85,18 @@ func (c *UseCaseContainer) DTEConsultUseCase() *dte.DTEConsultUseCase {
 	return c.dteConsult
 }
 
+func (c *UseCaseContainer) DTEPDFUseCase() *dte.DTEPDFUseCase {
+	return c.dtePDFUseCase
+}
+
+func (c *UseCaseContainer) DTEDeliveryUseCase() *dte.DTEDeliveryUseCase {
+	return c.dteDeliveryUseCase
+}
+
+func (c *UseCaseContainer) DTEArchiveUseCase() *dte.DTEArchiveUseCase {
+	return c.dteArchiveUseCase
+}
+
 func (c *UseCaseContainer) InvoiceUseCase() *dte.GenericDTEUseCase {
 	return c.invoiceUseCase
 }
@@ -77,3 +120,35 @@ func (c *UseCaseContainer) InvalidationUseCase() *dte.InvalidationUseCase {
 func (c *UseCaseContainer) AuthUseCase() *auth.AuthUseCase {
 	return c.authUseCase
 }
+
+func (c *UseCaseContainer) BranchUseCase() *auth.BranchUseCase {
+	return c.branchUseCase
+}
+
+func (c *UseCaseContainer) RegistrationUseCase() *auth.RegistrationUseCase {
+	return c.registrationUseCase
+}
+
+func (c *UseCaseContainer) AuditUseCase() *audit.AuditUseCase {
+	return c.auditUseCase
+}
+
+func (c *UseCaseContainer) BusinessMetricsUseCase() *metrics.BusinessMetricsUseCase {
+	return c.metricsUseCase
+}
+
+func (c *UseCaseContainer) OperatorUseCase() *operator.OperatorUseCase {
+	return c.operatorUseCase
+}
+
+func (c *UseCaseContainer) WebhookUseCase() *webhook.WebhookUseCase {
+	return c.webhookUseCase
+}
+
+func (c *UseCaseContainer) ActivityUseCase() *activity.ActivityUseCase {
+	return c.activityUseCase
+}
+
+func (c *UseCaseContainer) NotificationUseCase() *notification.NotificationUseCase {
+	return c.notificationUseCase
+}
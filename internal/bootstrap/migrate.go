@@ -0,0 +1,141 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// migrateUsage ayuda del comando de migraciones
+const migrateUsage = `Usage: main migrate <command>
+
+Commands:
+  up                  apply every pending migration
+  down [steps]        revert the last applied migrations (default 1)
+  status              show the state of every migration
+  baseline [version]  register an existing database as migrated up to version without running it, after checking
+                      that its tables and columns exist (default 1)`
+
+// RunMigrateCommand ejecuta el comando de migraciones recibido por línea de comandos sobre la base de datos configurada
+func RunMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	// 1. Inicializar la configuración, el logger y el tiempo global
+	rootPath := utils.FindProjectRoot()
+	if err := config.InitEnvConfig(rootPath); err != nil {
+		return fmt.Errorf("error initializing environment configuration: %w", err)
+	}
+
+	if err := logs.InitLogger(config.Log.Level, config.Log.Path, config.Log.Format); err != nil {
+		return fmt.Errorf("error initializing logger: %w", err)
+	}
+
+	if err := utils.TimeInit(); err != nil {
+		return fmt.Errorf("error initializing global time: %w", err)
+	}
+
+	// 2. Abrir la conexión a la base de datos y cargar las migraciones del driver
+	dbConnection, err := NewApplication().openDatabase()
+	if err != nil {
+		return err
+	}
+	defer dbConnection.Close()
+
+	migrator, err := database.NewMigrator(dbConnection.Db)
+	if err != nil {
+		return err
+	}
+
+	// 3. Ejecutar el comando
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations, schema version %d\n", count, migrator.LatestVersion())
+
+	case "down":
+		steps, err := parsePositiveArg(args, 1)
+		if err != nil {
+			return err
+		}
+
+		count, err := migrator.Down(int(steps))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migrations\n", count)
+
+	case "status":
+		return printMigrationStatus(migrator)
+
+	case "baseline":
+		version, err := parsePositiveArg(args, 1)
+		if err != nil {
+			return err
+		}
+
+		if err = migrator.Baseline(version); err != nil {
+			return err
+		}
+		fmt.Printf("Database registered at schema version %d\n", version)
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+
+	return nil
+}
+
+// printMigrationStatus imprime la versión actual del esquema y el estado de cada migración
+func printMigrationStatus(migrator *database.Migrator) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	current, dirty, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Schema version: %d (expected %d, dirty %t)\n\n", current, migrator.LatestVersion(), dirty)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, migration := range status {
+		state, appliedAt := "pending", "-"
+		if migration.Applied {
+			state = "applied"
+			appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if migration.Dirty {
+			state = "dirty"
+		}
+		fmt.Fprintf(writer, "%04d\t%s\t%s\t%s\n", migration.Version, migration.Name, state, appliedAt)
+	}
+
+	return writer.Flush()
+}
+
+// parsePositiveArg obtiene el argumento opcional del comando, si no se indica se utiliza el valor por defecto
+func parsePositiveArg(args []string, defaultValue uint) (uint, error) {
+	if len(args) < 2 {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil || value == 0 {
+		return 0, fmt.Errorf("invalid argument %q, a positive number is expected", args[1])
+	}
+
+	return uint(value), nil
+}
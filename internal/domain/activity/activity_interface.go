@@ -0,0 +1,19 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+)
+
+// ActivityManager publica los cambios de estado de los DTE, de la contingencia y del circuit breaker en el stream de
+// actividad de las sucursales y los distribuye en tiempo real a los clientes conectados en cualquier réplica
+type ActivityManager interface {
+	// Publish registra el evento en el stream de la sucursal y lo notifica a los clientes conectados, los eventos de
+	// la sucursal SystemBranchID se notifican a todas las sucursales. Un error solo se registra en el log porque el
+	// evento no debe interrumpir la acción que lo origina
+	Publish(ctx context.Context, event *models.Event)
+	// Subscribe retorna los eventos de la sucursal hasta que se cancele el contexto. Si se indica lastEventID primero
+	// se retornan los eventos registrados después de ese ID que aún conserva el stream
+	Subscribe(ctx context.Context, branchID uint, lastEventID string) (<-chan models.Event, error)
+}
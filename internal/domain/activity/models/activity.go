@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+)
+
+// EventCircuitBreakerStateChanged se publica cuando el circuit breaker de la transmisión a Hacienda cambia de estado.
+// Los demás tipos de evento son los del ciclo de vida de los DTE que también se entregan por webhooks
+const EventCircuitBreakerStateChanged = "circuit_breaker.state_changed"
+
+// SystemBranchID sucursal de los eventos que afectan a todo el sistema, se entregan en el stream de todas las sucursales
+const SystemBranchID uint = 0
+
+// Event representa un cambio publicado en el stream de actividad de una sucursal. El ID lo asigna el stream al
+// publicar el evento y es el que el cliente envía en Last-Event-ID para reanudar la conexión
+type Event struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"event"`
+	BranchID   uint                   `json:"branch_id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// IsSystem indica si el evento afecta a todas las sucursales
+func (e *Event) IsSystem() bool {
+	return e.BranchID == SystemBranchID
+}
+
+// NewCircuitBreakerEvent crea el evento de cambio de estado del circuit breaker de la transmisión a Hacienda
+func NewCircuitBreakerEvent(from, to constants.State) *Event {
+	return &Event{
+		Type:     EventCircuitBreakerStateChanged,
+		BranchID: SystemBranchID,
+		Data: map[string]interface{}{
+			"previous_state": from.String(),
+			"state":          to.String(),
+		},
+	}
+}
+
+// StreamID representa el ID "<milisegundos>-<secuencia>" que Redis asigna a las entradas de un stream
+type StreamID struct {
+	Millis   uint64
+	Sequence uint64
+}
+
+// ParseStreamID obtiene un StreamID a partir de su representación en texto
+func ParseStreamID(id string) (StreamID, error) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 2 {
+		return StreamID{}, fmt.Errorf("invalid stream id %q", id)
+	}
+
+	millis, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return StreamID{}, fmt.Errorf("invalid stream id %q", id)
+	}
+
+	sequence, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return StreamID{}, fmt.Errorf("invalid stream id %q", id)
+	}
+
+	return StreamID{Millis: millis, Sequence: sequence}, nil
+}
+
+// Next retorna el menor ID posterior, se usa como inicio exclusivo al leer un stream desde un ID
+func (s StreamID) Next() StreamID {
+	if s.Sequence == ^uint64(0) {
+		return StreamID{Millis: s.Millis + 1}
+	}
+	return StreamID{Millis: s.Millis, Sequence: s.Sequence + 1}
+}
+
+// After indica si el ID es posterior a other
+func (s StreamID) After(other StreamID) bool {
+	if s.Millis != other.Millis {
+		return s.Millis > other.Millis
+	}
+	return s.Sequence > other.Sequence
+}
+
+func (s StreamID) String() string {
+	return fmt.Sprintf("%d-%d", s.Millis, s.Sequence)
+}
@@ -0,0 +1,40 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+)
+
+// ArchiveManager genera archivos ZIP con los documentos originales de los DTE de un período
+type ArchiveManager interface {
+	// CreateJob registra un trabajo de exportación y lo procesa en segundo plano
+	CreateJob(ctx context.Context, job *models.ArchiveJob) (*models.ArchiveJob, error)
+	// GetJob obtiene el estado de un trabajo de exportación de la sucursal
+	GetJob(ctx context.Context, branchID uint, id string) (*models.ArchiveJob, error)
+	// OpenArchive abre el archivo ZIP de un trabajo completado, el llamador debe cerrar el lector
+	OpenArchive(ctx context.Context, branchID uint, id string) (io.ReadCloser, *models.ArchiveJob, error)
+	// RecoverJobs vuelve a procesar los trabajos que quedaron pendientes o en proceso al detenerse la aplicación
+	RecoverJobs(ctx context.Context) error
+	// CleanupExpired elimina los archivos de los trabajos completados que superaron su tiempo de retención
+	CleanupExpired(ctx context.Context) error
+}
+
+// ArchiveRepositoryPort define el almacenamiento de los trabajos de exportación
+type ArchiveRepositoryPort interface {
+	// Create registra un trabajo de exportación
+	Create(ctx context.Context, job *models.ArchiveJob) error
+	// ClaimJob reserva un trabajo pendiente, o en proceso con la reserva vencida, hasta leaseUntil para que solo un
+	// proceso lo genere, retorna false si otro proceso ya lo reservó
+	ClaimJob(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error)
+	// Update actualiza el estado y el resultado de un trabajo de exportación
+	Update(ctx context.Context, job *models.ArchiveJob) error
+	// GetByID obtiene un trabajo de exportación de la sucursal
+	GetByID(ctx context.Context, branchID uint, id string) (*models.ArchiveJob, error)
+	// GetByStatus obtiene los trabajos de exportación de todas las sucursales con alguno de los estados indicados
+	GetByStatus(ctx context.Context, statuses ...string) ([]models.ArchiveJob, error)
+	// GetCompletedBefore obtiene hasta limit trabajos completados antes de la fecha indicada
+	GetCompletedBefore(ctx context.Context, before time.Time, limit int) ([]models.ArchiveJob, error)
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+)
+
+const (
+	// ArchiveScopeBranch exporta los DTE de la sucursal que solicita el archivo
+	ArchiveScopeBranch = "branch"
+	// ArchiveScopeNIT exporta los DTE de todas las sucursales del contribuyente
+	ArchiveScopeNIT = "nit"
+
+	// ArchivePending indica que el trabajo fue registrado y aún no se procesa
+	ArchivePending = "PENDING"
+	// ArchiveProcessing indica que el archivo se está generando
+	ArchiveProcessing = "PROCESSING"
+	// ArchiveCompleted indica que el archivo está listo para su descarga
+	ArchiveCompleted = "COMPLETED"
+	// ArchiveFailed indica que no fue posible generar el archivo
+	ArchiveFailed = "FAILED"
+	// ArchiveExpired indica que el archivo se eliminó al cumplir su tiempo de retención
+	ArchiveExpired = "EXPIRED"
+
+	// MaxArchivePeriodDays es la cantidad máxima de días que puede abarcar un archivo
+	MaxArchivePeriodDays = 366
+)
+
+// ArchiveJob representa un trabajo de exportación de los documentos originales de los DTE de un período
+type ArchiveJob struct {
+	ID            string     `json:"id"`
+	BranchID      uint       `json:"branch_id"`
+	ClientID      uint       `json:"-"`
+	NIT           string     `json:"nit"`
+	Scope         string     `json:"scope"`
+	StartDate     time.Time  `json:"start_date"`
+	EndDate       time.Time  `json:"end_date"`
+	IncludePDF    bool       `json:"include_pdf"`
+	Status        string     `json:"status"`
+	DocumentCount int        `json:"document_count"`
+	FileSize      int64      `json:"file_size,omitempty"`
+	FileHash      string     `json:"sha256,omitempty"`
+	FilePath      string     `json:"-"`
+	ErrorMessage  *string    `json:"error_message,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// Validate valida el alcance y el período del trabajo de exportación
+func (j *ArchiveJob) Validate() error {
+	if j.Scope != ArchiveScopeBranch && j.Scope != ArchiveScopeNIT {
+		return dte_errors.NewValidationError("InvalidFormat", "scope", "'branch' or 'nit'", j.Scope)
+	}
+
+	if j.EndDate.Before(j.StartDate) {
+		return dte_errors.NewValidationError("InvalidArchivePeriod", j.StartDate.Format("2006-01-02"), j.EndDate.Format("2006-01-02"))
+	}
+
+	if j.EndDate.Sub(j.StartDate) > MaxArchivePeriodDays*24*time.Hour {
+		return dte_errors.NewValidationError("ArchivePeriodTooLong", MaxArchivePeriodDays)
+	}
+
+	return nil
+}
+
+// Filename retorna el nombre con el que se descarga el archivo
+func (j *ArchiveJob) Filename() string {
+	return "dte-archive-" + j.NIT + "-" + j.StartDate.Format("20060102") + "-" + j.EndDate.Format("20060102") + ".zip"
+}
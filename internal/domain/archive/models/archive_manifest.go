@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+const (
+	// ArtifactSignedDocument es el JWS firmado que se transmitió a Hacienda
+	ArtifactSignedDocument = "signed_document"
+	// ArtifactMHResponse es la respuesta de Hacienda a la transmisión
+	ArtifactMHResponse = "mh_response"
+	// ArtifactPDF es la representación gráfica del documento
+	ArtifactPDF = "pdf"
+)
+
+// ArchiveManifest describe el contenido de un archivo de exportación, se incluye como manifest.json en la raíz del ZIP
+type ArchiveManifest struct {
+	JobID       string             `json:"job_id"`
+	NIT         string             `json:"nit"`
+	Scope       string             `json:"scope"`
+	BranchID    uint               `json:"branch_id,omitempty"`
+	StartDate   string             `json:"start_date"`
+	EndDate     string             `json:"end_date"`
+	IncludePDF  bool               `json:"include_pdf"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Totals      ManifestTotals     `json:"totals"`
+	Documents   []ManifestDocument `json:"documents"`
+}
+
+// ManifestTotals contiene los conteos de documentos del archivo por tipo y estado
+type ManifestTotals struct {
+	Documents int            `json:"documents"`
+	Files     int            `json:"files"`
+	ByType    map[string]int `json:"by_type"`
+	ByStatus  map[string]int `json:"by_status"`
+}
+
+// ManifestDocument describe los archivos de un DTE dentro del ZIP. Missing contiene los artefactos que no se
+// encontraron, por ejemplo los documentos transmitidos antes de que se almacenara el JWS firmado.
+type ManifestDocument struct {
+	GenerationCode string         `json:"generation_code"`
+	ControlNumber  string         `json:"control_number"`
+	DTEType        string         `json:"dte_type"`
+	Status         string         `json:"status"`
+	Transmission   string         `json:"transmission"`
+	ReceptionStamp *string        `json:"reception_stamp,omitempty"`
+	BranchID       uint           `json:"branch_id"`
+	Files          []ManifestFile `json:"files"`
+	Missing        []string       `json:"missing,omitempty"`
+}
+
+// ManifestFile describe un archivo del ZIP con su hash SHA-256
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// NewArchiveManifest crea el manifiesto vacío de un trabajo de exportación
+func NewArchiveManifest(job *ArchiveJob, generatedAt time.Time) *ArchiveManifest {
+	manifest := &ArchiveManifest{
+		JobID:       job.ID,
+		NIT:         job.NIT,
+		Scope:       job.Scope,
+		StartDate:   job.StartDate.Format("2006-01-02"),
+		EndDate:     job.EndDate.Format("2006-01-02"),
+		IncludePDF:  job.IncludePDF,
+		GeneratedAt: generatedAt,
+		Totals: ManifestTotals{
+			ByType:   make(map[string]int),
+			ByStatus: make(map[string]int),
+		},
+		Documents: make([]ManifestDocument, 0),
+	}
+
+	if job.Scope == ArchiveScopeBranch {
+		manifest.BranchID = job.BranchID
+	}
+
+	return manifest
+}
+
+// AddDocument registra un documento en el manifiesto actualizando los conteos
+func (m *ArchiveManifest) AddDocument(document ManifestDocument) {
+	m.Documents = append(m.Documents, document)
+	m.Totals.Documents++
+	m.Totals.Files += len(document.Files)
+	m.Totals.ByType[document.DTEType]++
+	m.Totals.ByStatus[document.Status]++
+}
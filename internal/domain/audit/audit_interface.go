@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+)
+
+// AuditManager registra y consulta la auditoría de las acciones que modifican el estado del sistema
+type AuditManager interface {
+	// Record registra una acción, un error al registrarla no interrumpe la acción que la origina
+	Record(ctx context.Context, entry *models.AuditEntry)
+	// GetEvents obtiene una página de eventos de auditoría que cumplen con los filtros
+	GetEvents(ctx context.Context, filters *models.AuditFilters) (*models.AuditListResponse, error)
+	// ExportEvents recorre todos los eventos que cumplen con los filtros, sin paginación, invocando fn por cada evento
+	ExportEvents(ctx context.Context, filters *models.AuditFilters, fn func(*models.AuditEvent) error) error
+}
+
+// AuditRepositoryPort define el almacenamiento de los eventos de auditoría
+type AuditRepositoryPort interface {
+	// Create registra un evento, si no se indica la sucursal se utiliza la casa matriz del usuario
+	Create(ctx context.Context, event *models.AuditEvent) error
+	// GetPaged obtiene una página de eventos ordenados del más reciente al más antiguo junto con el total de eventos
+	GetPaged(ctx context.Context, filters *models.AuditFilters) ([]models.AuditEvent, int64, error)
+	// Stream recorre los eventos que cumplen con los filtros en lotes, invocando fn por cada evento
+	Stream(ctx context.Context, filters *models.AuditFilters, batchSize int, fn func(*models.AuditEvent) error) error
+}
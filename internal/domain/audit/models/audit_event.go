@@ -0,0 +1,130 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Tipos de eventos de auditoría, se registran en la tabla domain_events
+const (
+	EventAuthLogin            = "AUTH_LOGIN"
+	EventAuthLogout           = "AUTH_LOGOUT"
+	EventUserRegistered       = "USER_REGISTERED"
+	EventRegistrationApproved = "REGISTRATION_APPROVED"
+	EventRegistrationRejected = "REGISTRATION_REJECTED"
+	EventDTEIssued            = "DTE_ISSUED"
+	EventDTEInvalidated       = "DTE_INVALIDATED"
+	EventContingencyStored    = "CONTINGENCY_STORED"
+	EventBatchRetransmitted   = "BATCH_RETRANSMITTED"
+	EventCredentialsChanged   = "CREDENTIALS_CHANGED"
+	EventBranchCreated        = "BRANCH_CREATED"
+	EventBranchUpdated        = "BRANCH_UPDATED"
+	EventBranchDeactivated    = "BRANCH_DEACTIVATED"
+	EventOperatorCreated      = "OPERATOR_CREATED"
+	EventOperatorGranted      = "OPERATOR_GRANTED"
+	EventOperatorRevoked      = "OPERATOR_REVOKED"
+	EventOperatorDeactivated  = "OPERATOR_DEACTIVATED"
+	EventOperatorLogin        = "OPERATOR_LOGIN"
+	EventIssuerSwitched       = "ISSUER_SWITCHED"
+)
+
+// Tipos de actor que originan un evento de auditoría
+const (
+	// ActorUser indica que la acción se realizó con un token emitido con las llaves principales de una sucursal
+	ActorUser = "USER"
+	// ActorAPIKey indica que la acción se realizó con un token emitido con una llave adicional de la sucursal
+	ActorAPIKey = "API_KEY"
+	// ActorOperator indica que la acción se realizó con un token de operador o con un token emitido para que el
+	// operador actúe como una de sus sucursales asignadas
+	ActorOperator = "OPERATOR"
+	// ActorAdmin indica que la acción se realizó con la llave de administración
+	ActorAdmin = "ADMIN"
+	// ActorSystem indica que la acción la realizó un proceso en segundo plano
+	ActorSystem = "SYSTEM"
+	// ActorAnonymous indica que la acción se realizó desde una ruta pública sin autenticación
+	ActorAnonymous = "ANONYMOUS"
+)
+
+// AuditEntry representa una acción a registrar en la auditoría. El actor, la IP y el ID de la solicitud se obtienen
+// del contexto, ActorType y ActorID solo se establecen cuando el actor no puede obtenerse del contexto (ej. login)
+type AuditEntry struct {
+	EventType string
+	UserID    uint
+	BranchID  uint
+	ActorType string
+	ActorID   uint
+	Payload   interface{}
+}
+
+// AuditEvent representa un evento de auditoría registrado
+type AuditEvent struct {
+	ID            uint            `json:"id"`
+	EventType     string          `json:"event_type"`
+	ActorType     string          `json:"actor_type"`
+	ActorID       uint            `json:"actor_id"`
+	UserID        uint            `json:"user_id"`
+	BranchID      uint            `json:"branch_id"`
+	IPAddress     string          `json:"ip_address"`
+	RequestID     string          `json:"request_id"`
+	Payload       json.RawMessage `json:"payload"`
+	PayloadDigest string          `json:"payload_digest"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// AuditFilters representa los filtros de búsqueda de eventos de auditoría, los campos vacíos no se aplican
+type AuditFilters struct {
+	UserID    uint
+	BranchID  uint
+	EventType string
+	ActorType string
+	RequestID string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	PageSize  int
+}
+
+// AuditListResponse representa una página de eventos de auditoría
+type AuditListResponse struct {
+	Events     []AuditEvent            `json:"events"`
+	Pagination AuditPaginationResponse `json:"pagination"`
+}
+
+type AuditPaginationResponse struct {
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+}
+
+// PayloadDigest calcula el digest SHA-256 en hexadecimal de un payload. El digest se calcula sobre la forma canónica
+// del JSON (llaves ordenadas y sin espacios) porque los motores de base de datos pueden reordenar las llaves y agregar
+// espacios al almacenar columnas JSON
+func PayloadDigest(payload []byte) string {
+	sum := sha256.Sum256(canonicalJSON(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalJSON retorna la forma canónica de un JSON, si el payload no es un JSON válido se retorna sin cambios
+func canonicalJSON(payload []byte) []byte {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return payload
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return payload
+	}
+	return canonical
+}
+
+// VerifyDigest verifica que el payload del evento corresponda con el digest registrado
+func (e *AuditEvent) VerifyDigest() bool {
+	return e.PayloadDigest == PayloadDigest(e.Payload)
+}
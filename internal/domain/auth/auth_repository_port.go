@@ -37,6 +37,36 @@ type AuthRepositoryPort interface {
 	DeleteBranchOffice(context.Context, uint, uint) error
 	// GetMatrixBranch obtiene la sucursal registrada como casa matriz
 	GetMatrixBranch(context.Context, uint) (*user.BranchOffice, error)
+	// GetBranchOffices obtiene todas las sucursales de un usuario
+	GetBranchOffices(context.Context, uint) ([]user.BranchOffice, error)
+	// CreateBranchOffice crea una sucursal para un usuario existente
+	CreateBranchOffice(context.Context, uint, *user.BranchOffice) error
+	// SetBranchOfficeStatus activa o desactiva una sucursal de un usuario
+	SetBranchOfficeStatus(context.Context, uint, uint, bool) error
+	// UpdateBranchCredentials reemplaza el API key, el hash del API secret y el API secret cifrado de una sucursal de un usuario
+	UpdateBranchCredentials(context.Context, uint, uint, string, string, string) error
+	// GetBranchByClientCertSubject obtiene la sucursal, junto con su usuario, asignada al subject de un certificado de cliente
+	GetBranchByClientCertSubject(context.Context, string) (*user.BranchOffice, error)
+	// GetBranchAPIKeyByKey obtiene una llave de acceso adicional por su API key
+	GetBranchAPIKeyByKey(context.Context, string) (*user.BranchAPIKey, error)
+	// GetBranchAPIKeyByClientCertSubject obtiene la llave de acceso adicional asignada al subject o SAN de un certificado de cliente
+	GetBranchAPIKeyByClientCertSubject(context.Context, string) (*user.BranchAPIKey, error)
+	// GetBranchAPIKeys obtiene las llaves de acceso adicionales de una sucursal
+	GetBranchAPIKeys(context.Context, uint) ([]user.BranchAPIKey, error)
+	// CreateBranchAPIKey crea una llave de acceso adicional para una sucursal
+	CreateBranchAPIKey(context.Context, *user.BranchAPIKey) error
+	// SetBranchAPIKeyStatus activa o desactiva una llave de acceso adicional de una sucursal
+	SetBranchAPIKeyStatus(context.Context, uint, uint, bool) error
+	// GetRegistrationConflicts obtiene los usuarios registrados o pendientes con el NIT o NRC indicados
+	GetRegistrationConflicts(context.Context, string, string) ([]user.User, error)
+	// GetRegistrations obtiene los usuarios con el estado de registro indicado
+	GetRegistrations(context.Context, string) ([]user.User, error)
+	// GetRegistrationByID obtiene un usuario con sus sucursales sin importar su estado
+	GetRegistrationByID(context.Context, uint) (*user.User, error)
+	// ApproveRegistration activa un usuario pendiente y asigna las llaves de sus sucursales
+	ApproveRegistration(context.Context, uint, []user.BranchOffice) error
+	// DeleteRegistration elimina un usuario pendiente de aprobación
+	DeleteRegistration(context.Context, uint) error
 }
 
 // AuthStrategy define el comportamiento que debe implementar cada estrategia de autenticación
@@ -46,17 +76,33 @@ type AuthStrategy interface {
 	// Authenticate valida las credenciales y retorna los claims para el token
 	Authenticate(ctx context.Context, credentials *models.AuthCredentials) (*models.AuthClaims, error)
 	// ValidateCredentials valida el formato de las credenciales para esta estrategia
-	ValidateCredentials(credentials *models.AuthCredentials) error
+	ValidateCredentials(ctx context.Context, credentials *models.AuthCredentials) error
 	// GetHaciendaCredentials obtiene las credenciales de hacienda segun el tipo de autenticación
-	GetHaciendaCredentials(token string) (*models.HaciendaCredentials, error)
+	GetHaciendaCredentials(ctx context.Context, token string) (*models.HaciendaCredentials, error)
 	// GetTokenLifetime obtiene la duración de vida de un token
-	GetTokenLifetime(credentials *models.AuthCredentials) (time.Duration, error)
+	GetTokenLifetime(ctx context.Context, credentials *models.AuthCredentials) (time.Duration, error)
+}
+
+// RequestAuthStrategy define el comportamiento de las estrategias que autentican cada solicitud en lugar de emitir un
+// token al iniciar sesión
+type RequestAuthStrategy interface {
+	AuthStrategy
+	// AuthenticateRequest valida los datos de autenticación de la solicitud y retorna los claims de la sucursal
+	AuthenticateRequest(ctx context.Context, credentials *models.RequestCredentials) (*models.AuthClaims, error)
 }
 
 // AuthManager define el comportamiento de un servicio de autenticación
 type AuthManager interface {
-	// Login maneja el proceso de autenticación
-	Login(ctx context.Context, credentials *models.AuthCredentials) (string, error)
+	// Login maneja el proceso de autenticación, retorna el access token y el refresh token de la sesión
+	Login(ctx context.Context, credentials *models.AuthCredentials) (*models.TokenResponse, error)
+	// Refresh renueva una sesión rotando su refresh token
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenResponse, error)
+	// AuthenticateRequest autentica una solicitud firmada o con certificado de cliente según el tipo de autenticación
+	AuthenticateRequest(ctx context.Context, credentials *models.RequestCredentials) (*models.AuthClaims, error)
+	// StoreHaciendaCredentials reemplaza las credenciales de Hacienda del usuario almacenadas en el vault
+	StoreHaciendaCredentials(ctx context.Context, claims *models.AuthClaims, creds *models.HaciendaCredentials) error
+	// Logout revoca el access token y el refresh token de una sesión junto con sus datos de Hacienda
+	Logout(ctx context.Context, token string, claims *models.AuthClaims) error
 	// GetByNIT obtiene un usuario por su NIT
 	GetByNIT(ctx context.Context, nit string) (*user.User, error)
 	// GetBranchByBranchID obtiene la sucursal por su ID
@@ -67,4 +113,30 @@ type AuthManager interface {
 	GetHaciendaCredentials(ctx context.Context, nit, token string) (*models.HaciendaCredentials, error)
 	// Create crea un usuario con sus sucursales
 	Create(ctx context.Context, user *user.User) error
+	// GetBranchOffices obtiene todas las sucursales de un usuario
+	GetBranchOffices(ctx context.Context, userID uint) ([]user.BranchOffice, error)
+	// CreateBranchOffice crea una sucursal para un usuario existente
+	CreateBranchOffice(ctx context.Context, userID uint, branch *user.BranchOffice) error
+	// UpdateBranchOffice actualiza los datos de una sucursal de un usuario
+	UpdateBranchOffice(ctx context.Context, userID uint, branch *user.BranchOffice) error
+	// DeactivateBranchOffice desactiva una sucursal e invalida los tokens emitidos para ella
+	DeactivateBranchOffice(ctx context.Context, userID, branchID uint) error
+	// RotateBranchCredentials reemplaza las llaves de una sucursal e invalida los tokens emitidos con las anteriores
+	RotateBranchCredentials(ctx context.Context, userID, branchID uint, apiKey, apiSecret, signingSecret string) error
+	// GetBranchAPIKeys obtiene las llaves de acceso adicionales de una sucursal
+	GetBranchAPIKeys(ctx context.Context, branchID uint) ([]user.BranchAPIKey, error)
+	// CreateBranchAPIKey crea una llave de acceso adicional para una sucursal
+	CreateBranchAPIKey(ctx context.Context, key *user.BranchAPIKey) error
+	// RevokeBranchAPIKey desactiva una llave de acceso adicional e invalida los tokens emitidos con ella
+	RevokeBranchAPIKey(ctx context.Context, branchID, keyID uint) error
+	// GetRegistrationConflicts obtiene los usuarios registrados o pendientes con el NIT o NRC indicados
+	GetRegistrationConflicts(ctx context.Context, nit, nrc string) ([]user.User, error)
+	// GetPendingRegistrations obtiene las solicitudes de registro pendientes de aprobación
+	GetPendingRegistrations(ctx context.Context) ([]user.User, error)
+	// GetRegistration obtiene una solicitud de registro con sus sucursales
+	GetRegistration(ctx context.Context, userID uint) (*user.User, error)
+	// ApproveRegistration activa un usuario pendiente y asigna las llaves de sus sucursales
+	ApproveRegistration(ctx context.Context, userID uint, branches []user.BranchOffice) error
+	// RejectRegistration elimina una solicitud de registro pendiente
+	RejectRegistration(ctx context.Context, userID uint) error
 }
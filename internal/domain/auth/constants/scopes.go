@@ -0,0 +1,49 @@
+package constants
+
+// Scopes que limitan las operaciones permitidas a un token
+const (
+	ScopeDTEIssue           = "dte:issue"             // Emitir DTE y reenviarlos por correo
+	ScopeDTEInvalidate      = "dte:invalidate"        // Invalidar DTE
+	ScopeDTERead            = "dte:read"              // Consultar los DTE de la sucursal
+	ScopeDTEReadAllBranches = "dte:read:all-branches" // Consultar los DTE de todas las sucursales del contribuyente
+	ScopeReports            = "reports"               // Exportaciones, archivos y métricas
+	ScopeAdmin              = "admin"                 // Administrar sucursales, llaves y configuración
+	ScopeOperator           = "operator"              // Operar los contribuyentes asignados a un operador, exclusivo de los tokens de operador
+)
+
+var (
+	// AllScopes scopes de las llaves principales de las sucursales, permiten realizar cualquier operación
+	AllScopes = []string{
+		ScopeDTEIssue,
+		ScopeDTEInvalidate,
+		ScopeDTERead,
+		ScopeDTEReadAllBranches,
+		ScopeReports,
+		ScopeAdmin,
+	}
+
+	// OperatorIssuerScopes scopes de los tokens que emite un operador para actuar como una sucursal asignada, no permiten
+	// administrar la sucursal ni consultar las demás sucursales del contribuyente
+	OperatorIssuerScopes = []string{
+		ScopeDTEIssue,
+		ScopeDTEInvalidate,
+		ScopeDTERead,
+		ScopeReports,
+	}
+
+	// MTLSDefaultScopes scopes de los certificados de cliente asignados a una sucursal sin una llave de acceso, los
+	// certificados asignados a una llave adicional obtienen los scopes de la llave
+	MTLSDefaultScopes = []string{
+		ScopeDTERead,
+	}
+
+	// ValidScopes scopes que pueden asignarse a una llave de acceso
+	ValidScopes = map[string]bool{
+		ScopeDTEIssue:           true,
+		ScopeDTEInvalidate:      true,
+		ScopeDTERead:            true,
+		ScopeDTEReadAllBranches: true,
+		ScopeReports:            true,
+		ScopeAdmin:              true,
+	}
+)
@@ -2,4 +2,16 @@ package constants
 
 var (
 	StandardAuthType = "STANDARD"
+	OperatorAuthType = "OPERATOR"
+	// HMACAuthType autentica cada solicitud con una firma HMAC del API key de la sucursal, no emite tokens
+	HMACAuthType = "HMAC"
+	// MTLSAuthType autentica cada solicitud con el certificado de cliente asignado a la sucursal, no emite tokens
+	MTLSAuthType = "MTLS"
 )
+
+// ValidUserAuthTypes tipos de autenticación que pueden asignarse a un usuario
+var ValidUserAuthTypes = map[string]bool{
+	StandardAuthType: true,
+	HMACAuthType:     true,
+	MTLSAuthType:     true,
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"strings"
 	"time"
 )
 
@@ -37,11 +38,33 @@ func (a *AuthCredentials) Validate() error {
 
 // AuthClaims representa la información que se incluirá en el token JWT
 type AuthClaims struct {
-	ClientID  uint      `json:"sub"`
-	BranchID  uint      `json:"branch_sub"`
-	AuthType  string    `json:"auth_type"`
-	NIT       string    `json:"nit"`
-	ExpiresAt time.Time `json:"expires_at"`
+	ClientID   uint      `json:"sub"`
+	BranchID   uint      `json:"branch_sub"`
+	AuthType   string    `json:"auth_type"`
+	NIT        string    `json:"nit"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IssuedAt   time.Time `json:"issued_at"`
+	KeyID      uint      `json:"key_id,omitempty"`
+	Scopes     []string  `json:"scopes"`
+	SessionID  string    `json:"sid,omitempty"`
+	OperatorID uint      `json:"operator_id,omitempty"` // Operador que inició sesión o que actúa como la sucursal del token
+	Language   string    `json:"language,omitempty"`    // Idioma por defecto del usuario para las respuestas
+}
+
+// HasScope verifica si el token posee el scope indicado. Los tokens emitidos antes de existir los scopes no los
+// incluyen y conservan el acceso completo de la sucursal hasta su expiración
+func (c *AuthClaims) HasScope(scope string) bool {
+	if c.Scopes == nil {
+		return true
+	}
+
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
 }
 
 // HaciendaCredentials representa las credenciales de hacienda
@@ -49,3 +72,66 @@ type HaciendaCredentials struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
+
+func (h *HaciendaCredentials) Validate() error {
+	if h.Username == "" {
+		return dte_errors.NewValidationError("RequiredField", "username")
+	}
+	if h.Password == "" {
+		return dte_errors.NewValidationError("RequiredField", "password")
+	}
+
+	return nil
+}
+
+// RequestCredentials representa los datos con los que se autentica una solicitud sin token. Las solicitudes firmadas
+// con HMAC incluyen el API key, la fecha de la firma y la firma, las autenticadas con certificado de cliente incluyen
+// el subject del certificado verificado por el servidor
+type RequestCredentials struct {
+	AuthType    string
+	APIKey      string
+	Timestamp   string
+	Signature   string
+	Method      string
+	Path        string
+	BodyHash    string
+	CertSubject string
+	CertSANs    []string // Nombres alternativos del certificado: DNS, correos y URIs
+}
+
+// CanonicalRequest retorna el contenido firmado de una solicitud: el método, la ruta con sus parámetros de consulta,
+// la fecha de la firma en segundos Unix y el SHA-256 en hexadecimal del cuerpo, separados por saltos de línea
+func (c *RequestCredentials) CanonicalRequest() string {
+	return strings.Join([]string{strings.ToUpper(c.Method), c.Path, c.Timestamp, c.BodyHash}, "\n")
+}
+
+// TokenResponse representa los tokens emitidos al iniciar sesión o al renovar la sesión
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshRequest representa la solicitud de renovación de la sesión
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *RefreshRequest) Validate() error {
+	if r.RefreshToken == "" {
+		return dte_errors.NewValidationError("RequiredField", "refresh_token")
+	}
+
+	return nil
+}
+
+// RefreshSession representa la sesión asociada a un refresh token. Los refresh tokens emitidos a partir de un mismo
+// inicio de sesión pertenecen a la misma familia, si uno de ellos se reutiliza se revoca la familia completa
+type RefreshSession struct {
+	FamilyID      string        `json:"family_id"`
+	AccessToken   string        `json:"access_token"`
+	Claims        AuthClaims    `json:"claims"`
+	TokenLifetime time.Duration `json:"token_lifetime"`
+	Reused        bool          `json:"-"`
+}
@@ -6,94 +6,332 @@ import (
 	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
 	"gorm.io/gorm"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	auditModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// defaultRevocationTTL vida de la revocación de tokens cuando el usuario no tiene configurada la vida de sus tokens
+const defaultRevocationTTL = 14 * 24 * time.Hour
+
 type AuthService struct {
 	strategies   map[string]auth.AuthStrategy
 	authRepo     auth.AuthRepositoryPort
 	tokenService ports.TokenManager
 	cacheService ports.CacheManager
+	vault        vault.CredentialVault
+	audit        audit.AuditManager
 }
 
 func NewAuthService(
 	tokenService ports.TokenManager,
 	clientRepository auth.AuthRepositoryPort,
 	cacheService ports.CacheManager,
+	cryptManager ports.CryptManager,
+	credentialVault vault.CredentialVault,
+	auditManager audit.AuditManager,
 ) auth.AuthManager {
 	return &AuthService{
 		strategies: map[string]auth.AuthStrategy{
-			constants.StandardAuthType: NewStandardAuthStrategy(clientRepository, cacheService),
+			constants.StandardAuthType: NewStandardAuthStrategy(clientRepository, cacheService, cryptManager),
+			constants.HMACAuthType:     NewHMACAuthStrategy(clientRepository, cacheService, cryptManager, credentialVault),
+			constants.MTLSAuthType:     NewMTLSAuthStrategy(clientRepository),
 		},
 		tokenService: tokenService,
 		authRepo:     clientRepository,
 		cacheService: cacheService,
+		vault:        credentialVault,
+		audit:        auditManager,
 	}
 }
 
 // Login maneja el proceso de autenticación
-func (s *AuthService) Login(ctx context.Context, credentials *models.AuthCredentials) (string, error) {
+func (s *AuthService) Login(ctx context.Context, credentials *models.AuthCredentials) (*models.TokenResponse, error) {
 	// 0. Verificar existencia de credenciales
 	if !credentialsExists(credentials) {
-		return "", shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "MissingCredentials")
+		return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "MissingCredentials")
 	}
 
 	// 1. Obtener tipo de autenticación
 	authType, err := s.authRepo.GetAuthTypeByApiKey(ctx, credentials.APIKey)
 	if err != nil {
 		if errors.Is(err, errPackage.ErrUserNotFound) {
-			return "", shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "NotFound")
+			return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "NotFound")
 		}
 
-		return "", err
+		return nil, err
 	}
 
 	// 2. Obtener la estrategia apropiada
 	strategy, exists := s.strategies[authType]
 	if !exists {
-		logs.Error("Auth strategy not found", map[string]interface{}{
+		logs.ErrorContext(ctx, "Auth strategy not found", map[string]interface{}{
 			"authType": authType,
 		})
-		return "", shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "ServerError", authType)
+		return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "ServerError", authType)
 	}
 
 	// 3. Validar formato de credenciales
-	if err = strategy.ValidateCredentials(credentials); err != nil {
-		return "", err
+	if err = strategy.ValidateCredentials(ctx, credentials); err != nil {
+		return nil, err
 	}
 
 	// 4. Autenticar usando la estrategia
 	claims, err := strategy.Authenticate(ctx, credentials)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 5. Obtener duración de vida del token
-	tokenLifetime, err := strategy.GetTokenLifetime(credentials)
+	tokenLifetime, err := strategy.GetTokenLifetime(ctx, credentials)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	// 6. Guardar credenciales en el vault para los procesos que no dependen de la sesión
+	if err = s.vault.Store(ctx, claims.ClientID, credentials.MHCredentials); err != nil {
+		return nil, err
+	}
+
+	// 7. Emitir el access token y el refresh token de una nueva sesión
+	tokens, err := s.issueTokens(ctx, claims, tokenLifetime, uuid.NewString(), credentials.MHCredentials)
+	if err != nil {
+		return nil, err
 	}
 
-	// 5. Generar token JWT
+	// 8. Registrar el inicio de sesión, la solicitud no está autenticada por lo que el actor se obtiene de los claims
+	actorType, actorID := auditModels.ActorUser, claims.ClientID
+	if claims.KeyID != 0 {
+		actorType, actorID = auditModels.ActorAPIKey, claims.KeyID
+	}
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventAuthLogin,
+		UserID:    claims.ClientID,
+		BranchID:  claims.BranchID,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Payload: map[string]interface{}{
+			"auth_type":  claims.AuthType,
+			"session_id": claims.SessionID,
+			"expires_in": tokens.ExpiresIn,
+		},
+	})
+
+	return tokens, nil
+}
+
+// AuthenticateRequest autentica una solicitud firmada o con certificado de cliente. La estrategia se elige según los
+// datos presentes en la solicitud y solo acepta a los usuarios que tienen asignado su tipo de autenticación
+func (s *AuthService) AuthenticateRequest(ctx context.Context, credentials *models.RequestCredentials) (*models.AuthClaims, error) {
+	// 1. Obtener la estrategia del tipo de autenticación de la solicitud
+	strategy, exists := s.strategies[credentials.AuthType].(auth.RequestAuthStrategy)
+	if !exists {
+		logs.ErrorContext(ctx, "Request auth strategy not found", map[string]interface{}{
+			"authType": credentials.AuthType,
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "AuthenticateRequest", "Unauthorized")
+	}
+
+	// 2. Autenticar la solicitud usando la estrategia
+	claims, err := strategy.AuthenticateRequest(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	logs.InfoContext(ctx, "Request authenticated successfully", map[string]interface{}{
+		"clientID": claims.ClientID,
+		"branchID": claims.BranchID,
+		"authType": claims.AuthType,
+	})
+
+	return claims, nil
+}
+
+// StoreHaciendaCredentials reemplaza las credenciales de Hacienda del usuario en el vault. Los usuarios que autentican
+// cada solicitud no inician sesión, por lo que es la única forma de registrar sus credenciales
+func (s *AuthService) StoreHaciendaCredentials(ctx context.Context, claims *models.AuthClaims, creds *models.HaciendaCredentials) error {
+	// 1. Reemplazar las credenciales almacenadas
+	if err := s.vault.Store(ctx, claims.ClientID, creds); err != nil {
+		return err
+	}
+
+	// 2. Descartar el token de Hacienda obtenido con las credenciales anteriores
+	if err := s.cacheService.DeleteSession(vault.HaciendaTokenKey(claims.ClientID)); err != nil {
+		logs.WarnContext(ctx, "Failed to clear the Hacienda token of the previous credentials", map[string]interface{}{
+			"clientID": claims.ClientID,
+			"error":    err.Error(),
+		})
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventCredentialsChanged,
+		UserID:    claims.ClientID,
+		BranchID:  claims.BranchID,
+		Payload: map[string]interface{}{
+			"action":   "hacienda_credentials_stored",
+			"username": creds.Username,
+		},
+	})
+
+	return nil
+}
+
+// Refresh renueva una sesión a partir de un refresh token. El refresh token utilizado deja de ser válido y se emite uno
+// nuevo junto con el access token, si se presenta un refresh token ya utilizado se revoca la sesión completa
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.TokenResponse, error) {
+	// 1. Obtener la sesión del refresh token
+	session, err := s.tokenService.ConsumeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Un refresh token reutilizado indica que fue robado, se revoca la sesión para el cliente legítimo y el atacante
+	if session.Reused {
+		logs.WarnContext(ctx, "Refresh token reuse detected, revoking session", map[string]interface{}{
+			"clientID": session.Claims.ClientID,
+			"branchID": session.Claims.BranchID,
+		})
+		s.revokeSession(ctx, session.FamilyID, "")
+		return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "Refresh", "RefreshTokenReused")
+	}
+
+	// 3. Verificar que el usuario y la sucursal sigan activos
+	claims := session.Claims
+	owner, err := s.authRepo.GetByBranchID(ctx, claims.BranchID)
+	if err != nil || !owner.Status {
+		s.revokeSession(ctx, session.FamilyID, session.AccessToken)
+		return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "Refresh", "UserNotActive")
+	}
+
+	// 4. Obtener las credenciales de Hacienda de la sesión desde el vault
+	creds, err := s.vault.Get(ctx, claims.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Invalidar el access token anterior y emitir los nuevos tokens en la misma sesión
+	s.revokeAccessToken(ctx, session.AccessToken)
+	return s.issueTokens(ctx, &claims, session.TokenLifetime, session.FamilyID, creds)
+}
+
+// Logout revoca el access token, su refresh token y los datos de Hacienda almacenados para la sesión
+func (s *AuthService) Logout(ctx context.Context, token string, claims *models.AuthClaims) error {
+	// 1. Revocar el access token, AuthMiddleware deja de aceptarlo
+	if err := s.tokenService.RevokeToken(token); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("AuthService", "Logout", err, "AuthServiceUnavailable")
+	}
+
+	// 2. Eliminar las credenciales y el token de Hacienda de la sesión
+	if err := s.cacheService.DeleteSession(token); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("AuthService", "Logout", err, "AuthServiceUnavailable")
+	}
+
+	// 3. Revocar los refresh tokens de la sesión, los tokens emitidos antes de existir los refresh tokens no tienen sesión
+	if claims.SessionID != "" {
+		if _, err := s.tokenService.RevokeRefreshFamily(ctx, claims.SessionID); err != nil {
+			return shared_error.NewFormattedGeneralServiceWithError("AuthService", "Logout", err, "AuthServiceUnavailable")
+		}
+	}
+
+	logs.InfoContext(ctx, "Client logged out successfully", map[string]interface{}{
+		"clientID": claims.ClientID,
+		"branchID": claims.BranchID,
+	})
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventAuthLogout,
+		UserID:    claims.ClientID,
+		BranchID:  claims.BranchID,
+		Payload: map[string]interface{}{
+			"session_id": claims.SessionID,
+		},
+	})
+
+	return nil
+}
+
+// issueTokens genera el access token y el refresh token de una sesión y guarda las credenciales de Hacienda del
+// access token. El ID de la sesión se incluye en los claims para poder revocarla al cerrar sesión
+func (s *AuthService) issueTokens(ctx context.Context, claims *models.AuthClaims, tokenLifetime time.Duration, familyID string, creds *models.HaciendaCredentials) (*models.TokenResponse, error) {
+	// 1. Generar token JWT
+	claims.SessionID = familyID
 	token, err := s.tokenService.GenerateToken(claims, tokenLifetime)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	// 2. Guardar credenciales en cache
+	if err = s.cacheService.SetCredentials(token, creds, tokenLifetime); err != nil {
+		return nil, err
+	}
+
+	// 3. Generar el refresh token de la sesión
+	session := &models.RefreshSession{
+		FamilyID:      familyID,
+		AccessToken:   token,
+		Claims:        *claims,
+		TokenLifetime: tokenLifetime,
+	}
+	refreshToken, err := s.tokenService.GenerateRefreshToken(ctx, session, time.Duration(config.Server.RefreshLifetime)*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokenLifetime.Seconds()),
+	}, nil
+}
+
+// revokeSession revoca los refresh tokens de una sesión junto con su último access token
+func (s *AuthService) revokeSession(ctx context.Context, familyID, accessToken string) {
+	latest, err := s.tokenService.RevokeRefreshFamily(ctx, familyID)
+	if err != nil {
+		logs.WarnContext(ctx, "Failed to revoke refresh token family", map[string]interface{}{
+			"familyID": familyID,
+			"error":    err.Error(),
+		})
+	}
+
+	if latest != "" {
+		s.revokeAccessToken(ctx, latest)
+	}
+	if accessToken != "" && accessToken != latest {
+		s.revokeAccessToken(ctx, accessToken)
 	}
+}
 
-	//6. Guardar credenciales en cache
-	if err = s.cacheService.SetCredentials(token, credentials.MHCredentials, tokenLifetime); err != nil {
-		return "", err
+// revokeAccessToken revoca un access token y elimina los datos de Hacienda de su sesión, los errores solo se registran
+// porque el token expira por sí solo
+func (s *AuthService) revokeAccessToken(ctx context.Context, token string) {
+	if err := s.tokenService.RevokeToken(token); err != nil {
+		logs.WarnContext(ctx, "Failed to revoke access token", map[string]interface{}{
+			"error": err.Error(),
+		})
 	}
 
-	return token, nil
+	if err := s.cacheService.DeleteSession(token); err != nil {
+		logs.WarnContext(ctx, "Failed to delete access token session", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
 }
 
 func (s *AuthService) GetHaciendaCredentials(ctx context.Context, nit, token string) (*models.HaciendaCredentials, error) {
@@ -101,30 +339,34 @@ func (s *AuthService) GetHaciendaCredentials(ctx context.Context, nit, token str
 	// 1. Obtener tipo de autenticación
 	authType, err := s.authRepo.GetAuthTypeByNIT(ctx, nit)
 	if err != nil {
-		logs.Info("Error getting auth type", map[string]interface{}{"error": err.Error()})
+		logs.InfoContext(ctx, "Error getting auth type", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
-	logs.Info("Auth type retrieved", map[string]interface{}{"authType": authType})
+	logs.InfoContext(ctx, "Auth type retrieved", map[string]interface{}{"authType": authType})
 
 	// 2. Obtener la estrategia apropiada
 	strategy, exists := s.strategies[authType]
 	if !exists {
-		logs.Info("Unsupported authentication type", map[string]interface{}{"authType": authType})
+		logs.InfoContext(ctx, "Unsupported authentication type", map[string]interface{}{"authType": authType})
 		return nil, errors.New("unsupported authentication type")
 	}
-	logs.Info("Strategy found", map[string]interface{}{"strategy": strategy.GetAuthType()})
+	logs.InfoContext(ctx, "Strategy found", map[string]interface{}{"strategy": strategy.GetAuthType()})
 
-	return strategy.GetHaciendaCredentials(token)
+	return strategy.GetHaciendaCredentials(ctx, token)
 }
 
 // GetIssuer retorna el emisor por su id de sucursal
 func (s *AuthService) GetIssuer(ctx context.Context, branchID uint) (*dte.IssuerDTE, error) {
-	return s.authRepo.GetIssuerInfoByBranchID(ctx, branchID)
+	ctx, span := tracing.Start(ctx, "AuthService.GetIssuer", attribute.Int("branch.id", int(branchID)))
+	issuer, err := s.authRepo.GetIssuerInfoByBranchID(ctx, branchID)
+	tracing.End(span, err)
+
+	return issuer, err
 }
 
 // ValidateToken valida un token existente
-func (s *AuthService) ValidateToken(token string) (*models.AuthClaims, error) {
-	return s.tokenService.ValidateToken(token)
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (*models.AuthClaims, error) {
+	return s.tokenService.ValidateToken(ctx, token)
 }
 
 // RevokeToken revoca un token
@@ -137,13 +379,25 @@ func credentialsExists(credentials *models.AuthCredentials) bool {
 	return credentials.APIKey != "" && credentials.APISecret != "" && credentials.MHCredentials != nil && credentials.MHCredentials.Username != "" && credentials.MHCredentials.Password != ""
 }
 
-// Create crea un usuario con sus sucursales
+// Create crea un usuario con sus sucursales. El evento de auditoría no referencia al usuario porque un registro
+// rechazado se elimina y su auditoría debe conservarse
 func (s *AuthService) Create(ctx context.Context, user *user.User) error {
 	err := s.authRepo.Create(ctx, user)
 	if err != nil {
 		return handleGormError("Create", err)
 	}
 
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventUserRegistered,
+		Payload: map[string]interface{}{
+			"user_id":             user.ID,
+			"nit":                 user.NIT,
+			"nrc":                 user.NRC,
+			"registration_status": user.RegistrationStatus,
+			"branches":            len(user.BranchOffices),
+		},
+	})
+
 	return nil
 }
 
@@ -165,6 +419,240 @@ func (s *AuthService) GetBranchByBranchID(ctx context.Context, branchID uint) (*
 	return branch, nil
 }
 
+// GetBranchOffices obtiene todas las sucursales de un usuario
+func (s *AuthService) GetBranchOffices(ctx context.Context, userID uint) ([]user.BranchOffice, error) {
+	branches, err := s.authRepo.GetBranchOffices(ctx, userID)
+	if err != nil {
+		return nil, handleGormError("GetBranchOffices", err)
+	}
+
+	return branches, nil
+}
+
+// CreateBranchOffice crea una sucursal para un usuario existente
+func (s *AuthService) CreateBranchOffice(ctx context.Context, userID uint, branch *user.BranchOffice) error {
+	if err := s.authRepo.CreateBranchOffice(ctx, userID, branch); err != nil {
+		return handleGormError("CreateBranchOffice", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventBranchCreated,
+		UserID:    userID,
+		BranchID:  branch.ID,
+		Payload:   branchAuditPayload(branch),
+	})
+
+	return nil
+}
+
+// UpdateBranchOffice actualiza los datos de una sucursal de un usuario
+func (s *AuthService) UpdateBranchOffice(ctx context.Context, userID uint, branch *user.BranchOffice) error {
+	if err := s.authRepo.UpdateBranchOffices(ctx, userID, []user.BranchOffice{*branch}); err != nil {
+		return handleGormError("UpdateBranchOffice", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventBranchUpdated,
+		UserID:    userID,
+		BranchID:  branch.ID,
+		Payload:   branchAuditPayload(branch),
+	})
+
+	return nil
+}
+
+// DeactivateBranchOffice desactiva una sucursal e invalida los tokens emitidos para ella
+func (s *AuthService) DeactivateBranchOffice(ctx context.Context, userID, branchID uint) error {
+	// 1. Desactivar la sucursal, a partir de este momento no puede iniciar sesión
+	if err := s.authRepo.SetBranchOfficeStatus(ctx, userID, branchID, false); err != nil {
+		return handleGormError("DeactivateBranchOffice", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventBranchDeactivated,
+		UserID:    userID,
+		BranchID:  branchID,
+	})
+
+	// 2. Invalidar los tokens vigentes de la sucursal
+	return s.revokeBranchTokens(ctx, branchID)
+}
+
+// RotateBranchCredentials reemplaza las llaves de una sucursal e invalida los tokens emitidos con las anteriores
+func (s *AuthService) RotateBranchCredentials(ctx context.Context, userID, branchID uint, apiKey, apiSecret, signingSecret string) error {
+	// 1. Reemplazar las llaves, las anteriores dejan de ser válidas para iniciar sesión
+	if err := s.authRepo.UpdateBranchCredentials(ctx, userID, branchID, apiKey, apiSecret, signingSecret); err != nil {
+		return handleGormError("RotateBranchCredentials", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventCredentialsChanged,
+		UserID:    userID,
+		BranchID:  branchID,
+		Payload: map[string]interface{}{
+			"action":  "branch_keys_rotated",
+			"api_key": apiKey,
+		},
+	})
+
+	// 2. Invalidar los tokens emitidos con las llaves anteriores
+	return s.revokeBranchTokens(ctx, branchID)
+}
+
+// GetBranchAPIKeys obtiene las llaves de acceso adicionales de una sucursal
+func (s *AuthService) GetBranchAPIKeys(ctx context.Context, branchID uint) ([]user.BranchAPIKey, error) {
+	keys, err := s.authRepo.GetBranchAPIKeys(ctx, branchID)
+	if err != nil {
+		return nil, handleGormError("GetBranchAPIKeys", err)
+	}
+
+	return keys, nil
+}
+
+// CreateBranchAPIKey crea una llave de acceso adicional para una sucursal
+func (s *AuthService) CreateBranchAPIKey(ctx context.Context, key *user.BranchAPIKey) error {
+	if err := s.authRepo.CreateBranchAPIKey(ctx, key); err != nil {
+		return handleGormError("CreateBranchAPIKey", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventCredentialsChanged,
+		UserID:    userIDFromContext(ctx),
+		BranchID:  key.BranchID,
+		Payload: map[string]interface{}{
+			"action":  "api_key_created",
+			"key_id":  key.ID,
+			"name":    key.Name,
+			"api_key": key.APIKey,
+			"scopes":  key.Scopes,
+		},
+	})
+
+	return nil
+}
+
+// RevokeBranchAPIKey desactiva una llave de acceso adicional e invalida los tokens emitidos con ella
+func (s *AuthService) RevokeBranchAPIKey(ctx context.Context, branchID, keyID uint) error {
+	// 1. Desactivar la llave, a partir de este momento no puede iniciar sesión
+	if err := s.authRepo.SetBranchAPIKeyStatus(ctx, branchID, keyID, false); err != nil {
+		return handleGormError("RevokeBranchAPIKey", err)
+	}
+
+	// 2. Invalidar los tokens vigentes de la llave
+	tokenLifetime, err := s.revocationTTL(ctx, branchID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.tokenService.RevokeAPIKeyTokens(keyID, tokenLifetime); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("AuthService", "RevokeBranchAPIKey", err, "AuthServiceUnavailable")
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventCredentialsChanged,
+		UserID:    userIDFromContext(ctx),
+		BranchID:  branchID,
+		Payload: map[string]interface{}{
+			"action": "api_key_revoked",
+			"key_id": keyID,
+		},
+	})
+
+	return nil
+}
+
+// GetRegistrationConflicts obtiene los usuarios registrados o pendientes con el NIT o NRC indicados
+func (s *AuthService) GetRegistrationConflicts(ctx context.Context, nit, nrc string) ([]user.User, error) {
+	users, err := s.authRepo.GetRegistrationConflicts(ctx, nit, nrc)
+	if err != nil {
+		return nil, handleGormError("GetRegistrationConflicts", err)
+	}
+
+	return users, nil
+}
+
+// GetPendingRegistrations obtiene las solicitudes de registro pendientes de aprobación
+func (s *AuthService) GetPendingRegistrations(ctx context.Context) ([]user.User, error) {
+	users, err := s.authRepo.GetRegistrations(ctx, user.RegistrationPending)
+	if err != nil {
+		return nil, handleGormError("GetPendingRegistrations", err)
+	}
+
+	return users, nil
+}
+
+// GetRegistration obtiene una solicitud de registro con sus sucursales
+func (s *AuthService) GetRegistration(ctx context.Context, userID uint) (*user.User, error) {
+	registration, err := s.authRepo.GetRegistrationByID(ctx, userID)
+	if err != nil {
+		return nil, handleGormError("GetRegistration", err)
+	}
+
+	return registration, nil
+}
+
+// ApproveRegistration activa un usuario pendiente y asigna las llaves de sus sucursales
+func (s *AuthService) ApproveRegistration(ctx context.Context, userID uint, branches []user.BranchOffice) error {
+	if err := s.authRepo.ApproveRegistration(ctx, userID, branches); err != nil {
+		return handleGormError("ApproveRegistration", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventRegistrationApproved,
+		UserID:    userID,
+		Payload: map[string]interface{}{
+			"branches": len(branches),
+		},
+	})
+
+	return nil
+}
+
+// RejectRegistration elimina una solicitud de registro pendiente
+func (s *AuthService) RejectRegistration(ctx context.Context, userID uint) error {
+	if err := s.authRepo.DeleteRegistration(ctx, userID); err != nil {
+		return handleGormError("RejectRegistration", err)
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventRegistrationRejected,
+		Payload: map[string]interface{}{
+			"user_id": userID,
+		},
+	})
+
+	return nil
+}
+
+// revokeBranchTokens invalida los tokens de una sucursal durante la vida máxima de los tokens de su usuario
+func (s *AuthService) revokeBranchTokens(ctx context.Context, branchID uint) error {
+	tokenLifetime, err := s.revocationTTL(ctx, branchID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.tokenService.RevokeBranchTokens(branchID, tokenLifetime); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("AuthService", "RevokeBranchTokens", err, "AuthServiceUnavailable")
+	}
+
+	return nil
+}
+
+// revocationTTL obtiene la vida máxima de los tokens de una sucursal, la revocación debe mantenerse durante ese tiempo
+func (s *AuthService) revocationTTL(ctx context.Context, branchID uint) (time.Duration, error) {
+	branch, err := s.authRepo.GetBranchByBranchID(ctx, branchID)
+	if err != nil {
+		return 0, handleGormError("RevokeTokens", err)
+	}
+
+	tokenLifetime := time.Duration(branch.User.TokenLifetime) * 24 * time.Hour
+	if tokenLifetime <= 0 {
+		tokenLifetime = defaultRevocationTTL
+	}
+
+	return tokenLifetime, nil
+}
+
 func handleGormError(operation string, err error) error {
 	if errors.Is(err, gorm.ErrInvalidData) {
 		return shared_error.NewFormattedGeneralServiceError("AuthService", operation, "InvalidData")
@@ -200,3 +688,25 @@ func isDuplicatedEntryErr(err error) bool {
 		strings.Contains(errMsg, "violates unique") || // PostgreSQL
 		strings.Contains(errMsg, "unique key constraint") // SQL Server
 }
+
+// branchAuditPayload retorna los datos de una sucursal que se registran en la auditoría, sin sus llaves de acceso
+func branchAuditPayload(branch *user.BranchOffice) map[string]interface{} {
+	return map[string]interface{}{
+		"establishment_type":    branch.EstablishmentType,
+		"establishment_code":    branch.EstablishmentCode,
+		"establishment_code_mh": branch.EstablishmentCodeMH,
+		"pos_code":              branch.POSCode,
+		"pos_code_mh":           branch.POSCodeMH,
+		"email":                 branch.Email,
+		"phone":                 branch.Phone,
+		"client_cert_subject":   branch.ClientCertSubject,
+	}
+}
+
+// userIDFromContext obtiene el ID del usuario autenticado, retorna cero si la solicitud no está autenticada
+func userIDFromContext(ctx context.Context) uint {
+	if claims, ok := ctx.Value("claims").(*models.AuthClaims); ok && claims != nil {
+		return claims.ClientID
+	}
+	return 0
+}
@@ -0,0 +1,142 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// HMACMaxClockSkew diferencia máxima entre la fecha de una firma y la hora del servidor. Cada firma se acepta una sola
+// vez mientras su fecha se encuentre dentro de este margen
+const HMACMaxClockSkew = 5 * time.Minute
+
+type HMACAuthStrategy struct {
+	requestOnlyStrategy
+	authRepo     auth.AuthRepositoryPort
+	cacheService ports.CacheManager
+	cryptManager ports.CryptManager
+	vault        vault.CredentialVault
+}
+
+// NewHMACAuthStrategy crea una instancia de HMACAuthStrategy. Recibe un repositorio de clientes y el vault con el que
+// se descifran los API secrets de las llaves.
+func NewHMACAuthStrategy(repo auth.AuthRepositoryPort, cacheService ports.CacheManager, cryptManager ports.CryptManager, credentialVault vault.CredentialVault) *HMACAuthStrategy {
+	return &HMACAuthStrategy{
+		requestOnlyStrategy: requestOnlyStrategy{authType: constants.HMACAuthType},
+		authRepo:            repo,
+		cacheService:        cacheService,
+		cryptManager:        cryptManager,
+		vault:               credentialVault,
+	}
+}
+
+// AuthenticateRequest autentica una solicitud firmada con HMAC-SHA256. La firma cubre el método, la ruta, la fecha de
+// la firma y el hash del cuerpo, y se calcula con el API secret de la llave indicada en la solicitud
+func (s *HMACAuthStrategy) AuthenticateRequest(ctx context.Context, credentials *models.RequestCredentials) (*models.AuthClaims, error) {
+	// 1. Validar que la solicitud incluya los datos de la firma
+	if credentials.APIKey == "" {
+		return nil, dte_errors.NewValidationError("RequiredField", "X-API-Key")
+	}
+	if credentials.Timestamp == "" {
+		return nil, dte_errors.NewValidationError("RequiredField", "X-Timestamp")
+	}
+	if credentials.Signature == "" {
+		return nil, dte_errors.NewValidationError("RequiredField", "X-Signature")
+	}
+
+	// 2. Rechazar las firmas fuera del margen permitido, limita el tiempo en que una firma capturada puede reutilizarse
+	timestamp, err := strconv.ParseInt(credentials.Timestamp, 10, 64)
+	if err != nil {
+		return nil, dte_errors.NewValidationError("InvalidFormat", "X-Timestamp", "Unix time in seconds", credentials.Timestamp)
+	}
+	skew := utils.TimeNow().Sub(time.Unix(timestamp, 0))
+	if skew > HMACMaxClockSkew || skew < -HMACMaxClockSkew {
+		logs.WarnContext(ctx, "Request signature outside the allowed clock skew", map[string]interface{}{
+			"apiKey": credentials.APIKey,
+			"skew":   skew.String(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "ExpiredSignature", int(HMACMaxClockSkew.Seconds()))
+	}
+
+	// 3. Obtener la llave de acceso y verificar la firma con su API secret, una llave inexistente y una firma inválida
+	// retornan el mismo error
+	access, err := getAccessKey(ctx, s.authRepo, credentials.APIKey)
+	var secret string
+	if err == nil {
+		secret, err = s.openSigningSecret(access)
+	}
+	if err != nil || !s.cryptManager.VerifyRequestSignature(secret, credentials.CanonicalRequest(), credentials.Signature) {
+		logs.ErrorContext(ctx, "Invalid request signature", map[string]interface{}{
+			"apiKey": credentials.APIKey,
+			"method": credentials.Method,
+			"path":   credentials.Path,
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "InvalidCredentials")
+	}
+
+	// 4. Obtener y verificar el usuario de la sucursal
+	owner, err := s.authRepo.GetByBranchID(ctx, access.branchID)
+	if err != nil {
+		logs.ErrorContext(ctx, "Invalid credentials", map[string]interface{}{
+			"apiKey": credentials.APIKey,
+			"error":  err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "InvalidCredentials")
+	}
+
+	if err = s.checkRequestUser(ctx, owner, access.branchID); err != nil {
+		return nil, err
+	}
+
+	// 5. Aceptar cada firma una sola vez, la llave expira después de que la fecha de la firma sale del margen permitido
+	accepted, err := s.cacheService.GetRedisClient().
+		SetNX(ctx, fmt.Sprintf("hmac:signature:%s", credentials.Signature), timestamp, 2*HMACMaxClockSkew).
+		Result()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to register request signature", map[string]interface{}{
+			"apiKey": credentials.APIKey,
+			"error":  err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "AuthServiceUnavailable")
+	}
+	if !accepted {
+		logs.WarnContext(ctx, "Replayed request signature", map[string]interface{}{
+			"apiKey": credentials.APIKey,
+			"method": credentials.Method,
+			"path":   credentials.Path,
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "ReplayedSignature")
+	}
+
+	// 6. Crear claims con los scopes de la llave utilizada
+	return &models.AuthClaims{
+		ClientID: owner.ID,
+		BranchID: access.branchID,
+		KeyID:    access.keyID,
+		AuthType: owner.AuthType,
+		NIT:      owner.NIT,
+		Language: owner.Language,
+		Scopes:   access.scopes,
+		IssuedAt: utils.TimeNow(),
+	}, nil
+}
+
+// openSigningSecret descifra el API secret de la llave de acceso. Las llaves emitidas antes de almacenar su API secret
+// cifrado solo tienen su hash y deben rotarse para firmar solicitudes
+func (s *HMACAuthStrategy) openSigningSecret(access *accessKey) (string, error) {
+	if access.signingSecret == nil || *access.signingSecret == "" {
+		return "", fmt.Errorf("api key of branch %d has no signing secret, rotate it to sign requests", access.branchID)
+	}
+	return s.vault.OpenSecret(*access.signingSecret)
+}
@@ -0,0 +1,102 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+type MTLSAuthStrategy struct {
+	requestOnlyStrategy
+	authRepo auth.AuthRepositoryPort
+}
+
+// NewMTLSAuthStrategy crea una instancia de MTLSAuthStrategy. Recibe un repositorio de clientes.
+func NewMTLSAuthStrategy(repo auth.AuthRepositoryPort) *MTLSAuthStrategy {
+	return &MTLSAuthStrategy{
+		requestOnlyStrategy: requestOnlyStrategy{authType: constants.MTLSAuthType},
+		authRepo:            repo,
+	}
+}
+
+// AuthenticateRequest autentica una solicitud con el certificado de cliente verificado por el servidor. El subject o
+// uno de los SAN del certificado debe estar asignado a una llave de acceso adicional activa, la solicitud obtiene los
+// scopes de la llave, o a una sucursal activa, la solicitud solo obtiene el scope de consulta
+func (s *MTLSAuthStrategy) AuthenticateRequest(ctx context.Context, credentials *models.RequestCredentials) (*models.AuthClaims, error) {
+	// 1. Obtener la sucursal y los scopes asignados al certificado
+	branch, access, err := s.getCertAccess(ctx, credentials)
+	if err != nil {
+		logs.ErrorContext(ctx, "Client certificate is not assigned to a branch office", map[string]interface{}{
+			"subject": credentials.CertSubject,
+			"sans":    credentials.CertSANs,
+			"error":   err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("MTLSAuth", "AuthenticateRequest", "InvalidCredentials")
+	}
+
+	// 2. Verificar el usuario de la sucursal
+	if err = s.checkRequestUser(ctx, branch.User, branch.ID); err != nil {
+		return nil, err
+	}
+
+	// 3. Crear claims con los scopes del certificado
+	return &models.AuthClaims{
+		ClientID: branch.User.ID,
+		BranchID: branch.ID,
+		AuthType: branch.User.AuthType,
+		NIT:      branch.User.NIT,
+		Language: branch.User.Language,
+		KeyID:    access.keyID,
+		Scopes:   access.scopes,
+		IssuedAt: utils.TimeNow(),
+	}, nil
+}
+
+// getCertAccess obtiene la sucursal y los scopes asignados al subject o a los SAN del certificado. Las llaves de acceso
+// adicionales tienen prioridad sobre la sucursal, los certificados asignados solo a la sucursal obtienen
+// constants.MTLSDefaultScopes
+func (s *MTLSAuthStrategy) getCertAccess(ctx context.Context, credentials *models.RequestCredentials) (*user.BranchOffice, *accessKey, error) {
+	identities := append([]string{credentials.CertSubject}, credentials.CertSANs...)
+
+	// 1. Buscar la llave de acceso adicional asignada al certificado
+	for _, identity := range identities {
+		key, err := s.authRepo.GetBranchAPIKeyByClientCertSubject(ctx, identity)
+		if err != nil {
+			continue
+		}
+		if !key.IsActive {
+			return nil, nil, fmt.Errorf("api key %d is revoked", key.ID)
+		}
+
+		branch, err := s.authRepo.GetBranchByBranchID(ctx, key.BranchID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !branch.IsActive {
+			return nil, nil, fmt.Errorf("branch office %d is not active", branch.ID)
+		}
+
+		return branch, &accessKey{branchID: branch.ID, keyID: key.ID, scopes: key.Scopes}, nil
+	}
+
+	// 2. Buscar la sucursal asignada al certificado
+	var err error
+	for _, identity := range identities {
+		var branch *user.BranchOffice
+		if branch, err = s.authRepo.GetBranchByClientCertSubject(ctx, identity); err == nil {
+			return branch, &accessKey{
+				branchID: branch.ID,
+				scopes:   append([]string(nil), constants.MTLSDefaultScopes...),
+			}, nil
+		}
+	}
+
+	return nil, nil, err
+}
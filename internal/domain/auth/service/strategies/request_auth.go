@@ -0,0 +1,68 @@
+package strategies
+
+import (
+	"context"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+// requestOnlyStrategy implementa el inicio de sesión de las estrategias que autentican cada solicitud. Estas
+// estrategias no emiten tokens, por lo que el inicio de sesión se rechaza y las credenciales de Hacienda se obtienen
+// únicamente del vault
+type requestOnlyStrategy struct {
+	authType string
+}
+
+// GetAuthType devuelve el tipo de autenticación.
+func (s *requestOnlyStrategy) GetAuthType() string {
+	return s.authType
+}
+
+// ValidateCredentials rechaza el inicio de sesión, los usuarios de este tipo de autenticación no obtienen tokens.
+func (s *requestOnlyStrategy) ValidateCredentials(ctx context.Context, credentials *models.AuthCredentials) error {
+	logs.WarnContext(ctx, "Token login attempted with a request authentication type", map[string]interface{}{
+		"apiKey":   credentials.APIKey,
+		"authType": s.authType,
+	})
+	return shared_error.NewFormattedGeneralServiceError("AuthService", "Login", "TokenLoginNotSupported", s.authType)
+}
+
+// Authenticate rechaza el inicio de sesión, los usuarios de este tipo de autenticación no obtienen tokens.
+func (s *requestOnlyStrategy) Authenticate(ctx context.Context, credentials *models.AuthCredentials) (*models.AuthClaims, error) {
+	return nil, s.ValidateCredentials(ctx, credentials)
+}
+
+// GetTokenLifetime rechaza el inicio de sesión, los usuarios de este tipo de autenticación no obtienen tokens.
+func (s *requestOnlyStrategy) GetTokenLifetime(ctx context.Context, credentials *models.AuthCredentials) (time.Duration, error) {
+	return 0, s.ValidateCredentials(ctx, credentials)
+}
+
+// GetHaciendaCredentials no cuenta con credenciales de sesión, las credenciales deben almacenarse en el vault
+func (s *requestOnlyStrategy) GetHaciendaCredentials(_ context.Context, _ string) (*models.HaciendaCredentials, error) {
+	return nil, shared_error.NewFormattedGeneralServiceError("AuthService", "GetHaciendaCredentials", "HaciendaCredentialsNotFound")
+}
+
+// checkRequestUser verifica que el usuario de la sucursal esté activo y utilice el tipo de autenticación de la estrategia
+func (s *requestOnlyStrategy) checkRequestUser(ctx context.Context, owner *user.User, branchID uint) error {
+	if !owner.Status {
+		logs.ErrorContext(ctx, "Client account is not active", map[string]interface{}{
+			"clientID": owner.ID,
+		})
+		return shared_error.NewFormattedGeneralServiceError("AuthService", "AuthenticateRequest", "UserNotActive")
+	}
+
+	if owner.AuthType != s.authType {
+		logs.WarnContext(ctx, "Request authenticated with an auth type not assigned to the user", map[string]interface{}{
+			"clientID": owner.ID,
+			"branchID": branchID,
+			"authType": s.authType,
+		})
+		return shared_error.NewFormattedGeneralServiceError("AuthService", "AuthenticateRequest", "AuthTypeNotAllowed", s.authType)
+	}
+
+	return nil
+}
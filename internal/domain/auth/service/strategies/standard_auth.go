@@ -2,7 +2,7 @@ package strategies
 
 import (
 	"context"
-	"crypto/subtle"
+	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
@@ -16,13 +16,15 @@ import (
 type StandardAuthStrategy struct {
 	authRepo     auth.AuthRepositoryPort
 	cacheService ports.CacheManager
+	cryptManager ports.CryptManager
 }
 
 // NewStandardAuthStrategy crea una instancia de StandardAuthStrategy. Recibe un repositorio de clientes.
-func NewStandardAuthStrategy(repo auth.AuthRepositoryPort, cacheService ports.CacheManager) *StandardAuthStrategy {
+func NewStandardAuthStrategy(repo auth.AuthRepositoryPort, cacheService ports.CacheManager, cryptManager ports.CryptManager) *StandardAuthStrategy {
 	return &StandardAuthStrategy{
 		cacheService: cacheService,
 		authRepo:     repo,
+		cryptManager: cryptManager,
 	}
 }
 
@@ -32,11 +34,9 @@ func (s *StandardAuthStrategy) GetAuthType() string {
 }
 
 // ValidateCredentials valida las credenciales de autenticación. Devuelve un error si las credenciales son inválidas.
-func (s *StandardAuthStrategy) ValidateCredentials(credentials *models.AuthCredentials) error {
+func (s *StandardAuthStrategy) ValidateCredentials(ctx context.Context, credentials *models.AuthCredentials) error {
 	if credentials.APIKey == "" {
-		logs.Error("API key is required", map[string]interface{}{
-			"credentials": credentials,
-		})
+		logs.ErrorContext(ctx, "API key is required")
 		return shared_error.NewFormattedGeneralServiceError(
 			"StandardAuth",
 			"ValidateCredentials",
@@ -45,8 +45,8 @@ func (s *StandardAuthStrategy) ValidateCredentials(credentials *models.AuthCrede
 		)
 	}
 	if credentials.APISecret == "" {
-		logs.Error("API secret is required", map[string]interface{}{
-			"credentials": credentials,
+		logs.ErrorContext(ctx, "API secret is required", map[string]interface{}{
+			"apiKey": credentials.APIKey,
 		})
 		return shared_error.NewFormattedGeneralServiceError(
 			"StandardAuth",
@@ -60,10 +60,10 @@ func (s *StandardAuthStrategy) ValidateCredentials(credentials *models.AuthCrede
 
 // Authenticate autentica un cliente. Devuelve los claims del cliente autenticado.
 func (s *StandardAuthStrategy) Authenticate(ctx context.Context, credentials *models.AuthCredentials) (*models.AuthClaims, error) {
-	// 1. Obtener sucursal por API key
-	branch, err := s.authRepo.GetBranchByBranchApiKey(ctx, credentials.APIKey)
+	// 1. Obtener la llave de acceso, puede ser la llave principal de una sucursal o una llave adicional
+	access, err := getAccessKey(ctx, s.authRepo, credentials.APIKey)
 	if err != nil {
-		logs.Error("Invalid credentials", map[string]interface{}{
+		logs.ErrorContext(ctx, "Invalid credentials", map[string]interface{}{
 			"apiKey": credentials.APIKey,
 			"error":  err.Error(),
 		})
@@ -74,9 +74,9 @@ func (s *StandardAuthStrategy) Authenticate(ctx context.Context, credentials *mo
 		)
 	}
 
-	// 2. Verificar credenciales
-	if subtle.ConstantTimeCompare([]byte(credentials.APISecret), []byte(branch.APISecret)) != 1 {
-		logs.Error("Invalid credentials", map[string]interface{}{
+	// 2. Verificar credenciales, el API secret se almacena como hash
+	if !s.cryptManager.VerifyAPISecret(credentials.APISecret, access.hashedSecret) {
+		logs.ErrorContext(ctx, "Invalid credentials", map[string]interface{}{
 			"apiKey": credentials.APIKey,
 		})
 		return nil, shared_error.NewFormattedGeneralServiceError(
@@ -86,10 +86,10 @@ func (s *StandardAuthStrategy) Authenticate(ctx context.Context, credentials *mo
 		)
 	}
 
-	// 3. Obtener usuario por API key
-	user, err := s.authRepo.GetByBranchApiKey(ctx, credentials.APIKey)
+	// 3. Obtener usuario de la sucursal
+	user, err := s.authRepo.GetByBranchID(ctx, access.branchID)
 	if err != nil {
-		logs.Error("Invalid credentials", map[string]interface{}{
+		logs.ErrorContext(ctx, "Invalid credentials", map[string]interface{}{
 			"apiKey": credentials.APIKey,
 			"error":  err.Error(),
 		})
@@ -102,7 +102,7 @@ func (s *StandardAuthStrategy) Authenticate(ctx context.Context, credentials *mo
 
 	// 4. Verificar estado de cuenta de usuario
 	if !user.Status {
-		logs.Error("Client account is not active", map[string]interface{}{
+		logs.ErrorContext(ctx, "Client account is not active", map[string]interface{}{
 			"clientID": user.ID,
 		})
 		return nil, shared_error.NewFormattedGeneralServiceError(
@@ -112,45 +112,94 @@ func (s *StandardAuthStrategy) Authenticate(ctx context.Context, credentials *mo
 		)
 	}
 
-	// 5. Crear claims
+	// 5. Crear claims con los scopes de la llave utilizada
 	claims := &models.AuthClaims{
 		ClientID: user.ID,
-		BranchID: branch.ID,
+		BranchID: access.branchID,
+		KeyID:    access.keyID,
 		AuthType: user.AuthType,
 		NIT:      user.NIT,
+		Language: user.Language,
+		Scopes:   access.scopes,
 	}
 
-	logs.Info("Client authenticated successfully", map[string]interface{}{
+	logs.InfoContext(ctx, "Client authenticated successfully", map[string]interface{}{
 		"clientID": claims.ClientID,
+		"keyID":    claims.KeyID,
 	})
 
 	return claims, nil
 }
 
-func (s *StandardAuthStrategy) GetTokenLifetime(credentials *models.AuthCredentials) (time.Duration, error) {
-	// 1. Obtener informacion del usuario
-	user, err := s.authRepo.GetByBranchApiKey(context.Background(), credentials.APIKey)
-	if err != nil {
-		logs.Error("Failed to get user information", map[string]interface{}{
-			"apiKey": credentials.APIKey,
-			"error":  err.Error(),
-		})
-		return 0, shared_error.NewFormattedGeneralServiceError(
-			"StandardAuth",
-			"GetTokenLifetime",
-			"ServerError",
-		)
+func (s *StandardAuthStrategy) GetTokenLifetime(ctx context.Context, credentials *models.AuthCredentials) (time.Duration, error) {
+	// 1. Obtener la sucursal de la llave de acceso
+	access, err := getAccessKey(ctx, s.authRepo, credentials.APIKey)
+	if err == nil {
+		// 2. Obtener informacion del usuario
+		user, userErr := s.authRepo.GetByBranchID(ctx, access.branchID)
+		if userErr == nil {
+			return time.Duration(user.TokenLifetime) * 24 * time.Hour, nil
+		}
+		err = userErr
 	}
 
-	return time.Duration(user.TokenLifetime) * 24 * time.Hour, nil
+	logs.ErrorContext(ctx, "Failed to get user information", map[string]interface{}{
+		"apiKey": credentials.APIKey,
+		"error":  err.Error(),
+	})
+	return 0, shared_error.NewFormattedGeneralServiceError(
+		"StandardAuth",
+		"GetTokenLifetime",
+		"ServerError",
+	)
+}
+
+// accessKey datos de la llave con la que se autentica un cliente
+type accessKey struct {
+	branchID      uint
+	keyID         uint
+	hashedSecret  string
+	signingSecret *string
+	scopes        []string
+}
+
+// getAccessKey obtiene la llave de acceso por su API key. Las llaves principales de las sucursales tienen todos los
+// scopes, las llaves adicionales solo los que se les asignaron al crearlas y deben estar activas
+func getAccessKey(ctx context.Context, authRepo auth.AuthRepositoryPort, apiKey string) (*accessKey, error) {
+	// 1. Buscar entre las llaves principales de las sucursales
+	branch, err := authRepo.GetBranchByBranchApiKey(ctx, apiKey)
+	if err == nil {
+		return &accessKey{
+			branchID:      branch.ID,
+			hashedSecret:  branch.APISecret,
+			signingSecret: branch.APISigningSecret,
+			scopes:        append([]string(nil), constants.AllScopes...),
+		}, nil
+	}
+
+	// 2. Buscar entre las llaves adicionales
+	key, keyErr := authRepo.GetBranchAPIKeyByKey(ctx, apiKey)
+	if keyErr != nil {
+		return nil, err
+	}
+	if !key.IsActive {
+		return nil, fmt.Errorf("api key %d is revoked", key.ID)
+	}
+
+	return &accessKey{
+		branchID:      key.BranchID,
+		keyID:         key.ID,
+		hashedSecret:  key.APISecret,
+		signingSecret: key.APISigningSecret,
+		scopes:        key.Scopes,
+	}, nil
 }
 
 // GetHaciendaCredentials obtiene las credenciales de Hacienda. Devuelve las credenciales de Hacienda.
-func (s *StandardAuthStrategy) GetHaciendaCredentials(token string) (*models.HaciendaCredentials, error) {
+func (s *StandardAuthStrategy) GetHaciendaCredentials(ctx context.Context, token string) (*models.HaciendaCredentials, error) {
 	creds, err := s.cacheService.GetCredentials(token)
 	if err != nil {
-		logs.Error("Failed to get Hacienda credentials", map[string]interface{}{
-			"token": token,
+		logs.ErrorContext(ctx, "Failed to get Hacienda credentials", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, shared_error.NewFormattedGeneralServiceError(
@@ -160,9 +209,7 @@ func (s *StandardAuthStrategy) GetHaciendaCredentials(token string) (*models.Hac
 		)
 	}
 
-	logs.Info("Hacienda credentials retrieved successfully", map[string]interface{}{
-		"token": token,
-	})
+	logs.InfoContext(ctx, "Hacienda credentials retrieved successfully")
 
 	return creds, nil
 }
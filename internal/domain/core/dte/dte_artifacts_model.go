@@ -0,0 +1,9 @@
+package dte
+
+// DTEArtifacts representa los documentos originales de la transmisión de un DTE: el JWS firmado que se envió a
+// Hacienda y la respuesta de Hacienda. Los documentos transmitidos antes de su registro no poseen artefactos.
+type DTEArtifacts struct {
+	GenerationCode string  `json:"generation_code"`
+	SignedDocument *string `json:"signed_document,omitempty"`
+	MHResponse     *string `json:"mh_response,omitempty"`
+}
@@ -0,0 +1,17 @@
+package dte
+
+import "time"
+
+// DTEExportRecord representa un documento leído durante la exportación masiva de DTE
+type DTEExportRecord struct {
+	GenerationCode string
+	ControlNumber  string
+	DTEType        string
+	Status         string
+	Transmission   string
+	ReceptionStamp *string
+	BranchID       uint
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	JSONData       string
+}
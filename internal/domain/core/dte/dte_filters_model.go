@@ -2,8 +2,32 @@ package dte
 
 import "time"
 
+const (
+	// SortByDate ordena por la fecha de creación del documento
+	SortByDate = "date"
+	// SortByAmount ordena por el monto total del documento
+	SortByAmount = "amount"
+	// SortByControlNumber ordena por el número de control
+	SortByControlNumber = "control_number"
+	// SortByReceiverName ordena por el nombre del receptor
+	SortByReceiverName = "receiver_name"
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ValidSortFields contiene los campos por los que se puede ordenar el listado de DTE
+var ValidSortFields = map[string]bool{
+	SortByDate:          true,
+	SortByAmount:        true,
+	SortByControlNumber: true,
+	SortByReceiverName:  true,
+}
+
 type DTEFilters struct {
 	BranchID     uint       `query:"-"`
+	ClientID     uint       `query:"-"`
+	BranchIDs    []uint     `query:"-"`
 	IncludeAll   bool       `query:"all,omitempty"`
 	StartDate    *time.Time `query:"startDate,omitempty"`
 	EndDate      *time.Time `query:"endDate,omitempty"`
@@ -11,6 +35,21 @@ type DTEFilters struct {
 	Transmission string     `query:"transmission,omitempty"`
 	DTEType      string     `query:"type,omitempty"`
 
+	// Búsqueda sobre el contenido del DTE
+	ReceiverNIT     string   `query:"receiverNit,omitempty"`
+	ReceiverNRC     string   `query:"receiverNrc,omitempty"`
+	ReceiverName    string   `query:"receiverName,omitempty"`
+	ControlNumber   string   `query:"controlNumber,omitempty"`
+	ReceptionStamp  string   `query:"receptionStamp,omitempty"`
+	MinAmount       *float64 `query:"minAmount,omitempty"`
+	MaxAmount       *float64 `query:"maxAmount,omitempty"`
+	ItemCode        string   `query:"itemCode,omitempty"`
+	ItemDescription string   `query:"itemDescription,omitempty"`
+
+	// Ordenamiento
+	SortBy    string `query:"sortBy,omitempty"`
+	SortOrder string `query:"sortOrder,omitempty"`
+
 	// Paginación
 	Page     int `json:"page,omitempty"`
 	PageSize int `json:"page_size,omitempty"`
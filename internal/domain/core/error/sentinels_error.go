@@ -8,4 +8,6 @@ var (
 	ErrDontHaveBranchMatrix       = errors.New("don't have branch matrix, is required that the user have a branch matrix")
 	ErrMoreThanOneBranchMatrix    = errors.New("more than one branch matrix, is required that the user have only one branch matrix")
 	ErrBranchMatrixWithoutAddress = errors.New("for the branch matrix is required that have an address associated")
+	ErrBranchMatrixDeactivation   = errors.New("the branch matrix cannot be deactivated")
+	ErrBranchMatrixTypeChange     = errors.New("the establishment type of the branch matrix cannot be changed")
 )
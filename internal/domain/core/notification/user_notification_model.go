@@ -11,4 +11,7 @@ type UserNotification struct {
 	Message          string    `json:"message"`
 	DeliveryStatus   string    `json:"delivery_status"`
 	DeliveryAt       time.Time `json:"delivery_at,omitempty"`
+	Recipient        *string   `json:"recipient,omitempty"`
+	DocumentID       *string   `json:"document_id,omitempty"`
+	ErrorMessage     *string   `json:"error_message,omitempty"`
 }
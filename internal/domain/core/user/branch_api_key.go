@@ -0,0 +1,73 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+)
+
+// BranchAPIKey representa una llave de acceso adicional de una sucursal con scopes limitados, por ejemplo llaves de
+// solo lectura para integraciones contables. Las llaves principales de la sucursal conservan todos los scopes
+type BranchAPIKey struct {
+	ID        uint   `json:"id"`
+	BranchID  uint   `json:"branch_id"`
+	Name      string `json:"name"`
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"-"`
+	// APISigningSecret API secret cifrado con el vault, con él se verifican las solicitudes firmadas con HMAC
+	APISigningSecret *string  `json:"-"`
+	Scopes           []string `json:"scopes"`
+	// ClientCertSubject subject o SAN del certificado de cliente con el que se autentica la llave por mTLS
+	ClientCertSubject *string   `json:"client_cert_subject,omitempty"`
+	IsActive          bool      `json:"is_active"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// BranchAPIKeyCredentialsResponse contiene una llave de acceso recién generada, el API secret solo se retorna una vez
+type BranchAPIKeyCredentialsResponse struct {
+	ID        uint     `json:"id"`
+	BranchID  uint     `json:"branch_id"`
+	Name      string   `json:"name"`
+	APIKey    string   `json:"api_key"`
+	APISecret string   `json:"api_secret"`
+	Scopes    []string `json:"scopes"`
+	// ClientCertSubject subject o SAN del certificado de cliente asignado a la llave
+	ClientCertSubject *string `json:"client_cert_subject,omitempty"`
+}
+
+// Validate valida el nombre, los scopes y el certificado de cliente de la llave, los scopes repetidos se eliminan
+func (k *BranchAPIKey) Validate() error {
+	k.Name = strings.TrimSpace(k.Name)
+	if k.Name == "" {
+		return dte_errors.NewValidationError("RequiredField", "name")
+	}
+	if len(k.Name) > 100 {
+		return dte_errors.NewValidationError("InvalidLength", "name", "1 to 100", fmt.Sprint(len(k.Name)))
+	}
+
+	if len(k.Scopes) == 0 {
+		return dte_errors.NewValidationError("RequiredField", "scopes")
+	}
+
+	seen := make(map[string]bool, len(k.Scopes))
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, scope := range k.Scopes {
+		if !constants.ValidScopes[scope] {
+			return dte_errors.NewValidationError("InvalidScope", scope, strings.Join(constants.AllScopes, ", "))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	k.Scopes = scopes
+
+	if k.ClientCertSubject != nil && (*k.ClientCertSubject == "" || len(*k.ClientCertSubject) > 255) {
+		return dte_errors.NewValidationError("InvalidLength", "client_cert_subject", "1 to 255", fmt.Sprint(len(*k.ClientCertSubject)))
+	}
+
+	return nil
+}
@@ -15,11 +15,14 @@ type BranchOffice struct {
 	Email               *string  `json:"email,omitempty"`
 	APIKey              string   `json:"api_key"`
 	APISecret           string   `json:"api_secret"`
+	APISigningSecret    *string  `json:"-"`
 	Phone               *string  `json:"phone,omitempty"`
 	EstablishmentType   string   `json:"establishment_type"`
 	POSCode             *string  `json:"pos_code,omitempty"`
 	POSCodeMH           *string  `json:"pos_code_mh,omitempty"`
 	IsActive            bool     `json:"is_active"`
+	RateLimitPerMinute  *int     `json:"rate_limit_per_minute,omitempty"`
+	ClientCertSubject   *string  `json:"client_cert_subject,omitempty"`
 	Address             *Address `json:"address,omitempty"`
 	User                *User    `json:"user,omitempty"`
 }
@@ -49,6 +52,14 @@ func (b *BranchOffice) Validate() error {
 		}
 	}
 
+	if b.RateLimitPerMinute != nil && *b.RateLimitPerMinute < 0 {
+		return dte_errors.NewValidationError("InvalidValue", *b.RateLimitPerMinute, "greater than or equal to 0", "rate_limit_per_minute")
+	}
+
+	if b.ClientCertSubject != nil && (*b.ClientCertSubject == "" || len(*b.ClientCertSubject) > 255) {
+		return dte_errors.NewValidationError("InvalidLength", "client_cert_subject", "1 to 255", fmt.Sprint(len(*b.ClientCertSubject)))
+	}
+
 	if b.Email != nil {
 		if _, err := base.NewEmail(*b.Email); err != nil {
 			return err
@@ -73,3 +84,22 @@ func (b *BranchOffice) Validate() error {
 
 	return nil
 }
+
+// ToResponse convierte la sucursal en su representación de respuesta sin el API secret
+func (b *BranchOffice) ToResponse() BranchOfficeResponse {
+	return BranchOfficeResponse{
+		ID:                  b.ID,
+		EstablishmentType:   b.EstablishmentType,
+		EstablishmentCode:   b.EstablishmentCode,
+		EstablishmentCodeMH: b.EstablishmentCodeMH,
+		POSCode:             b.POSCode,
+		POSCodeMH:           b.POSCodeMH,
+		Email:               b.Email,
+		Phone:               b.Phone,
+		APIKey:              b.APIKey,
+		IsActive:            b.IsActive,
+		RateLimitPerMinute:  b.RateLimitPerMinute,
+		ClientCertSubject:   b.ClientCertSubject,
+		Address:             b.Address,
+	}
+}
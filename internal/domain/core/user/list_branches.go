@@ -7,3 +7,27 @@ type ListBranchesResponse struct {
 	APIKey            string  `json:"api_key"`
 	APISecret         string  `json:"api_secret"`
 }
+
+// BranchOfficeResponse representa una sucursal del usuario, el API secret solo se retorna al crear o rotar las llaves
+type BranchOfficeResponse struct {
+	ID                  uint     `json:"id"`
+	EstablishmentType   string   `json:"establishment_type"`
+	EstablishmentCode   *string  `json:"establishment_code,omitempty"`
+	EstablishmentCodeMH *string  `json:"establishment_code_mh,omitempty"`
+	POSCode             *string  `json:"pos_code,omitempty"`
+	POSCodeMH           *string  `json:"pos_code_mh,omitempty"`
+	Email               *string  `json:"email,omitempty"`
+	Phone               *string  `json:"phone,omitempty"`
+	APIKey              string   `json:"api_key"`
+	IsActive            bool     `json:"is_active"`
+	RateLimitPerMinute  *int     `json:"rate_limit_per_minute,omitempty"`
+	ClientCertSubject   *string  `json:"client_cert_subject,omitempty"`
+	Address             *Address `json:"address,omitempty"`
+}
+
+// BranchCredentialsResponse representa las llaves de acceso de una sucursal
+type BranchCredentialsResponse struct {
+	ID        uint   `json:"id"`
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+}
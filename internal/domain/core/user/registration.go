@@ -0,0 +1,45 @@
+package user
+
+import "time"
+
+const (
+	RegistrationPending  = "PENDING"  // Registro pendiente de aprobación por un administrador
+	RegistrationApproved = "APPROVED" // Registro aprobado, el usuario puede iniciar sesión
+)
+
+// RegistrationResponse representa la respuesta de una solicitud de registro
+type RegistrationResponse struct {
+	NIT      string `json:"nit"`
+	Status   string `json:"status"`
+	Branches int    `json:"branches"`
+}
+
+// RegistrationSummary representa una solicitud de registro pendiente de revisión
+type RegistrationSummary struct {
+	ID             uint      `json:"id"`
+	NIT            string    `json:"nit"`
+	NRC            string    `json:"nrc"`
+	Business       string    `json:"business_name"`
+	CommercialName string    `json:"commercial_name"`
+	Email          string    `json:"email"`
+	Phone          string    `json:"phone"`
+	Status         string    `json:"status"`
+	Branches       int       `json:"branches"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ToRegistrationSummary convierte el usuario en el resumen de su solicitud de registro
+func (u *User) ToRegistrationSummary() RegistrationSummary {
+	return RegistrationSummary{
+		ID:             u.ID,
+		NIT:            u.NIT,
+		NRC:            u.NRC,
+		Business:       u.Business,
+		CommercialName: u.CommercialName,
+		Email:          u.Email,
+		Phone:          u.Phone,
+		Status:         u.RegistrationStatus,
+		Branches:       len(u.BranchOffices),
+		CreatedAt:      u.CreatedAt,
+	}
+}
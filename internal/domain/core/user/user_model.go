@@ -2,11 +2,13 @@ package user
 
 import (
 	"encoding/json"
+	authConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
 	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/error"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/value_objects/base"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/value_objects/identification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/i18n"
 	"time"
 )
 
@@ -25,6 +27,8 @@ type User struct {
 	Phone                string    `json:"phone"`
 	YearInDTE            bool      `json:"year_in_dte"`
 	TokenLifetime        int       `json:"token_lifetime"`
+	Language             string    `json:"language,omitempty"` // Idioma de las respuestas cuando la solicitud no incluye Accept-Language
+	RegistrationStatus   string    `json:"-"`
 	CreatedAt            time.Time `json:"-"`
 	UpdatedAt            time.Time `json:"-"`
 
@@ -58,6 +62,14 @@ func (u *User) Validate() error {
 		return dte_errors.NewValidationError("RequiredField", "auth_type")
 	}
 
+	if !authConstants.ValidUserAuthTypes[u.AuthType] {
+		return dte_errors.NewValidationError("InvalidFormat", "auth_type", "'STANDARD', 'HMAC', 'MTLS'", u.AuthType)
+	}
+
+	if u.Language != "" && !i18n.IsSupported(u.Language) {
+		return dte_errors.NewValidationError("InvalidFormat", "language", "'es', 'en'", u.Language)
+	}
+
 	if u.PasswordPri == "" {
 		return dte_errors.NewValidationError("RequiredField", "password_pri")
 	}
@@ -163,6 +175,23 @@ func (u *User) SetBranchesKeysAndSecrets(keys []string, secrets []string) {
 	}
 }
 
+// SetBranchesSigningSecrets asigna a las sucursales del usuario sus API secrets cifrados, con los que se verifican las
+// solicitudes firmadas con HMAC
+func (u *User) SetBranchesSigningSecrets(signingSecrets []string) {
+	for i := range u.BranchOffices {
+		u.BranchOffices[i].APISigningSecret = &signingSecrets[i]
+	}
+}
+
+// SetBranchesKeys asigna las llaves a las sucursales de un registro pendiente, los secretos se generan al aprobarlo
+func (u *User) SetBranchesKeys(keys []string) {
+	for i := range u.BranchOffices {
+		u.BranchOffices[i].APIKey = keys[i]
+		u.BranchOffices[i].APISecret = ""
+		u.BranchOffices[i].IsActive = true
+	}
+}
+
 func (u *User) ToStringJSON() string {
 	jsonUser, _ := json.Marshal(u)
 	return string(jsonUser)
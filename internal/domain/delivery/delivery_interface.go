@@ -0,0 +1,36 @@
+package delivery
+
+import (
+	"context"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+)
+
+// MailTransport es una interfaz que define el medio por el cual se envían los correos electrónicos
+type MailTransport interface {
+	// Send envía un correo electrónico a sus destinatarios
+	Send(ctx context.Context, message *models.EmailMessage) error
+	// Name retorna el nombre del transporte, se utiliza para el registro de los envíos
+	Name() string
+}
+
+// DeliveryManager es una interfaz que define los métodos para la entrega de DTE a los receptores
+type DeliveryManager interface {
+	// DeliverDTE envía el JSON y la representación gráfica de un DTE, si no se indican destinatarios se utiliza el correo del receptor
+	DeliverDTE(ctx context.Context, branchID uint, generationCode string, recipients []string) (*models.DeliveryResult, error)
+	// DeliverDTEAsync programa el envío automático de un DTE en segundo plano, solo si el envío automático está habilitado.
+	// El envío continúa la traza de ctx pero no depende de su cancelación
+	DeliverDTEAsync(ctx context.Context, branchID uint, generationCode string)
+	// GetDeliveries obtiene el historial de envíos de un DTE
+	GetDeliveries(ctx context.Context, branchID uint, generationCode string) ([]models.Delivery, error)
+}
+
+// DeliveryRepositoryPort es una interfaz que define los métodos del repositorio de envíos de DTE
+type DeliveryRepositoryPort interface {
+	// Create registra un envío pendiente junto con su evento de dominio y notificación de usuario
+	Create(ctx context.Context, delivery *models.Delivery) error
+	// UpdateStatus actualiza el estado de un envío
+	UpdateStatus(ctx context.Context, id uint, status string, errorMessage *string) error
+	// GetByDocument obtiene los envíos realizados para un DTE
+	GetByDocument(ctx context.Context, branchID uint, generationCode string) ([]models.Delivery, error)
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	// DeliveryPending indica que el envío fue registrado pero aún no se ha completado
+	DeliveryPending = "PENDING"
+	// DeliverySent indica que el transporte aceptó el correo
+	DeliverySent = "SENT"
+	// DeliveryFailed indica que el transporte rechazó el correo o no fue posible construirlo
+	DeliveryFailed = "FAILED"
+
+	// NotificationTypeEmail es el tipo de notificación con el que se registran los envíos en user_notifications
+	NotificationTypeEmail = "EMAIL"
+	// EventTypeDTEDelivery es el tipo de evento de dominio que origina cada envío
+	EventTypeDTEDelivery = "DTE_DELIVERY"
+)
+
+// Delivery representa el envío de un DTE a un destinatario
+type Delivery struct {
+	ID             uint      `json:"id"`
+	BranchID       uint      `json:"-"`
+	GenerationCode string    `json:"generation_code"`
+	DTEType        string    `json:"dte_type"`
+	Recipient      string    `json:"recipient"`
+	Subject        string    `json:"subject"`
+	Status         string    `json:"status"`
+	ErrorMessage   *string   `json:"error_message,omitempty"`
+	DeliveryAt     time.Time `json:"delivery_at"`
+}
+
+// DeliveryResult representa el resultado de un envío a uno o varios destinatarios
+type DeliveryResult struct {
+	GenerationCode string     `json:"generation_code"`
+	Sent           int        `json:"sent"`
+	Failed         int        `json:"failed"`
+	Deliveries     []Delivery `json:"deliveries"`
+}
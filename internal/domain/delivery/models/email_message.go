@@ -0,0 +1,19 @@
+package models
+
+// EmailMessage representa un correo electrónico listo para ser enviado por un transporte
+type EmailMessage struct {
+	From        string
+	FromName    string
+	To          []string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Attachment representa un archivo adjunto de un correo electrónico
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
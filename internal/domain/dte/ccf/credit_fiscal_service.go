@@ -39,12 +39,12 @@ func (s *creditFiscalService) Create(ctx context.Context, input interface{}, bra
 	}
 
 	if err := s.validate(creditFiscalDocument); err != nil {
-		logs.Error("Failed to validate credit fiscal document basic validation", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Failed to validate credit fiscal document basic validation", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
 	if err := buisnessValidator.ValidateDTEDocument(creditFiscalDocument); err != nil {
-		logs.Error("Failed to validate credit fiscal document generic validations", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Failed to validate credit fiscal document generic validations", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
@@ -7,3 +7,17 @@ const (
 	StateOpen                  // Representa el estado abierto del circuit breaker
 	StateHalfOpen              // Representa el estado semi-abierto del circuit breaker
 )
+
+// String retorna el nombre del estado del circuit breaker
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
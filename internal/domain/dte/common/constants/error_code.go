@@ -8,7 +8,12 @@ import (
 
 // GetErrorMessage obtiene el mensaje de error según el idioma configurado
 func GetErrorMessage(errorCode string, params ...interface{}) string {
-	message := i18n.Translate(fmt.Sprintf("validation_errors.%s", errorCode), params...)
+	return GetErrorMessageIn(i18n.DefaultLanguage(), errorCode, params...)
+}
+
+// GetErrorMessageIn obtiene el mensaje de error en el idioma indicado, utilizado para responder en el idioma de la solicitud
+func GetErrorMessageIn(lang, errorCode string, params ...interface{}) string {
+	message := i18n.TranslateIn(lang, fmt.Sprintf("validation_errors.%s", errorCode), params...)
 
 	// Si el modo debug está activado, se muestra el código de error
 	if config.Server.Debug {
@@ -1,6 +1,10 @@
 package dte_errors
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/i18n"
+)
 
 // CompositeError representa un error compuesto que contiene varios errores
 type CompositeError struct {
@@ -21,3 +25,12 @@ func (e *CompositeError) Error() string {
 	}
 	return strings.Join(messages, "; ")
 }
+
+// Localize Retorna los mensajes de los errores en el idioma indicado
+func (e *CompositeError) Localize(lang string) string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, i18n.LocalizeError(err, lang))
+	}
+	return strings.Join(messages, "; ")
+}
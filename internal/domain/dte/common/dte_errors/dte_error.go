@@ -13,7 +13,8 @@ type DTEError struct {
 	BusinessErrors   []*DTEError // Errores de reglas de negocio DTE
 	ErrorType        string
 	Message          string
-	Code             string // Campo explícito para el código de error
+	Code             string        // Campo explícito para el código de error
+	Params           []interface{} // Parámetros del mensaje, permiten traducirlo al idioma de la solicitud
 }
 
 // getDTEErrorMessage Obtiene el mensaje de error DTE con los parámetros enviados
@@ -28,6 +29,7 @@ func NewDTEErrorSimple(errorType string, params ...interface{}) *DTEError {
 		ErrorType:        errorType,
 		Message:          getDTEErrorMessage(errorType, params...),
 		Code:             strings.ToUpper(errorType),
+		Params:           params,
 	}
 }
 
@@ -59,19 +61,45 @@ func (e *DTEError) Error() string {
 	return e.Message
 }
 
+// Localize Retorna el mensaje del error DTE en el idioma indicado
+func (e *DTEError) Localize(lang string) string {
+	if e == nil {
+		return "Unknown DTE error"
+	}
+
+	if len(e.BusinessErrors) > 0 {
+		var messages []string
+		for _, err := range e.BusinessErrors {
+			messages = append(messages, err.Localize(lang))
+		}
+		return strings.Join(messages, "; ")
+	}
+
+	if e.ErrorType == "" {
+		return e.Message
+	}
+
+	return constants.GetErrorMessageIn(lang, e.ErrorType, e.Params...)
+}
+
 // GetValidationErrorsString Obtiene los errores de validación asociados al error DTE en caso de existir
 func (e *DTEError) GetValidationErrorsString() []string {
+	return e.GetValidationErrorsStringIn(i18n.DefaultLanguage())
+}
+
+// GetValidationErrorsStringIn Obtiene los errores de validación asociados al error DTE en el idioma indicado
+func (e *DTEError) GetValidationErrorsStringIn(lang string) []string {
 	var messages []string
 	for _, err := range e.ValidationErrors {
 		if err != nil {
-			messages = append(messages, err.Error())
+			messages = append(messages, i18n.LocalizeError(err, lang))
 		}
 	}
 
 	if e.BusinessErrors != nil {
 		for _, err := range e.BusinessErrors {
 			if err != nil {
-				messages = append(messages, err.Message)
+				messages = append(messages, err.Localize(lang))
 			}
 		}
 	}
@@ -94,20 +122,25 @@ func (e *DTEError) GetCode() string {
 
 // GetMessage Obtiene el mensaje traducido del error
 func (e *DTEError) GetMessage() string {
+	return e.GetMessageIn(i18n.DefaultLanguage())
+}
+
+// GetMessageIn Obtiene el mensaje del error traducido al idioma indicado
+func (e *DTEError) GetMessageIn(lang string) string {
 	if e == nil {
 		return "Unknown DTE error"
 	}
 
 	if len(e.ValidationErrors) > 0 || len(e.BusinessErrors) > 0 {
-		return i18n.Translate("service_errors.FailedToCreateDTE")
+		return i18n.TranslateIn(lang, "service_errors.FailedToCreateDTE")
 	}
 
 	key := fmt.Sprintf("service_errors.%s", e.ErrorType)
-	translated := i18n.Translate(key)
+	translated := i18n.TranslateIn(lang, key)
 
 	// Si no hay traducción específica, usa el mensaje original
 	if translated == key {
-		return e.Message
+		return e.Localize(lang)
 	}
 
 	return translated
@@ -3,21 +3,24 @@ package dte_errors
 import (
 	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/i18n"
 )
 
 type ValidationError struct {
 	ErrorType string
 	Message   string
+	Params    []interface{} // Parámetros del mensaje, permiten traducirlo al idioma de la solicitud
+	cause     error
 }
 
 // NewValidationError Crea un nuevo error de validación con el tipo de error y los parámetros enviados
 func NewValidationError(errorType string, params ...interface{}) *ValidationError {
 	message := constants.GetErrorMessage(errorType, params...)
-	return &ValidationError{ErrorType: errorType, Message: message}
+	return &ValidationError{ErrorType: errorType, Message: message, Params: params}
 }
 
 func NewFormattedValidationError(err error) *ValidationError {
-	return &ValidationError{ErrorType: "", Message: err.Error()}
+	return &ValidationError{ErrorType: "", Message: err.Error(), cause: err}
 }
 
 // Error Implementación de la interfaz error para el error de validación
@@ -25,6 +28,18 @@ func (v *ValidationError) Error() string {
 	return fmt.Sprintf("%s", v.Message)
 }
 
+// Localize Retorna el mensaje del error de validación en el idioma indicado
+func (v *ValidationError) Localize(lang string) string {
+	if v.ErrorType == "" {
+		if v.cause != nil {
+			return i18n.LocalizeError(v.cause, lang)
+		}
+		return v.Message
+	}
+
+	return constants.GetErrorMessageIn(lang, v.ErrorType, v.Params...)
+}
+
 // GetType Retorna el tipo de error de validación
 func (v *ValidationError) GetType() string {
 	if v == nil {
@@ -16,4 +16,6 @@ type ContingencyRepositoryPort interface {
 	UpdateBatch(ctx context.Context, ids []string, observations []string, stamps map[string]string, batchID string, mhBatchID string, status string) error
 	// GetFirstContingencyTimestamp obtiene la fecha de la primera contingencia de un sistema
 	GetFirstContingencyTimestamp(ctx context.Context, branchID uint) (*time.Time, error)
+	// CountPending obtiene la cantidad de documentos en estado PENDING en la cola de contingencia
+	CountPending(ctx context.Context) (int64, error)
 }
@@ -2,23 +2,25 @@ package contingency
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"sort"
 	"strings"
 
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	appPorts "github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	auditModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
-	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
 	batch "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter"
 	transmitterModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
@@ -29,13 +31,14 @@ type ContingencyService struct {
 	dteManager        dte_documents.DTEManager
 	repo              ContingencyRepositoryPort
 	haciendaAuth      appPorts.HaciendaAuthManager
-	cache             ports.CacheManager
-	tokenService      ports.TokenManager
+	vault             vault.CredentialVault
 	signer            appPorts.SignerManager
 	batchTransmitter  batch.BatchTransmitterPort
 	contingencyEvents ContingencyEventSender
 	timeProvider      ports.TimeProvider
 	config            *transmitterModels.TransmissionConfig
+	audit             audit.AuditManager
+	webhooks          webhook.WebhookManager
 }
 
 func NewContingencyManager(
@@ -43,26 +46,28 @@ func NewContingencyManager(
 	dteManager dte_documents.DTEManager,
 	repo ContingencyRepositoryPort,
 	haciendaAuth appPorts.HaciendaAuthManager,
-	cache ports.CacheManager,
-	tokenService ports.TokenManager,
+	credentialVault vault.CredentialVault,
 	signer appPorts.SignerManager,
 	batchTransmitter batch.BatchTransmitterPort,
 	contingencyEvents ContingencyEventSender,
 	timeProvider ports.TimeProvider,
 	config *transmitterModels.TransmissionConfig,
+	auditManager audit.AuditManager,
+	webhookManager webhook.WebhookManager,
 ) ContingencyManager {
 	return &ContingencyService{
 		authManager:       authManager,
 		dteManager:        dteManager,
 		repo:              repo,
 		haciendaAuth:      haciendaAuth,
-		cache:             cache,
-		tokenService:      tokenService,
+		vault:             credentialVault,
 		signer:            signer,
 		batchTransmitter:  batchTransmitter,
 		contingencyEvents: contingencyEvents,
 		config:            config,
 		timeProvider:      timeProvider,
+		audit:             auditManager,
+		webhooks:          webhookManager,
 	}
 }
 
@@ -74,7 +79,7 @@ func (s *ContingencyService) StoreDocumentInContingency(ctx context.Context, doc
 	// 2. Extraer información general del documento
 	dteInfo, err := utils.ExtractAuxiliarIdentification(document)
 	if err != nil {
-		logs.Error("Failed to extract general DTE info", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to extract general DTE info", map[string]interface{}{
 			"error": err.Error(),
 			"type":  dteType,
 		})
@@ -85,7 +90,7 @@ func (s *ContingencyService) StoreDocumentInContingency(ctx context.Context, doc
 	err = s.dteManager.Create(ctx, document, constants.TransmissionContingency,
 		constants.DocumentPending, nil)
 	if err != nil {
-		logs.Error("Failed to store DTE", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to store DTE", map[string]interface{}{
 			"error": err.Error(),
 			"type":  dteType,
 		})
@@ -102,19 +107,46 @@ func (s *ContingencyService) StoreDocumentInContingency(ctx context.Context, doc
 
 	// 5. Almacenar el documento en contingencia
 	if err = s.repo.Create(ctx, contingencyDoc); err != nil {
-		logs.Error("Failed to store contingency document", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to store contingency document", map[string]interface{}{
 			"error": err.Error(),
 			"id":    contingencyDoc.ID,
 		})
 		return shared_error.NewGeneralServiceError("ContingencyService", "StoreDocumentInContingency", "failed to store contingency document", err)
 	}
 
-	logs.Info("Document stored in contingency", map[string]interface{}{
+	logs.InfoContext(ctx, "Document stored in contingency", map[string]interface{}{
 		"id":              contingencyDoc.ID,
 		"type":            dteType,
 		"contingencyType": contingencyType,
 	})
 
+	// 6. Registrar el almacenamiento en la auditoría
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventContingencyStored,
+		UserID:    claims.ClientID,
+		BranchID:  claims.BranchID,
+		Payload: map[string]interface{}{
+			"generation_code":  dteInfo.Identification.GenerationCode,
+			"control_number":   dteInfo.Identification.ControlNumber,
+			"dte_type":         dteType,
+			"contingency_type": contingencyType,
+			"reason":           reason,
+		},
+	})
+
+	// 7. Notificar el almacenamiento a las suscripciones de webhooks
+	s.webhooks.Emit(ctx, &webhookModels.Event{
+		Type:     webhookModels.EventDTEContingencyStored,
+		BranchID: claims.BranchID,
+		Data: map[string]interface{}{
+			"generation_code":  dteInfo.Identification.GenerationCode,
+			"control_number":   dteInfo.Identification.ControlNumber,
+			"dte_type":         dteType,
+			"contingency_type": contingencyType,
+			"reason":           reason,
+		},
+	})
+
 	return nil
 }
 
@@ -126,7 +158,7 @@ func (s *ContingencyService) RetransmitPendingDocuments(ctx context.Context) err
 	}
 
 	if len(pendingDocs) == 0 {
-		logs.Info("No pending documents found")
+		logs.InfoContext(ctx, "No pending documents found")
 		return nil
 	}
 
@@ -136,7 +168,7 @@ func (s *ContingencyService) RetransmitPendingDocuments(ctx context.Context) err
 	for systemNIT, typeGroups := range docsBySystemAndType {
 		// Primero enviar el evento de contingencia para todos los documentos del sistema
 		if err := s.contingencyEvents.PrepareAndSendContingencyEvent(ctx, pendingDocs); err != nil {
-			logs.Error("Failed to send contingency event", map[string]interface{}{
+			logs.ErrorContext(ctx, "Failed to send contingency event", map[string]interface{}{
 				"error":     err.Error(),
 				"systemNIT": systemNIT,
 			})
@@ -146,7 +178,7 @@ func (s *ContingencyService) RetransmitPendingDocuments(ctx context.Context) err
 		// Luego procesar cada grupo de documentos por tipo
 		for dteType, docs := range typeGroups {
 			if err := s.processSystemDocumentsByType(ctx, systemNIT, dteType, docs); err != nil {
-				logs.Error("Failed to process system documents", map[string]interface{}{
+				logs.ErrorContext(ctx, "Failed to process system documents", map[string]interface{}{
 					"error":     err.Error(),
 					"systemNIT": systemNIT,
 					"dteType":   dteType,
@@ -162,25 +194,22 @@ func (s *ContingencyService) RetransmitPendingDocuments(ctx context.Context) err
 // processSystemDocumentsByType procesa documentos de un tipo específico para un sistema
 func (s *ContingencyService) processSystemDocumentsByType(ctx context.Context, systemNIT string, dteType string, docs []dte.ContingencyDocument) error {
 	if len(docs) == 0 {
-		logs.Warn("No documents to process")
+		logs.WarnContext(ctx, "No documents to process")
 		return nil
 	}
-	// 1. Obtener el cliente y generar token
+	// 1. Obtener el cliente
 	branchID := docs[0].BranchID
 	client, err := s.authManager.GetBranchByBranchID(ctx, branchID)
 	if err != nil {
 		return shared_error.NewGeneralServiceError("ContingencyService", "processSystemDocumentsByType", "failed to get branch by ID", err)
 	}
-	token, err := s.generateMatchingToken(client)
-	if err != nil {
-		return shared_error.NewGeneralServiceError("ContingencyService", "processSystemDocumentsByType", "failed to generate matching token", err)
-	}
 
-	// 2. Obtener credenciales
-	encryptedCreds, err := s.cache.GetCredentials(token)
+	// 2. Obtener credenciales del vault, el token de Hacienda se almacena en caché con la llave del usuario
+	creds, err := s.vault.Get(ctx, client.User.ID)
 	if err != nil {
 		return shared_error.NewGeneralServiceError("ContingencyService", "processSystemDocumentsByType", "failed to get credentials", err)
 	}
+	token := vault.HaciendaTokenKey(client.User.ID)
 
 	// 3. Procesar documentos en lotes de máximo 100
 	for i := 0; i < len(docs); i += s.config.GetBatchSize() {
@@ -198,7 +227,7 @@ func (s *ContingencyService) processSystemDocumentsByType(ctx context.Context, s
 		for _, doc := range batchDocs {
 			signedDoc, err := s.signer.SignDTE(ctx, []byte(doc.Document.JSONData), systemNIT)
 			if err != nil {
-				logs.Error("Failed to sign document", map[string]interface{}{
+				logs.ErrorContext(ctx, "Failed to sign document", map[string]interface{}{
 					"error": err.Error(),
 					"nit":   systemNIT,
 					"id":    doc.ID,
@@ -209,10 +238,18 @@ func (s *ContingencyService) processSystemDocumentsByType(ctx context.Context, s
 			docsMap[doc.Document.ID] = doc
 			docIds = append(docIds, doc.ID)
 			signedDocs = append(signedDocs, signedDoc)
+
+			// Guardar el JWS firmado que se transmite en el lote
+			if err = s.dteManager.SaveArtifacts(ctx, doc.Document.ID, &signedDoc, nil); err != nil {
+				logs.WarnContext(ctx, "Failed to save signed contingency document", map[string]interface{}{
+					"error": err.Error(),
+					"id":    doc.Document.ID,
+				})
+			}
 		}
 
 		if len(signedDocs) == 0 {
-			logs.Warn("No documents signed")
+			logs.WarnContext(ctx, "No documents signed")
 			continue
 		}
 
@@ -220,9 +257,9 @@ func (s *ContingencyService) processSystemDocumentsByType(ctx context.Context, s
 		batchID := strings.ToUpper(uuid.New().String())
 
 		// Transmitir el lote
-		response, haciendaToken, err := s.batchTransmitter.TransmitBatch(ctx, systemNIT, dteType, signedDocs, token, *encryptedCreds)
+		response, haciendaToken, err := s.batchTransmitter.TransmitBatch(ctx, systemNIT, dteType, signedDocs, token, *creds)
 		if err != nil {
-			logs.Error("Failed to transmit batch", map[string]interface{}{
+			logs.ErrorContext(ctx, "Failed to transmit batch", map[string]interface{}{
 				"error":    err.Error(),
 				"batchId":  batchID,
 				"dteType":  dteType,
@@ -231,15 +268,47 @@ func (s *ContingencyService) processSystemDocumentsByType(ctx context.Context, s
 			continue
 		}
 
+		// Notificar la retransmisión del lote a las suscripciones de webhooks antes de conocer su resultado
+		generationCodes := make([]string, 0, len(docsMap))
+		for generationCode := range docsMap {
+			generationCodes = append(generationCodes, generationCode)
+		}
+		sort.Strings(generationCodes)
+
+		s.webhooks.Emit(ctx, &webhookModels.Event{
+			Type:     webhookModels.EventDTERetransmitted,
+			BranchID: branchID,
+			Data: map[string]interface{}{
+				"batch_id":   batchID,
+				"batch_code": response.BatchCode,
+				"dte_type":   dteType,
+				"documents":  generationCodes,
+			},
+		})
+
 		// Verificar el estado del lote y procesar resultados
 		err = s.batchTransmitter.VerifyContingencyBatchStatus(ctx, batchID, response.BatchCode, haciendaToken, branchID, docsMap)
 		if err != nil {
-			logs.Error("Failed to verify batch status", map[string]interface{}{
+			logs.ErrorContext(ctx, "Failed to verify batch status", map[string]interface{}{
 				"error":   err.Error(),
 				"batchId": batchID,
 				"dteType": dteType,
 			})
 		}
+
+		// Registrar la retransmisión del lote en la auditoría
+		s.audit.Record(ctx, &auditModels.AuditEntry{
+			EventType: auditModels.EventBatchRetransmitted,
+			UserID:    client.User.ID,
+			BranchID:  branchID,
+			Payload: map[string]interface{}{
+				"batch_id":   batchID,
+				"batch_code": response.BatchCode,
+				"dte_type":   dteType,
+				"documents":  generationCodes,
+				"verified":   err == nil,
+			},
+		})
 	}
 
 	return nil
@@ -256,39 +325,3 @@ func (s *ContingencyService) groupBySystemAndType(docs []dte.ContingencyDocument
 	}
 	return result
 }
-
-// generateMatchingToken genera un token para el cliente
-func (s *ContingencyService) generateMatchingToken(client *user.BranchOffice) (string, error) {
-	key := fmt.Sprintf("token:timestamps:%d", client.User.ID)
-	var timestamps struct {
-		IssuedAt  int64 `json:"IssuedAt"`
-		ExpiresAt int64 `json:"ExpiresAt"`
-	}
-
-	jsonTimestamps, err := s.cache.Get(key)
-	if err != nil {
-		return "", err
-	}
-
-	if err = json.Unmarshal([]byte(jsonTimestamps), &timestamps); err != nil {
-		return "", err
-	}
-
-	claims := &authModels.AuthClaims{
-		ClientID: client.User.ID,
-		BranchID: client.ID,
-		AuthType: client.User.AuthType,
-		NIT:      client.User.NIT,
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":        claims.ClientID,
-		"branch_sub": claims.BranchID,
-		"auth_type":  claims.AuthType,
-		"nit":        claims.NIT,
-		"exp":        timestamps.ExpiresAt,
-		"iat":        timestamps.IssuedAt,
-	})
-
-	return token.SignedString([]byte(s.tokenService.GetSecretKey()))
-}
@@ -37,7 +37,7 @@ func (s *creditNoteService) Create(ctx context.Context, input interface{}, branc
 	data := input.(*credit_note_models.CreditNoteInput)
 	// 1. Validar la existencia de documentos relacionados
 	if err := s.validateRelatedDocs(ctx, data, branchID); err != nil {
-		logs.Error("Failed to validate related documents", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Failed to validate related documents", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
@@ -51,13 +51,13 @@ func (s *creditNoteService) Create(ctx context.Context, input interface{}, branc
 
 	// 3. Validar el documento base
 	if err := s.validate(creditNote); err != nil {
-		logs.Error("Failed to validate credit note document basic validation", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Failed to validate credit note document basic validation", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
 	// 4. Validar contra reglas principales de negocio
 	if err := buisnessValidator.ValidateDTEDocument(creditNote); err != nil {
-		logs.Error("Failed to validate credit note document generic validations", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Failed to validate credit note document generic validations", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
@@ -65,7 +65,7 @@ func (s *creditNoteService) Create(ctx context.Context, input interface{}, branc
 	for _, doc := range creditNote.RelatedDocuments {
 		if doc.GetGenerationType() == constants.ElectronicDocument {
 			if err := s.dteManager.ValidateForCreditNote(ctx, branchID, doc.GetDocumentNumber(), creditNote); err != nil {
-				logs.Error("Failed to validate credit note document totals", map[string]interface{}{"error": err.Error()})
+				logs.ErrorContext(ctx, "Failed to validate credit note document totals", map[string]interface{}{"error": err.Error()})
 				return nil, err
 			}
 		}
@@ -26,4 +26,10 @@ type DTERepositoryPort interface {
 	GetSummaryStats(ctx context.Context, filters *dte.DTEFilters) (*dte.ListSummary, error)
 	// GetPagedDocuments obtiene una lista paginada de DTEs en la base de datos.
 	GetPagedDocuments(ctx context.Context, filters *dte.DTEFilters) ([]dte.DTEModelResponse, error)
+	// StreamDocuments recorre los DTEs que cumplen con los filtros en lotes, invocando fn por cada documento.
+	StreamDocuments(ctx context.Context, filters *dte.DTEFilters, batchSize int, fn func(*dte.DTEExportRecord) error) error
+	// SaveArtifacts registra el JWS firmado y la respuesta de Hacienda de un DTE.
+	SaveArtifacts(ctx context.Context, artifacts *dte.DTEArtifacts) error
+	// GetArtifacts obtiene el JWS firmado y la respuesta de Hacienda de un DTE.
+	GetArtifacts(ctx context.Context, generationCode string) (*dte.DTEArtifacts, error)
 }
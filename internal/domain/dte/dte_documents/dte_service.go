@@ -13,6 +13,9 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
+// ExportBatchSize cantidad de documentos leídos por consulta durante la exportación
+const ExportBatchSize = 500
+
 type DTEService struct {
 	repo DTERepositoryPort
 }
@@ -198,6 +201,40 @@ func (m *DTEService) GetAllDTEs(ctx context.Context, filters *dte.DTEFilters) (*
 	return response, nil
 }
 
+// ExportDTEs recorre todos los DTEs que cumplen con los filtros en lotes de tamaño fijo, de modo que el consumo
+// de memoria no depende de la cantidad de documentos exportados
+func (m *DTEService) ExportDTEs(ctx context.Context, filters *dte.DTEFilters, fn func(*dte.DTEExportRecord) error) error {
+	if err := m.repo.StreamDocuments(ctx, filters, ExportBatchSize, fn); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("DTEService", "ExportDTEs", err, "FailedToExportDTEs")
+	}
+
+	return nil
+}
+
+// SaveArtifacts registra los artefactos de la transmisión de un DTE
+func (m *DTEService) SaveArtifacts(ctx context.Context, generationCode string, signedDocument, mhResponse *string) error {
+	err := m.repo.SaveArtifacts(ctx, &dte.DTEArtifacts{
+		GenerationCode: generationCode,
+		SignedDocument: signedDocument,
+		MHResponse:     mhResponse,
+	})
+	if err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("DTEService", "SaveArtifacts", err, "FailedToSaveArtifacts", generationCode)
+	}
+
+	return nil
+}
+
+// GetArtifacts obtiene los artefactos de la transmisión de un DTE
+func (m *DTEService) GetArtifacts(ctx context.Context, generationCode string) (*dte.DTEArtifacts, error) {
+	artifacts, err := m.repo.GetArtifacts(ctx, generationCode)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("DTEService", "GetArtifacts", err, "FailedToGetArtifacts", generationCode)
+	}
+
+	return artifacts, nil
+}
+
 func (m *DTEService) setReceptionStampIntoAppendix(document interface{}, receptionStamp *string) error {
 	// 1. Determinar el tipo de DTE
 	dteType, err := m.determineDTEType(document)
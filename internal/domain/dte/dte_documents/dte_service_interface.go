@@ -26,4 +26,10 @@ type DTEManager interface {
 	GetByGenerationCodeConsult(ctx context.Context, branchID uint, generationCode string) (*dte.DTEResponse, error)
 	// GetAllDTEs obtiene todos los DTEs en la base de datos con filtros y paginación.
 	GetAllDTEs(ctx context.Context, filters *dte.DTEFilters) (*dte.DTEListResponse, error)
+	// ExportDTEs recorre todos los DTEs que cumplen con los filtros sin paginación, invocando fn por cada documento.
+	ExportDTEs(ctx context.Context, filters *dte.DTEFilters, fn func(*dte.DTEExportRecord) error) error
+	// SaveArtifacts registra el JWS firmado y la respuesta de Hacienda de un DTE, los valores nil no se reemplazan.
+	SaveArtifacts(ctx context.Context, generationCode string, signedDocument, mhResponse *string) error
+	// GetArtifacts obtiene el JWS firmado y la respuesta de Hacienda de un DTE.
+	GetArtifacts(ctx context.Context, generationCode string) (*dte.DTEArtifacts, error)
 }
@@ -108,7 +108,7 @@ func (s *invalidationService) handleControlBalance(ctx context.Context, branchID
 	for _, item := range extractor.Items {
 		relatedDoc, ok := relatedDocsMap[item.RelatedDoc]
 		if ok && relatedDoc.GenerationType == constants.ElectronicDocument {
-			logs.Info("Generating balance control transaction", map[string]interface{}{
+			logs.InfoContext(ctx, "Generating balance control transaction", map[string]interface{}{
 				"branchID":         branchID,
 				"originalCode":     originalCode,
 				"relatedDocument":  relatedDoc.DocumentNumber,
@@ -125,7 +125,7 @@ func (s *invalidationService) handleControlBalance(ctx context.Context, branchID
 				item.TaxedAmount,
 				item.ExemptAmount,
 				item.NotSubjectAmount); err != nil {
-				logs.Error("Error generating balance transaction", map[string]interface{}{
+				logs.ErrorContext(ctx, "Error generating balance transaction", map[string]interface{}{
 					"error": err.Error(),
 				})
 				return shared_error.NewFormattedGeneralServiceError("InvalidationService", "InvalidateDocument", "FailedToRecoverInvalidatedAmounts")
@@ -133,7 +133,7 @@ func (s *invalidationService) handleControlBalance(ctx context.Context, branchID
 		}
 	}
 
-	logs.Info("Balance control transaction generated successfully, the amounts was added to the balance", map[string]interface{}{
+	logs.InfoContext(ctx, "Balance control transaction generated successfully, the amounts was added to the balance", map[string]interface{}{
 		"branchID":     branchID,
 		"originalCode": originalCode,
 	})
@@ -7,4 +7,9 @@ type TransmitResult struct {
 	MessageCode    string
 	MessageDesc    string
 	Observations   []string
+
+	// SignedDocument JWS firmado que se transmitió a Hacienda
+	SignedDocument string
+	// Response respuesta original de Hacienda
+	Response *HaciendaResponse
 }
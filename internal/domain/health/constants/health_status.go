@@ -1,6 +1,16 @@
 package constants
 
 const (
-	StatusUp   = "UP"   // StatusUp significa que el servicio está funcionando correctamente y disponible.
-	StatusDown = "DOWN" // StatusDown significa que el servicio no está disponible o no está funcionando correctamente.
+	StatusUp       = "UP"       // StatusUp significa que el servicio está funcionando correctamente y disponible.
+	StatusDown     = "DOWN"     // StatusDown significa que el servicio no está disponible o no está funcionando correctamente.
+	StatusDegraded = "DEGRADED" // StatusDegraded significa que el servicio está disponible pero alguna dependencia no crítica falla.
+)
+
+// Componentes verificados por el servicio de salud
+const (
+	ComponentDatabase   = "database"
+	ComponentRedis      = "redis"
+	ComponentHacienda   = "hacienda"
+	ComponentFileSystem = "filesystem"
+	ComponentSigner     = "dte_signer"
 )
@@ -0,0 +1,17 @@
+package health
+
+import "fmt"
+
+// DependencyUnavailableError indica que una operación no se intentó porque la dependencia estaba caída en la última
+// verificación de salud
+type DependencyUnavailableError struct {
+	Component string
+}
+
+func NewDependencyUnavailableError(component string) *DependencyUnavailableError {
+	return &DependencyUnavailableError{Component: component}
+}
+
+func (e *DependencyUnavailableError) Error() string {
+	return fmt.Sprintf("dependency %s is unavailable, service running in degraded mode", e.Component)
+}
@@ -1,6 +1,8 @@
 package health
 
 import (
+	"context"
+
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/models"
 )
 
@@ -10,7 +12,15 @@ type ComponentChecker interface {
 	Name() string         // Name devuelve el nombre del componente
 }
 
+// DependencyMonitor es una interfaz que define los métodos para consultar la disponibilidad de las dependencias sin
+// esperar una verificación, se utiliza en la emisión para enviar los documentos a contingencia en modo degradado
+type DependencyMonitor interface {
+	IsAvailable(component string) bool // IsAvailable indica si el componente estaba disponible en la última verificación
+}
+
 // HealthManager es una interfaz que define los métodos para verificar el estado de todos los componentes
 type HealthManager interface {
-	CheckHealth() (*models.HealthStatus, error) // CheckHealth verifica el estado de todos los componentes y devuelve un modelo de models.HealthStatus
+	DependencyMonitor
+	CheckHealth(ctx context.Context) (*models.HealthStatus, error) // CheckHealth verifica el estado de todos los componentes y devuelve un modelo de models.HealthStatus
+	CheckLiveness() *models.HealthStatus                           // CheckLiveness indica si el proceso está en ejecución, no verifica dependencias
 }
@@ -2,11 +2,12 @@ package models
 
 type HealthStatus struct {
 	Status     string            `json:"status"`
-	Components map[string]Health `json:"components"`
+	Components map[string]Health `json:"components,omitempty"`
 	Timestamp  string            `json:"timestamp"`
 }
 
 type Health struct {
-	Status  string `json:"status"`
-	Details string `json:"details,omitempty"`
+	Status   string `json:"status"`
+	Details  string `json:"details,omitempty"`
+	Critical bool   `json:"critical"`
 }
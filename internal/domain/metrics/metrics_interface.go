@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"context"
+	"time"
+
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
 )
 
@@ -11,3 +14,21 @@ type MetricsManager interface {
 	// GetEndpointMetrics obtiene las métricas de un endpoint específico
 	GetEndpointMetrics(systemNIT, method, endpoint string) (*models.EndpointMetrics, error)
 }
+
+// BusinessMetricsManager calcula las métricas de negocio de los DTE emitidos por un contribuyente o una sucursal
+type BusinessMetricsManager interface {
+	// GetBusinessMetrics obtiene las cantidades y montos por tipo, estado y transmisión del período, la cola de
+	// contingencia pendiente y la tasa de rechazo con los códigos de Hacienda más frecuentes
+	GetBusinessMetrics(ctx context.Context, filters *models.BusinessMetricsFilters) (*models.Metrics, error)
+}
+
+// BusinessMetricsRepositoryPort define las consultas agregadas sobre los DTE almacenados
+type BusinessMetricsRepositoryPort interface {
+	// GetDTEGroups obtiene la cantidad y el monto de los DTE del período por sucursal, tipo, estado y transmisión
+	GetDTEGroups(ctx context.Context, filters *models.BusinessMetricsFilters) ([]models.DTEMetricGroup, error)
+	// GetPendingContingency obtiene por sucursal los documentos pendientes en la cola de contingencia, con la cantidad
+	// acumulada de los que tienen menos de una hora, un día y tres días de antigüedad respecto a now
+	GetPendingContingency(ctx context.Context, filters *models.BusinessMetricsFilters, now time.Time) ([]models.ContingencyAgeGroup, error)
+	// GetTopRejectionCodes obtiene los códigos de mensaje de Hacienda más frecuentes de los DTE rechazados del período
+	GetTopRejectionCodes(ctx context.Context, filters *models.BusinessMetricsFilters, limit int) ([]models.RejectionCode, error)
+}
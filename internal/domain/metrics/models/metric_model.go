@@ -2,17 +2,57 @@ package models
 
 import "time"
 
+// Períodos de las métricas de negocio
+const (
+	PeriodToday  = "today"
+	PeriodMonth  = "month"
+	PeriodCustom = "custom"
+)
+
+// Metrics métricas de negocio de un contribuyente o una sucursal en un período, los totales corresponden a todas las
+// sucursales consultadas y Branches contiene el detalle de cada una
 type Metrics struct {
+	Period        MetricsPeriod      `json:"period"`
 	ProcessedDTEs DTEMetrics         `json:"processed_dtes"`
 	Contingency   ContingencyMetrics `json:"contingency"`
+	Rejections    RejectionMetrics   `json:"rejections"`
+	Branches      []BranchMetrics    `json:"branches"`
 	Timestamp     string             `json:"timestamp"`
 }
 
+// MetricsPeriod período consultado, la fecha de fin es inclusiva
+type MetricsPeriod struct {
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
 type DTEMetrics struct {
-	Total     int64            `json:"total"`
-	LastMonth int64            `json:"last_month"`
-	ByType    map[string]int64 `json:"by_type"`   // Factura, CCF, etc.
-	ByStatus  map[string]int64 `json:"by_status"` // RECEIVED, INVALIDATED, REJECTED
+	Total          int64              `json:"total"`
+	TotalAmount    float64            `json:"total_amount"`
+	ByType         map[string]int64   `json:"by_type"`   // Factura, CCF, etc.
+	ByStatus       map[string]int64   `json:"by_status"` // RECEIVED, INVALIDATED, REJECTED
+	ByTransmission map[string]int64   `json:"by_transmission"`
+	AmountByType   map[string]float64 `json:"amount_by_type"`
+	Breakdown      []DTEMetricGroup   `json:"breakdown"`
+}
+
+// DTEMetricGroup cantidad y monto de los DTE de una sucursal agrupados por tipo, estado y transmisión
+type DTEMetricGroup struct {
+	BranchID     uint    `json:"-"`
+	DTEType      string  `json:"dte_type"`
+	Status       string  `json:"status"`
+	Transmission string  `json:"transmission"`
+	Count        int64   `json:"count"`
+	Amount       float64 `json:"amount"`
+}
+
+// BranchMetrics métricas de una sucursal en el período
+type BranchMetrics struct {
+	BranchID           uint       `json:"branch_id"`
+	ProcessedDTEs      DTEMetrics `json:"processed_dtes"`
+	PendingContingency int64      `json:"pending_contingency"`
+	RejectionRate      float64    `json:"rejection_rate"`
 }
 
 type RequestMetric struct {
@@ -21,8 +61,49 @@ type RequestMetric struct {
 	StatusCode int       `json:"status_code"`
 }
 
+// ContingencyMetrics documentos en contingencia pendientes de transmitir al momento de la consulta, sin importar el
+// período, agrupados por antigüedad
 type ContingencyMetrics struct {
-	PendingDTEs int64 `json:"pending_dtes"`
+	PendingDTEs      int64            `json:"pending_dtes"`
+	OldestPendingAt  *time.Time       `json:"oldest_pending_at,omitempty"`
+	OldestAgeSeconds int64            `json:"oldest_age_seconds"`
+	ByAge            map[string]int64 `json:"by_age"`
+}
+
+// ContingencyAgeGroup documentos pendientes de una sucursal por rango de antigüedad, es el resultado del agregado
+// sobre la cola de contingencia
+type ContingencyAgeGroup struct {
+	BranchID          uint
+	Pending           int64
+	LessThanHour      int64
+	LessThanDay       int64
+	LessThanThreeDays int64
+	OldestPendingAt   *time.Time
+}
+
+// RejectionMetrics documentos rechazados por Hacienda en el período, la tasa es la proporción de rechazados sobre el
+// total de documentos procesados
+type RejectionMetrics struct {
+	Total    int64           `json:"total"`
+	Rate     float64         `json:"rate"`
+	TopCodes []RejectionCode `json:"top_codes"`
+}
+
+// RejectionCode código de mensaje de Hacienda de los documentos rechazados
+type RejectionCode struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Count       int64  `json:"count"`
+}
+
+// BusinessMetricsFilters sucursales y período de las métricas de negocio, si BranchID es 0 se consultan todas las
+// sucursales del contribuyente ClientID
+type BusinessMetricsFilters struct {
+	ClientID  uint
+	BranchID  uint
+	Period    string
+	StartDate time.Time
+	EndDate   time.Time
 }
 
 type EndpointMetrics struct {
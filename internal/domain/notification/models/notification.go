@@ -0,0 +1,233 @@
+package models
+
+import (
+	"encoding/json"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+)
+
+// Alertas que genera el motor de notificaciones
+const (
+	// AlertContingencyEntered se genera cuando una sucursal almacena documentos en contingencia
+	AlertContingencyEntered = "contingency.entered"
+	// AlertDTERejected se genera cuando Hacienda rechaza un DTE, ya sea en línea o en un lote de contingencia
+	AlertDTERejected = "dte.rejected"
+	// AlertCertificateExpiring se genera cuando el certificado de firma del contribuyente está próximo a vencer
+	AlertCertificateExpiring = "certificate.expiring"
+	// AlertContingencyDeadline se genera cuando documentos en contingencia se acercan al plazo de 72 horas para su transmisión
+	AlertContingencyDeadline = "contingency.deadline"
+	// AlertSequenceGap se genera cuando Hacienda rechaza un número de control y queda un salto en la numeración
+	AlertSequenceGap = "sequence.gap"
+)
+
+// Tipos de entidad de los destinatarios
+const (
+	// EntityClient destinatarios del contribuyente, reciben las alertas de sus DTE y sucursales
+	EntityClient = "CLIENT"
+	// EntityAdmin destinatarios de la administración del sistema, reciben las alertas de todos los contribuyentes
+	EntityAdmin = "ADMIN"
+)
+
+// Canales de entrega, se registran como notification_type en user_notifications
+const (
+	// ChannelEmail envía la alerta por correo electrónico a cada destinatario
+	ChannelEmail = "ALERT_EMAIL"
+	// ChannelWebhook entrega la alerta a las suscripciones de webhooks del contribuyente al evento notification.created
+	ChannelWebhook = "ALERT_WEBHOOK"
+)
+
+const (
+	// NotificationPending indica que la notificación aún no se entrega o está programada para un nuevo intento
+	NotificationPending = "PENDING"
+	// NotificationSent indica que el canal aceptó la notificación
+	NotificationSent = "SENT"
+	// NotificationFailed indica que la notificación agotó sus intentos
+	NotificationFailed = "FAILED"
+
+	// MaxNotificationAttempts cantidad de intentos de una notificación antes de marcarla como fallida
+	MaxNotificationAttempts = 5
+	// BaseNotificationRetryDelay espera antes del primer reintento, se duplica en cada intento fallido
+	BaseNotificationRetryDelay = 5 * time.Minute
+
+	// CertificateExpiryWarning anticipación con la que se avisa el vencimiento del certificado de firma
+	CertificateExpiryWarning = 30 * 24 * time.Hour
+	// ContingencyDeadline plazo de Hacienda para transmitir un documento emitido en contingencia
+	ContingencyDeadline = 72 * time.Hour
+	// ContingencyDeadlineWarning anticipación con la que se avisa el vencimiento del plazo de contingencia
+	ContingencyDeadlineWarning = 24 * time.Hour
+)
+
+// Rule define los destinatarios y canales de una alerta. Cooldown evita repetir la alerta del mismo sujeto
+// (sucursal, documento, contribuyente) dentro del periodo indicado
+type Rule struct {
+	Alert       string
+	EntityTypes []string
+	Channels    []string
+	Cooldown    time.Duration
+	Subject     string
+}
+
+// Rules reglas del motor de notificaciones por tipo de alerta
+var Rules = map[string]Rule{
+	AlertContingencyEntered: {
+		Alert:       AlertContingencyEntered,
+		EntityTypes: []string{EntityClient, EntityAdmin},
+		Channels:    []string{ChannelEmail, ChannelWebhook},
+		Cooldown:    time.Hour,
+		Subject:     "Sucursal en contingencia",
+	},
+	AlertDTERejected: {
+		Alert:       AlertDTERejected,
+		EntityTypes: []string{EntityClient},
+		Channels:    []string{ChannelEmail, ChannelWebhook},
+		Cooldown:    24 * time.Hour,
+		Subject:     "Documento rechazado por Hacienda",
+	},
+	AlertCertificateExpiring: {
+		Alert:       AlertCertificateExpiring,
+		EntityTypes: []string{EntityClient, EntityAdmin},
+		Channels:    []string{ChannelEmail, ChannelWebhook},
+		Cooldown:    24 * time.Hour,
+		Subject:     "Certificado de firma próximo a vencer",
+	},
+	AlertContingencyDeadline: {
+		Alert:       AlertContingencyDeadline,
+		EntityTypes: []string{EntityClient, EntityAdmin},
+		Channels:    []string{ChannelEmail, ChannelWebhook},
+		Cooldown:    6 * time.Hour,
+		Subject:     "Documentos en contingencia próximos al plazo de transmisión",
+	},
+	AlertSequenceGap: {
+		Alert:       AlertSequenceGap,
+		EntityTypes: []string{EntityClient},
+		Channels:    []string{ChannelEmail, ChannelWebhook},
+		Cooldown:    7 * 24 * time.Hour,
+		Subject:     "Salto en la numeración de documentos",
+	},
+}
+
+// Alert representa una situación que debe notificarse a los destinatarios del contribuyente. SubjectKey identifica
+// el sujeto de la alerta para aplicar el cooldown de la regla. El NIT y el nombre del contribuyente identifican la
+// alerta en los correos dirigidos a la administración
+type Alert struct {
+	Type         string                 `json:"type"`
+	UserID       uint                   `json:"user_id"`
+	NIT          string                 `json:"nit"`
+	BusinessName string                 `json:"business_name"`
+	BranchID     uint                   `json:"branch_id,omitempty"`
+	DocumentID   string                 `json:"document_id,omitempty"`
+	SubjectKey   string                 `json:"-"`
+	Message      string                 `json:"message"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	OccurredAt   time.Time              `json:"occurred_at"`
+}
+
+// Recipient representa un destinatario de las alertas. Los destinatarios con EnabledPush en false se conservan pero
+// no reciben alertas
+type Recipient struct {
+	ID          uint   `json:"id"`
+	UserID      uint   `json:"-"`
+	EntityType  string `json:"entity_type"`
+	Email       string `json:"email"`
+	EnabledPush bool   `json:"enabled_push"`
+}
+
+// Validate valida el correo y el tipo de entidad del destinatario
+func (r *Recipient) Validate() error {
+	if r.EntityType != EntityClient && r.EntityType != EntityAdmin {
+		return dte_errors.NewValidationError("InvalidFormat", "entity_type", "'CLIENT' or 'ADMIN'", r.EntityType)
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(r.Email))
+	if err != nil {
+		return dte_errors.NewValidationError("InvalidFormat", "email", "email", r.Email)
+	}
+	r.Email = address.Address
+
+	return nil
+}
+
+// Notification representa la entrega de una alerta por un canal, se almacena en user_notifications. Payload contiene
+// la alerta registrada en el evento de dominio para repetir la entrega en los reintentos
+type Notification struct {
+	ID            uint            `json:"id"`
+	UserID        uint            `json:"-"`
+	EventID       uint            `json:"-"`
+	Alert         string          `json:"alert"`
+	Channel       string          `json:"channel"`
+	Recipient     *string         `json:"recipient,omitempty"`
+	DocumentID    *string         `json:"document_id,omitempty"`
+	Message       string          `json:"message"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	ErrorMessage  *string         `json:"error_message,omitempty"`
+	DeliveryAt    time.Time       `json:"delivery_at"`
+	Payload       json.RawMessage `json:"-"`
+}
+
+// NotificationFilters filtros de la consulta de notificaciones de un contribuyente
+type NotificationFilters struct {
+	Alert   string
+	Channel string
+	Status  string
+	Limit   int
+}
+
+// RetryDelay calcula la espera antes del siguiente intento de una notificación que ha fallado attempts veces
+func RetryDelay(attempts int) time.Duration {
+	if attempts <= 0 {
+		return 0
+	}
+	return BaseNotificationRetryDelay << (attempts - 1)
+}
+
+// Taxpayer identifica al contribuyente de una alerta en los mensajes dirigidos a la administración
+type Taxpayer struct {
+	UserID       uint
+	NIT          string
+	BusinessName string
+}
+
+// NewAlert crea una alerta del contribuyente
+func NewAlert(alertType string, taxpayer Taxpayer, subjectKey, message string, occurredAt time.Time) *Alert {
+	return &Alert{
+		Type:         alertType,
+		UserID:       taxpayer.UserID,
+		NIT:          taxpayer.NIT,
+		BusinessName: taxpayer.BusinessName,
+		SubjectKey:   subjectKey,
+		Message:      message,
+		Data:         make(map[string]interface{}),
+		OccurredAt:   occurredAt,
+	}
+}
+
+// CertificateExpiry representa el vencimiento registrado del certificado de firma de un contribuyente
+type CertificateExpiry struct {
+	Taxpayer
+	ExpiresAt time.Time
+}
+
+// ContingencyBacklog representa los documentos de una sucursal que siguen pendientes en contingencia
+type ContingencyBacklog struct {
+	Taxpayer
+	BranchID  uint
+	Documents int
+	OldestAt  time.Time
+}
+
+// SequenceGap representa un número de control rechazado por Hacienda que dejó un salto en la numeración
+type SequenceGap struct {
+	Taxpayer
+	ID             uint
+	BranchID       uint
+	DTEType        string
+	SequenceNumber uint
+	Year           uint
+	FailureReason  string
+	CreatedAt      time.Time
+}
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification/models"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+)
+
+// NotificationManager genera alertas a partir de los eventos del dominio y de revisiones periódicas, y las entrega a
+// los destinatarios configurados según las reglas de cada alerta
+type NotificationManager interface {
+	// Notify registra las notificaciones de una alerta según su regla y las entrega en segundo plano, un error solo se
+	// registra en el log porque la alerta no debe interrumpir la acción que la origina
+	Notify(ctx context.Context, alert *models.Alert)
+	// HandleEvent genera las alertas que corresponden a un evento del ciclo de vida de los DTE
+	HandleEvent(ctx context.Context, event *webhookModels.Event)
+	// ScanScheduledAlerts genera las alertas que dependen del tiempo: vencimiento de certificados, plazo de
+	// contingencia y saltos en la numeración
+	ScanScheduledAlerts(ctx context.Context) error
+	// ProcessPendingNotifications realiza las notificaciones cuyo siguiente intento ya está programado
+	ProcessPendingNotifications(ctx context.Context) error
+	// ListRecipients obtiene los destinatarios de un tipo de entidad, los de tipo ADMIN no pertenecen a un contribuyente
+	ListRecipients(ctx context.Context, userID uint, entityType string) ([]models.Recipient, error)
+	// CreateRecipient registra un destinatario
+	CreateRecipient(ctx context.Context, recipient *models.Recipient) error
+	// DeleteRecipient elimina un destinatario de un tipo de entidad
+	DeleteRecipient(ctx context.Context, userID uint, entityType string, id uint) error
+	// GetNotifications obtiene las notificaciones del contribuyente que cumplen con los filtros
+	GetNotifications(ctx context.Context, userID uint, filters *models.NotificationFilters) ([]models.Notification, error)
+	// SetCertificateExpiry registra la fecha de vencimiento del certificado de firma del contribuyente
+	SetCertificateExpiry(ctx context.Context, userID uint, expiresAt *time.Time) error
+}
+
+// NotificationRepositoryPort define el almacenamiento de los destinatarios y las notificaciones, y las consultas de
+// las revisiones periódicas
+type NotificationRepositoryPort interface {
+	// GetRecipients obtiene los destinatarios de un tipo de entidad
+	GetRecipients(ctx context.Context, userID uint, entityType string) ([]models.Recipient, error)
+	// GetAlertRecipients obtiene los destinatarios habilitados de los tipos de entidad indicados, los de tipo CLIENT
+	// solo del contribuyente userID
+	GetAlertRecipients(ctx context.Context, userID uint, entityTypes []string) ([]models.Recipient, error)
+	// CreateRecipient registra un destinatario
+	CreateRecipient(ctx context.Context, recipient *models.Recipient) error
+	// DeleteRecipient elimina un destinatario
+	DeleteRecipient(ctx context.Context, userID uint, entityType string, id uint) error
+	// GetTaxpayer obtiene el contribuyente por su ID
+	GetTaxpayer(ctx context.Context, userID uint) (*models.Taxpayer, error)
+	// GetBranchTaxpayer obtiene el contribuyente propietario de una sucursal
+	GetBranchTaxpayer(ctx context.Context, branchID uint) (*models.Taxpayer, error)
+	// CreateNotifications registra el evento de dominio de la alerta y sus notificaciones
+	CreateNotifications(ctx context.Context, alert *models.Alert, notifications []models.Notification) error
+	// GetNotifications obtiene las notificaciones de un contribuyente que cumplen con los filtros
+	GetNotifications(ctx context.Context, userID uint, filters *models.NotificationFilters) ([]models.Notification, error)
+	// GetDueNotifications obtiene hasta limit notificaciones pendientes cuyo siguiente intento es anterior a now
+	GetDueNotifications(ctx context.Context, now time.Time, limit int) ([]models.Notification, error)
+	// ClaimNotification reserva una notificación pendiente hasta leaseUntil para que solo un proceso la intente
+	ClaimNotification(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error)
+	// UpdateNotification actualiza el estado, los intentos y el siguiente intento de una notificación
+	UpdateNotification(ctx context.Context, notification *models.Notification) error
+	// SetCertificateExpiry registra la fecha de vencimiento del certificado de firma de un contribuyente
+	SetCertificateExpiry(ctx context.Context, userID uint, expiresAt *time.Time) error
+	// GetExpiringCertificates obtiene los contribuyentes activos cuyo certificado vence antes de until
+	GetExpiringCertificates(ctx context.Context, until time.Time) ([]models.CertificateExpiry, error)
+	// GetContingencyBacklog obtiene las sucursales con documentos pendientes en contingencia desde antes de createdBefore
+	GetContingencyBacklog(ctx context.Context, createdBefore time.Time) ([]models.ContingencyBacklog, error)
+	// GetSequenceGaps obtiene los números de control rechazados registrados desde since
+	GetSequenceGaps(ctx context.Context, since time.Time) ([]models.SequenceGap, error)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/value_objects/base"
+)
+
+// Operator representa una cuenta de operador, por ejemplo una firma contable, que emite DTE en nombre de los
+// contribuyentes que tiene asignados. El API secret solo se almacena como hash
+type Operator struct {
+	ID        uint            `json:"id"`
+	Name      string          `json:"name"`
+	Email     string          `json:"email"`
+	APIKey    string          `json:"api_key"`
+	APISecret string          `json:"-"`
+	IsActive  bool            `json:"is_active"`
+	Grants    []OperatorGrant `json:"grants"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// OperatorGrant representa el acceso de un operador a un contribuyente. Si no se indica la sucursal el operador
+// puede actuar como cualquier sucursal activa del contribuyente
+type OperatorGrant struct {
+	ID         uint      `json:"id"`
+	OperatorID uint      `json:"operator_id"`
+	UserID     uint      `json:"user_id"`
+	BranchID   *uint     `json:"branch_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ManagedBranch representa una sucursal que un operador puede utilizar como emisor
+type ManagedBranch struct {
+	UserID            uint    `json:"user_id"`
+	BranchID          uint    `json:"branch_id"`
+	NIT               string  `json:"nit"`
+	BusinessName      string  `json:"business_name"`
+	AuthType          string  `json:"-"`
+	EstablishmentType string  `json:"establishment_type"`
+	EstablishmentCode *string `json:"establishment_code,omitempty"`
+	POSCode           *string `json:"pos_code,omitempty"`
+}
+
+// CreateOperatorRequest representa la solicitud de creación de un operador
+type CreateOperatorRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Validate valida el nombre y el correo electrónico del operador
+func (r *CreateOperatorRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return dte_errors.NewValidationError("RequiredField", "name")
+	}
+	if len(r.Name) > 150 {
+		return dte_errors.NewValidationError("InvalidLength", "name", "1 to 150", fmt.Sprint(len(r.Name)))
+	}
+
+	r.Email = strings.TrimSpace(r.Email)
+	if r.Email == "" {
+		return dte_errors.NewValidationError("RequiredField", "email")
+	}
+	if _, err := base.NewEmail(r.Email); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// OperatorCredentialsResponse contiene las llaves de un operador recién creado, el API secret solo se retorna una vez
+type OperatorCredentialsResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+}
+
+// GrantRequest representa la solicitud para asignar un contribuyente, o una de sus sucursales, a un operador
+type GrantRequest struct {
+	UserID   uint  `json:"user_id"`
+	BranchID *uint `json:"branch_id,omitempty"`
+}
+
+func (r *GrantRequest) Validate() error {
+	if r.UserID == 0 {
+		return dte_errors.NewValidationError("RequiredField", "user_id")
+	}
+	if r.BranchID != nil && *r.BranchID == 0 {
+		return dte_errors.NewValidationError("RequiredField", "branch_id")
+	}
+
+	return nil
+}
+
+// OperatorCredentials representa las credenciales de inicio de sesión de un operador
+type OperatorCredentials struct {
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+}
+
+func (c *OperatorCredentials) Validate() error {
+	if c.APIKey == "" {
+		return dte_errors.NewValidationError("RequiredField", "api_key")
+	}
+	if c.APISecret == "" {
+		return dte_errors.NewValidationError("RequiredField", "api_secret")
+	}
+
+	return nil
+}
+
+// SwitchIssuerRequest representa la solicitud de un operador para actuar como una de sus sucursales asignadas
+type SwitchIssuerRequest struct {
+	BranchID uint `json:"branch_id"`
+}
+
+func (r *SwitchIssuerRequest) Validate() error {
+	if r.BranchID == 0 {
+		return dte_errors.NewValidationError("RequiredField", "branch_id")
+	}
+
+	return nil
+}
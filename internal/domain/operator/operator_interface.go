@@ -0,0 +1,46 @@
+package operator
+
+import (
+	"context"
+
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+)
+
+// OperatorManager define las operaciones de las cuentas de operador que emiten DTE en nombre de varios contribuyentes
+type OperatorManager interface {
+	// Create crea un operador, el API secret debe recibirse como hash
+	Create(ctx context.Context, operator *models.Operator) error
+	// GetAll obtiene todos los operadores con sus accesos
+	GetAll(ctx context.Context) ([]models.Operator, error)
+	// Grant asigna un contribuyente, o una de sus sucursales, a un operador
+	Grant(ctx context.Context, grant *models.OperatorGrant) error
+	// RevokeGrant elimina un acceso de un operador e invalida los tokens que emitió para las sucursales del acceso
+	RevokeGrant(ctx context.Context, operatorID, grantID uint) error
+	// Deactivate desactiva un operador e invalida todos sus tokens
+	Deactivate(ctx context.Context, operatorID uint) error
+	// Login autentica a un operador, el token emitido solo permite consultar y cambiar de emisor
+	Login(ctx context.Context, credentials *models.OperatorCredentials) (*authModels.TokenResponse, error)
+	// GetManagedBranches obtiene las sucursales activas que el operador puede utilizar como emisor
+	GetManagedBranches(ctx context.Context, operatorID uint) ([]models.ManagedBranch, error)
+	// SwitchIssuer emite un token para que el operador actúe como una de sus sucursales asignadas
+	SwitchIssuer(ctx context.Context, claims *authModels.AuthClaims, branchID uint) (*authModels.TokenResponse, error)
+}
+
+// OperatorRepositoryPort define las operaciones de persistencia de los operadores y sus accesos
+type OperatorRepositoryPort interface {
+	// Create crea un operador
+	Create(ctx context.Context, operator *models.Operator) error
+	// GetAll obtiene todos los operadores con sus accesos
+	GetAll(ctx context.Context) ([]models.Operator, error)
+	// GetByAPIKey obtiene un operador por su API key
+	GetByAPIKey(ctx context.Context, apiKey string) (*models.Operator, error)
+	// CreateGrant crea un acceso, el contribuyente debe estar activo y la sucursal, si se indica, debe pertenecerle
+	CreateGrant(ctx context.Context, grant *models.OperatorGrant) error
+	// DeleteGrant elimina un acceso de un operador y retorna el acceso eliminado
+	DeleteGrant(ctx context.Context, operatorID, grantID uint) (*models.OperatorGrant, error)
+	// Deactivate desactiva un operador
+	Deactivate(ctx context.Context, operatorID uint) error
+	// GetManagedBranches obtiene las sucursales activas de los contribuyentes activos asignados al operador
+	GetManagedBranches(ctx context.Context, operatorID uint) ([]models.ManagedBranch, error)
+}
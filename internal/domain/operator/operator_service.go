@@ -0,0 +1,312 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	auditModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// OperatorTokenLifetime vida de los tokens de operador. Los tokens emitidos para actuar como una sucursal expiran junto
+// con el token de operador que los solicitó, por lo que las revocaciones de los tokens de operador se conservan durante
+// este tiempo
+const OperatorTokenLifetime = 8 * time.Hour
+
+type OperatorService struct {
+	repo         OperatorRepositoryPort
+	tokenManager ports.TokenManager
+	cacheManager ports.CacheManager
+	cryptManager ports.CryptManager
+	vault        vault.CredentialVault
+	audit        audit.AuditManager
+}
+
+func NewOperatorService(
+	repo OperatorRepositoryPort,
+	tokenManager ports.TokenManager,
+	cacheManager ports.CacheManager,
+	cryptManager ports.CryptManager,
+	credentialVault vault.CredentialVault,
+	auditManager audit.AuditManager,
+) OperatorManager {
+	return &OperatorService{
+		repo:         repo,
+		tokenManager: tokenManager,
+		cacheManager: cacheManager,
+		cryptManager: cryptManager,
+		vault:        credentialVault,
+		audit:        auditManager,
+	}
+}
+
+// Create crea un operador, el API secret debe recibirse como hash
+func (s *OperatorService) Create(ctx context.Context, operator *models.Operator) error {
+	if err := s.repo.Create(ctx, operator); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
+			return shared_error.NewFormattedGeneralServiceError("OperatorService", "Create", "DuplicatedEntry", "email")
+		}
+		return shared_error.NewFormattedGeneralServiceWithError("OperatorService", "Create", err, "FailedToCreateOperator")
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventOperatorCreated,
+		Payload: map[string]interface{}{
+			"operator_id": operator.ID,
+			"name":        operator.Name,
+			"email":       operator.Email,
+		},
+	})
+
+	return nil
+}
+
+// GetAll obtiene todos los operadores con sus accesos
+func (s *OperatorService) GetAll(ctx context.Context) ([]models.Operator, error) {
+	operators, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("OperatorService", "GetAll", err, "FailedToGetOperators")
+	}
+
+	return operators, nil
+}
+
+// Grant asigna un contribuyente, o una de sus sucursales, a un operador
+func (s *OperatorService) Grant(ctx context.Context, grant *models.OperatorGrant) error {
+	if err := s.repo.CreateGrant(ctx, grant); err != nil {
+		switch {
+		case errors.Is(err, errPackage.ErrOperatorNotFound):
+			return shared_error.NewFormattedGeneralServiceError("OperatorService", "Grant", "OperatorNotFound", grant.OperatorID)
+		case errors.Is(err, errPackage.ErrUserNotFound), errors.Is(err, errPackage.ErrBranchDoesNotBelong):
+			return shared_error.NewFormattedGeneralServiceError("OperatorService", "Grant", "InvalidOperatorGrant", grant.UserID)
+		case errors.Is(err, errPackage.ErrOperatorGrantExists):
+			return shared_error.NewFormattedGeneralServiceError("OperatorService", "Grant", "OperatorGrantExists")
+		}
+		return shared_error.NewFormattedGeneralServiceWithError("OperatorService", "Grant", err, "FailedToGrantOperator")
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventOperatorGranted,
+		UserID:    grant.UserID,
+		BranchID:  optionalBranchID(grant.BranchID),
+		Payload: map[string]interface{}{
+			"operator_id": grant.OperatorID,
+			"grant_id":    grant.ID,
+			"user_id":     grant.UserID,
+			"branch_id":   grant.BranchID,
+		},
+	})
+
+	return nil
+}
+
+// RevokeGrant elimina un acceso de un operador e invalida los tokens que el operador ya emitió para las sucursales del
+// acceso. El token de operador sigue vigente para las sucursales de sus demás accesos
+func (s *OperatorService) RevokeGrant(ctx context.Context, operatorID, grantID uint) error {
+	// 1. Eliminar el acceso
+	grant, err := s.repo.DeleteGrant(ctx, operatorID, grantID)
+	if err != nil {
+		if errors.Is(err, errPackage.ErrOperatorGrantNotFound) {
+			return shared_error.NewFormattedGeneralServiceError("OperatorService", "RevokeGrant", "OperatorGrantNotFound", grantID)
+		}
+		return shared_error.NewFormattedGeneralServiceWithError("OperatorService", "RevokeGrant", err, "FailedToGrantOperator")
+	}
+
+	// 2. Invalidar los tokens emitidos para las sucursales del acceso
+	if err = s.tokenManager.RevokeOperatorGrantTokens(operatorID, grant.UserID, grant.BranchID, OperatorTokenLifetime); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("OperatorService", "RevokeGrant", err, "AuthServiceUnavailable")
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventOperatorRevoked,
+		UserID:    grant.UserID,
+		BranchID:  optionalBranchID(grant.BranchID),
+		Payload: map[string]interface{}{
+			"operator_id": operatorID,
+			"grant_id":    grantID,
+			"user_id":     grant.UserID,
+			"branch_id":   grant.BranchID,
+		},
+	})
+
+	return nil
+}
+
+// Deactivate desactiva un operador e invalida su token de operador y los tokens que emitió para sus sucursales, a partir
+// de ese momento no puede iniciar sesión
+func (s *OperatorService) Deactivate(ctx context.Context, operatorID uint) error {
+	// 1. Desactivar el operador
+	if err := s.repo.Deactivate(ctx, operatorID); err != nil {
+		if errors.Is(err, errPackage.ErrOperatorNotFound) {
+			return shared_error.NewFormattedGeneralServiceError("OperatorService", "Deactivate", "OperatorNotFound", operatorID)
+		}
+		return shared_error.NewFormattedGeneralServiceWithError("OperatorService", "Deactivate", err, "FailedToDeactivateOperator")
+	}
+
+	// 2. Invalidar los tokens vigentes del operador
+	if err := s.tokenManager.RevokeOperatorTokens(operatorID, OperatorTokenLifetime); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("OperatorService", "Deactivate", err, "AuthServiceUnavailable")
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventOperatorDeactivated,
+		Payload: map[string]interface{}{
+			"operator_id": operatorID,
+		},
+	})
+
+	return nil
+}
+
+// Login autentica a un operador. El token emitido no pertenece a ninguna sucursal, solo permite consultar las
+// sucursales asignadas, listar sus documentos y cambiar de emisor
+func (s *OperatorService) Login(ctx context.Context, credentials *models.OperatorCredentials) (*authModels.TokenResponse, error) {
+	// 1. Obtener el operador, una API key inexistente y un API secret incorrecto retornan el mismo error
+	operator, err := s.repo.GetByAPIKey(ctx, credentials.APIKey)
+	if err != nil {
+		if !errors.Is(err, errPackage.ErrOperatorNotFound) {
+			return nil, err
+		}
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorService", "Login", "InvalidCredentials")
+	}
+
+	if !s.cryptManager.VerifyAPISecret(credentials.APISecret, operator.APISecret) {
+		logs.WarnContext(ctx, "Invalid operator credentials", map[string]interface{}{
+			"operatorID": operator.ID,
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorService", "Login", "InvalidCredentials")
+	}
+
+	if !operator.IsActive {
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorService", "Login", "OperatorNotActive")
+	}
+
+	// 2. Emitir el token de operador
+	claims := &authModels.AuthClaims{
+		AuthType:   constants.OperatorAuthType,
+		Scopes:     []string{constants.ScopeOperator},
+		OperatorID: operator.ID,
+	}
+	token, err := s.tokenManager.GenerateToken(claims, OperatorTokenLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Registrar el inicio de sesión, la solicitud no está autenticada por lo que el actor se indica explícitamente
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventOperatorLogin,
+		ActorType: auditModels.ActorOperator,
+		ActorID:   operator.ID,
+		Payload: map[string]interface{}{
+			"operator_id": operator.ID,
+			"expires_in":  int64(OperatorTokenLifetime.Seconds()),
+		},
+	})
+
+	return &authModels.TokenResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresIn: int64(OperatorTokenLifetime.Seconds()),
+	}, nil
+}
+
+// GetManagedBranches obtiene las sucursales activas que el operador puede utilizar como emisor
+func (s *OperatorService) GetManagedBranches(ctx context.Context, operatorID uint) ([]models.ManagedBranch, error) {
+	branches, err := s.repo.GetManagedBranches(ctx, operatorID)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("OperatorService", "GetManagedBranches", err, "FailedToGetManagedBranches")
+	}
+
+	return branches, nil
+}
+
+// SwitchIssuer emite un token para que el operador actúe como una de sus sucursales asignadas. El token pertenece a la
+// sucursal, por lo que el emisor, la numeración y las credenciales de Hacienda son los de la sucursal y su contribuyente
+func (s *OperatorService) SwitchIssuer(ctx context.Context, claims *authModels.AuthClaims, branchID uint) (*authModels.TokenResponse, error) {
+	// 1. Verificar que el operador tenga acceso a la sucursal
+	branches, err := s.GetManagedBranches(ctx, claims.OperatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed *models.ManagedBranch
+	for i := range branches {
+		if branches[i].BranchID == branchID {
+			managed = &branches[i]
+			break
+		}
+	}
+	if managed == nil {
+		logs.WarnContext(ctx, "Operator tried to switch to a branch without access", map[string]interface{}{
+			"operatorID": claims.OperatorID,
+			"branchID":   branchID,
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorService", "SwitchIssuer", "BranchNotGranted", branchID)
+	}
+
+	// 2. El token de la sucursal expira junto con el token de operador
+	tokenLifetime := claims.IssuedAt.Add(OperatorTokenLifetime).Sub(utils.TimeNow())
+	if tokenLifetime <= 0 {
+		return nil, shared_error.NewFormattedGeneralServiceError("OperatorService", "SwitchIssuer", "Unauthorized")
+	}
+
+	// 3. Obtener las credenciales de Hacienda del contribuyente desde el vault
+	creds, err := s.vault.Get(ctx, managed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Emitir el token de la sucursal y guardar las credenciales de Hacienda de la sesión
+	branchClaims := &authModels.AuthClaims{
+		ClientID:   managed.UserID,
+		BranchID:   managed.BranchID,
+		AuthType:   managed.AuthType,
+		NIT:        managed.NIT,
+		Scopes:     constants.OperatorIssuerScopes,
+		OperatorID: claims.OperatorID,
+	}
+	token, err := s.tokenManager.GenerateToken(branchClaims, tokenLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.cacheManager.SetCredentials(token, creds, tokenLifetime); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &auditModels.AuditEntry{
+		EventType: auditModels.EventIssuerSwitched,
+		UserID:    managed.UserID,
+		BranchID:  managed.BranchID,
+		Payload: map[string]interface{}{
+			"operator_id": claims.OperatorID,
+			"nit":         managed.NIT,
+			"expires_in":  int64(tokenLifetime.Seconds()),
+		},
+	})
+
+	return &authModels.TokenResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresIn: int64(tokenLifetime.Seconds()),
+	}, nil
+}
+
+// optionalBranchID retorna el ID de la sucursal de un acceso, cero si el acceso abarca todas las sucursales
+func optionalBranchID(branchID *uint) uint {
+	if branchID == nil {
+		return 0
+	}
+	return *branchID
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+)
+
+const (
+	// DefaultPrimaryColor es el color utilizado en los encabezados cuando la sucursal no define uno
+	DefaultPrimaryColor = "#1F3864"
+	// MaxLogoSize es el tamaño máximo permitido para el logo en bytes (512 KB)
+	MaxLogoSize = 512 * 1024
+)
+
+var (
+	hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+	// ValidLogoFormats contiene los formatos de imagen soportados para el logo
+	ValidLogoFormats = map[string]bool{
+		"PNG": true,
+		"JPG": true,
+	}
+)
+
+// Branding representa la configuración de marca de una sucursal para la representación gráfica de sus DTE
+type Branding struct {
+	BranchID     uint   `json:"-"`
+	Logo         []byte `json:"-"`
+	LogoFormat   string `json:"logo_format,omitempty"`
+	PrimaryColor string `json:"primary_color"`
+	FooterText   string `json:"footer_text,omitempty"`
+	HasLogo      bool   `json:"has_logo"`
+}
+
+// NewDefaultBranding crea una configuración de marca sin logo y con el color por defecto
+func NewDefaultBranding(branchID uint) *Branding {
+	return &Branding{
+		BranchID:     branchID,
+		PrimaryColor: DefaultPrimaryColor,
+	}
+}
+
+// Validate valida la configuración de marca
+func (b *Branding) Validate() error {
+	if b.PrimaryColor != "" && !hexColorPattern.MatchString(b.PrimaryColor) {
+		return dte_errors.NewValidationError("InvalidPattern", "primary_color", "#RRGGBB", b.PrimaryColor)
+	}
+
+	if len(b.FooterText) > 255 {
+		return dte_errors.NewValidationError("InvalidLength", "footer_text", "0 to 255", b.FooterText)
+	}
+
+	if len(b.Logo) > 0 {
+		b.LogoFormat = strings.ToUpper(b.LogoFormat)
+		if !ValidLogoFormats[b.LogoFormat] {
+			return dte_errors.NewValidationError("InvalidFormat", "logo_format", "PNG, JPG", b.LogoFormat)
+		}
+
+		if len(b.Logo) > MaxLogoSize {
+			return dte_errors.NewValidationError("InvalidLength", "logo", "0 to 524288 bytes", fmt.Sprint(len(b.Logo)))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	// ReceiptDocument es la representación gráfica del documento emitido
+	ReceiptDocument = "document"
+	// ReceiptInvalidation es el comprobante de invalidación de un documento
+	ReceiptInvalidation = "invalidation"
+)
+
+// PDFDocument contiene la información necesaria para generar la representación gráfica de un DTE
+type PDFDocument struct {
+	DTEType        string
+	ReceiptType    string
+	ControlNumber  string
+	GenerationCode string
+	ReceptionStamp *string
+	Status         string
+	Transmission   string
+	QRLink         string
+	EmissionDate   time.Time
+	UpdatedAt      string
+	JSONData       []byte
+	Branding       *Branding
+}
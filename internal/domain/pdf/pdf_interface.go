@@ -0,0 +1,28 @@
+package pdf
+
+import (
+	"context"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+)
+
+// PDFManager es una interfaz que define los métodos para generar la representación gráfica de los DTE
+type PDFManager interface {
+	// GenerateDTE genera la representación gráfica (PDF) de un DTE según su tipo
+	GenerateDTE(ctx context.Context, document *models.PDFDocument) ([]byte, error)
+	// GenerateFromDocument genera el PDF de un DTE almacenado con la marca de su sucursal, retorna el contenido y el nombre del archivo
+	GenerateFromDocument(ctx context.Context, document *dte.DTEDocument, receiptType string) ([]byte, string, error)
+	// GetBranding obtiene la configuración de marca de una sucursal, si no existe se retorna la configuración por defecto
+	GetBranding(ctx context.Context, branchID uint) (*models.Branding, error)
+	// SaveBranding valida y almacena la configuración de marca de una sucursal
+	SaveBranding(ctx context.Context, branding *models.Branding) error
+}
+
+// BrandingRepositoryPort es una interfaz que define los métodos del repositorio de configuración de marca
+type BrandingRepositoryPort interface {
+	// GetByBranchID obtiene la configuración de marca de una sucursal
+	GetByBranchID(ctx context.Context, branchID uint) (*models.Branding, error)
+	// Upsert crea o actualiza la configuración de marca de una sucursal
+	Upsert(ctx context.Context, branding *models.Branding) error
+}
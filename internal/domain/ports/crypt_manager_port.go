@@ -14,4 +14,10 @@ type CryptManager interface {
 	DecryptStruct(token string, data string) (models.HaciendaCredentials, error)
 	// GenerateBulkAPIKeys genera una cantidad de API Keys aleatorios
 	GenerateBulkAPIKeys(amount int) ([]string, []string, error)
+	// HashAPISecret calcula el hash con el que se almacena un API Secret
+	HashAPISecret(secret string) string
+	// VerifyAPISecret compara un API Secret con el hash almacenado
+	VerifyAPISecret(secret, hashed string) bool
+	// VerifyRequestSignature compara la firma HMAC de una solicitud con la calculada con el API Secret
+	VerifyRequestSignature(secret, payload, signature string) bool
 }
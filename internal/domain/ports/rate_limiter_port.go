@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter limita la cantidad de eventos registrados por llave dentro de una ventana deslizante
+type RateLimiter interface {
+	// Allow registra un evento para la llave si dentro de la ventana no se ha alcanzado el límite
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+	// Reset elimina los eventos registrados para la llave
+	Reset(ctx context.Context, key string) error
+	// Lock bloquea la llave durante el tiempo indicado
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	// LockedFor retorna el tiempo restante del bloqueo de la llave, cero si no está bloqueada
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+}
+
+// RateLimitResult resultado de registrar un evento en el RateLimiter
+type RateLimitResult struct {
+	Allowed    bool          // Allowed indica si el evento fue registrado
+	Limit      int           // Limit es el máximo de eventos dentro de la ventana
+	Remaining  int           // Remaining es la cantidad de eventos que aún pueden registrarse dentro de la ventana
+	RetryAfter time.Duration // RetryAfter es el tiempo hasta que se libere un espacio en la ventana, cero si el evento fue registrado
+}
@@ -1,6 +1,7 @@
 package ports
 
 import (
+	"context"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/go-redis/redis/v8"
 	"time"
@@ -13,6 +14,7 @@ type CacheManager interface {
 	GetCredentials(token string) (*models.HaciendaCredentials, error)                             // GetCredentials obtiene las credenciales del cache
 	Get(key string) (string, error)                                                               // Get obtiene un token del cache
 	Delete(token string) error                                                                    // Delete elimina un token del cache
+	DeleteSession(token string) error                                                             // DeleteSession elimina las credenciales y el token de Hacienda de la sesión de un token
 	GetRedisClient() *redis.Client                                                                // GetRedisClient retorna el cliente de Redis
 	CacheListManager
 }
@@ -29,8 +31,15 @@ type CacheListManager interface {
 // TokenManager define el comportamiento para la gestión de tokens
 type TokenManager interface {
 	GenerateToken(claims *models.AuthClaims, tokenLifetime time.Duration) (string, error)                                     // GenerateToken genera un nuevo token JWT con los claims proporcionados
-	ValidateToken(token string) (*models.AuthClaims, error)                                                                   // ValidateToken valida un token y retorna sus claims
+	ValidateToken(ctx context.Context, token string) (*models.AuthClaims, error)                                              // ValidateToken valida un token y retorna sus claims
 	RevokeToken(token string) error                                                                                           // RevokeToken revoca un token específico
+	RevokeBranchTokens(branchID uint, ttl time.Duration) error                                                                // RevokeBranchTokens revoca todos los tokens emitidos hasta ahora para una sucursal
+	RevokeAPIKeyTokens(keyID uint, ttl time.Duration) error                                                                   // RevokeAPIKeyTokens revoca todos los tokens emitidos hasta ahora con una llave de acceso adicional
+	RevokeOperatorTokens(operatorID uint, ttl time.Duration) error                                                            // RevokeOperatorTokens revoca todos los tokens emitidos hasta ahora para un operador
+	RevokeOperatorGrantTokens(operatorID, userID uint, branchID *uint, ttl time.Duration) error                               // RevokeOperatorGrantTokens revoca los tokens que un operador emitió para las sucursales de un acceso
+	GenerateRefreshToken(ctx context.Context, session *models.RefreshSession, ttl time.Duration) (string, error)              // GenerateRefreshToken genera un refresh token para una sesión
+	ConsumeRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshSession, error)                             // ConsumeRefreshToken obtiene la sesión de un refresh token y lo marca como utilizado
+	RevokeRefreshFamily(ctx context.Context, familyID string) (string, error)                                                 // RevokeRefreshFamily revoca todos los refresh tokens de una sesión y retorna su último access token
 	SaveTimestampsForContingency(issuedAt, expiresAt time.Time, tokenLifetime time.Duration, claims *models.AuthClaims) error // SaveTimestampsForContingency guarda los timestamps de un token en contingencia
 	GetSecretKey() string                                                                                                     // GetSecretKey retorna la clave secreta para firmar los tokens
 }
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SealedCredentials representa las credenciales de Hacienda de un usuario cifradas con envelope encryption.
+// Las credenciales se cifran con una llave de datos propia (Ciphertext) y esta se cifra con la llave maestra
+// identificada por KeyVersion (WrappedKey), rotar la llave maestra solo requiere volver a cifrar la llave de datos
+type SealedCredentials struct {
+	UserID     uint
+	KeyVersion string
+	WrappedKey string
+	Ciphertext string
+	UpdatedAt  time.Time
+}
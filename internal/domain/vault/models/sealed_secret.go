@@ -0,0 +1,23 @@
+package models
+
+// SealedSecretSeparator separa la versión de la llave maestra del secret cifrado con SealSecret
+const SealedSecretSeparator = "$"
+
+// SealedSecretSource identifica la tabla y la columna en la que se almacena un secret cifrado con SealSecret
+type SealedSecretSource string
+
+const (
+	BranchSigningSecret SealedSecretSource = "branch_offices.api_signing_secret"  // Llave de firma HMAC de una sucursal
+	APIKeySigningSecret SealedSecretSource = "branch_api_keys.api_signing_secret" // Llave de firma HMAC de una llave adicional
+	WebhookSecret       SealedSecretSource = "webhook_subscriptions.secret"       // Llave de firma de las entregas de una suscripción
+)
+
+// SealedSecretSources contiene todas las ubicaciones de secrets cifrados que se vuelven a cifrar al rotar la llave maestra
+var SealedSecretSources = []SealedSecretSource{BranchSigningSecret, APIKeySigningSecret, WebhookSecret}
+
+// SealedSecret representa un secret cifrado con SealSecret, Value incluye la versión de la llave maestra con la que se cifró
+type SealedSecret struct {
+	Source SealedSecretSource
+	ID     uint
+	Value  string
+}
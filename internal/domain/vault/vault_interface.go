@@ -0,0 +1,49 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+)
+
+// CredentialVault almacena de forma permanente y cifrada las credenciales de Hacienda de cada usuario,
+// permite obtenerlas sin depender de una sesión activa
+type CredentialVault interface {
+	// Store cifra y almacena las credenciales de Hacienda del usuario, reemplazando las anteriores
+	Store(ctx context.Context, userID uint, creds *authModels.HaciendaCredentials) error
+	// Get obtiene y descifra las credenciales de Hacienda del usuario
+	Get(ctx context.Context, userID uint) (*authModels.HaciendaCredentials, error)
+	// RotateKeys vuelve a cifrar con la llave maestra activa las llaves de datos y los secrets cifrados con versiones
+	// anteriores, retorna la cantidad de registros actualizados
+	RotateKeys(ctx context.Context) (int, error)
+	// SealSecret cifra con la llave maestra activa un secret que el servidor debe conocer en texto plano, como la llave
+	// con la que se firman las solicitudes HMAC y las entregas de webhooks
+	SealSecret(secret string) (string, error)
+	// OpenSecret descifra un secret cifrado con SealSecret
+	OpenSecret(sealed string) (string, error)
+}
+
+// CredentialVaultRepositoryPort define el almacenamiento de las credenciales cifradas
+type CredentialVaultRepositoryPort interface {
+	// Save crea o reemplaza las credenciales cifradas del usuario
+	Save(ctx context.Context, sealed *models.SealedCredentials) error
+	// GetByUserID obtiene las credenciales cifradas del usuario
+	GetByUserID(ctx context.Context, userID uint) (*models.SealedCredentials, error)
+	// GetByOutdatedKey obtiene las credenciales cuya llave de datos no está cifrada con la versión indicada
+	GetByOutdatedKey(ctx context.Context, activeVersion string, limit int) ([]models.SealedCredentials, error)
+	// UpdateWrappedKey reemplaza la llave de datos cifrada y su versión de llave maestra
+	UpdateWrappedKey(ctx context.Context, userID uint, keyVersion, wrappedKey string) error
+	// GetOutdatedSecrets obtiene, en orden de ID y a partir del ID indicado, los secrets de una ubicación que no están
+	// cifrados con la versión indicada
+	GetOutdatedSecrets(ctx context.Context, source models.SealedSecretSource, activeVersion string, afterID uint, limit int) ([]models.SealedSecret, error)
+	// UpdateSecret reemplaza un secret cifrado solo si no cambió desde que se obtuvo, retorna si fue reemplazado
+	UpdateSecret(ctx context.Context, secret *models.SealedSecret, value string) (bool, error)
+}
+
+// HaciendaTokenKey retorna la llave con la que se almacena en caché el token de Hacienda obtenido con las credenciales
+// del vault, se utiliza en los procesos en segundo plano que no cuentan con el token de una sesión
+func HaciendaTokenKey(userID uint) string {
+	return fmt.Sprintf("vault:user:%d", userID)
+}
@@ -0,0 +1,249 @@
+package models
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+)
+
+// Eventos del ciclo de vida de los DTE que pueden suscribirse
+const (
+	// EventDTEIssued se emite cuando Hacienda recibe un DTE transmitido en línea
+	EventDTEIssued = "dte.issued"
+	// EventDTEReceived se emite cuando Hacienda recibe un DTE retransmitido en un lote de contingencia
+	EventDTEReceived = "dte.received"
+	// EventDTERejected se emite cuando Hacienda rechaza un DTE, ya sea en línea o en un lote de contingencia
+	EventDTERejected = "dte.rejected"
+	// EventDTEContingencyStored se emite cuando un DTE se almacena en contingencia para su retransmisión
+	EventDTEContingencyStored = "dte.contingency_stored"
+	// EventDTERetransmitted se emite cuando un lote de DTE en contingencia se retransmite a Hacienda
+	EventDTERetransmitted = "dte.retransmitted"
+	// EventDTEInvalidated se emite cuando Hacienda acepta la invalidación de un DTE
+	EventDTEInvalidated = "dte.invalidated"
+	// EventBatchCompleted se emite cuando Hacienda termina de procesar un lote de contingencia
+	EventBatchCompleted = "batch.completed"
+	// EventNotificationCreated se emite cuando el motor de notificaciones genera una alerta para el contribuyente
+	EventNotificationCreated = "notification.created"
+)
+
+const (
+	// DeliveryPending indica que la entrega aún no se realiza o está programada para un nuevo intento
+	DeliveryPending = "PENDING"
+	// DeliveryDelivered indica que el destino respondió con un código 2xx
+	DeliveryDelivered = "DELIVERED"
+	// DeliveryDead indica que la entrega agotó sus intentos y se encuentra en la lista de entregas fallidas
+	DeliveryDead = "DEAD"
+
+	// MaxDeliveryAttempts cantidad de intentos de una entrega antes de moverla a la lista de entregas fallidas
+	MaxDeliveryAttempts = 6
+	// BaseRetryDelay espera antes del primer reintento, se duplica en cada intento fallido
+	BaseRetryDelay = time.Minute
+	// MaxRetryDelay espera máxima entre dos intentos de una entrega
+	MaxRetryDelay = time.Hour
+)
+
+// ValidEvents eventos que pueden indicarse en una suscripción
+var ValidEvents = map[string]bool{
+	EventDTEIssued:            true,
+	EventDTEReceived:          true,
+	EventDTERejected:          true,
+	EventDTEContingencyStored: true,
+	EventDTERetransmitted:     true,
+	EventDTEInvalidated:       true,
+	EventBatchCompleted:       true,
+	EventNotificationCreated:  true,
+}
+
+// internalHostSuffixes sufijos de los nombres de host que solo se resuelven dentro de redes internas
+var internalHostSuffixes = []string{".localhost", ".local", ".localdomain", ".internal", ".intranet", ".lan", ".corp", ".home.arpa"}
+
+// nonPublicNetworks rangos que no son enrutables en internet y que no cubren los métodos de net.IP: la red "this", la
+// red compartida de los proveedores (CGNAT), las asignaciones del IETF, las redes de pruebas y documentación, los
+// rangos reservados y el prefijo NAT64 con el que una dirección IPv6 puede apuntar a una dirección IPv4 interna
+var nonPublicNetworks = parseNetworks(
+	"0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "192.0.2.0/24", "198.18.0.0/15", "198.51.100.0/24",
+	"203.0.113.0/24", "240.0.0.0/4", "64:ff9b::/96", "64:ff9b:1::/48", "100::/64", "2001:db8::/32",
+)
+
+// Subscription representa la suscripción de un contribuyente a los eventos de sus DTE. El secret se almacena cifrado
+// con el vault y solo se retorna al crear la suscripción
+type Subscription struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"-"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"-"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SubscriptionCredentialsResponse representa una suscripción recién creada junto con el secret de firma
+type SubscriptionCredentialsResponse struct {
+	Subscription
+	Secret string `json:"secret"`
+}
+
+// Validate valida la URL de destino y los eventos de la suscripción. Fuera del modo de desarrollo (DEBUG) la URL debe
+// utilizar https, y su host no puede ser una dirección privada, de loopback o link-local ni un nombre de una red interna
+// para que las entregas no alcancen servicios internos. Las direcciones a las que se resuelve el nombre se verifican al
+// conectarse en cada entrega
+func (s *Subscription) Validate() error {
+	parsed, err := url.Parse(s.URL)
+	if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return dte_errors.NewValidationError("InvalidFormat", "url", "http(s)://host/path", s.URL)
+	}
+
+	if parsed.Scheme != "https" && !config.Server.Debug {
+		return dte_errors.NewValidationError("InvalidFormat", "url", "https://host/path", s.URL)
+	}
+
+	if !IsPublicHost(parsed.Hostname()) {
+		return dte_errors.NewValidationError("WebhookTargetNotAllowed", parsed.Hostname())
+	}
+
+	if len(s.Events) == 0 {
+		return dte_errors.NewValidationError("RequiredField", "events")
+	}
+
+	for _, event := range s.Events {
+		if !ValidEvents[event] {
+			return dte_errors.NewValidationError("InvalidWebhookEvent", event)
+		}
+	}
+
+	return nil
+}
+
+// IsPublicHost indica si el host de una URL de destino puede alcanzarse desde internet. Las direcciones IP se verifican
+// con IsPublicIP, y se rechazan localhost, los nombres sin dominio y los nombres de redes internas
+func IsPublicHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return false
+	}
+
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPublicIP indica si una dirección IP es enrutable en internet. Se rechazan las direcciones privadas, de loopback,
+// link-local (incluyendo el servicio de metadatos 169.254.169.254 de los proveedores de nube), multicast, sin
+// especificar y los rangos reservados de nonPublicNetworks
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNetworks convierte los rangos CIDR de la lista, los rangos son constantes por lo que un error es un defecto
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// Subscribes indica si la suscripción está activa y recibe el evento
+func (s *Subscription) Subscribes(eventType string) bool {
+	if !s.IsActive {
+		return false
+	}
+
+	for _, event := range s.Events {
+		if strings.EqualFold(event, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Event representa un evento del ciclo de vida de un DTE a entregar a las suscripciones del contribuyente propietario
+// de la sucursal. Los eventos que no corresponden a una sucursal se entregan a las suscripciones del contribuyente UserID
+type Event struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"event"`
+	UserID     uint                   `json:"-"`
+	BranchID   uint                   `json:"branch_id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// Delivery representa la entrega de un evento a una suscripción
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID uint            `json:"subscription_id"`
+	UserID         uint            `json:"-"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+
+	// Logs contiene los intentos realizados, solo se obtiene al consultar una entrega
+	Logs []DeliveryAttempt `json:"logs,omitempty"`
+}
+
+// DeliveryAttempt representa el registro de un intento de entrega
+type DeliveryAttempt struct {
+	Attempt      int       `json:"attempt"`
+	StatusCode   *int      `json:"status_code,omitempty"`
+	ErrorMessage *string   `json:"error_message,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+// DeliveryFilters representa los filtros de búsqueda de entregas, los campos vacíos no se aplican
+type DeliveryFilters struct {
+	SubscriptionID uint
+	Status         string
+	EventType      string
+	Limit          int
+}
+
+// RetryDelay calcula la espera antes del siguiente intento de una entrega que ha fallado attempts veces. La espera
+// crece de forma exponencial a partir de BaseRetryDelay hasta MaxRetryDelay
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	delay := BaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= MaxRetryDelay {
+			return MaxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+)
+
+// WebhookManager administra las suscripciones de los contribuyentes a los eventos del ciclo de vida de sus DTE y la
+// entrega firmada de esos eventos
+type WebhookManager interface {
+	// Emit registra una entrega por cada suscripción del contribuyente propietario de la sucursal que recibe el evento
+	// y las realiza en segundo plano, además lo publica en el stream de actividad de la sucursal. Un error al
+	// registrarlas no interrumpe la acción que origina el evento
+	Emit(ctx context.Context, event *models.Event)
+	// CreateSubscription registra una suscripción del contribuyente, el secret de firma solo se retorna en la creación
+	CreateSubscription(ctx context.Context, subscription *models.Subscription) (*models.SubscriptionCredentialsResponse, error)
+	// ListSubscriptions obtiene las suscripciones del contribuyente
+	ListSubscriptions(ctx context.Context, userID uint) ([]models.Subscription, error)
+	// DeleteSubscription elimina una suscripción del contribuyente junto con sus entregas pendientes
+	DeleteSubscription(ctx context.Context, userID, id uint) error
+	// GetDeliveries obtiene las entregas del contribuyente que cumplen con los filtros, de la más reciente a la más antigua
+	GetDeliveries(ctx context.Context, userID uint, filters *models.DeliveryFilters) ([]models.Delivery, error)
+	// GetDelivery obtiene una entrega del contribuyente junto con el registro de sus intentos
+	GetDelivery(ctx context.Context, userID uint, id string) (*models.Delivery, error)
+	// Redeliver programa una nueva entrega inmediata de un evento, reiniciando sus intentos
+	Redeliver(ctx context.Context, userID uint, id string) (*models.Delivery, error)
+	// ProcessPendingDeliveries realiza las entregas cuyo siguiente intento ya está programado
+	ProcessPendingDeliveries(ctx context.Context) error
+	// AddListener registra un listener que recibe todos los eventos emitidos, tengan o no suscripciones
+	AddListener(listener EventListener)
+}
+
+// EventListener recibe los eventos emitidos por el WebhookManager, por ejemplo el motor de notificaciones
+type EventListener interface {
+	// HandleEvent procesa un evento emitido, no debe bloquear la acción que lo origina
+	HandleEvent(ctx context.Context, event *models.Event)
+}
+
+// WebhookRepositoryPort define el almacenamiento de las suscripciones, entregas e intentos de entrega
+type WebhookRepositoryPort interface {
+	// CreateSubscription registra una suscripción
+	CreateSubscription(ctx context.Context, subscription *models.Subscription) error
+	// GetSubscriptions obtiene las suscripciones de un contribuyente
+	GetSubscriptions(ctx context.Context, userID uint) ([]models.Subscription, error)
+	// GetSubscriptionsByBranch obtiene las suscripciones activas del contribuyente propietario de una sucursal
+	GetSubscriptionsByBranch(ctx context.Context, branchID uint) ([]models.Subscription, error)
+	// GetSubscription obtiene una suscripción por su ID, sin importar el contribuyente
+	GetSubscription(ctx context.Context, id uint) (*models.Subscription, error)
+	// DeleteSubscription elimina una suscripción del contribuyente y descarta sus entregas pendientes
+	DeleteSubscription(ctx context.Context, userID, id uint) error
+	// CreateDeliveries registra las entregas de un evento
+	CreateDeliveries(ctx context.Context, deliveries []models.Delivery) error
+	// GetDeliveries obtiene las entregas de un contribuyente que cumplen con los filtros
+	GetDeliveries(ctx context.Context, userID uint, filters *models.DeliveryFilters) ([]models.Delivery, error)
+	// GetDelivery obtiene una entrega de un contribuyente junto con sus intentos
+	GetDelivery(ctx context.Context, userID uint, id string) (*models.Delivery, error)
+	// GetDueDeliveries obtiene hasta limit entregas pendientes cuyo siguiente intento es anterior a now
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error)
+	// ClaimDelivery reserva una entrega pendiente hasta leaseUntil para que solo un proceso la intente, retorna false
+	// si la entrega ya fue reservada, realizada o descartada
+	ClaimDelivery(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error)
+	// RecordAttempt registra un intento de entrega y actualiza el estado de la entrega
+	RecordAttempt(ctx context.Context, delivery *models.Delivery, attempt *models.DeliveryAttempt) error
+	// ResetDelivery programa un nuevo intento inmediato de una entrega reiniciando sus intentos
+	ResetDelivery(ctx context.Context, delivery *models.Delivery) error
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageContextKey llave del contexto en la que se almacena el idioma de la solicitud
+const LanguageContextKey = "lang"
+
+// WithLanguage retorna un contexto con el idioma indicado
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, LanguageContextKey, lang)
+}
+
+// LanguageFromContext retorna el idioma de la solicitud, si el contexto no tiene uno se utiliza el idioma por defecto
+func LanguageFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if lang, ok := ctx.Value(LanguageContextKey).(string); ok && lang != "" {
+			return lang
+		}
+	}
+
+	return DefaultLanguage()
+}
+
+// ParseAcceptLanguage obtiene de una cabecera Accept-Language el idioma cargado con mayor preferencia. Retorna una
+// cadena vacía si la cabecera no incluye ningún idioma cargado
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	// 1. Obtener los idiomas de la cabecera con su preferencia, "es-SV;q=0.8" se interpreta como "es" con 0.8
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: strings.SplitN(tag, "-", 2)[0], quality: quality})
+	}
+
+	// 2. Elegir el idioma cargado con mayor preferencia, a igual preferencia se respeta el orden de la cabecera
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	for _, c := range candidates {
+		if IsSupported(c.lang) {
+			return c.lang
+		}
+	}
+
+	return ""
+}
+
+// Localizable es implementado por los errores que conservan su código de traducción, permite mostrarlos en el idioma
+// de cada solicitud aunque se hayan creado con el idioma por defecto
+type Localizable interface {
+	Localize(lang string) string
+}
+
+// LocalizeError retorna el mensaje del error en el idioma indicado, los errores que no conservan su código de
+// traducción retornan su mensaje original
+func LocalizeError(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+
+	if localizable, ok := err.(Localizable); ok {
+		return localizable.Localize(lang)
+	}
+
+	return err.Error()
+}
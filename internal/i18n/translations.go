@@ -3,6 +3,7 @@ package i18n
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -15,11 +16,12 @@ var (
 	translations = make(map[string]map[string]string)
 	// initialized indica si el sistema ya fue inicializado
 	initialized = false
-	// lang almacena el idioma actual
+	// globalLang almacena el idioma por defecto, utilizado cuando la solicitud no indica uno
 	globalLang = "en"
 )
 
-// InitTranslations carga los archivos de traducción al inicio
+// InitTranslations carga al inicio todos los archivos de traducción del directorio, el idioma indicado se utiliza
+// cuando la solicitud no indica uno
 func InitTranslations(configPath, lang string) error {
 	translateMutex.Lock()
 	defer translateMutex.Unlock()
@@ -28,20 +30,32 @@ func InitTranslations(configPath, lang string) error {
 		return nil
 	}
 
-	// Cargar traducciones para cada idioma soportado
-	globalLang = strings.ToLower(lang)
-	v := viper.New()
+	// 1. Obtener los archivos de traducción del directorio
+	files, err := filepath.Glob(filepath.Join(configPath, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no translation files found in %s: %w", configPath, os.ErrNotExist)
+	}
 
-	// Configurar viper para el archivo de errores
-	errorsFile := filepath.Join(configPath, fmt.Sprintf("%s.yaml", globalLang))
-	v.SetConfigFile(errorsFile)
-	v.SetConfigType("yaml")
+	// 2. Cargar las traducciones de cada idioma, el nombre del archivo es el código del idioma
+	for _, file := range files {
+		v := viper.New()
+		v.SetConfigFile(file)
+		v.SetConfigType("yaml")
 
-	// Leer el archivo
-	if err := v.ReadInConfig(); err != nil {
-		return err
+		if err := v.ReadInConfig(); err != nil {
+			return err
+		}
+		processKeys(v, strings.ToLower(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))))
+	}
+
+	// 3. Establecer el idioma por defecto, debe ser uno de los idiomas cargados
+	globalLang = strings.ToLower(lang)
+	if translations[globalLang] == nil {
+		return fmt.Errorf("translation file for language %q not found in %s", lang, configPath)
 	}
-	processKeys(v, globalLang)
 
 	initialized = true
 	return nil
@@ -79,12 +93,22 @@ func processKeys(v *viper.Viper, lang string) {
 }
 
 func TranslateServiceArgs(code string, params ...interface{}) string {
+	return TranslateServiceArgsIn(globalLang, code, params...)
+}
+
+// TranslateServiceArgsIn obtiene la traducción de un error de servicio en el idioma indicado
+func TranslateServiceArgsIn(lang, code string, params ...interface{}) string {
 	code = fmt.Sprintf("service_errors.%s", strings.ToLower(code))
-	return Translate(code, params...)
+	return TranslateIn(lang, code, params...)
 }
 
-// Translate obtiene la traducción para un código y lenguaje
+// Translate obtiene la traducción para un código en el idioma por defecto
 func Translate(code string, params ...interface{}) string {
+	return TranslateIn(globalLang, code, params...)
+}
+
+// TranslateIn obtiene la traducción para un código y lenguaje
+func TranslateIn(lang, code string, params ...interface{}) string {
 	code = strings.ToLower(code)
 	translateMutex.RLock()
 	defer translateMutex.RUnlock()
@@ -93,7 +117,7 @@ func Translate(code string, params ...interface{}) string {
 		return code
 	}
 
-	normalizedLang := normalizeLanguage(globalLang)
+	normalizedLang := normalizeLanguage(lang)
 	template, found := translations[normalizedLang][code]
 	if !found {
 		// Si no se encuentra en el idioma solicitado, intentar con inglés
@@ -112,6 +136,36 @@ func Translate(code string, params ...interface{}) string {
 	return template
 }
 
+// Keys retorna las claves de traducción cargadas para un idioma
+func Keys(lang string) []string {
+	translateMutex.RLock()
+	defer translateMutex.RUnlock()
+
+	keys := make([]string, 0, len(translations[lang]))
+	for key := range translations[lang] {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// DefaultLanguage retorna el idioma utilizado cuando la solicitud no indica uno
+func DefaultLanguage() string {
+	translateMutex.RLock()
+	defer translateMutex.RUnlock()
+
+	return globalLang
+}
+
+// IsSupported indica si existe un archivo de traducción para el idioma indicado
+func IsSupported(lang string) bool {
+	translateMutex.RLock()
+	defer translateMutex.RUnlock()
+
+	_, ok := translations[strings.ToLower(lang)]
+	return ok
+}
+
 // normalizeLanguage normaliza el código de idioma
 func normalizeLanguage(lang string) string {
 	// Convertir a minúsculas y tomar solo los primeros dos caracteres
@@ -120,18 +174,18 @@ func normalizeLanguage(lang string) string {
 		simpleLang = simpleLang[:2]
 	}
 
-	// Verificar si es un idioma soportado
-	switch simpleLang {
-	case "es":
-		return "es"
-	default:
-		return "en"
+	// Verificar si es un idioma cargado
+	if _, ok := translations[simpleLang]; ok {
+		return simpleLang
 	}
+
+	return "en"
 }
 
 func ForceReload(configPath string) error {
 	translateMutex.Lock()
 	initialized = false
+	translations = make(map[string]map[string]string)
 	translateMutex.Unlock()
 
 	return InitTranslations(configPath, "en")
@@ -0,0 +1,241 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	activityPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/activity"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+const (
+	// streamMaxLength cantidad aproximada de eventos que conserva el stream de cada sucursal para reanudar conexiones
+	streamMaxLength = 1000
+	// streamRetention tiempo que se conserva el stream de una sucursal desde su último evento
+	streamRetention = 24 * time.Hour
+	// subscriberBuffer cantidad de eventos en espera de ser escritos en la conexión de un cliente
+	subscriberBuffer = 64
+)
+
+// publishScript registra el evento en el stream y lo publica en el canal junto con el ID asignado, de forma atómica
+// para que el ID del mensaje en tiempo real siempre coincida con el del historial. Retorna el ID asignado
+var publishScript = redis.NewScript(`
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*', 'event', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('PUBLISH', KEYS[2], id .. ' ' .. ARGV[2])
+return id
+`)
+
+// ActivityService implementa el stream de actividad de las sucursales con Redis. Cada sucursal tiene un stream con
+// sus eventos más recientes para reanudar conexiones desde Last-Event-ID y un canal pub/sub para distribuir los
+// eventos en tiempo real a los clientes conectados en cualquier réplica
+type ActivityService struct {
+	client *redis.Client
+}
+
+// NewActivityService crea una instancia de ActivityService con el cliente de Redis del cache
+func NewActivityService(client *redis.Client) activityPorts.ActivityManager {
+	return &ActivityService{
+		client: client,
+	}
+}
+
+// Publish registra el evento en el stream de su sucursal y lo publica a los clientes conectados
+func (s *ActivityService) Publish(ctx context.Context, event *models.Event) {
+	ctx = context.WithoutCancel(ctx)
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = utils.TimeNow()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to marshal activity event", map[string]interface{}{
+			"event": event.Type,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	id, err := publishScript.Run(ctx, s.client,
+		[]string{streamKey(event.BranchID), channelKey(event.BranchID)},
+		streamMaxLength, string(payload), streamRetention.Milliseconds(),
+	).Text()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to publish activity event", map[string]interface{}{
+			"event":    event.Type,
+			"branchID": event.BranchID,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	event.ID = id
+}
+
+// Subscribe retorna los eventos de la sucursal y los del sistema hasta que se cancele el contexto
+func (s *ActivityService) Subscribe(ctx context.Context, branchID uint, lastEventID string) (<-chan models.Event, error) {
+	// 1. Validar el ID desde el que se reanuda la conexión
+	var since *models.StreamID
+	if lastEventID = strings.TrimSpace(lastEventID); lastEventID != "" {
+		parsed, err := models.ParseStreamID(lastEventID)
+		if err != nil {
+			return nil, dte_errors.NewValidationError("InvalidFormat", "Last-Event-ID", "<milliseconds>-<sequence>", lastEventID)
+		}
+		since = &parsed
+	}
+
+	// 2. Suscribirse a los canales antes de leer el historial para no perder los eventos publicados entre ambas lecturas
+	pubsub := s.client.Subscribe(ctx, channelKey(branchID), channelKey(models.SystemBranchID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, shared_error.NewFormattedGeneralServiceWithError("ActivityService", "Subscribe", err, "FailedToSubscribeActivity")
+	}
+
+	// 3. Obtener los eventos registrados después del ID indicado
+	var history []models.Event
+	if since != nil {
+		var err error
+		history, err = s.readHistory(ctx, branchID, since.Next())
+		if err != nil {
+			_ = pubsub.Close()
+			return nil, shared_error.NewFormattedGeneralServiceWithError("ActivityService", "Subscribe", err, "FailedToGetActivityHistory")
+		}
+	}
+
+	events := make(chan models.Event, subscriberBuffer)
+	go s.forward(ctx, pubsub, history, events)
+
+	return events, nil
+}
+
+// readHistory lee los streams de la sucursal y del sistema desde start y los combina en el orden de sus IDs
+func (s *ActivityService) readHistory(ctx context.Context, branchID uint, start models.StreamID) ([]models.Event, error) {
+	var history []models.Event
+	for _, key := range []string{streamKey(branchID), streamKey(models.SystemBranchID)} {
+		messages, err := s.client.XRangeN(ctx, key, start.String(), "+", streamMaxLength).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, message := range messages {
+			payload, _ := message.Values["event"].(string)
+			event, err := decodeEvent(message.ID, payload)
+			if err != nil {
+				logs.WarnContext(ctx, "Skipping malformed activity event", map[string]interface{}{
+					"stream": key,
+					"id":     message.ID,
+					"error":  err.Error(),
+				})
+				continue
+			}
+			history = append(history, *event)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		current, _ := models.ParseStreamID(history[i].ID)
+		next, _ := models.ParseStreamID(history[j].ID)
+		return next.After(current)
+	})
+
+	return history, nil
+}
+
+// forward entrega el historial y luego los eventos en tiempo real hasta que se cancele el contexto. Los eventos que
+// ya se entregaron desde el historial se descartan comparando con el último ID entregado de cada stream
+func (s *ActivityService) forward(ctx context.Context, pubsub *redis.PubSub, history []models.Event, events chan<- models.Event) {
+	defer close(events)
+	defer pubsub.Close()
+
+	lastDelivered := make(map[uint]models.StreamID, 2)
+	deliver := func(event models.Event) bool {
+		id, err := models.ParseStreamID(event.ID)
+		if err != nil {
+			return true
+		}
+		if last, ok := lastDelivered[event.BranchID]; ok && !id.After(last) {
+			return true
+		}
+
+		select {
+		case events <- event:
+			lastDelivered[event.BranchID] = id
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for _, event := range history {
+		if !deliver(event) {
+			return
+		}
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			id, payload, found := strings.Cut(message.Payload, " ")
+			if !found {
+				continue
+			}
+
+			event, err := decodeEvent(id, payload)
+			if err != nil {
+				logs.WarnContext(ctx, "Skipping malformed activity message", map[string]interface{}{
+					"channel": message.Channel,
+					"error":   err.Error(),
+				})
+				continue
+			}
+
+			if !deliver(*event) {
+				return
+			}
+		}
+	}
+}
+
+// decodeEvent obtiene un evento a partir de su contenido y el ID asignado por el stream
+func decodeEvent(id, payload string) (*models.Event, error) {
+	var event models.Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, err
+	}
+	event.ID = id
+	return &event, nil
+}
+
+// streamKey llave del stream con el historial de eventos de una sucursal
+func streamKey(branchID uint) string {
+	if branchID == models.SystemBranchID {
+		return "activity:stream:system"
+	}
+	return fmt.Sprintf("activity:stream:branch:%d", branchID)
+}
+
+// channelKey canal pub/sub de los eventos en tiempo real de una sucursal
+func channelKey(branchID uint) string {
+	if branchID == models.SystemBranchID {
+		return "activity:channel:system"
+	}
+	return fmt.Sprintf("activity:channel:branch:%d", branchID)
+}
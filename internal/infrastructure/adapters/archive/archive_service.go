@@ -0,0 +1,483 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	archivePorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
+	pdfModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+const (
+	// archiveJobTimeout tiempo máximo para generar un archivo en segundo plano, también es la duración de la reserva del
+	// trabajo por lo que otro proceso no lo retoma mientras se genera
+	archiveJobTimeout = 2 * time.Hour
+	// manifestFilename nombre del manifiesto en la raíz del ZIP
+	manifestFilename = "manifest.json"
+	// cleanupBatchSize cantidad de archivos expirados que se eliminan por consulta
+	cleanupBatchSize = 100
+)
+
+// ArchiveService genera archivos ZIP con el JSON, el JWS firmado, la respuesta de Hacienda y opcionalmente la
+// representación gráfica de cada DTE de un período, junto a un manifiesto con los hashes y conteos del archivo
+type ArchiveService struct {
+	dteManager  dte_documents.DTEManager
+	pdfManager  pdf.PDFManager
+	repo        archivePorts.ArchiveRepositoryPort
+	storagePath string
+	retention   time.Duration
+}
+
+// NewArchiveService crea una instancia de ArchiveService, los archivos generados se almacenan en storagePath y se
+// eliminan al cumplir el tiempo de retención. Con varias instancias storagePath debe ser un volumen compartido porque
+// el archivo puede generarse en una instancia y descargarse desde otra
+func NewArchiveService(
+	dteManager dte_documents.DTEManager,
+	pdfManager pdf.PDFManager,
+	repo archivePorts.ArchiveRepositoryPort,
+	storagePath string,
+	retention time.Duration,
+) archivePorts.ArchiveManager {
+	return &ArchiveService{
+		dteManager:  dteManager,
+		pdfManager:  pdfManager,
+		repo:        repo,
+		storagePath: storagePath,
+		retention:   retention,
+	}
+}
+
+// CreateJob registra un trabajo de exportación y programa su procesamiento en segundo plano
+func (s *ArchiveService) CreateJob(ctx context.Context, job *models.ArchiveJob) (*models.ArchiveJob, error) {
+	// 1. Validar el alcance y el período del archivo
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Registrar el trabajo
+	job.ID = strings.ToUpper(uuid.New().String())
+	job.Status = models.ArchivePending
+	job.CreatedAt = utils.TimeNow()
+	if err := s.repo.Create(ctx, job); err != nil {
+		logs.ErrorContext(ctx, "Failed to create archive job", map[string]interface{}{
+			"branchID": job.BranchID,
+			"error":    err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("ArchiveService", "CreateJob", err, "FailedToCreateArchive")
+	}
+
+	// 3. Generar el archivo en segundo plano
+	go s.process(ctx, *job)
+
+	return job, nil
+}
+
+// GetJob obtiene el estado de un trabajo de exportación
+func (s *ArchiveService) GetJob(ctx context.Context, branchID uint, id string) (*models.ArchiveJob, error) {
+	return s.repo.GetByID(ctx, branchID, id)
+}
+
+// OpenArchive abre el archivo ZIP de un trabajo completado
+func (s *ArchiveService) OpenArchive(ctx context.Context, branchID uint, id string) (io.ReadCloser, *models.ArchiveJob, error) {
+	job, err := s.repo.GetByID(ctx, branchID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status == models.ArchiveExpired {
+		return nil, nil, shared_error.NewFormattedGeneralServiceError("ArchiveService", "OpenArchive", "ArchiveExpired", id)
+	}
+	if job.Status != models.ArchiveCompleted {
+		return nil, nil, shared_error.NewFormattedGeneralServiceError("ArchiveService", "OpenArchive", "ArchiveNotReady", id, job.Status)
+	}
+
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to open archive file", map[string]interface{}{
+			"jobID": id,
+			"error": err.Error(),
+		})
+		return nil, nil, shared_error.NewFormattedGeneralServiceWithError("ArchiveService", "OpenArchive", err, "FailedToOpenArchive", id)
+	}
+
+	return file, job, nil
+}
+
+// RecoverJobs vuelve a procesar los trabajos que quedaron pendientes o en proceso al detenerse la aplicación, cada
+// trabajo se reserva antes de procesarlo por lo que los trabajos que otra instancia sigue generando se omiten. Los
+// trabajos registrados hace más de archiveJobTimeout se marcan como fallidos para no reintentar indefinidamente un
+// archivo que detiene la aplicación
+func (s *ArchiveService) RecoverJobs(ctx context.Context) error {
+	// 1. Obtener los trabajos interrumpidos
+	jobs, err := s.repo.GetByStatus(ctx, models.ArchivePending, models.ArchiveProcessing)
+	if err != nil {
+		return shared_error.NewGeneralServiceError("ArchiveService", "RecoverJobs", "failed to get interrupted archive jobs", err)
+	}
+
+	// 2. Reprogramar los trabajos recientes y marcar como fallidos los demás
+	staleBefore := utils.TimeNow().Add(-archiveJobTimeout)
+	for i := range jobs {
+		job := jobs[i]
+		if job.CreatedAt.Before(staleBefore) {
+			if s.claim(ctx, &job) {
+				s.finish(ctx, &job, fmt.Errorf("archive job interrupted by a server restart"))
+			}
+			continue
+		}
+
+		logs.InfoContext(ctx, "Requeuing interrupted archive job", map[string]interface{}{
+			"jobID":  job.ID,
+			"status": job.Status,
+		})
+		go s.process(ctx, job)
+	}
+
+	return nil
+}
+
+// CleanupExpired elimina los archivos de los trabajos completados que superaron su tiempo de retención, el trabajo se
+// conserva con el estado ArchiveExpired para que su consulta indique que el archivo ya no está disponible
+func (s *ArchiveService) CleanupExpired(ctx context.Context) error {
+	expiredBefore := utils.TimeNow().Add(-s.retention)
+
+	for {
+		// 1. Obtener el siguiente lote de trabajos expirados
+		jobs, err := s.repo.GetCompletedBefore(ctx, expiredBefore, cleanupBatchSize)
+		if err != nil {
+			return shared_error.NewGeneralServiceError("ArchiveService", "CleanupExpired", "failed to get expired archive jobs", err)
+		}
+
+		// 2. Eliminar el archivo y marcar el trabajo como expirado, un archivo que ya no existe no impide expirarlo
+		for i := range jobs {
+			job := &jobs[i]
+			if err = os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+				return shared_error.NewGeneralServiceError("ArchiveService", "CleanupExpired", "failed to remove expired archive file", err)
+			}
+
+			job.Status = models.ArchiveExpired
+			job.FilePath = ""
+			if err = s.repo.Update(ctx, job); err != nil {
+				return shared_error.NewGeneralServiceError("ArchiveService", "CleanupExpired", "failed to update expired archive job", err)
+			}
+
+			logs.InfoContext(ctx, "Expired archive file removed", map[string]interface{}{
+				"jobID": job.ID,
+			})
+		}
+
+		if len(jobs) < cleanupBatchSize {
+			return nil
+		}
+	}
+}
+
+// process genera el archivo de un trabajo y registra su resultado, el contexto conserva los valores de la solicitud que
+// creó el trabajo (ID de la solicitud y traza) pero no su cancelación
+func (s *ArchiveService) process(parent context.Context, job models.ArchiveJob) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), archiveJobTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logs.ErrorContext(ctx, "Recovered from panic in archive job", map[string]interface{}{
+				"jobID": job.ID,
+				"panic": fmt.Sprint(r),
+			})
+			s.finish(ctx, &job, fmt.Errorf("archive job panic: %v", r))
+		}
+	}()
+
+	// 1. Reservar el trabajo, si otro proceso ya lo reservó no se genera de nuevo
+	if !s.claim(ctx, &job) {
+		return
+	}
+
+	// 2. Generar el archivo
+	logs.InfoContext(ctx, "Archive job started", map[string]interface{}{
+		"jobID":     job.ID,
+		"scope":     job.Scope,
+		"startDate": job.StartDate.Format("2006-01-02"),
+		"endDate":   job.EndDate.Format("2006-01-02"),
+	})
+	s.finish(ctx, &job, s.build(ctx, &job))
+}
+
+// claim reserva el trabajo hasta archiveJobTimeout y lo marca como en proceso, retorna false si la reserva falla o si
+// otro proceso tiene la reserva vigente
+func (s *ArchiveService) claim(ctx context.Context, job *models.ArchiveJob) bool {
+	now := utils.TimeNow()
+	claimed, err := s.repo.ClaimJob(ctx, job.ID, now, now.Add(archiveJobTimeout))
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to claim archive job", map[string]interface{}{
+			"jobID": job.ID,
+			"error": err.Error(),
+		})
+		return false
+	}
+	if !claimed {
+		logs.InfoContext(ctx, "Archive job already claimed by another process", map[string]interface{}{
+			"jobID": job.ID,
+		})
+		return false
+	}
+
+	job.Status = models.ArchiveProcessing
+	return true
+}
+
+// finish registra el resultado final de un trabajo
+func (s *ArchiveService) finish(ctx context.Context, job *models.ArchiveJob, buildErr error) {
+	completedAt := utils.TimeNow()
+	job.CompletedAt = &completedAt
+	job.Status = models.ArchiveCompleted
+	job.ErrorMessage = nil
+
+	if buildErr != nil {
+		logs.ErrorContext(ctx, "Archive job failed", map[string]interface{}{
+			"jobID": job.ID,
+			"error": buildErr.Error(),
+		})
+		job.Status = models.ArchiveFailed
+		job.ErrorMessage = utils.ToStringPointer(truncate(buildErr.Error(), 500))
+	} else {
+		logs.InfoContext(ctx, "Archive job completed", map[string]interface{}{
+			"jobID":     job.ID,
+			"documents": job.DocumentCount,
+			"size":      job.FileSize,
+		})
+	}
+
+	if err := s.repo.Update(ctx, job); err != nil {
+		logs.ErrorContext(ctx, "Failed to update archive job status", map[string]interface{}{
+			"jobID":  job.ID,
+			"status": job.Status,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// build escribe el ZIP del trabajo en un archivo temporal y lo renombra al completarse
+func (s *ArchiveService) build(ctx context.Context, job *models.ArchiveJob) error {
+	// 1. Crear el archivo temporal
+	if err := os.MkdirAll(s.storagePath, 0755); err != nil {
+		return err
+	}
+
+	finalPath := filepath.Join(s.storagePath, job.ID+".zip")
+	tmpPath := finalPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	hasher := sha256.New()
+	counter := &countingWriter{}
+	zw := zip.NewWriter(io.MultiWriter(file, hasher, counter))
+
+	// 2. Agregar cada documento del período
+	manifest := models.NewArchiveManifest(job, utils.TimeNow())
+	filters := &dte.DTEFilters{
+		StartDate: &job.StartDate,
+		EndDate:   &job.EndDate,
+	}
+	if job.Scope == models.ArchiveScopeNIT {
+		filters.ClientID = job.ClientID
+	} else {
+		filters.BranchID = job.BranchID
+	}
+
+	err = s.dteManager.ExportDTEs(ctx, filters, func(record *dte.DTEExportRecord) error {
+		document, err := s.addDocument(ctx, zw, record, job.IncludePDF)
+		if err != nil {
+			return err
+		}
+		manifest.AddDocument(*document)
+		return nil
+	})
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	// 3. Escribir el manifiesto y cerrar el archivo
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if _, err = writeEntry(zw, manifestFilename, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpPath, finalPath); err != nil {
+		return err
+	}
+
+	job.FilePath = finalPath
+	job.FileSize = counter.n
+	job.FileHash = hex.EncodeToString(hasher.Sum(nil))
+	job.DocumentCount = manifest.Totals.Documents
+	return nil
+}
+
+// addDocument escribe los archivos de un DTE en el ZIP, los artefactos que no existen se registran como faltantes
+func (s *ArchiveService) addDocument(ctx context.Context, zw *zip.Writer, record *dte.DTEExportRecord, includePDF bool) (*models.ManifestDocument, error) {
+	dir := fmt.Sprintf("%s/%s/", record.DTEType, record.GenerationCode)
+	document := &models.ManifestDocument{
+		GenerationCode: record.GenerationCode,
+		ControlNumber:  record.ControlNumber,
+		DTEType:        record.DTEType,
+		Status:         record.Status,
+		Transmission:   record.Transmission,
+		ReceptionStamp: record.ReceptionStamp,
+		BranchID:       record.BranchID,
+		Files:          make([]models.ManifestFile, 0, 4),
+	}
+
+	add := func(name string, content []byte) error {
+		entry, err := writeEntry(zw, dir+name, content)
+		if err != nil {
+			return err
+		}
+		document.Files = append(document.Files, *entry)
+		return nil
+	}
+
+	// 1. JSON del documento
+	if err := add("dte.json", []byte(record.JSONData)); err != nil {
+		return nil, err
+	}
+
+	// 2. JWS firmado y respuesta de Hacienda
+	artifacts, err := s.dteManager.GetArtifacts(ctx, record.GenerationCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if artifacts.SignedDocument != nil {
+		if err = add("dte.jws", []byte(*artifacts.SignedDocument)); err != nil {
+			return nil, err
+		}
+	} else {
+		document.Missing = append(document.Missing, models.ArtifactSignedDocument)
+	}
+
+	if artifacts.MHResponse != nil {
+		if err = add("mh_response.json", []byte(*artifacts.MHResponse)); err != nil {
+			return nil, err
+		}
+	} else {
+		document.Missing = append(document.Missing, models.ArtifactMHResponse)
+	}
+
+	// 3. Representación gráfica, un error al generarla no detiene el archivo
+	if !includePDF {
+		return document, nil
+	}
+
+	receipts := []string{pdfModels.ReceiptDocument}
+	if record.Status == constants.DocumentInvalid {
+		receipts = append(receipts, pdfModels.ReceiptInvalidation)
+	}
+
+	source := &dte.DTEDocument{
+		BranchID:   record.BranchID,
+		DocumentID: record.GenerationCode,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+		Details: &dte.DTEDetails{
+			ID:             record.GenerationCode,
+			DTEType:        record.DTEType,
+			ControlNumber:  record.ControlNumber,
+			ReceptionStamp: record.ReceptionStamp,
+			Transmission:   record.Transmission,
+			Status:         record.Status,
+			JSONData:       record.JSONData,
+		},
+	}
+
+	for _, receipt := range receipts {
+		content, name, err := s.pdfManager.GenerateFromDocument(ctx, source, receipt)
+		if err != nil {
+			logs.WarnContext(ctx, "Failed to generate archive PDF", map[string]interface{}{
+				"generationCode": record.GenerationCode,
+				"receipt":        receipt,
+				"error":          err.Error(),
+			})
+			document.Missing = append(document.Missing, models.ArtifactPDF)
+			continue
+		}
+		if err = add(name, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return document, nil
+}
+
+// writeEntry escribe un archivo en el ZIP y retorna su descripción para el manifiesto
+func writeEntry(zw *zip.Writer, name string, content []byte) (*models.ManifestFile, error) {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: utils.TimeNow(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(content); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	return &models.ManifestFile{
+		Path:   name,
+		Size:   len(content),
+		SHA256: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// countingWriter cuenta los bytes escritos en el archivo
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
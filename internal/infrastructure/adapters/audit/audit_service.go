@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	auditPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// ExportBatchSize cantidad de eventos leídos por consulta durante la exportación
+const ExportBatchSize = 500
+
+// AuditService registra las acciones que modifican el estado del sistema como eventos de dominio, junto con el actor
+// que las realizó, la IP y el ID de la solicitud de origen y el digest del payload
+type AuditService struct {
+	repo auditPorts.AuditRepositoryPort
+}
+
+// NewAuditService crea una instancia de AuditService. Recibe el repositorio de eventos de auditoría.
+func NewAuditService(repo auditPorts.AuditRepositoryPort) auditPorts.AuditManager {
+	return &AuditService{repo: repo}
+}
+
+// Record registra una acción. La auditoría no debe interrumpir la acción que la origina, por lo que los errores solo
+// se registran en el log y el evento se almacena aunque la solicitud de origen haya sido cancelada
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditEntry) {
+	// 1. Serializar el payload y calcular su digest
+	payload, err := marshalPayload(entry.Payload)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to marshal audit payload", map[string]interface{}{
+			"eventType": entry.EventType,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	event := &models.AuditEvent{
+		EventType:     entry.EventType,
+		ActorType:     entry.ActorType,
+		ActorID:       entry.ActorID,
+		UserID:        entry.UserID,
+		BranchID:      entry.BranchID,
+		Payload:       payload,
+		PayloadDigest: models.PayloadDigest(payload),
+		OccurredAt:    utils.TimeNow(),
+	}
+
+	// 2. Obtener el actor del contexto si la acción no lo indica
+	if event.ActorType == "" {
+		event.ActorType, event.ActorID = actorFromContext(ctx)
+	}
+
+	// 3. Obtener la IP y el ID de la solicitud de origen
+	event.IPAddress, _ = ctx.Value("client_ip").(string)
+	event.RequestID, _ = ctx.Value("request_id").(string)
+
+	// 4. Almacenar el evento
+	if err = s.repo.Create(context.WithoutCancel(ctx), event); err != nil {
+		logs.ErrorContext(ctx, "Failed to record audit event", map[string]interface{}{
+			"eventType": event.EventType,
+			"branchID":  event.BranchID,
+			"requestID": event.RequestID,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// GetEvents obtiene una página de eventos de auditoría que cumplen con los filtros
+func (s *AuditService) GetEvents(ctx context.Context, filters *models.AuditFilters) (*models.AuditListResponse, error) {
+	events, total, err := s.repo.GetPaged(ctx, filters)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("AuditService", "GetEvents", err, "FailedToGetAuditEvents")
+	}
+
+	totalPages := 0
+	if filters.PageSize > 0 {
+		totalPages = int((total + int64(filters.PageSize) - 1) / int64(filters.PageSize))
+	}
+
+	return &models.AuditListResponse{
+		Events: events,
+		Pagination: models.AuditPaginationResponse{
+			Total:      total,
+			TotalPages: totalPages,
+			Page:       filters.Page,
+			PageSize:   filters.PageSize,
+		},
+	}, nil
+}
+
+// ExportEvents recorre todos los eventos que cumplen con los filtros en lotes de tamaño fijo, de modo que el consumo
+// de memoria no depende de la cantidad de eventos exportados
+func (s *AuditService) ExportEvents(ctx context.Context, filters *models.AuditFilters, fn func(*models.AuditEvent) error) error {
+	if err := s.repo.Stream(ctx, filters, ExportBatchSize, fn); err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("AuditService", "ExportEvents", err, "FailedToExportAuditEvents")
+	}
+
+	return nil
+}
+
+// actorFromContext obtiene el actor de la solicitud. Las solicitudes autenticadas se atribuyen a la llave con la que
+// se emitió el token, las de la llave de administración al administrador y las que no provienen de una solicitud HTTP
+// a los procesos en segundo plano
+func actorFromContext(ctx context.Context) (string, uint) {
+	if claims, ok := ctx.Value("claims").(*authModels.AuthClaims); ok && claims != nil {
+		if claims.OperatorID != 0 {
+			return models.ActorOperator, claims.OperatorID
+		}
+		if claims.KeyID != 0 {
+			return models.ActorAPIKey, claims.KeyID
+		}
+		return models.ActorUser, claims.ClientID
+	}
+
+	if isAdmin, _ := ctx.Value("admin_key").(bool); isAdmin {
+		return models.ActorAdmin, 0
+	}
+
+	if _, ok := ctx.Value("request_id").(string); ok {
+		return models.ActorAnonymous, 0
+	}
+
+	return models.ActorSystem, 0
+}
+
+// marshalPayload serializa el payload del evento, un payload nulo se registra como un objeto vacío
+func marshalPayload(payload interface{}) (json.RawMessage, error) {
+	if payload == nil {
+		return json.RawMessage("{}"), nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
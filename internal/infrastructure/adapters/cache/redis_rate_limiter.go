@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+// slidingWindowScript registra un evento en un sorted set cuyo score es el instante del evento en milisegundos.
+// Descarta los eventos fuera de la ventana y solo agrega el nuevo si no se ha alcanzado el límite, todo de forma atómica.
+// Retorna {permitido, restantes, milisegundos hasta que se libere un espacio}
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, limit - count - 1, 0}
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local retry = window
+if oldest[2] then
+	retry = tonumber(oldest[2]) + window - now
+end
+return {0, 0, retry}
+`)
+
+type RedisRateLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimiter crea una nueva instancia de RedisRateLimiter
+func NewRedisRateLimiter(client *redis.Client) ports.RateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+	}
+}
+
+// Allow registra un evento para la llave si dentro de la ventana no se ha alcanzado el límite
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*ports.RateLimitResult, error) {
+	now := time.Now().UnixMilli()
+
+	values, err := slidingWindowScript.Run(ctx, l.client, []string{key}, now, window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return nil, shared_error.NewGeneralServiceError("RedisRateLimiter", "Allow", "failed to evaluate rate limit in Redis", err)
+	}
+
+	return &ports.RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// Reset elimina los eventos registrados para la llave
+func (l *RedisRateLimiter) Reset(ctx context.Context, key string) error {
+	if err := l.client.Del(ctx, key).Err(); err != nil {
+		return shared_error.NewGeneralServiceError("RedisRateLimiter", "Reset", "failed to reset rate limit in Redis", err)
+	}
+	return nil
+}
+
+// Lock bloquea la llave durante el tiempo indicado
+func (l *RedisRateLimiter) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	if err := l.client.Set(ctx, key, "1", ttl).Err(); err != nil {
+		return shared_error.NewGeneralServiceError("RedisRateLimiter", "Lock", "failed to set lock in Redis", err)
+	}
+	return nil
+}
+
+// LockedFor retorna el tiempo restante del bloqueo de la llave, cero si no está bloqueada
+func (l *RedisRateLimiter) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := l.client.PTTL(ctx, key).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, shared_error.NewGeneralServiceError("RedisRateLimiter", "LockedFor", "failed to get lock from Redis", err)
+	}
+
+	// PTTL retorna un valor negativo si la llave no existe o no tiene expiración
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
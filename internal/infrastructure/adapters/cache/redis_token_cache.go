@@ -12,6 +12,7 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 )
 
 type RedisTokenCache struct {
@@ -37,6 +38,7 @@ func NewRedisTokenCache(config *config.RedisConfig, cryptService ports.CryptMana
 	}
 
 	client := redis.NewClient(opt)
+	client.AddHook(tracing.RedisHook{})
 
 	// Verificar conexión
 	ctx := context.Background()
@@ -224,6 +226,29 @@ func (c *RedisTokenCache) Delete(token string) error {
 	return nil
 }
 
+// DeleteSession elimina de Redis las credenciales de Hacienda y el token de Hacienda asociados a un token del sistema
+func (c *RedisTokenCache) DeleteSession(token string) error {
+	keys := []string{
+		fmt.Sprintf("hacienda:credentials:%s", token),
+		fmt.Sprintf("hacienda:token:%s", token),
+	}
+
+	if err := c.client.Del(c.ctx, keys...).Err(); err != nil {
+		logs.Error("Failed to delete session from Redis", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return shared_error.NewGeneralServiceError(
+			"RedisTokenCache",
+			"DeleteSession",
+			"failed to delete session from Redis",
+			err,
+		)
+	}
+
+	logs.Info("Session deleted successfully from Redis")
+	return nil
+}
+
 // Close cierra la conexión con Redis
 func (c *RedisTokenCache) Close() error {
 	err := c.client.Close()
@@ -16,6 +16,9 @@ type CircuitBreaker struct {
 	resetTime   time.Duration
 	state       constants.State
 	mu          sync.RWMutex
+
+	// onStateChange se invoca fuera del lock después de cada cambio de estado
+	onStateChange func(from, to constants.State)
 }
 
 func NewCircuitBreaker(threshold int32, resetTime time.Duration) *CircuitBreaker {
@@ -26,12 +29,20 @@ func NewCircuitBreaker(threshold int32, resetTime time.Duration) *CircuitBreaker
 	}
 }
 
+// OnStateChange registra la función que se invoca cada vez que el circuit breaker cambia de estado
+func (cb *CircuitBreaker) OnStateChange(fn func(from, to constants.State)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onStateChange = fn
+}
+
 func (cb *CircuitBreaker) AllowRequest() bool {
-	cb.mu.RLock()
-	defer cb.mu.RUnlock()
+	// El paso de abierto a semi-abierto modifica el estado, por lo que se requiere el lock de escritura
+	cb.mu.Lock()
 
 	switch cb.state {
 	case constants.StateClosed:
+		cb.mu.Unlock()
 		return true
 	case constants.StateOpen:
 		if time.Since(cb.lastFailure) > cb.resetTime {
@@ -39,20 +50,22 @@ func (cb *CircuitBreaker) AllowRequest() bool {
 				"lastFailure": cb.lastFailure,
 				"resetTime":   cb.resetTime,
 			})
-			cb.state = constants.StateHalfOpen
+			cb.transition(constants.StateHalfOpen)
 			return true
 		}
+		cb.mu.Unlock()
 		return false
 	case constants.StateHalfOpen:
+		cb.mu.Unlock()
 		return true
 	default:
+		cb.mu.Unlock()
 		return false
 	}
 }
 
 func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
 
 	cb.failures = 0
 	if cb.state != constants.StateClosed {
@@ -60,12 +73,11 @@ func (cb *CircuitBreaker) RecordSuccess() {
 			"previousState": cb.state,
 		})
 	}
-	cb.state = constants.StateClosed
+	cb.transition(constants.StateClosed)
 }
 
 func (cb *CircuitBreaker) RecordFailure() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
 
 	cb.failures++
 	cb.lastFailure = utils.TimeNow()
@@ -75,7 +87,21 @@ func (cb *CircuitBreaker) RecordFailure() {
 			"failures":  cb.failures,
 			"threshold": cb.threshold,
 		})
-		cb.state = constants.StateOpen
+		cb.transition(constants.StateOpen)
+		return
+	}
+	cb.mu.Unlock()
+}
+
+// transition cambia el estado, libera el lock que debe tener tomado quien la invoca y notifica el cambio
+func (cb *CircuitBreaker) transition(to constants.State) {
+	from := cb.state
+	cb.state = to
+	onStateChange := cb.onStateChange
+	cb.mu.Unlock()
+
+	if from != to && onStateChange != nil {
+		onStateChange(from, to)
 	}
 }
 
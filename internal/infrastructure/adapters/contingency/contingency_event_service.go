@@ -4,13 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	"github.com/MarlonG1/api-facturacion-sv/config/drivers"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"io"
 	"net/http"
@@ -18,13 +16,13 @@ import (
 	"time"
 
 	haciendaPorts "github.com/MarlonG1/api-facturacion-sv/internal/application/ports"
-	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
-	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency/models"
 	authPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
@@ -32,8 +30,7 @@ import (
 type ContingencyEventService struct {
 	authManager  auth.AuthManager
 	haciendaAuth haciendaPorts.HaciendaAuthManager
-	cache        authPorts.CacheManager
-	tokenService authPorts.TokenManager
+	vault        vault.CredentialVault
 	signer       haciendaPorts.SignerManager
 	repo         contingency.ContingencyRepositoryPort
 	timeProvider authPorts.TimeProvider
@@ -51,8 +48,7 @@ type HaciendaContingencyRequest struct {
 func NewContingencyEventService(
 	authManager auth.AuthManager,
 	haciendaAuth haciendaPorts.HaciendaAuthManager,
-	cache authPorts.CacheManager,
-	tokenService authPorts.TokenManager,
+	credentialVault vault.CredentialVault,
 	signer haciendaPorts.SignerManager,
 	repo contingency.ContingencyRepositoryPort,
 	timeProvider authPorts.TimeProvider,
@@ -61,19 +57,18 @@ func NewContingencyEventService(
 	return &ContingencyEventService{
 		authManager:  authManager,
 		haciendaAuth: haciendaAuth,
-		cache:        cache,
-		tokenService: tokenService,
+		vault:        credentialVault,
 		signer:       signer,
 		repo:         repo,
 		timeProvider: timeProvider,
 		connection:   connection,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
+			Transport: tracing.NewTransport(&http.Transport{
 				MaxIdleConns:       100,
 				IdleConnTimeout:    90 * time.Second,
 				DisableCompression: true,
-			},
+			}),
 		},
 	}
 }
@@ -120,7 +115,7 @@ func (s *ContingencyEventService) PrepareAndSendContingencyEvent(ctx context.Con
 		Reason:     reason,
 	}
 
-	return s.sendContingencyEvent(ctx, event, docs[0].BranchID)
+	return s.sendContingencyEvent(ctx, event)
 }
 
 // prepareDTEDetails prepara los detalles de los documentos para el evento de contingencia
@@ -156,19 +151,15 @@ func (s *ContingencyEventService) prepareContingencyReason(doc dte.ContingencyDo
 }
 
 // sendContingencyEvent envía el evento de contingencia a Hacienda
-func (s *ContingencyEventService) sendContingencyEvent(ctx context.Context, event *models.ContingencyEvent, branchID uint) error {
-	// Obtener el client y generar token del sistema
+func (s *ContingencyEventService) sendContingencyEvent(ctx context.Context, event *models.ContingencyEvent) error {
+	// Obtener el client
 	client, err := s.authManager.GetByNIT(ctx, event.Issuer.NIT)
 	if err != nil {
 		return shared_error.NewGeneralServiceError("ContingencyEventService", "sendContingencyEvent", "failed to get client", err)
 	}
-	token, err := s.generateMatchingToken(client, branchID)
-	if err != nil {
-		return shared_error.NewGeneralServiceError("ContingencyEventService", "sendContingencyEvent", "failed to generate matching token", err)
-	}
 
-	// Obtener credenciales
-	encryptedCreds, err := s.cache.GetCredentials(token)
+	// Obtener credenciales del vault
+	creds, err := s.vault.Get(ctx, client.ID)
 	if err != nil {
 		return shared_error.NewGeneralServiceError("ContingencyEventService", "sendContingencyEvent", "failed to get hacienda credentials", err)
 	}
@@ -176,8 +167,8 @@ func (s *ContingencyEventService) sendContingencyEvent(ctx context.Context, even
 	// Obtener token de Hacienda
 	haciendaToken, err := s.haciendaAuth.GetOrCreateHaciendaTokenWithCreds(
 		ctx,
-		token,
-		*encryptedCreds,
+		vault.HaciendaTokenKey(client.ID),
+		*creds,
 	)
 
 	if err != nil {
@@ -212,7 +203,7 @@ func (s *ContingencyEventService) sendContingencyEvent(ctx context.Context, even
 	req.Header.Set("Authorization", haciendaToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	logs.Info("Sending contingency event request", map[string]interface{}{
+	logs.InfoContext(ctx, "Sending contingency event request", map[string]interface{}{
 		"url":          config.MHPaths.ContingencyURL,
 		"method":       "POST",
 		"content-type": req.Header.Get("Content-Type"),
@@ -228,14 +219,14 @@ func (s *ContingencyEventService) sendContingencyEvent(ctx context.Context, even
 	var responseBody map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
 		if err == io.EOF {
-			logs.Warn("Contingency event response is empty")
+			logs.WarnContext(ctx, "Contingency event response is empty")
 			responseBody = nil
 		} else {
 			return shared_error.NewGeneralServiceError("ContingencyEventService", "sendContingencyEvent", "failed to decode response body", err)
 		}
 	}
 
-	logs.Info("Contingency event response", map[string]interface{}{
+	logs.InfoContext(ctx, "Contingency event response", map[string]interface{}{
 		"statusCode": resp.StatusCode,
 		"body":       responseBody,
 	})
@@ -246,39 +237,3 @@ func (s *ContingencyEventService) sendContingencyEvent(ctx context.Context, even
 
 	return nil
 }
-
-// generateMatchingToken genera un token para el cliente
-func (s *ContingencyEventService) generateMatchingToken(client *user.User, branchID uint) (string, error) {
-	key := fmt.Sprintf("token:timestamps:%d", client.ID)
-	var timestamps struct {
-		IssuedAt  int64 `json:"IssuedAt"`
-		ExpiresAt int64 `json:"ExpiresAt"`
-	}
-
-	jsonTimestamps, err := s.cache.Get(key)
-	if err != nil {
-		return "", err
-	}
-
-	if err = json.Unmarshal([]byte(jsonTimestamps), &timestamps); err != nil {
-		return "", err
-	}
-
-	claims := &authModels.AuthClaims{
-		ClientID: client.ID,
-		AuthType: client.AuthType,
-		BranchID: branchID,
-		NIT:      client.NIT,
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":        claims.ClientID,
-		"branch_sub": claims.BranchID,
-		"auth_type":  claims.AuthType,
-		"nit":        claims.NIT,
-		"exp":        timestamps.ExpiresAt,
-		"iat":        timestamps.IssuedAt,
-	})
-
-	return token.SignedString([]byte(s.tokenService.GetSecretKey()))
-}
@@ -0,0 +1,308 @@
+package crypt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gtank/cryptopasta"
+
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+// rotationBatchSize cantidad de credenciales que se procesan por consulta al rotar la llave maestra
+const rotationBatchSize = 100
+
+// CredentialVaultService cifra las credenciales de Hacienda con envelope encryption, cada registro posee una llave de
+// datos aleatoria que a su vez se cifra con la llave maestra activa del servidor
+type CredentialVaultService struct {
+	repo          vault.CredentialVaultRepositoryPort
+	masterKeys    map[string]*[32]byte
+	activeVersion string
+}
+
+// NewCredentialVaultService crea una instancia de CredentialVaultService. Recibe las llaves maestras por versión y la
+// versión con la que se cifran las nuevas credenciales
+func NewCredentialVaultService(repo vault.CredentialVaultRepositoryPort, masterKeys map[string][]byte, activeVersion string) (vault.CredentialVault, error) {
+	keys := make(map[string]*[32]byte, len(masterKeys))
+	for version, key := range masterKeys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("vault master key %s must be 32 bytes long", version)
+		}
+		var masterKey [32]byte
+		copy(masterKey[:], key)
+		keys[version] = &masterKey
+	}
+
+	if _, ok := keys[activeVersion]; !ok {
+		return nil, fmt.Errorf("vault master key %s is not configured", activeVersion)
+	}
+
+	return &CredentialVaultService{
+		repo:          repo,
+		masterKeys:    keys,
+		activeVersion: activeVersion,
+	}, nil
+}
+
+// Store cifra y almacena las credenciales de Hacienda del usuario, reemplazando las anteriores
+func (s *CredentialVaultService) Store(ctx context.Context, userID uint, creds *authModels.HaciendaCredentials) error {
+	// 1. Cifrar las credenciales con una llave de datos nueva
+	plain, err := json.Marshal(creds)
+	if err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Store", err, "FailedToStoreCredentials")
+	}
+
+	dataKey := cryptopasta.NewEncryptionKey()
+	ciphertext, err := cryptopasta.Encrypt(plain, dataKey)
+	if err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Store", err, "FailedToStoreCredentials")
+	}
+
+	// 2. Cifrar la llave de datos con la llave maestra activa
+	wrappedKey, err := cryptopasta.Encrypt(dataKey[:], s.masterKeys[s.activeVersion])
+	if err != nil {
+		return shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Store", err, "FailedToStoreCredentials")
+	}
+
+	// 3. Almacenar las credenciales cifradas
+	sealed := &models.SealedCredentials{
+		UserID:     userID,
+		KeyVersion: s.activeVersion,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}
+	if err = s.repo.Save(ctx, sealed); err != nil {
+		logs.ErrorContext(ctx, "Failed to store Hacienda credentials in vault", map[string]interface{}{
+			"userID": userID,
+			"error":  err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Store", err, "FailedToStoreCredentials")
+	}
+
+	return nil
+}
+
+// Get obtiene y descifra las credenciales de Hacienda del usuario
+func (s *CredentialVaultService) Get(ctx context.Context, userID uint) (*authModels.HaciendaCredentials, error) {
+	sealed, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Descifrar la llave de datos con la llave maestra de su versión
+	dataKey, err := s.unwrapKey(sealed)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to unwrap Hacienda credentials key", map[string]interface{}{
+			"userID":     userID,
+			"keyVersion": sealed.KeyVersion,
+			"error":      err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Get", err, "FailedToGetCredentials")
+	}
+
+	// 2. Descifrar las credenciales
+	ciphertext, err := base64.StdEncoding.DecodeString(sealed.Ciphertext)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Get", err, "FailedToGetCredentials")
+	}
+
+	plain, err := cryptopasta.Decrypt(ciphertext, dataKey)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Get", err, "FailedToGetCredentials")
+	}
+
+	var creds authModels.HaciendaCredentials
+	if err = json.Unmarshal(plain, &creds); err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("CredentialVault", "Get", err, "FailedToGetCredentials")
+	}
+
+	return &creds, nil
+}
+
+// RotateKeys vuelve a cifrar con la llave maestra activa las llaves de datos de las credenciales y los secrets cifrados
+// con versiones anteriores. Al finalizar sin registros pendientes, las versiones anteriores pueden retirarse de las llaves
+// maestras configuradas
+func (s *CredentialVaultService) RotateKeys(ctx context.Context) (int, error) {
+	// 1. Volver a cifrar las llaves de datos de las credenciales de Hacienda
+	rotated, err := s.rotateCredentials(ctx)
+	if err != nil {
+		return rotated, err
+	}
+
+	// 2. Volver a cifrar los secrets de firma de las sucursales, las llaves adicionales y los webhooks
+	for _, source := range models.SealedSecretSources {
+		count, err := s.rotateSecrets(ctx, source)
+		rotated += count
+		if err != nil {
+			return rotated, err
+		}
+	}
+
+	return rotated, nil
+}
+
+// rotateCredentials vuelve a cifrar con la llave maestra activa las llaves de datos cifradas con versiones anteriores.
+// Las credenciales no se vuelven a cifrar, solo su llave de datos
+func (s *CredentialVaultService) rotateCredentials(ctx context.Context) (int, error) {
+	rotated := 0
+
+	for {
+		outdated, err := s.repo.GetByOutdatedKey(ctx, s.activeVersion, rotationBatchSize)
+		if err != nil {
+			return rotated, shared_error.NewGeneralServiceError("CredentialVault", "RotateKeys", "failed to get outdated credentials", err)
+		}
+
+		updated := 0
+		for i := range outdated {
+			if err = s.rewrapKey(ctx, &outdated[i]); err != nil {
+				logs.WarnContext(ctx, "Failed to rotate Hacienda credentials key", map[string]interface{}{
+					"userID":     outdated[i].UserID,
+					"keyVersion": outdated[i].KeyVersion,
+					"error":      err.Error(),
+				})
+				continue
+			}
+			updated++
+		}
+		rotated += updated
+
+		// Los registros que no pudieron rotarse se vuelven a obtener en la siguiente consulta, se detiene si no hubo avance
+		if len(outdated) < rotationBatchSize || updated == 0 {
+			return rotated, nil
+		}
+	}
+}
+
+// rotateSecrets vuelve a cifrar con la llave maestra activa los secrets de una ubicación cifrados con versiones anteriores,
+// los registros se recorren en orden de ID por lo que uno que no pueda descifrarse no detiene la rotación de los demás
+func (s *CredentialVaultService) rotateSecrets(ctx context.Context, source models.SealedSecretSource) (int, error) {
+	rotated := 0
+	var afterID uint
+
+	for {
+		outdated, err := s.repo.GetOutdatedSecrets(ctx, source, s.activeVersion, afterID, rotationBatchSize)
+		if err != nil {
+			return rotated, shared_error.NewGeneralServiceError("CredentialVault", "RotateKeys", "failed to get outdated secrets", err)
+		}
+
+		for i := range outdated {
+			afterID = outdated[i].ID
+			updated, err := s.resealSecret(ctx, &outdated[i])
+			if err != nil {
+				logs.WarnContext(ctx, "Failed to rotate sealed secret", map[string]interface{}{
+					"source": source,
+					"id":     outdated[i].ID,
+					"error":  err.Error(),
+				})
+				continue
+			}
+			if updated {
+				rotated++
+			}
+		}
+
+		if len(outdated) < rotationBatchSize {
+			return rotated, nil
+		}
+	}
+}
+
+// resealSecret descifra un secret con la llave maestra de su versión y lo vuelve a cifrar con la llave activa. Si el secret
+// cambió mientras tanto, por ejemplo al rotar las llaves de la sucursal, el valor nuevo ya está cifrado con la llave activa
+func (s *CredentialVaultService) resealSecret(ctx context.Context, secret *models.SealedSecret) (bool, error) {
+	plain, err := s.OpenSecret(secret.Value)
+	if err != nil {
+		return false, err
+	}
+
+	sealed, err := s.SealSecret(plain)
+	if err != nil {
+		return false, err
+	}
+
+	return s.repo.UpdateSecret(ctx, secret, sealed)
+}
+
+// SealSecret cifra un secret con la llave maestra activa, el resultado incluye la versión de la llave para poder
+// descifrarlo después de rotarla mientras la versión anterior siga configurada
+func (s *CredentialVaultService) SealSecret(secret string) (string, error) {
+	ciphertext, err := cryptopasta.Encrypt([]byte(secret), s.masterKeys[s.activeVersion])
+	if err != nil {
+		return "", shared_error.NewGeneralServiceError("CredentialVault", "SealSecret", "error encrypting secret", err)
+	}
+
+	return s.activeVersion + models.SealedSecretSeparator + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// OpenSecret descifra un secret cifrado con SealSecret con la llave maestra de su versión
+func (s *CredentialVaultService) OpenSecret(sealed string) (string, error) {
+	version, encoded, found := strings.Cut(sealed, models.SealedSecretSeparator)
+	if !found {
+		return "", shared_error.NewGeneralServiceError("CredentialVault", "OpenSecret", "invalid sealed secret format", nil)
+	}
+
+	masterKey, ok := s.masterKeys[version]
+	if !ok {
+		return "", shared_error.NewGeneralServiceError("CredentialVault", "OpenSecret", fmt.Sprintf("vault master key %s is not configured", version), nil)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", shared_error.NewGeneralServiceError("CredentialVault", "OpenSecret", "error decoding sealed secret", err)
+	}
+
+	secret, err := cryptopasta.Decrypt(ciphertext, masterKey)
+	if err != nil {
+		return "", shared_error.NewGeneralServiceError("CredentialVault", "OpenSecret", "error decrypting secret", err)
+	}
+
+	return string(secret), nil
+}
+
+// rewrapKey cifra la llave de datos de un registro con la llave maestra activa
+func (s *CredentialVaultService) rewrapKey(ctx context.Context, sealed *models.SealedCredentials) error {
+	dataKey, err := s.unwrapKey(sealed)
+	if err != nil {
+		return err
+	}
+
+	wrappedKey, err := cryptopasta.Encrypt(dataKey[:], s.masterKeys[s.activeVersion])
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateWrappedKey(ctx, sealed.UserID, s.activeVersion, base64.StdEncoding.EncodeToString(wrappedKey))
+}
+
+// unwrapKey descifra la llave de datos de un registro con la llave maestra de su versión
+func (s *CredentialVaultService) unwrapKey(sealed *models.SealedCredentials) (*[32]byte, error) {
+	masterKey, ok := s.masterKeys[sealed.KeyVersion]
+	if !ok {
+		return nil, fmt.Errorf("vault master key %s is not configured", sealed.KeyVersion)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(sealed.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plainKey, err := cryptopasta.Decrypt(wrappedKey, masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plainKey) != 32 {
+		return nil, fmt.Errorf("invalid data key length")
+	}
+
+	var dataKey [32]byte
+	copy(dataKey[:], plainKey)
+	return &dataKey, nil
+}
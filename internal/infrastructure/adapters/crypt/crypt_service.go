@@ -3,6 +3,7 @@ package crypt
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
 type CryptService struct{}
@@ -37,6 +39,28 @@ func (cs *CryptService) GenerateAPISecret() (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
+// HashAPISecret calcula el hash con el que se almacena un API secret
+func (cs *CryptService) HashAPISecret(secret string) string {
+	return utils.HashAPISecret(secret)
+}
+
+// VerifyAPISecret compara en tiempo constante un API secret con el hash almacenado
+func (cs *CryptService) VerifyAPISecret(secret, hashed string) bool {
+	if secret == "" || !utils.IsHashedAPISecret(hashed) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(utils.HashAPISecret(secret)), []byte(hashed)) == 1
+}
+
+// VerifyRequestSignature compara en tiempo constante la firma HMAC de una solicitud con la calculada con el API secret,
+// la firma debe enviarse en hexadecimal en minúsculas
+func (cs *CryptService) VerifyRequestSignature(secret, payload, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(utils.SignRequest(secret, payload)), []byte(signature)) == 1
+}
+
 // DeriveKeyFromToken deriva una clave de un token dado a través de SHA-256
 func (cs *CryptService) deriveKeyFromToken(token string) *[32]byte {
 	hash := sha256.Sum256([]byte(token))
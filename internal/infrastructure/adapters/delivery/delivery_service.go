@@ -0,0 +1,318 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	deliveryPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
+	pdfModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// asyncDeliveryTimeout tiempo máximo para completar un envío automático en segundo plano
+const asyncDeliveryTimeout = 2 * time.Minute
+
+// deliveryDocument contiene los campos del JSON del DTE necesarios para construir el correo
+type deliveryDocument struct {
+	Identificacion struct {
+		Ambiente string `json:"ambiente"`
+		FecEmi   string `json:"fecEmi"`
+		HorEmi   string `json:"horEmi"`
+	} `json:"identificacion"`
+	Emisor struct {
+		Nombre          string  `json:"nombre"`
+		NombreComercial *string `json:"nombreComercial"`
+	} `json:"emisor"`
+	Receptor struct {
+		Nombre *string `json:"nombre"`
+		Correo *string `json:"correo"`
+	} `json:"receptor"`
+	Resumen struct {
+		TotalPagar          float64 `json:"totalPagar"`
+		MontoTotalOperacion float64 `json:"montoTotalOperacion"`
+		TotalIvaRetenido    float64 `json:"totalIVAretenido"`
+	} `json:"resumen"`
+}
+
+// DeliveryService entrega el JSON y la representación gráfica de los DTE a sus receptores por correo electrónico
+type DeliveryService struct {
+	dteManager dte_documents.DTEManager
+	pdfManager pdf.PDFManager
+	repo       deliveryPorts.DeliveryRepositoryPort
+	transport  deliveryPorts.MailTransport
+	from       string
+	fromName   string
+	autoSend   bool
+}
+
+// NewDeliveryService crea una instancia de DeliveryService, si el transporte es nil el envío de correos queda deshabilitado
+func NewDeliveryService(
+	dteManager dte_documents.DTEManager,
+	pdfManager pdf.PDFManager,
+	repo deliveryPorts.DeliveryRepositoryPort,
+	transport deliveryPorts.MailTransport,
+	from string,
+	fromName string,
+	autoSend bool,
+) deliveryPorts.DeliveryManager {
+	return &DeliveryService{
+		dteManager: dteManager,
+		pdfManager: pdfManager,
+		repo:       repo,
+		transport:  transport,
+		from:       from,
+		fromName:   fromName,
+		autoSend:   autoSend,
+	}
+}
+
+// DeliverDTE envía el JSON y la representación gráfica de un DTE, si no se indican destinatarios se utiliza el correo del receptor
+func (s *DeliveryService) DeliverDTE(ctx context.Context, branchID uint, generationCode string, recipients []string) (*models.DeliveryResult, error) {
+	if s.transport == nil {
+		return nil, shared_error.NewFormattedGeneralServiceError("DeliveryService", "DeliverDTE", "MailDeliveryDisabled")
+	}
+
+	// 1. Obtener el DTE y validar que pueda ser entregado
+	document, err := s.dteManager.GetByGenerationCode(ctx, branchID, generationCode)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("DeliveryService", "DeliverDTE", err, "FailedToGetDTE", generationCode)
+	}
+
+	status := document.Details.Status
+	if status != constants.DocumentReceived && status != constants.DocumentInvalid {
+		return nil, shared_error.NewFormattedGeneralServiceError("DeliveryService", "DeliverDTE", "DocumentNotDeliverable", generationCode, status)
+	}
+
+	var content deliveryDocument
+	if err = json.Unmarshal([]byte(document.Details.JSONData), &content); err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("DeliveryService", "DeliverDTE", err, "FailedToBuildEmail", generationCode)
+	}
+
+	// 2. Determinar y validar los destinatarios
+	if len(recipients) == 0 {
+		if email := utils.PointerToString(content.Receptor.Correo); email != "" {
+			recipients = []string{email}
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, shared_error.NewFormattedGeneralServiceError("DeliveryService", "DeliverDTE", "RecipientNotFound", generationCode)
+	}
+
+	for i, recipient := range recipients {
+		address, err := mail.ParseAddress(strings.TrimSpace(recipient))
+		if err != nil {
+			return nil, dte_errors.NewValidationError("InvalidFormat", "recipients", "email", recipient)
+		}
+		recipients[i] = address.Address
+	}
+
+	// 3. Construir el correo con sus adjuntos
+	message, err := s.buildMessage(ctx, document.Details.DTEType, &content, document)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to build DTE email", map[string]interface{}{
+			"generationCode": generationCode,
+			"error":          err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("DeliveryService", "DeliverDTE", err, "FailedToBuildEmail", generationCode)
+	}
+
+	// 4. Enviar el correo a cada destinatario registrando el estado del envío
+	result := &models.DeliveryResult{GenerationCode: generationCode}
+	for _, recipient := range recipients {
+		record := &models.Delivery{
+			BranchID:       branchID,
+			GenerationCode: generationCode,
+			DTEType:        document.Details.DTEType,
+			Recipient:      recipient,
+			Subject:        message.Subject,
+		}
+		if err = s.repo.Create(ctx, record); err != nil {
+			logs.ErrorContext(ctx, "Failed to register DTE delivery", map[string]interface{}{
+				"generationCode": generationCode,
+				"error":          err.Error(),
+			})
+			return nil, shared_error.NewFormattedGeneralServiceWithError("DeliveryService", "DeliverDTE", err, "FailedToRegisterDelivery", generationCode)
+		}
+
+		s.send(ctx, message, record)
+		if record.Status == models.DeliverySent {
+			result.Sent++
+		} else {
+			result.Failed++
+		}
+		result.Deliveries = append(result.Deliveries, *record)
+	}
+
+	return result, nil
+}
+
+// DeliverDTEAsync programa el envío automático de un DTE en segundo plano, solo si el envío automático está habilitado
+func (s *DeliveryService) DeliverDTEAsync(ctx context.Context, branchID uint, generationCode string) {
+	if s.transport == nil || !s.autoSend {
+		return
+	}
+
+	parent := tracing.Detach(ctx)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logs.ErrorContext(ctx, "Recovered from panic in DTE delivery", map[string]interface{}{
+					"generationCode": generationCode,
+					"panic":          fmt.Sprint(r),
+				})
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(parent, asyncDeliveryTimeout)
+		defer cancel()
+
+		result, err := s.DeliverDTE(ctx, branchID, generationCode, nil)
+		if err != nil {
+			logs.WarnContext(ctx, "Automatic DTE delivery was not completed", map[string]interface{}{
+				"generationCode": generationCode,
+				"error":          err.Error(),
+			})
+			return
+		}
+
+		logs.InfoContext(ctx, "Automatic DTE delivery completed", map[string]interface{}{
+			"generationCode": generationCode,
+			"sent":           result.Sent,
+			"failed":         result.Failed,
+		})
+	}()
+}
+
+// GetDeliveries obtiene el historial de envíos de un DTE
+func (s *DeliveryService) GetDeliveries(ctx context.Context, branchID uint, generationCode string) ([]models.Delivery, error) {
+	deliveries, err := s.repo.GetByDocument(ctx, branchID, generationCode)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to get DTE deliveries", map[string]interface{}{
+			"generationCode": generationCode,
+			"error":          err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("DeliveryService", "GetDeliveries", err, "FailedToGetDeliveries", generationCode)
+	}
+
+	return deliveries, nil
+}
+
+// send envía el correo a un destinatario y actualiza el estado del envío
+func (s *DeliveryService) send(ctx context.Context, message *models.EmailMessage, record *models.Delivery) {
+	recipientMessage := *message
+	recipientMessage.To = []string{record.Recipient}
+
+	record.Status = models.DeliverySent
+	record.ErrorMessage = nil
+	if err := s.transport.Send(ctx, &recipientMessage); err != nil {
+		logs.ErrorContext(ctx, "Failed to send DTE email", map[string]interface{}{
+			"generationCode": record.GenerationCode,
+			"transport":      s.transport.Name(),
+			"error":          err.Error(),
+		})
+		record.Status = models.DeliveryFailed
+		record.ErrorMessage = utils.ToStringPointer(err.Error())
+	}
+
+	if err := s.repo.UpdateStatus(ctx, record.ID, record.Status, record.ErrorMessage); err != nil {
+		logs.ErrorContext(ctx, "Failed to update DTE delivery status", map[string]interface{}{
+			"deliveryID": record.ID,
+			"status":     record.Status,
+			"error":      err.Error(),
+		})
+	}
+	record.DeliveryAt = utils.TimeNow()
+}
+
+// buildMessage construye el correo de un DTE con el JSON y la representación gráfica como adjuntos
+func (s *DeliveryService) buildMessage(ctx context.Context, dteType string, content *deliveryDocument, document *dte.DTEDocument) (*models.EmailMessage, error) {
+	details := document.Details
+	invalidated := details.Status == constants.DocumentInvalid
+
+	// 1. Generar la representación gráfica del documento y, si aplica, el comprobante de invalidación
+	pdfContent, pdfName, err := s.pdfManager.GenerateFromDocument(ctx, document, pdfModels.ReceiptDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []models.Attachment{
+		{Filename: fmt.Sprintf("%s.json", details.ControlNumber), ContentType: "application/json", Content: []byte(details.JSONData)},
+		{Filename: pdfName, ContentType: "application/pdf", Content: pdfContent},
+	}
+
+	if invalidated {
+		receipt, receiptName, err := s.pdfManager.GenerateFromDocument(ctx, document, pdfModels.ReceiptInvalidation)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, models.Attachment{Filename: receiptName, ContentType: "application/pdf", Content: receipt})
+	}
+
+	// 2. Preparar los datos de la plantilla
+	emissionDate, err := time.Parse("2006-01-02", content.Identificacion.FecEmi)
+	if err != nil {
+		emissionDate = document.CreatedAt
+	}
+
+	issuerName := content.Emisor.Nombre
+	if commercialName := utils.PointerToString(content.Emisor.NombreComercial); commercialName != "" {
+		issuerName = commercialName
+	}
+
+	receiverName := utils.PointerToString(content.Receptor.Nombre)
+	if receiverName == "" {
+		receiverName = "cliente"
+	}
+
+	total := content.Resumen.TotalPagar
+	if total == 0 {
+		total = content.Resumen.MontoTotalOperacion
+	}
+	if total == 0 {
+		total = content.Resumen.TotalIvaRetenido
+	}
+
+	data := emailData{
+		IssuerName:     issuerName,
+		ReceiverName:   receiverName,
+		ControlNumber:  details.ControlNumber,
+		GenerationCode: details.ID,
+		ReceptionStamp: utils.PointerToString(details.ReceptionStamp),
+		EmissionDate:   strings.TrimSpace(content.Identificacion.FecEmi + " " + content.Identificacion.HorEmi),
+		QRLink:         response.GenerateQRLink(content.Identificacion.Ambiente, details.ID, emissionDate),
+		Invalidated:    invalidated,
+	}
+	if total > 0 {
+		data.Total = fmt.Sprintf("$%.2f", total)
+	}
+
+	// 3. Generar el asunto y los cuerpos del correo
+	subject, text, html, err := renderEmail(dteType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EmailMessage{
+		From:        s.from,
+		FromName:    s.fromName,
+		Subject:     subject,
+		TextBody:    text,
+		HTMLBody:    html,
+		Attachments: attachments,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package delivery
+
+import (
+	"bytes"
+	htmlTemplate "html/template"
+	textTemplate "text/template"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+)
+
+// emailTemplate define el asunto y la introducción del correo para un tipo de DTE
+type emailTemplate struct {
+	Name    string
+	Subject string
+	Intro   string
+}
+
+// emailData contiene la información del DTE disponible en las plantillas
+type emailData struct {
+	Template       emailTemplate
+	IssuerName     string
+	ReceiverName   string
+	ControlNumber  string
+	GenerationCode string
+	ReceptionStamp string
+	EmissionDate   string
+	Total          string
+	QRLink         string
+	Invalidated    bool
+}
+
+// emailTemplates plantillas de correo por tipo de DTE
+var emailTemplates = map[string]emailTemplate{
+	constants.FacturaElectronica: {
+		Name:    "Factura electrónica",
+		Subject: "Factura electrónica {{.ControlNumber}} - {{.IssuerName}}",
+		Intro:   "le hacemos llegar la factura electrónica emitida a su nombre",
+	},
+	constants.CCFElectronico: {
+		Name:    "Comprobante de crédito fiscal",
+		Subject: "Comprobante de crédito fiscal {{.ControlNumber}} - {{.IssuerName}}",
+		Intro:   "le hacemos llegar el comprobante de crédito fiscal emitido a su nombre",
+	},
+	constants.NotaCreditoElectronica: {
+		Name:    "Nota de crédito",
+		Subject: "Nota de crédito {{.ControlNumber}} - {{.IssuerName}}",
+		Intro:   "le hacemos llegar la nota de crédito que ajusta documentos emitidos previamente a su nombre",
+	},
+	constants.ComprobanteRetencionElectronico: {
+		Name:    "Comprobante de retención",
+		Subject: "Comprobante de retención {{.ControlNumber}} - {{.IssuerName}}",
+		Intro:   "le hacemos llegar el comprobante de retención de IVA emitido a su nombre",
+	},
+}
+
+// defaultEmailTemplate plantilla utilizada para los tipos de DTE sin plantilla propia
+var defaultEmailTemplate = emailTemplate{
+	Name:    "Documento tributario electrónico",
+	Subject: "Documento tributario electrónico {{.ControlNumber}} - {{.IssuerName}}",
+	Intro:   "le hacemos llegar el documento tributario electrónico emitido a su nombre",
+}
+
+var textBodyTemplate = textTemplate.Must(textTemplate.New("text").Parse(`Estimado(a) {{.ReceiverName}},
+
+Por medio del presente, {{.IssuerName}} {{.Template.Intro}}.
+{{- if .Invalidated}}
+
+Este documento fue INVALIDADO ante el Ministerio de Hacienda y no posee validez tributaria, se adjunta el comprobante de invalidación.
+{{- end}}
+
+{{.Template.Name}}
+Número de control: {{.ControlNumber}}
+Código de generación: {{.GenerationCode}}
+Sello de recepción: {{.ReceptionStamp}}
+Fecha de emisión: {{.EmissionDate}}
+{{- if .Total}}
+Total: {{.Total}}
+{{- end}}
+
+Se adjuntan el documento en formato JSON y su representación gráfica en PDF.
+Puede verificar el documento en el portal de consulta pública del Ministerio de Hacienda:
+{{.QRLink}}
+
+Este es un correo generado automáticamente, por favor no responda a este mensaje.
+`))
+
+var htmlBodyTemplate = htmlTemplate.Must(htmlTemplate.New("html").Parse(`<!DOCTYPE html>
+<html lang="es">
+<body style="font-family: Arial, Helvetica, sans-serif; color: #333333; font-size: 14px;">
+<p>Estimado(a) <strong>{{.ReceiverName}}</strong>,</p>
+<p>Por medio del presente, <strong>{{.IssuerName}}</strong> {{.Template.Intro}}.</p>
+{{- if .Invalidated}}
+<p style="color: #B00020;"><strong>Este documento fue INVALIDADO ante el Ministerio de Hacienda y no posee validez tributaria</strong>, se adjunta el comprobante de invalidación.</p>
+{{- end}}
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse; font-size: 13px;">
+<tr><td colspan="2" style="background: #1F3864; color: #FFFFFF;"><strong>{{.Template.Name}}</strong></td></tr>
+<tr><td><strong>Número de control</strong></td><td>{{.ControlNumber}}</td></tr>
+<tr><td><strong>Código de generación</strong></td><td>{{.GenerationCode}}</td></tr>
+<tr><td><strong>Sello de recepción</strong></td><td>{{.ReceptionStamp}}</td></tr>
+<tr><td><strong>Fecha de emisión</strong></td><td>{{.EmissionDate}}</td></tr>
+{{- if .Total}}
+<tr><td><strong>Total</strong></td><td>{{.Total}}</td></tr>
+{{- end}}
+</table>
+<p>Se adjuntan el documento en formato JSON y su representación gráfica en PDF.</p>
+<p><a href="{{.QRLink}}">Verificar el documento en el portal de consulta pública del Ministerio de Hacienda</a></p>
+<p style="font-size: 11px; color: #777777;">Este es un correo generado automáticamente, por favor no responda a este mensaje.</p>
+</body>
+</html>
+`))
+
+// renderEmail genera el asunto y los cuerpos del correo para el tipo de DTE indicado
+func renderEmail(dteType string, data emailData) (subject, text, html string, err error) {
+	template, ok := emailTemplates[dteType]
+	if !ok {
+		template = defaultEmailTemplate
+	}
+	data.Template = template
+
+	// 1. Asunto
+	subjectTemplate, err := textTemplate.New("subject").Parse(template.Subject)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var buf bytes.Buffer
+	if err = subjectTemplate.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	subject = buf.String()
+	if data.Invalidated {
+		subject = "[INVALIDADO] " + subject
+	}
+
+	// 2. Cuerpo en texto plano
+	buf.Reset()
+	if err = textBodyTemplate.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	text = buf.String()
+
+	// 3. Cuerpo en HTML
+	buf.Reset()
+	if err = htmlBodyTemplate.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	html = buf.String()
+
+	return subject, text, html, nil
+}
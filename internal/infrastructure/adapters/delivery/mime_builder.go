@@ -0,0 +1,123 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+)
+
+// buildMIMEMessage construye el contenido RFC 5322 de un correo con cuerpo alternativo (texto y HTML) y archivos adjuntos
+func buildMIMEMessage(message *models.EmailMessage, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// 1. Encabezados principales
+	from := (&mail.Address{Name: message.FromName, Address: message.From}).String()
+	domain := message.From[strings.LastIndex(message.From, "@")+1:]
+
+	writeHeader(&buf, "From", from)
+	writeHeader(&buf, "To", strings.Join(message.To, ", "))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	writeHeader(&buf, "Date", date.Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domain))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	// 2. Contenedor principal de cuerpo y adjuntos
+	mixed := multipart.NewWriter(&buf)
+	writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", mixed.Boundary()))
+	buf.WriteString("\r\n")
+
+	// 3. Cuerpo alternativo en texto plano y HTML
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	if err := writeQuotedPart(alternative, "text/plain; charset=utf-8", message.TextBody); err != nil {
+		return nil, err
+	}
+	if message.HTMLBody != "" {
+		if err := writeQuotedPart(alternative, "text/html; charset=utf-8", message.HTMLBody); err != nil {
+			return nil, err
+		}
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+
+	bodyPart, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf("multipart/alternative; boundary=%q", alternative.Boundary())},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = bodyPart.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// 4. Archivos adjuntos codificados en base64
+	for _, attachment := range message.Attachments {
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", attachment.ContentType, attachment.Filename)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", attachment.Filename)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = writeBase64(part, attachment.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = mixed.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeHeader escribe un encabezado del correo
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key + ": " + value + "\r\n")
+}
+
+// writeQuotedPart escribe una parte de texto utilizando la codificación quoted-printable
+func writeQuotedPart(writer *multipart.Writer, contentType, body string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err = qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 escribe el contenido en base64 con líneas de 76 caracteres como lo exige RFC 2045
+func writeBase64(part io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+
+	_, err := part.Write([]byte(encoded + "\r\n"))
+	return err
+}
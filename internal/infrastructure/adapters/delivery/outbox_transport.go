@@ -0,0 +1,54 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	deliveryPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// OutboxTransport escribe cada correo como un archivo .eml en un directorio local, pensado para pruebas y
+// ambientes sin servidor SMTP. Los archivos pueden abrirse con cualquier cliente de correo.
+type OutboxTransport struct {
+	path string
+}
+
+// NewOutboxTransport crea una instancia de OutboxTransport. Recibe el directorio donde se escribirán los correos.
+func NewOutboxTransport(path string) deliveryPorts.MailTransport {
+	return &OutboxTransport{path: path}
+}
+
+func (t *OutboxTransport) Name() string {
+	return "outbox"
+}
+
+// Send escribe el correo en el directorio de salida
+func (t *OutboxTransport) Send(ctx context.Context, message *models.EmailMessage) error {
+	now := utils.TimeNow()
+
+	// 1. Construir el contenido del correo
+	content, err := buildMIMEMessage(message, now)
+	if err != nil {
+		return shared_error.NewGeneralServiceError("OutboxTransport", "Send", "failed to build message", err)
+	}
+
+	// 2. Asegurar que el directorio exista
+	if err = os.MkdirAll(t.path, 0755); err != nil {
+		return shared_error.NewGeneralServiceError("OutboxTransport", "Send", "failed to create outbox directory", err)
+	}
+
+	// 3. Escribir el archivo
+	filename := fmt.Sprintf("%s-%s.eml", now.Format("20060102150405"), uuid.NewString())
+	if err = os.WriteFile(filepath.Join(t.path, filename), content, 0644); err != nil {
+		return shared_error.NewGeneralServiceError("OutboxTransport", "Send", "failed to write message", err)
+	}
+
+	return nil
+}
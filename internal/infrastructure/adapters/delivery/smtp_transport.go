@@ -0,0 +1,136 @@
+package delivery
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+
+	deliveryPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+const (
+	EncryptionNone     = "none"
+	EncryptionStartTLS = "starttls"
+	EncryptionTLS      = "tls"
+
+	smtpTimeout = 30 * time.Second
+)
+
+// SMTPTransport envía los correos a través de un servidor SMTP
+type SMTPTransport struct {
+	host       string
+	port       string
+	username   string
+	password   string
+	encryption string
+}
+
+// NewSMTPTransport crea una instancia de SMTPTransport, si no se indica el cifrado se utiliza STARTTLS
+func NewSMTPTransport(host, port, username, password, encryption string) deliveryPorts.MailTransport {
+	if encryption == "" {
+		encryption = EncryptionStartTLS
+	}
+
+	return &SMTPTransport{
+		host:       host,
+		port:       port,
+		username:   username,
+		password:   password,
+		encryption: encryption,
+	}
+}
+
+func (t *SMTPTransport) Name() string {
+	return "smtp"
+}
+
+// Send envía un correo electrónico al servidor SMTP configurado
+func (t *SMTPTransport) Send(ctx context.Context, message *models.EmailMessage) error {
+	// 1. Construir el contenido del correo
+	content, err := buildMIMEMessage(message, utils.TimeNow())
+	if err != nil {
+		return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "failed to build message", err)
+	}
+
+	// 2. Conectar con el servidor
+	client, err := t.dial(ctx)
+	if err != nil {
+		return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "failed to connect to smtp server", err)
+	}
+	defer client.Close()
+
+	// 3. Autenticar si se configuraron credenciales
+	if t.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "failed to authenticate", err)
+		}
+	}
+
+	// 4. Indicar remitente y destinatarios
+	if err = client.Mail(message.From); err != nil {
+		return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "sender rejected", err)
+	}
+	for _, recipient := range message.To {
+		if err = client.Rcpt(recipient); err != nil {
+			return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "recipient rejected", err)
+		}
+	}
+
+	// 5. Escribir el contenido del correo
+	writer, err := client.Data()
+	if err != nil {
+		return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "failed to open data", err)
+	}
+	if _, err = writer.Write(content); err != nil {
+		return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "failed to write message", err)
+	}
+	if err = writer.Close(); err != nil {
+		return shared_error.NewGeneralServiceError("SMTPTransport", "Send", "message rejected", err)
+	}
+
+	return client.Quit()
+}
+
+// dial abre la conexión con el servidor SMTP según el tipo de cifrado configurado
+func (t *SMTPTransport) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(t.host, t.port)
+	tlsConfig := &tls.Config{ServerName: t.host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if t.encryption == EncryptionTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(smtpTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if t.encryption == EncryptionStartTLS {
+		if err = client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
@@ -0,0 +1,28 @@
+package delivery
+
+import (
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	deliveryPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// NewMailTransportFromConfig crea el transporte de correo configurado en el archivo .env,
+// retorna nil si el envío de correos está deshabilitado
+func NewMailTransportFromConfig() deliveryPorts.MailTransport {
+	if config.Mail == nil || !config.Mail.Enabled {
+		return nil
+	}
+
+	switch config.Mail.Transport {
+	case "outbox":
+		return NewOutboxTransport(utils.FindProjectRoot() + config.Mail.OutboxPath)
+	default:
+		return NewSMTPTransport(
+			config.Mail.Host,
+			config.Mail.Port,
+			config.Mail.Username,
+			config.Mail.Password,
+			config.Mail.Encryption,
+		)
+	}
+}
@@ -1,36 +1,39 @@
 package checkers
 
 import (
+	"context"
 	"fmt"
+	"time"
+
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/models"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
-	"github.com/dimiro1/health/redis"
+	"github.com/go-redis/redis/v8"
 )
 
 type redisChecker struct {
+	client *redis.Client
 }
 
-func NewRedisChecker() health.ComponentChecker {
-	return &redisChecker{}
+// NewRedisChecker crea el verificador de Redis, utiliza el mismo cliente de la caché para verificar el host, el puerto
+// y la contraseña configurados
+func NewRedisChecker(client *redis.Client) health.ComponentChecker {
+	return &redisChecker{client: client}
 }
 
 func (c *redisChecker) Name() string {
-	return "redis"
+	return constants.ComponentRedis
 }
 
 func (c *redisChecker) Check() models.Health {
-	checker := redis.NewChecker("tcp", ":6379")
-	health := checker.Check()
-	if health.IsDown() {
-		details := utils.TranslateHealthDown(c.Name())
-		if health.GetInfo("error") != nil {
-			details = fmt.Sprintf("%s: %v", details, health.GetInfo("error"))
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.client.Ping(ctx).Err(); err != nil {
 		return models.Health{
 			Status:  constants.StatusDown,
-			Details: details,
+			Details: fmt.Sprintf("%s: %v", utils.TranslateHealthDown(c.Name()), err),
 		}
 	}
 
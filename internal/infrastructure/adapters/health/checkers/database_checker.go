@@ -19,7 +19,7 @@ func NewDatabaseChecker(db *gorm.DB) health.ComponentChecker {
 }
 
 func (c *databaseChecker) Name() string {
-	return "database"
+	return constants.ComponentDatabase
 }
 
 func (c *databaseChecker) Check() models.Health {
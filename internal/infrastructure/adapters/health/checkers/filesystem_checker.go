@@ -28,7 +28,7 @@ func NewFileSystemChecker() health2.ComponentChecker {
 }
 
 func (c *fileSystemChecker) Name() string {
-	return "filesystem"
+	return constants.ComponentFileSystem
 }
 
 // Check verifica si el sistema de archivos tiene permisos de escritura
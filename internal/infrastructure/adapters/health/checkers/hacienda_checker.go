@@ -30,7 +30,7 @@ func NewHaciendaChecker() health2.ComponentChecker {
 }
 
 func (c *haciendaChecker) Name() string {
-	return "hacienda"
+	return constants.ComponentHacienda
 }
 
 // Check implementa ports.ComponentChecker.Check
@@ -24,7 +24,7 @@ func NewSignerChecker() health.ComponentChecker {
 }
 
 func (c *signerChecker) Name() string {
-	return "dte_signer"
+	return constants.ComponentSigner
 }
 
 func (c *signerChecker) Check() models.Health {
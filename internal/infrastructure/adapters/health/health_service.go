@@ -1,45 +1,177 @@
 package health
 
 import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/health/checkers"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
 
+// Component es un componente verificado por el servicio de salud. La falla de un componente crítico deja el servicio
+// DOWN, la falla de un componente no crítico lo deja DEGRADED y la emisión continúa en contingencia
+type Component struct {
+	Checker  health.ComponentChecker
+	Critical bool
+}
+
 type healthService struct {
-	checkers []health.ComponentChecker
+	components []Component
+	timeout    time.Duration
+	cacheTTL   time.Duration
+
+	mu        sync.Mutex
+	last      *models.HealthStatus
+	checkedAt time.Time
+	inFlight  chan struct{}
 }
 
 type HealthServiceConfig struct {
-	DB *gorm.DB
+	DB           *gorm.DB
+	Redis        *redis.Client
+	CheckTimeout time.Duration
+	CacheTTL     time.Duration
 }
 
+// NewHealthService crea el servicio de salud con los componentes del microservicio, solo la base de datos es crítica
 func NewHealthService(cfg *HealthServiceConfig) health.HealthManager {
-	service := &healthService{
-		checkers: []health.ComponentChecker{
-			checkers.NewDatabaseChecker(cfg.DB),
-			checkers.NewRedisChecker(),
-			checkers.NewHaciendaChecker(),
-			checkers.NewFileSystemChecker(),
-			checkers.NewSignerChecker(),
-		},
+	return NewHealthServiceWithComponents([]Component{
+		{Checker: checkers.NewDatabaseChecker(cfg.DB), Critical: true},
+		{Checker: checkers.NewRedisChecker(cfg.Redis)},
+		{Checker: checkers.NewHaciendaChecker()},
+		{Checker: checkers.NewFileSystemChecker()},
+		{Checker: checkers.NewSignerChecker()},
+	}, cfg.CheckTimeout, cfg.CacheTTL)
+}
+
+// NewHealthServiceWithComponents crea el servicio de salud con los componentes indicados. Cada componente se verifica
+// con un tiempo máximo de timeout y el resultado de la verificación se reutiliza durante cacheTTL
+func NewHealthServiceWithComponents(components []Component, timeout, cacheTTL time.Duration) health.HealthManager {
+	return &healthService{
+		components: components,
+		timeout:    timeout,
+		cacheTTL:   cacheTTL,
 	}
-	return service
 }
 
-func (s *healthService) CheckHealth() (*models.HealthStatus, error) {
-	components := make(map[string]models.Health)
-	status := constants.StatusUp
+// CheckHealth retorna el estado de todos los componentes, si el último resultado ya no está vigente se verifican de nuevo
+func (s *healthService) CheckHealth(ctx context.Context) (*models.HealthStatus, error) {
+	// 1. Reutilizar el último resultado si sigue vigente
+	s.mu.Lock()
+	if s.isFresh() {
+		last := s.last
+		s.mu.Unlock()
+		return last, nil
+	}
+
+	// 2. Esperar la verificación en curso o iniciar una nueva, las solicitudes concurrentes comparten la misma
+	done := s.refresh()
+	s.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last, nil
+}
+
+// CheckLiveness indica que el proceso está en ejecución y responde, no verifica dependencias para que una falla externa
+// no provoque el reinicio del servicio
+func (s *healthService) CheckLiveness() *models.HealthStatus {
+	return &models.HealthStatus{
+		Status:    constants.StatusUp,
+		Timestamp: utils.TimeNow().Format("02-01-2006 15:04:05"),
+	}
+}
+
+// IsAvailable indica si el componente estaba disponible en la última verificación sin esperar una nueva. Si el resultado
+// ya no está vigente se inicia una verificación en segundo plano, antes de la primera verificación se asume disponible
+func (s *healthService) IsAvailable(component string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isFresh() {
+		s.refresh()
+	}
+
+	if s.last == nil {
+		return true
+	}
+
+	status, ok := s.last.Components[component]
+	return !ok || status.Status != constants.StatusDown
+}
+
+// isFresh indica si el último resultado sigue vigente, debe invocarse con el lock tomado
+func (s *healthService) isFresh() bool {
+	return s.last != nil && time.Since(s.checkedAt) < s.cacheTTL
+}
+
+// refresh inicia una verificación de los componentes si no hay una en curso y retorna el canal que se cierra al
+// finalizar, debe invocarse con el lock tomado
+func (s *healthService) refresh() <-chan struct{} {
+	if s.inFlight != nil {
+		return s.inFlight
+	}
+
+	done := make(chan struct{})
+	s.inFlight = done
 
-	for _, checker := range s.checkers {
-		health := checker.Check()
-		components[checker.Name()] = health
+	go func() {
+		status := s.checkComponents()
 
-		if health.Status == constants.StatusDown {
+		s.mu.Lock()
+		previous := s.last
+		s.last = status
+		s.checkedAt = time.Now()
+		s.inFlight = nil
+		s.mu.Unlock()
+
+		logStatusChange(previous, status)
+		close(done)
+	}()
+
+	return done
+}
+
+// checkComponents verifica todos los componentes de forma concurrente y determina el estado general del servicio
+func (s *healthService) checkComponents() *models.HealthStatus {
+	results := make([]models.Health, len(s.components))
+
+	var wg sync.WaitGroup
+	for i, component := range s.components {
+		wg.Add(1)
+		go func(i int, component Component) {
+			defer wg.Done()
+			results[i] = s.checkComponent(component)
+		}(i, component)
+	}
+	wg.Wait()
+
+	components := make(map[string]models.Health, len(s.components))
+	status := constants.StatusUp
+	for i, component := range s.components {
+		components[component.Checker.Name()] = results[i]
+
+		if results[i].Status != constants.StatusDown {
+			continue
+		}
+		if component.Critical {
 			status = constants.StatusDown
+		} else if status == constants.StatusUp {
+			status = constants.StatusDegraded
 		}
 	}
 
@@ -47,5 +179,56 @@ func (s *healthService) CheckHealth() (*models.HealthStatus, error) {
 		Status:     status,
 		Components: components,
 		Timestamp:  utils.TimeNow().Format("02-01-2006 15:04:05"),
-	}, nil
+	}
+}
+
+// checkComponent verifica un componente, si no responde dentro del tiempo máximo se considera caído. La verificación
+// que excede el tiempo continúa en segundo plano hasta que el verificador alcanza su propio timeout
+func (s *healthService) checkComponent(component Component) models.Health {
+	result := make(chan models.Health, 1)
+	go func() {
+		result <- component.Checker.Check()
+	}()
+
+	var status models.Health
+	select {
+	case status = <-result:
+	case <-time.After(s.timeout):
+		status = models.Health{
+			Status:  constants.StatusDown,
+			Details: utils.TranslateHealthError("CheckTimeout", component.Checker.Name(), s.timeout.String()),
+		}
+	}
+
+	status.Critical = component.Critical
+	return status
+}
+
+// logStatusChange registra los cambios del estado general del servicio junto con los componentes caídos
+func logStatusChange(previous, current *models.HealthStatus) {
+	if previous != nil && previous.Status == current.Status {
+		return
+	}
+
+	down := make([]string, 0)
+	for name, component := range current.Components {
+		if component.Status == constants.StatusDown {
+			down = append(down, name)
+		}
+	}
+	sort.Strings(down)
+
+	fields := map[string]interface{}{
+		"status":         current.Status,
+		"downComponents": down,
+	}
+	if previous != nil {
+		fields["previousStatus"] = previous.Status
+	}
+
+	if current.Status == constants.StatusUp {
+		logs.Info("Health status changed", fields)
+		return
+	}
+	logs.Warn("Health status changed", fields)
 }
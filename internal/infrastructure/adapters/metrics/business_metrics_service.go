@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	metricsPort "github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// TopRejectionCodes cantidad de códigos de rechazo de Hacienda incluidos en las métricas
+const TopRejectionCodes = 10
+
+// Rangos de antigüedad de los documentos pendientes en la cola de contingencia
+const (
+	AgeLessThanHour = "lt_1h"
+	AgeLessThanDay  = "1h_24h"
+	AgeLessThan72h  = "24h_72h"
+	AgeOver72h      = "gt_72h"
+)
+
+// BusinessMetricsService calcula las métricas de negocio a partir de consultas agregadas sobre los DTE almacenados
+type BusinessMetricsService struct {
+	repo metricsPort.BusinessMetricsRepositoryPort
+}
+
+// NewBusinessMetricsService crea una instancia de BusinessMetricsService. Recibe el repositorio de métricas de negocio.
+func NewBusinessMetricsService(repo metricsPort.BusinessMetricsRepositoryPort) metricsPort.BusinessMetricsManager {
+	return &BusinessMetricsService{repo: repo}
+}
+
+// GetBusinessMetrics obtiene las métricas del período para la sucursal o todas las sucursales del contribuyente
+func (s *BusinessMetricsService) GetBusinessMetrics(ctx context.Context, filters *models.BusinessMetricsFilters) (*models.Metrics, error) {
+	now := utils.TimeNow()
+
+	// 1. Obtener los agregados de los DTE, la cola de contingencia y los códigos de rechazo
+	groups, err := s.repo.GetDTEGroups(ctx, filters)
+	if err != nil {
+		return nil, s.metricsError(ctx, err, filters)
+	}
+
+	pending, err := s.repo.GetPendingContingency(ctx, filters, now)
+	if err != nil {
+		return nil, s.metricsError(ctx, err, filters)
+	}
+
+	codes, err := s.repo.GetTopRejectionCodes(ctx, filters, TopRejectionCodes)
+	if err != nil {
+		return nil, s.metricsError(ctx, err, filters)
+	}
+
+	// 2. Acumular los totales y el detalle de cada sucursal
+	result := &models.Metrics{
+		Period: models.MetricsPeriod{
+			Name:      filters.Period,
+			StartDate: filters.StartDate,
+			EndDate:   filters.EndDate,
+		},
+		ProcessedDTEs: newDTEMetrics(),
+		Contingency: models.ContingencyMetrics{
+			ByAge: map[string]int64{AgeLessThanHour: 0, AgeLessThanDay: 0, AgeLessThan72h: 0, AgeOver72h: 0},
+		},
+		Rejections: models.RejectionMetrics{TopCodes: codes},
+		Timestamp:  now.Format(time.RFC3339),
+	}
+
+	branches := make(map[uint]*models.BranchMetrics)
+	branch := func(id uint) *models.BranchMetrics {
+		if _, ok := branches[id]; !ok {
+			branches[id] = &models.BranchMetrics{BranchID: id, ProcessedDTEs: newDTEMetrics()}
+		}
+		return branches[id]
+	}
+
+	for _, group := range groups {
+		addGroup(&result.ProcessedDTEs, group)
+		addGroup(&branch(group.BranchID).ProcessedDTEs, group)
+	}
+
+	// 3. Acumular la cola de contingencia por antigüedad, el documento más antiguo determina la antigüedad máxima
+	for _, group := range pending {
+		result.Contingency.PendingDTEs += group.Pending
+		result.Contingency.ByAge[AgeLessThanHour] += group.LessThanHour
+		result.Contingency.ByAge[AgeLessThanDay] += group.LessThanDay - group.LessThanHour
+		result.Contingency.ByAge[AgeLessThan72h] += group.LessThanThreeDays - group.LessThanDay
+		result.Contingency.ByAge[AgeOver72h] += group.Pending - group.LessThanThreeDays
+
+		if group.OldestPendingAt != nil && (result.Contingency.OldestPendingAt == nil || group.OldestPendingAt.Before(*result.Contingency.OldestPendingAt)) {
+			result.Contingency.OldestPendingAt = group.OldestPendingAt
+		}
+		branch(group.BranchID).PendingContingency = group.Pending
+	}
+	if result.Contingency.OldestPendingAt != nil {
+		result.Contingency.OldestAgeSeconds = int64(now.Sub(*result.Contingency.OldestPendingAt).Seconds())
+	}
+
+	// 4. Calcular las tasas de rechazo sobre el total de documentos procesados
+	result.Rejections.Total = result.ProcessedDTEs.ByStatus[constants.DocumentRejected]
+	result.Rejections.Rate = rejectionRate(&result.ProcessedDTEs)
+
+	result.Branches = make([]models.BranchMetrics, 0, len(branches))
+	for _, metrics := range branches {
+		metrics.RejectionRate = rejectionRate(&metrics.ProcessedDTEs)
+		result.Branches = append(result.Branches, *metrics)
+	}
+	sort.Slice(result.Branches, func(i, j int) bool {
+		return result.Branches[i].BranchID < result.Branches[j].BranchID
+	})
+
+	return result, nil
+}
+
+// metricsError registra el error de una consulta agregada y lo retorna como error de servicio
+func (s *BusinessMetricsService) metricsError(ctx context.Context, err error, filters *models.BusinessMetricsFilters) error {
+	logs.ErrorContext(ctx, "Failed to aggregate business metrics", map[string]interface{}{
+		"error":    err.Error(),
+		"clientID": filters.ClientID,
+		"branchID": filters.BranchID,
+	})
+	return shared_error.NewFormattedGeneralServiceWithError("BusinessMetricsService", "GetBusinessMetrics", err, "FailedToGetBusinessMetrics")
+}
+
+func newDTEMetrics() models.DTEMetrics {
+	return models.DTEMetrics{
+		ByType:         make(map[string]int64),
+		ByStatus:       make(map[string]int64),
+		ByTransmission: make(map[string]int64),
+		AmountByType:   make(map[string]float64),
+		Breakdown:      make([]models.DTEMetricGroup, 0),
+	}
+}
+
+// addGroup acumula un grupo del agregado en las métricas, los grupos de la misma combinación de tipo, estado y
+// transmisión de distintas sucursales se suman en un mismo elemento del detalle
+func addGroup(metrics *models.DTEMetrics, group models.DTEMetricGroup) {
+	metrics.Total += group.Count
+	metrics.TotalAmount = roundAmount(metrics.TotalAmount + group.Amount)
+	metrics.ByType[group.DTEType] += group.Count
+	metrics.ByStatus[group.Status] += group.Count
+	metrics.ByTransmission[group.Transmission] += group.Count
+	metrics.AmountByType[group.DTEType] = roundAmount(metrics.AmountByType[group.DTEType] + group.Amount)
+
+	for i := range metrics.Breakdown {
+		item := &metrics.Breakdown[i]
+		if item.DTEType == group.DTEType && item.Status == group.Status && item.Transmission == group.Transmission {
+			item.Count += group.Count
+			item.Amount = roundAmount(item.Amount + group.Amount)
+			return
+		}
+	}
+
+	group.BranchID = 0
+	metrics.Breakdown = append(metrics.Breakdown, group)
+}
+
+// rejectionRate calcula la proporción de documentos rechazados sobre el total procesado
+func rejectionRate(metrics *models.DTEMetrics) float64 {
+	if metrics.Total == 0 {
+		return 0
+	}
+	return float64(metrics.ByStatus[constants.DocumentRejected]) / float64(metrics.Total)
+}
+
+// roundAmount redondea un monto a dos decimales para evitar acumular errores de punto flotante en las sumas
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
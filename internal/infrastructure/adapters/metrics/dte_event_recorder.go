@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+)
+
+// DTEEventRecorder cuenta los DTE procesados a partir de los eventos del ciclo de vida emitidos por el WebhookManager,
+// de esta forma cada DTE se cuenta una sola vez sin importar el flujo que lo originó
+type DTEEventRecorder struct{}
+
+func NewDTEEventRecorder() webhook.EventListener {
+	return &DTEEventRecorder{}
+}
+
+// HandleEvent registra el DTE del evento con su estado y tipo de transmisión, los eventos que no corresponden a un
+// cambio de estado de un DTE se ignoran
+func (r *DTEEventRecorder) HandleEvent(_ context.Context, event *webhookModels.Event) {
+	var status, transmission string
+
+	switch event.Type {
+	case webhookModels.EventDTEIssued:
+		status, transmission = constants.DocumentReceived, constants.TransmissionNormal
+	case webhookModels.EventDTEReceived:
+		status, transmission = constants.DocumentReceived, constants.TransmissionContingency
+	case webhookModels.EventDTERejected:
+		// Los rechazos de un lote incluyen el ID del lote, los demás corresponden a una transmisión individual
+		status, transmission = constants.DocumentRejected, constants.TransmissionNormal
+		if _, ok := event.Data["batch_id"]; ok {
+			transmission = constants.TransmissionContingency
+		}
+	case webhookModels.EventDTEContingencyStored:
+		status, transmission = constants.DocumentPending, constants.TransmissionContingency
+	case webhookModels.EventDTEInvalidated:
+		status, transmission = constants.DocumentInvalid, constants.TransmissionNormal
+	default:
+		return
+	}
+
+	dteType := "unknown"
+	if value, ok := event.Data["dte_type"]; ok && value != nil {
+		dteType = fmt.Sprint(value)
+	}
+
+	RecordDTE(dteType, status, transmission)
+}
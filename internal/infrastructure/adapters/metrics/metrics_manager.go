@@ -7,31 +7,16 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"strings"
 )
 
 type MetricManager struct {
-	cache     ports.CacheManager
-	endpoints []struct {
-		path   string
-		method string
-	}
+	cache ports.CacheManager
 }
 
 func NewMetricService(cache ports.CacheManager) metricsPort.MetricsManager {
 	return &MetricManager{
 		cache: cache,
-		endpoints: []struct {
-			path   string
-			method string
-		}{
-			{path: "invoices", method: "POST"},
-			{path: "ccf", method: "POST"},
-			{path: "invalidation", method: "POST"},
-			{path: "retention", method: "POST"},
-			{path: "credit_note", method: "POST"},
-			{path: "dte", method: "GET"},
-			{path: "dte/{id}", method: "GET"},
-		},
 	}
 }
 
@@ -94,21 +79,32 @@ func (m *MetricManager) GetEndpointMetrics(systemNIT, method, endpoint string) (
 }
 
 func (m *MetricManager) GetAllMetricsEndpoint(systemNIT string) (map[string]*models.EndpointMetrics, error) {
-	// Lista de endpoints conocidos del sistema
+	// Endpoints con métricas del sistema, el middleware de métricas los registra con el formato "METHOD:endpoint"
+	client := m.cache.GetRedisClient()
+	endpoints, err := client.SMembers(client.Context(), fmt.Sprintf("metrics:%s:endpoints", systemNIT)).Result()
+	if err != nil {
+		return nil, err
+	}
+
 	allMetrics := make(map[string]*models.EndpointMetrics)
 
-	for _, ep := range m.endpoints {
-		metrics, err := m.GetEndpointMetrics(systemNIT, ep.method, ep.path)
+	for _, ep := range endpoints {
+		method, path, found := strings.Cut(ep, ":")
+		if !found {
+			continue
+		}
+
+		metrics, err := m.GetEndpointMetrics(systemNIT, method, path)
 		if err != nil {
 			logs.Warn("Failed to get metrics for endpoint", map[string]interface{}{
-				"endpoint": ep.path,
-				"method":   ep.method,
+				"endpoint": path,
+				"method":   method,
 				"error":    err.Error(),
 			})
 			continue
 		}
 
-		key := fmt.Sprintf("%s-%s", ep.method, ep.path)
+		key := fmt.Sprintf("%s-%s", method, path)
 		allMetrics[key] = metrics
 	}
 
@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+// Orígenes de las respuestas de Hacienda
+const (
+	HaciendaSourceOnline = "online" // Transmisión individual de un documento, evento de contingencia o invalidación
+	HaciendaSourceBatch  = "batch"  // Resultado de un documento en la consulta de un lote de contingencia
+)
+
+// queueDepthTimeout tiempo máximo para consultar la profundidad de la cola de contingencia durante un scrape
+const queueDepthTimeout = 2 * time.Second
+
+// Registry contiene las métricas expuestas en /metrics, se utiliza un registro propio para no depender del registro
+// global de Prometheus
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duración de las solicitudes HTTP por método, plantilla de ruta y código de estado",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dteDocuments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dte_documents_total",
+		Help: "DTE procesados por tipo de documento, estado y tipo de transmisión",
+	}, []string{"dte_type", "status", "transmission"})
+
+	haciendaResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hacienda_responses_total",
+		Help: "Respuestas de Hacienda por origen, estado y código de mensaje",
+	}, []string{"source", "status", "code"})
+
+	signerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "signer_request_duration_seconds",
+		Help:    "Duración de las solicitudes al firmador por resultado",
+		Buckets: []float64{.025, .05, .1, .25, .5, 1, 2, 5},
+	}, []string{"outcome"})
+
+	contingencyBatchSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "contingency_batch_size",
+		Help:    "Cantidad de documentos por lote de contingencia transmitido a Hacienda",
+		Buckets: []float64{1, 5, 10, 25, 50, 75, 100},
+	})
+
+	circuitBreakerState = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "Estado del circuit breaker de la transmisión de lotes: 0 cerrado, 1 abierto, 2 semi-abierto",
+	})
+
+	contingencyQueueDepth = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "contingency_queue_depth",
+		Help: "Documentos en estado PENDING en la cola de contingencia",
+	}, queueDepth.value)
+)
+
+// queueDepth mantiene la fuente de la profundidad de la cola de contingencia y el último valor obtenido, si la consulta
+// falla se reporta el último valor conocido
+var queueDepth = &queueDepthSource{}
+
+type queueDepthSource struct {
+	mu     sync.Mutex
+	source func(ctx context.Context) (int64, error)
+	last   float64
+}
+
+func (q *queueDepthSource) value() float64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.source == nil {
+		return q.last
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queueDepthTimeout)
+	defer cancel()
+
+	count, err := q.source(ctx)
+	if err != nil {
+		logs.Warn("Failed to get contingency queue depth", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return q.last
+	}
+
+	q.last = float64(count)
+	return q.last
+}
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		dteDocuments,
+		haciendaResponses,
+		signerRequestDuration,
+		contingencyBatchSize,
+		circuitBreakerState,
+		contingencyQueueDepth,
+	)
+}
+
+// SetContingencyQueueSource establece la consulta con la que se obtiene la profundidad de la cola de contingencia en
+// cada scrape
+func SetContingencyQueueSource(source func(ctx context.Context) (int64, error)) {
+	queueDepth.mu.Lock()
+	defer queueDepth.mu.Unlock()
+	queueDepth.source = source
+}
+
+// ObserveHTTPRequest registra la duración de una solicitud HTTP, route debe ser la plantilla de la ruta para no
+// generar una serie por cada identificador
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// RecordDTE registra un DTE procesado
+func RecordDTE(dteType, status, transmission string) {
+	dteDocuments.WithLabelValues(dteType, status, transmission).Inc()
+}
+
+// RecordHaciendaResponse registra una respuesta de Hacienda, el estado se normaliza en mayúsculas
+func RecordHaciendaResponse(source, status, code string) {
+	haciendaResponses.WithLabelValues(source, strings.ToUpper(status), code).Inc()
+}
+
+// ObserveSignerRequest registra la duración de una solicitud al firmador
+func ObserveSignerRequest(outcome string, duration time.Duration) {
+	signerRequestDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// ObserveBatchSize registra la cantidad de documentos de un lote transmitido
+func ObserveBatchSize(size int) {
+	contingencyBatchSize.Observe(float64(size))
+}
+
+// SetCircuitBreakerState registra el estado actual del circuit breaker
+func SetCircuitBreakerState(state constants.State) {
+	circuitBreakerState.Set(float64(state))
+}
+
+// PrometheusHandler expone las métricas del registro en formato de Prometheus. Si se indica un token, las solicitudes
+// deben enviarlo en el encabezado "Authorization: Bearer <token>"
+func PrometheusHandler(token string) http.Handler {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
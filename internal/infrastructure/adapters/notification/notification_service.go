@@ -0,0 +1,605 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	deliveryPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	deliveryModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification/models"
+	webhookPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+const (
+	// cooldownKeyPrefix prefijo de las llaves de Redis que registran la última alerta de cada sujeto
+	cooldownKeyPrefix = "notification:cooldown:"
+	// notificationLease tiempo durante el cual una notificación reservada no puede ser intentada por otro proceso
+	notificationLease = 2 * time.Minute
+	// eventAlertTimeout tiempo máximo para registrar y entregar las alertas de un evento en segundo plano
+	eventAlertTimeout = 2 * time.Minute
+	// dueNotificationsBatchSize cantidad de notificaciones pendientes leídas por consulta
+	dueNotificationsBatchSize = 100
+	// sequenceGapWindow antigüedad máxima de los números de control rechazados revisados, cubre las revisiones que no
+	// se ejecutaron mientras la aplicación estuvo detenida
+	sequenceGapWindow = 24 * time.Hour
+	// maxErrorLength longitud máxima del error registrado de un intento
+	maxErrorLength = 500
+	// dateLayout formato de las fechas en los mensajes de las alertas
+	dateLayout = "02/01/2006 15:04"
+)
+
+// NotificationService genera alertas a partir de los eventos del ciclo de vida de los DTE y de revisiones periódicas, y
+// las entrega según las reglas de cada alerta a los destinatarios configurados por tipo de entidad. Cada entrega se
+// registra en user_notifications, los correos fallidos se reintentan con espera exponencial y las alertas por webhook
+// se entregan como el evento notification.created a las suscripciones del contribuyente
+type NotificationService struct {
+	repo      notification.NotificationRepositoryPort
+	transport deliveryPorts.MailTransport
+	from      string
+	fromName  string
+	webhooks  webhookPorts.WebhookManager
+	redis     *redis.Client
+}
+
+// NewNotificationService crea una instancia de NotificationService. Si el transporte es nil el canal de correo queda
+// deshabilitado y si el cliente de Redis es nil las alertas se generan sin cooldown
+func NewNotificationService(
+	repo notification.NotificationRepositoryPort,
+	transport deliveryPorts.MailTransport,
+	from string,
+	fromName string,
+	webhooks webhookPorts.WebhookManager,
+	redisClient *redis.Client,
+) notification.NotificationManager {
+	return &NotificationService{
+		repo:      repo,
+		transport: transport,
+		from:      from,
+		fromName:  fromName,
+		webhooks:  webhooks,
+		redis:     redisClient,
+	}
+}
+
+// Notify registra las notificaciones de una alerta según su regla y las entrega en segundo plano
+func (s *NotificationService) Notify(ctx context.Context, alert *models.Alert) {
+	ctx = context.WithoutCancel(ctx)
+
+	// 1. Obtener la regla de la alerta y verificar que el sujeto no esté en cooldown
+	rule, ok := models.Rules[alert.Type]
+	if !ok {
+		logs.WarnContext(ctx, "Notification rule not found", map[string]interface{}{
+			"alert": alert.Type,
+		})
+		return
+	}
+	if !s.acquireCooldown(ctx, rule, alert) {
+		return
+	}
+
+	// 2. Obtener los destinatarios habilitados de los tipos de entidad de la regla
+	recipients, err := s.repo.GetAlertRecipients(ctx, alert.UserID, rule.EntityTypes)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to get notification recipients", map[string]interface{}{
+			"alert":  alert.Type,
+			"userID": alert.UserID,
+			"error":  err.Error(),
+		})
+		s.releaseCooldown(ctx, rule, alert)
+		return
+	}
+
+	// 3. Registrar una notificación por destinatario en el canal de correo y una por alerta en el canal de webhooks
+	notifications := s.buildNotifications(rule, alert, recipients)
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err = s.repo.CreateNotifications(ctx, alert, notifications); err != nil {
+		logs.ErrorContext(ctx, "Failed to create notifications", map[string]interface{}{
+			"alert":  alert.Type,
+			"userID": alert.UserID,
+			"error":  err.Error(),
+		})
+		s.releaseCooldown(ctx, rule, alert)
+		return
+	}
+
+	// 4. Realizar las entregas en segundo plano, los intentos fallidos los retoma el job de notificaciones pendientes
+	for _, n := range notifications {
+		go s.dispatch(ctx, n)
+	}
+}
+
+// HandleEvent genera las alertas de los eventos del ciclo de vida de los DTE. Se invoca desde la emisión del evento,
+// por lo que las alertas se generan en segundo plano para no retrasar la acción que lo origina
+func (s *NotificationService) HandleEvent(ctx context.Context, event *webhookModels.Event) {
+	if event.BranchID == 0 {
+		return
+	}
+	if event.Type != webhookModels.EventDTEContingencyStored && event.Type != webhookModels.EventDTERejected {
+		return
+	}
+
+	parent := tracing.Detach(ctx)
+	go func(event webhookModels.Event) {
+		defer func() {
+			if r := recover(); r != nil {
+				logs.ErrorContext(ctx, "Recovered from panic in event notification", map[string]interface{}{
+					"event": event.Type,
+					"panic": fmt.Sprint(r),
+				})
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(parent, eventAlertTimeout)
+		defer cancel()
+
+		alert, err := s.eventAlert(ctx, &event)
+		if err != nil {
+			logs.ErrorContext(ctx, "Failed to build event notification", map[string]interface{}{
+				"event":    event.Type,
+				"branchID": event.BranchID,
+				"error":    err.Error(),
+			})
+			return
+		}
+
+		s.Notify(ctx, alert)
+	}(*event)
+}
+
+// ScanScheduledAlerts genera las alertas de los certificados próximos a vencer, de los documentos en contingencia
+// próximos al plazo de transmisión y de los saltos en la numeración. El cooldown de cada regla evita repetir las
+// alertas en cada revisión
+func (s *NotificationService) ScanScheduledAlerts(ctx context.Context) error {
+	now := utils.TimeNow()
+
+	// 1. Certificados de firma próximos a vencer o vencidos
+	certificates, err := s.repo.GetExpiringCertificates(ctx, now.Add(models.CertificateExpiryWarning))
+	if err != nil {
+		return shared_error.NewGeneralServiceError("NotificationService", "ScanScheduledAlerts", "failed to get expiring certificates", err)
+	}
+	for _, certificate := range certificates {
+		s.Notify(ctx, certificateAlert(certificate, now))
+	}
+
+	// 2. Documentos en contingencia cuyo plazo de transmisión vence dentro del periodo de aviso
+	backlog, err := s.repo.GetContingencyBacklog(ctx, now.Add(models.ContingencyDeadlineWarning-models.ContingencyDeadline))
+	if err != nil {
+		return shared_error.NewGeneralServiceError("NotificationService", "ScanScheduledAlerts", "failed to get contingency backlog", err)
+	}
+	for _, branch := range backlog {
+		s.Notify(ctx, contingencyDeadlineAlert(branch, now))
+	}
+
+	// 3. Números de control rechazados recientemente
+	gaps, err := s.repo.GetSequenceGaps(ctx, now.Add(-sequenceGapWindow))
+	if err != nil {
+		return shared_error.NewGeneralServiceError("NotificationService", "ScanScheduledAlerts", "failed to get sequence gaps", err)
+	}
+	for _, gap := range gaps {
+		s.Notify(ctx, sequenceGapAlert(gap, now))
+	}
+
+	return nil
+}
+
+// ProcessPendingNotifications realiza las notificaciones cuyo siguiente intento ya está programado, se detiene al
+// cancelarse el contexto y las notificaciones restantes se retoman en la siguiente ejecución
+func (s *NotificationService) ProcessPendingNotifications(ctx context.Context) error {
+	processed := make(map[uint]bool)
+
+	for {
+		// 1. Obtener un lote de notificaciones pendientes
+		notifications, err := s.repo.GetDueNotifications(ctx, utils.TimeNow(), dueNotificationsBatchSize)
+		if err != nil {
+			return shared_error.NewGeneralServiceError("NotificationService", "ProcessPendingNotifications", "failed to get due notifications", err)
+		}
+
+		// 2. Intentar las notificaciones del lote, una notificación solo se intenta una vez por ejecución
+		attempted := 0
+		for i := range notifications {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if processed[notifications[i].ID] {
+				continue
+			}
+
+			processed[notifications[i].ID] = true
+			if s.claimAndAttempt(ctx, &notifications[i]) {
+				attempted++
+			}
+		}
+
+		if len(notifications) < dueNotificationsBatchSize || attempted == 0 {
+			return nil
+		}
+	}
+}
+
+// ListRecipients obtiene los destinatarios de un tipo de entidad
+func (s *NotificationService) ListRecipients(ctx context.Context, userID uint, entityType string) ([]models.Recipient, error) {
+	recipients, err := s.repo.GetRecipients(ctx, userID, entityType)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("NotificationService", "ListRecipients", err, "FailedToGetNotificationRecipients")
+	}
+	return recipients, nil
+}
+
+// CreateRecipient valida y registra un destinatario
+func (s *NotificationService) CreateRecipient(ctx context.Context, recipient *models.Recipient) error {
+	if err := recipient.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.CreateRecipient(ctx, recipient); err != nil {
+		logs.ErrorContext(ctx, "Failed to create notification recipient", map[string]interface{}{
+			"userID":     recipient.UserID,
+			"entityType": recipient.EntityType,
+			"error":      err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("NotificationService", "CreateRecipient", err, "FailedToCreateNotificationRecipient")
+	}
+
+	return nil
+}
+
+// DeleteRecipient elimina un destinatario de un tipo de entidad
+func (s *NotificationService) DeleteRecipient(ctx context.Context, userID uint, entityType string, id uint) error {
+	return s.repo.DeleteRecipient(ctx, userID, entityType, id)
+}
+
+// GetNotifications obtiene las notificaciones del contribuyente que cumplen con los filtros
+func (s *NotificationService) GetNotifications(ctx context.Context, userID uint, filters *models.NotificationFilters) ([]models.Notification, error) {
+	notifications, err := s.repo.GetNotifications(ctx, userID, filters)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("NotificationService", "GetNotifications", err, "FailedToGetNotifications")
+	}
+	return notifications, nil
+}
+
+// SetCertificateExpiry registra la fecha de vencimiento del certificado de firma del contribuyente, una fecha nil
+// desactiva la alerta de vencimiento
+func (s *NotificationService) SetCertificateExpiry(ctx context.Context, userID uint, expiresAt *time.Time) error {
+	if err := s.repo.SetCertificateExpiry(ctx, userID, expiresAt); err != nil {
+		logs.ErrorContext(ctx, "Failed to set certificate expiry", map[string]interface{}{
+			"userID": userID,
+			"error":  err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("NotificationService", "SetCertificateExpiry", err, "FailedToSetCertificateExpiry")
+	}
+	return nil
+}
+
+// eventAlert construye la alerta de un evento con el contribuyente propietario de la sucursal
+func (s *NotificationService) eventAlert(ctx context.Context, event *webhookModels.Event) (*models.Alert, error) {
+	taxpayer, err := s.repo.GetBranchTaxpayer(ctx, event.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	generationCode, _ := event.Data["generation_code"].(string)
+
+	var alert *models.Alert
+	switch event.Type {
+	case webhookModels.EventDTEContingencyStored:
+		reason, _ := event.Data["reason"].(string)
+		alert = models.NewAlert(models.AlertContingencyEntered, *taxpayer, fmt.Sprintf("branch:%d", event.BranchID),
+			fmt.Sprintf("La sucursal %d comenzó a almacenar documentos en contingencia: %s. Los documentos deben "+
+				"transmitirse a Hacienda dentro de las %d horas siguientes a su emisión.",
+				event.BranchID, reason, int(models.ContingencyDeadline.Hours())), event.OccurredAt)
+	default:
+		description, _ := event.Data["description"].(string)
+		alert = models.NewAlert(models.AlertDTERejected, *taxpayer, "document:"+generationCode,
+			fmt.Sprintf("Hacienda rechazó el documento %s: %s", generationCode, description), event.OccurredAt)
+		alert.DocumentID = generationCode
+	}
+
+	alert.BranchID = event.BranchID
+	for key, value := range event.Data {
+		alert.Data[key] = value
+	}
+	return alert, nil
+}
+
+// certificateAlert construye la alerta del vencimiento del certificado de firma de un contribuyente
+func certificateAlert(certificate models.CertificateExpiry, now time.Time) *models.Alert {
+	message := fmt.Sprintf("El certificado de firma vence el %s, quedan %d días para renovarlo y cargarlo en el firmador.",
+		certificate.ExpiresAt.Format(dateLayout), int(math.Ceil(certificate.ExpiresAt.Sub(now).Hours()/24)))
+	if !certificate.ExpiresAt.After(now) {
+		message = fmt.Sprintf("El certificado de firma venció el %s, los documentos no podrán firmarse hasta cargar "+
+			"un certificado vigente.", certificate.ExpiresAt.Format(dateLayout))
+	}
+
+	alert := models.NewAlert(models.AlertCertificateExpiring, certificate.Taxpayer,
+		fmt.Sprintf("user:%d", certificate.UserID), message, now)
+	alert.Data["expires_at"] = certificate.ExpiresAt
+	return alert
+}
+
+// contingencyDeadlineAlert construye la alerta de los documentos de una sucursal próximos al plazo de contingencia
+func contingencyDeadlineAlert(branch models.ContingencyBacklog, now time.Time) *models.Alert {
+	deadline := branch.OldestAt.Add(models.ContingencyDeadline)
+	message := fmt.Sprintf("La sucursal %d tiene %d documentos pendientes en contingencia, el plazo de transmisión "+
+		"del más antiguo vence el %s.", branch.BranchID, branch.Documents, deadline.Format(dateLayout))
+	if !deadline.After(now) {
+		message = fmt.Sprintf("La sucursal %d tiene %d documentos pendientes en contingencia, el plazo de transmisión "+
+			"del más antiguo venció el %s.", branch.BranchID, branch.Documents, deadline.Format(dateLayout))
+	}
+
+	alert := models.NewAlert(models.AlertContingencyDeadline, branch.Taxpayer, fmt.Sprintf("branch:%d", branch.BranchID), message, now)
+	alert.BranchID = branch.BranchID
+	alert.Data["documents"] = branch.Documents
+	alert.Data["oldest_at"] = branch.OldestAt
+	alert.Data["deadline"] = deadline
+	return alert
+}
+
+// sequenceGapAlert construye la alerta de un número de control rechazado por Hacienda
+func sequenceGapAlert(gap models.SequenceGap, now time.Time) *models.Alert {
+	alert := models.NewAlert(models.AlertSequenceGap, gap.Taxpayer, fmt.Sprintf("gap:%d", gap.ID),
+		fmt.Sprintf("Hacienda rechazó el número de control %d del año %d para el tipo de DTE %s en la sucursal %d, "+
+			"la numeración presenta un salto: %s", gap.SequenceNumber, gap.Year, gap.DTEType, gap.BranchID, gap.FailureReason), now)
+	alert.BranchID = gap.BranchID
+	alert.Data["dte_type"] = gap.DTEType
+	alert.Data["sequence_number"] = gap.SequenceNumber
+	alert.Data["year"] = gap.Year
+	alert.Data["failure_reason"] = gap.FailureReason
+	return alert
+}
+
+// buildNotifications construye las notificaciones de una alerta por canal, el canal de correo se omite si el envío de
+// correos está deshabilitado
+func (s *NotificationService) buildNotifications(rule models.Rule, alert *models.Alert, recipients []models.Recipient) []models.Notification {
+	now := utils.TimeNow()
+	base := models.Notification{
+		UserID:        alert.UserID,
+		Alert:         alert.Type,
+		Message:       alert.Message,
+		Status:        models.NotificationPending,
+		NextAttemptAt: &now,
+		DeliveryAt:    now,
+	}
+	if alert.DocumentID != "" {
+		base.DocumentID = utils.ToStringPointer(alert.DocumentID)
+	}
+
+	var notifications []models.Notification
+	for _, channel := range rule.Channels {
+		switch channel {
+		case models.ChannelEmail:
+			if s.transport == nil {
+				continue
+			}
+			for _, recipient := range recipients {
+				n := base
+				n.Channel = channel
+				n.Recipient = utils.ToStringPointer(recipient.Email)
+				notifications = append(notifications, n)
+			}
+		case models.ChannelWebhook:
+			if s.webhooks == nil {
+				continue
+			}
+			n := base
+			n.Channel = channel
+			notifications = append(notifications, n)
+		}
+	}
+
+	return notifications
+}
+
+// acquireCooldown registra la alerta del sujeto en Redis, retorna false si el sujeto ya recibió la alerta dentro del
+// cooldown de la regla. Si Redis no está disponible la alerta se genera para no perder avisos
+func (s *NotificationService) acquireCooldown(ctx context.Context, rule models.Rule, alert *models.Alert) bool {
+	if s.redis == nil || alert.SubjectKey == "" || rule.Cooldown <= 0 {
+		return true
+	}
+
+	acquired, err := s.redis.SetNX(ctx, cooldownKey(alert), utils.TimeNow().Unix(), rule.Cooldown).Result()
+	if err != nil {
+		logs.WarnContext(ctx, "Failed to check notification cooldown", map[string]interface{}{
+			"alert": alert.Type,
+			"error": err.Error(),
+		})
+		return true
+	}
+
+	return acquired
+}
+
+// releaseCooldown elimina el cooldown de una alerta que no se pudo registrar para que se genere en la siguiente ocasión
+func (s *NotificationService) releaseCooldown(ctx context.Context, rule models.Rule, alert *models.Alert) {
+	if s.redis == nil || alert.SubjectKey == "" || rule.Cooldown <= 0 {
+		return
+	}
+
+	if err := s.redis.Del(ctx, cooldownKey(alert)).Err(); err != nil {
+		logs.WarnContext(ctx, "Failed to release notification cooldown", map[string]interface{}{
+			"alert": alert.Type,
+			"error": err.Error(),
+		})
+	}
+}
+
+// cooldownKey llave de Redis del cooldown de una alerta
+func cooldownKey(alert *models.Alert) string {
+	return cooldownKeyPrefix + alert.Type + ":" + alert.SubjectKey
+}
+
+// dispatch realiza una notificación en segundo plano, el contexto conserva los valores de la solicitud que generó la
+// alerta (ID de la solicitud y traza) pero no su cancelación
+func (s *NotificationService) dispatch(parent context.Context, n models.Notification) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), notificationLease)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logs.ErrorContext(ctx, "Panic while delivering notification", map[string]interface{}{
+				"notificationID": n.ID,
+				"panic":          fmt.Sprint(r),
+			})
+		}
+	}()
+
+	s.claimAndAttempt(ctx, &n)
+}
+
+// claimAndAttempt reserva una notificación y realiza un intento, retorna false si otro proceso ya la reservó
+func (s *NotificationService) claimAndAttempt(ctx context.Context, n *models.Notification) bool {
+	// 1. Reservar la notificación para evitar intentos simultáneos desde el job y desde la generación de la alerta
+	now := utils.TimeNow()
+	claimed, err := s.repo.ClaimNotification(ctx, n.ID, now, now.Add(notificationLease))
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to claim notification", map[string]interface{}{
+			"notificationID": n.ID,
+			"error":          err.Error(),
+		})
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	// 2. Obtener la alerta registrada en el evento de dominio, una alerta ilegible no puede reintentarse
+	var alert models.Alert
+	if err = json.Unmarshal(n.Payload, &alert); err != nil {
+		s.recordResult(ctx, n, err.Error(), true)
+		return true
+	}
+
+	// 3. Realizar el intento por el canal de la notificación y registrar su resultado
+	errMessage := ""
+	if err = s.send(ctx, n, &alert); err != nil {
+		errMessage = err.Error()
+	}
+	s.recordResult(ctx, n, errMessage, false)
+	return true
+}
+
+// send entrega la alerta por el canal de la notificación
+func (s *NotificationService) send(ctx context.Context, n *models.Notification, alert *models.Alert) error {
+	switch n.Channel {
+	case models.ChannelEmail:
+		if s.transport == nil {
+			return fmt.Errorf("mail delivery is disabled")
+		}
+		if n.Recipient == nil {
+			return fmt.Errorf("notification has no recipient")
+		}
+		return s.transport.Send(ctx, s.buildMessage(alert, *n.Recipient))
+	case models.ChannelWebhook:
+		if s.webhooks == nil {
+			return fmt.Errorf("webhook delivery is disabled")
+		}
+
+		// Los reintentos de las entregas a cada suscripción se registran en las entregas de webhooks
+		s.webhooks.Emit(ctx, &webhookModels.Event{
+			Type:       webhookModels.EventNotificationCreated,
+			BranchID:   alert.BranchID,
+			UserID:     alert.UserID,
+			OccurredAt: alert.OccurredAt,
+			Data: map[string]interface{}{
+				"notification_id": n.ID,
+				"alert":           alert.Type,
+				"message":         alert.Message,
+				"document_id":     alert.DocumentID,
+				"data":            alert.Data,
+			},
+		})
+		return nil
+	default:
+		return fmt.Errorf("unsupported notification channel %s", n.Channel)
+	}
+}
+
+// buildMessage construye el correo de una alerta para un destinatario
+func (s *NotificationService) buildMessage(alert *models.Alert, recipient string) *deliveryModels.EmailMessage {
+	subject := models.Rules[alert.Type].Subject
+	if alert.BusinessName != "" {
+		subject = fmt.Sprintf("%s - %s", subject, alert.BusinessName)
+	}
+
+	var body strings.Builder
+	body.WriteString(alert.Message)
+	body.WriteString("\n\n")
+	fmt.Fprintf(&body, "Contribuyente: %s (NIT %s)\n", alert.BusinessName, alert.NIT)
+	if alert.BranchID != 0 {
+		fmt.Fprintf(&body, "Sucursal: %d\n", alert.BranchID)
+	}
+	if alert.DocumentID != "" {
+		fmt.Fprintf(&body, "Documento: %s\n", alert.DocumentID)
+	}
+	fmt.Fprintf(&body, "Fecha: %s\n\n", alert.OccurredAt.Format(dateLayout))
+	body.WriteString("Este es un mensaje automático del sistema de facturación electrónica, por favor no responda a este correo.")
+
+	return &deliveryModels.EmailMessage{
+		From:     s.from,
+		FromName: s.fromName,
+		To:       []string{recipient},
+		Subject:  subject,
+		TextBody: body.String(),
+	}
+}
+
+// recordResult actualiza el estado de una notificación según el resultado de un intento. Las notificaciones fallidas
+// se reprograman con espera exponencial hasta agotar sus intentos, o se descartan de inmediato si discard es verdadero
+func (s *NotificationService) recordResult(ctx context.Context, n *models.Notification, errMessage string, discard bool) {
+	now := utils.TimeNow()
+	n.Attempts++
+	n.ErrorMessage = nil
+	n.DeliveryAt = now
+
+	switch {
+	case errMessage == "":
+		n.Status = models.NotificationSent
+		n.NextAttemptAt = nil
+	case discard || n.Attempts >= models.MaxNotificationAttempts:
+		n.Status = models.NotificationFailed
+		n.NextAttemptAt = nil
+	default:
+		next := now.Add(models.RetryDelay(n.Attempts))
+		n.Status = models.NotificationPending
+		n.NextAttemptAt = &next
+	}
+
+	if errMessage != "" {
+		if len(errMessage) > maxErrorLength {
+			errMessage = errMessage[:maxErrorLength]
+		}
+		n.ErrorMessage = &errMessage
+	}
+
+	if err := s.repo.UpdateNotification(context.WithoutCancel(ctx), n); err != nil {
+		logs.ErrorContext(ctx, "Failed to update notification", map[string]interface{}{
+			"notificationID": n.ID,
+			"error":          err.Error(),
+		})
+		return
+	}
+
+	if n.Status == models.NotificationFailed {
+		logs.WarnContext(ctx, "Notification delivery failed", map[string]interface{}{
+			"notificationID": n.ID,
+			"alert":          n.Alert,
+			"channel":        n.Channel,
+			"attempts":       n.Attempts,
+			"error":          errMessage,
+		})
+	}
+}
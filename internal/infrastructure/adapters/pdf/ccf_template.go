@@ -0,0 +1,87 @@
+package pdf
+
+import (
+	"encoding/json"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// ccfTemplate plantilla para el Comprobante de Crédito Fiscal Electrónico (03), los precios no incluyen IVA
+type ccfTemplate struct{}
+
+func (t *ccfTemplate) Title() string {
+	return "Comprobante de crédito fiscal"
+}
+
+func (t *ccfTemplate) Render(b *pdfBuilder, doc *models.PDFDocument) error {
+	var document structs.CCFDTEResponse
+	if err := json.Unmarshal(doc.JSONData, &document); err != nil {
+		return err
+	}
+
+	// 1. Encabezado, emisor y receptor
+	b.header(t.Title(), doc, identificationRows(doc, document.Identificacion))
+	applyStatusWatermark(b, doc)
+	b.parties("Emisor", issuerRows(document.Emisor), "Receptor", receiverRows(document.Receptor))
+	relatedDocumentsTable(b, document.DocumentoRelacionado)
+
+	// 2. Cuerpo del documento
+	b.sectionTitle("Detalle")
+	rows := make([][]string, len(document.CuerpoDocumento))
+	for i, item := range document.CuerpoDocumento {
+		rows[i] = []string{
+			quantity(item.Cantidad),
+			utils.PointerToString(item.Codigo),
+			item.Descripcion,
+			money(item.PrecioUni),
+			money(item.MontoDescu),
+			money(item.VentaNoSuj),
+			money(item.VentaExenta),
+			money(item.VentaGravada),
+		}
+	}
+	b.table([]column{
+		{Title: "Cantidad", Width: 15, Align: "R"},
+		{Title: "Código", Width: 20, Align: "L"},
+		{Title: "Descripción", Width: b.contentW - 135, Align: "L"},
+		{Title: "Precio unitario", Width: 20, Align: "R"},
+		{Title: "Descuento", Width: 20, Align: "R"},
+		{Title: "No sujetas", Width: 20, Align: "R"},
+		{Title: "Exentas", Width: 20, Align: "R"},
+		{Title: "Gravadas", Width: 20, Align: "R"},
+	}, rows)
+
+	// 3. Resumen, en el CCF los tributos se detallan por separado
+	if summary := document.Resumen; summary != nil {
+		totals := [][2]string{
+			{"Suma de ventas", money(summary.SubTotalVentas)},
+			{"Total descuentos", money(summary.TotalDescu)},
+		}
+		for _, tax := range summary.Tributos {
+			totals = append(totals, [2]string{tax.Descripcion, money(tax.Valor)})
+		}
+
+		perceived := 0.0
+		if summary.IvaPerci1 != nil {
+			perceived = *summary.IvaPerci1
+		}
+
+		totals = append(totals,
+			[2]string{"Sub-total", money(summary.SubTotal)},
+			[2]string{"IVA percibido", money(perceived)},
+			[2]string{"IVA retenido", money(summary.IvaRete1)},
+			[2]string{"Retención de renta", money(summary.ReteRenta)},
+			[2]string{"Monto total de la operación", money(summary.MontoTotalOperacion)},
+			[2]string{"Total no gravado", money(summary.TotalNoGravado)},
+			[2]string{"Total a pagar", money(summary.TotalPagar)},
+		)
+
+		b.totals(totals, amountInLetters(summary.TotalLetras, summary.TotalPagar))
+		b.keyValueRows([][2]string{{"Condición de la operación", operationConditionName(summary.CondicionOperacion)}})
+	}
+
+	extensionAndAppendix(b, document.Extension, document.Apendice)
+	return nil
+}
@@ -0,0 +1,100 @@
+package pdf
+
+import (
+	"encoding/json"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// creditNoteTemplate plantilla para la Nota de Crédito Electrónica (05), cada ítem referencia al documento ajustado
+type creditNoteTemplate struct{}
+
+func (t *creditNoteTemplate) Title() string {
+	return "Nota de crédito"
+}
+
+func (t *creditNoteTemplate) Render(b *pdfBuilder, doc *models.PDFDocument) error {
+	var document structs.CreditNoteDTEResponse
+	if err := json.Unmarshal(doc.JSONData, &document); err != nil {
+		return err
+	}
+
+	// 1. Encabezado, emisor y receptor
+	issuer := structs.DTEIssuer{
+		NIT:             document.Emisor.NIT,
+		NRC:             document.Emisor.NRC,
+		Nombre:          document.Emisor.Nombre,
+		DescActividad:   document.Emisor.DescActividad,
+		Direccion:       document.Emisor.Direccion,
+		Telefono:        document.Emisor.Telefono,
+		Correo:          document.Emisor.Correo,
+		NombreComercial: document.Emisor.NombreComercial,
+	}
+	b.header(t.Title(), doc, identificationRows(doc, document.Identificacion))
+	applyStatusWatermark(b, doc)
+	b.parties("Emisor", issuerRows(issuer), "Receptor", receiverRows(document.Receptor))
+	relatedDocumentsTable(b, document.DocumentoRelacionado)
+
+	// 2. Cuerpo del documento
+	b.sectionTitle("Detalle")
+	rows := make([][]string, len(document.CuerpoDocumento))
+	for i, item := range document.CuerpoDocumento {
+		rows[i] = []string{
+			quantity(item.Cantidad),
+			utils.PointerToString(item.NumeroDocumento),
+			item.Descripcion,
+			money(item.PrecioUni),
+			money(item.MontoDescu),
+			money(item.VentaNoSuj),
+			money(item.VentaExenta),
+			money(item.VentaGravada),
+		}
+	}
+	b.table([]column{
+		{Title: "Cantidad", Width: 15, Align: "R"},
+		{Title: "Documento relacionado", Width: 35, Align: "L"},
+		{Title: "Descripción", Width: b.contentW - 150, Align: "L"},
+		{Title: "Precio unitario", Width: 20, Align: "R"},
+		{Title: "Descuento", Width: 20, Align: "R"},
+		{Title: "No sujetas", Width: 20, Align: "R"},
+		{Title: "Exentas", Width: 20, Align: "R"},
+		{Title: "Gravadas", Width: 20, Align: "R"},
+	}, rows)
+
+	// 3. Resumen
+	if summary := document.Resumen; summary != nil {
+		totals := [][2]string{
+			{"Suma de ventas", money(summary.SubTotalVentas)},
+			{"Total descuentos", money(summary.TotalDescu)},
+		}
+		for _, tax := range summary.Tributos {
+			totals = append(totals, [2]string{tax.Descripcion, money(tax.Valor)})
+		}
+		totals = append(totals,
+			[2]string{"Sub-total", money(summary.SubTotal)},
+			[2]string{"IVA percibido", money(summary.IvaPerci1)},
+			[2]string{"IVA retenido", money(summary.IvaRete1)},
+			[2]string{"Retención de renta", money(summary.ReteRenta)},
+			[2]string{"Monto total de la operación", money(summary.MontoTotalOperacion)},
+		)
+
+		b.totals(totals, amountInLetters(summary.TotalLetras, summary.MontoTotalOperacion))
+		b.keyValueRows([][2]string{{"Condición de la operación", operationConditionName(summary.CondicionOperacion)}})
+	}
+
+	// 4. Extensión y apéndice
+	var extension *structs.DTEExtension
+	if document.Extension != nil {
+		extension = &structs.DTEExtension{
+			NombreEntrega:    document.Extension.NombreEntrega,
+			DocumentoEntrega: document.Extension.DocumentoEntrega,
+			NombreRecibe:     document.Extension.NombreRecibe,
+			DocumentoRecibe:  document.Extension.DocumentoRecibe,
+			Observacion:      document.Extension.Observacion,
+		}
+	}
+	extensionAndAppendix(b, extension, document.Apendice)
+	return nil
+}
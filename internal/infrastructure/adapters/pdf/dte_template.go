@@ -0,0 +1,186 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// dteTemplate define el comportamiento de una plantilla de representación gráfica para un tipo de DTE
+type dteTemplate interface {
+	// Title retorna el nombre del tipo de documento que se muestra en el encabezado
+	Title() string
+	// Render dibuja el contenido del documento utilizando el builder
+	Render(b *pdfBuilder, doc *models.PDFDocument) error
+}
+
+// identificationRows construye los datos de identificación que se muestran en el encabezado del documento
+func identificationRows(doc *models.PDFDocument, identification *structs.DTEIdentification) map[string]string {
+	rows := map[string]string{
+		"Código de generación": doc.GenerationCode,
+		"Número de control":    doc.ControlNumber,
+		"Sello de recepción":   utils.PointerToString(doc.ReceptionStamp),
+		"Tipo de transmisión":  transmissionName(doc.Transmission),
+	}
+
+	if rows["Sello de recepción"] == "" {
+		rows["Sello de recepción"] = "Pendiente"
+	}
+
+	if identification != nil {
+		rows["Modelo de facturación"] = billingModelName(identification.TipoModelo)
+		rows["Fecha y hora de emisión"] = fmt.Sprintf("%s %s", identification.FecEmi, identification.HorEmi)
+	}
+
+	return rows
+}
+
+// issuerRows construye la información del emisor a partir de la estructura común de los DTE
+func issuerRows(issuer structs.DTEIssuer) [][2]string {
+	return [][2]string{
+		{"Nombre", issuer.Nombre},
+		{"Nombre comercial", utils.PointerToString(issuer.NombreComercial)},
+		{"NIT", issuer.NIT},
+		{"NRC", issuer.NRC},
+		{"Actividad", issuer.DescActividad},
+		{"Dirección", formatAddress(&issuer.Direccion)},
+		{"Teléfono", issuer.Telefono},
+		{"Correo", issuer.Correo},
+	}
+}
+
+// receiverRows construye la información del receptor a partir de la estructura común de los DTE
+func receiverRows(receiver structs.DTEReceiver) [][2]string {
+	document := utils.PointerToString(receiver.NIT)
+	if document == "" {
+		document = utils.PointerToString(receiver.NumDocumento)
+	}
+
+	return [][2]string{
+		{"Nombre", utils.PointerToString(receiver.Nombre)},
+		{"Nombre comercial", utils.PointerToString(receiver.NombreComercial)},
+		{"Documento", document},
+		{"NRC", utils.PointerToString(receiver.NRC)},
+		{"Actividad", utils.PointerToString(receiver.DescActividad)},
+		{"Dirección", formatAddress(receiver.Direccion)},
+		{"Teléfono", utils.PointerToString(receiver.Telefono)},
+		{"Correo", utils.PointerToString(receiver.Correo)},
+	}
+}
+
+// relatedDocumentsTable dibuja la tabla de documentos relacionados si existen
+func relatedDocumentsTable(b *pdfBuilder, related []structs.DTERelatedDocument) {
+	if len(related) == 0 {
+		return
+	}
+
+	b.sectionTitle("Documentos relacionados")
+	rows := make([][]string, len(related))
+	for i, doc := range related {
+		generation := "Físico"
+		if doc.TipoGeneracion == constants.ElectronicDocument {
+			generation = "Electrónico"
+		}
+		rows[i] = []string{dteTypeName(doc.TipoDocumento), generation, doc.NumeroDocumento, doc.FechaEmision}
+	}
+
+	b.table([]column{
+		{Title: "Tipo de documento", Width: b.contentW * 0.3, Align: "L"},
+		{Title: "Generación", Width: b.contentW * 0.15, Align: "C"},
+		{Title: "Número de documento", Width: b.contentW * 0.4, Align: "L"},
+		{Title: "Fecha de emisión", Width: b.contentW * 0.15, Align: "C"},
+	}, rows)
+}
+
+// extensionAndAppendix dibuja la extensión y el apéndice del documento si existen
+func extensionAndAppendix(b *pdfBuilder, extension *structs.DTEExtension, appendix []structs.DTEApendice) {
+	if extension != nil {
+		b.sectionTitle("Extensión")
+		b.keyValueRows([][2]string{
+			{"Entrega", strings.TrimSpace(extension.NombreEntrega + " " + extension.DocumentoEntrega)},
+			{"Recibe", strings.TrimSpace(extension.NombreRecibe + " " + extension.DocumentoRecibe)},
+			{"Observaciones", utils.PointerToString(extension.Observacion)},
+			{"Placa vehículo", utils.PointerToString(extension.PlacaVehiculo)},
+		})
+	}
+
+	if len(appendix) > 0 {
+		b.sectionTitle("Apéndice")
+		rows := make([][2]string, 0, len(appendix))
+		for _, item := range appendix {
+			rows = append(rows, [2]string{item.Etiqueta, item.Valor})
+		}
+		b.keyValueRows(rows)
+	}
+}
+
+// applyStatusWatermark dibuja una marca de agua según el estado del documento
+func applyStatusWatermark(b *pdfBuilder, doc *models.PDFDocument) {
+	switch doc.Status {
+	case constants.DocumentInvalid:
+		b.watermark("INVALIDADO")
+	case constants.DocumentRejected:
+		b.watermark("RECHAZADO")
+	case constants.DocumentPending:
+		b.watermark("PENDIENTE")
+	}
+}
+
+// amountInLetters retorna el total en letras del documento, si no existe se calcula a partir del monto
+func amountInLetters(letters string, amount float64) string {
+	if letters != "" {
+		return letters
+	}
+	return utils.InLetters(amount) + " USD"
+}
+
+// formatAddress concatena la dirección en una sola línea
+func formatAddress(address *structs.DTEAddress) string {
+	if address == nil {
+		return ""
+	}
+	return strings.Trim(fmt.Sprintf("%s, Municipio %s, Departamento %s", address.Complemento, address.Municipio, address.Departamento), ", ")
+}
+
+// taxTotal suma el valor de los tributos del resumen
+func taxTotal(taxes []structs.DTETax) float64 {
+	var total float64
+	for _, tax := range taxes {
+		total += tax.Valor
+	}
+	return total
+}
+
+// dteTypeName obtiene el nombre de un tipo de DTE
+func dteTypeName(dteType string) string {
+	switch dteType {
+	case constants.FacturaElectronica:
+		return "Factura"
+	case constants.CCFElectronico:
+		return "Comprobante de crédito fiscal"
+	case constants.NotaRemisionElectronica:
+		return "Nota de remisión"
+	case constants.NotaCreditoElectronica:
+		return "Nota de crédito"
+	case constants.NotaDebitoElectronica:
+		return "Nota de débito"
+	case constants.ComprobanteRetencionElectronico:
+		return "Comprobante de retención"
+	case constants.ComprobanteLiquidacionElectronico:
+		return "Comprobante de liquidación"
+	case constants.DocContableLiquidacionElectronico:
+		return "Documento contable de liquidación"
+	case constants.FacturaExportacionElectronica:
+		return "Factura de exportación"
+	case constants.FacturaSujetoExcluidoElectronica:
+		return "Factura de sujeto excluido"
+	case constants.ComprobanteDonacionElectronico:
+		return "Comprobante de donación"
+	default:
+		return dteType
+	}
+}
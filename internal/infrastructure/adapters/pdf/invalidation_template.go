@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"encoding/json"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// invalidationTemplate plantilla para el comprobante de invalidación de un documento previamente emitido
+type invalidationTemplate struct{}
+
+// invalidatedDocument contiene los campos del documento original necesarios para el comprobante,
+// se decodifica de forma independiente ya que aplica a cualquier tipo de DTE
+type invalidatedDocument struct {
+	Identificacion *structs.DTEIdentification `json:"identificacion"`
+	Emisor         structs.DTEIssuer          `json:"emisor"`
+	Receptor       structs.DTEReceiver        `json:"receptor"`
+	Resumen        struct {
+		TotalIva            float64          `json:"totalIva"`
+		Tributos            []structs.DTETax `json:"tributos"`
+		MontoTotalOperacion float64          `json:"montoTotalOperacion"`
+		TotalPagar          float64          `json:"totalPagar"`
+		TotalIvaRetenido    float64          `json:"totalIVAretenido"`
+	} `json:"resumen"`
+}
+
+func (t *invalidationTemplate) Title() string {
+	return "Comprobante de invalidación"
+}
+
+func (t *invalidationTemplate) Render(b *pdfBuilder, doc *models.PDFDocument) error {
+	var document invalidatedDocument
+	if err := json.Unmarshal(doc.JSONData, &document); err != nil {
+		return err
+	}
+
+	// 1. Encabezado y emisor
+	b.header(t.Title(), doc, identificationRows(doc, document.Identificacion))
+	b.sectionTitle("Emisor")
+	b.keyValueRows(issuerRows(document.Emisor))
+
+	// 2. Documento invalidado
+	emission := ""
+	if document.Identificacion != nil {
+		emission = document.Identificacion.FecEmi + " " + document.Identificacion.HorEmi
+	}
+
+	iva := document.Resumen.TotalIva
+	if iva == 0 {
+		iva = taxTotal(document.Resumen.Tributos)
+	}
+
+	total := document.Resumen.TotalPagar
+	if total == 0 {
+		total = document.Resumen.MontoTotalOperacion
+	}
+	if total == 0 {
+		total = document.Resumen.TotalIvaRetenido
+	}
+
+	b.sectionTitle("Documento invalidado")
+	b.keyValueRows([][2]string{
+		{"Tipo de documento", dteTypeName(doc.DTEType)},
+		{"Código de generación", doc.GenerationCode},
+		{"Número de control", doc.ControlNumber},
+		{"Sello de recepción", utils.PointerToString(doc.ReceptionStamp)},
+		{"Fecha de emisión", emission},
+		{"Fecha de invalidación", doc.UpdatedAt},
+		{"Receptor", utils.PointerToString(document.Receptor.Nombre)},
+	})
+
+	// 3. Montos del documento invalidado
+	b.totals([][2]string{
+		{"Monto de IVA", money(iva)},
+		{"Monto total invalidado", money(total)},
+	}, amountInLetters("", total))
+
+	b.paragraph("El documento descrito ha sido invalidado ante el Ministerio de Hacienda y no posee validez tributaria. " +
+		"Puede verificar su estado escaneando el código QR o ingresando al portal de consulta pública de Hacienda.")
+	return nil
+}
@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"encoding/json"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// invoiceTemplate plantilla para la Factura Electrónica (01), los precios incluyen IVA
+type invoiceTemplate struct{}
+
+func (t *invoiceTemplate) Title() string {
+	return "Factura"
+}
+
+func (t *invoiceTemplate) Render(b *pdfBuilder, doc *models.PDFDocument) error {
+	var document structs.CommonDTEDocument
+	if err := json.Unmarshal(doc.JSONData, &document); err != nil {
+		return err
+	}
+
+	// 1. Encabezado, emisor y receptor
+	b.header(t.Title(), doc, identificationRows(doc, document.Identificacion))
+	applyStatusWatermark(b, doc)
+	b.parties("Emisor", issuerRows(document.Emisor), "Receptor", receiverRows(document.Receptor))
+	relatedDocumentsTable(b, document.DocumentoRelacionado)
+
+	// 2. Cuerpo del documento
+	b.sectionTitle("Detalle")
+	rows := make([][]string, len(document.CuerpoDocumento))
+	for i, item := range document.CuerpoDocumento {
+		rows[i] = []string{
+			quantity(item.Cantidad),
+			utils.PointerToString(item.Codigo),
+			item.Descripcion,
+			money(item.PrecioUni),
+			money(item.MontoDescu),
+			money(item.VentaNoSuj),
+			money(item.VentaExenta),
+			money(item.VentaGravada),
+		}
+	}
+	b.table([]column{
+		{Title: "Cantidad", Width: 15, Align: "R"},
+		{Title: "Código", Width: 20, Align: "L"},
+		{Title: "Descripción", Width: b.contentW - 135, Align: "L"},
+		{Title: "Precio unitario", Width: 20, Align: "R"},
+		{Title: "Descuento", Width: 20, Align: "R"},
+		{Title: "No sujetas", Width: 20, Align: "R"},
+		{Title: "Exentas", Width: 20, Align: "R"},
+		{Title: "Gravadas", Width: 20, Align: "R"},
+	}, rows)
+
+	// 3. Resumen
+	if summary := document.Resumen; summary != nil {
+		b.totals([][2]string{
+			{"Suma de ventas", money(summary.SubTotalVentas)},
+			{"Total descuentos", money(summary.TotalDescu)},
+			{"Sub-total", money(summary.SubTotal)},
+			{"IVA incluido", money(summary.TotalIva)},
+			{"IVA retenido", money(summary.IvaRete1)},
+			{"Retención de renta", money(summary.ReteRenta)},
+			{"Monto total de la operación", money(summary.MontoTotalOperacion)},
+			{"Total no gravado", money(summary.TotalNoGravado)},
+			{"Total a pagar", money(summary.TotalPagar)},
+		}, amountInLetters(summary.TotalLetras, summary.TotalPagar))
+		b.keyValueRows([][2]string{{"Condición de la operación", operationConditionName(summary.CondicionOperacion)}})
+	}
+
+	extensionAndAppendix(b, document.Extension, document.Apendice)
+	return nil
+}
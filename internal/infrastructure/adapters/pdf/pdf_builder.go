@@ -0,0 +1,381 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+)
+
+const (
+	pageMargin   = 10.0
+	lineHeight   = 4.5
+	sectionSpace = 3.0
+	qrSize       = 32.0
+	logoMaxWidth = 40.0
+	logoHeight   = 20.0
+	fontFamily   = "Helvetica"
+)
+
+// column representa una columna de una tabla de ítems
+type column struct {
+	Title string
+	Width float64
+	Align string
+}
+
+// rgb representa un color en formato RGB
+type rgb struct {
+	r, g, b int
+}
+
+// pdfBuilder encapsula la librería de generación de PDF y expone los bloques comunes de la representación gráfica
+type pdfBuilder struct {
+	pdf        *fpdf.Fpdf
+	tr         func(string) string
+	primary    rgb
+	contentW   float64
+	branding   *models.Branding
+	footerText string
+}
+
+// newPDFBuilder crea una instancia de pdfBuilder configurada en tamaño carta con la marca de la sucursal
+func newPDFBuilder(branding *models.Branding) *pdfBuilder {
+	doc := fpdf.New("P", "mm", "Letter", "")
+	doc.SetMargins(pageMargin, pageMargin, pageMargin)
+	doc.SetAutoPageBreak(true, 15)
+	doc.AliasNbPages("")
+
+	if branding == nil {
+		branding = models.NewDefaultBranding(0)
+	}
+
+	pageW, _ := doc.GetPageSize()
+	b := &pdfBuilder{
+		pdf:        doc,
+		tr:         doc.UnicodeTranslatorFromDescriptor(""),
+		primary:    parseHexColor(branding.PrimaryColor),
+		contentW:   pageW - 2*pageMargin,
+		branding:   branding,
+		footerText: branding.FooterText,
+	}
+
+	doc.SetFooterFunc(b.footer)
+	doc.AddPage()
+	return b
+}
+
+// header dibuja el encabezado del documento: logo, título, datos de identificación y código QR
+func (b *pdfBuilder) header(title string, doc *models.PDFDocument, identification map[string]string) {
+	startY := b.pdf.GetY()
+
+	// 1. Logo de la sucursal, si no puede leerse se omite para no impedir la generación del documento
+	if len(b.branding.Logo) > 0 {
+		if err := b.registerLogo(); err == nil {
+			opts := fpdf.ImageOptions{ImageType: b.logoImageType()}
+			b.pdf.ImageOptions("logo", pageMargin, startY, 0, logoHeight, false, opts, 0, "")
+		} else {
+			b.pdf.ClearError()
+		}
+	}
+
+	// 2. Título del documento
+	b.pdf.SetXY(pageMargin+logoMaxWidth, startY)
+	b.setTextPrimary()
+	b.pdf.SetFont(fontFamily, "B", 11)
+	b.pdf.CellFormat(b.contentW-logoMaxWidth-qrSize, lineHeight+1, b.tr("DOCUMENTO TRIBUTARIO ELECTRÓNICO"), "", 2, "C", false, 0, "")
+	b.pdf.SetFont(fontFamily, "B", 10)
+	b.pdf.CellFormat(b.contentW-logoMaxWidth-qrSize, lineHeight+1, b.tr(strings.ToUpper(title)), "", 2, "C", false, 0, "")
+	b.resetText()
+
+	// 3. Código QR con el enlace de consulta pública de Hacienda
+	if doc.QRLink != "" {
+		if png, err := qrcode.Encode(doc.QRLink, qrcode.Medium, 256); err == nil {
+			opts := fpdf.ImageOptions{ImageType: "PNG"}
+			b.pdf.RegisterImageOptionsReader("qr", opts, bytes.NewReader(png))
+			b.pdf.ImageOptions("qr", pageMargin+b.contentW-qrSize, startY, qrSize, qrSize, false, opts, 0, doc.QRLink)
+		}
+	}
+
+	// 4. Datos de identificación del documento
+	b.pdf.SetXY(pageMargin+logoMaxWidth, startY+2*(lineHeight+1)+1)
+	keys := []string{"Código de generación", "Número de control", "Sello de recepción", "Modelo de facturación", "Tipo de transmisión", "Fecha y hora de emisión"}
+	for _, key := range keys {
+		value, ok := identification[key]
+		if !ok {
+			continue
+		}
+		b.pdf.SetX(pageMargin + logoMaxWidth)
+		b.pdf.SetFont(fontFamily, "B", 7)
+		b.pdf.CellFormat(35, lineHeight-1, b.tr(key+":"), "", 0, "L", false, 0, "")
+		b.pdf.SetFont(fontFamily, "", 7)
+		b.pdf.CellFormat(b.contentW-logoMaxWidth-qrSize-35, lineHeight-1, b.tr(value), "", 1, "L", false, 0, "")
+	}
+
+	if b.pdf.GetY() < startY+qrSize {
+		b.pdf.SetY(startY + qrSize)
+	}
+	b.pdf.Ln(sectionSpace)
+}
+
+// registerLogo registra el logo de la sucursal en el documento, retorna un error si la imagen no es válida
+func (b *pdfBuilder) registerLogo() error {
+	opts := fpdf.ImageOptions{ImageType: b.logoImageType()}
+	b.pdf.RegisterImageOptionsReader("logo", opts, bytes.NewReader(b.branding.Logo))
+	return b.pdf.Error()
+}
+
+// sectionTitle dibuja una barra de título de sección con el color de la marca
+func (b *pdfBuilder) sectionTitle(title string) {
+	b.pdf.SetFillColor(b.primary.r, b.primary.g, b.primary.b)
+	b.pdf.SetTextColor(255, 255, 255)
+	b.pdf.SetFont(fontFamily, "B", 8)
+	b.pdf.CellFormat(b.contentW, lineHeight+0.5, b.tr(strings.ToUpper(title)), "", 1, "L", true, 0, "")
+	b.resetText()
+}
+
+// parties dibuja en dos columnas la información del emisor y del receptor
+func (b *pdfBuilder) parties(issuerTitle string, issuer [][2]string, receiverTitle string, receiver [][2]string) {
+	half := b.contentW / 2
+	startY := b.pdf.GetY()
+
+	b.pdf.SetFillColor(b.primary.r, b.primary.g, b.primary.b)
+	b.pdf.SetTextColor(255, 255, 255)
+	b.pdf.SetFont(fontFamily, "B", 8)
+	b.pdf.CellFormat(half-1, lineHeight+0.5, b.tr(strings.ToUpper(issuerTitle)), "", 0, "L", true, 0, "")
+	b.pdf.SetX(pageMargin + half + 1)
+	b.pdf.CellFormat(half-1, lineHeight+0.5, b.tr(strings.ToUpper(receiverTitle)), "", 1, "L", true, 0, "")
+	b.resetText()
+
+	leftEnd := b.keyValueColumn(pageMargin, b.pdf.GetY(), half-1, issuer)
+	rightEnd := b.keyValueColumn(pageMargin+half+1, startY+lineHeight+0.5, half-1, receiver)
+
+	b.pdf.SetY(max(leftEnd, rightEnd))
+	b.pdf.Ln(sectionSpace)
+}
+
+// keyValueColumn dibuja una lista de pares llave-valor en una columna y retorna la posición Y final
+func (b *pdfBuilder) keyValueColumn(x, y, w float64, rows [][2]string) float64 {
+	b.pdf.SetXY(x, y)
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		b.pdf.SetX(x)
+		b.pdf.SetFont(fontFamily, "B", 7)
+		b.pdf.CellFormat(28, lineHeight-1, b.tr(row[0]+":"), "", 0, "L", false, 0, "")
+		b.pdf.SetFont(fontFamily, "", 7)
+		b.pdf.MultiCell(w-28, lineHeight-1, b.tr(row[1]), "", "L", false)
+	}
+	return b.pdf.GetY()
+}
+
+// keyValueRows dibuja una lista de pares llave-valor a todo lo ancho del documento
+func (b *pdfBuilder) keyValueRows(rows [][2]string) {
+	b.keyValueColumn(pageMargin, b.pdf.GetY(), b.contentW, rows)
+	b.pdf.Ln(sectionSpace)
+}
+
+// table dibuja una tabla con encabezado y filas, ajustando la altura de cada fila al texto más largo
+func (b *pdfBuilder) table(columns []column, rows [][]string) {
+	// 1. Encabezado
+	b.pdf.SetFillColor(b.primary.r, b.primary.g, b.primary.b)
+	b.pdf.SetTextColor(255, 255, 255)
+	b.pdf.SetFont(fontFamily, "B", 7)
+	for _, col := range columns {
+		b.pdf.CellFormat(col.Width, lineHeight+0.5, b.tr(col.Title), "1", 0, "C", true, 0, "")
+	}
+	b.pdf.Ln(-1)
+	b.resetText()
+
+	// 2. Filas
+	b.pdf.SetFont(fontFamily, "", 7)
+	for _, row := range rows {
+		rowHeight := lineHeight
+		for i, col := range columns {
+			lines := b.pdf.SplitLines([]byte(b.tr(row[i])), col.Width-1)
+			if h := float64(len(lines)) * (lineHeight - 1); h > rowHeight {
+				rowHeight = h
+			}
+		}
+
+		// 2.1 Salto de página manual para no partir una fila entre dos páginas
+		_, pageH := b.pdf.GetPageSize()
+		if b.pdf.GetY()+rowHeight > pageH-20 {
+			b.pdf.AddPage()
+		}
+
+		x, y := b.pdf.GetXY()
+		for i, col := range columns {
+			b.pdf.Rect(x, y, col.Width, rowHeight, "D")
+			b.pdf.SetXY(x, y)
+			b.pdf.MultiCell(col.Width, lineHeight-1, b.tr(row[i]), "", col.Align, false)
+			x += col.Width
+		}
+		b.pdf.SetXY(pageMargin, y+rowHeight)
+	}
+	b.pdf.Ln(sectionSpace)
+}
+
+// totals dibuja el resumen de montos alineado a la derecha y el total en letras a la izquierda
+func (b *pdfBuilder) totals(rows [][2]string, amountInLetters string) {
+	startY := b.pdf.GetY()
+	labelW, valueW := 45.0, 25.0
+	x := pageMargin + b.contentW - labelW - valueW
+
+	// 1. Total en letras
+	if amountInLetters != "" {
+		b.pdf.SetXY(pageMargin, startY)
+		b.pdf.SetFont(fontFamily, "B", 7)
+		b.pdf.CellFormat(x-pageMargin-3, lineHeight-1, b.tr("Valor en letras:"), "", 2, "L", false, 0, "")
+		b.pdf.SetFont(fontFamily, "", 7)
+		b.pdf.MultiCell(x-pageMargin-3, lineHeight-1, b.tr(amountInLetters), "", "L", false)
+	}
+	lettersEnd := b.pdf.GetY()
+
+	// 2. Montos, la última fila se resalta como el total a pagar
+	b.pdf.SetY(startY)
+	for i, row := range rows {
+		b.pdf.SetX(x)
+		style, fill := "", false
+		if i == len(rows)-1 {
+			style, fill = "B", true
+			b.pdf.SetFillColor(235, 235, 235)
+		}
+		b.pdf.SetFont(fontFamily, style, 7)
+		b.pdf.CellFormat(labelW, lineHeight, b.tr(row[0]), "1", 0, "L", fill, 0, "")
+		b.pdf.CellFormat(valueW, lineHeight, b.tr(row[1]), "1", 1, "R", fill, 0, "")
+	}
+
+	b.pdf.SetY(max(lettersEnd, b.pdf.GetY()))
+	b.pdf.Ln(sectionSpace)
+}
+
+// paragraph dibuja un bloque de texto libre
+func (b *pdfBuilder) paragraph(text string) {
+	b.pdf.SetFont(fontFamily, "", 7)
+	b.pdf.MultiCell(b.contentW, lineHeight-1, b.tr(text), "", "L", false)
+	b.pdf.Ln(sectionSpace)
+}
+
+// watermark dibuja una marca de agua diagonal en la página actual, se utiliza para documentos invalidados o pendientes
+func (b *pdfBuilder) watermark(text string) {
+	pageW, pageH := b.pdf.GetPageSize()
+	x, y := b.pdf.GetXY()
+
+	b.pdf.SetAlpha(0.15, "Normal")
+	b.pdf.SetFont(fontFamily, "B", 60)
+	b.pdf.SetTextColor(200, 0, 0)
+	b.pdf.TransformBegin()
+	b.pdf.TransformRotate(45, pageW/2, pageH/2)
+	textW := b.pdf.GetStringWidth(b.tr(text))
+	b.pdf.Text(pageW/2-textW/2, pageH/2, b.tr(text))
+	b.pdf.TransformEnd()
+	b.pdf.SetAlpha(1, "Normal")
+
+	b.resetText()
+	b.pdf.SetXY(x, y)
+}
+
+// footer dibuja el pie de página con el texto de la marca y la numeración
+func (b *pdfBuilder) footer() {
+	b.pdf.SetY(-12)
+	b.pdf.SetFont(fontFamily, "I", 6)
+	b.pdf.SetTextColor(110, 110, 110)
+	if b.footerText != "" {
+		b.pdf.CellFormat(b.contentW*0.8, lineHeight-1, b.tr(b.footerText), "", 0, "L", false, 0, "")
+	} else {
+		b.pdf.CellFormat(b.contentW*0.8, lineHeight-1, "", "", 0, "L", false, 0, "")
+	}
+	b.pdf.CellFormat(b.contentW*0.2, lineHeight-1, fmt.Sprintf(b.tr("Página %d de {nb}"), b.pdf.PageNo()), "", 0, "R", false, 0, "")
+	b.resetText()
+}
+
+// output genera los bytes del documento PDF
+func (b *pdfBuilder) output() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := b.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *pdfBuilder) setTextPrimary() {
+	b.pdf.SetTextColor(b.primary.r, b.primary.g, b.primary.b)
+}
+
+func (b *pdfBuilder) resetText() {
+	b.pdf.SetTextColor(0, 0, 0)
+}
+
+func (b *pdfBuilder) logoImageType() string {
+	if strings.EqualFold(b.branding.LogoFormat, "JPG") {
+		return "JPG"
+	}
+	return "PNG"
+}
+
+// parseHexColor convierte un color en formato #RRGGBB a RGB, si el color es inválido se utiliza el color por defecto
+func parseHexColor(hex string) rgb {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		hex = strings.TrimPrefix(models.DefaultPrimaryColor, "#")
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return parseHexColor(models.DefaultPrimaryColor)
+	}
+
+	return rgb{
+		r: int(value >> 16 & 0xFF),
+		g: int(value >> 8 & 0xFF),
+		b: int(value & 0xFF),
+	}
+}
+
+// money formatea un monto con el símbolo de dólar y dos decimales
+func money(amount float64) string {
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// quantity formatea una cantidad eliminando los ceros decimales innecesarios
+func quantity(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// billingModelName obtiene la descripción del modelo de facturación
+func billingModelName(model int) string {
+	if model == constants.ModeloFacturacionDiferido {
+		return "Diferido"
+	}
+	return "Previo"
+}
+
+// transmissionName obtiene la descripción del tipo de transmisión
+func transmissionName(transmission string) string {
+	if transmission == constants.TransmissionContingency {
+		return "Contingencia"
+	}
+	return "Normal"
+}
+
+// operationConditionName obtiene la descripción de la condición de la operación
+func operationConditionName(condition int) string {
+	switch condition {
+	case constants.Cash:
+		return "Contado"
+	case constants.Credit:
+		return "Crédito"
+	case constants.Other:
+		return "Otro"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,191 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	pdfPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// PDFService genera la representación gráfica de los DTE utilizando plantillas por tipo de documento
+type PDFService struct {
+	brandingRepo         pdfPorts.BrandingRepositoryPort
+	templates            map[string]dteTemplate
+	invalidationTemplate dteTemplate
+}
+
+// NewPDFService crea una instancia de PDFService. Recibe el repositorio de configuración de marca.
+func NewPDFService(brandingRepo pdfPorts.BrandingRepositoryPort) pdfPorts.PDFManager {
+	return &PDFService{
+		brandingRepo: brandingRepo,
+		templates: map[string]dteTemplate{
+			constants.FacturaElectronica:              &invoiceTemplate{},
+			constants.CCFElectronico:                  &ccfTemplate{},
+			constants.NotaCreditoElectronica:          &creditNoteTemplate{},
+			constants.ComprobanteRetencionElectronico: &retentionTemplate{},
+		},
+		invalidationTemplate: &invalidationTemplate{},
+	}
+}
+
+// GenerateDTE genera la representación gráfica (PDF) de un DTE según su tipo
+func (s *PDFService) GenerateDTE(ctx context.Context, document *models.PDFDocument) ([]byte, error) {
+	// 1. Seleccionar la plantilla según el tipo de comprobante y de documento
+	template, err := s.selectTemplate(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Si no se proporcionó la marca, utilizar la configuración por defecto
+	branding := document.Branding
+	if branding == nil {
+		branding = models.NewDefaultBranding(0)
+	}
+
+	// 3. Dibujar el documento
+	builder := newPDFBuilder(branding)
+	if err = template.Render(builder, document); err != nil {
+		logs.ErrorContext(ctx, "Failed to render PDF template", map[string]interface{}{
+			"dteType":        document.DTEType,
+			"generationCode": document.GenerationCode,
+			"error":          err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("PDFService", "GenerateDTE", err, "FailedToGeneratePDF", document.GenerationCode)
+	}
+
+	// 4. Generar los bytes del PDF
+	content, err := builder.output()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to output PDF", map[string]interface{}{
+			"generationCode": document.GenerationCode,
+			"error":          err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("PDFService", "GenerateDTE", err, "FailedToGeneratePDF", document.GenerationCode)
+	}
+
+	return content, nil
+}
+
+// GenerateFromDocument genera el PDF de un DTE almacenado utilizando la configuración de marca de su sucursal
+func (s *PDFService) GenerateFromDocument(ctx context.Context, document *dte.DTEDocument, receiptType string) ([]byte, string, error) {
+	// 1. Extraer la identificación para construir el enlace de consulta de Hacienda
+	identification, err := utils.ExtractAuxiliarIdentificationFromStringJSON(document.Details.JSONData)
+	if err != nil {
+		return nil, "", shared_error.NewFormattedGeneralServiceWithError("PDFService", "GenerateFromDocument", err, "FailedToGeneratePDF", document.Details.ID)
+	}
+
+	emissionDate, err := time.Parse("2006-01-02", identification.Identification.EmissionDate)
+	if err != nil {
+		emissionDate = document.CreatedAt
+	}
+
+	// 2. Obtener la configuración de marca de la sucursal
+	branding, err := s.GetBranding(ctx, document.BranchID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 3. Generar el PDF
+	content, err := s.GenerateDTE(ctx, &models.PDFDocument{
+		DTEType:        document.Details.DTEType,
+		ReceiptType:    receiptType,
+		ControlNumber:  document.Details.ControlNumber,
+		GenerationCode: document.Details.ID,
+		ReceptionStamp: document.Details.ReceptionStamp,
+		Status:         document.Details.Status,
+		Transmission:   document.Details.Transmission,
+		QRLink:         response.GenerateQRLink(identification.Identification.Ambient, document.Details.ID, emissionDate),
+		EmissionDate:   emissionDate,
+		UpdatedAt:      document.UpdatedAt.Format("2006-01-02 15:04:05"),
+		JSONData:       []byte(document.Details.JSONData),
+		Branding:       branding,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("%s.pdf", document.Details.ControlNumber)
+	if receiptType == models.ReceiptInvalidation {
+		filename = fmt.Sprintf("%s-invalidacion.pdf", document.Details.ControlNumber)
+	}
+
+	return content, filename, nil
+}
+
+// GetBranding obtiene la configuración de marca de una sucursal, si no existe se retorna la configuración por defecto
+func (s *PDFService) GetBranding(ctx context.Context, branchID uint) (*models.Branding, error) {
+	branding, err := s.brandingRepo.GetByBranchID(ctx, branchID)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to get branch branding", map[string]interface{}{
+			"branchID": branchID,
+			"error":    err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("PDFService", "GetBranding", err, "FailedToGetBranding")
+	}
+
+	if branding == nil {
+		return models.NewDefaultBranding(branchID), nil
+	}
+
+	if branding.PrimaryColor == "" {
+		branding.PrimaryColor = models.DefaultPrimaryColor
+	}
+
+	return branding, nil
+}
+
+// SaveBranding valida y almacena la configuración de marca de una sucursal
+func (s *PDFService) SaveBranding(ctx context.Context, branding *models.Branding) error {
+	// 1. Validar la configuración
+	if err := branding.Validate(); err != nil {
+		return err
+	}
+
+	if branding.PrimaryColor == "" {
+		branding.PrimaryColor = models.DefaultPrimaryColor
+	}
+
+	// 2. Validar que el logo pueda ser dibujado antes de almacenarlo
+	if len(branding.Logo) > 0 {
+		if err := newPDFBuilder(branding).registerLogo(); err != nil {
+			return shared_error.NewFormattedGeneralServiceWithError("PDFService", "SaveBranding", err, "InvalidLogo", branding.LogoFormat)
+		}
+	}
+
+	// 3. Almacenar la configuración
+	if err := s.brandingRepo.Upsert(ctx, branding); err != nil {
+		logs.ErrorContext(ctx, "Failed to save branch branding", map[string]interface{}{
+			"branchID": branding.BranchID,
+			"error":    err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceWithError("PDFService", "SaveBranding", err, "FailedToSaveBranding")
+	}
+
+	branding.HasLogo = len(branding.Logo) > 0
+	return nil
+}
+
+// selectTemplate obtiene la plantilla que corresponde al documento
+func (s *PDFService) selectTemplate(document *models.PDFDocument) (dteTemplate, error) {
+	if document.ReceiptType == models.ReceiptInvalidation {
+		if document.Status != constants.DocumentInvalid {
+			return nil, shared_error.NewFormattedGeneralServiceError("PDFService", "GenerateDTE", "DocumentNotInvalidated", document.GenerationCode)
+		}
+		return s.invalidationTemplate, nil
+	}
+
+	template, ok := s.templates[document.DTEType]
+	if !ok {
+		return nil, shared_error.NewFormattedGeneralServiceError("PDFService", "GenerateDTE", "PDFTemplateNotFound", document.DTEType)
+	}
+
+	return template, nil
+}
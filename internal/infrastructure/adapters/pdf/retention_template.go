@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"encoding/json"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/response_mapper/structs"
+)
+
+// retentionTemplate plantilla para el Comprobante de Retención Electrónico (07)
+type retentionTemplate struct{}
+
+func (t *retentionTemplate) Title() string {
+	return "Comprobante de retención"
+}
+
+func (t *retentionTemplate) Render(b *pdfBuilder, doc *models.PDFDocument) error {
+	var document structs.RetentionDTEResponse
+	if err := json.Unmarshal(doc.JSONData, &document); err != nil {
+		return err
+	}
+
+	// 1. Encabezado, agente de retención y sujeto retenido
+	issuer := structs.DTEIssuer{
+		NIT:             document.Emisor.NIT,
+		NRC:             document.Emisor.NRC,
+		Nombre:          document.Emisor.Nombre,
+		DescActividad:   document.Emisor.DescActividad,
+		Direccion:       document.Emisor.Direccion,
+		Telefono:        document.Emisor.Telefono,
+		Correo:          document.Emisor.Correo,
+		NombreComercial: document.Emisor.NombreComercial,
+	}
+	b.header(t.Title(), doc, identificationRows(doc, document.Identificacion))
+	applyStatusWatermark(b, doc)
+	b.parties("Agente de retención", issuerRows(issuer), "Sujeto retenido", receiverRows(document.Receptor))
+
+	// 2. Documentos sujetos a retención
+	b.sectionTitle("Documentos sujetos a retención")
+	rows := make([][]string, len(document.CuerpoDocumento))
+	for i, item := range document.CuerpoDocumento {
+		rows[i] = []string{
+			dteTypeName(item.TipoDTE),
+			item.NumDoc,
+			item.FechaEmision,
+			item.Descripcion,
+			item.CodigoRetencionMH,
+			money(item.MontoSujetoGravado),
+			money(item.IvaRetenido),
+		}
+	}
+	b.table([]column{
+		{Title: "Tipo de documento", Width: 28, Align: "L"},
+		{Title: "Número de documento", Width: 42, Align: "L"},
+		{Title: "Fecha", Width: 18, Align: "C"},
+		{Title: "Descripción", Width: b.contentW - 156, Align: "L"},
+		{Title: "Código", Width: 18, Align: "C"},
+		{Title: "Monto sujeto", Width: 25, Align: "R"},
+		{Title: "IVA retenido", Width: 25, Align: "R"},
+	}, rows)
+
+	// 3. Resumen
+	if summary := document.Resumen; summary != nil {
+		b.totals([][2]string{
+			{"Total monto sujeto a retención", money(summary.TotalSujRetencion)},
+			{"Total IVA retenido", money(summary.TotalIvaRetenido)},
+		}, amountInLetters(summary.TotalIvaRetenidoLetras, summary.TotalIvaRetenido))
+	}
+
+	// 4. Extensión y apéndice
+	var extension *structs.DTEExtension
+	if document.Extension != nil {
+		extension = &structs.DTEExtension{
+			NombreEntrega:    document.Extension.NombreEntrega,
+			DocumentoEntrega: document.Extension.DocumentoEntrega,
+			NombreRecibe:     document.Extension.NombreRecibe,
+			DocumentoRecibe:  document.Extension.DocumentoRecibe,
+			Observacion:      document.Extension.Observacion,
+		}
+	}
+	extensionAndAppendix(b, extension, document.Apendice)
+	return nil
+}
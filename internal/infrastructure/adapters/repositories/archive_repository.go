@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+type ArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewArchiveRepository crea una instancia de ArchiveRepository. Recibe una instancia de gorm.DB.
+func NewArchiveRepository(db *gorm.DB) archive.ArchiveRepositoryPort {
+	return &ArchiveRepository{db: db}
+}
+
+// Create registra un trabajo de exportación
+func (r *ArchiveRepository) Create(ctx context.Context, job *models.ArchiveJob) error {
+	record := db_models.ArchiveJob{
+		ID:         job.ID,
+		BranchID:   job.BranchID,
+		UserID:     job.ClientID,
+		Scope:      job.Scope,
+		StartDate:  job.StartDate,
+		EndDate:    job.EndDate,
+		IncludePDF: job.IncludePDF,
+		Status:     job.Status,
+		CreatedAt:  job.CreatedAt,
+	}
+
+	return r.db.WithContext(ctx).Create(&record).Error
+}
+
+// Update actualiza el estado y el resultado de un trabajo de exportación
+func (r *ArchiveRepository) Update(ctx context.Context, job *models.ArchiveJob) error {
+	return r.db.WithContext(ctx).
+		Model(&db_models.ArchiveJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":         job.Status,
+			"document_count": job.DocumentCount,
+			"file_size":      job.FileSize,
+			"file_hash":      utils.ToStringPointer(job.FileHash),
+			"file_path":      utils.ToStringPointer(job.FilePath),
+			"error_message":  job.ErrorMessage,
+			"completed_at":   job.CompletedAt,
+		}).Error
+}
+
+// ClaimJob reserva un trabajo marcándolo en proceso hasta leaseUntil, la actualización solo afecta a los trabajos
+// pendientes o en proceso con la reserva vencida por lo que solo un proceso obtiene la reserva
+func (r *ArchiveRepository) ClaimJob(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&db_models.ArchiveJob{}).
+		Where("id = ? AND (status = ? OR (status = ? AND (lease_until IS NULL OR lease_until <= ?)))",
+			id, models.ArchivePending, models.ArchiveProcessing, now).
+		Updates(map[string]interface{}{
+			"status":      models.ArchiveProcessing,
+			"lease_until": leaseUntil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// GetByID obtiene un trabajo de exportación de la sucursal
+func (r *ArchiveRepository) GetByID(ctx context.Context, branchID uint, id string) (*models.ArchiveJob, error) {
+	var record db_models.ArchiveJob
+
+	result := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id = ? AND branch_id = ?", id, branchID).
+		First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, shared_error.NewFormattedGeneralServiceError("ArchiveRepository", "GetByID", "ArchiveNotFound", id)
+		}
+		return nil, result.Error
+	}
+
+	return toArchiveJob(&record), nil
+}
+
+// GetByStatus obtiene los trabajos de exportación de todas las sucursales con alguno de los estados indicados
+func (r *ArchiveRepository) GetByStatus(ctx context.Context, statuses ...string) ([]models.ArchiveJob, error) {
+	var records []db_models.ArchiveJob
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("status IN ?", statuses).
+		Order("created_at ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toArchiveJobs(records), nil
+}
+
+// GetCompletedBefore obtiene hasta limit trabajos completados antes de la fecha indicada
+func (r *ArchiveRepository) GetCompletedBefore(ctx context.Context, before time.Time, limit int) ([]models.ArchiveJob, error) {
+	var records []db_models.ArchiveJob
+
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND completed_at < ?", models.ArchiveCompleted, before).
+		Order("completed_at ASC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toArchiveJobs(records), nil
+}
+
+// toArchiveJob convierte un registro de la base de datos en un trabajo de exportación
+func toArchiveJob(record *db_models.ArchiveJob) *models.ArchiveJob {
+	job := &models.ArchiveJob{
+		ID:            record.ID,
+		BranchID:      record.BranchID,
+		ClientID:      record.UserID,
+		Scope:         record.Scope,
+		StartDate:     record.StartDate,
+		EndDate:       record.EndDate,
+		IncludePDF:    record.IncludePDF,
+		Status:        record.Status,
+		DocumentCount: record.DocumentCount,
+		FileSize:      record.FileSize,
+		FileHash:      utils.PointerToString(record.FileHash),
+		FilePath:      utils.PointerToString(record.FilePath),
+		ErrorMessage:  record.ErrorMessage,
+		CreatedAt:     record.CreatedAt,
+		CompletedAt:   record.CompletedAt,
+	}
+	if record.User != nil {
+		job.NIT = record.User.NIT
+	}
+
+	return job
+}
+
+func toArchiveJobs(records []db_models.ArchiveJob) []models.ArchiveJob {
+	jobs := make([]models.ArchiveJob, 0, len(records))
+	for i := range records {
+		jobs = append(jobs, *toArchiveJob(&records[i]))
+	}
+
+	return jobs
+}
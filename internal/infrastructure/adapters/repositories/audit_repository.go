@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+)
+
+// auditEventResult es el resultado de las consultas de eventos, la fecha se obtiene como time.Time para no depender
+// del formato de cadena del motor de base de datos
+type auditEventResult struct {
+	ID            uint      `gorm:"column:id"`
+	UserID        *uint     `gorm:"column:user_id"`
+	BranchID      *uint     `gorm:"column:branch_id"`
+	EventType     string    `gorm:"column:event_type"`
+	ActorType     string    `gorm:"column:actor_type"`
+	ActorID       uint      `gorm:"column:actor_id"`
+	IPAddress     string    `gorm:"column:ip_address"`
+	RequestID     string    `gorm:"column:request_id"`
+	Payload       string    `gorm:"column:payload"`
+	PayloadDigest string    `gorm:"column:payload_digest"`
+	OccurredAt    time.Time `gorm:"column:occurred_at"`
+}
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository crea una instancia de AuditRepository. Recibe una instancia de gorm.DB.
+func NewAuditRepository(db *gorm.DB) audit.AuditRepositoryPort {
+	return &AuditRepository{db: db}
+}
+
+// Create registra un evento de auditoría en la tabla de eventos de dominio
+func (r *AuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	record := &db_models.DomainEvent{
+		UserID:        optionalID(event.UserID),
+		BranchID:      optionalID(event.BranchID),
+		EventType:     event.EventType,
+		ActorType:     event.ActorType,
+		ActorID:       event.ActorID,
+		IPAddress:     event.IPAddress,
+		RequestID:     event.RequestID,
+		Payload:       string(event.Payload),
+		PayloadDigest: event.PayloadDigest,
+		OccurredAt:    event.OccurredAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+		return err
+	}
+
+	event.ID = record.ID
+	return nil
+}
+
+// GetPaged obtiene una página de eventos ordenados del más reciente al más antiguo junto con el total de eventos
+func (r *AuditRepository) GetPaged(ctx context.Context, filters *models.AuditFilters) ([]models.AuditEvent, int64, error) {
+	// 1. Contar los eventos que cumplen con los filtros
+	var total int64
+	if err := loadAuditFilters(r.db.WithContext(ctx).Table("domain_events"), filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 2. Obtener la página solicitada
+	var results []auditEventResult
+	err := loadAuditFilters(r.db.WithContext(ctx).Table("domain_events"), filters).
+		Order("id DESC").
+		Offset((filters.Page - 1) * filters.PageSize).
+		Limit(filters.PageSize).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]models.AuditEvent, len(results))
+	for i := range results {
+		events[i] = results[i].toAuditEvent()
+	}
+
+	return events, total, nil
+}
+
+// Stream recorre los eventos que cumplen con los filtros utilizando paginación por cursor sobre el ID, cada lote es una
+// consulta independiente por lo que no se mantiene abierta una conexión durante toda la exportación
+func (r *AuditRepository) Stream(ctx context.Context, filters *models.AuditFilters, batchSize int, fn func(*models.AuditEvent) error) error {
+	var cursor uint
+	for {
+		// 1. Obtener el lote siguiente al último evento entregado
+		var results []auditEventResult
+		err := loadAuditFilters(r.db.WithContext(ctx).Table("domain_events"), filters).
+			Where("id > ?", cursor).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&results).Error
+		if err != nil {
+			return err
+		}
+
+		// 2. Entregar cada evento del lote
+		for i := range results {
+			event := results[i].toAuditEvent()
+			if err = fn(&event); err != nil {
+				return err
+			}
+		}
+
+		// 3. Finalizar cuando el lote no esté completo
+		if len(results) < batchSize {
+			return nil
+		}
+		cursor = results[len(results)-1].ID
+	}
+}
+
+// loadAuditFilters aplica los filtros de búsqueda de eventos a la consulta
+func loadAuditFilters(query *gorm.DB, filters *models.AuditFilters) *gorm.DB {
+	if filters.UserID != 0 {
+		query = query.Where("user_id = ?", filters.UserID)
+	}
+	if filters.BranchID != 0 {
+		query = query.Where("branch_id = ?", filters.BranchID)
+	}
+	if filters.EventType != "" {
+		query = query.Where("event_type = ?", filters.EventType)
+	}
+	if filters.ActorType != "" {
+		query = query.Where("actor_type = ?", filters.ActorType)
+	}
+	if filters.RequestID != "" {
+		query = query.Where("request_id = ?", filters.RequestID)
+	}
+	if filters.StartDate != nil {
+		query = query.Where("occurred_at >= ?", *filters.StartDate)
+	}
+	if filters.EndDate != nil {
+		query = query.Where("occurred_at <= ?", *filters.EndDate)
+	}
+
+	return query
+}
+
+func (e *auditEventResult) toAuditEvent() models.AuditEvent {
+	var userID, branchID uint
+	if e.UserID != nil {
+		userID = *e.UserID
+	}
+	if e.BranchID != nil {
+		branchID = *e.BranchID
+	}
+
+	return models.AuditEvent{
+		ID:            e.ID,
+		EventType:     e.EventType,
+		ActorType:     e.ActorType,
+		ActorID:       e.ActorID,
+		UserID:        userID,
+		BranchID:      branchID,
+		IPAddress:     e.IPAddress,
+		RequestID:     e.RequestID,
+		Payload:       json.RawMessage(e.Payload),
+		PayloadDigest: e.PayloadDigest,
+		OccurredAt:    e.OccurredAt,
+	}
+}
+
+// optionalID convierte un ID en un puntero, el valor cero se almacena como NULL
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
 	"gorm.io/gorm"
+	"strings"
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
@@ -23,8 +24,13 @@ func NewAuthRepository(db *gorm.DB) auth.AuthRepositoryPort {
 
 // GetAuthTypeByApiKey obtiene el tipo de autenticación de un usuario por su API key
 func (r *AuthRepository) GetAuthTypeByApiKey(ctx context.Context, apiKey string) (string, error) {
-	// 1. Obtener usuario por API key
+	// 1. Obtener usuario por API key, si no es la llave principal de una sucursal se busca entre sus llaves adicionales
 	user, err := r.GetByBranchApiKey(ctx, apiKey)
+	if errors.Is(err, errPackage.ErrBranchOfficeNotFound) {
+		if key, keyErr := r.GetBranchAPIKeyByKey(ctx, apiKey); keyErr == nil && key.IsActive {
+			user, err = r.GetByBranchID(ctx, key.BranchID)
+		}
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return "", errPackage.ErrUserNotFound
@@ -64,6 +70,7 @@ func (r *AuthRepository) GetByNIT(ctx context.Context, nit string) (*user.User,
 		YearInDTE:      dbUser.YearInDTE,
 		Phone:          dbUser.Phone,
 		TokenLifetime:  dbUser.TokenLifetime,
+		Language:       dbUser.Language,
 		CreatedAt:      dbUser.CreatedAt,
 		UpdatedAt:      dbUser.UpdatedAt,
 	}
@@ -109,6 +116,7 @@ func (r *AuthRepository) GetByBranchApiKey(ctx context.Context, apiKey string) (
 		Business:             dbUser.Business,
 		Email:                dbUser.Email,
 		TokenLifetime:        dbUser.TokenLifetime,
+		Language:             dbUser.Language,
 		YearInDTE:            dbUser.YearInDTE,
 		CreatedAt:            dbUser.CreatedAt,
 		UpdatedAt:            dbUser.UpdatedAt,
@@ -135,11 +143,14 @@ func (r *AuthRepository) GetBranchByBranchApiKey(ctx context.Context, apiKey str
 		Email:               branch.Email,
 		APIKey:              branch.APIKey,
 		APISecret:           branch.APISecret,
+		APISigningSecret:    branch.APISigningSecret,
 		Phone:               branch.Phone,
 		EstablishmentType:   branch.EstablishmentType,
 		POSCode:             branch.POSCode,
 		POSCodeMH:           branch.POSCodeMH,
 		IsActive:            branch.IsActive,
+		RateLimitPerMinute:  branch.RateLimitPerMinute,
+		ClientCertSubject:   branch.ClientCertSubject,
 	}
 
 	if localUser.Address != nil {
@@ -191,6 +202,7 @@ func (r *AuthRepository) GetByBranchID(ctx context.Context, branchID uint) (*use
 		Business:             dbUser.Business,
 		Email:                dbUser.Email,
 		TokenLifetime:        dbUser.TokenLifetime,
+		Language:             dbUser.Language,
 		YearInDTE:            dbUser.YearInDTE,
 		CreatedAt:            dbUser.CreatedAt,
 		UpdatedAt:            dbUser.UpdatedAt,
@@ -204,7 +216,8 @@ func (r *AuthRepository) Create(ctx context.Context, user *user.User) error {
 		dbUser := db_models.User{
 			NIT:                  user.NIT,
 			NRC:                  user.NRC,
-			Status:               true,
+			Status:               user.Status,
+			RegistrationStatus:   user.RegistrationStatus,
 			AuthType:             user.AuthType,
 			PasswordPri:          user.PasswordPri,
 			CommercialName:       user.CommercialName,
@@ -237,11 +250,14 @@ func (r *AuthRepository) Create(ctx context.Context, user *user.User) error {
 				Email:               user.BranchOffices[i].Email,
 				APIKey:              user.BranchOffices[i].APIKey,
 				APISecret:           user.BranchOffices[i].APISecret,
+				APISigningSecret:    user.BranchOffices[i].APISigningSecret,
 				Phone:               user.BranchOffices[i].Phone,
 				EstablishmentType:   user.BranchOffices[i].EstablishmentType,
 				POSCode:             user.BranchOffices[i].POSCode,
 				POSCodeMH:           user.BranchOffices[i].POSCodeMH,
 				IsActive:            user.BranchOffices[i].IsActive,
+				RateLimitPerMinute:  user.BranchOffices[i].RateLimitPerMinute,
+				ClientCertSubject:   user.BranchOffices[i].ClientCertSubject,
 			}
 
 			if err := tx.Create(&dbBranch).Error; err != nil {
@@ -286,6 +302,7 @@ func (r *AuthRepository) Update(ctx context.Context, user *user.User) error {
 		YearInDTE:      user.YearInDTE,
 		Phone:          user.Phone,
 		TokenLifetime:  user.TokenLifetime,
+		Language:       user.Language,
 	}
 
 	// 2. Actualizar usuario
@@ -303,22 +320,25 @@ func (r *AuthRepository) UpdateBranchOffices(ctx context.Context, userID uint, b
 				return errPackage.ErrBranchDoesNotBelong
 			}
 
-			// 2. Actualizar sucursal
+			// 2. Actualizar sucursal, los campos se seleccionan para que los valores nulos también se guarden. Las llaves y
+			// el estado solo cambian con UpdateBranchCredentials y SetBranchOfficeStatus
 			dbBranch := db_models.BranchOffice{
 				ID:                  branch.ID,
 				EstablishmentCode:   branch.EstablishmentCode,
 				EstablishmentCodeMH: branch.EstablishmentCodeMH,
 				Email:               branch.Email,
-				APIKey:              branch.APIKey,
-				APISecret:           branch.APISecret,
 				Phone:               branch.Phone,
 				EstablishmentType:   branch.EstablishmentType,
 				POSCode:             branch.POSCode,
 				POSCodeMH:           branch.POSCodeMH,
-				IsActive:            branch.IsActive,
+				RateLimitPerMinute:  branch.RateLimitPerMinute,
+				ClientCertSubject:   branch.ClientCertSubject,
 			}
 
-			if err := tx.Model(&dbBranch).Updates(dbBranch).Error; err != nil {
+			if err := tx.Model(&dbBranch).
+				Select("establishment_code", "establishment_code_mh", "email", "phone", "establishment_type",
+					"pos_code", "pos_code_mh", "rate_limit_per_minute", "client_cert_subject").
+				Updates(dbBranch).Error; err != nil {
 				return err
 			}
 
@@ -377,6 +397,7 @@ func (r *AuthRepository) DeleteBranchOffice(ctx context.Context, userID uint, br
 	})
 }
 
+// GetBranchByBranchID obtiene una sucursal por su ID junto con su usuario
 func (r *AuthRepository) GetBranchByBranchID(ctx context.Context, branchID uint) (*user.BranchOffice, error) {
 	var branch db_models.BranchOffice
 
@@ -404,6 +425,8 @@ func (r *AuthRepository) GetBranchByBranchID(ctx context.Context, branchID uint)
 		POSCode:             branch.POSCode,
 		POSCodeMH:           branch.POSCodeMH,
 		IsActive:            branch.IsActive,
+		RateLimitPerMinute:  branch.RateLimitPerMinute,
+		ClientCertSubject:   branch.ClientCertSubject,
 		User: &user.User{
 			ID:                   branch.User.ID,
 			Status:               branch.User.Status,
@@ -414,6 +437,8 @@ func (r *AuthRepository) GetBranchByBranchID(ctx context.Context, branchID uint)
 			AuthType:             branch.User.AuthType,
 			EconomicActivity:     branch.User.EconomicActivity,
 			EconomicActivityDesc: branch.User.EconomicActivityDesc,
+			TokenLifetime:        branch.User.TokenLifetime,
+			Language:             branch.User.Language,
 		},
 	}
 
@@ -428,6 +453,260 @@ func (r *AuthRepository) GetBranchByBranchID(ctx context.Context, branchID uint)
 	return localBranch, nil
 }
 
+// GetBranchByClientCertSubject obtiene la sucursal activa asignada al subject de un certificado de cliente junto con su
+// usuario
+func (r *AuthRepository) GetBranchByClientCertSubject(ctx context.Context, subject string) (*user.BranchOffice, error) {
+	var branch db_models.BranchOffice
+
+	result := r.db.WithContext(ctx).
+		Select("id").
+		Where("client_cert_subject = ? AND is_active = ?", subject, true).
+		First(&branch)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errPackage.ErrBranchOfficeNotFound
+		}
+		return nil, result.Error
+	}
+
+	return r.GetBranchByBranchID(ctx, branch.ID)
+}
+
+// GetBranchOffices obtiene todas las sucursales de un usuario, activas e inactivas
+func (r *AuthRepository) GetBranchOffices(ctx context.Context, userID uint) ([]user.BranchOffice, error) {
+	var branches []db_models.BranchOffice
+
+	result := r.db.WithContext(ctx).
+		Preload("Address").
+		Where("user_id = ?", userID).
+		Order("id asc").
+		Find(&branches)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	localBranches := make([]user.BranchOffice, len(branches))
+	for i, branch := range branches {
+		localBranches[i] = user.BranchOffice{
+			ID:                  branch.ID,
+			UserID:              branch.UserID,
+			EstablishmentCode:   branch.EstablishmentCode,
+			EstablishmentCodeMH: branch.EstablishmentCodeMH,
+			Email:               branch.Email,
+			APIKey:              branch.APIKey,
+			APISecret:           branch.APISecret,
+			Phone:               branch.Phone,
+			EstablishmentType:   branch.EstablishmentType,
+			POSCode:             branch.POSCode,
+			POSCodeMH:           branch.POSCodeMH,
+			IsActive:            branch.IsActive,
+			RateLimitPerMinute:  branch.RateLimitPerMinute,
+			ClientCertSubject:   branch.ClientCertSubject,
+		}
+
+		if branch.Address != nil {
+			localBranches[i].Address = &user.Address{
+				Municipality: branch.Address.Municipality,
+				Department:   branch.Address.Department,
+				Complement:   branch.Address.Complement,
+			}
+		}
+	}
+
+	return localBranches, nil
+}
+
+// CreateBranchOffice crea una sucursal para un usuario existente
+func (r *AuthRepository) CreateBranchOffice(ctx context.Context, userID uint, branch *user.BranchOffice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Crear sucursal
+		dbBranch := db_models.BranchOffice{
+			UserID:              userID,
+			EstablishmentCode:   branch.EstablishmentCode,
+			EstablishmentCodeMH: branch.EstablishmentCodeMH,
+			Email:               branch.Email,
+			APIKey:              branch.APIKey,
+			APISecret:           branch.APISecret,
+			APISigningSecret:    branch.APISigningSecret,
+			Phone:               branch.Phone,
+			EstablishmentType:   branch.EstablishmentType,
+			POSCode:             branch.POSCode,
+			POSCodeMH:           branch.POSCodeMH,
+			IsActive:            branch.IsActive,
+			RateLimitPerMinute:  branch.RateLimitPerMinute,
+			ClientCertSubject:   branch.ClientCertSubject,
+		}
+
+		if err := tx.Create(&dbBranch).Error; err != nil {
+			return err
+		}
+
+		// 2. Si la sucursal tiene dirección, crearla también
+		if branch.Address != nil {
+			dbAddress := db_models.Address{
+				BranchID:     dbBranch.ID,
+				Municipality: branch.Address.Municipality,
+				Department:   branch.Address.Department,
+				Complement:   branch.Address.Complement,
+			}
+
+			if err := tx.Create(&dbAddress).Error; err != nil {
+				return err
+			}
+		}
+
+		// 3. Actualizar ID en el modelo de dominio
+		branch.ID = dbBranch.ID
+		branch.UserID = userID
+		return nil
+	})
+}
+
+// SetBranchOfficeStatus activa o desactiva una sucursal de un usuario
+func (r *AuthRepository) SetBranchOfficeStatus(ctx context.Context, userID uint, branchID uint, active bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Comprobar que la sucursal pertenece al usuario
+		var count int64
+		tx.Model(&db_models.BranchOffice{}).Where("id = ? AND user_id = ?", branchID, userID).Count(&count)
+		if count == 0 {
+			return errPackage.ErrBranchDoesNotBelong
+		}
+
+		// 2. Actualizar la columna directamente, Updates con un struct ignora el valor false
+		return tx.Model(&db_models.BranchOffice{}).Where("id = ?", branchID).Update("is_active", active).Error
+	})
+}
+
+// UpdateBranchCredentials reemplaza el API key, el hash del API secret y el API secret cifrado de una sucursal de un usuario
+func (r *AuthRepository) UpdateBranchCredentials(ctx context.Context, userID uint, branchID uint, apiKey, apiSecret, signingSecret string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Comprobar que la sucursal pertenece al usuario
+		var count int64
+		tx.Model(&db_models.BranchOffice{}).Where("id = ? AND user_id = ?", branchID, userID).Count(&count)
+		if count == 0 {
+			return errPackage.ErrBranchDoesNotBelong
+		}
+
+		// 2. Reemplazar las llaves de la sucursal
+		return tx.Model(&db_models.BranchOffice{}).Where("id = ?", branchID).Updates(map[string]interface{}{
+			"api_key":            apiKey,
+			"api_secret":         apiSecret,
+			"api_signing_secret": signingSecret,
+		}).Error
+	})
+}
+
+// GetBranchAPIKeyByKey obtiene una llave de acceso adicional por su API key, sin importar su estado
+func (r *AuthRepository) GetBranchAPIKeyByKey(ctx context.Context, apiKey string) (*user.BranchAPIKey, error) {
+	var key db_models.BranchAPIKey
+
+	result := r.db.WithContext(ctx).Where("api_key = ?", apiKey).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errPackage.ErrAPIKeyNotFound
+		}
+		return nil, result.Error
+	}
+
+	return toDomainBranchAPIKey(&key), nil
+}
+
+// GetBranchAPIKeyByClientCertSubject obtiene la llave de acceso adicional asignada al subject o SAN de un certificado
+// de cliente
+func (r *AuthRepository) GetBranchAPIKeyByClientCertSubject(ctx context.Context, subject string) (*user.BranchAPIKey, error) {
+	var key db_models.BranchAPIKey
+
+	result := r.db.WithContext(ctx).Where("client_cert_subject = ?", subject).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errPackage.ErrAPIKeyNotFound
+		}
+		return nil, result.Error
+	}
+
+	return toDomainBranchAPIKey(&key), nil
+}
+
+// GetBranchAPIKeys obtiene las llaves de acceso adicionales de una sucursal, activas e inactivas
+func (r *AuthRepository) GetBranchAPIKeys(ctx context.Context, branchID uint) ([]user.BranchAPIKey, error) {
+	var keys []db_models.BranchAPIKey
+
+	result := r.db.WithContext(ctx).
+		Where("branch_id = ?", branchID).
+		Order("id asc").
+		Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	localKeys := make([]user.BranchAPIKey, len(keys))
+	for i := range keys {
+		localKeys[i] = *toDomainBranchAPIKey(&keys[i])
+	}
+
+	return localKeys, nil
+}
+
+// CreateBranchAPIKey crea una llave de acceso adicional para una sucursal
+func (r *AuthRepository) CreateBranchAPIKey(ctx context.Context, key *user.BranchAPIKey) error {
+	dbKey := db_models.BranchAPIKey{
+		BranchID:          key.BranchID,
+		Name:              key.Name,
+		APIKey:            key.APIKey,
+		APISecret:         key.APISecret,
+		APISigningSecret:  key.APISigningSecret,
+		Scopes:            strings.Join(key.Scopes, ","),
+		ClientCertSubject: key.ClientCertSubject,
+		IsActive:          key.IsActive,
+	}
+
+	if err := r.db.WithContext(ctx).Create(&dbKey).Error; err != nil {
+		return err
+	}
+
+	key.ID = dbKey.ID
+	key.CreatedAt = dbKey.CreatedAt
+	return nil
+}
+
+// SetBranchAPIKeyStatus activa o desactiva una llave de acceso adicional de una sucursal
+func (r *AuthRepository) SetBranchAPIKeyStatus(ctx context.Context, branchID, keyID uint, active bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Comprobar que la llave pertenece a la sucursal
+		var count int64
+		tx.Model(&db_models.BranchAPIKey{}).Where("id = ? AND branch_id = ?", keyID, branchID).Count(&count)
+		if count == 0 {
+			return errPackage.ErrAPIKeyNotFound
+		}
+
+		// 2. Actualizar la columna directamente, Updates con un struct ignora el valor false
+		return tx.Model(&db_models.BranchAPIKey{}).Where("id = ?", keyID).Update("is_active", active).Error
+	})
+}
+
+// toDomainBranchAPIKey convierte una llave de acceso de base de datos al modelo de dominio
+func toDomainBranchAPIKey(key *db_models.BranchAPIKey) *user.BranchAPIKey {
+	scopes := make([]string, 0)
+	for _, scope := range strings.Split(key.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return &user.BranchAPIKey{
+		ID:                key.ID,
+		BranchID:          key.BranchID,
+		Name:              key.Name,
+		APIKey:            key.APIKey,
+		APISecret:         key.APISecret,
+		APISigningSecret:  key.APISigningSecret,
+		Scopes:            scopes,
+		ClientCertSubject: key.ClientCertSubject,
+		IsActive:          key.IsActive,
+		CreatedAt:         key.CreatedAt,
+	}
+}
+
 // GetAuthTypeByNIT obtiene el tipo de autenticación de un usuario por su NIT
 func (r *AuthRepository) GetAuthTypeByNIT(ctx context.Context, nit string) (string, error) {
 	user, err := r.GetByNIT(ctx, nit)
@@ -531,6 +810,8 @@ func (r *AuthRepository) GetMatrixBranch(ctx context.Context, userID uint) (*use
 		POSCode:             branch.POSCode,
 		POSCodeMH:           branch.POSCodeMH,
 		IsActive:            branch.IsActive,
+		RateLimitPerMinute:  branch.RateLimitPerMinute,
+		ClientCertSubject:   branch.ClientCertSubject,
 		Address: &user.Address{
 			Municipality: branch.Address.Municipality,
 			Department:   branch.Address.Department,
@@ -538,3 +819,148 @@ func (r *AuthRepository) GetMatrixBranch(ctx context.Context, userID uint) (*use
 		},
 	}, nil
 }
+
+// GetRegistrationConflicts obtiene los usuarios registrados o pendientes de aprobación con el NIT o NRC indicados
+func (r *AuthRepository) GetRegistrationConflicts(ctx context.Context, nit, nrc string) ([]user.User, error) {
+	var dbUsers []db_models.User
+
+	result := r.db.WithContext(ctx).Where("nit = ? OR nrc = ?", nit, nrc).Find(&dbUsers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	users := make([]user.User, len(dbUsers))
+	for i := range dbUsers {
+		users[i] = *toDomainUser(&dbUsers[i])
+	}
+
+	return users, nil
+}
+
+// GetRegistrations obtiene los usuarios con el estado de registro indicado junto con sus sucursales
+func (r *AuthRepository) GetRegistrations(ctx context.Context, status string) ([]user.User, error) {
+	var dbUsers []db_models.User
+
+	result := r.db.WithContext(ctx).Where("registration_status = ?", status).Order("created_at asc").Find(&dbUsers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	users := make([]user.User, len(dbUsers))
+	for i := range dbUsers {
+		users[i] = *toDomainUser(&dbUsers[i])
+
+		branches, err := r.GetBranchOffices(ctx, dbUsers[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		users[i].BranchOffices = branches
+	}
+
+	return users, nil
+}
+
+// GetRegistrationByID obtiene un usuario por su ID junto con sus sucursales sin importar su estado
+func (r *AuthRepository) GetRegistrationByID(ctx context.Context, userID uint) (*user.User, error) {
+	var dbUser db_models.User
+
+	result := r.db.WithContext(ctx).Where("id = ?", userID).First(&dbUser)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errPackage.ErrUserNotFound
+		}
+		return nil, result.Error
+	}
+
+	localUser := toDomainUser(&dbUser)
+	branches, err := r.GetBranchOffices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	localUser.BranchOffices = branches
+
+	return localUser, nil
+}
+
+// ApproveRegistration activa un usuario pendiente de aprobación y asigna las llaves de sus sucursales
+func (r *AuthRepository) ApproveRegistration(ctx context.Context, userID uint, branches []user.BranchOffice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Activar el usuario, solo si su registro sigue pendiente
+		result := tx.Model(&db_models.User{}).
+			Where("id = ? AND registration_status = ?", userID, user.RegistrationPending).
+			Updates(map[string]interface{}{
+				"status":              true,
+				"registration_status": user.RegistrationApproved,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPackage.ErrUserNotFound
+		}
+
+		// 2. Asignar las llaves de cada sucursal
+		for _, branch := range branches {
+			err := tx.Model(&db_models.BranchOffice{}).
+				Where("id = ? AND user_id = ?", branch.ID, userID).
+				Updates(map[string]interface{}{
+					"api_key":            branch.APIKey,
+					"api_secret":         branch.APISecret,
+					"api_signing_secret": branch.APISigningSecret,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteRegistration elimina un usuario pendiente de aprobación junto con sus sucursales y direcciones
+func (r *AuthRepository) DeleteRegistration(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Comprobar que el registro sigue pendiente
+		var count int64
+		tx.Model(&db_models.User{}).Where("id = ? AND registration_status = ?", userID, user.RegistrationPending).Count(&count)
+		if count == 0 {
+			return errPackage.ErrUserNotFound
+		}
+
+		// 2. Eliminar direcciones y sucursales primero (debido a la restricción de clave foránea)
+		branchIDs := tx.Model(&db_models.BranchOffice{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("branch_id IN (?)", branchIDs).Delete(&db_models.Address{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&db_models.BranchOffice{}).Error; err != nil {
+			return err
+		}
+
+		// 3. Eliminar usuario
+		return tx.Delete(&db_models.User{}, userID).Error
+	})
+}
+
+// toDomainUser convierte un usuario de base de datos en el modelo de dominio
+func toDomainUser(dbUser *db_models.User) *user.User {
+	return &user.User{
+		ID:                   dbUser.ID,
+		NIT:                  dbUser.NIT,
+		NRC:                  dbUser.NRC,
+		Status:               dbUser.Status,
+		AuthType:             dbUser.AuthType,
+		PasswordPri:          dbUser.PasswordPri,
+		CommercialName:       dbUser.CommercialName,
+		EconomicActivity:     dbUser.EconomicActivity,
+		EconomicActivityDesc: dbUser.EconomicActivityDesc,
+		Phone:                dbUser.Phone,
+		Business:             dbUser.Business,
+		Email:                dbUser.Email,
+		TokenLifetime:        dbUser.TokenLifetime,
+		Language:             dbUser.Language,
+		YearInDTE:            dbUser.YearInDTE,
+		RegistrationStatus:   dbUser.RegistrationStatus,
+		CreatedAt:            dbUser.CreatedAt,
+		UpdatedAt:            dbUser.UpdatedAt,
+	}
+}
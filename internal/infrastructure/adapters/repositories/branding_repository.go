@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+type BrandingRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandingRepository crea una instancia de BrandingRepository. Recibe una instancia de gorm.DB.
+func NewBrandingRepository(db *gorm.DB) pdf.BrandingRepositoryPort {
+	return &BrandingRepository{db: db}
+}
+
+// GetByBranchID obtiene la configuración de marca de una sucursal, retorna nil si la sucursal no posee configuración
+func (r *BrandingRepository) GetByBranchID(ctx context.Context, branchID uint) (*models.Branding, error) {
+	var branding db_models.BranchBranding
+
+	result := r.db.WithContext(ctx).Where("branch_id = ?", branchID).First(&branding)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &models.Branding{
+		BranchID:     branding.BranchID,
+		Logo:         branding.Logo,
+		LogoFormat:   utils.PointerToString(branding.LogoFormat),
+		PrimaryColor: branding.PrimaryColor,
+		FooterText:   utils.PointerToString(branding.FooterText),
+		HasLogo:      len(branding.Logo) > 0,
+	}, nil
+}
+
+// Upsert crea o actualiza la configuración de marca de una sucursal
+func (r *BrandingRepository) Upsert(ctx context.Context, branding *models.Branding) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Buscar si ya existe una configuración para la sucursal
+		var existing db_models.BranchBranding
+		result := tx.Where("branch_id = ?", branding.BranchID).First(&existing)
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return result.Error
+		}
+
+		dbBranding := db_models.BranchBranding{
+			ID:           existing.ID,
+			BranchID:     branding.BranchID,
+			Logo:         branding.Logo,
+			LogoFormat:   utils.ToStringPointer(branding.LogoFormat),
+			PrimaryColor: branding.PrimaryColor,
+			FooterText:   utils.ToStringPointer(branding.FooterText),
+			CreatedAt:    existing.CreatedAt,
+			UpdatedAt:    utils.TimeNow(),
+		}
+
+		// 2. Crear la configuración si no existe
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			dbBranding.CreatedAt = utils.TimeNow()
+			return tx.Create(&dbBranding).Error
+		}
+
+		// 3. Actualizar la configuración existente, Save permite limpiar el logo y el pie de página
+		return tx.Save(&dbBranding).Error
+	})
+}
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
+)
+
+// dteGroupResult es el resultado del agregado de los DTE por sucursal, tipo, estado y transmisión
+type dteGroupResult struct {
+	BranchID     uint    `gorm:"column:branch_id"`
+	DTEType      string  `gorm:"column:dte_type"`
+	Status       string  `gorm:"column:status"`
+	Transmission string  `gorm:"column:transmission"`
+	Count        int64   `gorm:"column:count"`
+	Amount       float64 `gorm:"column:amount"`
+}
+
+// rejectionCodeResult es el resultado del agregado de los códigos de rechazo de Hacienda
+type rejectionCodeResult struct {
+	Code        string `gorm:"column:code"`
+	Description string `gorm:"column:description"`
+	Count       int64  `gorm:"column:count"`
+}
+
+// contingencyAgeResult es el resultado del agregado de la cola de contingencia, la fecha del documento más antiguo se
+// obtiene como time.Time para no depender del formato de cadena del motor de base de datos
+type contingencyAgeResult struct {
+	BranchID          uint          `gorm:"column:branch_id"`
+	Pending           int64         `gorm:"column:pending"`
+	LessThanHour      int64         `gorm:"column:less_than_hour"`
+	LessThanDay       int64         `gorm:"column:less_than_day"`
+	LessThanThreeDays int64         `gorm:"column:less_than_three_days"`
+	OldestPendingAt   aggregateTime `gorm:"column:oldest_pending_at"`
+}
+
+// aggregateTimeLayouts formatos en los que SQLite almacena las fechas, el del driver y el de CURRENT_TIMESTAMP
+var aggregateTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05",
+}
+
+// aggregateTime es una fecha obtenida con MIN o MAX. SQLite no conserva el tipo de la columna en los agregados y
+// retorna la fecha como cadena, MySQL y Postgres la retornan como time.Time
+type aggregateTime struct {
+	Time *time.Time
+}
+
+// Scan implementa sql.Scanner
+func (a *aggregateTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		a.Time = nil
+		return nil
+	case time.Time:
+		a.Time = &v
+		return nil
+	case []byte:
+		return a.parse(string(v))
+	case string:
+		return a.parse(v)
+	default:
+		return fmt.Errorf("unsupported aggregate time value %T", value)
+	}
+}
+
+// Value implementa driver.Valuer, requerido por gorm para los campos con tipo propio
+func (a aggregateTime) Value() (driver.Value, error) {
+	if a.Time == nil {
+		return nil, nil
+	}
+	return *a.Time, nil
+}
+
+// parse interpreta la fecha en los formatos de SQLite
+func (a *aggregateTime) parse(value string) error {
+	for _, layout := range aggregateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			a.Time = &t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid aggregate time value %q", value)
+}
+
+type BusinessMetricsRepository struct {
+	db *gorm.DB
+}
+
+// NewBusinessMetricsRepository crea una instancia de BusinessMetricsRepository. Recibe una instancia de gorm.DB.
+func NewBusinessMetricsRepository(db *gorm.DB) metrics.BusinessMetricsRepositoryPort {
+	return &BusinessMetricsRepository{db: db}
+}
+
+// GetDTEGroups agrupa los DTE del período por sucursal, tipo, estado y transmisión. El monto se toma de la columna
+// generada total_amount de dte_details, por lo que no se lee el JSON de los documentos
+func (r *BusinessMetricsRepository) GetDTEGroups(ctx context.Context, filters *models.BusinessMetricsFilters) ([]models.DTEMetricGroup, error) {
+	var results []dteGroupResult
+
+	err := loadMetricsScope(r.db.WithContext(ctx).Table("dte_documents"), filters).
+		Select("dte_documents.branch_id, dte_details.dte_type, dte_details.status, dte_details.transmission, "+
+			"COUNT(*) AS count, COALESCE(SUM(dte_details.total_amount), 0) AS amount").
+		Joins("JOIN dte_details ON dte_details.id = dte_documents.document_id").
+		Where("dte_documents.created_at BETWEEN ? AND ?", filters.StartDate, filters.EndDate).
+		Group("dte_documents.branch_id, dte_details.dte_type, dte_details.status, dte_details.transmission").
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating DTE metrics: %w", err)
+	}
+
+	groups := make([]models.DTEMetricGroup, len(results))
+	for i, result := range results {
+		groups[i] = models.DTEMetricGroup{
+			BranchID:     result.BranchID,
+			DTEType:      result.DTEType,
+			Status:       result.Status,
+			Transmission: result.Transmission,
+			Count:        result.Count,
+			Amount:       result.Amount,
+		}
+	}
+
+	return groups, nil
+}
+
+// GetPendingContingency agrupa por sucursal los documentos pendientes en la cola de contingencia, las cantidades por
+// antigüedad se calculan en la misma consulta comparando la fecha de registro con los límites de cada rango
+func (r *BusinessMetricsRepository) GetPendingContingency(ctx context.Context, filters *models.BusinessMetricsFilters, now time.Time) ([]models.ContingencyAgeGroup, error) {
+	var results []contingencyAgeResult
+
+	err := loadMetricsScope(r.db.WithContext(ctx).Table("contingency_documents"), filters).
+		Select("contingency_documents.branch_id, COUNT(*) AS pending, "+
+			"SUM(CASE WHEN contingency_documents.created_at >= ? THEN 1 ELSE 0 END) AS less_than_hour, "+
+			"SUM(CASE WHEN contingency_documents.created_at >= ? THEN 1 ELSE 0 END) AS less_than_day, "+
+			"SUM(CASE WHEN contingency_documents.created_at >= ? THEN 1 ELSE 0 END) AS less_than_three_days, "+
+			"MIN(contingency_documents.created_at) AS oldest_pending_at",
+			now.Add(-time.Hour), now.Add(-24*time.Hour), now.Add(-72*time.Hour)).
+		Joins("JOIN dte_details ON dte_details.id = contingency_documents.document_id").
+		Where("dte_details.status = ?", constants.DocumentPending).
+		Group("contingency_documents.branch_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating pending contingency documents: %w", err)
+	}
+
+	groups := make([]models.ContingencyAgeGroup, len(results))
+	for i, result := range results {
+		groups[i] = models.ContingencyAgeGroup{
+			BranchID:          result.BranchID,
+			Pending:           result.Pending,
+			LessThanHour:      result.LessThanHour,
+			LessThanDay:       result.LessThanDay,
+			LessThanThreeDays: result.LessThanThreeDays,
+			OldestPendingAt:   result.OldestPendingAt.Time,
+		}
+	}
+
+	return groups, nil
+}
+
+// GetTopRejectionCodes obtiene los códigos de mensaje más frecuentes de las respuestas de Hacienda almacenadas en
+// dte_artifacts para los DTE rechazados del período
+func (r *BusinessMetricsRepository) GetTopRejectionCodes(ctx context.Context, filters *models.BusinessMetricsFilters, limit int) ([]models.RejectionCode, error) {
+	var results []rejectionCodeResult
+
+	dialect := r.db.Dialector.Name()
+	code := mhResponseField(dialect, "codigoMsg")
+
+	err := loadMetricsScope(r.db.WithContext(ctx).Table("dte_documents"), filters).
+		Select(fmt.Sprintf("%s AS code, MAX(%s) AS description, COUNT(*) AS count", code, mhResponseField(dialect, "descripcionMsg"))).
+		Joins("JOIN dte_details ON dte_details.id = dte_documents.document_id").
+		Joins("JOIN dte_artifacts ON dte_artifacts.document_id = dte_documents.document_id").
+		Where("dte_details.status = ?", constants.DocumentRejected).
+		Where("dte_documents.created_at BETWEEN ? AND ?", filters.StartDate, filters.EndDate).
+		Where(code + " IS NOT NULL").
+		Group(code).
+		Order("count DESC").
+		Limit(limit).
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating rejection codes: %w", err)
+	}
+
+	codes := make([]models.RejectionCode, len(results))
+	for i, result := range results {
+		codes[i] = models.RejectionCode{
+			Code:        result.Code,
+			Description: result.Description,
+			Count:       result.Count,
+		}
+	}
+
+	return codes, nil
+}
+
+// loadMetricsScope limita la consulta a la sucursal indicada o a todas las sucursales del contribuyente, la tabla de
+// la consulta debe tener la columna branch_id
+func loadMetricsScope(query *gorm.DB, filters *models.BusinessMetricsFilters) *gorm.DB {
+	table := query.Statement.Table
+	if filters.BranchID != 0 {
+		return query.Where(table+".branch_id = ?", filters.BranchID)
+	}
+
+	return query.Where(table+".branch_id IN (?)",
+		query.Session(&gorm.Session{NewDB: true}).Table("branch_offices").Select("id").Where("user_id = ?", filters.ClientID))
+}
+
+// mhResponseField construye la expresión para leer un campo de la respuesta de Hacienda almacenada como texto
+func mhResponseField(dialect, field string) string {
+	switch dialect {
+	case "postgres":
+		return fmt.Sprintf("CAST(dte_artifacts.mh_response AS json)->>'%s'", field)
+	case "sqlite":
+		return fmt.Sprintf("json_extract(dte_artifacts.mh_response, '$.%s')", field)
+	default:
+		return fmt.Sprintf("JSON_VALUE(dte_artifacts.mh_response, '$.%s')", field)
+	}
+}
@@ -122,7 +122,7 @@ func (r *ContingencyRepository) UpdateBatch(ctx context.Context, ids []string, o
 				// 8. Actualizar el apéndice del DTE
 				var dteDoc db_models.DTEDetails
 				if err := tx.Where("id = ?", contingencyDoc.DocumentID).First(&dteDoc).Error; err != nil {
-					logs.Error("Failed to get user", map[string]interface{}{
+					logs.ErrorContext(ctx, "Failed to get user", map[string]interface{}{
 						"error": err.Error(),
 					})
 					tx.Rollback()
@@ -132,7 +132,7 @@ func (r *ContingencyRepository) UpdateBatch(ctx context.Context, ids []string, o
 				// Actualizamos el apéndice
 				updatedJSON, err := utils.SetReceptionStampIntoAppendix(dteDoc.JSONData, &stamp)
 				if err != nil {
-					logs.Error("Failed to set reception stamp into appendix", map[string]interface{}{
+					logs.ErrorContext(ctx, "Failed to set reception stamp into appendix", map[string]interface{}{
 						"error": err.Error(),
 					})
 					tx.Rollback()
@@ -143,7 +143,7 @@ func (r *ContingencyRepository) UpdateBatch(ctx context.Context, ids []string, o
 				if err := tx.Model(&db_models.DTEDetails{}).
 					Where("id = ?", contingencyDoc.DocumentID).
 					Update("json_data", updatedJSON).Error; err != nil {
-					logs.Error("Failed to update DTE json_data", map[string]interface{}{
+					logs.ErrorContext(ctx, "Failed to update DTE json_data", map[string]interface{}{
 						"error": err.Error(),
 					})
 					tx.Rollback()
@@ -186,6 +186,22 @@ func (r *ContingencyRepository) GetFirstContingencyTimestamp(ctx context.Context
 	return &doc.CreatedAt, nil
 }
 
+// CountPending obtiene la cantidad de documentos en estado PENDING en la cola de contingencia
+func (r *ContingencyRepository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&db_models.ContingencyDocument{}).
+		Joins("JOIN dte_details ON contingency_documents.document_id = dte_details.id").
+		Where("dte_details.status = ?", constants.DocumentPending).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("error counting pending contingency documents: %w", err)
+	}
+
+	return count, nil
+}
+
 func convertToDomainModel(doc *db_models.ContingencyDocument) dte.ContingencyDocument {
 	return dte.ContingencyDocument{
 		ID:              doc.ID,
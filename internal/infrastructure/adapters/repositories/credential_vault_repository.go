@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// sealedSecretColumns relaciona cada ubicación de secrets cifrados con su tabla y columna
+var sealedSecretColumns = map[models.SealedSecretSource]struct{ table, column string }{
+	models.BranchSigningSecret: {table: "branch_offices", column: "api_signing_secret"},
+	models.APIKeySigningSecret: {table: "branch_api_keys", column: "api_signing_secret"},
+	models.WebhookSecret:       {table: "webhook_subscriptions", column: "secret"},
+}
+
+type CredentialVaultRepository struct {
+	db *gorm.DB
+}
+
+// NewCredentialVaultRepository crea una instancia de CredentialVaultRepository. Recibe una instancia de gorm.DB.
+func NewCredentialVaultRepository(db *gorm.DB) vault.CredentialVaultRepositoryPort {
+	return &CredentialVaultRepository{db: db}
+}
+
+// Save crea o reemplaza las credenciales cifradas del usuario
+func (r *CredentialVaultRepository) Save(ctx context.Context, sealed *models.SealedCredentials) error {
+	record := db_models.HaciendaCredentials{
+		UserID:     sealed.UserID,
+		KeyVersion: sealed.KeyVersion,
+		WrappedKey: sealed.WrappedKey,
+		Ciphertext: sealed.Ciphertext,
+		UpdatedAt:  utils.TimeNow(),
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"key_version", "wrapped_key", "ciphertext", "updated_at"}),
+		}).
+		Create(&record).Error
+}
+
+// GetByUserID obtiene las credenciales cifradas del usuario
+func (r *CredentialVaultRepository) GetByUserID(ctx context.Context, userID uint) (*models.SealedCredentials, error) {
+	var record db_models.HaciendaCredentials
+
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, shared_error.NewFormattedGeneralServiceError("CredentialVaultRepository", "GetByUserID", "HaciendaCredentialsNotFound")
+		}
+		return nil, result.Error
+	}
+
+	return toSealedCredentials(&record), nil
+}
+
+// GetByOutdatedKey obtiene las credenciales cuya llave de datos no está cifrada con la versión indicada
+func (r *CredentialVaultRepository) GetByOutdatedKey(ctx context.Context, activeVersion string, limit int) ([]models.SealedCredentials, error) {
+	var records []db_models.HaciendaCredentials
+
+	if err := r.db.WithContext(ctx).
+		Where("key_version <> ?", activeVersion).
+		Order("id").
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.SealedCredentials, len(records))
+	for i := range records {
+		result[i] = *toSealedCredentials(&records[i])
+	}
+
+	return result, nil
+}
+
+// UpdateWrappedKey reemplaza la llave de datos cifrada y su versión de llave maestra
+func (r *CredentialVaultRepository) UpdateWrappedKey(ctx context.Context, userID uint, keyVersion, wrappedKey string) error {
+	return r.db.WithContext(ctx).
+		Model(&db_models.HaciendaCredentials{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"key_version": keyVersion,
+			"wrapped_key": wrappedKey,
+			"updated_at":  utils.TimeNow(),
+		}).Error
+}
+
+// GetOutdatedSecrets obtiene, en orden de ID y a partir del ID indicado, los secrets de una ubicación que no están
+// cifrados con la versión indicada. La versión se compara con el prefijo del valor, que se almacena como "versión$secret"
+func (r *CredentialVaultRepository) GetOutdatedSecrets(ctx context.Context, source models.SealedSecretSource, activeVersion string, afterID uint, limit int) ([]models.SealedSecret, error) {
+	location, ok := sealedSecretColumns[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown sealed secret source %s", source)
+	}
+
+	var rows []struct {
+		ID    uint
+		Value string
+	}
+
+	prefix := activeVersion + models.SealedSecretSeparator
+	if err := r.db.WithContext(ctx).
+		Table(location.table).
+		Select(fmt.Sprintf("id, %s AS value", location.column)).
+		Where(fmt.Sprintf("%[1]s IS NOT NULL AND %[1]s <> '' AND SUBSTR(%[1]s, 1, ?) <> ? AND id > ?", location.column), len(prefix), prefix, afterID).
+		Order("id").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.SealedSecret, len(rows))
+	for i := range rows {
+		result[i] = models.SealedSecret{Source: source, ID: rows[i].ID, Value: rows[i].Value}
+	}
+
+	return result, nil
+}
+
+// UpdateSecret reemplaza un secret cifrado solo si conserva el valor con el que se obtuvo, retorna si fue reemplazado
+func (r *CredentialVaultRepository) UpdateSecret(ctx context.Context, secret *models.SealedSecret, value string) (bool, error) {
+	location, ok := sealedSecretColumns[secret.Source]
+	if !ok {
+		return false, fmt.Errorf("unknown sealed secret source %s", secret.Source)
+	}
+
+	result := r.db.WithContext(ctx).
+		Table(location.table).
+		Where(fmt.Sprintf("id = ? AND %s = ?", location.column), secret.ID, secret.Value).
+		Update(location.column, value)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// toSealedCredentials convierte el modelo de base de datos en el modelo de dominio
+func toSealedCredentials(record *db_models.HaciendaCredentials) *models.SealedCredentials {
+	return &models.SealedCredentials{
+		UserID:     record.UserID,
+		KeyVersion: record.KeyVersion,
+		WrappedKey: record.WrappedKey,
+		Ciphertext: record.Ciphertext,
+		UpdatedAt:  record.UpdatedAt,
+	}
+}
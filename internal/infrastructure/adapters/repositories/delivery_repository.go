@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// deliveryPayload es el contenido del evento de dominio que origina un envío
+type deliveryPayload struct {
+	GenerationCode string `json:"generation_code"`
+	DTEType        string `json:"dte_type"`
+	Recipient      string `json:"recipient"`
+}
+
+type DeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewDeliveryRepository crea una instancia de DeliveryRepository. Recibe una instancia de gorm.DB.
+func NewDeliveryRepository(db *gorm.DB) delivery.DeliveryRepositoryPort {
+	return &DeliveryRepository{db: db}
+}
+
+// Create registra un envío pendiente, cada envío genera un evento de dominio y una notificación de tipo EMAIL
+func (r *DeliveryRepository) Create(ctx context.Context, record *models.Delivery) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Obtener el usuario propietario de la sucursal
+		var branch db_models.BranchOffice
+		if err := tx.Select("id", "user_id").Where("id = ?", record.BranchID).First(&branch).Error; err != nil {
+			return handleGormErr(err, "CreateDelivery")
+		}
+
+		// 2. Registrar el evento de dominio del envío
+		payload, err := json.Marshal(deliveryPayload{
+			GenerationCode: record.GenerationCode,
+			DTEType:        record.DTEType,
+			Recipient:      record.Recipient,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := db_models.DomainEvent{
+			UserID:     &branch.UserID,
+			BranchID:   &branch.ID,
+			EventType:  models.EventTypeDTEDelivery,
+			Payload:    string(payload),
+			OccurredAt: utils.TimeNow().Format("2006-01-02 15:04:05"),
+		}
+		if err = tx.Create(&event).Error; err != nil {
+			return err
+		}
+
+		// 3. Registrar la notificación con el estado del envío
+		notification := db_models.UserNotification{
+			UserID:           branch.UserID,
+			EventID:          event.ID,
+			NotificationType: models.NotificationTypeEmail,
+			Message:          record.Subject,
+			DeliveryStatus:   models.DeliveryPending,
+			DeliveryAt:       utils.TimeNow(),
+			Recipient:        utils.ToStringPointer(record.Recipient),
+			DocumentID:       utils.ToStringPointer(record.GenerationCode),
+		}
+		if err = tx.Create(&notification).Error; err != nil {
+			return err
+		}
+
+		record.ID = notification.ID
+		record.Status = notification.DeliveryStatus
+		record.DeliveryAt = notification.DeliveryAt
+		return nil
+	})
+}
+
+// UpdateStatus actualiza el estado de un envío y el mensaje de error si existe
+func (r *DeliveryRepository) UpdateStatus(ctx context.Context, id uint, status string, errorMessage *string) error {
+	return r.db.WithContext(ctx).
+		Model(&db_models.UserNotification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"delivery_status": status,
+			"error_message":   errorMessage,
+			"delivery_at":     utils.TimeNow(),
+		}).Error
+}
+
+// GetByDocument obtiene los envíos de un DTE de la sucursal ordenados del más reciente al más antiguo
+func (r *DeliveryRepository) GetByDocument(ctx context.Context, branchID uint, generationCode string) ([]models.Delivery, error) {
+	var notifications []db_models.UserNotification
+
+	err := r.db.WithContext(ctx).
+		Preload("Event").
+		Joins("JOIN domain_events ON domain_events.id = user_notifications.event_id").
+		Where("domain_events.branch_id = ? AND user_notifications.document_id = ? AND user_notifications.notification_type = ?",
+			branchID, generationCode, models.NotificationTypeEmail).
+		Order("user_notifications.id desc").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]models.Delivery, len(notifications))
+	for i, notification := range notifications {
+		var payload deliveryPayload
+		if notification.Event != nil {
+			_ = json.Unmarshal([]byte(notification.Event.Payload), &payload)
+		}
+
+		deliveries[i] = models.Delivery{
+			ID:             notification.ID,
+			BranchID:       branchID,
+			GenerationCode: utils.PointerToString(notification.DocumentID),
+			DTEType:        payload.DTEType,
+			Recipient:      utils.PointerToString(notification.Recipient),
+			Subject:        notification.Message,
+			Status:         notification.DeliveryStatus,
+			ErrorMessage:   notification.ErrorMessage,
+			DeliveryAt:     notification.DeliveryAt,
+		}
+	}
+
+	return deliveries, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
@@ -277,8 +278,8 @@ func (D *DTERepository) GetPagedDocuments(ctx context.Context, filters *dte.DTEF
 
 	var documents []DocumentResult
 
-	// Ordenar por fecha de creación (más recientes primero)
-	query = query.Order("dte_documents.created_at DESC")
+	// Ordenar según el campo solicitado (por defecto los más recientes primero)
+	query = applySort(query, filters)
 
 	// Aplicar paginación si es necesario
 	if filters.Page > 0 && filters.PageSize > 0 {
@@ -305,6 +306,116 @@ func (D *DTERepository) GetPagedDocuments(ctx context.Context, filters *dte.DTEF
 	return result, nil
 }
 
+// StreamDocuments recorre los documentos que cumplen con los filtros utilizando paginación por cursor (fecha de creación
+// y código de generación), cada lote es una consulta independiente por lo que no se mantiene abierta una conexión
+// durante toda la exportación
+func (D *DTERepository) StreamDocuments(ctx context.Context, filters *dte.DTEFilters, batchSize int, fn func(*dte.DTEExportRecord) error) error {
+	type DocumentResult struct {
+		DocumentID     string    `gorm:"column:document_id"`
+		BranchID       uint      `gorm:"column:branch_id"`
+		CreatedAt      time.Time `gorm:"column:created_at"`
+		UpdatedAt      time.Time `gorm:"column:updated_at"`
+		ControlNumber  string    `gorm:"column:control_number"`
+		DTEType        string    `gorm:"column:dte_type"`
+		Status         string    `gorm:"column:status"`
+		Transmission   string    `gorm:"column:transmission"`
+		ReceptionStamp *string   `gorm:"column:reception_stamp"`
+		JSONData       string    `gorm:"column:json_data"`
+	}
+
+	var cursor *DocumentResult
+	for {
+		// 1. Crear la consulta del lote aplicando los filtros
+		query := D.db.WithContext(ctx).
+			Table("dte_documents").
+			Joins("JOIN dte_details ON dte_documents.document_id = dte_details.id")
+		loadFilters(query, filters)
+
+		// 2. Continuar después del último documento del lote anterior
+		if cursor != nil {
+			query = query.Where("(dte_documents.created_at > ? OR (dte_documents.created_at = ? AND dte_documents.document_id > ?))",
+				cursor.CreatedAt, cursor.CreatedAt, cursor.DocumentID)
+		}
+
+		var documents []DocumentResult
+		if err := query.Select("dte_documents.document_id, dte_documents.branch_id, dte_documents.created_at, dte_documents.updated_at, " +
+			"dte_details.control_number, dte_details.dte_type, dte_details.status, dte_details.transmission, " +
+			"dte_details.reception_stamp, dte_details.json_data").
+			Order("dte_documents.created_at ASC, dte_documents.document_id ASC").
+			Limit(batchSize).
+			Find(&documents).Error; err != nil {
+			return err
+		}
+
+		// 3. Entregar cada documento del lote
+		for i := range documents {
+			doc := documents[i]
+			if err := fn(&dte.DTEExportRecord{
+				GenerationCode: doc.DocumentID,
+				ControlNumber:  doc.ControlNumber,
+				DTEType:        doc.DTEType,
+				Status:         doc.Status,
+				Transmission:   doc.Transmission,
+				ReceptionStamp: doc.ReceptionStamp,
+				BranchID:       doc.BranchID,
+				CreatedAt:      doc.CreatedAt,
+				UpdatedAt:      doc.UpdatedAt,
+				JSONData:       doc.JSONData,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// 4. Finalizar cuando el lote no esté completo
+		if len(documents) < batchSize {
+			return nil
+		}
+		cursor = &documents[len(documents)-1]
+	}
+}
+
+// SaveArtifacts registra los artefactos de la transmisión de un DTE, los campos nil no reemplazan los valores existentes
+func (D *DTERepository) SaveArtifacts(ctx context.Context, artifacts *dte.DTEArtifacts) error {
+	record := &db_models.DTEArtifacts{
+		DocumentID:     artifacts.GenerationCode,
+		SignedDocument: artifacts.SignedDocument,
+		MHResponse:     artifacts.MHResponse,
+		CreatedAt:      utils.TimeNow(),
+		UpdatedAt:      utils.TimeNow(),
+	}
+
+	updates := []string{"updated_at"}
+	if artifacts.SignedDocument != nil {
+		updates = append(updates, "signed_document")
+	}
+	if artifacts.MHResponse != nil {
+		updates = append(updates, "mh_response")
+	}
+
+	return D.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "document_id"}},
+			DoUpdates: clause.AssignmentColumns(updates),
+		}).
+		Create(record).Error
+}
+
+// GetArtifacts obtiene los artefactos de la transmisión de un DTE, si no existen retorna artefactos vacíos
+func (D *DTERepository) GetArtifacts(ctx context.Context, generationCode string) (*dte.DTEArtifacts, error) {
+	var records []db_models.DTEArtifacts
+	if err := D.db.WithContext(ctx).Where("document_id = ?", generationCode).Limit(1).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	artifacts := &dte.DTEArtifacts{GenerationCode: generationCode}
+	if len(records) > 0 {
+		artifacts.SignedDocument = records[0].SignedDocument
+		artifacts.MHResponse = records[0].MHResponse
+	}
+
+	return artifacts, nil
+}
+
 func (D *DTERepository) GetByGenerationCode(ctx context.Context, branchID uint, generationCode string) (*dte.DTEDocument, error) {
 	var document db_models.DTEDocument
 
@@ -340,6 +451,16 @@ func loadFilters(query *gorm.DB, filters *dte.DTEFilters) {
 		query = query.Where("dte_documents.branch_id = ?", filters.BranchID)
 	}
 
+	if filters.ClientID != 0 {
+		query = query.Where("dte_documents.branch_id IN (?)",
+			query.Session(&gorm.Session{NewDB: true}).Table("branch_offices").Select("id").Where("user_id = ?", filters.ClientID))
+	}
+
+	// Los operadores consultan los documentos de todas las sucursales que tienen asignadas
+	if filters.BranchIDs != nil {
+		query = query.Where("dte_documents.branch_id IN ?", filters.BranchIDs)
+	}
+
 	if filters.DTEType != "" {
 		query = query.Where("dte_details.dte_type = ?", filters.DTEType)
 	}
@@ -359,6 +480,8 @@ func loadFilters(query *gorm.DB, filters *dte.DTEFilters) {
 	if filters.Transmission != "" {
 		query = query.Where("dte_details.transmission = ?", filters.Transmission)
 	}
+
+	loadSearchFilters(query, filters)
 }
 
 func handleGormErr(err error, operation string) error {
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+)
+
+// sortColumns columnas de ordenamiento permitidas para el listado de DTE
+var sortColumns = map[string]string{
+	dte.SortByDate:          "dte_documents.created_at",
+	dte.SortByAmount:        "dte_details.total_amount",
+	dte.SortByControlNumber: "dte_details.control_number",
+	dte.SortByReceiverName:  "dte_details.receiver_name",
+}
+
+// loadSearchFilters aplica los filtros de búsqueda sobre el contenido del DTE, los datos del receptor y el monto total
+// se consultan en columnas generadas e indexadas, los ítems del documento con consultas JSON propias de cada driver
+func loadSearchFilters(query *gorm.DB, filters *dte.DTEFilters) {
+	dialect := query.Dialector.Name()
+
+	if filters.ReceiverNIT != "" {
+		query = query.Where("dte_details.receiver_nit = ?", filters.ReceiverNIT)
+	}
+
+	if filters.ReceiverNRC != "" {
+		query = query.Where("dte_details.receiver_nrc = ?", filters.ReceiverNRC)
+	}
+
+	if filters.ReceiverName != "" {
+		query = query.Where("LOWER(dte_details.receiver_name) "+likeComparison(dialect), containsPattern(filters.ReceiverName))
+	}
+
+	if filters.ControlNumber != "" {
+		query = query.Where("LOWER(dte_details.control_number) "+likeComparison(dialect), containsPattern(filters.ControlNumber))
+	}
+
+	if filters.ReceptionStamp != "" {
+		query = query.Where("dte_details.reception_stamp = ?", filters.ReceptionStamp)
+	}
+
+	if filters.MinAmount != nil {
+		query = query.Where("dte_details.total_amount >= ?", *filters.MinAmount)
+	}
+
+	if filters.MaxAmount != nil {
+		query = query.Where("dte_details.total_amount <= ?", *filters.MaxAmount)
+	}
+
+	if filters.ItemCode != "" {
+		query = query.Where(jsonItemCondition(dialect, "codigo", "= ?"), filters.ItemCode)
+	}
+
+	if filters.ItemDescription != "" {
+		query = query.Where(jsonItemCondition(dialect, "descripcion", likeComparison(dialect)), containsPattern(filters.ItemDescription))
+	}
+}
+
+// jsonItemCondition construye la condición para buscar un campo dentro de los ítems (cuerpoDocumento) del DTE.
+// En MySQL se recorre el arreglo con JSON_TABLE, en Postgres con json_array_elements y en SQLite con json_each, para
+// los campos de texto la comparación se hace en minúsculas
+func jsonItemCondition(dialect, field, comparison string) string {
+	value := "item.value"
+	if strings.HasPrefix(comparison, "LIKE") {
+		value = "LOWER(item.value)"
+	}
+
+	switch dialect {
+	case "postgres":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM json_array_elements(dte_details.json_data->'cuerpoDocumento') AS elem, "+
+			"LATERAL (SELECT elem->>'%s' AS value) AS item WHERE %s %s)", field, value, comparison)
+	case "sqlite":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM (SELECT json_extract(elem.value, '$.%s') AS value "+
+			"FROM json_each(dte_details.json_data, '$.cuerpoDocumento') AS elem) AS item WHERE %s %s)", field, value, comparison)
+	default:
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM JSON_TABLE(dte_details.json_data, '$.cuerpoDocumento[*]' "+
+			"COLUMNS (value VARCHAR(1000) PATH '$.%s')) AS item WHERE %s %s)", field, value, comparison)
+	}
+}
+
+// applySort aplica el ordenamiento solicitado, por defecto los documentos más recientes primero
+func applySort(query *gorm.DB, filters *dte.DTEFilters) *gorm.DB {
+	column, ok := sortColumns[filters.SortBy]
+	if !ok {
+		column = sortColumns[dte.SortByDate]
+	}
+
+	order := "DESC"
+	if filters.SortOrder == dte.SortAsc {
+		order = "ASC"
+	}
+
+	query = query.Order(fmt.Sprintf("%s %s", column, order))
+	if column != sortColumns[dte.SortByDate] {
+		// Desempate estable para la paginación
+		query = query.Order("dte_documents.created_at DESC")
+	}
+
+	return query
+}
+
+// likeComparison construye la comparación LIKE para los patrones de containsPattern, MySQL y Postgres utilizan la barra
+// invertida como carácter de escape por defecto y SQLite requiere indicarlo
+func likeComparison(dialect string) string {
+	if dialect == "sqlite" {
+		return `LIKE ? ESCAPE '\'`
+	}
+	return "LIKE ?"
+}
+
+// containsPattern construye un patrón LIKE en minúsculas que escapa los comodines ingresados por el usuario
+func containsPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + strings.ToLower(replacer.Replace(value)) + "%"
+}
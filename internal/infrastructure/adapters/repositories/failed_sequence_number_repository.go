@@ -34,7 +34,7 @@ func (r *FailedSequenceNumberRepository) RegisterFailedSequence(
 	// Convertir los datos de la solicitud original a JSON
 	requestDataJSON, err := json.Marshal(originalRequestData)
 	if err != nil {
-		logs.Error("Failed to marshal original request data", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to marshal original request data", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return err
@@ -55,7 +55,7 @@ func (r *FailedSequenceNumberRepository) RegisterFailedSequence(
 
 	result := r.db.WithContext(ctx).Create(failedSeq)
 	if result.Error != nil {
-		logs.Error("Failed to register failed sequence number", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to register failed sequence number", map[string]interface{}{
 			"error":          result.Error.Error(),
 			"branchID":       branchID,
 			"dteType":        dteType,
@@ -64,7 +64,7 @@ func (r *FailedSequenceNumberRepository) RegisterFailedSequence(
 		return result.Error
 	}
 
-	logs.Info("Failed sequence number registered successfully", map[string]interface{}{
+	logs.InfoContext(ctx, "Failed sequence number registered successfully", map[string]interface{}{
 		"id":             failedSeq.ID,
 		"branchID":       branchID,
 		"dteType":        dteType,
@@ -91,7 +91,7 @@ func (r *FailedSequenceNumberRepository) GetFailedSequences(
 		Find(&failedSequences)
 
 	if result.Error != nil {
-		logs.Error("Failed to get failed sequences", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to get failed sequences", map[string]interface{}{
 			"error":    result.Error.Error(),
 			"branchID": branchID,
 			"dteType":  dteType,
@@ -119,7 +119,7 @@ func (r *FailedSequenceNumberRepository) GetFailedSequencesByYear(
 		Find(&failedSequences)
 
 	if result.Error != nil {
-		logs.Error("Failed to get failed sequences by year", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to get failed sequences by year", map[string]interface{}{
 			"error":    result.Error.Error(),
 			"branchID": branchID,
 			"dteType":  dteType,
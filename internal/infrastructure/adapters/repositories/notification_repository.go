@@ -0,0 +1,415 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// defaultNotificationsLimit cantidad de notificaciones retornadas cuando la consulta no indica un límite
+const defaultNotificationsLimit = 50
+
+// alertChannels tipos de user_notifications que corresponden a alertas, los demás son envíos de DTE
+var alertChannels = []string{models.ChannelEmail, models.ChannelWebhook}
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository crea una instancia de NotificationRepository. Recibe una instancia de gorm.DB.
+func NewNotificationRepository(db *gorm.DB) notification.NotificationRepositoryPort {
+	return &NotificationRepository{db: db}
+}
+
+// GetRecipients obtiene los destinatarios de un tipo de entidad ordenados por su ID
+func (r *NotificationRepository) GetRecipients(ctx context.Context, userID uint, entityType string) ([]models.Recipient, error) {
+	var records []db_models.NotifiableUser
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND entity_type = ?", userID, entityType).
+		Order("id").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toRecipients(records), nil
+}
+
+// GetAlertRecipients obtiene los destinatarios habilitados de los tipos de entidad, los de tipo CLIENT solo del
+// contribuyente y los de tipo ADMIN sin importar el contribuyente
+func (r *NotificationRepository) GetAlertRecipients(ctx context.Context, userID uint, entityTypes []string) ([]models.Recipient, error) {
+	var records []db_models.NotifiableUser
+
+	query := r.db.WithContext(ctx).Where("enabled_push = ?", true)
+	conditions := r.db.Where("1 = 0")
+	for _, entityType := range entityTypes {
+		switch entityType {
+		case models.EntityClient:
+			conditions = conditions.Or("entity_type = ? AND user_id = ?", models.EntityClient, userID)
+		case models.EntityAdmin:
+			conditions = conditions.Or("entity_type = ?", models.EntityAdmin)
+		}
+	}
+
+	if err := query.Where(conditions).Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return toRecipients(records), nil
+}
+
+// CreateRecipient registra un destinatario
+func (r *NotificationRepository) CreateRecipient(ctx context.Context, recipient *models.Recipient) error {
+	record := db_models.NotifiableUser{
+		UserID:      recipient.UserID,
+		EntityType:  recipient.EntityType,
+		Email:       recipient.Email,
+		EnabledPush: recipient.EnabledPush,
+	}
+
+	if err := r.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return err
+	}
+
+	recipient.ID = record.ID
+	return nil
+}
+
+// DeleteRecipient elimina un destinatario, las notificaciones que ya recibió se conservan como historial
+func (r *NotificationRepository) DeleteRecipient(ctx context.Context, userID uint, entityType string, id uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND entity_type = ?", id, userID, entityType).
+		Delete(&db_models.NotifiableUser{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_error.NewFormattedGeneralServiceError("NotificationRepository", "DeleteRecipient", "NotificationRecipientNotFound", id)
+	}
+
+	return nil
+}
+
+// GetTaxpayer obtiene el NIT y el nombre de un contribuyente
+func (r *NotificationRepository) GetTaxpayer(ctx context.Context, userID uint) (*models.Taxpayer, error) {
+	var user db_models.User
+	if err := r.db.WithContext(ctx).Select("id", "nit", "business_name").Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, handleGormErr(err, "GetTaxpayer")
+	}
+
+	return &models.Taxpayer{UserID: user.ID, NIT: user.NIT, BusinessName: user.Business}, nil
+}
+
+// GetBranchTaxpayer obtiene el contribuyente propietario de una sucursal
+func (r *NotificationRepository) GetBranchTaxpayer(ctx context.Context, branchID uint) (*models.Taxpayer, error) {
+	var taxpayer models.Taxpayer
+
+	err := r.db.WithContext(ctx).
+		Table("branch_offices").
+		Select("users.id AS user_id, users.nit, users.business_name").
+		Joins("JOIN users ON users.id = branch_offices.user_id").
+		Where("branch_offices.id = ?", branchID).
+		Take(&taxpayer).Error
+	if err != nil {
+		return nil, handleGormErr(err, "GetBranchTaxpayer")
+	}
+
+	return &taxpayer, nil
+}
+
+// CreateNotifications registra el evento de dominio de la alerta y una notificación por canal y destinatario
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, alert *models.Alert, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Registrar el evento de dominio de la alerta
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+
+		event := db_models.DomainEvent{
+			UserID:     &alert.UserID,
+			EventType:  alert.Type,
+			Payload:    string(payload),
+			OccurredAt: alert.OccurredAt.Format("2006-01-02 15:04:05"),
+		}
+		if alert.BranchID != 0 {
+			event.BranchID = &alert.BranchID
+		}
+		if err = tx.Create(&event).Error; err != nil {
+			return err
+		}
+
+		// 2. Registrar las notificaciones de la alerta
+		records := make([]db_models.UserNotification, len(notifications))
+		for i, n := range notifications {
+			records[i] = db_models.UserNotification{
+				UserID:           n.UserID,
+				EventID:          event.ID,
+				NotificationType: n.Channel,
+				Message:          n.Message,
+				DeliveryStatus:   n.Status,
+				DeliveryAt:       n.DeliveryAt,
+				Recipient:        n.Recipient,
+				DocumentID:       n.DocumentID,
+				Attempts:         n.Attempts,
+				NextAttemptAt:    n.NextAttemptAt,
+			}
+		}
+		if err = tx.Create(&records).Error; err != nil {
+			return err
+		}
+
+		for i := range notifications {
+			notifications[i].ID = records[i].ID
+			notifications[i].EventID = event.ID
+			notifications[i].Payload = payload
+		}
+		return nil
+	})
+}
+
+// GetNotifications obtiene las alertas de un contribuyente que cumplen con los filtros de la más reciente a la más antigua
+func (r *NotificationRepository) GetNotifications(ctx context.Context, userID uint, filters *models.NotificationFilters) ([]models.Notification, error) {
+	var records []db_models.UserNotification
+
+	query := r.db.WithContext(ctx).
+		Preload("Event").
+		Joins("JOIN domain_events ON domain_events.id = user_notifications.event_id").
+		Where("user_notifications.user_id = ? AND user_notifications.notification_type IN ?", userID, alertChannels)
+	if filters.Alert != "" {
+		query = query.Where("domain_events.event_type = ?", filters.Alert)
+	}
+	if filters.Channel != "" {
+		query = query.Where("user_notifications.notification_type = ?", filters.Channel)
+	}
+	if filters.Status != "" {
+		query = query.Where("user_notifications.delivery_status = ?", filters.Status)
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = defaultNotificationsLimit
+	}
+
+	if err := query.Order("user_notifications.id desc").Limit(limit).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return toNotifications(records), nil
+}
+
+// GetDueNotifications obtiene las alertas pendientes cuyo siguiente intento ya está programado, de la más antigua a la más reciente
+func (r *NotificationRepository) GetDueNotifications(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	var records []db_models.UserNotification
+
+	err := r.db.WithContext(ctx).
+		Preload("Event").
+		Where("notification_type IN ? AND delivery_status = ? AND next_attempt_at <= ?", alertChannels, models.NotificationPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toNotifications(records), nil
+}
+
+// ClaimNotification reserva una alerta moviendo su siguiente intento a leaseUntil, la actualización solo afecta a la
+// alerta si sigue pendiente y su intento ya está programado, por lo que solo un proceso puede reservarla
+func (r *NotificationRepository) ClaimNotification(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&db_models.UserNotification{}).
+		Where("id = ? AND delivery_status = ? AND next_attempt_at <= ?", id, models.NotificationPending, now).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateNotification actualiza el estado, los intentos, el siguiente intento y el error de una alerta
+func (r *NotificationRepository) UpdateNotification(ctx context.Context, n *models.Notification) error {
+	return r.db.WithContext(ctx).
+		Model(&db_models.UserNotification{}).
+		Where("id = ?", n.ID).
+		Updates(map[string]interface{}{
+			"delivery_status": n.Status,
+			"attempts":        n.Attempts,
+			"next_attempt_at": n.NextAttemptAt,
+			"error_message":   n.ErrorMessage,
+			"delivery_at":     n.DeliveryAt,
+		}).Error
+}
+
+// SetCertificateExpiry registra la fecha de vencimiento del certificado de firma de un contribuyente
+func (r *NotificationRepository) SetCertificateExpiry(ctx context.Context, userID uint, expiresAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&db_models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"certificate_expires_at": expiresAt,
+			"updated_at":             utils.TimeNow(),
+		}).Error
+}
+
+// GetExpiringCertificates obtiene los contribuyentes activos cuyo certificado vence antes de until, incluidos los vencidos
+func (r *NotificationRepository) GetExpiringCertificates(ctx context.Context, until time.Time) ([]models.CertificateExpiry, error) {
+	var users []db_models.User
+
+	err := r.db.WithContext(ctx).
+		Select("id", "nit", "business_name", "certificate_expires_at").
+		Where("status = ? AND certificate_expires_at IS NOT NULL AND certificate_expires_at <= ?", true, until).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]models.CertificateExpiry, len(users))
+	for i, user := range users {
+		certificates[i] = models.CertificateExpiry{
+			Taxpayer:  models.Taxpayer{UserID: user.ID, NIT: user.NIT, BusinessName: user.Business},
+			ExpiresAt: *user.CertificateExpiresAt,
+		}
+	}
+	return certificates, nil
+}
+
+// GetContingencyBacklog obtiene por sucursal los documentos pendientes en contingencia desde antes de createdBefore
+func (r *NotificationRepository) GetContingencyBacklog(ctx context.Context, createdBefore time.Time) ([]models.ContingencyBacklog, error) {
+	var rows []struct {
+		BranchID     uint
+		UserID       uint
+		NIT          string
+		BusinessName string
+		Documents    int
+		OldestAt     time.Time
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("contingency_documents").
+		Select("contingency_documents.branch_id, users.id AS user_id, users.nit, users.business_name, "+
+			"COUNT(*) AS documents, MIN(contingency_documents.created_at) AS oldest_at").
+		Joins("JOIN dte_details ON dte_details.id = contingency_documents.document_id").
+		Joins("JOIN branch_offices ON branch_offices.id = contingency_documents.branch_id").
+		Joins("JOIN users ON users.id = branch_offices.user_id").
+		Where("dte_details.status = ? AND contingency_documents.created_at <= ?", constants.DocumentPending, createdBefore).
+		Group("contingency_documents.branch_id, users.id, users.nit, users.business_name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	backlog := make([]models.ContingencyBacklog, len(rows))
+	for i, row := range rows {
+		backlog[i] = models.ContingencyBacklog{
+			Taxpayer:  models.Taxpayer{UserID: row.UserID, NIT: row.NIT, BusinessName: row.BusinessName},
+			BranchID:  row.BranchID,
+			Documents: row.Documents,
+			OldestAt:  row.OldestAt,
+		}
+	}
+	return backlog, nil
+}
+
+// GetSequenceGaps obtiene los números de control rechazados registrados desde since junto con su contribuyente
+func (r *NotificationRepository) GetSequenceGaps(ctx context.Context, since time.Time) ([]models.SequenceGap, error) {
+	var rows []struct {
+		ID             uint
+		BranchID       uint
+		UserID         uint
+		NIT            string
+		BusinessName   string
+		DTEType        string
+		SequenceNumber uint
+		Year           uint
+		FailureReason  string
+		CreatedAt      time.Time
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("failed_sequence_numbers").
+		Select("failed_sequence_numbers.id, failed_sequence_numbers.branch_id, users.id AS user_id, users.nit, "+
+			"users.business_name, failed_sequence_numbers.dte_type, failed_sequence_numbers.sequence_number, "+
+			"failed_sequence_numbers.year, failed_sequence_numbers.failure_reason, failed_sequence_numbers.created_at").
+		Joins("JOIN branch_offices ON branch_offices.id = failed_sequence_numbers.branch_id").
+		Joins("JOIN users ON users.id = branch_offices.user_id").
+		Where("failed_sequence_numbers.created_at >= ?", since).
+		Order("failed_sequence_numbers.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := make([]models.SequenceGap, len(rows))
+	for i, row := range rows {
+		gaps[i] = models.SequenceGap{
+			Taxpayer:       models.Taxpayer{UserID: row.UserID, NIT: row.NIT, BusinessName: row.BusinessName},
+			ID:             row.ID,
+			BranchID:       row.BranchID,
+			DTEType:        row.DTEType,
+			SequenceNumber: row.SequenceNumber,
+			Year:           row.Year,
+			FailureReason:  row.FailureReason,
+			CreatedAt:      row.CreatedAt,
+		}
+	}
+	return gaps, nil
+}
+
+// toRecipients convierte los registros de destinatarios en modelos de dominio
+func toRecipients(records []db_models.NotifiableUser) []models.Recipient {
+	recipients := make([]models.Recipient, len(records))
+	for i, record := range records {
+		recipients[i] = models.Recipient{
+			ID:          record.ID,
+			UserID:      record.UserID,
+			EntityType:  record.EntityType,
+			Email:       record.Email,
+			EnabledPush: record.EnabledPush,
+		}
+	}
+	return recipients
+}
+
+// toNotifications convierte los registros de alertas en modelos de dominio, el tipo de alerta y su contenido se
+// obtienen del evento de dominio
+func toNotifications(records []db_models.UserNotification) []models.Notification {
+	notifications := make([]models.Notification, len(records))
+	for i, record := range records {
+		notifications[i] = models.Notification{
+			ID:            record.ID,
+			UserID:        record.UserID,
+			EventID:       record.EventID,
+			Channel:       record.NotificationType,
+			Recipient:     record.Recipient,
+			DocumentID:    record.DocumentID,
+			Message:       record.Message,
+			Status:        record.DeliveryStatus,
+			Attempts:      record.Attempts,
+			NextAttemptAt: record.NextAttemptAt,
+			ErrorMessage:  record.ErrorMessage,
+			DeliveryAt:    record.DeliveryAt,
+		}
+		if record.Event != nil {
+			notifications[i].Alert = record.Event.EventType
+			notifications[i].Payload = json.RawMessage(record.Event.Payload)
+		}
+	}
+	return notifications
+}
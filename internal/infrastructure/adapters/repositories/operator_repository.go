@@ -0,0 +1,253 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
+)
+
+// managedBranchResult es el resultado de la consulta de sucursales asignadas a un operador
+type managedBranchResult struct {
+	UserID            uint    `gorm:"column:user_id"`
+	BranchID          uint    `gorm:"column:branch_id"`
+	NIT               string  `gorm:"column:nit"`
+	BusinessName      string  `gorm:"column:business_name"`
+	AuthType          string  `gorm:"column:auth_type"`
+	EstablishmentType string  `gorm:"column:establishment_type"`
+	EstablishmentCode *string `gorm:"column:establishment_code"`
+	POSCode           *string `gorm:"column:pos_code"`
+}
+
+type OperatorRepository struct {
+	db *gorm.DB
+}
+
+// NewOperatorRepository crea una instancia de OperatorRepository. Recibe una instancia de gorm.DB.
+func NewOperatorRepository(db *gorm.DB) operator.OperatorRepositoryPort {
+	return &OperatorRepository{db: db}
+}
+
+// Create crea un operador
+func (r *OperatorRepository) Create(ctx context.Context, operator *models.Operator) error {
+	record := db_models.Operator{
+		Name:      operator.Name,
+		Email:     operator.Email,
+		APIKey:    operator.APIKey,
+		APISecret: operator.APISecret,
+		IsActive:  operator.IsActive,
+	}
+
+	if err := r.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return err
+	}
+
+	operator.ID = record.ID
+	operator.CreatedAt = record.CreatedAt
+	return nil
+}
+
+// GetAll obtiene todos los operadores con sus accesos
+func (r *OperatorRepository) GetAll(ctx context.Context) ([]models.Operator, error) {
+	var records []db_models.Operator
+
+	result := r.db.WithContext(ctx).
+		Preload("Grants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id asc")
+		}).
+		Order("id asc").
+		Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	operators := make([]models.Operator, len(records))
+	for i := range records {
+		operators[i] = *toDomainOperator(&records[i])
+	}
+
+	return operators, nil
+}
+
+// GetByAPIKey obtiene un operador por su API key
+func (r *OperatorRepository) GetByAPIKey(ctx context.Context, apiKey string) (*models.Operator, error) {
+	var record db_models.Operator
+
+	result := r.db.WithContext(ctx).Where("api_key = ?", apiKey).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errPackage.ErrOperatorNotFound
+		}
+		return nil, result.Error
+	}
+
+	return toDomainOperator(&record), nil
+}
+
+// CreateGrant crea un acceso, el contribuyente debe estar activo y la sucursal, si se indica, debe pertenecerle
+func (r *OperatorRepository) CreateGrant(ctx context.Context, grant *models.OperatorGrant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Comprobar que el operador existe
+		var count int64
+		tx.Model(&db_models.Operator{}).Where("id = ?", grant.OperatorID).Count(&count)
+		if count == 0 {
+			return errPackage.ErrOperatorNotFound
+		}
+
+		// 2. Comprobar que el contribuyente existe, está activo y su registro fue aprobado
+		tx.Model(&db_models.User{}).
+			Where("id = ? AND status = ? AND registration_status = ?", grant.UserID, true, user.RegistrationApproved).
+			Count(&count)
+		if count == 0 {
+			return errPackage.ErrUserNotFound
+		}
+
+		// 3. Comprobar que la sucursal pertenece al contribuyente
+		if grant.BranchID != nil {
+			tx.Model(&db_models.BranchOffice{}).Where("id = ? AND user_id = ?", *grant.BranchID, grant.UserID).Count(&count)
+			if count == 0 {
+				return errPackage.ErrBranchDoesNotBelong
+			}
+		}
+
+		// 4. Comprobar que el acceso no existe
+		query := tx.Model(&db_models.OperatorGrant{}).Where("operator_id = ? AND user_id = ?", grant.OperatorID, grant.UserID)
+		if grant.BranchID != nil {
+			query = query.Where("branch_id = ?", *grant.BranchID)
+		} else {
+			query = query.Where("branch_id IS NULL")
+		}
+		query.Count(&count)
+		if count > 0 {
+			return errPackage.ErrOperatorGrantExists
+		}
+
+		// 5. Crear el acceso
+		record := db_models.OperatorGrant{
+			OperatorID: grant.OperatorID,
+			UserID:     grant.UserID,
+			BranchID:   grant.BranchID,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+
+		grant.ID = record.ID
+		grant.CreatedAt = record.CreatedAt
+		return nil
+	})
+}
+
+// DeleteGrant elimina un acceso de un operador y retorna el acceso eliminado
+func (r *OperatorRepository) DeleteGrant(ctx context.Context, operatorID, grantID uint) (*models.OperatorGrant, error) {
+	var record db_models.OperatorGrant
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Obtener el acceso del operador
+		result := tx.Where("id = ? AND operator_id = ?", grantID, operatorID).First(&record)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return errPackage.ErrOperatorGrantNotFound
+			}
+			return result.Error
+		}
+
+		// 2. Eliminar el acceso
+		return tx.Delete(&record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OperatorGrant{
+		ID:         record.ID,
+		OperatorID: record.OperatorID,
+		UserID:     record.UserID,
+		BranchID:   record.BranchID,
+		CreatedAt:  record.CreatedAt,
+	}, nil
+}
+
+// Deactivate desactiva un operador, a partir de ese momento no puede iniciar sesión
+func (r *OperatorRepository) Deactivate(ctx context.Context, operatorID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Comprobar que el operador existe
+		var count int64
+		tx.Model(&db_models.Operator{}).Where("id = ?", operatorID).Count(&count)
+		if count == 0 {
+			return errPackage.ErrOperatorNotFound
+		}
+
+		// 2. Desactivar el operador
+		return tx.Model(&db_models.Operator{}).Where("id = ?", operatorID).Update("is_active", false).Error
+	})
+}
+
+// GetManagedBranches obtiene las sucursales activas de los contribuyentes activos asignados al operador. Un acceso sin
+// sucursal abarca todas las sucursales del contribuyente, por lo que se eliminan las sucursales repetidas
+func (r *OperatorRepository) GetManagedBranches(ctx context.Context, operatorID uint) ([]models.ManagedBranch, error) {
+	var results []managedBranchResult
+
+	err := r.db.WithContext(ctx).
+		Table("operator_grants").
+		Select("DISTINCT users.id AS user_id, branch_offices.id AS branch_id, users.nit, users.business_name, "+
+			"users.auth_type, branch_offices.establishment_type, branch_offices.establishment_code, branch_offices.pos_code").
+		Joins("JOIN users ON users.id = operator_grants.user_id").
+		Joins("JOIN branch_offices ON branch_offices.user_id = users.id AND "+
+			"(operator_grants.branch_id IS NULL OR operator_grants.branch_id = branch_offices.id)").
+		Where("operator_grants.operator_id = ?", operatorID).
+		Where("users.status = ? AND users.registration_status = ?", true, user.RegistrationApproved).
+		Where("branch_offices.is_active = ?", true).
+		Order("users.id asc, branch_offices.id asc").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]models.ManagedBranch, len(results))
+	for i, result := range results {
+		branches[i] = models.ManagedBranch{
+			UserID:            result.UserID,
+			BranchID:          result.BranchID,
+			NIT:               result.NIT,
+			BusinessName:      result.BusinessName,
+			AuthType:          result.AuthType,
+			EstablishmentType: result.EstablishmentType,
+			EstablishmentCode: result.EstablishmentCode,
+			POSCode:           result.POSCode,
+		}
+	}
+
+	return branches, nil
+}
+
+// toDomainOperator convierte un operador de base de datos al modelo de dominio
+func toDomainOperator(record *db_models.Operator) *models.Operator {
+	grants := make([]models.OperatorGrant, len(record.Grants))
+	for i, grant := range record.Grants {
+		grants[i] = models.OperatorGrant{
+			ID:         grant.ID,
+			OperatorID: grant.OperatorID,
+			UserID:     grant.UserID,
+			BranchID:   grant.BranchID,
+			CreatedAt:  grant.CreatedAt,
+		}
+	}
+
+	return &models.Operator{
+		ID:        record.ID,
+		Name:      record.Name,
+		Email:     record.Email,
+		APIKey:    record.APIKey,
+		APISecret: record.APISecret,
+		IsActive:  record.IsActive,
+		Grants:    grants,
+		CreatedAt: record.CreatedAt,
+	}
+}
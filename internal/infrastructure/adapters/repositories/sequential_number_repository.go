@@ -6,8 +6,11 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ControlNumberRepository struct {
@@ -20,14 +23,21 @@ func NewControlNumberRepository(db *gorm.DB) ports.SequentialNumberRepositoryPor
 }
 
 // GetNext obtiene el siguiente número de control para un tipo de DTE, NIT de sistema y código de establecimiento.
-func (r *ControlNumberRepository) GetNext(ctx context.Context, dteType string, branchID uint) (int, error) {
+func (r *ControlNumberRepository) GetNext(ctx context.Context, dteType string, branchID uint) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "ControlNumberRepository.GetNext",
+		attribute.String("dte.type", dteType),
+		attribute.Int("branch.id", int(branchID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	currentYear := utils.TimeNow().Year()
 	var sequence db_models.ControlNumberSequence
 
 	// 1. Crear transacción para obtener el siguiente número de control de la secuencia
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// FOR UPDATE bloquea la fila para otras transacciones
-		result := tx.Set("gorm:query_option", "FOR UPDATE").
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// FOR UPDATE bloquea la fila para otras transacciones, SQLite no lo admite y bloquea la base de datos completa
+		// al iniciar la transacción
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("branch_id = ? AND dte_type = ? AND year = ?", branchID, dteType, currentYear).
 			First(&sequence)
 
@@ -61,7 +71,7 @@ func (r *ControlNumberRepository) GetNext(ctx context.Context, dteType string, b
 	})
 
 	if err != nil {
-		logs.Error("Failed to get next control number", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to get next control number", map[string]interface{}{
 			"dteType":  dteType,
 			"branchID": branchID,
 			"error":    err.Error(),
@@ -0,0 +1,330 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+// defaultDeliveriesLimit cantidad de entregas retornadas cuando la consulta no indica un límite
+const defaultDeliveriesLimit = 50
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository crea una instancia de WebhookRepository. Recibe una instancia de gorm.DB.
+func NewWebhookRepository(db *gorm.DB) webhook.WebhookRepositoryPort {
+	return &WebhookRepository{db: db}
+}
+
+// CreateSubscription registra una suscripción, los eventos se almacenan separados por comas
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.Subscription) error {
+	record := db_models.WebhookSubscription{
+		UserID:    subscription.UserID,
+		URL:       subscription.URL,
+		Events:    strings.Join(subscription.Events, ","),
+		Secret:    subscription.Secret,
+		IsActive:  subscription.IsActive,
+		CreatedAt: subscription.CreatedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return err
+	}
+
+	subscription.ID = record.ID
+	return nil
+}
+
+// GetSubscriptions obtiene las suscripciones de un contribuyente ordenadas por su fecha de creación
+func (r *WebhookRepository) GetSubscriptions(ctx context.Context, userID uint) ([]models.Subscription, error) {
+	var records []db_models.WebhookSubscription
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscriptions(records), nil
+}
+
+// GetSubscriptionsByBranch obtiene las suscripciones activas del contribuyente propietario de una sucursal
+func (r *WebhookRepository) GetSubscriptionsByBranch(ctx context.Context, branchID uint) ([]models.Subscription, error) {
+	var records []db_models.WebhookSubscription
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN branch_offices ON branch_offices.user_id = webhook_subscriptions.user_id").
+		Where("branch_offices.id = ? AND webhook_subscriptions.is_active = ?", branchID, true).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscriptions(records), nil
+}
+
+// GetSubscription obtiene una suscripción por su ID
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uint) (*models.Subscription, error) {
+	var record db_models.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_error.NewFormattedGeneralServiceError("WebhookRepository", "GetSubscription", "WebhookSubscriptionNotFound", id)
+		}
+		return nil, err
+	}
+
+	subscription := toSubscription(record)
+	return &subscription, nil
+}
+
+// DeleteSubscription elimina una suscripción del contribuyente, sus entregas pendientes se descartan y las demás
+// entregas se conservan como historial
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, userID, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Eliminar la suscripción
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&db_models.WebhookSubscription{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return shared_error.NewFormattedGeneralServiceError("WebhookRepository", "DeleteSubscription", "WebhookSubscriptionNotFound", id)
+		}
+
+		// 2. Descartar las entregas pendientes de la suscripción
+		return tx.Model(&db_models.WebhookDelivery{}).
+			Where("subscription_id = ? AND status = ?", id, models.DeliveryPending).
+			Updates(map[string]interface{}{
+				"status":          models.DeliveryDead,
+				"next_attempt_at": nil,
+				"last_error":      "subscription deleted",
+			}).Error
+	})
+}
+
+// CreateDeliveries registra las entregas de un evento en una sola operación
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	records := make([]db_models.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		records[i] = db_models.WebhookDelivery{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			UserID:         delivery.UserID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        string(delivery.Payload),
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			CreatedAt:      delivery.CreatedAt,
+		}
+	}
+
+	return r.db.WithContext(ctx).Create(&records).Error
+}
+
+// GetDeliveries obtiene las entregas de un contribuyente que cumplen con los filtros de la más reciente a la más antigua
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, userID uint, filters *models.DeliveryFilters) ([]models.Delivery, error) {
+	var records []db_models.WebhookDelivery
+
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if filters.SubscriptionID != 0 {
+		query = query.Where("subscription_id = ?", filters.SubscriptionID)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.EventType != "" {
+		query = query.Where("event_type = ?", filters.EventType)
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	if err := query.Order("created_at desc").Limit(limit).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]models.Delivery, len(records))
+	for i, record := range records {
+		deliveries[i] = toDelivery(record)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery obtiene una entrega de un contribuyente junto con sus intentos ordenados
+func (r *WebhookRepository) GetDelivery(ctx context.Context, userID uint, id string) (*models.Delivery, error) {
+	var record db_models.WebhookDelivery
+
+	err := r.db.WithContext(ctx).
+		Preload("Logs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("attempt")
+		}).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_error.NewFormattedGeneralServiceError("WebhookRepository", "GetDelivery", "WebhookDeliveryNotFound", id)
+		}
+		return nil, err
+	}
+
+	delivery := toDelivery(record)
+	delivery.Logs = make([]models.DeliveryAttempt, len(record.Logs))
+	for i, log := range record.Logs {
+		delivery.Logs[i] = models.DeliveryAttempt{
+			Attempt:      log.Attempt,
+			StatusCode:   log.StatusCode,
+			ErrorMessage: log.ErrorMessage,
+			DurationMs:   log.DurationMs,
+			AttemptedAt:  log.AttemptedAt,
+		}
+	}
+
+	return &delivery, nil
+}
+
+// GetDueDeliveries obtiene las entregas pendientes cuyo siguiente intento ya está programado, de la más antigua a la más reciente
+func (r *WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error) {
+	var records []db_models.WebhookDelivery
+
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]models.Delivery, len(records))
+	for i, record := range records {
+		deliveries[i] = toDelivery(record)
+	}
+	return deliveries, nil
+}
+
+// ClaimDelivery reserva una entrega moviendo su siguiente intento a leaseUntil, la actualización solo afecta a la
+// entrega si sigue pendiente y su intento ya está programado, por lo que solo un proceso puede reservarla
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&db_models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, models.DeliveryPending, now).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// RecordAttempt registra un intento de entrega y actualiza el estado, los intentos y el siguiente intento de la entrega
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *models.Delivery, attempt *models.DeliveryAttempt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Registrar el intento
+		record := db_models.WebhookDeliveryAttempt{
+			DeliveryID:   delivery.ID,
+			Attempt:      attempt.Attempt,
+			StatusCode:   attempt.StatusCode,
+			ErrorMessage: attempt.ErrorMessage,
+			DurationMs:   attempt.DurationMs,
+			AttemptedAt:  attempt.AttemptedAt,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+
+		// 2. Actualizar el estado de la entrega
+		return tx.Model(&db_models.WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Updates(map[string]interface{}{
+				"status":           delivery.Status,
+				"attempts":         delivery.Attempts,
+				"next_attempt_at":  delivery.NextAttemptAt,
+				"last_status_code": delivery.LastStatusCode,
+				"last_error":       delivery.LastError,
+				"delivered_at":     delivery.DeliveredAt,
+			}).Error
+	})
+}
+
+// ResetDelivery programa un nuevo intento inmediato de una entrega reiniciando sus intentos, el registro de los
+// intentos anteriores se conserva
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, delivery *models.Delivery) error {
+	return r.db.WithContext(ctx).
+		Model(&db_models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+}
+
+// toSubscriptions convierte los registros de suscripciones en modelos de dominio
+func toSubscriptions(records []db_models.WebhookSubscription) []models.Subscription {
+	subscriptions := make([]models.Subscription, len(records))
+	for i, record := range records {
+		subscriptions[i] = toSubscription(record)
+	}
+	return subscriptions
+}
+
+// toSubscription convierte un registro de suscripción en su modelo de dominio
+func toSubscription(record db_models.WebhookSubscription) models.Subscription {
+	events := make([]string, 0)
+	for _, event := range strings.Split(record.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+
+	return models.Subscription{
+		ID:        record.ID,
+		UserID:    record.UserID,
+		URL:       record.URL,
+		Events:    events,
+		Secret:    record.Secret,
+		IsActive:  record.IsActive,
+		CreatedAt: record.CreatedAt,
+	}
+}
+
+// toDelivery convierte un registro de entrega en su modelo de dominio
+func toDelivery(record db_models.WebhookDelivery) models.Delivery {
+	return models.Delivery{
+		ID:             record.ID,
+		SubscriptionID: record.SubscriptionID,
+		UserID:         record.UserID,
+		EventID:        record.EventID,
+		EventType:      record.EventType,
+		Payload:        json.RawMessage(record.Payload),
+		Status:         record.Status,
+		Attempts:       record.Attempts,
+		NextAttemptAt:  record.NextAttemptAt,
+		LastStatusCode: record.LastStatusCode,
+		LastError:      record.LastError,
+		CreatedAt:      record.CreatedAt,
+		DeliveredAt:    record.DeliveredAt,
+	}
+}
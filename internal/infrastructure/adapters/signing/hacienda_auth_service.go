@@ -15,15 +15,18 @@ import (
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
 	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 )
 
 type HaciendaAuthService struct {
 	client      *http.Client
 	cache       ports.CacheManager
 	authService auth.AuthManager
+	vault       vault.CredentialVault
 }
 
 type haciendaAuthRequest struct {
@@ -39,45 +42,78 @@ type haciendaAuthResponse struct {
 	} `json:"body"`
 }
 
-// NewHaciendaAuthService crea una instancia de HaciendaAuthService. Recibe un cache de tokens de Hacienda.
-func NewHaciendaAuthService(cache ports.CacheManager, authService auth.AuthManager) ports2.HaciendaAuthManager {
+// NewHaciendaAuthService crea una instancia de HaciendaAuthService. Recibe un cache de tokens de Hacienda y el vault
+// de credenciales.
+func NewHaciendaAuthService(cache ports.CacheManager, authService auth.AuthManager, credentialVault vault.CredentialVault) ports2.HaciendaAuthManager {
 	return &HaciendaAuthService{
 		authService: authService,
-		client:      &http.Client{},
+		vault:       credentialVault,
+		client:      &http.Client{Transport: tracing.NewTransport(nil)},
 		cache:       cache,
 	}
 }
 
 // GetOrCreateHaciendaToken obtiene un token de Hacienda, primero verificando la caché.
 func (s *HaciendaAuthService) GetOrCreateHaciendaToken(ctx context.Context, systemToken string) (string, error) {
-	haciendaToken, err := s.getFromCache(systemToken)
+	haciendaToken, err := s.getFromCache(ctx, systemToken)
 	if err == nil {
-		logs.Info("Token found in cache, skipping authentication")
+		logs.InfoContext(ctx, "Token found in cache, skipping authentication")
 		return haciendaToken, nil
 	}
 
-	logs.Info("Token not found in cache, starting process to get it")
+	logs.InfoContext(ctx, "Token not found in cache, starting process to get it")
 	//Obtener del contexto los claims
 	claims, ok := ctx.Value("claims").(*models.AuthClaims)
 	if !ok {
-		logs.Error("Claims not found in context")
+		logs.ErrorContext(ctx, "Claims not found in context")
 		return "", shared_error.NewGeneralServiceError("AuthHacienda", "GetOrCreateHaciendaToken", "claims not found in context", nil)
 	}
-	logs.Info("Claims found in context", map[string]interface{}{"claims": claims})
+	logs.InfoContext(ctx, "Claims found in context", map[string]interface{}{
+		"clientID": claims.ClientID,
+		"branchID": claims.BranchID,
+	})
 
 	//Obtener las credenciales de hacienda
-	haciendaCreds, err := s.authService.GetHaciendaCredentials(ctx, claims.NIT, systemToken)
+	haciendaCreds, err := s.getCredentials(ctx, claims, systemToken)
 	if err != nil {
-		logs.Error("Error getting hacienda credentials", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error getting hacienda credentials", map[string]interface{}{"error": err.Error()})
 		return "", err
 	}
-	logs.Info("Hacienda credentials retrieved", map[string]interface{}{"haciendaCreds": haciendaCreds})
+	logs.InfoContext(ctx, "Hacienda credentials retrieved", map[string]interface{}{"nit": claims.NIT})
 
 	return s.createAndCacheToken(ctx, systemToken, *haciendaCreds)
 }
 
+// getCredentials obtiene las credenciales de Hacienda del vault, si el usuario aún no las tiene almacenadas se
+// obtienen de la sesión y se guardan en el vault
+func (s *HaciendaAuthService) getCredentials(ctx context.Context, claims *models.AuthClaims, systemToken string) (*models.HaciendaCredentials, error) {
+	creds, err := s.vault.Get(ctx, claims.ClientID)
+	if err == nil {
+		return creds, nil
+	}
+
+	logs.WarnContext(ctx, "Hacienda credentials not found in vault, using session credentials", map[string]interface{}{
+		"clientID": claims.ClientID,
+		"error":    err.Error(),
+	})
+
+	creds, err = s.authService.GetHaciendaCredentials(ctx, claims.NIT, systemToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.vault.Store(ctx, claims.ClientID, creds); err != nil {
+		logs.WarnContext(ctx, "Failed to store session credentials in vault", map[string]interface{}{
+			"clientID": claims.ClientID,
+			"error":    err.Error(),
+		})
+	}
+
+	return creds, nil
+}
+
 func (s *HaciendaAuthService) GetOrCreateHaciendaTokenWithCreds(ctx context.Context, systemToken string, creds models.HaciendaCredentials) (string, error) {
-	haciendaToken, err := s.getFromCache(systemToken)
+	haciendaToken, err := s.getFromCache(ctx, systemToken)
 	if err == nil {
 		return haciendaToken, nil
 	}
@@ -85,18 +121,17 @@ func (s *HaciendaAuthService) GetOrCreateHaciendaTokenWithCreds(ctx context.Cont
 	return s.createAndCacheToken(ctx, systemToken, creds)
 }
 
-func (s *HaciendaAuthService) getFromCache(systemToken string) (string, error) {
+func (s *HaciendaAuthService) getFromCache(ctx context.Context, systemToken string) (string, error) {
 	key := fmt.Sprintf("hacienda:token:%s", systemToken)
 	haciendaToken, err := s.cache.Get(key)
 	if err != nil {
-		logs.Warn("Token not found in cache or expired", map[string]interface{}{
-			"systemToken": systemToken,
-			"error":       err.Error(),
+		logs.WarnContext(ctx, "Token not found in cache or expired", map[string]interface{}{
+			"error": err.Error(),
 		})
 		return "", shared_error.NewGeneralServiceError("AuthHacienda", "GetOrCreateHaciendaToken", "token not found in cache", errPackage.ErrExpiredToken)
 	}
 
-	logs.Info("Token found in cache, returned successfully")
+	logs.InfoContext(ctx, "Token found in cache, returned successfully")
 	return haciendaToken, nil
 }
 
@@ -108,20 +143,16 @@ func (s *HaciendaAuthService) createAndCacheToken(ctx context.Context, systemTok
 	}
 
 	if err := s.cache.Set(key, []byte(token), 24*time.Hour); err != nil {
-		return "", s.cacheError(err, systemToken)
+		return "", s.cacheError(ctx, err)
 	}
 
-	logs.Info("Token authenticated and stored in cache", map[string]interface{}{
-		"key":   key,
-		"token": token,
-	})
+	logs.InfoContext(ctx, "Token authenticated and stored in cache")
 	return token, nil
 }
 
-func (s *HaciendaAuthService) cacheError(err error, systemToken string) error {
-	logs.Error("Error storing token in cache", map[string]interface{}{
-		"systemToken": systemToken,
-		"error":       err.Error(),
+func (s *HaciendaAuthService) cacheError(ctx context.Context, err error) error {
+	logs.ErrorContext(ctx, "Error storing token in cache", map[string]interface{}{
+		"error": err.Error(),
 	})
 	return shared_error.NewGeneralServiceError("AuthHacienda", "GetOrCreateHaciendaToken", "error caching Hacienda token", err)
 }
@@ -132,9 +163,8 @@ func (s *HaciendaAuthService) authenticateWithHacienda(ctx context.Context, cred
 		Pwd:  creds.Password,
 	}
 
-	logs.Info("Authenticating with Hacienda", map[string]interface{}{
+	logs.InfoContext(ctx, "Authenticating with Hacienda", map[string]interface{}{
 		"username": creds.Username,
-		"password": creds.Password,
 	})
 
 	req, err := s.createHTTPRequest(ctx, reqBody)
@@ -144,19 +174,19 @@ func (s *HaciendaAuthService) authenticateWithHacienda(ctx context.Context, cred
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", s.httpRequestError(err)
+		return "", s.httpRequestError(ctx, err)
 	}
 	defer resp.Body.Close()
 
-	if err := s.verifyAuthResponse(resp); err != nil {
+	if err := s.verifyAuthResponse(ctx, resp); err != nil {
 		return "", err
 	}
 
-	return s.decodeAuthResponse(resp.Body)
+	return s.decodeAuthResponse(ctx, resp.Body)
 }
 
-func (s *HaciendaAuthService) encodeError(err error) error {
-	logs.Error("Error encoding authentication request", map[string]interface{}{"error": err.Error()})
+func (s *HaciendaAuthService) encodeError(ctx context.Context, err error) error {
+	logs.ErrorContext(ctx, "Error encoding authentication request", map[string]interface{}{"error": err.Error()})
 	return shared_error.NewGeneralServiceError("AuthHacienda", "authenticateWithHacienda", "failed to encode authentication request", err)
 }
 
@@ -172,25 +202,25 @@ func (s *HaciendaAuthService) createHTTPRequest(ctx context.Context, creds hacie
 		strings.NewReader(formData.Encode()),
 	)
 	if err != nil {
-		logs.Error("Error creating HTTP request", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
 		return nil, shared_error.NewGeneralServiceError("AuthHacienda", "authenticateWithHacienda", "failed to create HTTP request", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("User-Agent", "HaciendaApp/1.0")
 
-	logs.Info("HTTP request created", map[string]interface{}{})
+	logs.InfoContext(ctx, "HTTP request created", map[string]interface{}{})
 	return req, nil
 }
 
-func (s *HaciendaAuthService) httpRequestError(err error) error {
-	logs.Error("Error performing HTTP request", map[string]interface{}{"error": err.Error()})
+func (s *HaciendaAuthService) httpRequestError(ctx context.Context, err error) error {
+	logs.ErrorContext(ctx, "Error performing HTTP request", map[string]interface{}{"error": err.Error()})
 	return shared_error.NewGeneralServiceError("AuthHacienda", "authenticateWithHacienda", "HTTP request failed", err)
 }
 
-func (s *HaciendaAuthService) verifyAuthResponse(resp *http.Response) error {
+func (s *HaciendaAuthService) verifyAuthResponse(ctx context.Context, resp *http.Response) error {
 	if resp.StatusCode != http.StatusOK {
-		logs.Error("Error authenticating with Hacienda", map[string]interface{}{
+		logs.ErrorContext(ctx, "Error authenticating with Hacienda", map[string]interface{}{
 			"status": resp.StatusCode,
 		})
 		return shared_error.NewGeneralServiceError("AuthHacienda", "authenticateWithHacienda", fmt.Sprintf("Hacienda signing failed with status: %d", resp.StatusCode), nil)
@@ -198,19 +228,19 @@ func (s *HaciendaAuthService) verifyAuthResponse(resp *http.Response) error {
 	return nil
 }
 
-func (s *HaciendaAuthService) decodeAuthResponse(body io.Reader) (string, error) {
+func (s *HaciendaAuthService) decodeAuthResponse(ctx context.Context, body io.Reader) (string, error) {
 	var authResp haciendaAuthResponse
 	if err := json.NewDecoder(body).Decode(&authResp); err != nil {
-		logs.Error("Error decoding authentication response", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error decoding authentication response", map[string]interface{}{"error": err.Error()})
 		return "", shared_error.NewGeneralServiceError("AuthHacienda", "authenticateWithHacienda", "failed to decode Hacienda signing response", err)
 	}
 
 	if authResp.Status != "OK" {
-		logs.Error("Error authenticating with Hacienda", map[string]interface{}{"status": authResp.Status})
+		logs.ErrorContext(ctx, "Error authenticating with Hacienda", map[string]interface{}{"status": authResp.Status})
 		return "", shared_error.NewGeneralServiceError("AuthHacienda", "authenticateWithHacienda", "Hacienda signing failed: "+authResp.Status, nil)
 	}
 
-	logs.Info("Authentication successful", map[string]interface{}{
+	logs.InfoContext(ctx, "Authentication successful", map[string]interface{}{
 		"tokenType": authResp.Body.TokenType,
 		"status":    authResp.Status,
 	})
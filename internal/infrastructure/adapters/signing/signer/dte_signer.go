@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
 	"io/ioutil"
 	"net/http"
 	"time"
 
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 )
 
 type DTESigner struct {
@@ -44,11 +46,14 @@ type SpringBootError struct {
 func NewDTESigner(clientRepo auth.AuthRepositoryPort) *DTESigner {
 	return &DTESigner{
 		clientRepo: clientRepo,
-		client:     &http.Client{Timeout: 2 * time.Second},
+		client:     &http.Client{Timeout: 2 * time.Second, Transport: tracing.NewTransport(nil)},
 	}
 }
 
-func (s *DTESigner) SignDTE(ctx context.Context, dte json.RawMessage, nit string) (string, error) {
+func (s *DTESigner) SignDTE(ctx context.Context, dte json.RawMessage, nit string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "DTESigner.SignDTE")
+	defer func() { tracing.End(span, err) }()
+
 	client, err := s.clientRepo.GetByNIT(ctx, nit)
 	if err != nil {
 		return "", shared_error.NewGeneralServiceError("DTESigner", "SignDTE", "Error getting client by NIT", err)
@@ -61,9 +66,9 @@ func (s *DTESigner) SignDTE(ctx context.Context, dte json.RawMessage, nit string
 		DteJson:     dte,
 	}
 
-	logs.Debug("Signing request", map[string]interface{}{
-		"nit":     nit,
-		"DteJson": string(dte),
+	logs.DebugContext(ctx, "Signing request", map[string]interface{}{
+		"nit":  nit,
+		"size": len(dte),
 	})
 
 	jsonData, err := json.Marshal(req)
@@ -81,8 +86,10 @@ func (s *DTESigner) SignDTE(ctx context.Context, dte json.RawMessage, nit string
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
+	start := time.Now()
 	resp, err := s.client.Do(httpReq)
 	if err != nil {
+		metrics.ObserveSignerRequest("error", time.Since(start))
 		return "", fmt.Errorf("error calling signer service: %w", err)
 	}
 	defer resp.Body.Close()
@@ -90,9 +97,16 @@ func (s *DTESigner) SignDTE(ctx context.Context, dte json.RawMessage, nit string
 	// Leer el cuerpo de la respuesta
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
+		metrics.ObserveSignerRequest("error", time.Since(start))
 		return "", fmt.Errorf("error reading response body: %w", err)
 	}
 
+	outcome := "success"
+	if resp.StatusCode != http.StatusOK {
+		outcome = "error"
+	}
+	metrics.ObserveSignerRequest(outcome, time.Since(start))
+
 	if resp.StatusCode != http.StatusOK {
 		var springError SpringBootError
 		if err := json.Unmarshal(body, &springError); err == nil {
@@ -128,10 +142,10 @@ func (s *DTESigner) SignDTE(ctx context.Context, dte json.RawMessage, nit string
 		return "", fmt.Errorf("error decoding successful response: %w, body: %s", err, string(body))
 	}
 
-	logs.Debug("Sign response", map[string]interface{}{
+	logs.DebugContext(ctx, "Sign response", map[string]interface{}{
 		"status": signResp.Body,
 	})
-	logs.Info("Document signed successfully")
+	logs.InfoContext(ctx, "Document signed successfully")
 
 	return signResp.Body, nil
 }
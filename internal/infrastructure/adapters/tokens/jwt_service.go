@@ -1,10 +1,14 @@
 package tokens
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/go-redis/redis/v8"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"time"
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
@@ -31,14 +35,25 @@ func (s *JWTService) GenerateToken(claims *models.AuthClaims, tokenLifetime time
 	now := utils.TimeNow()
 	exp := now.Add(tokenLifetime)
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	mapClaims := jwt.MapClaims{
 		"sub":        claims.ClientID,
 		"branch_sub": claims.BranchID,
 		"auth_type":  claims.AuthType,
 		"nit":        claims.NIT,
 		"exp":        exp.Unix(),
 		"iat":        now.Unix(),
-	})
+		// El ID del token evita que dos sesiones, o un access token y el que lo reemplaza al renovar la sesión,
+		// obtengan el mismo token al emitirse en el mismo segundo
+		"jti": uuid.NewString(),
+	}
+
+	// Los tokens de operador no pertenecen a ningún contribuyente, el ID del operador evita que dos operadores
+	// obtengan el mismo token al iniciar sesión en el mismo segundo
+	if claims.OperatorID != 0 {
+		mapClaims["operator_sub"] = claims.OperatorID
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
 
 	signedToken, err := token.SignedString([]byte(s.SecretKey))
 	if err != nil {
@@ -66,8 +81,8 @@ func (s *JWTService) GenerateToken(claims *models.AuthClaims, tokenLifetime time
 		)
 	}
 
-	fmt.Printf("token, %s\n", signedToken)
 	key := "token:" + signedToken
+	claims.IssuedAt = now
 	jsonClaims, err := json.Marshal(claims)
 	if err != nil {
 		logs.Error("Failed to marshal claims", map[string]interface{}{
@@ -138,12 +153,12 @@ func (s *JWTService) SaveTimestampsForContingency(issuedAt, expiresAt time.Time,
 }
 
 // ValidateToken válida un token JWT y retorna los claims si es válido.
-func (s *JWTService) ValidateToken(tokenString string) (*models.AuthClaims, error) {
+func (s *JWTService) ValidateToken(ctx context.Context, tokenString string) (*models.AuthClaims, error) {
 	key := "token:" + tokenString
 
 	claims, err := s.cacheService.Get(key)
 	if err != nil {
-		logs.Error("Failed to retrieve token from cacheService", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to retrieve token from cacheService", map[string]interface{}{
 			"token": tokenString,
 			"error": err.Error(),
 		})
@@ -157,7 +172,7 @@ func (s *JWTService) ValidateToken(tokenString string) (*models.AuthClaims, erro
 
 	var authClaims models.AuthClaims
 	if err = json.Unmarshal([]byte(claims), &authClaims); err != nil {
-		logs.Error("Failed to unmarshal claims", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to unmarshal claims", map[string]interface{}{
 			"token": tokenString,
 			"error": err.Error(),
 		})
@@ -168,11 +183,19 @@ func (s *JWTService) ValidateToken(tokenString string) (*models.AuthClaims, erro
 		)
 	}
 
+	if err = s.checkBranchRevocation(ctx, &authClaims); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkOperatorRevocation(ctx, &authClaims); err != nil {
+		return nil, err
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return []byte(s.SecretKey), nil
 	})
 	if err != nil {
-		logs.Error("Failed to parse token", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to parse token", map[string]interface{}{
 			"token": tokenString,
 			"error": err.Error(),
 		})
@@ -184,7 +207,7 @@ func (s *JWTService) ValidateToken(tokenString string) (*models.AuthClaims, erro
 	}
 
 	if !token.Valid {
-		logs.Error("Invalid token", map[string]interface{}{
+		logs.ErrorContext(ctx, "Invalid token", map[string]interface{}{
 			"token": tokenString,
 		})
 		return nil, shared_error.NewFormattedGeneralServiceError(
@@ -194,7 +217,7 @@ func (s *JWTService) ValidateToken(tokenString string) (*models.AuthClaims, erro
 		)
 	}
 
-	logs.Info("Token validated successfully", map[string]interface{}{
+	logs.InfoContext(ctx, "Token validated successfully", map[string]interface{}{
 		"token": tokenString,
 	})
 
@@ -224,6 +247,214 @@ func (s *JWTService) RevokeToken(token string) error {
 	return nil
 }
 
+// RevokeBranchTokens revoca todos los tokens emitidos hasta ahora para una sucursal. El ttl debe cubrir la vida
+// máxima de los tokens de la sucursal, después de ese tiempo ningún token anterior a la revocación sigue vigente.
+func (s *JWTService) RevokeBranchTokens(branchID uint, ttl time.Duration) error {
+	revokedAt := utils.TimeNow().Format(time.RFC3339Nano)
+
+	if err := s.cacheService.Set(branchRevocationKey(branchID), []byte(revokedAt), ttl); err != nil {
+		logs.Error("Failed to revoke branch tokens", map[string]interface{}{
+			"branchID": branchID,
+			"error":    err.Error(),
+		})
+		return shared_error.NewGeneralServiceError(
+			"JWTService",
+			"RevokeBranchTokens",
+			"failed to revoke branch tokens",
+			err,
+		)
+	}
+
+	logs.Info("Branch tokens revoked successfully", map[string]interface{}{
+		"branchID": branchID,
+	})
+
+	return nil
+}
+
+// RevokeAPIKeyTokens revoca todos los tokens emitidos hasta ahora con una llave de acceso adicional. El ttl debe cubrir
+// la vida máxima de los tokens de la sucursal a la que pertenece la llave.
+func (s *JWTService) RevokeAPIKeyTokens(keyID uint, ttl time.Duration) error {
+	revokedAt := utils.TimeNow().Format(time.RFC3339Nano)
+
+	if err := s.cacheService.Set(apiKeyRevocationKey(keyID), []byte(revokedAt), ttl); err != nil {
+		logs.Error("Failed to revoke API key tokens", map[string]interface{}{
+			"keyID": keyID,
+			"error": err.Error(),
+		})
+		return shared_error.NewGeneralServiceError(
+			"JWTService",
+			"RevokeAPIKeyTokens",
+			"failed to revoke API key tokens",
+			err,
+		)
+	}
+
+	logs.Info("API key tokens revoked successfully", map[string]interface{}{
+		"keyID": keyID,
+	})
+
+	return nil
+}
+
+// RevokeOperatorTokens revoca todos los tokens emitidos hasta ahora para un operador, tanto su token de operador como los
+// tokens que emitió para actuar como sus sucursales. El ttl debe cubrir la vida de los tokens de operador.
+func (s *JWTService) RevokeOperatorTokens(operatorID uint, ttl time.Duration) error {
+	revokedAt := utils.TimeNow().Format(time.RFC3339Nano)
+
+	if err := s.cacheService.Set(operatorRevocationKey(operatorID), []byte(revokedAt), ttl); err != nil {
+		logs.Error("Failed to revoke operator tokens", map[string]interface{}{
+			"operatorID": operatorID,
+			"error":      err.Error(),
+		})
+		return shared_error.NewGeneralServiceError(
+			"JWTService",
+			"RevokeOperatorTokens",
+			"failed to revoke operator tokens",
+			err,
+		)
+	}
+
+	logs.Info("Operator tokens revoked successfully", map[string]interface{}{
+		"operatorID": operatorID,
+	})
+
+	return nil
+}
+
+// RevokeOperatorGrantTokens revoca los tokens que un operador emitió hasta ahora para las sucursales de un acceso, todas
+// las sucursales del contribuyente si el acceso no indica la sucursal. El ttl debe cubrir la vida de los tokens de
+// operador.
+func (s *JWTService) RevokeOperatorGrantTokens(operatorID, userID uint, branchID *uint, ttl time.Duration) error {
+	revokedAt := utils.TimeNow().Format(time.RFC3339Nano)
+
+	key := operatorUserRevocationKey(operatorID, userID)
+	if branchID != nil {
+		key = operatorBranchRevocationKey(operatorID, *branchID)
+	}
+
+	if err := s.cacheService.Set(key, []byte(revokedAt), ttl); err != nil {
+		logs.Error("Failed to revoke operator grant tokens", map[string]interface{}{
+			"operatorID": operatorID,
+			"userID":     userID,
+			"error":      err.Error(),
+		})
+		return shared_error.NewGeneralServiceError(
+			"JWTService",
+			"RevokeOperatorGrantTokens",
+			"failed to revoke operator grant tokens",
+			err,
+		)
+	}
+
+	logs.Info("Operator grant tokens revoked successfully", map[string]interface{}{
+		"operatorID": operatorID,
+		"userID":     userID,
+	})
+
+	return nil
+}
+
+// checkBranchRevocation rechaza los tokens emitidos antes de la última revocación de su sucursal o de su llave de acceso
+func (s *JWTService) checkBranchRevocation(ctx context.Context, claims *models.AuthClaims) error {
+	// 1. Verificar la revocación de la sucursal
+	if err := s.checkRevocation(ctx, branchRevocationKey(claims.BranchID), claims); err != nil {
+		return err
+	}
+
+	// 2. Verificar la revocación de la llave de acceso adicional, si el token fue emitido con una
+	if claims.KeyID != 0 {
+		return s.checkRevocation(ctx, apiKeyRevocationKey(claims.KeyID), claims)
+	}
+
+	return nil
+}
+
+// checkOperatorRevocation rechaza los tokens emitidos para un operador antes de su desactivación y los tokens que emitió
+// para una sucursal antes de la revocación del acceso a la sucursal o a su contribuyente
+func (s *JWTService) checkOperatorRevocation(ctx context.Context, claims *models.AuthClaims) error {
+	if claims.OperatorID == 0 {
+		return nil
+	}
+
+	// 1. Verificar la revocación del operador
+	if err := s.checkRevocation(ctx, operatorRevocationKey(claims.OperatorID), claims); err != nil {
+		return err
+	}
+
+	// 2. Verificar la revocación del acceso, solo los tokens emitidos al cambiar de emisor pertenecen a una sucursal
+	if claims.BranchID == 0 {
+		return nil
+	}
+	if err := s.checkRevocation(ctx, operatorUserRevocationKey(claims.OperatorID, claims.ClientID), claims); err != nil {
+		return err
+	}
+
+	return s.checkRevocation(ctx, operatorBranchRevocationKey(claims.OperatorID, claims.BranchID), claims)
+}
+
+// checkRevocation rechaza el token si fue emitido antes de la fecha de revocación almacenada en la llave indicada
+func (s *JWTService) checkRevocation(ctx context.Context, key string, claims *models.AuthClaims) error {
+	// 1. Obtener la fecha de revocación, si no existe el token sigue vigente
+	value, err := s.cacheService.GetRedisClient().Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to get token revocation", map[string]interface{}{
+			"branchID":   claims.BranchID,
+			"keyID":      claims.KeyID,
+			"operatorID": claims.OperatorID,
+			"error":      err.Error(),
+		})
+		return shared_error.NewFormattedGeneralServiceError("JWTService", "ValidateToken", "AuthServiceUnavailable")
+	}
+
+	revokedAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return shared_error.NewFormattedGeneralServiceError("JWTService", "ValidateToken", "AuthServiceUnavailable")
+	}
+
+	// 2. Rechazar los tokens emitidos antes de la revocación, los tokens sin fecha de emisión se consideran anteriores
+	if !claims.IssuedAt.After(revokedAt) {
+		logs.WarnContext(ctx, "Token was revoked by credentials change", map[string]interface{}{
+			"branchID":   claims.BranchID,
+			"keyID":      claims.KeyID,
+			"operatorID": claims.OperatorID,
+		})
+		return shared_error.NewFormattedGeneralServiceError("JWTService", "ValidateToken", "Unauthorized")
+	}
+
+	return nil
+}
+
+// branchRevocationKey retorna la llave de la fecha de revocación de los tokens de una sucursal
+func branchRevocationKey(branchID uint) string {
+	return fmt.Sprintf("token:revoked:branch:%d", branchID)
+}
+
+// apiKeyRevocationKey retorna la llave de la fecha de revocación de los tokens de una llave de acceso adicional
+func apiKeyRevocationKey(keyID uint) string {
+	return fmt.Sprintf("token:revoked:key:%d", keyID)
+}
+
+// operatorRevocationKey retorna la llave de la fecha de revocación de los tokens de un operador
+func operatorRevocationKey(operatorID uint) string {
+	return fmt.Sprintf("token:revoked:operator:%d", operatorID)
+}
+
+// operatorUserRevocationKey retorna la llave de la fecha de revocación de los tokens que un operador emitió para las
+// sucursales de un contribuyente
+func operatorUserRevocationKey(operatorID, userID uint) string {
+	return fmt.Sprintf("token:revoked:operator:%d:user:%d", operatorID, userID)
+}
+
+// operatorBranchRevocationKey retorna la llave de la fecha de revocación de los tokens que un operador emitió para una
+// sucursal
+func operatorBranchRevocationKey(operatorID, branchID uint) string {
+	return fmt.Sprintf("token:revoked:operator:%d:branch:%d", operatorID, branchID)
+}
+
 // GetSecretKey retorna la clave secreta para firmar los tokens.
 func (s *JWTService) GetSecretKey() string {
 	return s.SecretKey
@@ -0,0 +1,146 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+)
+
+// GenerateRefreshToken genera un refresh token opaco para la sesión indicada. La familia de la sesión apunta siempre al
+// último access token emitido y vive tanto como su último refresh token.
+func (s *JWTService) GenerateRefreshToken(ctx context.Context, session *models.RefreshSession, ttl time.Duration) (string, error) {
+	// 1. Generar el refresh token, en Redis solo se almacena su hash
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", shared_error.NewGeneralServiceError("JWTService", "GenerateRefreshToken", "failed to generate refresh token", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", shared_error.NewGeneralServiceError("JWTService", "GenerateRefreshToken", "failed to marshal refresh session", err)
+	}
+
+	// 2. Almacenar la sesión y actualizar la familia con el último access token
+	_, err = s.cacheService.GetRedisClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, refreshTokenKey(refreshToken), data, ttl)
+		pipe.Set(ctx, refreshFamilyKey(session.FamilyID), session.AccessToken, ttl)
+		return nil
+	})
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to store refresh token", map[string]interface{}{
+			"clientID": session.Claims.ClientID,
+			"error":    err.Error(),
+		})
+		return "", shared_error.NewGeneralServiceError("JWTService", "GenerateRefreshToken", "failed to store refresh token", err)
+	}
+
+	return refreshToken, nil
+}
+
+// ConsumeRefreshToken obtiene la sesión de un refresh token y lo marca como utilizado. Si el refresh token ya había sido
+// utilizado la sesión se retorna con Reused en verdadero para que se revoque su familia completa.
+func (s *JWTService) ConsumeRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshSession, error) {
+	client := s.cacheService.GetRedisClient()
+	key := refreshTokenKey(refreshToken)
+
+	// 1. Obtener la sesión del refresh token
+	data, err := client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "InvalidRefreshToken")
+	}
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to get refresh token", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "AuthServiceUnavailable")
+	}
+
+	var session models.RefreshSession
+	if err = json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "AuthServiceUnavailable")
+	}
+
+	// 2. La familia deja de existir al cerrar sesión o al detectar la reutilización de un refresh token
+	if exists, err := client.Exists(ctx, refreshFamilyKey(session.FamilyID)).Result(); err != nil || exists == 0 {
+		return nil, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "InvalidRefreshToken")
+	}
+
+	// 3. Rechazar las sesiones revocadas por cambios en las credenciales de la sucursal o de la llave
+	if err = s.checkBranchRevocation(ctx, &session.Claims); err != nil {
+		return nil, err
+	}
+
+	// 4. Marcar el refresh token como utilizado, la marca vive tanto como el refresh token
+	ttl, err := client.PTTL(ctx, key).Result()
+	if err != nil || ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	first, err := client.SetNX(ctx, refreshUsedKey(refreshToken), 1, ttl).Result()
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to mark refresh token as used", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "AuthServiceUnavailable")
+	}
+	session.Reused = !first
+
+	return &session, nil
+}
+
+// RevokeRefreshFamily elimina una familia de refresh tokens, ninguno de sus refresh tokens puede volver a utilizarse.
+// Retorna el último access token emitido para la familia o una cadena vacía si la familia ya no existía.
+func (s *JWTService) RevokeRefreshFamily(ctx context.Context, familyID string) (string, error) {
+	client := s.cacheService.GetRedisClient()
+
+	accessToken, err := client.GetDel(ctx, refreshFamilyKey(familyID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to revoke refresh token family", map[string]interface{}{
+			"familyID": familyID,
+			"error":    err.Error(),
+		})
+		return "", shared_error.NewGeneralServiceError("JWTService", "RevokeRefreshFamily", "failed to revoke refresh token family", err)
+	}
+
+	logs.InfoContext(ctx, "Refresh token family revoked successfully", map[string]interface{}{
+		"familyID": familyID,
+	})
+
+	return accessToken, nil
+}
+
+// refreshTokenKey retorna la llave de la sesión de un refresh token, se utiliza su hash para no almacenarlo en claro
+func refreshTokenKey(refreshToken string) string {
+	return "token:refresh:" + hashRefreshToken(refreshToken)
+}
+
+// refreshUsedKey retorna la llave que marca un refresh token como utilizado
+func refreshUsedKey(refreshToken string) string {
+	return "token:refresh:used:" + hashRefreshToken(refreshToken)
+}
+
+// refreshFamilyKey retorna la llave de una familia de refresh tokens
+func refreshFamilyKey(familyID string) string {
+	return fmt.Sprintf("token:refresh:family:%s", familyID)
+}
+
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
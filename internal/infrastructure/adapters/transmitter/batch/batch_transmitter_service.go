@@ -8,9 +8,15 @@ import (
 	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	"github.com/MarlonG1/api-facturacion-sv/config/drivers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity"
+	activityModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
 	batchPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter"
 	ports2 "github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
 	"io"
 	"net"
 	"net/http"
@@ -23,9 +29,11 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/circuit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter/hacienda_error"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 	"github.com/google/uuid"
 )
@@ -40,6 +48,10 @@ type BatchTransmitterService struct {
 	httpClient      *http.Client
 	circuitBreaker  *circuit.CircuitBreaker
 	connection      *drivers.DbConnection
+	delivery        delivery.DeliveryManager
+	dteManager      dte_documents.DTEManager
+	webhooks        webhook.WebhookManager
+	activity        activity.ActivityManager
 }
 
 // NewBatchTransmitterService constructor para BatchTransmitterService
@@ -50,27 +62,51 @@ func NewBatchTransmitterService(
 	config *models.TransmissionConfig,
 	timeProvider ports2.TimeProvider,
 	connection *drivers.DbConnection,
+	deliveryManager delivery.DeliveryManager,
+	dteManager dte_documents.DTEManager,
+	webhookManager webhook.WebhookManager,
+	activityManager activity.ActivityManager,
 ) batchPorts.BatchTransmitterPort {
-	return &BatchTransmitterService{
+	service := &BatchTransmitterService{
 		haciendaAuth:    haciendaAuth,
 		signer:          signer,
 		contingencyRepo: contingencyRepo,
 		config:          config,
 		timeProvider:    timeProvider,
 		connection:      connection,
+		delivery:        deliveryManager,
+		dteManager:      dteManager,
+		webhooks:        webhookManager,
+		activity:        activityManager,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
+			Transport: tracing.NewTransport(&http.Transport{
 				MaxIdleConns:       100,
 				IdleConnTimeout:    90 * time.Second,
 				DisableCompression: true,
-			},
+			}),
 		},
 		circuitBreaker: circuit.NewCircuitBreaker(
 			3,
 			5*time.Minute,
 		),
 	}
+
+	// Publicar los cambios de estado del circuit breaker en el stream de actividad de todas las sucursales
+	service.circuitBreaker.OnStateChange(service.publishCircuitState)
+
+	return service
+}
+
+// publishCircuitState publica un cambio de estado del circuit breaker en el stream de actividad
+func (s *BatchTransmitterService) publishCircuitState(from, to constants.State) {
+	metrics.SetCircuitBreakerState(to)
+
+	if s.activity == nil {
+		return
+	}
+
+	s.activity.Publish(context.Background(), activityModels.NewCircuitBreakerEvent(from, to))
 }
 
 // GetDTEVersion determina la versión según el tipo de DTE
@@ -112,14 +148,14 @@ func (s *BatchTransmitterService) TransmitBatch(
 
 	response, err := s.sendBatchWithRetry(ctx, batch, haciendaToken)
 	if err != nil {
-		logs.Error("Failed to send batch", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to send batch", map[string]interface{}{
 			"error":   err.Error(),
 			"batchId": batchID,
 		})
 		return nil, "", err
 	}
 
-	logs.Info("Batch sent successfully", map[string]interface{}{
+	logs.InfoContext(ctx, "Batch sent successfully", map[string]interface{}{
 		"batchId": response.BatchCode,
 		"status":  response.Status,
 		"msg":     response.Description,
@@ -145,7 +181,7 @@ func (s *BatchTransmitterService) getHaciendaTokenWithRetry(
 			return haciendaToken, nil
 		}
 
-		if !s.shouldRetry(err) {
+		if !s.shouldRetry(ctx, err) {
 			return "", err
 		}
 
@@ -171,7 +207,7 @@ func (s *BatchTransmitterService) sendBatchWithRetry(
 			return response, nil
 		}
 
-		if !s.shouldRetry(err) {
+		if !s.shouldRetry(ctx, err) {
 			return nil, err
 		}
 
@@ -188,7 +224,7 @@ func (s *BatchTransmitterService) transmitToHacienda(
 	token string,
 ) (*models.BatchResponse, error) {
 	if !s.circuitBreaker.AllowRequest() {
-		logs.Warn("Circuit breaker preventing request to Hacienda", map[string]interface{}{
+		logs.WarnContext(ctx, "Circuit breaker preventing request to Hacienda", map[string]interface{}{
 			"state": s.circuitBreaker.GetState(),
 		})
 		return nil, shared_error.NewGeneralServiceError(
@@ -204,7 +240,8 @@ func (s *BatchTransmitterService) transmitToHacienda(
 		return nil, shared_error.NewGeneralServiceError("BatchTransmitterService", "transmitToHacienda", "failed to marshal request", err)
 	}
 
-	logs.Info("Sending batch to Hacienda", map[string]interface{}{
+	metrics.ObserveBatchSize(len(batch.Documents))
+	logs.InfoContext(ctx, "Sending batch to Hacienda", map[string]interface{}{
 		"batchId": batch.SendID,
 		"ambient": batch.Ambient,
 		"docs":    len(batch.Documents),
@@ -222,7 +259,7 @@ func (s *BatchTransmitterService) transmitToHacienda(
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		s.circuitBreaker.RecordFailure()
-		logs.Error("Request to Hacienda failed", map[string]interface{}{
+		logs.ErrorContext(ctx, "Request to Hacienda failed", map[string]interface{}{
 			"error":        err.Error(),
 			"failureCount": s.circuitBreaker.GetFailureCount(),
 		})
@@ -267,7 +304,7 @@ func (s *BatchTransmitterService) VerifyContingencyBatchStatus(
 		case <-ticker.C:
 			status, isProcessed, err := s.checkBatchStatus(ctx, mhBatchID, token)
 			if err != nil {
-				logs.Error("Failed to check batch status, verify", map[string]interface{}{
+				logs.ErrorContext(ctx, "Failed to check batch status, verify", map[string]interface{}{
 					"error":   err.Error(),
 					"batchID": mhBatchID,
 				})
@@ -275,7 +312,7 @@ func (s *BatchTransmitterService) VerifyContingencyBatchStatus(
 			}
 
 			if !isProcessed {
-				logs.Info("Batch still processing", map[string]interface{}{
+				logs.InfoContext(ctx, "Batch still processing", map[string]interface{}{
 					"batchID": mhBatchID,
 				})
 
@@ -299,7 +336,7 @@ func (s *BatchTransmitterService) VerifyContingencyBatchStatus(
 				processedStamps = make(map[string]string)
 				for _, processed := range status.Processed {
 					if doc, exists := docsMap[processed.GenerationCode]; exists {
-						logs.Info("Document processed", map[string]interface{}{
+						logs.InfoContext(ctx, "Document processed", map[string]interface{}{
 							"code":            processed.MessageCode,
 							"message":         processed.DescriptionMessage,
 							"observations":    processed.Observations,
@@ -309,20 +346,38 @@ func (s *BatchTransmitterService) VerifyContingencyBatchStatus(
 						processedStamps[doc.ID] = processed.ReceptionStamp
 						proccesedObservations = append(proccesedObservations, processed.DescriptionMessage)
 						processedIDs = append(processedIDs, doc.ID)
+						s.saveMHResponse(ctx, doc.DocumentID, processed)
+						metrics.RecordHaciendaResponse(metrics.HaciendaSourceBatch, processed.Status, processed.MessageCode)
 					}
 				}
 
 				if err := s.contingencyRepo.UpdateBatch(ctx, processedIDs, proccesedObservations, processedStamps, batchID, mhBatchID, constants.DocumentReceived); err != nil {
-					logs.Error("Failed to update processed documents", map[string]interface{}{
+					logs.ErrorContext(ctx, "Failed to update processed documents", map[string]interface{}{
 						"error":   err.Error(),
 						"batchID": batchID,
 					})
 					return shared_error.NewGeneralServiceError("BatchTransmitterService", "VerifyContingencyBatchStatus", "failed to update processed documents", err)
 				}
 
-				logs.Info("Processed documents updated", map[string]interface{}{
+				logs.InfoContext(ctx, "Processed documents updated", map[string]interface{}{
 					"batchID": batchID,
 				})
+
+				// Programar el envío de los documentos procesados a sus receptores
+				if s.delivery != nil {
+					for _, processed := range status.Processed {
+						if doc, exists := docsMap[processed.GenerationCode]; exists {
+							s.delivery.DeliverDTEAsync(ctx, doc.BranchID, doc.DocumentID)
+						}
+					}
+				}
+
+				// Notificar la recepción de los documentos a las suscripciones de webhooks
+				for _, processed := range status.Processed {
+					if doc, exists := docsMap[processed.GenerationCode]; exists {
+						s.emitDocumentEvent(ctx, webhookModels.EventDTEReceived, batchID, doc, processed)
+					}
+				}
 			}
 
 			// Procesar documentos rechazados
@@ -331,7 +386,7 @@ func (s *BatchTransmitterService) VerifyContingencyBatchStatus(
 				var rejectedObservations []string
 				for _, rejected := range status.Rejected {
 					if doc, exists := docsMap[rejected.GenerationCode]; exists {
-						logs.Info("Document rejected", map[string]interface{}{
+						logs.InfoContext(ctx, "Document rejected", map[string]interface{}{
 							"code":         rejected.MessageCode,
 							"message":      rejected.DescriptionMessage,
 							"observations": rejected.Observations,
@@ -339,33 +394,102 @@ func (s *BatchTransmitterService) VerifyContingencyBatchStatus(
 						})
 						rejectedIDs = append(rejectedIDs, doc.ID)
 						rejectedObservations = append(rejectedObservations, rejected.DescriptionMessage)
+						s.saveMHResponse(ctx, doc.DocumentID, rejected)
+						metrics.RecordHaciendaResponse(metrics.HaciendaSourceBatch, rejected.Status, rejected.MessageCode)
 					}
 				}
 
 				if err := s.contingencyRepo.UpdateBatch(ctx, rejectedIDs, rejectedObservations, nil, batchID, mhBatchID, constants.DocumentRejected); err != nil {
-					logs.Error("Failed to update rejected documents", map[string]interface{}{
+					logs.ErrorContext(ctx, "Failed to update rejected documents", map[string]interface{}{
 						"error":   err.Error(),
 						"batchID": batchID,
 					})
 				}
 
-				logs.Info("Rejected documents updated", map[string]interface{}{
+				logs.InfoContext(ctx, "Rejected documents updated", map[string]interface{}{
 					"batchID": batchID,
 				})
+
+				// Notificar el rechazo de los documentos a las suscripciones de webhooks
+				for _, rejected := range status.Rejected {
+					if doc, exists := docsMap[rejected.GenerationCode]; exists {
+						s.emitDocumentEvent(ctx, webhookModels.EventDTERejected, batchID, doc, rejected)
+					}
+				}
 			}
 
-			logs.Info("Batch status verified", map[string]interface{}{
+			logs.InfoContext(ctx, "Batch status verified", map[string]interface{}{
 				"batchID":        batchID,
 				"totalProcessed": len(status.Processed),
 				"totalRejected":  len(status.Rejected),
 			})
 
+			// Notificar el resultado del lote a las suscripciones de webhooks
+			s.emitEvent(ctx, webhookModels.EventBatchCompleted, branchID, map[string]interface{}{
+				"batch_id":        batchID,
+				"batch_code":      mhBatchID,
+				"total_documents": len(docsMap),
+				"total_processed": len(status.Processed),
+				"total_rejected":  len(status.Rejected),
+			})
+
 			return nil
 		}
 	}
 }
 
+// emitDocumentEvent notifica el resultado de Hacienda para un documento del lote a las suscripciones de webhooks
+func (s *BatchTransmitterService) emitDocumentEvent(ctx context.Context, eventType, batchID string, doc dte.ContingencyDocument, response models.HaciendaResponse) {
+	data := map[string]interface{}{
+		"generation_code": doc.DocumentID,
+		"batch_id":        batchID,
+		"code":            response.MessageCode,
+		"description":     response.DescriptionMessage,
+		"observations":    response.Observations,
+	}
+	if response.ReceptionStamp != "" {
+		data["reception_stamp"] = response.ReceptionStamp
+	}
+	if doc.Document != nil {
+		data["dte_type"] = doc.Document.DTEType
+		data["control_number"] = doc.Document.ControlNumber
+	}
+
+	s.emitEvent(ctx, eventType, doc.BranchID, data)
+}
+
+// emitEvent notifica un evento a las suscripciones de webhooks del contribuyente propietario de la sucursal
+func (s *BatchTransmitterService) emitEvent(ctx context.Context, eventType string, branchID uint, data map[string]interface{}) {
+	if s.webhooks == nil {
+		return
+	}
+
+	s.webhooks.Emit(ctx, &webhookModels.Event{
+		Type:     eventType,
+		BranchID: branchID,
+		Data:     data,
+	})
+}
+
 // checkBatchStatus verifica el estado de un lote en Hacienda
+// saveMHResponse guarda la respuesta de Hacienda de un documento del lote junto al JWS firmado que se transmitió
+func (s *BatchTransmitterService) saveMHResponse(ctx context.Context, generationCode string, response models.HaciendaResponse) {
+	if s.dteManager == nil {
+		return
+	}
+
+	content, err := json.Marshal(response)
+	if err == nil {
+		err = s.dteManager.SaveArtifacts(ctx, generationCode, nil, utils.ToStringPointer(string(content)))
+	}
+	if err != nil {
+		logs.WarnContext(ctx, "Failed to save Hacienda response of contingency document", map[string]interface{}{
+			"error":          err.Error(),
+			"generationCode": generationCode,
+		})
+	}
+}
+
 func (s *BatchTransmitterService) checkBatchStatus(ctx context.Context, batchID string, haciendaToken string) (*models.ConsultBatchResponse, bool, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -374,7 +498,7 @@ func (s *BatchTransmitterService) checkBatchStatus(ctx context.Context, batchID
 		nil,
 	)
 	if err != nil {
-		logs.Error("Failed to create batch status request", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to create batch status request", map[string]interface{}{
 			"error":   err.Error(),
 			"batchID": batchID,
 		})
@@ -384,7 +508,7 @@ func (s *BatchTransmitterService) checkBatchStatus(ctx context.Context, batchID
 	req.Header.Set("Authorization", haciendaToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	logs.Info("Checking batch status", map[string]interface{}{
+	logs.InfoContext(ctx, "Checking batch status", map[string]interface{}{
 		"url":     req.URL.String(),
 		"method":  req.Method,
 		"batchID": batchID,
@@ -392,7 +516,7 @@ func (s *BatchTransmitterService) checkBatchStatus(ctx context.Context, batchID
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logs.Error("Failed to check batch status inner", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to check batch status inner", map[string]interface{}{
 			"error":   err.Error(),
 			"batchID": batchID,
 		})
@@ -406,14 +530,14 @@ func (s *BatchTransmitterService) checkBatchStatus(ctx context.Context, batchID
 			// Si no hay contenido, el lote aun no ha sido procesado
 			return nil, false, nil
 		}
-		logs.Error("Failed to decode batch response", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to decode batch response", map[string]interface{}{
 			"error":   err.Error(),
 			"batchID": batchID,
 		})
 		return nil, false, err
 	}
 
-	logs.Info("Batch status response", map[string]interface{}{
+	logs.InfoContext(ctx, "Batch status response", map[string]interface{}{
 		"Processed": len(batchResp.Processed),
 		"Rejected":  len(batchResp.Rejected),
 	})
@@ -421,11 +545,11 @@ func (s *BatchTransmitterService) checkBatchStatus(ctx context.Context, batchID
 }
 
 // shouldRetry determina si se debe reintentar una operación
-func (s *BatchTransmitterService) shouldRetry(err error) bool {
+func (s *BatchTransmitterService) shouldRetry(ctx context.Context, err error) bool {
 	// Errores de red/conexión - siempre reintentar
 	var netErr *net.OpError
 	if errors.As(err, &netErr) {
-		logs.Info("Network error detected, will retry", map[string]interface{}{
+		logs.InfoContext(ctx, "Network error detected, will retry", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return true
@@ -434,7 +558,7 @@ func (s *BatchTransmitterService) shouldRetry(err error) bool {
 	var httpErr *hacienda_error.HTTPResponseError
 	if errors.As(err, &httpErr) {
 		if httpErr.StatusCode >= 500 && httpErr.StatusCode <= 599 {
-			logs.Info("Server error detected, will retry", map[string]interface{}{
+			logs.InfoContext(ctx, "Server error detected, will retry", map[string]interface{}{
 				"statusCode": httpErr.StatusCode,
 			})
 			return true
@@ -446,14 +570,14 @@ func (s *BatchTransmitterService) shouldRetry(err error) bool {
 			http.StatusBadGateway,         // 502
 			http.StatusServiceUnavailable, // 503
 			http.StatusGatewayTimeout:     // 504
-			logs.Info("Retryable HTTP error detected", map[string]interface{}{
+			logs.InfoContext(ctx, "Retryable HTTP error detected", map[string]interface{}{
 				"statusCode": httpErr.StatusCode,
 			})
 			return true
 		}
 
 		// No reintentar otros codigos HTTP
-		logs.Info("Non-retryable HTTP error", map[string]interface{}{
+		logs.InfoContext(ctx, "Non-retryable HTTP error", map[string]interface{}{
 			"statusCode": httpErr.StatusCode,
 		})
 		return false
@@ -464,7 +588,7 @@ func (s *BatchTransmitterService) shouldRetry(err error) bool {
 		// No reintentar errores de validación o autorización
 		if strings.Contains(strings.ToLower(haciendaErr.Description), "validaci") ||
 			strings.Contains(strings.ToLower(haciendaErr.Description), "autoriza") {
-			logs.Info("Non-retryable Hacienda error", map[string]interface{}{
+			logs.InfoContext(ctx, "Non-retryable Hacienda error", map[string]interface{}{
 				"code":    haciendaErr.Code,
 				"message": haciendaErr.Description,
 			})
@@ -472,7 +596,7 @@ func (s *BatchTransmitterService) shouldRetry(err error) bool {
 		}
 
 		// Reintentar otros errores de Hacienda
-		logs.Info("Retryable Hacienda error", map[string]interface{}{
+		logs.InfoContext(ctx, "Retryable Hacienda error", map[string]interface{}{
 			"code":    haciendaErr.Code,
 			"message": haciendaErr.Description,
 		})
@@ -482,7 +606,7 @@ func (s *BatchTransmitterService) shouldRetry(err error) bool {
 	// Errores de contexto
 	if errors.Is(err, context.DeadlineExceeded) ||
 		errors.Is(err, context.Canceled) {
-		logs.Info("Context error detected, will retry", map[string]interface{}{
+		logs.InfoContext(ctx, "Context error detected, will retry", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return true
@@ -490,7 +614,7 @@ func (s *BatchTransmitterService) shouldRetry(err error) bool {
 
 	// Para errores no clasificados, no son retryables
 	// pero se loguea para análisis
-	logs.Warn("Unclassified error, defaulting to retry", map[string]interface{}{
+	logs.WarnContext(ctx, "Unclassified error, defaulting to retry", map[string]interface{}{
 		"error": err.Error(),
 	})
 	return true
@@ -11,9 +11,11 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	models2 "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 	ports2 "github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter/hacienda_error"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter/processors"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 	"io/ioutil"
 	"net/http"
@@ -39,7 +41,8 @@ func NewMHTransmitter(haciendaAuth ports.HaciendaAuthManager, failedSequenceRepo
 		haciendaAuth:       haciendaAuth,
 		failedSequenceRepo: failedSequenceRepo,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
 		processors: make(map[string]DocumentProcessor),
 	}
@@ -53,7 +56,7 @@ func NewMHTransmitter(haciendaAuth ports.HaciendaAuthManager, failedSequenceRepo
 func (t *MHTransmitter) Transmit(ctx context.Context, document interface{}, signedDoc string, systemToken string) (*models2.TransmitResult, error) {
 	// Forzar modo de contingencia si está activado
 	if config.Server.ForceContingency && config.Server.AmbientCode == "00" {
-		logs.Info("Forcing contingency mode - simulating service unavailable")
+		logs.InfoContext(ctx, "Forcing contingency mode - simulating service unavailable")
 		return nil, &hacienda_error.HTTPResponseError{
 			StatusCode: http.StatusServiceUnavailable,
 			Body:       []byte("Forced contingency - service unavailable"),
@@ -62,15 +65,15 @@ func (t *MHTransmitter) Transmit(ctx context.Context, document interface{}, sign
 		}
 	}
 
-	processor := t.getProcessor(document)
+	processor := t.getProcessor(ctx, document)
 	if processor == nil {
 		return nil, fmt.Errorf("no processor found for document type: %T", document)
 	}
 
 	// Preparar request
-	req, err := processor.ProcessRequest(signedDoc, document)
+	req, err := processor.ProcessRequest(ctx, signedDoc, document)
 	if err != nil {
-		logs.Error("Failed to process request", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to process request", map[string]interface{}{
 			"error": err.Error(),
 			"type":  fmt.Sprintf("%T", signedDoc),
 		})
@@ -85,11 +88,17 @@ func (t *MHTransmitter) Transmit(ctx context.Context, document interface{}, sign
 	}
 
 	// Procesar respuesta
-	return processor.ProcessResponse(resp)
+	result, err := processor.ProcessResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	result.SignedDocument = signedDoc
+	result.Response = resp
+	return result, nil
 }
 
 func (t *MHTransmitter) CheckDocumentStatus(ctx context.Context, document interface{}, nit string) (*models2.TransmitResult, error) {
-	_, dteType, generationCode, _, err := processors.GetDocumentRequestData(document)
+	_, dteType, generationCode, _, err := processors.GetDocumentRequestData(ctx, document)
 
 	haciendaReqBody := HaciendaConsultRequest{
 		IssuerNIT:      nit,
@@ -101,7 +110,7 @@ func (t *MHTransmitter) CheckDocumentStatus(ctx context.Context, document interf
 
 	req, err := http.NewRequestWithContext(ctx, "POST", config.MHPaths.ReceptionConsultURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		logs.Info("Failed to create request", map[string]interface{}{"error": err.Error()})
+		logs.InfoContext(ctx, "Failed to create request", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
@@ -110,7 +119,7 @@ func (t *MHTransmitter) CheckDocumentStatus(ctx context.Context, document interf
 
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
-		logs.Error("Failed to check document status", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to check document status", map[string]interface{}{
 			"error": err.Error(),
 			"code":  generationCode,
 		})
@@ -119,7 +128,7 @@ func (t *MHTransmitter) CheckDocumentStatus(ctx context.Context, document interf
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusAccepted {
-		logs.Error("Failed to check document status", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to check document status", map[string]interface{}{
 			"status": resp.Status,
 		})
 		return nil, fmt.Errorf("failed to check document status: %s", resp.Status)
@@ -137,11 +146,15 @@ func (t *MHTransmitter) CheckDocumentStatus(ctx context.Context, document interf
 		MessageCode:    haciendaResp.MessageCode,
 		MessageDesc:    haciendaResp.DescriptionMessage,
 		Observations:   haciendaResp.Observations,
+		Response:       &haciendaResp,
 	}, nil
 }
 
-func (t *MHTransmitter) SendToHacienda(ctx context.Context, request *models2.HaciendaRequest, systemToken string) (*models2.HaciendaResponse, error) {
-	err := t.getHaciendaToken(ctx, systemToken)
+func (t *MHTransmitter) SendToHacienda(ctx context.Context, request *models2.HaciendaRequest, systemToken string) (_ *models2.HaciendaResponse, err error) {
+	ctx, span := tracing.Start(ctx, "MHTransmitter.SendToHacienda")
+	defer func() { tracing.End(span, err) }()
+
+	err = t.getHaciendaToken(ctx, systemToken)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +180,7 @@ func (t *MHTransmitter) SendToHacienda(ctx context.Context, request *models2.Hac
 
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
-		logs.Error("Failed to send to Hacienda", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to send to Hacienda", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, err
@@ -182,9 +195,10 @@ func (t *MHTransmitter) SendToHacienda(ctx context.Context, request *models2.Hac
 
 	var haciendaResp models2.HaciendaResponse
 	if err := json.Unmarshal(body, &haciendaResp); err == nil {
+		metrics.RecordHaciendaResponse(metrics.HaciendaSourceOnline, haciendaResp.Status, haciendaResp.MessageCode)
 
 		if haciendaResp.Status == "RECHAZADO" {
-			logs.Error("Document rejected by Hacienda", map[string]interface{}{
+			logs.ErrorContext(ctx, "Document rejected by Hacienda", map[string]interface{}{
 				"code":         haciendaResp.MessageCode,
 				"message":      haciendaResp.DescriptionMessage,
 				"status":       haciendaResp.Status,
@@ -214,14 +228,14 @@ func (t *MHTransmitter) handleFailedSequence(ctx context.Context, err error, doc
 	// Extract necessary information for failed sequence
 	claims, ok := ctx.Value("claims").(*models.AuthClaims)
 	if !ok {
-		logs.Error("Failed to get claims from context for failed sequence", nil)
+		logs.ErrorContext(ctx, "Failed to get claims from context for failed sequence", nil)
 		return
 	}
 
 	// Extract sequence number from control number
-	_, dteType, _, sequenceNumber, extractErr := processors.GetDocumentRequestData(document)
+	_, dteType, _, sequenceNumber, extractErr := processors.GetDocumentRequestData(ctx, document)
 	if extractErr != nil {
-		logs.Error("Failed to extract document data for failed sequence", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to extract document data for failed sequence", map[string]interface{}{
 			"error": extractErr.Error(),
 		})
 		return
@@ -240,18 +254,18 @@ func (t *MHTransmitter) handleFailedSequence(ctx context.Context, err error, doc
 		haciendaErr.Description,
 		haciendaErr.Code,
 		document,
-		formatHaciendaErrorResponse(haciendaErr),
+		formatHaciendaErrorResponse(ctx, haciendaErr),
 	)
 
 	if registrationErr != nil {
-		logs.Error("Failed to register failed sequence", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to register failed sequence", map[string]interface{}{
 			"error":          registrationErr.Error(),
 			"branchID":       claims.BranchID,
 			"dteType":        dteType,
 			"sequenceNumber": sequenceNumber,
 		})
 	} else {
-		logs.Info("Failed sequence registered successfully", map[string]interface{}{
+		logs.InfoContext(ctx, "Failed sequence registered successfully", map[string]interface{}{
 			"branchID":       claims.BranchID,
 			"dteType":        dteType,
 			"sequenceNumber": sequenceNumber,
@@ -260,7 +274,7 @@ func (t *MHTransmitter) handleFailedSequence(ctx context.Context, err error, doc
 	}
 }
 
-func formatHaciendaErrorResponse(err *hacienda_error.HaciendaResponseError) string {
+func formatHaciendaErrorResponse(ctx context.Context, err *hacienda_error.HaciendaResponseError) string {
 	// Convert the Hacienda error to a JSON string for storage
 	response := map[string]interface{}{
 		"status":         err.Status,
@@ -274,7 +288,7 @@ func formatHaciendaErrorResponse(err *hacienda_error.HaciendaResponseError) stri
 
 	jsonData, jsonErr := json.Marshal(response)
 	if jsonErr != nil {
-		logs.Error("Failed to marshal Hacienda error response", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to marshal Hacienda error response", map[string]interface{}{
 			"error": jsonErr.Error(),
 		})
 		return fmt.Sprintf("Error parsing response: %s", err.Error())
@@ -283,13 +297,13 @@ func formatHaciendaErrorResponse(err *hacienda_error.HaciendaResponseError) stri
 	return string(jsonData)
 }
 
-func (t *MHTransmitter) getProcessor(document interface{}) DocumentProcessor {
+func (t *MHTransmitter) getProcessor(ctx context.Context, document interface{}) DocumentProcessor {
 	var docMap map[string]interface{}
 
 	// Si es string JSON, parsearlo
 	if jsonStr, ok := document.(string); ok {
 		if err := json.Unmarshal([]byte(jsonStr), &docMap); err != nil {
-			logs.Error("Failed to parse document JSON", map[string]interface{}{
+			logs.ErrorContext(ctx, "Failed to parse document JSON", map[string]interface{}{
 				"error": err.Error(),
 			})
 			return nil
@@ -298,13 +312,13 @@ func (t *MHTransmitter) getProcessor(document interface{}) DocumentProcessor {
 		// Si no es string, intentar convertir directamente
 		jsonBytes, err := json.Marshal(document)
 		if err != nil {
-			logs.Error("Failed to marshal document", map[string]interface{}{
+			logs.ErrorContext(ctx, "Failed to marshal document", map[string]interface{}{
 				"error": err.Error(),
 			})
 			return nil
 		}
 		if err := json.Unmarshal(jsonBytes, &docMap); err != nil {
-			logs.Error("Failed to parse marshaled document", map[string]interface{}{
+			logs.ErrorContext(ctx, "Failed to parse marshaled document", map[string]interface{}{
 				"error": err.Error(),
 			})
 			return nil
@@ -313,19 +327,19 @@ func (t *MHTransmitter) getProcessor(document interface{}) DocumentProcessor {
 
 	// Determinar el tipo de documento basado en su estructura
 	if isInvalidationDocument(docMap) {
-		logs.Info("Document identified as invalidation")
+		logs.InfoContext(ctx, "Document identified as invalidation")
 		return t.processors["invalidation"]
 	}
 
 	// Si no es invalidación, se asume que es un DTE
-	logs.Info("Document identified as DTE")
+	logs.InfoContext(ctx, "Document identified as DTE")
 	return t.processors["dte"]
 }
 
 func (t *MHTransmitter) getHaciendaToken(ctx context.Context, systemToken string) error {
 	haciendaToken, err := t.haciendaAuth.GetOrCreateHaciendaToken(ctx, systemToken)
 	if err != nil {
-		logs.Error("Failed to get Hacienda token", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to get Hacienda token", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return err
@@ -1,10 +1,12 @@
 package transmitter
 
 import (
+	"context"
+
 	models2 "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 )
 
 type DocumentProcessor interface {
-	ProcessRequest(signedDoc string, document interface{}) (*models2.HaciendaRequest, error)
+	ProcessRequest(ctx context.Context, signedDoc string, document interface{}) (*models2.HaciendaRequest, error)
 	ProcessResponse(resp *models2.HaciendaResponse) (*models2.TransmitResult, error)
 }
@@ -1,6 +1,8 @@
 package processors
 
 import (
+	"context"
+
 	"github.com/MarlonG1/api-facturacion-sv/config"
 	models2 "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/transmitter/models"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
@@ -9,10 +11,10 @@ import (
 
 type InvalidationProcessor struct{}
 
-func (p *InvalidationProcessor) ProcessRequest(signedDoc string, document interface{}) (*models2.HaciendaRequest, error) {
-	version, dteType, generationCode, sequenceNumber, err := GetDocumentRequestData(document)
+func (p *InvalidationProcessor) ProcessRequest(ctx context.Context, signedDoc string, document interface{}) (*models2.HaciendaRequest, error) {
+	version, dteType, generationCode, sequenceNumber, err := GetDocumentRequestData(ctx, document)
 	if err != nil {
-		logs.Error("Failed to get document request data", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to get document request data", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return nil, err
@@ -1,6 +1,7 @@
 package processors
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
@@ -8,7 +9,7 @@ import (
 	"strings"
 )
 
-func GetDocumentRequestData(document interface{}) (int, string, string, int, error) {
+func GetDocumentRequestData(ctx context.Context, document interface{}) (int, string, string, int, error) {
 	var docMap map[string]interface{}
 
 	jsonData, err := json.Marshal(document)
@@ -23,14 +24,14 @@ func GetDocumentRequestData(document interface{}) (int, string, string, int, err
 	// Buscar el objeto identificacion
 	identification, ok := docMap["identificacion"].(map[string]interface{})
 	if !ok {
-		logs.Error("Missing or invalid identificacion object")
+		logs.ErrorContext(ctx, "Missing or invalid identificacion object")
 		return 0, "", "", 0, fmt.Errorf("missing or invalid identificacion object")
 	}
 
 	// Extraer version
 	version, ok := identification["version"].(float64)
 	if !ok {
-		logs.Error("Missing or invalid version field")
+		logs.ErrorContext(ctx, "Missing or invalid version field")
 		return 0, "", "", 0, fmt.Errorf("missing or invalid version field")
 	}
 
@@ -39,7 +40,7 @@ func GetDocumentRequestData(document interface{}) (int, string, string, int, err
 		documento := docMap["documento"].(map[string]interface{})
 		dteType, ok = documento["tipoDte"].(string)
 		if !ok {
-			logs.Error("Missing or invalid tipoDte field")
+			logs.ErrorContext(ctx, "Missing or invalid tipoDte field")
 			return 0, "", "", 0, fmt.Errorf("missing or invalid tipoDte field")
 		}
 	}
@@ -49,20 +50,20 @@ func GetDocumentRequestData(document interface{}) (int, string, string, int, err
 		documento := docMap["documento"].(map[string]interface{})
 		controlNumber, ok = documento["numeroControl"].(string)
 		if !ok {
-			logs.Error("Missing or invalid numeroControl field")
+			logs.ErrorContext(ctx, "Missing or invalid numeroControl field")
 			return 0, "", "", 0, fmt.Errorf("missing or invalid numeroControl field")
 		}
 	}
 
 	generationCode, ok := identification["codigoGeneracion"].(string)
 	if !ok {
-		logs.Error("Missing or invalid codigoGeneracion field")
+		logs.ErrorContext(ctx, "Missing or invalid codigoGeneracion field")
 		return 0, "", "", 0, fmt.Errorf("missing or invalid codigoGeneracion field")
 	}
 
 	sequenceNumber, err := extractCorrelativo(controlNumber)
 	if err != nil {
-		logs.Error("Failed to extract correlativo", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to extract correlativo", map[string]interface{}{
 			"numeroControl": controlNumber,
 			"error":         err.Error(),
 		})
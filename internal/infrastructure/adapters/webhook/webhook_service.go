@@ -0,0 +1,515 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity"
+	activityModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	webhookPorts "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// Cabeceras de las entregas de webhooks
+const (
+	DeliveryIDHeader = "X-Webhook-ID"
+	EventHeader      = "X-Webhook-Event"
+	TimestampHeader  = "X-Webhook-Timestamp"
+	SignatureHeader  = "X-Webhook-Signature"
+)
+
+const (
+	// requestTimeout tiempo máximo de espera de la respuesta del destino de una entrega
+	requestTimeout = 10 * time.Second
+	// deliveryLease tiempo durante el cual una entrega reservada no puede ser intentada por otro proceso
+	deliveryLease = 2 * time.Minute
+	// dueDeliveriesBatchSize cantidad de entregas pendientes leídas por consulta
+	dueDeliveriesBatchSize = 100
+	// maxErrorLength longitud máxima del error registrado de un intento
+	maxErrorLength = 500
+)
+
+// ErrTargetNotAllowed indica que el destino de una entrega se resolvió a una dirección que no es pública
+var ErrTargetNotAllowed = errors.New("webhook target address is not allowed")
+
+// WebhookService entrega los eventos del ciclo de vida de los DTE a las suscripciones de los contribuyentes. Cada
+// entrega se firma con HMAC-SHA256, los intentos fallidos se reintentan con espera exponencial y las entregas que
+// agotan sus intentos quedan en la lista de entregas fallidas hasta que se solicite su reenvío. Los eventos también se
+// publican en el stream de actividad de la sucursal para los clientes conectados en tiempo real
+type WebhookService struct {
+	repo         webhookPorts.WebhookRepositoryPort
+	cryptManager ports.CryptManager
+	vault        vault.CredentialVault
+	activity     activity.ActivityManager
+	listeners    []webhookPorts.EventListener
+	httpClient   *http.Client
+}
+
+// NewWebhookService crea una instancia de WebhookService. Recibe el repositorio de suscripciones, el manager de
+// encriptación con el que se generan los secrets de firma, el vault con el que se cifran y el stream de actividad de
+// las sucursales
+func NewWebhookService(repo webhookPorts.WebhookRepositoryPort, cryptManager ports.CryptManager, credentialVault vault.CredentialVault, activityManager activity.ActivityManager) webhookPorts.WebhookManager {
+	return &WebhookService{
+		repo:         repo,
+		cryptManager: cryptManager,
+		vault:        credentialVault,
+		activity:     activityManager,
+		httpClient:   NewDeliveryClient(),
+	}
+}
+
+// NewDeliveryClient crea el cliente HTTP de las entregas. El dialer verifica la dirección a la que se conecta después
+// de resolver el nombre del destino, por lo que un nombre que cambia a una dirección interna después de validar la
+// suscripción (DNS rebinding) no alcanza servicios internos. Las redirecciones no se siguen, la respuesta 3xx se registra
+// como un intento fallido, y no se utiliza el proxy del entorno porque la conexión se verificaría contra el proxy
+func NewDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: checkTargetAddress,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkTargetAddress rechaza la conexión si la dirección resuelta del destino no es pública
+func checkTargetAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !models.IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrTargetNotAllowed, host)
+	}
+	return nil
+}
+
+// Emit registra las entregas de un evento y las realiza en segundo plano. Los errores solo se registran en el log
+// porque el evento no debe interrumpir la acción que lo origina
+func (s *WebhookService) Emit(ctx context.Context, event *models.Event) {
+	ctx = context.WithoutCancel(ctx)
+	now := utils.TimeNow()
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = now
+	}
+
+	// 1. Publicar el evento en el stream de actividad de la sucursal y notificar a los listeners, no depende de las
+	// suscripciones
+	if s.activity != nil && event.BranchID != 0 {
+		s.activity.Publish(ctx, &activityModels.Event{
+			Type:       event.Type,
+			BranchID:   event.BranchID,
+			OccurredAt: event.OccurredAt,
+			Data:       event.Data,
+		})
+	}
+	for _, listener := range s.listeners {
+		listener.HandleEvent(ctx, event)
+	}
+
+	// 2. Obtener las suscripciones del contribuyente que reciben el evento
+	subscriptions, err := s.getSubscriptions(ctx, event)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to get webhook subscriptions", map[string]interface{}{
+			"event":    event.Type,
+			"branchID": event.BranchID,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	receivers := make([]models.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Subscribes(event.Type) {
+			receivers = append(receivers, subscription)
+		}
+	}
+	if len(receivers) == 0 {
+		return
+	}
+
+	// 3. Serializar el evento, todas las suscripciones reciben el mismo contenido
+	if event.ID == "" {
+		event.ID = strings.ToUpper(uuid.New().String())
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to marshal webhook event", map[string]interface{}{
+			"event": event.Type,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 4. Registrar una entrega por suscripción
+	deliveries := make([]models.Delivery, len(receivers))
+	for i, subscription := range receivers {
+		deliveries[i] = models.Delivery{
+			ID:             strings.ToUpper(uuid.New().String()),
+			SubscriptionID: subscription.ID,
+			UserID:         subscription.UserID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+		}
+	}
+
+	if err = s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		logs.ErrorContext(ctx, "Failed to create webhook deliveries", map[string]interface{}{
+			"event":    event.Type,
+			"eventID":  event.ID,
+			"branchID": event.BranchID,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	// 5. Realizar las entregas en segundo plano, los intentos fallidos los retoma el job de entregas pendientes
+	for _, delivery := range deliveries {
+		go s.dispatch(ctx, delivery)
+	}
+}
+
+// AddListener registra un listener de los eventos emitidos, se invoca durante la inicialización de los servicios
+func (s *WebhookService) AddListener(listener webhookPorts.EventListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// getSubscriptions obtiene las suscripciones del contribuyente propietario de la sucursal del evento o, si el evento no
+// corresponde a una sucursal, las suscripciones activas del contribuyente indicado
+func (s *WebhookService) getSubscriptions(ctx context.Context, event *models.Event) ([]models.Subscription, error) {
+	if event.BranchID != 0 || event.UserID == 0 {
+		return s.repo.GetSubscriptionsByBranch(ctx, event.BranchID)
+	}
+
+	subscriptions, err := s.repo.GetSubscriptions(ctx, event.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]models.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.IsActive {
+			active = append(active, subscription)
+		}
+	}
+	return active, nil
+}
+
+// CreateSubscription registra una suscripción del contribuyente generando su secret de firma
+func (s *WebhookService) CreateSubscription(ctx context.Context, subscription *models.Subscription) (*models.SubscriptionCredentialsResponse, error) {
+	// 1. Validar la URL y los eventos de la suscripción
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Generar el secret de firma, se almacena cifrado con el vault porque el servidor lo necesita para firmar
+	secret, err := s.cryptManager.GenerateAPISecret()
+	if err == nil {
+		subscription.Secret, err = s.vault.SealSecret(secret)
+	}
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to generate webhook secret", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("WebhookService", "CreateSubscription", err, "FailedToCreateWebhookSubscription")
+	}
+
+	subscription.IsActive = true
+	subscription.CreatedAt = utils.TimeNow()
+
+	// 3. Registrar la suscripción
+	if err = s.repo.CreateSubscription(ctx, subscription); err != nil {
+		logs.ErrorContext(ctx, "Failed to create webhook subscription", map[string]interface{}{
+			"userID": subscription.UserID,
+			"error":  err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("WebhookService", "CreateSubscription", err, "FailedToCreateWebhookSubscription")
+	}
+
+	return &models.SubscriptionCredentialsResponse{
+		Subscription: *subscription,
+		Secret:       secret,
+	}, nil
+}
+
+// ListSubscriptions obtiene las suscripciones del contribuyente
+func (s *WebhookService) ListSubscriptions(ctx context.Context, userID uint) ([]models.Subscription, error) {
+	subscriptions, err := s.repo.GetSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("WebhookService", "ListSubscriptions", err, "FailedToGetWebhookSubscriptions")
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription elimina una suscripción del contribuyente
+func (s *WebhookService) DeleteSubscription(ctx context.Context, userID, id uint) error {
+	return s.repo.DeleteSubscription(ctx, userID, id)
+}
+
+// GetDeliveries obtiene las entregas del contribuyente que cumplen con los filtros
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID uint, filters *models.DeliveryFilters) ([]models.Delivery, error) {
+	deliveries, err := s.repo.GetDeliveries(ctx, userID, filters)
+	if err != nil {
+		return nil, shared_error.NewFormattedGeneralServiceWithError("WebhookService", "GetDeliveries", err, "FailedToGetWebhookDeliveries")
+	}
+	return deliveries, nil
+}
+
+// GetDelivery obtiene una entrega del contribuyente junto con el registro de sus intentos
+func (s *WebhookService) GetDelivery(ctx context.Context, userID uint, id string) (*models.Delivery, error) {
+	return s.repo.GetDelivery(ctx, userID, id)
+}
+
+// Redeliver programa una nueva entrega inmediata de un evento reiniciando sus intentos. Permite reenviar tanto las
+// entregas de la lista de entregas fallidas como las entregas ya realizadas
+func (s *WebhookService) Redeliver(ctx context.Context, userID uint, id string) (*models.Delivery, error) {
+	// 1. Obtener la entrega del contribuyente
+	delivery, err := s.repo.GetDelivery(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Verificar que la suscripción aún exista
+	if _, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	// 3. Reiniciar los intentos de la entrega
+	now := utils.TimeNow()
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.DeliveredAt = nil
+	if err = s.repo.ResetDelivery(ctx, delivery); err != nil {
+		logs.ErrorContext(ctx, "Failed to reset webhook delivery", map[string]interface{}{
+			"deliveryID": id,
+			"error":      err.Error(),
+		})
+		return nil, shared_error.NewFormattedGeneralServiceWithError("WebhookService", "Redeliver", err, "FailedToRedeliverWebhook", id)
+	}
+
+	// 4. Realizar la entrega en segundo plano
+	go s.dispatch(ctx, *delivery)
+
+	return delivery, nil
+}
+
+// ProcessPendingDeliveries realiza las entregas cuyo siguiente intento ya está programado, se detiene al cancelarse
+// el contexto y las entregas restantes se retoman en la siguiente ejecución
+func (s *WebhookService) ProcessPendingDeliveries(ctx context.Context) error {
+	processed := make(map[string]bool)
+
+	for {
+		// 1. Obtener un lote de entregas pendientes
+		deliveries, err := s.repo.GetDueDeliveries(ctx, utils.TimeNow(), dueDeliveriesBatchSize)
+		if err != nil {
+			return shared_error.NewGeneralServiceError("WebhookService", "ProcessPendingDeliveries", "failed to get due deliveries", err)
+		}
+
+		// 2. Intentar las entregas del lote, una entrega solo se intenta una vez por ejecución
+		attempted := 0
+		for i := range deliveries {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if processed[deliveries[i].ID] {
+				continue
+			}
+
+			processed[deliveries[i].ID] = true
+			if s.claimAndAttempt(ctx, &deliveries[i]) {
+				attempted++
+			}
+		}
+
+		if len(deliveries) < dueDeliveriesBatchSize || attempted == 0 {
+			return nil
+		}
+	}
+}
+
+// dispatch realiza una entrega en segundo plano, el contexto conserva los valores de la solicitud que emitió el evento
+// (ID de la solicitud y traza) pero no su cancelación
+func (s *WebhookService) dispatch(parent context.Context, delivery models.Delivery) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), deliveryLease)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logs.ErrorContext(ctx, "Panic while delivering webhook", map[string]interface{}{
+				"deliveryID": delivery.ID,
+				"panic":      fmt.Sprintf("%v", r),
+			})
+		}
+	}()
+
+	s.claimAndAttempt(ctx, &delivery)
+}
+
+// claimAndAttempt reserva una entrega y realiza un intento, retorna false si otro proceso ya la reservó
+func (s *WebhookService) claimAndAttempt(ctx context.Context, delivery *models.Delivery) bool {
+	// 1. Reservar la entrega para evitar intentos simultáneos desde el job y desde la emisión del evento
+	now := utils.TimeNow()
+	claimed, err := s.repo.ClaimDelivery(ctx, delivery.ID, now, now.Add(deliveryLease))
+	if err != nil {
+		logs.ErrorContext(ctx, "Failed to claim webhook delivery", map[string]interface{}{
+			"deliveryID": delivery.ID,
+			"error":      err.Error(),
+		})
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	// 2. Obtener la suscripción, las entregas de una suscripción inactiva se descartan
+	subscription, err := s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		s.recordResult(ctx, delivery, nil, err.Error(), 0, false)
+		return true
+	}
+	if !subscription.IsActive {
+		s.recordResult(ctx, delivery, nil, "subscription is not active", 0, true)
+		return true
+	}
+
+	// 3. Realizar el intento y registrar su resultado
+	start := time.Now()
+	statusCode, err := s.send(ctx, subscription, delivery)
+
+	errMessage := ""
+	if err != nil {
+		errMessage = err.Error()
+	}
+	s.recordResult(ctx, delivery, statusCode, errMessage, time.Since(start).Milliseconds(), false)
+	return true
+}
+
+// send envía el evento firmado al destino de la suscripción, retorna un error si el destino no responde con 2xx. Las
+// suscripciones creadas antes de cifrar su secret solo tienen su hash y deben crearse de nuevo para recibir entregas
+func (s *WebhookService) send(ctx context.Context, subscription *models.Subscription, delivery *models.Delivery) (*int, error) {
+	timestamp := utils.TimeNow().Unix()
+
+	secret, err := s.vault.OpenSecret(subscription.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("subscription secret cannot be decrypted, create the subscription again: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryIDHeader, delivery.ID)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, SignPayload(secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		return &statusCode, fmt.Errorf("unexpected status code %d", statusCode)
+	}
+
+	return &statusCode, nil
+}
+
+// recordResult actualiza el estado de una entrega según el resultado de un intento. Las entregas fallidas se
+// reprograman con espera exponencial hasta agotar sus intentos, o se descartan de inmediato si discard es verdadero
+func (s *WebhookService) recordResult(ctx context.Context, delivery *models.Delivery, statusCode *int, errMessage string, duration int64, discard bool) {
+	now := utils.TimeNow()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = nil
+
+	attempt := &models.DeliveryAttempt{
+		Attempt:     delivery.Attempts,
+		StatusCode:  statusCode,
+		DurationMs:  duration,
+		AttemptedAt: now,
+	}
+
+	switch {
+	case errMessage == "":
+		delivery.Status = models.DeliveryDelivered
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	case discard || delivery.Attempts >= models.MaxDeliveryAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(models.RetryDelay(delivery.Attempts))
+		delivery.Status = models.DeliveryPending
+		delivery.NextAttemptAt = &next
+	}
+
+	if errMessage != "" {
+		if len(errMessage) > maxErrorLength {
+			errMessage = errMessage[:maxErrorLength]
+		}
+		delivery.LastError = &errMessage
+		attempt.ErrorMessage = &errMessage
+	}
+
+	if err := s.repo.RecordAttempt(context.WithoutCancel(ctx), delivery, attempt); err != nil {
+		logs.ErrorContext(ctx, "Failed to record webhook delivery attempt", map[string]interface{}{
+			"deliveryID": delivery.ID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	if delivery.Status == models.DeliveryDead {
+		logs.WarnContext(ctx, "Webhook delivery moved to dead letters", map[string]interface{}{
+			"deliveryID": delivery.ID,
+			"event":      delivery.EventType,
+			"attempts":   delivery.Attempts,
+			"error":      errMessage,
+		})
+	}
+}
+
+// SignPayload calcula la firma de una entrega. La firma es el HMAC-SHA256 en hexadecimal de "timestamp\ncuerpo" con el
+// secret como llave, igual que la firma de las solicitudes autenticadas con HMAC
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	return utils.SignRequest(secret, strconv.FormatInt(timestamp, 10)+"\n"+string(body))
+}
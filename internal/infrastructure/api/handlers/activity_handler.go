@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/activity"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+const (
+	// activityHeartbeatInterval intervalo de los comentarios que mantienen abierta la conexión en los proxies
+	activityHeartbeatInterval = 25 * time.Second
+	// activityRetryMillis espera que el cliente aplica antes de reconectarse cuando se cierra la conexión
+	activityRetryMillis = 3000
+)
+
+type ActivityHandler struct {
+	activityUseCase *activity.ActivityUseCase
+	respWriter      *response.ResponseWriter
+}
+
+func NewActivityHandler(activityUseCase *activity.ActivityUseCase) *ActivityHandler {
+	return &ActivityHandler{
+		activityUseCase: activityUseCase,
+		respWriter:      response.NewResponseWriter(),
+	}
+}
+
+// Stream godoc
+// @Summary      Stream branch activity
+// @Description  Server-Sent Events stream with the DTE status changes, contingency transitions and circuit breaker state changes of the branch. Send the Last-Event-ID header to resume from the last received event
+// @Tags         Events
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param Last-Event-ID header string false "ID of the last received event, e.g. '1700000000000-0'"
+// @Success      200 {object} models.Event
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/events/stream [get]
+func (h *ActivityHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// 1. Suscribirse a los eventos de la sucursal, reanudando desde el último evento recibido
+	events, err := h.activityUseCase.Stream(ctx, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	// 2. Quitar el tiempo máximo de escritura del servidor, la conexión permanece abierta mientras el cliente la use
+	controller := http.NewResponseController(w)
+	if err = controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logs.WarnContext(r.Context(), "Failed to clear write deadline of activity stream", map[string]interface{}{"error": err.Error()})
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", activityRetryMillis)
+	if err = controller.Flush(); err != nil {
+		logs.ErrorContext(r.Context(), "Activity stream does not support flushing", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	// 3. Escribir los eventos a medida que se publican hasta que el cliente cierre la conexión
+	heartbeat := time.NewTicker(activityHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err = WriteActivityEvent(w, event); err != nil {
+				logs.ErrorContext(r.Context(), "Failed to write activity event", map[string]interface{}{
+					"event": event.Type,
+					"id":    event.ID,
+					"error": err.Error(),
+				})
+				continue
+			}
+		}
+
+		if err = controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// WriteActivityEvent escribe un evento con el formato de Server-Sent Events
+func WriteActivityEvent(w io.Writer, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type ArchiveHandler struct {
+	archiveUseCase *dte.DTEArchiveUseCase
+	respWriter     *response.ResponseWriter
+}
+
+func NewArchiveHandler(archiveUseCase *dte.DTEArchiveUseCase) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveUseCase: archiveUseCase,
+		respWriter:     response.NewResponseWriter(),
+	}
+}
+
+// CreateArchive godoc
+// @Summary      Create DTE archive
+// @Description  Start an asynchronous job that bundles, for a period, the JSON, signed JWS, Hacienda response and optional PDF of every DTE of the branch or NIT into a ZIP with a manifest
+// @Tags         DTE
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param request body structs.CreateArchiveRequest true "Archive period and options"
+// @Success      202 {object} models.ArchiveJob
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/archives [post]
+func (h *ArchiveHandler) CreateArchive(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req structs.CreateArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Registrar el trabajo ejecutando el caso de uso
+	job, err := h.archiveUseCase.CreateArchive(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusAccepted, job, nil)
+}
+
+// GetArchive godoc
+// @Summary      Get DTE archive status
+// @Description  Get the status of an archive job of the branch
+// @Tags         DTE
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Archive job ID"
+// @Success      200 {object} models.ArchiveJob
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/archives/{id} [get]
+func (h *ArchiveHandler) GetArchive(w http.ResponseWriter, r *http.Request) {
+	job, err := h.archiveUseCase.GetArchive(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, job, nil)
+}
+
+// DownloadArchive godoc
+// @Summary      Download DTE archive
+// @Description  Download the ZIP of a completed archive job of the branch
+// @Tags         DTE
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Archive job ID"
+// @Success      200 {file} file
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/archives/{id}/download [get]
+func (h *ArchiveHandler) DownloadArchive(w http.ResponseWriter, r *http.Request) {
+	// 1. Abrir el archivo del trabajo
+	file, job, err := h.archiveUseCase.DownloadArchive(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+	defer file.Close()
+
+	// 2. Escribir el archivo en la respuesta
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.Filename()))
+	w.Header().Set("Content-Length", strconv.FormatInt(job.FileSize, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, file); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to write archive response", map[string]interface{}{"error": err.Error()})
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+)
+
+// auditExportWriter escribe los eventos de auditoría exportados en el formato solicitado
+type auditExportWriter interface {
+	// ContentType retorna el tipo de contenido de la respuesta
+	ContentType() string
+	// Begin escribe el encabezado del archivo, si el formato lo requiere
+	Begin() error
+	// Write escribe un evento
+	Write(event *models.AuditEvent) error
+	// Flush envía al cliente los datos pendientes
+	Flush() error
+}
+
+func newAuditExportWriter(format string, w io.Writer) auditExportWriter {
+	if format == ExportFormatNDJSON {
+		return &auditNDJSONWriter{w: w}
+	}
+	return &auditCSVWriter{w: csv.NewWriter(w)}
+}
+
+// auditCSVColumns columnas del CSV, el payload se exporta como JSON en una sola columna
+var auditCSVColumns = []string{
+	"id", "occurred_at", "event_type", "actor_type", "actor_id", "user_id", "branch_id",
+	"ip_address", "request_id", "payload", "payload_digest",
+}
+
+type auditCSVWriter struct {
+	w *csv.Writer
+}
+
+func (c *auditCSVWriter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (c *auditCSVWriter) Begin() error {
+	return c.w.Write(auditCSVColumns)
+}
+
+func (c *auditCSVWriter) Write(event *models.AuditEvent) error {
+	return c.w.Write([]string{
+		strconv.FormatUint(uint64(event.ID), 10),
+		event.OccurredAt.Format("2006-01-02 15:04:05"),
+		event.EventType,
+		event.ActorType,
+		formatOptionalID(event.ActorID),
+		formatOptionalID(event.UserID),
+		formatOptionalID(event.BranchID),
+		event.IPAddress,
+		event.RequestID,
+		string(event.Payload),
+		event.PayloadDigest,
+	})
+}
+
+func (c *auditCSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// auditNDJSONWriter escribe un evento por línea
+type auditNDJSONWriter struct {
+	w io.Writer
+}
+
+func (n *auditNDJSONWriter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (n *auditNDJSONWriter) Begin() error {
+	return nil
+}
+
+func (n *auditNDJSONWriter) Write(event *models.AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.w.Write(append(line, '\n'))
+	return err
+}
+
+func (n *auditNDJSONWriter) Flush() error {
+	return nil
+}
+
+// formatOptionalID formatea un ID, retorna una cadena vacía si el evento no lo registra
+func formatOptionalID(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
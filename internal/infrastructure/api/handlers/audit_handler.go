@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+type AuditHandler struct {
+	auditUseCase *audit.AuditUseCase
+	respWriter   *response.ResponseWriter
+}
+
+func NewAuditHandler(auditUseCase *audit.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+		respWriter:   response.NewResponseWriter(),
+	}
+}
+
+// List godoc
+// @Summary      List audit events
+// @Description  List the recorded state-changing actions from the most recent, requires the admin key or an administrator token
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param user_id query int false "Taxpayer user ID"
+// @Param branch_id query int false "Branch office ID"
+// @Param event_type query string false "Event type, e.g. 'DTE_ISSUED'"
+// @Param actor_type query string false "Actor type: 'user', 'api_key', 'operator', 'admin', 'system', 'anonymous'"
+// @Param request_id query string false "Request ID returned in the X-Request-ID header"
+// @Param startDate query string false "Start date (RFC3339)"
+// @Param endDate query string false "End date (RFC3339)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size, maximum 100"
+// @Success      200 {object} models.AuditListResponse
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/audit [get]
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	events, err := h.auditUseCase.GetEvents(r.Context(), r)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, events, nil)
+}
+
+// Export godoc
+// @Summary      Export audit events
+// @Description  Stream every audit event matching the listing filters as CSV or NDJSON, without pagination
+// @Tags         Admin
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param format query string false "Export format: 'csv' (default) or 'ndjson'"
+// @Success      200 {file} file
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/audit/export [get]
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	// 1. Validar el formato de exportación
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatNDJSON {
+		h.respWriter.HandleError(w, shared_error.NewFormattedGeneralServiceError("AuditHandler", "Export", "InvalidQueryParam", "format", "'csv', 'ndjson'"))
+		return
+	}
+
+	// 2. Los encabezados se escriben con el primer evento para poder responder con un error si la consulta falla
+	writer := newAuditExportWriter(format, w)
+	stream := newExportStream(w)
+	started := false
+	written := 0
+
+	start := func() error {
+		filename := fmt.Sprintf("audit-export-%s.%s", utils.TimeNow().Format("20060102150405"), format)
+		w.Header().Set("Content-Type", writer.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+		started = true
+		return writer.Begin()
+	}
+
+	// 3. Recorrer los eventos escribiéndolos en la respuesta a medida que se obtienen
+	err := h.auditUseCase.ExportEvents(r.Context(), r, func(event *models.AuditEvent) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Write(event); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if err := stream.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil && !started {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+	if err != nil {
+		logs.ErrorContext(r.Context(), "Audit export interrupted", map[string]interface{}{
+			"format":  format,
+			"written": written,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// 4. Si no hubo eventos se responde con el archivo vacío
+	if !started {
+		if err = start(); err != nil {
+			logs.ErrorContext(r.Context(), "Failed to write audit export", map[string]interface{}{"error": err.Error()})
+			return
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to write audit export", map[string]interface{}{"error": err.Error()})
+	}
+}
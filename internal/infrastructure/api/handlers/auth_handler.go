@@ -24,7 +24,7 @@ func NewAuthHandler(authUseCase *auth.AuthUseCase) *AuthHandler {
 
 // Login godoc
 // @Summary      Login
-// @Description  Login with API Key, API Secret and Hacienda Credentials
+// @Description  Login with API Key, API Secret and Hacienda Credentials, returns only the access token. Use /auth/token to also obtain a refresh token
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -33,18 +33,83 @@ func NewAuthHandler(authUseCase *auth.AuthUseCase) *AuthHandler {
 // @Success      200 {object} string "token"
 // @Failure      400 {object} response.APIError
 // @Failure      401 {object} response.APIError
+// @Failure      429 {object} response.APIError
+// @Failure      413 {object} response.APIError
 // @Failure      500 {object} response.APIError
 // @Router       /api/v1/auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	// 1. Decodificar la solicitud
+	// 1. Iniciar sesión
+	tokens, ok := h.login(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Responder con el access token, la respuesta conserva su formato para los clientes existentes
+	h.respWriter.Success(w, http.StatusOK, tokens.Token, nil)
+}
+
+// Token godoc
+// @Summary      Login with refresh token
+// @Description  Login with API Key, API Secret and Hacienda Credentials, returns the access token and a refresh token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param auth body models.AuthCredentials true "Auth credentials"
+// @Success      200 {object} models.TokenResponse
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      429 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/auth/token [post]
+func (h *AuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	// 1. Iniciar sesión
+	tokens, ok := h.login(w, r)
+	if !ok {
+		return
+	}
+
+	// 2. Responder con el access token y el refresh token
+	h.respWriter.Success(w, http.StatusOK, tokens, nil)
+}
+
+// login decodifica las credenciales e inicia sesión, retorna falso si ya se respondió con un error
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) (*models.TokenResponse, bool) {
 	var req models.AuthCredentials
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return nil, false
+	}
+
+	tokens, err := h.authUseCase.Login(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return nil, false
+	}
+
+	return tokens, true
+}
+
+// Refresh godoc
+// @Summary      Refresh session
+// @Description  Issue a new access token and refresh token from a refresh token, the used refresh token stops being valid. Reusing a refresh token revokes the whole session
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param request body models.RefreshRequest true "Refresh token"
+// @Success      200 {object} models.TokenResponse
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req models.RefreshRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
 		return
 	}
 
-	// 2. Iniciar sesión
-	response, err := h.authUseCase.Login(r.Context(), &req)
+	// 2. Renovar la sesión
+	response, err := h.authUseCase.Refresh(r.Context(), &req)
 	if err != nil {
 		h.respWriter.HandleError(w, err)
 		return
@@ -54,14 +119,65 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	h.respWriter.Success(w, http.StatusOK, response, nil)
 }
 
+// Logout godoc
+// @Summary      Logout
+// @Description  Revoke the access token and the refresh token of the session, and clear its cached Hacienda credentials and token
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Success      200 {object} string
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.authUseCase.Logout(r.Context()); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Logged out successfully", nil)
+}
+
+// StoreHaciendaCredentials godoc
+// @Summary      Store Hacienda credentials
+// @Description  Replace the Hacienda credentials of the authenticated user in the credential vault. Users authenticated with HMAC request signing or a client certificate do not log in, this is the only way to register their credentials
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param credentials body models.HaciendaCredentials true "Hacienda credentials"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/auth/hacienda-credentials [put]
+func (h *AuthHandler) StoreHaciendaCredentials(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req models.HaciendaCredentials
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Almacenar las credenciales
+	if err := h.authUseCase.StoreHaciendaCredentials(r.Context(), &req); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	// 3. Responder con éxito
+	h.respWriter.Success(w, http.StatusOK, "Hacienda credentials stored successfully", nil)
+}
+
 // Register godoc
 // @Summary      Register
-// @Description  Register a new user
+// @Description  Register a new user, the registration stays pending until an administrator approves it and the branch API keys and secrets are issued
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param user body user.User true "User data"
-// @Success      201 {object} []user.ListBranchesResponse
+// @Success      201 {object} user.RegistrationResponse
 // @Failure      400 {object} response.APIError
 // @Failure      401 {object} response.APIError
 // @Failure      500 {object} response.APIError
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type BranchHandler struct {
+	branchUseCase *auth.BranchUseCase
+	respWriter    *response.ResponseWriter
+}
+
+func NewBranchHandler(branchUseCase *auth.BranchUseCase) *BranchHandler {
+	return &BranchHandler{
+		branchUseCase: branchUseCase,
+		respWriter:    response.NewResponseWriter(),
+	}
+}
+
+// ListBranches godoc
+// @Summary      List branch offices
+// @Description  List the active and inactive branch offices of the authenticated user, API secrets are never returned
+// @Tags         Branches
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Success      200 {object} []user.BranchOfficeResponse
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches [get]
+func (h *BranchHandler) ListBranches(w http.ResponseWriter, r *http.Request) {
+	branches, err := h.branchUseCase.ListBranches(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, branches, nil)
+}
+
+// CreateBranch godoc
+// @Summary      Create branch office
+// @Description  Add a branch office to the authenticated user, the generated API key and API secret are returned only once. Assigning a client_cert_subject requires presenting that client certificate in the request
+// @Tags         Branches
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param branch body user.BranchOffice true "Branch office data"
+// @Success      201 {object} user.BranchCredentialsResponse
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches [post]
+func (h *BranchHandler) CreateBranch(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req user.BranchOffice
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Crear la sucursal
+	credentials, err := h.branchUseCase.CreateBranch(r.Context(), &req, helpers.ClientCertIdentities(r))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, credentials, nil)
+}
+
+// UpdateBranch godoc
+// @Summary      Update branch office
+// @Description  Update the data of a branch office of the authenticated user, omitted fields keep their current value. An empty client_cert_subject removes the assigned certificate and a rate_limit_per_minute of -1 restores the default quota. Assigning a client_cert_subject requires presenting that client certificate in the request
+// @Tags         Branches
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Branch office ID"
+// @Param branch body user.BranchOffice true "Branch office data"
+// @Success      200 {object} user.BranchOfficeResponse
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches/{id} [put]
+func (h *BranchHandler) UpdateBranch(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req user.BranchOffice
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Actualizar la sucursal
+	branch, err := h.branchUseCase.UpdateBranch(r.Context(), helpers.GetRequestVar(r, "id"), &req, helpers.ClientCertIdentities(r))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, branch, nil)
+}
+
+// DeactivateBranch godoc
+// @Summary      Deactivate branch office
+// @Description  Deactivate a branch office of the authenticated user and invalidate its tokens, the branch matrix cannot be deactivated
+// @Tags         Branches
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Branch office ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches/{id}/deactivate [post]
+func (h *BranchHandler) DeactivateBranch(w http.ResponseWriter, r *http.Request) {
+	if err := h.branchUseCase.DeactivateBranch(r.Context(), helpers.GetRequestVar(r, "id")); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Branch office deactivated successfully", nil)
+}
+
+// RotateBranchKeys godoc
+// @Summary      Rotate branch office keys
+// @Description  Generate a new API key and API secret for an active branch office, tokens issued with the previous keys stop being valid
+// @Tags         Branches
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Branch office ID"
+// @Success      200 {object} user.BranchCredentialsResponse
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches/{id}/rotate-keys [post]
+func (h *BranchHandler) RotateBranchKeys(w http.ResponseWriter, r *http.Request) {
+	credentials, err := h.branchUseCase.RotateBranchKeys(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, credentials, nil)
+}
+
+// ListAPIKeys godoc
+// @Summary      List branch API keys
+// @Description  List the additional API keys of a branch office with their scopes, API secrets are never returned
+// @Tags         Branches
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Branch office ID"
+// @Success      200 {object} []user.BranchAPIKey
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches/{id}/api-keys [get]
+func (h *BranchHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.branchUseCase.ListAPIKeys(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, keys, nil)
+}
+
+// CreateAPIKey godoc
+// @Summary      Create branch API key
+// @Description  Create an additional API key limited to the given scopes, e.g. read-only keys for accounting integrations. The API secret is returned only once. Assigning a client_cert_subject requires presenting that client certificate in the request
+// @Tags         Branches
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Branch office ID"
+// @Param key body user.BranchAPIKey true "API key name and scopes"
+// @Success      201 {object} user.BranchAPIKeyCredentialsResponse
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches/{id}/api-keys [post]
+func (h *BranchHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req user.BranchAPIKey
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Crear la llave
+	credentials, err := h.branchUseCase.CreateAPIKey(r.Context(), helpers.GetRequestVar(r, "id"), &req, helpers.ClientCertIdentities(r))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, credentials, nil)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke branch API key
+// @Description  Deactivate an additional API key of a branch office and invalidate the tokens issued with it
+// @Tags         Branches
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Branch office ID"
+// @Param keyId path string true "API key ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/branches/{id}/api-keys/{keyId}/revoke [post]
+func (h *BranchHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.branchUseCase.RevokeAPIKey(r.Context(), helpers.GetRequestVar(r, "id"), helpers.GetRequestVar(r, "keyId")); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "API key revoked successfully", nil)
+}
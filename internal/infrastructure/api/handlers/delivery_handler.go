@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type DeliveryHandler struct {
+	deliveryUseCase *dte.DTEDeliveryUseCase
+	respWriter      *response.ResponseWriter
+}
+
+func NewDeliveryHandler(deliveryUseCase *dte.DTEDeliveryUseCase) *DeliveryHandler {
+	return &DeliveryHandler{
+		deliveryUseCase: deliveryUseCase,
+		respWriter:      response.NewResponseWriter(),
+	}
+}
+
+// ResendDTE godoc
+// @Summary      Resend DTE by email
+// @Description  Send the JSON and the PDF graphic representation of a DTE by email. If no recipients are provided, the receiver email of the document is used
+// @Tags         DTE
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Generation code of the DTE"
+// @Param request body structs.ResendDTERequest false "Optional recipients"
+// @Success      200 {object} models.DeliveryResult
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/{id}/email [post]
+func (h *DeliveryHandler) ResendDTE(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud, el cuerpo es opcional
+	var req structs.ResendDTERequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Enviar el DTE ejecutando el caso de uso
+	result, err := h.deliveryUseCase.ResendDTE(r.Context(), helpers.GetRequestVar(r, "id"), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, result, nil)
+}
+
+// GetDeliveries godoc
+// @Summary      Get DTE email deliveries
+// @Description  Get the email delivery history of a DTE by its generation code
+// @Tags         DTE
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Generation code of the DTE"
+// @Success      200 {array} models.Delivery
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/{id}/email [get]
+func (h *DeliveryHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.deliveryUseCase.GetDeliveries(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, deliveries, nil)
+}
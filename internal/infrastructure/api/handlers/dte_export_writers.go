@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportWriteTimeout tiempo máximo para enviar cada bloque de una exportación. Reemplaza el WriteTimeout del servidor,
+// que cortaría las exportaciones grandes, y se renueva con cada bloque para liberar la conexión si el cliente se detiene
+const exportWriteTimeout = 30 * time.Second
+
+// exportStream envía al cliente los bloques de una exportación, utiliza http.ResponseController para alcanzar el
+// http.ResponseWriter original a través de los middlewares que lo envuelven
+type exportStream struct {
+	controller *http.ResponseController
+}
+
+func newExportStream(w http.ResponseWriter) *exportStream {
+	stream := &exportStream{controller: http.NewResponseController(w)}
+	stream.extendDeadline()
+	return stream
+}
+
+// Flush envía los datos escritos y renueva el tiempo máximo de escritura para el siguiente bloque
+func (s *exportStream) Flush() error {
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	s.extendDeadline()
+	return nil
+}
+
+// extendDeadline renueva el tiempo máximo de escritura, los writers que no lo admiten no tienen tiempo máximo
+func (s *exportStream) extendDeadline() {
+	_ = s.controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+}
+
+// dteExportWriter escribe los documentos exportados en el formato solicitado
+type dteExportWriter interface {
+	// ContentType retorna el tipo de contenido de la respuesta
+	ContentType() string
+	// Begin escribe el encabezado del archivo, si el formato lo requiere
+	Begin() error
+	// Write escribe un documento
+	Write(record *dte.DTEExportRecord) error
+	// Flush envía al cliente los datos pendientes
+	Flush() error
+}
+
+func newDTEExportWriter(format string, w io.Writer) dteExportWriter {
+	if format == ExportFormatNDJSON {
+		return &ndjsonExportWriter{w: w}
+	}
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+// csvExportColumns columnas del CSV, la identificación, el receptor y el resumen del DTE se aplanan en columnas
+var csvExportColumns = []string{
+	"generation_code", "control_number", "dte_type", "status", "transmission", "reception_stamp",
+	"emission_date", "emission_time", "currency", "issuer_nit", "issuer_name",
+	"receiver_document", "receiver_nrc", "receiver_name", "receiver_email",
+	"total_non_subject", "total_exempt", "total_taxed", "total_discount", "subtotal",
+	"total_iva", "iva_perceived", "iva_retained", "income_retention", "total_operation", "total_to_pay",
+	"created_at",
+}
+
+// csvExportDocument campos del JSON de Hacienda que se exportan en el CSV
+type csvExportDocument struct {
+	Identificacion struct {
+		FecEmi     string `json:"fecEmi"`
+		HorEmi     string `json:"horEmi"`
+		TipoMoneda string `json:"tipoMoneda"`
+	} `json:"identificacion"`
+	Emisor struct {
+		NIT    string `json:"nit"`
+		Nombre string `json:"nombre"`
+	} `json:"emisor"`
+	Receptor struct {
+		NIT          *string `json:"nit"`
+		NumDocumento *string `json:"numDocumento"`
+		NRC          *string `json:"nrc"`
+		Nombre       *string `json:"nombre"`
+		Correo       *string `json:"correo"`
+	} `json:"receptor"`
+	Resumen struct {
+		TotalNoSuj          *float64 `json:"totalNoSuj"`
+		TotalExenta         *float64 `json:"totalExenta"`
+		TotalGravada        *float64 `json:"totalGravada"`
+		TotalDescu          *float64 `json:"totalDescu"`
+		SubTotal            *float64 `json:"subTotal"`
+		TotalIva            *float64 `json:"totalIva"`
+		IvaPerci1           *float64 `json:"ivaPerci1"`
+		IvaRete1            *float64 `json:"ivaRete1"`
+		TotalIvaRetenido    *float64 `json:"totalIVAretenido"`
+		ReteRenta           *float64 `json:"reteRenta"`
+		MontoTotalOperacion *float64 `json:"montoTotalOperacion"`
+		TotalPagar          *float64 `json:"totalPagar"`
+		Tributos            []struct {
+			Valor float64 `json:"valor"`
+		} `json:"tributos"`
+	} `json:"resumen"`
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (c *csvExportWriter) Begin() error {
+	return c.w.Write(csvExportColumns)
+}
+
+func (c *csvExportWriter) Write(record *dte.DTEExportRecord) error {
+	var doc csvExportDocument
+	if err := json.Unmarshal([]byte(record.JSONData), &doc); err != nil {
+		return err
+	}
+
+	// El IVA de los CCF se informa en tributos, el de las facturas en totalIva
+	totalIva := doc.Resumen.TotalIva
+	if totalIva == nil && len(doc.Resumen.Tributos) > 0 {
+		var sum float64
+		for _, tax := range doc.Resumen.Tributos {
+			sum += tax.Valor
+		}
+		totalIva = &sum
+	}
+
+	ivaRetained := doc.Resumen.IvaRete1
+	if ivaRetained == nil {
+		ivaRetained = doc.Resumen.TotalIvaRetenido
+	}
+
+	receiverDocument := doc.Receptor.NIT
+	if receiverDocument == nil {
+		receiverDocument = doc.Receptor.NumDocumento
+	}
+
+	return c.w.Write([]string{
+		record.GenerationCode,
+		record.ControlNumber,
+		record.DTEType,
+		record.Status,
+		record.Transmission,
+		utils.PointerToString(record.ReceptionStamp),
+		doc.Identificacion.FecEmi,
+		doc.Identificacion.HorEmi,
+		doc.Identificacion.TipoMoneda,
+		doc.Emisor.NIT,
+		doc.Emisor.Nombre,
+		utils.PointerToString(receiverDocument),
+		utils.PointerToString(doc.Receptor.NRC),
+		utils.PointerToString(doc.Receptor.Nombre),
+		utils.PointerToString(doc.Receptor.Correo),
+		formatAmount(doc.Resumen.TotalNoSuj),
+		formatAmount(doc.Resumen.TotalExenta),
+		formatAmount(doc.Resumen.TotalGravada),
+		formatAmount(doc.Resumen.TotalDescu),
+		formatAmount(doc.Resumen.SubTotal),
+		formatAmount(totalIva),
+		formatAmount(doc.Resumen.IvaPerci1),
+		formatAmount(ivaRetained),
+		formatAmount(doc.Resumen.ReteRenta),
+		formatAmount(doc.Resumen.MontoTotalOperacion),
+		formatAmount(doc.Resumen.TotalPagar),
+		record.CreatedAt.Format("2006-01-02 15:04:05"),
+	})
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonExportWriter escribe un documento por línea con el JSON completo de Hacienda
+type ndjsonExportWriter struct {
+	w io.Writer
+}
+
+func (n *ndjsonExportWriter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (n *ndjsonExportWriter) Begin() error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) Write(record *dte.DTEExportRecord) error {
+	// Compactar el JSON para garantizar un documento por línea
+	var document bytes.Buffer
+	if err := json.Compact(&document, []byte(record.JSONData)); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(dte.DTEModelResponse{
+		Status:           record.Status,
+		TransmissionType: record.Transmission,
+		Document:         document.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.w.Write(append(line, '\n'))
+	return err
+}
+
+func (n *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+// formatAmount formatea un monto con dos decimales, retorna una cadena vacía si el monto no existe en el documento
+func formatAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*amount, 'f', 2, 64)
+}
@@ -2,15 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	dteModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
+// exportFlushInterval cantidad de documentos escritos entre cada envío de datos al cliente
+const exportFlushInterval = 100
+
 type DTEHandler struct {
 	GenericHandler      *GenericCreatorDTEHandler
 	dteConsultUseCase   *dte.DTEConsultUseCase
@@ -61,12 +68,101 @@ func (h *DTEHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	h.respWriter.Success(w, http.StatusOK, dtes, nil)
 }
 
+// Export godoc
+// @Summary      Export DTEs
+// @Description  Stream every DTE matching the listing filters as CSV or NDJSON, without pagination
+// @Tags         DTE
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param format query string false "Export format: 'csv' (default) or 'ndjson'"
+// @Success      200 {file} file
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/export [get]
+func (h *DTEHandler) Export(w http.ResponseWriter, r *http.Request) {
+	// 1. Validar el formato de exportación
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatNDJSON {
+		h.respWriter.HandleError(w, shared_error.NewFormattedGeneralServiceError("DTEHandler", "Export", "InvalidQueryParam", "format", "'csv', 'ndjson'"))
+		return
+	}
+
+	// 2. Los encabezados se escriben con el primer documento para poder responder con un error si la consulta falla
+	writer := newDTEExportWriter(format, w)
+	stream := newExportStream(w)
+	started := false
+	written := 0
+
+	start := func() error {
+		filename := fmt.Sprintf("dte-export-%s.%s", utils.TimeNow().Format("20060102150405"), format)
+		w.Header().Set("Content-Type", writer.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+		started = true
+		return writer.Begin()
+	}
+
+	// 3. Recorrer los documentos escribiéndolos en la respuesta a medida que se obtienen
+	err := h.dteConsultUseCase.ExportDTEs(r.Context(), r, func(record *dteModels.DTEExportRecord) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if err := stream.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil && !started {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+	if err != nil {
+		logs.ErrorContext(r.Context(), "DTE export interrupted", map[string]interface{}{
+			"format":  format,
+			"written": written,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// 4. Si no hubo documentos se responde con el archivo vacío
+	if !started {
+		if err = start(); err != nil {
+			logs.ErrorContext(r.Context(), "Failed to write DTE export", map[string]interface{}{"error": err.Error()})
+			return
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to write DTE export", map[string]interface{}{"error": err.Error()})
+	}
+}
+
 // InvalidateDocument maneja la solicitud HTTP para invalidar un DTE
 func (h *DTEHandler) InvalidateDocument(w http.ResponseWriter, r *http.Request) {
 	// 1. Decodificar la solicitud de invalidación de documento a un DTO de solicitud
 	var req structs.CreateInvalidationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logs.Error("Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
 		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
 		return
 	}
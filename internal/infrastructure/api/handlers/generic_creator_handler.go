@@ -51,7 +51,7 @@ func (h *GenericCreatorDTEHandler) HandleCreate(w http.ResponseWriter, r *http.R
 
 	// 3. Decodificar el JSON en la estructura de solicitud
 	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
-		logs.Error("Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
 		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
 		return
 	}
@@ -59,7 +59,7 @@ func (h *GenericCreatorDTEHandler) HandleCreate(w http.ResponseWriter, r *http.R
 	// 4. Invocar el caso de uso genérico
 	resp, options, err := config.UseCase.Create(r.Context(), request)
 	if err != nil {
-		logs.Warn("Error processing document because", map[string]interface{}{"error": err.Error()})
+		logs.WarnContext(r.Context(), "Error processing document because", map[string]interface{}{"error": err.Error()})
 
 		// 5. Si aplica contingencia, manejarla
 		if config.UsesContingency {
@@ -82,13 +82,13 @@ func (h *GenericCreatorDTEHandler) HandleCreate(w http.ResponseWriter, r *http.R
 // handleErrorForContingency maneja el error en caso de que se aplique una contingencia
 func (h *GenericCreatorDTEHandler) handleErrorForContingency(ctx context.Context, dte interface{}, dteType string, options *response.SuccessOptions, err error, w http.ResponseWriter) error {
 	// 1. Verificar si aplica a contingencia
-	logs.Warn("Error transmitting DTE because", map[string]interface{}{
+	logs.WarnContext(ctx, "Error transmitting DTE because", map[string]interface{}{
 		"error": err.Error(),
 	})
 
 	contiType, reason := h.contingencyHandler.HandleContingency(ctx, dte, dteType, err)
 	if contiType == nil || reason == nil {
-		logs.Error("Error creating DTE contingency", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "Error creating DTE contingency", map[string]interface{}{"error": err.Error()})
 		return err
 	}
 
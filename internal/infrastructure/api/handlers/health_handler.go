@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 )
@@ -22,7 +24,7 @@ func NewHealthHandler(checkHealthUseCase health.HealthManager) *HealthHandler {
 
 // CheckHealth godoc
 // @Summary      Health Check
-// @Description  Check the health of all core service
+// @Description  Check the health of all core service. The status is DOWN when a critical component (database) fails and DEGRADED when only a degradable component (Hacienda, signer, Redis, filesystem) fails, in which case new documents are issued in contingency
 // @Tags         Health
 // @Accept       json
 // @Produce      json
@@ -30,12 +32,12 @@ func NewHealthHandler(checkHealthUseCase health.HealthManager) *HealthHandler {
 // @Failure      500 {object} response.APIError
 // @Router       /api/v1/health [get]
 func (h *HealthHandler) CheckHealth(w http.ResponseWriter, r *http.Request) {
-	logs.Info("Starting health check")
-	defer logs.Info("Health check finished")
+	logs.InfoContext(r.Context(), "Starting health check")
+	defer logs.InfoContext(r.Context(), "Health check finished")
 
-	status, err := h.healthManager.CheckHealth()
+	status, err := h.healthManager.CheckHealth(r.Context())
 	if err != nil {
-		logs.Error("Health check failed", map[string]interface{}{
+		logs.ErrorContext(r.Context(), "Health check failed", map[string]interface{}{
 			"error": err.Error(),
 		})
 		h.responseWriter.Error(w, http.StatusInternalServerError, "Health check failed", []string{err.Error()})
@@ -44,3 +46,48 @@ func (h *HealthHandler) CheckHealth(w http.ResponseWriter, r *http.Request) {
 
 	h.responseWriter.Success(w, http.StatusOK, status, nil)
 }
+
+// CheckLiveness godoc
+// @Summary      Liveness probe
+// @Description  Check that the process is running. Dependencies are not checked, so an outage of Hacienda or the signer never restarts the service
+// @Tags         Health
+// @Produce      json
+// @Success      200 {object} models.HealthStatus
+// @Router       /api/v1/health/live [get]
+func (h *HealthHandler) CheckLiveness(w http.ResponseWriter, r *http.Request) {
+	h.responseWriter.Success(w, http.StatusOK, h.healthManager.CheckLiveness(), nil)
+}
+
+// CheckReadiness godoc
+// @Summary      Readiness probe
+// @Description  Check that the service can receive traffic. Only critical components make the service not ready, a DEGRADED service keeps receiving documents and issues them in contingency
+// @Tags         Health
+// @Produce      json
+// @Success      200 {object} models.HealthStatus
+// @Failure      503 {object} response.APIError
+// @Router       /api/v1/health/ready [get]
+func (h *HealthHandler) CheckReadiness(w http.ResponseWriter, r *http.Request) {
+	status, err := h.healthManager.CheckHealth(r.Context())
+	if err != nil {
+		logs.ErrorContext(r.Context(), "Readiness check failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		h.responseWriter.Error(w, http.StatusServiceUnavailable, "Service not ready", []string{err.Error()})
+		return
+	}
+
+	if status.Status == constants.StatusDown {
+		details := make([]string, 0)
+		for name, component := range status.Components {
+			if component.Critical && component.Status == constants.StatusDown {
+				details = append(details, name+": "+component.Details)
+			}
+		}
+		sort.Strings(details)
+
+		h.responseWriter.Error(w, http.StatusServiceUnavailable, "Service not ready", details)
+		return
+	}
+
+	h.responseWriter.Success(w, http.StatusOK, status, nil)
+}
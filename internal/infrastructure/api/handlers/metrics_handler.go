@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	appMetrics "github.com/MarlonG1/api-facturacion-sv/internal/application/metrics"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
@@ -10,14 +11,16 @@ import (
 )
 
 type MetricsHandler struct {
-	metricsManager metrics.MetricsManager
-	responseWriter *response.ResponseWriter
+	metricsManager  metrics.MetricsManager
+	businessUseCase *appMetrics.BusinessMetricsUseCase
+	responseWriter  *response.ResponseWriter
 }
 
-func NewMetricsHandler(metricsManager metrics.MetricsManager) *MetricsHandler {
+func NewMetricsHandler(metricsManager metrics.MetricsManager, businessUseCase *appMetrics.BusinessMetricsUseCase) *MetricsHandler {
 	return &MetricsHandler{
-		metricsManager: metricsManager,
-		responseWriter: response.NewResponseWriter(),
+		metricsManager:  metricsManager,
+		businessUseCase: businessUseCase,
+		responseWriter:  response.NewResponseWriter(),
 	}
 }
 
@@ -45,7 +48,7 @@ func (h *MetricsHandler) GetEndpointMetrics(w http.ResponseWriter, r *http.Reque
 	if endpoint == "" || method == "" {
 		allMetrics, err := h.metricsManager.GetAllMetricsEndpoint(claims.NIT)
 		if err != nil {
-			logs.Error("Failed to get all endpoint endpointMetrics", map[string]interface{}{
+			logs.ErrorContext(r.Context(), "Failed to get all endpoint endpointMetrics", map[string]interface{}{
 				"error":     err.Error(),
 				"systemNIT": claims.NIT,
 			})
@@ -59,7 +62,7 @@ func (h *MetricsHandler) GetEndpointMetrics(w http.ResponseWriter, r *http.Reque
 	// Si hay filtros, obtenemos las métricas específicas
 	endpointMetrics, err := h.metricsManager.GetEndpointMetrics(claims.NIT, method, endpoint)
 	if err != nil {
-		logs.Error("Failed to get endpoint endpointMetrics", map[string]interface{}{
+		logs.ErrorContext(r.Context(), "Failed to get endpoint endpointMetrics", map[string]interface{}{
 			"error":     err.Error(),
 			"systemNIT": claims.NIT,
 			"endpoint":  endpoint,
@@ -71,3 +74,31 @@ func (h *MetricsHandler) GetEndpointMetrics(w http.ResponseWriter, r *http.Reque
 
 	h.responseWriter.Success(w, http.StatusOK, endpointMetrics, nil)
 }
+
+// GetBusinessMetrics godoc
+// @Summary      Get business metrics
+// @Description  Get the DTE counts and amounts by type, status and transmission for a period, the pending contingency
+// @Description  queue by age and the rejection rate with the most frequent Hacienda rejection codes. With all=true the
+// @Description  metrics include every branch of the taxpayer and require the dte:read:all-branches scope
+// @Tags         Metrics
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param period query string false "Period: 'today' (default), 'month' or 'custom'"
+// @Param start_date query string false "Start date of the custom period (YYYY-MM-DD)"
+// @Param end_date query string false "End date of the custom period, inclusive (YYYY-MM-DD)"
+// @Param all query bool false "Include every branch of the taxpayer"
+// @Success      200 {object} models.Metrics
+// @Failure      400 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/metrics/business [get]
+func (h *MetricsHandler) GetBusinessMetrics(w http.ResponseWriter, r *http.Request) {
+	result, err := h.businessUseCase.GetBusinessMetrics(r.Context(), r)
+	if err != nil {
+		h.responseWriter.HandleError(w, err)
+		return
+	}
+
+	h.responseWriter.Success(w, http.StatusOK, result, nil)
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/notification"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type NotificationHandler struct {
+	notificationUseCase *notification.NotificationUseCase
+	respWriter          *response.ResponseWriter
+}
+
+func NewNotificationHandler(notificationUseCase *notification.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+		respWriter:          response.NewResponseWriter(),
+	}
+}
+
+// ListNotifications godoc
+// @Summary      List notifications
+// @Description  List the alerts delivered to the taxpayer from the most recent, with their channel, delivery status and attempts
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param alert query string false "Alert type, e.g. 'contingency.entered'"
+// @Param channel query string false "Delivery channel: 'email', 'webhook'"
+// @Param status query string false "Delivery status: 'pending', 'sent', 'failed'"
+// @Param limit query int false "Maximum number of notifications, maximum 200"
+// @Success      200 {object} []models.Notification
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/notifications [get]
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	notifications, err := h.notificationUseCase.GetNotifications(r.Context(), r)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, notifications, nil)
+}
+
+// ListRecipients godoc
+// @Summary      List notification recipients
+// @Description  List the email recipients of the alerts of the taxpayer
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Success      200 {object} []models.Recipient
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/notifications/recipients [get]
+func (h *NotificationHandler) ListRecipients(w http.ResponseWriter, r *http.Request) {
+	recipients, err := h.notificationUseCase.ListRecipients(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, recipients, nil)
+}
+
+// CreateRecipient godoc
+// @Summary      Create notification recipient
+// @Description  Add an email recipient for the alerts of the taxpayer
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param request body structs.CreateNotificationRecipientRequest true "Recipient email"
+// @Success      201 {object} models.Recipient
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/notifications/recipients [post]
+func (h *NotificationHandler) CreateRecipient(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req structs.CreateNotificationRecipientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Registrar el destinatario
+	recipient, err := h.notificationUseCase.CreateRecipient(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, recipient, nil)
+}
+
+// DeleteRecipient godoc
+// @Summary      Delete notification recipient
+// @Description  Delete an email recipient of the alerts of the taxpayer, the notifications already sent are kept
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Recipient ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/notifications/recipients/{id} [delete]
+func (h *NotificationHandler) DeleteRecipient(w http.ResponseWriter, r *http.Request) {
+	if err := h.notificationUseCase.DeleteRecipient(r.Context(), helpers.GetRequestVar(r, "id")); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Notification recipient deleted successfully", nil)
+}
+
+// SetCertificateExpiry godoc
+// @Summary      Set signing certificate expiration
+// @Description  Register the expiration date of the signing certificate of the taxpayer, the recipients are alerted 30 days before it expires. An empty date disables the alert
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param request body structs.SetCertificateExpiryRequest true "Expiration date in format YYYY-MM-DD"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/notifications/certificate [put]
+func (h *NotificationHandler) SetCertificateExpiry(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req structs.SetCertificateExpiryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Registrar la fecha de vencimiento
+	if err := h.notificationUseCase.SetCertificateExpiry(r.Context(), &req); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Certificate expiration updated successfully", nil)
+}
+
+// ListAdminRecipients godoc
+// @Summary      List admin notification recipients
+// @Description  List the email recipients of the system administration, they receive the alerts of every taxpayer
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Success      200 {object} []models.Recipient
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/notifications/recipients [get]
+func (h *NotificationHandler) ListAdminRecipients(w http.ResponseWriter, r *http.Request) {
+	recipients, err := h.notificationUseCase.ListAdminRecipients(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, recipients, nil)
+}
+
+// CreateAdminRecipient godoc
+// @Summary      Create admin notification recipient
+// @Description  Add an email recipient of the system administration
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param request body structs.CreateNotificationRecipientRequest true "Recipient email"
+// @Success      201 {object} models.Recipient
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/notifications/recipients [post]
+func (h *NotificationHandler) CreateAdminRecipient(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req structs.CreateNotificationRecipientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Registrar el destinatario
+	recipient, err := h.notificationUseCase.CreateAdminRecipient(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, recipient, nil)
+}
+
+// DeleteAdminRecipient godoc
+// @Summary      Delete admin notification recipient
+// @Description  Delete an email recipient of the system administration
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param id path string true "Recipient ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/notifications/recipients/{id} [delete]
+func (h *NotificationHandler) DeleteAdminRecipient(w http.ResponseWriter, r *http.Request) {
+	if err := h.notificationUseCase.DeleteAdminRecipient(r.Context(), helpers.GetRequestVar(r, "id")); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Notification recipient deleted successfully", nil)
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type OperatorHandler struct {
+	operatorUseCase *operator.OperatorUseCase
+	respWriter      *response.ResponseWriter
+}
+
+func NewOperatorHandler(operatorUseCase *operator.OperatorUseCase) *OperatorHandler {
+	return &OperatorHandler{
+		operatorUseCase: operatorUseCase,
+		respWriter:      response.NewResponseWriter(),
+	}
+}
+
+// Create godoc
+// @Summary      Create operator
+// @Description  Create an operator account that can issue documents on behalf of several taxpayers, the generated API key and API secret are returned only once
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param operator body models.CreateOperatorRequest true "Operator data"
+// @Success      201 {object} models.OperatorCredentialsResponse
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/operators [post]
+func (h *OperatorHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req models.CreateOperatorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Crear el operador
+	credentials, err := h.operatorUseCase.Create(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, credentials, nil)
+}
+
+// List godoc
+// @Summary      List operators
+// @Description  List the operator accounts and the taxpayers and branch offices granted to each one, API secrets are never returned
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Success      200 {object} []models.Operator
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/operators [get]
+func (h *OperatorHandler) List(w http.ResponseWriter, r *http.Request) {
+	operators, err := h.operatorUseCase.List(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, operators, nil)
+}
+
+// Grant godoc
+// @Summary      Grant taxpayer to operator
+// @Description  Grant an operator access to a taxpayer, if a branch office is given the access is limited to that branch office
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param id path string true "Operator ID"
+// @Param grant body models.GrantRequest true "Taxpayer and optional branch office"
+// @Success      201 {object} models.OperatorGrant
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/operators/{id}/grants [post]
+func (h *OperatorHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req models.GrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Crear el acceso
+	grant, err := h.operatorUseCase.Grant(r.Context(), helpers.GetRequestVar(r, "id"), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, grant, nil)
+}
+
+// RevokeGrant godoc
+// @Summary      Revoke operator grant
+// @Description  Remove an operator access, tokens the operator already issued for its branch offices are revoked
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param id path string true "Operator ID"
+// @Param grantId path string true "Grant ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/operators/{id}/grants/{grantId}/revoke [post]
+func (h *OperatorHandler) RevokeGrant(w http.ResponseWriter, r *http.Request) {
+	err := h.operatorUseCase.RevokeGrant(r.Context(), helpers.GetRequestVar(r, "id"), helpers.GetRequestVar(r, "grantId"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Operator grant revoked successfully", nil)
+}
+
+// Deactivate godoc
+// @Summary      Deactivate operator
+// @Description  Deactivate an operator, its operator token and the tokens it issued for its branch offices are revoked
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param id path string true "Operator ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/operators/{id}/deactivate [post]
+func (h *OperatorHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	err := h.operatorUseCase.Deactivate(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Operator deactivated successfully", nil)
+}
+
+// Login godoc
+// @Summary      Operator login
+// @Description  Login with the operator API Key and API Secret, the token only allows listing the granted branch offices and their documents and switching the active issuer
+// @Tags         Operators
+// @Accept       json
+// @Produce      json
+// @Param auth body models.OperatorCredentials true "Operator credentials"
+// @Success      200 {object} authModels.TokenResponse
+// @Failure      400 {object} response.APIError
+// @Failure      429 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/operator/login [post]
+func (h *OperatorHandler) Login(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req models.OperatorCredentials
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Iniciar sesión
+	token, err := h.operatorUseCase.Login(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, token, nil)
+}
+
+// ManagedBranches godoc
+// @Summary      List managed branch offices
+// @Description  List the active branch offices of the taxpayers granted to the authenticated operator
+// @Tags         Operators
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Success      200 {object} []models.ManagedBranch
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/operator/branches [get]
+func (h *OperatorHandler) ManagedBranches(w http.ResponseWriter, r *http.Request) {
+	branches, err := h.operatorUseCase.ManagedBranches(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, branches, nil)
+}
+
+// SwitchIssuer godoc
+// @Summary      Switch active issuer
+// @Description  Exchange the operator token for a token of one of the granted branch offices, the documents issued with it use the issuer data, numbering and Hacienda credentials of that branch office. The token expires together with the operator token
+// @Tags         Operators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param request body models.SwitchIssuerRequest true "Branch office to act as"
+// @Success      200 {object} authModels.TokenResponse
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/operator/switch [post]
+func (h *OperatorHandler) SwitchIssuer(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req models.SwitchIssuerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Emitir el token de la sucursal
+	token, err := h.operatorUseCase.SwitchIssuer(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, token, nil)
+}
+
+// ListDocuments godoc
+// @Summary      List managed documents
+// @Description  List the documents of every branch office granted to the authenticated operator, accepts the same filters as the DTE list plus user_id and branch_id
+// @Tags         Operators
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param user_id query int false "Limit to a taxpayer"
+// @Param branch_id query int false "Limit to a branch office"
+// @Success      200 {object} dte.DTEListResponse
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/operator/dte [get]
+func (h *OperatorHandler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	documents, err := h.operatorUseCase.ListDocuments(r.Context(), r)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, documents, nil)
+}
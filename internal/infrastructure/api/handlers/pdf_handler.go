@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type PDFHandler struct {
+	pdfUseCase *dte.DTEPDFUseCase
+	respWriter *response.ResponseWriter
+}
+
+func NewPDFHandler(pdfUseCase *dte.DTEPDFUseCase) *PDFHandler {
+	return &PDFHandler{
+		pdfUseCase: pdfUseCase,
+		respWriter: response.NewResponseWriter(),
+	}
+}
+
+// GetDTEPDF godoc
+// @Summary      Get DTE graphic representation
+// @Description  Generate the PDF graphic representation of a DTE by its generation code
+// @Tags         DTE
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Generation code of the DTE"
+// @Param receipt query string false "Receipt type: 'document' (default) or 'invalidation'"
+// @Param download query bool false "Send the PDF as an attachment"
+// @Success      200 {file} file
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/{id}/pdf [get]
+func (h *PDFHandler) GetDTEPDF(w http.ResponseWriter, r *http.Request) {
+	// 1. Obtener el código de generación y el tipo de comprobante
+	generationCode := helpers.GetRequestVar(r, "id")
+	receiptType := r.URL.Query().Get("receipt")
+
+	// 2. Generar el PDF ejecutando el caso de uso
+	content, filename, err := h.pdfUseCase.GeneratePDF(r.Context(), generationCode, receiptType)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	// 3. Escribir el PDF en la respuesta
+	disposition := "inline"
+	if r.URL.Query().Get("download") == "true" {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(content); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to write PDF response", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// GetBranding godoc
+// @Summary      Get branch branding
+// @Description  Get the branding configuration used in the graphic representation of the branch DTEs
+// @Tags         DTE
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Success      200 {object} models.Branding
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/pdf/branding [get]
+func (h *PDFHandler) GetBranding(w http.ResponseWriter, r *http.Request) {
+	branding, err := h.pdfUseCase.GetBranding(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, branding, nil)
+}
+
+// UpdateBranding godoc
+// @Summary      Update branch branding
+// @Description  Update the logo (base64 PNG or JPG), primary color and footer text used in the graphic representation
+// @Tags         DTE
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param request body structs.UpdateBrandingRequest true "Branding configuration"
+// @Success      200 {object} models.Branding
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/dte/pdf/branding [put]
+func (h *PDFHandler) UpdateBranding(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req structs.UpdateBrandingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Actualizar la configuración ejecutando el caso de uso
+	branding, err := h.pdfUseCase.UpdateBranding(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, branding, nil)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+)
+
+type RegistrationHandler struct {
+	registrationUseCase *auth.RegistrationUseCase
+	respWriter          *response.ResponseWriter
+}
+
+func NewRegistrationHandler(registrationUseCase *auth.RegistrationUseCase) *RegistrationHandler {
+	return &RegistrationHandler{
+		registrationUseCase: registrationUseCase,
+		respWriter:          response.NewResponseWriter(),
+	}
+}
+
+// ListPending godoc
+// @Summary      List pending registrations
+// @Description  List the taxpayer registrations pending of approval, requires the admin key or an administrator token
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Success      200 {object} []user.RegistrationSummary
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/registrations [get]
+func (h *RegistrationHandler) ListPending(w http.ResponseWriter, r *http.Request) {
+	registrations, err := h.registrationUseCase.ListPending(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, registrations, nil)
+}
+
+// Approve godoc
+// @Summary      Approve registration
+// @Description  Approve a pending taxpayer registration, the API keys and API secrets of its branches are returned only once
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param id path string true "Registration ID"
+// @Success      200 {object} []user.ListBranchesResponse
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/registrations/{id}/approve [post]
+func (h *RegistrationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	branches, err := h.registrationUseCase.Approve(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, branches, nil)
+}
+
+// Reject godoc
+// @Summary      Reject registration
+// @Description  Reject and remove a pending taxpayer registration
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param X-Admin-Key header string false "Admin API key"
+// @Param id path string true "Registration ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      401 {object} response.APIError
+// @Failure      403 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/admin/registrations/{id}/reject [post]
+func (h *RegistrationHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	if err := h.registrationUseCase.Reject(r.Context(), helpers.GetRequestVar(r, "id")); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Registration rejected successfully", nil)
+}
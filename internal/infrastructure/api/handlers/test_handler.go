@@ -32,7 +32,7 @@ func NewTestHandler(testManager test_endpoint.TestManager) *TestHandler {
 func (h *TestHandler) RunSystemTest(w http.ResponseWriter, r *http.Request) {
 	result, err := h.testManager.RunSystemTest()
 	if err != nil {
-		logs.Error("System test failed", map[string]interface{}{
+		logs.ErrorContext(r.Context(), "System test failed", map[string]interface{}{
 			"error": err.Error(),
 		})
 		h.responseWriter.Error(w, http.StatusInternalServerError, "System test failed", nil)
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/webhook"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/mapper/request_mapper/structs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type WebhookHandler struct {
+	webhookUseCase *webhook.WebhookUseCase
+	respWriter     *response.ResponseWriter
+}
+
+func NewWebhookHandler(webhookUseCase *webhook.WebhookUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+		respWriter:     response.NewResponseWriter(),
+	}
+}
+
+// CreateSubscription godoc
+// @Summary      Create webhook subscription
+// @Description  Subscribe a URL to the DTE lifecycle events of the taxpayer, the signing secret is returned only once. The URL must use https (http is accepted with DEBUG) and cannot target private, loopback, link-local or internal hosts
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param request body structs.CreateWebhookSubscriptionRequest true "Destination URL and events"
+// @Success      201 {object} models.SubscriptionCredentialsResponse
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	// 1. Decodificar la solicitud
+	var req structs.CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logs.ErrorContext(r.Context(), "Failed to decode request body", map[string]interface{}{"error": err.Error()})
+		h.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+
+	// 2. Crear la suscripción
+	subscription, err := h.webhookUseCase.CreateSubscription(r.Context(), &req)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusCreated, subscription, nil)
+}
+
+// ListSubscriptions godoc
+// @Summary      List webhook subscriptions
+// @Description  List the webhook subscriptions of the taxpayer, signing secrets are never returned
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Success      200 {object} []models.Subscription
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/subscriptions [get]
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookUseCase.ListSubscriptions(r.Context())
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, subscriptions, nil)
+}
+
+// DeleteSubscription godoc
+// @Summary      Delete webhook subscription
+// @Description  Delete a webhook subscription of the taxpayer, its pending deliveries are discarded
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Subscription ID"
+// @Success      200 {object} string
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/subscriptions/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhookUseCase.DeleteSubscription(r.Context(), helpers.GetRequestVar(r, "id")); err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, "Webhook subscription deleted successfully", nil)
+}
+
+// ListDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  List the webhook deliveries of the taxpayer from the most recent
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param subscription_id query int false "Subscription ID"
+// @Param status query string false "Delivery status: 'pending', 'delivered', 'dead'"
+// @Param event query string false "Event type, e.g. 'dte.issued'"
+// @Param limit query int false "Maximum number of deliveries, maximum 200"
+// @Success      200 {object} []models.Delivery
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.webhookUseCase.GetDeliveries(r.Context(), r)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, deliveries, nil)
+}
+
+// ListDeadLetters godoc
+// @Summary      List failed webhook deliveries
+// @Description  List the webhook deliveries of the taxpayer that exhausted their retries, they can be sent again with the redelivery endpoint
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param subscription_id query int false "Subscription ID"
+// @Param event query string false "Event type, e.g. 'dte.issued'"
+// @Param limit query int false "Maximum number of deliveries, maximum 200"
+// @Success      200 {object} []models.Delivery
+// @Failure      400 {object} response.APIError
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.webhookUseCase.GetDeadLetters(r.Context(), r)
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, deliveries, nil)
+}
+
+// GetDelivery godoc
+// @Summary      Get webhook delivery
+// @Description  Get a webhook delivery of the taxpayer with the log of its attempts
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Delivery ID"
+// @Success      200 {object} models.Delivery
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/deliveries/{id} [get]
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.webhookUseCase.GetDelivery(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusOK, delivery, nil)
+}
+
+// Redeliver godoc
+// @Summary      Redeliver webhook
+// @Description  Send a webhook delivery again immediately, resetting its attempts
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param Authorization header string true "Token JWT with Format 'Bearer {token}'"
+// @Param id path string true "Delivery ID"
+// @Success      202 {object} models.Delivery
+// @Failure      500 {object} response.APIError
+// @Router       /api/v1/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.webhookUseCase.Redeliver(r.Context(), helpers.GetRequestVar(r, "id"))
+	if err != nil {
+		h.respWriter.HandleError(w, err)
+		return
+	}
+
+	h.respWriter.Success(w, http.StatusAccepted, delivery, nil)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/contingency"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
+	healthConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/health/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/transmitter/hacienda_error"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
@@ -52,24 +54,24 @@ func NewContingencyHandler(
 func (ch *ContingencyHandler) HandleContingency(ctx context.Context,
 	document interface{}, dteType string, err error) (*int8, *string) {
 
-	logs.Info("Starting contingency handling", map[string]interface{}{
+	logs.InfoContext(ctx, "Starting contingency handling", map[string]interface{}{
 		"dteType": dteType,
 		"error":   err.Error(),
 	})
 
 	if !ch.shouldHandleAsContingency(err) {
-		logs.Info("Error does not require contingency handling")
+		logs.InfoContext(ctx, "Error does not require contingency handling")
 		return nil, nil
 	}
 
-	result := ch.classifyError(err)
+	result := ch.classifyError(ctx, err)
 
 	if err := ch.storeForContingency(ctx, document, dteType, result); err != nil {
-		logs.Error("CONTINGENCY SERVICE CRITICAL ERROR - Failed to store document in contingency", map[string]interface{}{"error": err.Error()})
+		logs.ErrorContext(ctx, "CONTINGENCY SERVICE CRITICAL ERROR - Failed to store document in contingency", map[string]interface{}{"error": err.Error()})
 		return nil, nil
 	}
 
-	logs.Info("Contingency handling finished", map[string]interface{}{"dteType": dteType, "contingencyType": result.ContingencyType})
+	logs.InfoContext(ctx, "Contingency handling finished", map[string]interface{}{"dteType": dteType, "contingencyType": result.ContingencyType})
 	return &result.ContingencyType, &result.ContingencyReason
 }
 
@@ -119,7 +121,7 @@ func (ch *ContingencyHandler) storeForContingency(
 	)
 
 	if err != nil {
-		logs.Error("Failed to store document in contingency", map[string]interface{}{
+		logs.ErrorContext(ctx, "Failed to store document in contingency", map[string]interface{}{
 			"error":           err.Error(),
 			"dteType":         dteType,
 			"contingencyType": result.ContingencyType,
@@ -127,7 +129,7 @@ func (ch *ContingencyHandler) storeForContingency(
 		return err
 	}
 
-	logs.Info("Document stored in contingency successfully", map[string]interface{}{
+	logs.InfoContext(ctx, "Document stored in contingency successfully", map[string]interface{}{
 		"dteType":         dteType,
 		"contingencyType": result.ContingencyType,
 	})
@@ -135,11 +137,17 @@ func (ch *ContingencyHandler) storeForContingency(
 	return nil
 }
 
-func (ch *ContingencyHandler) classifyError(err error) ContingencyResult {
+func (ch *ContingencyHandler) classifyError(ctx context.Context, err error) ContingencyResult {
+	// Dependencias caídas en modo degradado
+	var dependencyErr *health.DependencyUnavailableError
+	if errors.As(err, &dependencyErr) {
+		return ch.classifyDependencyError(dependencyErr)
+	}
+
 	// Errores de Hacienda
 	var haciendaErr *hacienda_error.HaciendaResponseError
 	if errors.As(err, &haciendaErr) {
-		return ch.classifyHaciendaError(haciendaErr)
+		return ch.classifyHaciendaError(ctx, haciendaErr)
 	}
 
 	// Errores de red
@@ -163,8 +171,28 @@ func (ch *ContingencyHandler) classifyError(err error) ContingencyResult {
 	return ch.defaultErrorClassification(err)
 }
 
-func (ch *ContingencyHandler) classifyHaciendaError(err *hacienda_error.HaciendaResponseError) ContingencyResult {
-	classification := ch.getHaciendaErrorClassification(err)
+// classifyDependencyError clasifica los documentos que no se transmitieron porque una dependencia estaba caída, la
+// caída de Hacienda es no disponibilidad del MH y la del firmador es una falla de los sistemas del emisor
+func (ch *ContingencyHandler) classifyDependencyError(err *health.DependencyUnavailableError) ContingencyResult {
+	contingencyType := int8(constants.FallaConexionSistema)
+	if err.Component == healthConstants.ComponentHacienda {
+		contingencyType = constants.NoDisponibilidadMH
+	}
+
+	return ContingencyResult{
+		ContingencyType:   contingencyType,
+		ContingencyReason: constants.GetContingencyReason(contingencyType),
+		ShouldRetry:       true,
+		RetryConfig: &RetryConfig{
+			MaxAttempts:     3,
+			InitialInterval: 5 * time.Minute,
+			MaxInterval:     30 * time.Minute,
+		},
+	}
+}
+
+func (ch *ContingencyHandler) classifyHaciendaError(ctx context.Context, err *hacienda_error.HaciendaResponseError) ContingencyResult {
+	classification := ch.getHaciendaErrorClassification(ctx, err)
 
 	return ContingencyResult{
 		ContingencyType:   classification.Type,
@@ -259,7 +287,7 @@ func (ch *ContingencyHandler) defaultErrorClassification(err error) ContingencyR
 	}
 }
 
-func (ch *ContingencyHandler) getHaciendaErrorClassification(err *hacienda_error.HaciendaResponseError) ErrClassification {
+func (ch *ContingencyHandler) getHaciendaErrorClassification(ctx context.Context, err *hacienda_error.HaciendaResponseError) ErrClassification {
 	switch err.StatusCode {
 	case http.StatusServiceUnavailable, http.StatusBadGateway:
 		return ErrClassification{
@@ -301,10 +329,10 @@ func (ch *ContingencyHandler) getHaciendaErrorClassification(err *hacienda_error
 		}
 	}
 
-	return ch.classifyByErrorDescription(err)
+	return ch.classifyByErrorDescription(ctx, err)
 }
 
-func (ch *ContingencyHandler) classifyByErrorDescription(err *hacienda_error.HaciendaResponseError) ErrClassification {
+func (ch *ContingencyHandler) classifyByErrorDescription(ctx context.Context, err *hacienda_error.HaciendaResponseError) ErrClassification {
 	errMsg := strings.ToLower(err.Description)
 
 	switch {
@@ -332,7 +360,7 @@ func (ch *ContingencyHandler) classifyByErrorDescription(err *hacienda_error.Hac
 		}
 	}
 
-	ch.logUnhandledError(err)
+	ch.logUnhandledError(ctx, err)
 
 	return ErrClassification{
 		Type:      constants.OtroMotivo,
@@ -356,8 +384,8 @@ func isContextError(err error) bool {
 		errors.Is(err, context.Canceled)
 }
 
-func (ch *ContingencyHandler) logUnhandledError(err *hacienda_error.HaciendaResponseError) {
-	logs.Warn("Unhandled Hacienda error", map[string]interface{}{
+func (ch *ContingencyHandler) logUnhandledError(ctx context.Context, err *hacienda_error.HaciendaResponseError) {
+	logs.WarnContext(ctx, "Unhandled Hacienda error", map[string]interface{}{
 		"statusCode": err.StatusCode,
 		"code":       err.Code,
 		"message":    err.Description,
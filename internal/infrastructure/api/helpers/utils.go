@@ -18,3 +18,30 @@ func GetRequestVar(r *http.Request, key string) string {
 	vars := mux.Vars(r)
 	return vars[key]
 }
+
+// VerifiedClientCert obtiene el subject y los SAN (DNS, correos y URIs) del certificado de cliente verificado por el
+// servidor, retorna false si la solicitud no presenta uno
+func VerifiedClientCert(r *http.Request) (string, []string, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", nil, false
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	sans := append(append([]string(nil), cert.DNSNames...), cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return cert.Subject.String(), sans, true
+}
+
+// ClientCertIdentities obtiene el subject y los SAN del certificado de cliente verificado de la solicitud, son los
+// valores que el cliente puede asignarse como client_cert_subject
+func ClientCertIdentities(r *http.Request) []string {
+	subject, sans, ok := VerifiedClientCert(r)
+	if !ok {
+		return nil
+	}
+
+	return append([]string{subject}, sans...)
+}
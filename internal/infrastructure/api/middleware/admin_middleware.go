@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+// AdminKeyHeader cabecera con la llave de administración configurada en ADMIN_API_KEY
+const AdminKeyHeader = "X-Admin-Key"
+
+type AdminMiddleware struct {
+	tokenService ports.TokenManager
+	authManager  auth.AuthManager
+	respWriter   *response.ResponseWriter
+}
+
+// NewAdminMiddleware crea una nueva instancia de AdminMiddleware. Recibe un servicio de tokens y el servicio de autenticación.
+func NewAdminMiddleware(tokenService ports.TokenManager, authManager auth.AuthManager) *AdminMiddleware {
+	return &AdminMiddleware{
+		tokenService: tokenService,
+		authManager:  authManager,
+		respWriter:   response.NewResponseWriter(),
+	}
+}
+
+// Handle es un middleware que permite el acceso solo a administradores. Un administrador se identifica con la llave
+// de administración en la cabecera X-Admin-Key o con un token de un usuario cuyo correo sea ADMIN_EMAIL.
+func (m *AdminMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 1. Validar la llave de administración si fue enviada
+		if adminKey := r.Header.Get(AdminKeyHeader); adminKey != "" {
+			if config.Server.AdminAPIKey == "" || subtle.ConstantTimeCompare([]byte(adminKey), []byte(config.Server.AdminAPIKey)) != 1 {
+				logs.WarnContext(r.Context(), "Invalid admin key", map[string]interface{}{
+					"path":   r.URL.Path,
+					"method": r.Method,
+				})
+				m.respWriter.Error(w, http.StatusUnauthorized, "Invalid admin key", nil)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "admin_key", true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		// 2. Validar el token del usuario administrador
+		parts := strings.Split(r.Header.Get("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			m.respWriter.Error(w, http.StatusUnauthorized, "Admin key or authorization header required", nil)
+			return
+		}
+
+		claims, err := m.tokenService.ValidateToken(r.Context(), parts[1])
+		if err != nil {
+			m.respWriter.Error(w, http.StatusUnauthorized, "error", []string{err.Error()})
+			return
+		}
+
+		admin, err := m.authManager.GetByNIT(r.Context(), claims.NIT)
+		if err != nil || config.Server.AdminEmail == "" || !strings.EqualFold(admin.Email, config.Server.AdminEmail) {
+			logs.WarnContext(r.Context(), "User is not an administrator", map[string]interface{}{
+				"userID": claims.ClientID,
+				"path":   r.URL.Path,
+				"method": r.Method,
+			})
+			m.respWriter.Error(w, http.StatusForbidden, "Administrator role required", nil)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "claims", claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -1,45 +1,65 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
 	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/helpers"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 )
 
+// Cabeceras de las solicitudes firmadas con HMAC
+const (
+	APIKeyHeader    = "X-API-Key"
+	TimestampHeader = "X-Timestamp"
+	SignatureHeader = "X-Signature"
+)
+
+// maxSignedBodySize tamaño máximo del cuerpo de una solicitud firmada con HMAC, el cuerpo se lee completo para
+// calcular su hash antes de autenticar la solicitud
+const maxSignedBodySize = 10 << 20
+
 type AuthMiddleware struct {
 	tokenService ports.TokenManager
+	authManager  auth.AuthManager
 	respWriter   *response.ResponseWriter
 }
 
-// NewAuthMiddleware crea una nueva instancia de AuthMiddleware. Recibe un servicio de tokens.
-func NewAuthMiddleware(tokenService ports.TokenManager) *AuthMiddleware {
+// NewAuthMiddleware crea una nueva instancia de AuthMiddleware. Recibe un servicio de tokens y el servicio de autenticación.
+func NewAuthMiddleware(tokenService ports.TokenManager, authManager auth.AuthManager) *AuthMiddleware {
 	return &AuthMiddleware{
 		tokenService: tokenService,
+		authManager:  authManager,
 		respWriter:   response.NewResponseWriter(),
 	}
 }
 
-// Handle es un middleware que valida el token de autorización.
+// Handle es un middleware que valida el token de autorización. Las solicitudes sin token se autentican con su firma
+// HMAC o con el certificado de cliente verificado por el servidor.
 func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			logs.Warn("Missing Authorization header", map[string]interface{}{
-				"path":   r.URL.Path,
-				"method": r.Method,
-			})
-			m.respWriter.Error(w, http.StatusUnauthorized, "Authorization header required", nil)
+			m.handleRequestAuth(w, r, next)
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			logs.Warn("Invalid Authorization header format", map[string]interface{}{
+			logs.WarnContext(r.Context(), "Invalid Authorization header format", map[string]interface{}{
 				"header": authHeader,
 				"path":   r.URL.Path,
 				"method": r.Method,
@@ -48,9 +68,9 @@ func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
-		claims, err := m.tokenService.ValidateToken(parts[1])
+		claims, err := m.tokenService.ValidateToken(r.Context(), parts[1])
 		if err != nil {
-			logs.Warn("Invalid token", map[string]interface{}{
+			logs.WarnContext(r.Context(), "Invalid token", map[string]interface{}{
 				"error":  err.Error(),
 				"path":   r.URL.Path,
 				"method": r.Method,
@@ -59,13 +79,101 @@ func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
-		logs.Info("Token validated successfully", map[string]interface{}{
+		logs.InfoContext(r.Context(), "Token validated successfully", map[string]interface{}{
 			"userID": claims.ClientID,
 			"path":   r.URL.Path,
 			"method": r.Method,
 		})
 
+		r = withUserLanguage(w, r, claims)
 		ctx := context.WithValue(r.Context(), "claims", claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// handleRequestAuth autentica una solicitud sin token. Las solicitudes que no incluyen firma ni certificado de cliente
+// se rechazan como antes de existir estos tipos de autenticación
+func (m *AuthMiddleware) handleRequestAuth(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	// 1. Obtener las credenciales de la solicitud
+	credentials, err := m.requestCredentials(w, r)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		logs.WarnContext(r.Context(), "Signed request body too large", map[string]interface{}{
+			"limit":  maxBytesErr.Limit,
+			"path":   r.URL.Path,
+			"method": r.Method,
+		})
+		m.respWriter.Error(w, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+		return
+	}
+	if err != nil {
+		logs.WarnContext(r.Context(), "Failed to read request body", map[string]interface{}{
+			"error":  err.Error(),
+			"path":   r.URL.Path,
+			"method": r.Method,
+		})
+		m.respWriter.Error(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+	if credentials == nil {
+		logs.WarnContext(r.Context(), "Missing Authorization header", map[string]interface{}{
+			"path":   r.URL.Path,
+			"method": r.Method,
+		})
+		m.respWriter.Error(w, http.StatusUnauthorized, "Authorization header required", nil)
+		return
+	}
+
+	// 2. Autenticar la solicitud
+	claims, err := m.authManager.AuthenticateRequest(r.Context(), credentials)
+	if err != nil {
+		logs.WarnContext(r.Context(), "Request authentication failed", map[string]interface{}{
+			"authType": credentials.AuthType,
+			"error":    err.Error(),
+			"path":     r.URL.Path,
+			"method":   r.Method,
+		})
+		m.respWriter.Error(w, http.StatusUnauthorized, "error", []string{err.Error()})
+		return
+	}
+
+	// 3. Las solicitudes sin token usan la llave del usuario para el token de Hacienda en caché
+	r = withUserLanguage(w, r, claims)
+	ctx := context.WithValue(r.Context(), "claims", claims)
+	ctx = context.WithValue(ctx, "token", vault.HaciendaTokenKey(claims.ClientID))
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// requestCredentials obtiene las credenciales de una solicitud sin token, retorna nil si la solicitud no incluye
+// firma HMAC ni certificado de cliente verificado
+func (m *AuthMiddleware) requestCredentials(w http.ResponseWriter, r *http.Request) (*models.RequestCredentials, error) {
+	if r.Header.Get(SignatureHeader) != "" {
+		// La firma cubre el hash del cuerpo, el cuerpo se restaura para los siguientes handlers
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		bodyHash := sha256.Sum256(body)
+
+		return &models.RequestCredentials{
+			AuthType:  constants.HMACAuthType,
+			APIKey:    r.Header.Get(APIKeyHeader),
+			Timestamp: r.Header.Get(TimestampHeader),
+			Signature: r.Header.Get(SignatureHeader),
+			Method:    r.Method,
+			Path:      r.URL.RequestURI(),
+			BodyHash:  hex.EncodeToString(bodyHash[:]),
+		}, nil
+	}
+
+	if subject, sans, ok := helpers.VerifiedClientCert(r); ok {
+		return &models.RequestCredentials{
+			AuthType:    constants.MTLSAuthType,
+			CertSubject: subject,
+			CertSANs:    sans,
+		}, nil
+	}
+
+	return nil, nil
+}
@@ -17,6 +17,12 @@ func (te *TokenExtractor) ExtractToken(next http.Handler) http.Handler {
 		authHeader := r.Header.Get("Authorization")
 		parts := strings.Split(authHeader, " ")
 
+		// Las solicitudes autenticadas sin token conservan la llave asignada por el AuthMiddleware
+		if len(parts) != 2 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Almacenar el token en el contexto
 		ctx := context.WithValue(r.Context(), "token", parts[1])
 		next.ServeHTTP(w, r.WithContext(ctx))
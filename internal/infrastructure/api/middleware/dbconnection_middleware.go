@@ -2,11 +2,19 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/MarlonG1/api-facturacion-sv/config/drivers"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
 )
 
+// probePaths rutas de las sondas de salud, no verifican la conexión a la base de datos para que la sonda de liveness
+// responda aunque la base de datos no esté disponible y la de readiness la verifique con su propio tiempo máximo
+var probePaths = map[string]bool{
+	"/api/v1/health/live":  true,
+	"/api/v1/health/ready": true,
+}
+
 type DBConnectionMiddleware struct {
 	connection *drivers.DbConnection
 }
@@ -17,10 +25,15 @@ func NewDBConnectionMiddleware(connection *drivers.DbConnection) *DBConnectionMi
 
 func (m *DBConnectionMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if probePaths[strings.TrimSuffix(r.URL.Path, "/")] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		sqlDB, err := m.connection.Db.DB()
 
 		if err != nil {
-			logs.Error("Error connecting to database", map[string]interface{}{
+			logs.ErrorContext(r.Context(), "Error connecting to database", map[string]interface{}{
 				"error": err.Error(),
 			})
 			http.Error(w, "Error connecting to database", http.StatusInternalServerError)
@@ -28,7 +41,7 @@ func (m *DBConnectionMiddleware) Handler(next http.Handler) http.Handler {
 		}
 		sqlDB.Ping()
 
-		logs.Debug("Database connection established")
+		logs.DebugContext(r.Context(), "Database connection established")
 		next.ServeHTTP(w, r)
 	})
 }
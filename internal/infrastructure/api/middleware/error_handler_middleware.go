@@ -28,7 +28,7 @@ func (m *ErrorMiddleware) Handler(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				stackTrace := string(debug.Stack())
-				logs.Error("Recovered from panic", map[string]interface{}{
+				logs.ErrorContext(r.Context(), "Recovered from panic", map[string]interface{}{
 					"error":      err,
 					"stackTrace": stackTrace,
 					"path":       r.URL.Path,
@@ -60,7 +60,7 @@ func (m *ErrorMiddleware) Handler(next http.Handler) http.Handler {
 				msg = http.StatusText(sw.status)
 			}
 
-			logs.Warn("Client error response", map[string]interface{}{
+			logs.WarnContext(r.Context(), "Client error response", map[string]interface{}{
 				"status":  sw.status,
 				"message": msg,
 				"path":    r.URL.Path,
@@ -68,7 +68,7 @@ func (m *ErrorMiddleware) Handler(next http.Handler) http.Handler {
 			})
 			m.responseWriter.Error(w, sw.status, msg, nil)
 		} else if sw.status >= 500 {
-			logs.Error("Server error response", map[string]interface{}{
+			logs.ErrorContext(r.Context(), "Server error response", map[string]interface{}{
 				"status": sw.status,
 				"path":   r.URL.Path,
 				"method": r.Method,
@@ -107,3 +107,8 @@ func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	}
 	return nil, nil, fmt.Errorf("hijacking not supported")
 }
+
+// Unwrap retorna el http.ResponseWriter original para que http.ResponseController pueda usar sus capacidades
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
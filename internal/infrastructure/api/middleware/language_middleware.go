@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/i18n"
+)
+
+type LanguageMiddleware struct{}
+
+func NewLanguageMiddleware() *LanguageMiddleware {
+	return &LanguageMiddleware{}
+}
+
+// Handler obtiene el idioma de la solicitud de la cabecera Accept-Language, si no incluye un idioma cargado se utiliza
+// APP_LANG. El idioma se almacena en el contexto y se indica en la cabecera Content-Language, de la que el
+// ResponseWriter lo obtiene para traducir los errores
+func (m *LanguageMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		lang := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+		if lang == "" {
+			lang = i18n.DefaultLanguage()
+		}
+
+		next.ServeHTTP(w, withLanguage(w, r, lang))
+	})
+}
+
+// withUserLanguage aplica el idioma por defecto del usuario autenticado cuando la solicitud no indica un idioma
+// cargado en Accept-Language
+func withUserLanguage(w http.ResponseWriter, r *http.Request, claims *models.AuthClaims) *http.Request {
+	if claims.Language == "" || !i18n.IsSupported(claims.Language) {
+		return r
+	}
+	if i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language")) != "" {
+		return r
+	}
+
+	return withLanguage(w, r, claims.Language)
+}
+
+// withLanguage almacena el idioma en el contexto de la solicitud y en la cabecera Content-Language de la respuesta
+func withLanguage(w http.ResponseWriter, r *http.Request, lang string) *http.Request {
+	w.Header().Set("Content-Language", lang)
+	return r.WithContext(i18n.WithLanguage(r.Context(), lang))
+}
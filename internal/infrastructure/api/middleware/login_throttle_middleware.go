@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+// maxLoginBodySize tamaño máximo del cuerpo de una solicitud de inicio de sesión
+const maxLoginBodySize = 64 << 10
+
+// loginSubject origen de los intentos de inicio de sesión, se limita de forma independiente por API key y por IP
+type loginSubject struct {
+	name        string
+	attemptsKey string
+	lockKey     string
+	maxAttempts int
+}
+
+type LoginThrottleMiddleware struct {
+	limiter    ports.RateLimiter
+	respWriter *response.ResponseWriter
+}
+
+// NewLoginThrottleMiddleware crea una nueva instancia de LoginThrottleMiddleware
+func NewLoginThrottleMiddleware(limiter ports.RateLimiter) *LoginThrottleMiddleware {
+	return &LoginThrottleMiddleware{
+		limiter:    limiter,
+		respWriter: response.NewResponseWriter(),
+	}
+}
+
+// Handle protege el inicio de sesión contra ataques de fuerza bruta. Los intentos fallidos se cuentan en una ventana
+// deslizante por API key y por IP, al alcanzar el máximo se bloquea el inicio de sesión de esa API key o IP durante
+// LOGIN_LOCKOUT_SECONDS y se responde con 429 y la cabecera Retry-After. Un inicio de sesión exitoso reinicia el
+// conteo de la API key. Si Redis no está disponible las solicitudes no se limitan.
+func (m *LoginThrottleMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// 1. Obtener la API key sin consumir el cuerpo de la solicitud, un cuerpo mayor al límite se rechaza en lugar de
+		// truncarse
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLoginBodySize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			logs.WarnContext(ctx, "Login request body too large", map[string]interface{}{
+				"limit": maxBytesErr.Limit,
+			})
+			m.respWriter.Error(w, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+			return
+		}
+		if err != nil {
+			m.respWriter.Error(w, http.StatusBadRequest, "Invalid request format", nil)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var credentials models.AuthCredentials
+		_ = json.Unmarshal(body, &credentials)
+		clientIP, _ := ctx.Value("client_ip").(string)
+		subjects := loginSubjects(credentials.APIKey, clientIP)
+
+		// 2. Rechazar la solicitud si la API key o la IP están bloqueadas
+		if retryAfter := m.lockedFor(ctx, subjects); retryAfter > 0 {
+			logs.WarnContext(r.Context(), "Login rejected, too many failed attempts", map[string]interface{}{
+				"ip":         clientIP,
+				"retryAfter": retryAfter.String(),
+			})
+			writeTooManyRequests(w, m.respWriter, retryAfter, "Too many failed login attempts, try again later")
+			return
+		}
+
+		// 3. Procesar el inicio de sesión capturando el código de estado
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next(sw, r)
+
+		// 4. Registrar el resultado, los errores del servidor no cuentan como intentos fallidos
+		switch {
+		case sw.status < http.StatusBadRequest:
+			if len(subjects) > 0 && subjects[0].name == "api_key" {
+				if err = m.limiter.Reset(ctx, subjects[0].attemptsKey); err != nil {
+					logs.WarnContext(r.Context(), "Failed to reset login attempts", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		case sw.status < http.StatusInternalServerError:
+			m.recordFailure(ctx, subjects, clientIP)
+		}
+	}
+}
+
+// loginSubjects obtiene los orígenes del intento de inicio de sesión que deben limitarse
+func loginSubjects(apiKey, clientIP string) []loginSubject {
+	subjects := make([]loginSubject, 0, 2)
+
+	if apiKey != "" {
+		subjects = append(subjects, loginSubject{
+			name:        "api_key",
+			attemptsKey: "ratelimit:login:attempts:key:" + apiKey,
+			lockKey:     "ratelimit:login:lock:key:" + apiKey,
+			maxAttempts: config.RateLimit.LoginMaxAttemptsPerKey,
+		})
+	}
+
+	if clientIP != "" {
+		subjects = append(subjects, loginSubject{
+			name:        "ip",
+			attemptsKey: "ratelimit:login:attempts:ip:" + clientIP,
+			lockKey:     "ratelimit:login:lock:ip:" + clientIP,
+			maxAttempts: config.RateLimit.LoginMaxAttemptsPerIP,
+		})
+	}
+
+	return subjects
+}
+
+// lockedFor retorna el mayor tiempo restante de bloqueo entre los orígenes del intento
+func (m *LoginThrottleMiddleware) lockedFor(ctx context.Context, subjects []loginSubject) time.Duration {
+	var retryAfter time.Duration
+
+	for _, subject := range subjects {
+		ttl, err := m.limiter.LockedFor(ctx, subject.lockKey)
+		if err != nil {
+			logs.WarnContext(ctx, "Failed to check login lockout", map[string]interface{}{"error": err.Error()})
+			continue
+		}
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	return retryAfter
+}
+
+// recordFailure registra un intento fallido para cada origen y bloquea los que alcanzaron el máximo de intentos
+func (m *LoginThrottleMiddleware) recordFailure(ctx context.Context, subjects []loginSubject, clientIP string) {
+	window := time.Duration(config.RateLimit.LoginAttemptWindow) * time.Second
+	lockout := time.Duration(config.RateLimit.LoginLockout) * time.Second
+
+	for _, subject := range subjects {
+		result, err := m.limiter.Allow(ctx, subject.attemptsKey, subject.maxAttempts, window)
+		if err != nil {
+			logs.WarnContext(ctx, "Failed to record failed login attempt", map[string]interface{}{"error": err.Error()})
+			continue
+		}
+		if result.Allowed && result.Remaining > 0 {
+			continue
+		}
+
+		// Al bloquear se reinicia el conteo para que el origen tenga todos sus intentos al terminar el bloqueo
+		if err = m.limiter.Lock(ctx, subject.lockKey, lockout); err != nil {
+			logs.WarnContext(ctx, "Failed to lock login", map[string]interface{}{"error": err.Error()})
+			continue
+		}
+		if err = m.limiter.Reset(ctx, subject.attemptsKey); err != nil {
+			logs.WarnContext(ctx, "Failed to reset login attempts", map[string]interface{}{"error": err.Error()})
+		}
+
+		logs.WarnContext(ctx, "Login locked after too many failed attempts", map[string]interface{}{
+			"subject":  subject.name,
+			"ip":       clientIP,
+			"attempts": subject.maxAttempts,
+			"lockout":  lockout.String(),
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
@@ -20,19 +20,8 @@ type MetricsMiddleware struct {
 	maxMetrics int
 }
 
-var (
-	uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
-
-	endpointMappings = map[string]string{
-		"GET:/api/v1/dte":               "dte",
-		"GET:/api/v1/dte/{id}":          "dte/{id}",
-		"POST:/api/v1/dte/invoices":     "invoices",
-		"POST:/api/v1/dte/ccf":          "ccf",
-		"POST:/api/v1/dte/invalidation": "invalidation",
-		"POST:/api/v1/dte/retention":    "retention",
-		"POST:/api/v1/dte/creditnote":   "creditnote",
-	}
-)
+// dteRoutePrefix prefijo de las rutas de DTE, sus endpoints se identifican sin el prefijo (por ejemplo "creditnote")
+const dteRoutePrefix = "/api/v1/dte"
 
 func NewMetricsMiddleware(cache ports.CacheManager) *MetricsMiddleware {
 	return &MetricsMiddleware{
@@ -41,48 +30,30 @@ func NewMetricsMiddleware(cache ports.CacheManager) *MetricsMiddleware {
 	}
 }
 
-func extractEndpoint(method, path string) string {
-	path = strings.TrimSuffix(path, "/")
-
-	// Primero intentar coincidencia directa
-	if endpoint, exists := endpointMappings[method+":"+path]; exists {
-		return endpoint
-	}
-
-	// Si no hay coincidencia directa, intentar con patrones
-	parts := strings.Split(strings.TrimPrefix(path, "/api/v1/dte/"), "/")
+// extractEndpoint obtiene el nombre del endpoint a partir de la plantilla de la ruta de mux, las rutas de DTE se
+// identifican sin su prefijo y las demás sin el prefijo de la API
+func extractEndpoint(r *http.Request) string {
+	template := strings.TrimSuffix(routeTemplate(r), "/")
 
-	if len(parts) == 0 || parts[0] == "" {
+	switch {
+	case template == dteRoutePrefix:
 		return "dte"
+	case strings.HasPrefix(template, dteRoutePrefix+"/{"):
+		return "dte/" + strings.TrimPrefix(template, dteRoutePrefix+"/")
+	case strings.HasPrefix(template, dteRoutePrefix+"/"):
+		return strings.TrimPrefix(template, dteRoutePrefix+"/")
 	}
 
-	// Verificar si el primer segmento es un UUID
-	if uuidRegex.MatchString(parts[0]) {
-		// Transformar la ruta reemplazando el UUID con {uuid}
-		templatePath := "/api/v1/dte/{id}"
-		if len(parts) > 1 {
-			templatePath += "/" + strings.Join(parts[1:], "/")
-		}
-
-		if endpoint, exists := endpointMappings[method+":"+templatePath]; exists {
-			return endpoint
-		}
-
-		if method == "GET" {
-			return "consult"
-		}
-	}
-
-	// Para rutas que no coinciden con ningún patrón específico, usar el primer segmento
-	if len(parts) > 0 && parts[0] != "" {
-		return parts[0]
-	}
-
-	return "dte"
+	return strings.TrimPrefix(template, "/api/v1/")
 }
 
 func (m *MetricsMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isStreamingRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		rw := &responseWriter{
 			ResponseWriter: w,
 			status:         http.StatusOK,
@@ -95,7 +66,8 @@ func (m *MetricsMiddleware) Handle(next http.Handler) http.Handler {
 		next.ServeHTTP(rw, r)
 		duration := time.Since(start)
 
-		endpoint := extractEndpoint(r.Method, r.URL.Path)
+		endpoint := extractEndpoint(r)
+		m.registerEndpoint(r.Context(), systemNIT, r.Method, endpoint)
 		durationsKey := fmt.Sprintf("metrics:%s:%s:%s:durations", systemNIT, r.Method, endpoint)
 		countersKey := fmt.Sprintf("metrics:%s:%s:%s:counters", systemNIT, r.Method, endpoint)
 
@@ -108,13 +80,13 @@ func (m *MetricsMiddleware) Handle(next http.Handler) http.Handler {
 		metricData, _ := json.Marshal(metric)
 
 		if err := m.cache.LPush(durationsKey, metricData); err != nil {
-			logs.Error("Failed to store metric", map[string]interface{}{
+			logs.ErrorContext(r.Context(), "Failed to store metric", map[string]interface{}{
 				"error": err.Error(),
 			})
 		}
 
 		if err := m.cache.LTrim(durationsKey, 0, 19); err != nil {
-			logs.Error("Failed to trim metrics", map[string]interface{}{
+			logs.ErrorContext(r.Context(), "Failed to trim metrics", map[string]interface{}{
 				"error": err.Error(),
 			})
 		}
@@ -134,7 +106,7 @@ func (m *MetricsMiddleware) Handle(next http.Handler) http.Handler {
 		// Guardar contadores actualizados
 		if countersData, err := json.Marshal(counters); err == nil {
 			if err := m.cache.Set(countersKey, countersData, 24*time.Hour); err != nil {
-				logs.Error("Failed to update counters", map[string]interface{}{
+				logs.ErrorContext(r.Context(), "Failed to update counters", map[string]interface{}{
 					"error": err.Error(),
 				})
 			}
@@ -142,6 +114,21 @@ func (m *MetricsMiddleware) Handle(next http.Handler) http.Handler {
 	})
 }
 
+// registerEndpoint agrega el endpoint al conjunto de endpoints con métricas del sistema, de este conjunto se obtienen
+// los endpoints consultados en el reporte de métricas
+func (m *MetricsMiddleware) registerEndpoint(ctx context.Context, systemNIT, method, endpoint string) {
+	key := fmt.Sprintf("metrics:%s:endpoints", systemNIT)
+	client := m.cache.GetRedisClient()
+
+	if err := client.SAdd(client.Context(), key, method+":"+endpoint).Err(); err != nil {
+		logs.ErrorContext(ctx, "Failed to register metrics endpoint", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	client.Expire(client.Context(), key, 24*time.Hour)
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	status  int
@@ -163,3 +150,8 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	}
 	return rw.ResponseWriter.Write(b)
 }
+
+// Unwrap retorna el http.ResponseWriter original para que http.ResponseController pueda usar sus capacidades
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
+)
+
+type PrometheusMiddleware struct{}
+
+func NewPrometheusMiddleware() *PrometheusMiddleware {
+	return &PrometheusMiddleware{}
+}
+
+// Handler registra la duración de cada solicitud etiquetada con la plantilla de la ruta de mux (por ejemplo
+// /api/v1/dte/{id}), las conexiones de streaming se excluyen porque su duración no representa latencia
+func (m *PrometheusMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isStreamingRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &responseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		start := time.Now()
+		next.ServeHTTP(rw, r)
+
+		metrics.ObserveHTTPRequest(r.Method, routeTemplate(r), rw.status, time.Since(start))
+	})
+}
+
+// routeTemplate obtiene la plantilla de la ruta que atendió la solicitud
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unmatched"
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return template
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+const (
+	// rateLimitWindow ventana de la cuota de solicitudes por sucursal
+	rateLimitWindow = time.Minute
+	// quotaCacheTTL tiempo durante el cual se reutiliza la cuota de una sucursal antes de volver a consultarla
+	quotaCacheTTL = time.Minute
+)
+
+// cachedQuota cuota de solicitudes por minuto de una sucursal
+type cachedQuota struct {
+	limit     int
+	expiresAt time.Time
+}
+
+type RateLimitMiddleware struct {
+	limiter     ports.RateLimiter
+	authManager auth.AuthManager
+	respWriter  *response.ResponseWriter
+	quotas      sync.Map
+}
+
+// NewRateLimitMiddleware crea una nueva instancia de RateLimitMiddleware
+func NewRateLimitMiddleware(limiter ports.RateLimiter, authManager auth.AuthManager) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter:     limiter,
+		authManager: authManager,
+		respWriter:  response.NewResponseWriter(),
+	}
+}
+
+// Limit envuelve un handler para aplicar la cuota de solicitudes por minuto de la sucursal del token. La cuota es la
+// configurada en la sucursal o DTE_REQUESTS_PER_MINUTE si no tiene una, una cuota de 0 deshabilita el límite. Al
+// superarla se responde con 429 y la cabecera Retry-After. Debe utilizarse en rutas protegidas por AuthMiddleware.
+func (m *RateLimitMiddleware) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value("claims").(*models.AuthClaims)
+		if !ok {
+			m.respWriter.Error(w, http.StatusUnauthorized, "Authorization header required", nil)
+			return
+		}
+
+		// 1. Obtener la cuota de la sucursal
+		limit := m.branchQuota(r.Context(), claims.BranchID)
+		if limit == 0 {
+			next(w, r)
+			return
+		}
+
+		// 2. Registrar la solicitud, si Redis no está disponible la solicitud no se limita
+		result, err := m.limiter.Allow(r.Context(), fmt.Sprintf("ratelimit:dte:branch:%d", claims.BranchID), limit, rateLimitWindow)
+		if err != nil {
+			logs.WarnContext(r.Context(), "Failed to evaluate branch rate limit", map[string]interface{}{
+				"branchID": claims.BranchID,
+				"error":    err.Error(),
+			})
+			next(w, r)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+		// 3. Rechazar la solicitud si se superó la cuota
+		if !result.Allowed {
+			logs.WarnContext(r.Context(), "Branch rate limit exceeded", map[string]interface{}{
+				"userID":     claims.ClientID,
+				"branchID":   claims.BranchID,
+				"limit":      result.Limit,
+				"path":       r.URL.Path,
+				"retryAfter": result.RetryAfter.String(),
+			})
+			writeTooManyRequests(w, m.respWriter, result.RetryAfter, "Rate limit exceeded for this branch office")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// branchQuota obtiene la cuota de solicitudes por minuto de una sucursal, si no puede consultarse se utiliza la cuota por defecto
+func (m *RateLimitMiddleware) branchQuota(ctx context.Context, branchID uint) int {
+	if cached, ok := m.quotas.Load(branchID); ok && time.Now().Before(cached.(cachedQuota).expiresAt) {
+		return cached.(cachedQuota).limit
+	}
+
+	limit := config.RateLimit.DTERequestsPerMinute
+	branch, err := m.authManager.GetBranchByBranchID(ctx, branchID)
+	if err != nil {
+		logs.WarnContext(ctx, "Failed to get branch rate limit, using default", map[string]interface{}{
+			"branchID": branchID,
+			"error":    err.Error(),
+		})
+		return limit
+	}
+
+	if branch.RateLimitPerMinute != nil {
+		limit = *branch.RateLimitPerMinute
+	}
+	m.quotas.Store(branchID, cachedQuota{limit: limit, expiresAt: time.Now().Add(quotaCacheTTL)})
+
+	return limit
+}
+
+// writeTooManyRequests responde con 429 indicando en la cabecera Retry-After los segundos que el cliente debe esperar
+func writeTooManyRequests(w http.ResponseWriter, respWriter *response.ResponseWriter, retryAfter time.Duration, message string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	respWriter.Error(w, http.StatusTooManyRequests, message, []string{fmt.Sprintf("retry after %d seconds", seconds)})
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+const (
+	// RequestIDHeader cabecera con el ID de la solicitud, si el cliente no la envía se genera uno nuevo
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength longitud máxima de un ID de solicitud enviado por el cliente
+	maxRequestIDLength = 64
+)
+
+type RequestContextMiddleware struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewRequestContextMiddleware crea una nueva instancia de RequestContextMiddleware con los proxies de confianza de
+// TRUSTED_PROXIES, sin proxies configurados se ignoran las cabeceras X-Forwarded-For y X-Real-IP
+func NewRequestContextMiddleware() *RequestContextMiddleware {
+	proxies, err := config.ParseTrustedProxies(config.Server.TrustedProxies)
+	if err != nil {
+		logs.Warn("Invalid trusted proxies, forwarded client IPs are ignored", map[string]interface{}{"error": err.Error()})
+	}
+
+	return &RequestContextMiddleware{trustedProxies: proxies}
+}
+
+// Handler almacena en el contexto el ID de la solicitud y la IP del cliente, se utilizan para identificar el origen
+// de cada acción en la auditoría. El ID de la solicitud se retorna en la cabecera X-Request-ID y se agrega a cada
+// entrada de log registrada con el contexto de la solicitud (logs.InfoContext, logs.ErrorContext, etc.)
+func (m *RequestContextMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 1. Obtener el ID de la solicitud, se descarta si excede la longitud máxima
+		requestID := strings.TrimSpace(r.Header.Get(RequestIDHeader))
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		// 2. Almacenar el ID de la solicitud y la IP del cliente en el contexto
+		ip := m.clientIP(r)
+		ctx := context.WithValue(r.Context(), "request_id", requestID)
+		ctx = context.WithValue(ctx, "client_ip", ip)
+
+		rw := &responseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		start := time.Now()
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		// 3. Registrar la solicitud atendida, la entrada incluye el ID de la solicitud y de la traza
+		logs.InfoContext(ctx, "Request completed", map[string]interface{}{
+			"method":      r.Method,
+			"route":       routeTemplate(r),
+			"status":      rw.status,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"client_ip":   ip,
+		})
+	})
+}
+
+// clientIP obtiene la IP del cliente. Las cabeceras X-Forwarded-For y X-Real-IP solo se aceptan si la conexión proviene
+// de un proxy de confianza, de X-Forwarded-For se utiliza la última IP que no pertenece a un proxy de confianza
+func (m *RequestContextMiddleware) clientIP(r *http.Request) string {
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIP = r.RemoteAddr
+	}
+
+	// 1. Las cabeceras de una conexión directa pueden ser falsificadas por el cliente
+	if !m.isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	// 2. Recorrer X-Forwarded-For desde el proxy más cercano, cada proxy de confianza agrega la IP que lo contactó
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if net.ParseIP(ip) == nil {
+				break
+			}
+			if !m.isTrustedProxy(ip) || i == 0 {
+				return ip
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remoteIP
+}
+
+// isTrustedProxy verifica si la IP pertenece a uno de los proxies de confianza
+func (m *RequestContextMiddleware) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, proxy := range m.trustedProxies {
+		if proxy.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
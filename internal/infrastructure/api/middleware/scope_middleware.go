@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+)
+
+type ScopeMiddleware struct {
+	respWriter *response.ResponseWriter
+}
+
+// NewScopeMiddleware crea una nueva instancia de ScopeMiddleware.
+func NewScopeMiddleware() *ScopeMiddleware {
+	return &ScopeMiddleware{
+		respWriter: response.NewResponseWriter(),
+	}
+}
+
+// Require envuelve un handler para que solo los tokens con el scope indicado puedan acceder a él. Debe utilizarse
+// en rutas protegidas por AuthMiddleware, que es quien agrega los claims al contexto.
+func (m *ScopeMiddleware) Require(scope string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value("claims").(*models.AuthClaims)
+		if !ok {
+			m.respWriter.Error(w, http.StatusUnauthorized, "Authorization header required", nil)
+			return
+		}
+
+		if !claims.HasScope(scope) {
+			logs.WarnContext(r.Context(), "Token does not have the required scope", map[string]interface{}{
+				"userID": claims.ClientID,
+				"keyID":  claims.KeyID,
+				"scope":  scope,
+				"path":   r.URL.Path,
+				"method": r.Method,
+			})
+			m.respWriter.Error(w, http.StatusForbidden, "Insufficient scope", []string{"required scope: " + scope})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
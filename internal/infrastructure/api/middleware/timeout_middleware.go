@@ -5,10 +5,51 @@ import (
 	"github.com/MarlonG1/api-facturacion-sv/internal/i18n"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/gorilla/mux"
 	"net/http"
+	"strings"
 	"time"
 )
 
+const (
+	// exportTimeout tiempo máximo de una exportación
+	exportTimeout = 10 * time.Minute
+	// streamingRoutePrefix prefijo del nombre de las rutas marcadas con StreamingRoute
+	streamingRoutePrefix = "streaming:"
+	// exportRoutePrefix prefijo del nombre de las rutas marcadas con ExportRoute
+	exportRoutePrefix = "export:"
+)
+
+// StreamingRoute marca una ruta que mantiene la conexión abierta, no tiene tiempo máximo ni registra métricas de
+// duración. La marca se guarda en el nombre de la ruta de mux, por lo que name debe ser único entre las rutas marcadas
+func StreamingRoute(route *mux.Route, name string) *mux.Route {
+	return route.Name(streamingRoutePrefix + name)
+}
+
+// ExportRoute marca una ruta de exportación que escribe la respuesta por bloques, tiene su propio tiempo máximo y el
+// handler renueva el tiempo de escritura del servidor con cada bloque enviado. La marca se guarda en el nombre de la
+// ruta de mux, por lo que name debe ser único entre las rutas marcadas
+func ExportRoute(route *mux.Route, name string) *mux.Route {
+	return route.Name(exportRoutePrefix + name)
+}
+
+// isStreamingRoute indica si la ruta fue marcada con StreamingRoute
+func isStreamingRoute(route *mux.Route) bool {
+	return route != nil && strings.HasPrefix(route.GetName(), streamingRoutePrefix)
+}
+
+// isStreamingRequest indica si la ruta que atiende la solicitud mantiene la conexión abierta, la ruta solo se conoce
+// después de que el router resolvió la solicitud
+func isStreamingRequest(r *http.Request) bool {
+	return isStreamingRoute(mux.CurrentRoute(r))
+}
+
+// isExportRequest indica si la ruta que atiende la solicitud fue marcada con ExportRoute
+func isExportRequest(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	return route != nil && strings.HasPrefix(route.GetName(), exportRoutePrefix)
+}
+
 type TimeoutMiddleware struct {
 	responseWriter *response.ResponseWriter
 }
@@ -21,6 +62,21 @@ func NewTimeoutMiddleware() *TimeoutMiddleware {
 
 func (m *TimeoutMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isStreamingRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Las exportaciones se ejecutan en la misma goroutine, una vez enviados los encabezados no se puede responder con
+		// el error de tiempo agotado y la cancelación del contexto detiene la consulta de los registros
+		if isExportRequest(r) {
+			ctx, cancel := context.WithTimeout(r.Context(), exportTimeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(r.Context(), 14*time.Second)
 		defer cancel()
 
@@ -41,10 +97,11 @@ func (m *TimeoutMiddleware) Handler(next http.Handler) http.Handler {
 		case <-done:
 			return
 		case <-ctx.Done():
-			timeoutTitle := i18n.TranslateServiceArgs("RequestTimeOutTitle")
-			timeoutMessage := i18n.TranslateServiceArgs("RequestTimeOut")
+			lang := i18n.LanguageFromContext(r.Context())
+			timeoutTitle := i18n.TranslateServiceArgsIn(lang, "RequestTimeOutTitle")
+			timeoutMessage := i18n.TranslateServiceArgsIn(lang, "RequestTimeOut")
 
-			logs.Warn("Request timed out", map[string]interface{}{
+			logs.WarnContext(r.Context(), "Request timed out", map[string]interface{}{
 				"method":              r.Method,
 				"path":                r.URL.Path,
 				"error":               ctx.Err(),
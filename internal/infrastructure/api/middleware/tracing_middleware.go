@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracingMiddleware struct{}
+
+func NewTracingMiddleware() *TracingMiddleware {
+	return &TracingMiddleware{}
+}
+
+// Wrap crea el span raíz de cada solicitud, continúa la traza si la solicitud incluye el encabezado traceparent. Se
+// aplica una sola vez sobre el router completo; las conexiones de streaming no se trazan porque permanecen abiertas
+// durante horas. El span se crea antes de resolver la ruta, por lo que el filtro la resuelve con el router
+func (m *TracingMiddleware) Wrap(router *mux.Router) http.Handler {
+	return otelhttp.NewHandler(router, "http.request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			var match mux.RouteMatch
+			return !router.Match(r, &match) || !isStreamingRoute(match.Route)
+		}),
+	)
+}
+
+// Handler renombra el span de la solicitud con la plantilla de la ruta de mux (por ejemplo "POST /api/v1/dte/ccf"),
+// la ruta solo se conoce después de que el router resolvió la solicitud
+func (m *TracingMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(attribute.String("http.route", route))
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -69,21 +69,27 @@ func (w *ResponseWriter) Error(rw http.ResponseWriter, status int, message strin
 }
 
 // HandleError maneja los diferentes tipos de errores y envía una respuesta de error con el código de estado y el mensaje correspondiente.
+// La entrada de log incluye el ID de la solicitud que el RequestContextMiddleware indica en la cabecera X-Request-ID
 func (w *ResponseWriter) HandleError(rw http.ResponseWriter, err error) {
 	rw.Header().Set("Content-Type", "application/json")
 
-	logs.Error("Error processing request", map[string]interface{}{
+	fields := map[string]interface{}{
 		"error_type": getErrorType(err),
 		"error":      err.Error(),
-	})
+	}
+	if requestID := rw.Header().Get("X-Request-ID"); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	logs.Error("Error processing request", fields)
 
+	lang := responseLanguage(rw)
 	switch errorType := getErrorType(err); errorType {
 	case errorValidation:
-		w.handleValidationError(rw, err)
+		w.handleValidationError(rw, err, lang)
 	case errorBusiness:
-		w.handleBusinessError(rw, err)
+		w.handleBusinessError(rw, err, lang)
 	default:
-		w.handleSystemError(rw, err)
+		w.handleSystemError(rw, err, lang)
 	}
 }
 
@@ -94,15 +100,15 @@ func GenerateQRLink(ambiente, codGeneracion string, fechaEmision time.Time) stri
 }
 
 // handleValidationError maneja los errores de validación y envía una respuesta de error con el código de estado y el mensaje correspondiente.
-func (w *ResponseWriter) handleValidationError(rw http.ResponseWriter, err error) {
+func (w *ResponseWriter) handleValidationError(rw http.ResponseWriter, err error, lang string) {
 	var dteErr *dte_errors.DTEError
 	if errors.As(err, &dteErr) {
 		rw.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(rw).Encode(APIResponse{
 			Success: false,
 			Error: &APIError{
-				Message: dteErr.GetMessage(),
-				Details: dteErr.GetValidationErrorsString(),
+				Message: dteErr.GetMessageIn(lang),
+				Details: dteErr.GetValidationErrorsStringIn(lang),
 				Code:    dteErr.GetCode(),
 			},
 		})
@@ -115,8 +121,8 @@ func (w *ResponseWriter) handleValidationError(rw http.ResponseWriter, err error
 		json.NewEncoder(rw).Encode(APIResponse{
 			Success: false,
 			Error: &APIError{
-				Message: validationErr.Error(),
-				Details: []string{i18n.Translate("service_errors.NoDetailsAvailable")},
+				Message: validationErr.Localize(lang),
+				Details: []string{i18n.TranslateIn(lang, "service_errors.NoDetailsAvailable")},
 				Code:    strings.ToUpper(validationErr.GetType()),
 			},
 		})
@@ -124,7 +130,7 @@ func (w *ResponseWriter) handleValidationError(rw http.ResponseWriter, err error
 }
 
 // handleBusinessError maneja los errores de negocio y envía una respuesta de error con el código de estado y el mensaje correspondiente.
-func (w *ResponseWriter) handleBusinessError(rw http.ResponseWriter, err error) {
+func (w *ResponseWriter) handleBusinessError(rw http.ResponseWriter, err error, lang string) {
 	var haciendaErr *hacienda_error.HaciendaResponseError
 	if errors.As(err, &haciendaErr) {
 		details := []string{
@@ -155,8 +161,8 @@ func (w *ResponseWriter) handleBusinessError(rw http.ResponseWriter, err error)
 		json.NewEncoder(rw).Encode(APIResponse{
 			Success: false,
 			Error: &APIError{
-				Message: svcErr.Message,
-				Details: svcErr.GetErrError(),
+				Message: svcErr.Localize(lang),
+				Details: svcErr.GetErrErrorIn(lang),
 				Code:    strings.ToUpper(svcErr.GetCode()),
 			},
 		})
@@ -166,7 +172,7 @@ func (w *ResponseWriter) handleBusinessError(rw http.ResponseWriter, err error)
 	var httpErr *hacienda_error.HTTPResponseError
 	if errors.As(err, &httpErr) {
 		rw.WriteHeader(httpErr.StatusCode)
-		detail := i18n.Translate("service_errors.ContingencyActiveTransmission")
+		detail := i18n.TranslateIn(lang, "service_errors.ContingencyActiveTransmission")
 		json.NewEncoder(rw).Encode(APIResponse{
 			Success: false,
 			Error: &APIError{
@@ -190,9 +196,9 @@ func (w *ResponseWriter) handleBusinessError(rw http.ResponseWriter, err error)
 }
 
 // handleSystemError maneja los errores de sistema y envía una respuesta de error con el código de estado y el mensaje correspondiente.
-func (w *ResponseWriter) handleSystemError(rw http.ResponseWriter, err error) {
+func (w *ResponseWriter) handleSystemError(rw http.ResponseWriter, err error, lang string) {
 	rw.WriteHeader(http.StatusInternalServerError)
-	message := i18n.Translate("validation_errors.ServerError")
+	message := i18n.TranslateIn(lang, "validation_errors.ServerError")
 	json.NewEncoder(rw).Encode(APIResponse{
 		Success: false,
 		Error: &APIError{
@@ -202,6 +208,15 @@ func (w *ResponseWriter) handleSystemError(rw http.ResponseWriter, err error) {
 	})
 }
 
+// responseLanguage obtiene el idioma de la respuesta, el LanguageMiddleware lo indica en la cabecera Content-Language
+func responseLanguage(rw http.ResponseWriter) string {
+	if lang := rw.Header().Get("Content-Language"); lang != "" {
+		return lang
+	}
+
+	return i18n.DefaultLanguage()
+}
+
 // deriveErrorCode deriva el código de error de acuerdo al estado y mensaje proporcionado.
 func deriveErrorCode(status int) string {
 	switch status {
@@ -219,6 +234,8 @@ func deriveErrorCode(status int) string {
 		return "INTERNAL_SERVER_ERROR"
 	case http.StatusRequestTimeout:
 		return "REQUEST_TIMEOUT"
+	case http.StatusTooManyRequests:
+		return "TOO_MANY_REQUESTS"
 	default:
 		return "UNKNOWN_ERROR"
 	}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterActivityRoutes(r *mux.Router, h *handlers.ActivityHandler, scopes *middleware.ScopeMiddleware) {
+	// Stream de actividad de la sucursal en tiempo real
+	middleware.StreamingRoute(r.Handle("/events/stream", scopes.Require(constants.ScopeDTERead, h.Stream)).Methods(http.MethodGet), "events")
+}
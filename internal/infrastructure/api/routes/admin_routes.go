@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterAdminRoutes(r *mux.Router, h *handlers.RegistrationHandler, audit *handlers.AuditHandler, operators *handlers.OperatorHandler, notifications *handlers.NotificationHandler) {
+	// Rutas de aprobación de registros
+	r.HandleFunc("/registrations", h.ListPending).Methods(http.MethodGet)
+	r.HandleFunc("/registrations/{id}/approve", h.Approve).Methods(http.MethodPost)
+	r.HandleFunc("/registrations/{id}/reject", h.Reject).Methods(http.MethodPost)
+
+	// Rutas de consulta de la auditoría
+	r.HandleFunc("/audit", audit.List).Methods(http.MethodGet)
+	middleware.ExportRoute(r.HandleFunc("/audit/export", audit.Export).Methods(http.MethodGet), "audit")
+
+	// Rutas de administración de operadores
+	r.HandleFunc("/operators", operators.List).Methods(http.MethodGet)
+	r.HandleFunc("/operators", operators.Create).Methods(http.MethodPost)
+	r.HandleFunc("/operators/{id}/grants", operators.Grant).Methods(http.MethodPost)
+	r.HandleFunc("/operators/{id}/grants/{grantId}/revoke", operators.RevokeGrant).Methods(http.MethodPost)
+	r.HandleFunc("/operators/{id}/deactivate", operators.Deactivate).Methods(http.MethodPost)
+
+	// Rutas de destinatarios de las alertas de la administración
+	r.HandleFunc("/notifications/recipients", notifications.ListAdminRecipients).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/recipients", notifications.CreateAdminRecipient).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/recipients/{id}", notifications.DeleteAdminRecipient).Methods(http.MethodDelete)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterArchiveRoutes(r *mux.Router, h *handlers.ArchiveHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de archivos de exportación de DTE
+	r.Handle("/dte/archives", scopes.Require(constants.ScopeReports, h.CreateArchive)).Methods(http.MethodPost)
+	r.Handle("/dte/archives/{id}", scopes.Require(constants.ScopeReports, h.GetArchive)).Methods(http.MethodGet)
+	r.Handle("/dte/archives/{id}/download", scopes.Require(constants.ScopeReports, h.DownloadArchive)).Methods(http.MethodGet)
+}
@@ -1,11 +1,22 @@
 package routes
 
 import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
 	"github.com/gorilla/mux"
 )
 
-func RegisterPublicAuthRoutes(r *mux.Router, h *handlers.AuthHandler) {
+func RegisterPublicAuthRoutes(r *mux.Router, h *handlers.AuthHandler, throttle *middleware.LoginThrottleMiddleware) {
 	r.HandleFunc("/auth/register", h.Register).Methods("POST")
-	r.HandleFunc("/auth/login", h.Login).Methods("POST")
+	r.HandleFunc("/auth/login", throttle.Handle(h.Login)).Methods("POST")
+	r.HandleFunc("/auth/token", throttle.Handle(h.Token)).Methods("POST")
+	r.HandleFunc("/auth/refresh", h.Refresh).Methods("POST")
+}
+
+func RegisterAuthRoutes(r *mux.Router, h *handlers.AuthHandler, scopes *middleware.ScopeMiddleware) {
+	r.HandleFunc("/auth/logout", h.Logout).Methods("POST")
+	r.Handle("/auth/hacienda-credentials", scopes.Require(constants.ScopeAdmin, h.StoreHaciendaCredentials)).Methods(http.MethodPut)
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterBranchRoutes(r *mux.Router, h *handlers.BranchHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de administración de sucursales
+	r.Handle("/branches", scopes.Require(constants.ScopeAdmin, h.ListBranches)).Methods(http.MethodGet)
+	r.Handle("/branches", scopes.Require(constants.ScopeAdmin, h.CreateBranch)).Methods(http.MethodPost)
+	r.Handle("/branches/{id}", scopes.Require(constants.ScopeAdmin, h.UpdateBranch)).Methods(http.MethodPut)
+	r.Handle("/branches/{id}/deactivate", scopes.Require(constants.ScopeAdmin, h.DeactivateBranch)).Methods(http.MethodPost)
+	r.Handle("/branches/{id}/rotate-keys", scopes.Require(constants.ScopeAdmin, h.RotateBranchKeys)).Methods(http.MethodPost)
+
+	// Rutas de llaves de acceso adicionales de las sucursales
+	r.Handle("/branches/{id}/api-keys", scopes.Require(constants.ScopeAdmin, h.ListAPIKeys)).Methods(http.MethodGet)
+	r.Handle("/branches/{id}/api-keys", scopes.Require(constants.ScopeAdmin, h.CreateAPIKey)).Methods(http.MethodPost)
+	r.Handle("/branches/{id}/api-keys/{keyId}/revoke", scopes.Require(constants.ScopeAdmin, h.RevokeAPIKey)).Methods(http.MethodPost)
+}
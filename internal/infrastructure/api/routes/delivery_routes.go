@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterDeliveryRoutes(r *mux.Router, h *handlers.DeliveryHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de envío de DTE por correo electrónico
+	r.Handle("/dte/{id}/email", scopes.Require(constants.ScopeDTEIssue, h.ResendDTE)).Methods(http.MethodPost)
+	r.Handle("/dte/{id}/email", scopes.Require(constants.ScopeDTERead, h.GetDeliveries)).Methods(http.MethodGet)
+}
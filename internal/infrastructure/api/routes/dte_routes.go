@@ -1,20 +1,23 @@
 package routes
 
 import (
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
 
 	"github.com/gorilla/mux"
 	"net/http"
 )
 
-func RegisterDTERoutes(r *mux.Router, h *handlers.DTEHandler) {
-	// Rutas para manejo de DTE
-	for path, _ := range h.GenericHandler.GetDocumentConfigs() {
-		r.HandleFunc(path, h.GenericHandler.HandleCreate).Methods(http.MethodPost)
+func RegisterDTERoutes(r *mux.Router, h *handlers.DTEHandler, scopes *middleware.ScopeMiddleware, limits *middleware.RateLimitMiddleware) {
+	// Rutas para manejo de DTE, todas comparten la cuota de solicitudes de la sucursal
+	for path := range h.GenericHandler.GetDocumentConfigs() {
+		r.Handle(path, scopes.Require(constants.ScopeDTEIssue, limits.Limit(h.GenericHandler.HandleCreate))).Methods(http.MethodPost)
 	}
 
 	// Rutas de consulta de DTE e Invalidación
-	r.HandleFunc("/dte/invalidation", h.InvalidateDocument).Methods(http.MethodPost)
-	r.HandleFunc("/dte/{id}", h.GetByGenerationCode).Methods(http.MethodGet)
-	r.HandleFunc("/dte", h.GetAll).Methods(http.MethodGet)
+	r.Handle("/dte/invalidation", scopes.Require(constants.ScopeDTEInvalidate, limits.Limit(h.InvalidateDocument))).Methods(http.MethodPost)
+	middleware.ExportRoute(r.Handle("/dte/export", scopes.Require(constants.ScopeDTERead, limits.Limit(h.Export))).Methods(http.MethodGet), "dte")
+	r.Handle("/dte/{id}", scopes.Require(constants.ScopeDTERead, limits.Limit(h.GetByGenerationCode))).Methods(http.MethodGet)
+	r.Handle("/dte", scopes.Require(constants.ScopeDTERead, limits.Limit(h.GetAll))).Methods(http.MethodGet)
 }
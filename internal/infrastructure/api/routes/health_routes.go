@@ -8,4 +8,6 @@ import (
 // RegisterHealthRoutes registra las rutas de salud en el router
 func RegisterHealthRoutes(router *mux.Router, healthHandler *handlers.HealthHandler) {
 	router.HandleFunc("/health", healthHandler.CheckHealth).Methods("GET")
+	router.HandleFunc("/health/live", healthHandler.CheckLiveness).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.CheckReadiness).Methods("GET")
 }
@@ -1,10 +1,21 @@
 package routes
 
 import (
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
 	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
 	"github.com/gorilla/mux"
 )
 
-func RegisterMetricsRoutes(router *mux.Router, handler *handlers.MetricsHandler) {
-	router.HandleFunc("/metrics", handler.GetEndpointMetrics).Methods("GET")
+func RegisterMetricsRoutes(router *mux.Router, handler *handlers.MetricsHandler, scopes *middleware.ScopeMiddleware) {
+	router.Handle("/metrics", scopes.Require(constants.ScopeReports, handler.GetEndpointMetrics)).Methods("GET")
+	router.Handle("/metrics/business", scopes.Require(constants.ScopeReports, handler.GetBusinessMetrics)).Methods("GET")
+}
+
+// RegisterPrometheusRoutes registra el endpoint /metrics en la raíz del servidor para el scrape de Prometheus, se
+// protege con METRICS_TOKEN si está configurado
+func RegisterPrometheusRoutes(router *mux.Router) {
+	router.Handle("/metrics", metrics.PrometheusHandler(config.Metrics.Token)).Methods("GET")
 }
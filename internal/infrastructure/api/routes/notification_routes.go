@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterNotificationRoutes(r *mux.Router, h *handlers.NotificationHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de consulta de las alertas
+	r.Handle("/notifications", scopes.Require(constants.ScopeAdmin, h.ListNotifications)).Methods(http.MethodGet)
+
+	// Rutas de destinatarios de las alertas
+	r.Handle("/notifications/recipients", scopes.Require(constants.ScopeAdmin, h.ListRecipients)).Methods(http.MethodGet)
+	r.Handle("/notifications/recipients", scopes.Require(constants.ScopeAdmin, h.CreateRecipient)).Methods(http.MethodPost)
+	r.Handle("/notifications/recipients/{id}", scopes.Require(constants.ScopeAdmin, h.DeleteRecipient)).Methods(http.MethodDelete)
+
+	// Ruta del vencimiento del certificado de firma
+	r.Handle("/notifications/certificate", scopes.Require(constants.ScopeAdmin, h.SetCertificateExpiry)).Methods(http.MethodPut)
+}
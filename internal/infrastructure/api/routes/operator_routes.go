@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterPublicOperatorRoutes(r *mux.Router, h *handlers.OperatorHandler, throttle *middleware.LoginThrottleMiddleware) {
+	r.HandleFunc("/operator/login", throttle.Handle(h.Login)).Methods(http.MethodPost)
+}
+
+func RegisterOperatorRoutes(r *mux.Router, h *handlers.OperatorHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de los operadores, solo aceptan tokens de operador
+	r.Handle("/operator/branches", scopes.Require(constants.ScopeOperator, h.ManagedBranches)).Methods(http.MethodGet)
+	r.Handle("/operator/switch", scopes.Require(constants.ScopeOperator, h.SwitchIssuer)).Methods(http.MethodPost)
+	r.Handle("/operator/dte", scopes.Require(constants.ScopeOperator, h.ListDocuments)).Methods(http.MethodGet)
+}
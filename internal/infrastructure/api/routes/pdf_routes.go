@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterPDFRoutes(r *mux.Router, h *handlers.PDFHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de configuración de marca de la representación gráfica
+	r.Handle("/dte/pdf/branding", scopes.Require(constants.ScopeDTERead, h.GetBranding)).Methods(http.MethodGet)
+	r.Handle("/dte/pdf/branding", scopes.Require(constants.ScopeAdmin, h.UpdateBranding)).Methods(http.MethodPut)
+
+	// Ruta de representación gráfica de un DTE
+	r.Handle("/dte/{id}/pdf", scopes.Require(constants.ScopeDTERead, h.GetDTEPDF)).Methods(http.MethodGet)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/gorilla/mux"
+)
+
+func RegisterWebhookRoutes(r *mux.Router, h *handlers.WebhookHandler, scopes *middleware.ScopeMiddleware) {
+	// Rutas de suscripciones de webhooks
+	r.Handle("/webhooks/subscriptions", scopes.Require(constants.ScopeAdmin, h.ListSubscriptions)).Methods(http.MethodGet)
+	r.Handle("/webhooks/subscriptions", scopes.Require(constants.ScopeAdmin, h.CreateSubscription)).Methods(http.MethodPost)
+	r.Handle("/webhooks/subscriptions/{id}", scopes.Require(constants.ScopeAdmin, h.DeleteSubscription)).Methods(http.MethodDelete)
+
+	// Rutas de entregas de webhooks
+	r.Handle("/webhooks/deliveries", scopes.Require(constants.ScopeAdmin, h.ListDeliveries)).Methods(http.MethodGet)
+	r.Handle("/webhooks/dead-letters", scopes.Require(constants.ScopeAdmin, h.ListDeadLetters)).Methods(http.MethodGet)
+	r.Handle("/webhooks/deliveries/{id}", scopes.Require(constants.ScopeAdmin, h.GetDelivery)).Methods(http.MethodGet)
+	r.Handle("/webhooks/deliveries/{id}/redeliver", scopes.Require(constants.ScopeAdmin, h.Redeliver)).Methods(http.MethodPost)
+}
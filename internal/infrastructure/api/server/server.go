@@ -2,8 +2,12 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/MarlonG1/api-facturacion-sv/config"
@@ -37,13 +41,17 @@ func (s *Server) ConfigureRoutes() {
 	s.configureGlobalOptions()
 
 	// Configurar rutas públicas y protegidas
+	admin := s.router.PathPrefix(s.privatePath + "/admin").Subrouter()
 	public := s.router.PathPrefix(s.publicPath).Subrouter()
 	protected := s.router.PathPrefix(s.privatePath).Subrouter()
 	s.configureProtectedMiddlewares(protected)
+	admin.Use(s.container.Middleware().AdminMiddleware().Handle)
 
 	s.router.Use(s.container.Middleware().DBConnectionMiddleware().Handler)
 	s.configurePublicRoutes(public)
 	s.configureProtectedRoutes(protected)
+	routes.RegisterPrometheusRoutes(s.router)
+	routes.RegisterAdminRoutes(admin, s.container.Handlers().RegistrationHandler(), s.container.Handlers().AuditHandler(), s.container.Handlers().OperatorHandler(), s.container.Handlers().NotificationHandler())
 
 	logs.Info("Routes configured successfully", map[string]interface{}{
 		"publicPath":    "/api/v1",
@@ -52,8 +60,19 @@ func (s *Server) ConfigureRoutes() {
 }
 
 func (s *Server) configureProtectedRoutes(protected *mux.Router) {
-	routes.RegisterDTERoutes(protected, s.container.Handlers().DTEHandler())
-	routes.RegisterMetricsRoutes(protected, s.container.Handlers().MetricsHandler())
+	scopes := s.container.Middleware().ScopeMiddleware()
+
+	routes.RegisterAuthRoutes(protected, s.container.Handlers().AuthHandler(), scopes)
+	routes.RegisterBranchRoutes(protected, s.container.Handlers().BranchHandler(), scopes)
+	routes.RegisterPDFRoutes(protected, s.container.Handlers().PDFHandler(), scopes)
+	routes.RegisterDeliveryRoutes(protected, s.container.Handlers().DeliveryHandler(), scopes)
+	routes.RegisterArchiveRoutes(protected, s.container.Handlers().ArchiveHandler(), scopes)
+	routes.RegisterWebhookRoutes(protected, s.container.Handlers().WebhookHandler(), scopes)
+	routes.RegisterActivityRoutes(protected, s.container.Handlers().ActivityHandler(), scopes)
+	routes.RegisterNotificationRoutes(protected, s.container.Handlers().NotificationHandler(), scopes)
+	routes.RegisterDTERoutes(protected, s.container.Handlers().DTEHandler(), scopes, s.container.Middleware().RateLimitMiddleware())
+	routes.RegisterMetricsRoutes(protected, s.container.Handlers().MetricsHandler(), scopes)
+	routes.RegisterOperatorRoutes(protected, s.container.Handlers().OperatorHandler(), scopes)
 }
 
 func (s *Server) configureGlobalOptions() {
@@ -63,13 +82,18 @@ func (s *Server) configureGlobalOptions() {
 }
 
 func (s *Server) configurePublicRoutes(public *mux.Router) {
-	routes.RegisterPublicAuthRoutes(public, s.container.Handlers().AuthHandler())
+	routes.RegisterPublicAuthRoutes(public, s.container.Handlers().AuthHandler(), s.container.Middleware().LoginThrottleMiddleware())
+	routes.RegisterPublicOperatorRoutes(public, s.container.Handlers().OperatorHandler(), s.container.Middleware().LoginThrottleMiddleware())
 	routes.RegisterHealthRoutes(public, s.container.Handlers().HealthHandler())
 	routes.RegisterTestRoutes(public, s.container.Handlers().TestHandler())
 }
 
 func (s *Server) configureGlobalMiddlewares() {
+	s.router.Use(s.container.Middleware().TracingMiddleware().Handler)
+	s.router.Use(s.container.Middleware().PrometheusMiddleware().Handler)
 	s.router.Use(s.container.Middleware().CorsMiddleware().Handler)
+	s.router.Use(s.container.Middleware().RequestContextMiddleware().Handler)
+	s.router.Use(s.container.Middleware().LanguageMiddleware().Handler)
 	s.router.Use(s.container.Middleware().ErrorMiddleware().Handler)
 	s.router.Use(s.container.Middleware().TimeoutMiddleware().Handler)
 }
@@ -84,18 +108,35 @@ func (s *Server) Start() error {
 	s.ConfigureRoutes()
 
 	s.srv = &http.Server{
-		Handler:      s.router,
+		Handler:      s.container.Middleware().TracingMiddleware().Wrap(s.router),
 		Addr:         ":" + config.Server.Port,
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		logs.Error("Failed to configure TLS", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return err
+	}
+	s.srv.TLSConfig = tlsConfig
+
 	logs.Info("Server starting", map[string]interface{}{
 		"port": config.Server.Port,
+		"tls":  config.TLS.CertFile != "",
+		"mtls": config.TLS.ClientCAFile != "",
 	})
 
-	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if config.TLS.CertFile != "" {
+		err = s.srv.ListenAndServeTLS(config.TLS.CertFile, config.TLS.KeyFile)
+	} else {
+		err = s.srv.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logs.Error("Server failed to start", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -105,11 +146,35 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// tlsConfig configura la verificación de certificados de cliente cuando se define TLS_CLIENT_CA_FILE. El certificado
+// es opcional para que los clientes con token o firma HMAC puedan conectarse, pero si se presenta debe ser válido
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	if config.TLS.ClientCAFile == "" {
+		return nil, nil
+	}
+
+	caPEM, err := os.ReadFile(config.TLS.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS_CLIENT_CA_FILE: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("TLS_CLIENT_CA_FILE does not contain valid PEM certificates")
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
-	logs.Info("Shutting down server...", nil)
+	logs.InfoContext(ctx, "Shutting down server...", nil)
 
 	if s.srv == nil {
-		logs.Warn("Server was not started, nothing to shutdown", nil)
+		logs.WarnContext(ctx, "Server was not started, nothing to shutdown", nil)
 		return nil
 	}
 
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// hashLegacyAPISecrets reemplaza los API secrets almacenados en texto plano por su hash. Los secrets de las sucursales
+// creadas antes de almacenarlos como hash siguen siendo válidos para iniciar sesión después de la migración.
+func hashLegacyAPISecrets(db *gorm.DB) error {
+	var branches []db_models.BranchOffice
+	if err := db.Select("id", "api_secret").Where("api_secret <> ''").Find(&branches).Error; err != nil {
+		return err
+	}
+
+	var migrated int
+	for _, branch := range branches {
+		if utils.IsHashedAPISecret(branch.APISecret) {
+			continue
+		}
+
+		err := db.Model(&db_models.BranchOffice{}).
+			Where("id = ?", branch.ID).
+			Update("api_secret", utils.HashAPISecret(branch.APISecret)).Error
+		if err != nil {
+			logs.Error("Failed to hash legacy API secret", map[string]interface{}{
+				"branchID": branch.ID,
+				"error":    err.Error(),
+			})
+			return err
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		logs.Info(fmt.Sprintf("%d legacy API secrets were hashed", migrated))
+	}
+
+	return nil
+}
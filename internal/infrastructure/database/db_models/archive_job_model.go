@@ -0,0 +1,34 @@
+package db_models
+
+import "time"
+
+// ArchiveJob representa un trabajo de exportación de los documentos originales de los DTE de un período.
+// El archivo ZIP generado se almacena en el directorio configurado en ARCHIVE_PATH y FilePath guarda su ubicación, con
+// varias instancias el directorio debe ser un volumen compartido. LeaseUntil indica hasta cuándo el proceso que reservó
+// el trabajo lo genera.
+type ArchiveJob struct {
+	ID            string     `gorm:"column:id;type:varchar(36);primaryKey;not null"`
+	BranchID      uint       `gorm:"column:branch_id;type:uint;not null;index:idx_archive_branch"`
+	UserID        uint       `gorm:"column:user_id;type:uint;not null"`
+	Scope         string     `gorm:"column:scope;type:varchar(10);not null"`
+	StartDate     time.Time  `gorm:"column:start_date;type:timestamp;not null"`
+	EndDate       time.Time  `gorm:"column:end_date;type:timestamp;not null"`
+	IncludePDF    bool       `gorm:"column:include_pdf;not null;default:false"`
+	Status        string     `gorm:"column:status;type:varchar(15);not null;index"`
+	DocumentCount int        `gorm:"column:document_count;not null;default:0"`
+	FileSize      int64      `gorm:"column:file_size;not null;default:0"`
+	FileHash      *string    `gorm:"column:file_hash;type:varchar(64)"`
+	FilePath      *string    `gorm:"column:file_path;type:varchar(255)"`
+	ErrorMessage  *string    `gorm:"column:error_message;type:varchar(500)"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	CompletedAt   *time.Time `gorm:"column:completed_at;type:timestamp"`
+	LeaseUntil    *time.Time `gorm:"column:lease_until;type:timestamp"`
+
+	// Relaciones
+	Branch *BranchOffice `gorm:"foreignKey:BranchID;references:ID"`
+	User   *User         `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (ArchiveJob) TableName() string {
+	return "archive_jobs"
+}
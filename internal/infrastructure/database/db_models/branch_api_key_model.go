@@ -0,0 +1,26 @@
+package db_models
+
+import "time"
+
+// BranchAPIKey representa una llave de acceso adicional de una sucursal con scopes limitados. El API secret se almacena
+// como hash al igual que el de la sucursal, junto con su copia cifrada para verificar las firmas HMAC, y los scopes se
+// guardan separados por comas
+type BranchAPIKey struct {
+	ID                uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	BranchID          uint      `gorm:"column:branch_id;type:uint;not null;index:idx_branch_api_keys_branch"`
+	Name              string    `gorm:"column:name;type:varchar(100);not null"`
+	APIKey            string    `gorm:"column:api_key;type:varchar(255);not null;uniqueIndex"`
+	APISecret         string    `gorm:"column:api_secret;type:varchar(255);not null"`
+	APISigningSecret  *string   `gorm:"column:api_signing_secret;type:varchar(255)"`
+	Scopes            string    `gorm:"column:scopes;type:varchar(255);not null"`
+	ClientCertSubject *string   `gorm:"column:client_cert_subject;type:varchar(255);uniqueIndex"`
+	IsActive          bool      `gorm:"column:is_active;type:tinyint(1);not null"`
+	CreatedAt         time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	Branch *BranchOffice `gorm:"foreignKey:BranchID;references:ID"`
+}
+
+func (BranchAPIKey) TableName() string {
+	return "branch_api_keys"
+}
@@ -0,0 +1,27 @@
+package db_models
+
+import "time"
+
+// BranchBranding representa la configuración de marca de una sucursal utilizada en la representación gráfica (PDF)
+// de los DTE. El logo se almacena en binario junto a su formato (PNG o JPG) para que el PDF pueda generarse sin depender
+// de servicios externos.
+//
+// Si una sucursal no posee configuración se utiliza la configuración por defecto definida en
+// /internal/domain/pdf/models/branding.go
+type BranchBranding struct {
+	ID           uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	BranchID     uint      `gorm:"column:branch_id;type:uint;not null;uniqueIndex"`
+	Logo         []byte    `gorm:"column:logo"`
+	LogoFormat   *string   `gorm:"column:logo_format;type:varchar(5)"`
+	PrimaryColor string    `gorm:"column:primary_color;type:varchar(7);not null"`
+	FooterText   *string   `gorm:"column:footer_text;type:varchar(255)"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	Branch *BranchOffice `gorm:"foreignKey:BranchID;references:ID"`
+}
+
+func (BranchBranding) TableName() string {
+	return "branch_brandings"
+}
@@ -19,11 +19,14 @@ type BranchOffice struct {
 	Email               *string `gorm:"column:email;type:varchar(255)"`
 	APIKey              string  `gorm:"column:api_key;type:varchar(255);not null;uniqueIndex"`
 	APISecret           string  `gorm:"column:api_secret;type:varchar(255);not null"`
+	APISigningSecret    *string `gorm:"column:api_signing_secret;type:varchar(255)"`
 	Phone               *string `gorm:"column:phone;type:varchar(30)"`
 	EstablishmentType   string  `gorm:"column:establishment_type;type:varchar(2);not null;index:idx_branch_est_type"`
 	POSCode             *string `gorm:"column:pos_code;type:varchar(15)"`
 	POSCodeMH           *string `gorm:"column:pos_code_mh;type:varchar(4)"`
 	IsActive            bool    `gorm:"column:is_active;type:tinyint(1);not null;index:idx_branch_offices_active"`
+	RateLimitPerMinute  *int    `gorm:"column:rate_limit_per_minute;type:int"`
+	ClientCertSubject   *string `gorm:"column:client_cert_subject;type:varchar(255);uniqueIndex"`
 
 	// Relaciones
 	User    *User    `gorm:"foreignKey:UserID;references:ID"`
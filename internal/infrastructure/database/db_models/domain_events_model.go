@@ -3,14 +3,22 @@ package db_models
 // DomainEvent representa un evento de dominio que se guarda en la base de datos
 // Su función es guardar los eventos de dominio que se generan en la aplicación por alguna situación específica que requiera
 // la atención de los usuarios.
-// Por ejemplo, cuando una sucursal entra en estado de contingencia, se genera un evento de dominio para notificar al usuario
+// Por ejemplo, cuando una sucursal entra en estado de contingencia, se genera un evento de dominio para notificar al usuario.
+// También conforma la auditoría del sistema, cada acción que modifica el estado registra el actor que la realizó,
+// la IP y el ID de la solicitud de origen y el digest SHA-256 del payload para verificar que no fue alterado.
+// El usuario y la sucursal son opcionales porque los registros rechazados se eliminan y su auditoría debe conservarse
 type DomainEvent struct {
-	ID         uint   `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
-	UserID     uint   `gorm:"column:user_id;type:uint;not null;index:idx_event_user"`
-	BranchID   uint   `gorm:"column:branch_id;type:uint;not null;index:idx_event_branch"`
-	EventType  string `gorm:"column:event_type;type:varchar(50);not null;index"`
-	Payload    string `gorm:"column:payload;type:json;not null"`
-	OccurredAt string `gorm:"column:occurred_at;type:timestamp;not null;index"`
+	ID            uint   `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	UserID        *uint  `gorm:"column:user_id;type:uint;index:idx_event_user"`
+	BranchID      *uint  `gorm:"column:branch_id;type:uint;index:idx_event_branch"`
+	EventType     string `gorm:"column:event_type;type:varchar(50);not null;index"`
+	ActorType     string `gorm:"column:actor_type;type:varchar(20);not null;default:'SYSTEM';index"`
+	ActorID       uint   `gorm:"column:actor_id;type:uint;not null;default:0"`
+	IPAddress     string `gorm:"column:ip_address;type:varchar(45)"`
+	RequestID     string `gorm:"column:request_id;type:varchar(64);index"`
+	Payload       string `gorm:"column:payload;type:json;not null"`
+	PayloadDigest string `gorm:"column:payload_digest;type:varchar(64)"`
+	OccurredAt    string `gorm:"column:occurred_at;type:timestamp;not null;index"`
 
 	// Índice compuesto
 	// `gorm:"index:idx_user_type,priority:1,2"` - Para buscar eventos de un usuario por tipo
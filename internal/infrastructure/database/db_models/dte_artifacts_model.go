@@ -0,0 +1,20 @@
+package db_models
+
+import "time"
+
+// DTEArtifacts almacena los documentos originales de la transmisión de un DTE, el JWS firmado y la respuesta de
+// Hacienda. Se guardan en una tabla aparte de dte_details para no cargar su contenido en las consultas de listado.
+type DTEArtifacts struct {
+	DocumentID     string    `gorm:"column:document_id;type:varchar(36);primaryKey;not null"`
+	SignedDocument *string   `gorm:"column:signed_document;type:text"`
+	MHResponse     *string   `gorm:"column:mh_response;type:text"`
+	CreatedAt      time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	Document *DTEDetails `gorm:"foreignKey:DocumentID;references:ID"`
+}
+
+func (DTEArtifacts) TableName() string {
+	return "dte_artifacts"
+}
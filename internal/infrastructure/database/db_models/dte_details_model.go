@@ -15,6 +15,10 @@ package db_models
 // https://factura.gob.sv/informacion-tecnica-y-funcional/
 // en la sección de "Documentos de Sistema de Transmisión DTE", documento: "2. Catálogos- Sistema de Transmisión"
 // página 5 del documento PDF y revisar /internal/domain/dte/common/constants/dte_type.go
+//
+// Los campos ReceiverNIT, ReceiverNRC, ReceiverName y TotalAmount son columnas generadas a partir de JSONData, la base de datos
+// las calcula e indexa para las búsquedas, por lo que son de solo lectura y se crean en las migraciones de cada driver, ver
+// /internal/infrastructure/database/migrations
 type DTEDetails struct {
 	ID             string  `gorm:"column:id;varchar(36);primaryKey;not null;index:idx_dte_details"`
 	DTEType        string  `gorm:"column:dte_type;varchar(2);not null;index:idx_dte_type"`
@@ -24,6 +28,12 @@ type DTEDetails struct {
 	Status         string  `gorm:"column:status;varchar(15);not null;index"`
 	JSONData       string  `gorm:"column:json_data;type:json;not null"`
 
+	// Columnas generadas
+	ReceiverNIT  *string  `gorm:"column:receiver_nit;->;-:migration"`
+	ReceiverNRC  *string  `gorm:"column:receiver_nrc;->;-:migration"`
+	ReceiverName *string  `gorm:"column:receiver_name;->;-:migration"`
+	TotalAmount  *float64 `gorm:"column:total_amount;->;-:migration"`
+
 	BalanceControl *DTEBalanceControl `gorm:"foreignKey:OriginalDTEID;references:ID"`
 }
 
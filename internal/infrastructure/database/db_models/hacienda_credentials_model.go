@@ -0,0 +1,23 @@
+package db_models
+
+import "time"
+
+// HaciendaCredentials representa las credenciales de Hacienda de un usuario cifradas con envelope encryption.
+// Ciphertext contiene las credenciales cifradas con una llave de datos aleatoria, WrappedKey contiene esa llave cifrada
+// con la llave maestra del servidor identificada por KeyVersion (VAULT_MASTER_KEYS).
+type HaciendaCredentials struct {
+	ID         uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	UserID     uint      `gorm:"column:user_id;type:uint;not null;uniqueIndex"`
+	KeyVersion string    `gorm:"column:key_version;type:varchar(20);not null;index"`
+	WrappedKey string    `gorm:"column:wrapped_key;type:varchar(255);not null"`
+	Ciphertext string    `gorm:"column:ciphertext;type:text;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (HaciendaCredentials) TableName() string {
+	return "hacienda_credentials"
+}
@@ -0,0 +1,41 @@
+package db_models
+
+import "time"
+
+// Operator representa una cuenta de operador que emite DTE en nombre de varios contribuyentes, por ejemplo una firma
+// contable. El API secret se almacena como hash al igual que el de las sucursales
+type Operator struct {
+	ID        uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	Name      string    `gorm:"column:name;type:varchar(150);not null"`
+	Email     string    `gorm:"column:email;type:varchar(255);not null;uniqueIndex"`
+	APIKey    string    `gorm:"column:api_key;type:varchar(255);not null;uniqueIndex"`
+	APISecret string    `gorm:"column:api_secret;type:varchar(255);not null"`
+	IsActive  bool      `gorm:"column:is_active;type:tinyint(1);not null"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	Grants []OperatorGrant `gorm:"foreignKey:OperatorID;references:ID"`
+}
+
+func (Operator) TableName() string {
+	return "operators"
+}
+
+// OperatorGrant representa el acceso de un operador a un contribuyente, si BranchID es nulo el acceso abarca todas
+// las sucursales del contribuyente
+type OperatorGrant struct {
+	ID         uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	OperatorID uint      `gorm:"column:operator_id;type:uint;not null;index:idx_operator_grants_operator"`
+	UserID     uint      `gorm:"column:user_id;type:uint;not null;index:idx_operator_grants_user"`
+	BranchID   *uint     `gorm:"column:branch_id;type:uint"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	Operator *Operator     `gorm:"foreignKey:OperatorID;references:ID"`
+	User     *User         `gorm:"foreignKey:UserID;references:ID"`
+	Branch   *BranchOffice `gorm:"foreignKey:BranchID;references:ID"`
+}
+
+func (OperatorGrant) TableName() string {
+	return "operator_grants"
+}
@@ -0,0 +1,16 @@
+package db_models
+
+import "time"
+
+// SchemaMigration registra una versión del esquema aplicada a la base de datos. Dirty indica que la migración inició y
+// no finalizó, el esquema debe revisarse manualmente antes de continuar
+type SchemaMigration struct {
+	Version   uint      `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name"`
+	Dirty     bool      `gorm:"column:dirty"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
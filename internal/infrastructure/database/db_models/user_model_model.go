@@ -13,23 +13,32 @@ import "time"
 // se muestre en el número de control. Por ejemplo:
 //  1. Si es true, el número de control será: DTE-01-00000000-202500000000001
 //  2. Si es false, el número de control será: DTE-01-00000000-000000000000001
+//
+// El campo RegistrationStatus indica si el registro del usuario fue aprobado por un administrador, los usuarios
+// registrados antes de la aprobación de registros se consideran aprobados.
+//
+// El campo CertificateExpiresAt es la fecha de vencimiento del certificado de firma registrada por el contribuyente, se
+// utiliza para avisar su vencimiento.
 type User struct {
-	ID                   uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
-	NIT                  string    `gorm:"column:nit;type:varchar(17);not null;uniqueIndex"`
-	NRC                  string    `gorm:"column:nrc;type:varchar(10);not null;uniqueIndex"`
-	Status               bool      `gorm:"column:status;type:tinyint;not null;index:idx_user_status"`
-	AuthType             string    `gorm:"column:auth_type;type:varchar(15);not null"`
-	PasswordPri          string    `gorm:"column:password_pri;type:varchar(255);not null"`
-	CommercialName       string    `gorm:"column:commercial_name;type:varchar(150);not null"`
-	EconomicActivity     string    `gorm:"column:economic_activity;type:varchar(6);not null"`
-	EconomicActivityDesc string    `gorm:"column:economic_activity_desc;type:varchar(150);not null"`
-	Business             string    `gorm:"column:business_name;type:varchar(200);not null"`
-	Email                string    `gorm:"column:email;type:varchar(100);not null;uniqueIndex:idx_user_email"`
-	Phone                string    `gorm:"column:phone;type:varchar(30);not null;uniqueIndex:idx_user_phone"`
-	YearInDTE            bool      `gorm:"column:year_in_dte;type:tinyint;not null"`
-	TokenLifetime        int       `gorm:"column:token_lifetime;type:int;not null;default:14"`
-	CreatedAt            time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
-	UpdatedAt            time.Time `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	ID                   uint       `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	NIT                  string     `gorm:"column:nit;type:varchar(17);not null;uniqueIndex"`
+	NRC                  string     `gorm:"column:nrc;type:varchar(10);not null;uniqueIndex"`
+	Status               bool       `gorm:"column:status;type:tinyint;not null;index:idx_user_status"`
+	AuthType             string     `gorm:"column:auth_type;type:varchar(15);not null"`
+	PasswordPri          string     `gorm:"column:password_pri;type:varchar(255);not null"`
+	CommercialName       string     `gorm:"column:commercial_name;type:varchar(150);not null"`
+	EconomicActivity     string     `gorm:"column:economic_activity;type:varchar(6);not null"`
+	EconomicActivityDesc string     `gorm:"column:economic_activity_desc;type:varchar(150);not null"`
+	Business             string     `gorm:"column:business_name;type:varchar(200);not null"`
+	Email                string     `gorm:"column:email;type:varchar(100);not null;uniqueIndex:idx_user_email"`
+	Phone                string     `gorm:"column:phone;type:varchar(30);not null;uniqueIndex:idx_user_phone"`
+	YearInDTE            bool       `gorm:"column:year_in_dte;type:tinyint;not null"`
+	TokenLifetime        int        `gorm:"column:token_lifetime;type:int;not null;default:14"`
+	Language             string     `gorm:"column:language;type:varchar(5)"`
+	RegistrationStatus   string     `gorm:"column:registration_status;type:varchar(10);not null;default:'APPROVED';index:idx_user_registration_status"`
+	CertificateExpiresAt *time.Time `gorm:"column:certificate_expires_at;type:timestamp;index:idx_user_certificate_expiry"`
+	CreatedAt            time.Time  `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	UpdatedAt            time.Time  `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP"`
 }
 
 func (User) TableName() string {
@@ -4,14 +4,25 @@ import "time"
 
 // UserNotification representa la tabla que almacenará la información de las notificaciones que se enviarán a los usuarios.
 // Esta tabla almacenará la información de las notificaciones que se enviarán a los usuarios.
+//
+// Para las notificaciones de tipo EMAIL (envío de DTE al receptor) se almacena además el destinatario, el código de generación
+// del documento enviado y el mensaje de error en caso de que el envío falle.
+//
+// Las alertas del motor de notificaciones (tipos ALERT_EMAIL y ALERT_WEBHOOK) registran además sus intentos y el
+// instante de su siguiente intento mientras están pendientes.
 type UserNotification struct {
-	ID               uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
-	UserID           uint      `gorm:"column:user_id;type:uint;not null;index:idx_notification_user"`
-	EventID          uint      `gorm:"column:event_id;type:uint;not null;index:idx_notification_event"`
-	NotificationType string    `gorm:"column:notification_type;type:varchar(15);not null;index"`
-	Message          string    `gorm:"column:message;type:text;not null"`
-	DeliveryStatus   string    `gorm:"column:delivery_status;type:varchar(15);not null;index"`
-	DeliveryAt       time.Time `gorm:"column:delivery_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	ID               uint       `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	UserID           uint       `gorm:"column:user_id;type:uint;not null;index:idx_notification_user"`
+	EventID          uint       `gorm:"column:event_id;type:uint;not null;index:idx_notification_event"`
+	NotificationType string     `gorm:"column:notification_type;type:varchar(15);not null;index"`
+	Message          string     `gorm:"column:message;type:text;not null"`
+	DeliveryStatus   string     `gorm:"column:delivery_status;type:varchar(15);not null;index"`
+	DeliveryAt       time.Time  `gorm:"column:delivery_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	Recipient        *string    `gorm:"column:recipient;type:varchar(255)"`
+	DocumentID       *string    `gorm:"column:document_id;type:varchar(36);index:idx_notification_document"`
+	ErrorMessage     *string    `gorm:"column:error_message;type:text"`
+	Attempts         int        `gorm:"column:attempts;type:int;not null;default:0"`
+	NextAttemptAt    *time.Time `gorm:"column:next_attempt_at;type:timestamp;index:idx_notification_next_attempt"`
 
 	// Relaciones
 	User  *User        `gorm:"foreignKey:UserID;references:ID"`
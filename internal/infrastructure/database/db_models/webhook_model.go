@@ -0,0 +1,63 @@
+package db_models
+
+import "time"
+
+// WebhookSubscription representa la suscripción de un contribuyente a los eventos del ciclo de vida de sus DTE.
+// Events almacena los eventos separados por comas y el secret de firma se almacena cifrado con el vault
+type WebhookSubscription struct {
+	ID        uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	UserID    uint      `gorm:"column:user_id;type:uint;not null;index:idx_webhook_subscription_user"`
+	URL       string    `gorm:"column:url;type:varchar(500);not null"`
+	Events    string    `gorm:"column:events;type:varchar(255);not null"`
+	Secret    string    `gorm:"column:secret;type:varchar(255);not null"`
+	IsActive  bool      `gorm:"column:is_active;type:tinyint(1);not null"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// Relaciones
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery representa la entrega de un evento a una suscripción. Las entregas pendientes se intentan cuando
+// se alcanza NextAttemptAt y las que agotan sus intentos quedan con estado DEAD como lista de entregas fallidas
+type WebhookDelivery struct {
+	ID             string     `gorm:"column:id;type:varchar(36);primaryKey;not null"`
+	SubscriptionID uint       `gorm:"column:subscription_id;type:uint;not null;index:idx_webhook_delivery_subscription"`
+	UserID         uint       `gorm:"column:user_id;type:uint;not null;index:idx_webhook_delivery_user"`
+	EventID        string     `gorm:"column:event_id;type:varchar(36);not null"`
+	EventType      string     `gorm:"column:event_type;type:varchar(50);not null"`
+	Payload        string     `gorm:"column:payload;type:json;not null"`
+	Status         string     `gorm:"column:status;type:varchar(15);not null;index:idx_webhook_delivery_due,priority:1"`
+	Attempts       int        `gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt  *time.Time `gorm:"column:next_attempt_at;type:timestamp;index:idx_webhook_delivery_due,priority:2"`
+	LastStatusCode *int       `gorm:"column:last_status_code"`
+	LastError      *string    `gorm:"column:last_error;type:varchar(500)"`
+	CreatedAt      time.Time  `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at;type:timestamp"`
+
+	// Relaciones
+	Subscription *WebhookSubscription     `gorm:"foreignKey:SubscriptionID;references:ID"`
+	Logs         []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID;references:ID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt representa el registro de un intento de entrega con la respuesta del destino
+type WebhookDeliveryAttempt struct {
+	ID           uint      `gorm:"column:id;type:uint;primaryKey;autoIncrement;not null"`
+	DeliveryID   string    `gorm:"column:delivery_id;type:varchar(36);not null;index:idx_webhook_attempt_delivery"`
+	Attempt      int       `gorm:"column:attempt;not null"`
+	StatusCode   *int      `gorm:"column:status_code"`
+	ErrorMessage *string   `gorm:"column:error_message;type:varchar(500)"`
+	DurationMs   int64     `gorm:"column:duration_ms;not null;default:0"`
+	AttemptedAt  time.Time `gorm:"column:attempted_at;type:timestamp;not null"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
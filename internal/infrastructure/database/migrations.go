@@ -1,51 +1,235 @@
 package database
 
 import (
+	"embed"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
 	"gorm.io/gorm"
-	"gorm.io/gorm/schema"
+)
+
+// migrationFiles contiene los archivos SQL de las migraciones, cada driver tiene su propio directorio con los archivos
+// NNNN_nombre.up.sql y NNNN_nombre.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
 
-	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
-	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+// migrationFilePattern formato del nombre de los archivos de migración: versión, nombre y dirección
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Patrones de las sentencias que definen las tablas y columnas del esquema, se utilizan para obtener el esquema que
+// crean las migraciones SQL hasta una versión
+var (
+	createTablePattern = regexp.MustCompile("(?is)^CREATE TABLE\\s+[`\"](\\w+)[`\"]\\s*\\((.*)\\)$")
+	columnPattern      = regexp.MustCompile("^\\s*[`\"](\\w+)[`\"]\\s")
+	addColumnPattern   = regexp.MustCompile("(?i)^ALTER TABLE\\s+[`\"](\\w+)[`\"]\\s+ADD COLUMN\\s+[`\"](\\w+)[`\"]")
+	dropColumnPattern  = regexp.MustCompile("(?i)^ALTER TABLE\\s+[`\"](\\w+)[`\"]\\s+DROP COLUMN\\s+[`\"](\\w+)[`\"]")
+	renameTablePattern = regexp.MustCompile("(?i)^ALTER TABLE\\s+[`\"](\\w+)[`\"]\\s+RENAME TO\\s+[`\"](\\w+)[`\"]")
+	dropTablePattern   = regexp.MustCompile("(?i)^DROP TABLE\\s+(?:IF EXISTS\\s+)?[`\"](\\w+)[`\"]")
 )
 
-// modelsToMigrate contiene todos los modelos que se deben migrar
-var modelsToMigrate = []schema.Tabler{
-	&db_models.User{},
-	&db_models.BranchOffice{},
-	&db_models.Address{},
-	&db_models.DTEDetails{},
-	&db_models.DTEDocument{},
-	&db_models.ContingencyDocument{},
-	&db_models.ControlNumberSequence{},
-	&db_models.FailedSequenceNumber{},
-	&db_models.DomainEvent{},
-	&db_models.UserNotification{},
-	&db_models.NotifiableUser{},
-	&db_models.DTEBalanceControl{},
-	&db_models.DTEBalanceTransaction{},
+// Migration es una versión del esquema de la base de datos. Up aplica los cambios y Down los revierte, ambas se
+// ejecutan dentro de una transacción
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+
+	// upSQL contenido del archivo up de las migraciones SQL, las migraciones de código no lo tienen
+	upSQL string
+}
+
+// codeMigrations migraciones que requieren código Go, como la transformación de datos que no puede expresarse en SQL.
+// Se aplican con cualquier driver y su versión no debe repetirse en los archivos SQL
+var codeMigrations = []Migration{
+	{
+		Version: 7,
+		Name:    "hash_legacy_api_secrets",
+		Up:      hashLegacyAPISecrets,
+		// Los secrets almacenados como hash siguen siendo válidos, no hay cambios que revertir
+		Down: func(tx *gorm.DB) error { return nil },
+	},
+}
+
+// LoadMigrations obtiene las migraciones del driver ordenadas por versión, los archivos SQL del driver junto con las
+// migraciones de código. Cada versión debe tener su archivo up y su archivo down
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations found for driver %s: %w", dialect, err)
+	}
+
+	// 1. Agrupar los archivos up y down de cada versión
+	type sqlMigration struct {
+		name     string
+		up, down string
+	}
+	files := make(map[uint]*sqlMigration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s/%s", dir, entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %s/%s", dir, entry.Name())
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		file, ok := files[uint(version)]
+		if !ok {
+			file = &sqlMigration{name: match[2]}
+			files[uint(version)] = file
+		}
+		if file.name != match[2] {
+			return nil, fmt.Errorf("migration %d has files with different names in %s", version, dir)
+		}
+
+		if match[3] == "up" {
+			file.up = string(content)
+		} else {
+			file.down = string(content)
+		}
+	}
+
+	// 2. Construir las migraciones SQL y agregar las de código
+	migrations := make([]Migration, 0, len(files)+len(codeMigrations))
+	for version, file := range files {
+		if file.up == "" || file.down == "" {
+			return nil, fmt.Errorf("migration %d_%s requires both up and down files in %s", version, file.name, dir)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    file.name,
+			Up:      execStatements(file.up),
+			Down:    execStatements(file.down),
+			upSQL:   file.up,
+		})
+	}
+
+	for _, migration := range codeMigrations {
+		if _, ok := files[migration.Version]; ok {
+			return nil, fmt.Errorf("migration version %d is defined both as SQL file and code migration", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// expectedSchema obtiene las tablas y columnas que crean las migraciones SQL hasta la versión indicada. Las migraciones
+// de código solo transforman datos y no modifican el esquema
+func expectedSchema(migrations []Migration, version uint) map[string][]string {
+	tables := make(map[string][]string)
+	for _, migration := range migrations {
+		if migration.Version > version {
+			break
+		}
+
+		for _, statement := range splitStatements(migration.upSQL) {
+			if match := createTablePattern.FindStringSubmatch(statement); match != nil {
+				columns := make([]string, 0)
+				for _, line := range strings.Split(match[2], "\n") {
+					if column := columnPattern.FindStringSubmatch(line); column != nil {
+						columns = append(columns, column[1])
+					}
+				}
+				tables[match[1]] = columns
+			} else if match = addColumnPattern.FindStringSubmatch(statement); match != nil {
+				tables[match[1]] = append(tables[match[1]], match[2])
+			} else if match = dropColumnPattern.FindStringSubmatch(statement); match != nil {
+				columns := make([]string, 0, len(tables[match[1]]))
+				for _, column := range tables[match[1]] {
+					if column != match[2] {
+						columns = append(columns, column)
+					}
+				}
+				tables[match[1]] = columns
+			} else if match = renameTablePattern.FindStringSubmatch(statement); match != nil {
+				tables[match[2]] = tables[match[1]]
+				delete(tables, match[1])
+			} else if match = dropTablePattern.FindStringSubmatch(statement); match != nil {
+				delete(tables, match[1])
+			}
+		}
+	}
+
+	return tables
+}
+
+// execStatements ejecuta una por una las sentencias de un archivo SQL, los drivers no permiten varias sentencias en una
+// misma ejecución
+func execStatements(sql string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(sql) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("error executing %q: %w", truncateStatement(statement), err)
+			}
+		}
+		return nil
+	}
 }
 
-// RunMigrations ejecuta todas las migraciones de la base de datos
-func RunMigrations(db *gorm.DB) error {
-	logs.Info("Starting database migrations")
+// splitStatements separa las sentencias de un archivo SQL por punto y coma, omite los comentarios de línea y los punto y
+// coma dentro de cadenas o identificadores entre comillas
+func splitStatements(sql string) []string {
+	statements := make([]string, 0)
+	var current strings.Builder
+	var quote rune
 
-	for i, model := range modelsToMigrate {
-		tn := model.TableName()
-		logs.Info(fmt.Sprintf("Starting model migration #%d: %s", i+1, tn))
+	for _, line := range strings.Split(sql, "\n") {
+		if quote == 0 && strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
 
-		if err := db.AutoMigrate(model); err != nil {
-			logs.Error("Failed to migrate model", map[string]interface{}{
-				"index": i + 1,
-				"model": tn,
-				"error": err.Error(),
-			})
-			return err
+		for _, char := range line {
+			switch {
+			case quote != 0:
+				if char == quote {
+					quote = 0
+				}
+			case char == '\'' || char == '"' || char == '`':
+				quote = char
+			case char == ';':
+				if statement := strings.TrimSpace(current.String()); statement != "" {
+					statements = append(statements, statement)
+				}
+				current.Reset()
+				continue
+			}
+			current.WriteRune(char)
 		}
+		current.WriteRune('\n')
+	}
 
-		logs.Info(fmt.Sprintf("Successfully migrated model %s", tn))
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
 	}
 
-	logs.Info("All migrations completed successfully")
-	return nil
+	return statements
+}
+
+// truncateStatement acorta una sentencia para incluirla en los mensajes de error
+func truncateStatement(statement string) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if len(statement) > 120 {
+		return statement[:120] + "..."
+	}
+	return statement
 }
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS `dte_balance_transactions`;
+DROP TABLE IF EXISTS `dte_balance_control`;
+DROP TABLE IF EXISTS `notification_users`;
+DROP TABLE IF EXISTS `user_notifications`;
+DROP TABLE IF EXISTS `domain_events`;
+DROP TABLE IF EXISTS `failed_sequence_numbers`;
+DROP TABLE IF EXISTS `control_number_sequences`;
+DROP TABLE IF EXISTS `contingency_documents`;
+DROP TABLE IF EXISTS `dte_documents`;
+DROP TABLE IF EXISTS `dte_details`;
+DROP TABLE IF EXISTS `addresses`;
+DROP TABLE IF EXISTS `branch_offices`;
+DROP TABLE IF EXISTS `users`;
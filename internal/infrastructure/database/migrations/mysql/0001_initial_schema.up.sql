@@ -0,0 +1,221 @@
+-- Esquema inicial del microservicio, corresponde al esquema que creaba AutoMigrate antes de las migraciones
+-- versionadas. Las bases de datos creadas con AutoMigrate se registran en esta versión con "migrate baseline 1"
+
+CREATE TABLE `users` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `nit` varchar(17) NOT NULL,
+    `nrc` varchar(10) NOT NULL,
+    `status` tinyint NOT NULL,
+    `auth_type` varchar(15) NOT NULL,
+    `password_pri` varchar(255) NOT NULL,
+    `commercial_name` varchar(150) NOT NULL,
+    `economic_activity` varchar(6) NOT NULL,
+    `economic_activity_desc` varchar(150) NOT NULL,
+    `business_name` varchar(200) NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `phone` varchar(30) NOT NULL,
+    `year_in_dte` tinyint NOT NULL,
+    `token_lifetime` bigint NOT NULL DEFAULT 14,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_user_status` (`status`),
+    UNIQUE INDEX `idx_user_email` (`email`),
+    UNIQUE INDEX `idx_user_phone` (`phone`),
+    UNIQUE INDEX `idx_users_nit` (`nit`),
+    UNIQUE INDEX `idx_users_nrc` (`nrc`)
+);
+
+CREATE TABLE `branch_offices` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `establishment_code` varchar(10),
+    `establishment_code_mh` varchar(4),
+    `email` varchar(255),
+    `api_key` varchar(255) NOT NULL,
+    `api_secret` varchar(255) NOT NULL,
+    `phone` varchar(30),
+    `establishment_type` varchar(2) NOT NULL,
+    `pos_code` varchar(15),
+    `pos_code_mh` varchar(4),
+    `is_active` tinyint(1) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_branch_est_type` (`establishment_type`),
+    INDEX `idx_branch_offices_active` (`is_active`),
+    INDEX `idx_branch_offices_user` (`user_id`),
+    UNIQUE INDEX `idx_branch_offices_api_key` (`api_key`),
+    CONSTRAINT `fk_branch_offices_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `addresses` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `municipality` varchar(2) NOT NULL,
+    `department` varchar(2) NOT NULL,
+    `complement` varchar(200) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_address_branch` (`branch_id`),
+    CONSTRAINT `fk_branch_offices_address` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`)
+);
+
+CREATE TABLE `dte_details` (
+    `id` varchar(191) NOT NULL,
+    `dte_type` varchar(191) NOT NULL,
+    `control_number` varchar(191) NOT NULL,
+    `reception_stamp` longtext,
+    `transmission` longtext NOT NULL,
+    `status` varchar(191) NOT NULL,
+    `json_data` json NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_dte_details` (`id`),
+    INDEX `idx_dte_type` (`dte_type`),
+    INDEX `idx_dte_details_control_number` (`control_number`),
+    INDEX `idx_dte_details_status` (`status`)
+);
+
+CREATE TABLE `dte_documents` (
+    `document_id` varchar(191) NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`document_id`),
+    INDEX `idx_dte_document` (`document_id`),
+    INDEX `idx_dte_branch` (`branch_id`),
+    INDEX `idx_dte_date` (`created_at`),
+    CONSTRAINT `fk_dte_documents_document` FOREIGN KEY (`document_id`) REFERENCES `dte_details`(`id`),
+    CONSTRAINT `fk_dte_documents_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`)
+);
+
+CREATE TABLE `contingency_documents` (
+    `id` varchar(36) NOT NULL,
+    `document_id` varchar(191) NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `type` tinyint NOT NULL,
+    `reason` varchar(150) NOT NULL,
+    `batch_id` varchar(36),
+    `mh_batch_id` varchar(36),
+    `observations` text,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_contingency_date` (`created_at`),
+    INDEX `idx_contingency_documents_document_id` (`document_id`),
+    INDEX `idx_contingency_branch` (`branch_id`),
+    INDEX `idx_contingency_documents_contingency_type` (`type`),
+    INDEX `idx_contingency_documents_reason` (`reason`),
+    INDEX `idx_contingency_documents_batch_id` (`batch_id`),
+    CONSTRAINT `fk_contingency_documents_document` FOREIGN KEY (`document_id`) REFERENCES `dte_details`(`id`),
+    CONSTRAINT `fk_contingency_documents_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`)
+);
+
+CREATE TABLE `control_number_sequences` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `dte_type` varchar(2) NOT NULL,
+    `year` bigint NOT NULL,
+    `last_number` bigint NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_branch_dte_type` (`branch_id`,`dte_type`),
+    INDEX `idx_sequence_year` (`year`),
+    CONSTRAINT `fk_control_number_sequences_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`)
+);
+
+CREATE TABLE `failed_sequence_numbers` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `dte_type` varchar(2) NOT NULL,
+    `sequence_number` bigint unsigned NOT NULL,
+    `year` bigint unsigned NOT NULL,
+    `failure_reason` text NOT NULL,
+    `response_code` varchar(10),
+    `original_request_data` json NOT NULL,
+    `mh_response` text,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_failed_seq` (`branch_id`,`dte_type`,`sequence_number`,`year`)
+);
+
+CREATE TABLE `domain_events` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `event_type` varchar(50) NOT NULL,
+    `payload` json NOT NULL,
+    `occurred_at` timestamp NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_domain_events_occurred_at` (`occurred_at`),
+    INDEX `idx_event_user` (`user_id`),
+    INDEX `idx_event_branch` (`branch_id`),
+    INDEX `idx_domain_events_event_type` (`event_type`),
+    CONSTRAINT `fk_domain_events_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_domain_events_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`)
+);
+
+CREATE TABLE `user_notifications` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `event_id` bigint unsigned NOT NULL,
+    `notification_type` varchar(15) NOT NULL,
+    `message` text NOT NULL,
+    `delivery_status` varchar(15) NOT NULL,
+    `delivery_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_notification_user` (`user_id`),
+    INDEX `idx_notification_event` (`event_id`),
+    INDEX `idx_user_notifications_notification_type` (`notification_type`),
+    INDEX `idx_user_notifications_delivery_status` (`delivery_status`),
+    CONSTRAINT `fk_user_notifications_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_user_notifications_event` FOREIGN KEY (`event_id`) REFERENCES `domain_events`(`id`)
+);
+
+CREATE TABLE `notification_users` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `entity_type` varchar(10) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `enabled_push` tinyint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_entity` (`user_id`,`entity_type`),
+    INDEX `idx_notifiable_email` (`email`),
+    INDEX `idx_push_enabled` (`enabled_push`)
+);
+
+CREATE TABLE `dte_balance_control` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `original_dte_id` varchar(191) NOT NULL,
+    `original_taxed_amount` decimal(18,2) NOT NULL,
+    `original_exempt_amount` decimal(18,2) NOT NULL,
+    `original_not_subject_amount` decimal(18,2) NOT NULL,
+    `remaining_taxed_amount` decimal(18,2) NOT NULL,
+    `remaining_exempt_amount` decimal(18,2) NOT NULL,
+    `remaining_not_subject_amount` decimal(18,2) NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_dte_balance_control` (`id`),
+    INDEX `idx_dte_branch` (`branch_id`),
+    INDEX `idx_dte_original` (`original_dte_id`),
+    CONSTRAINT `fk_dte_balance_control_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`),
+    CONSTRAINT `fk_dte_details_balance_control` FOREIGN KEY (`original_dte_id`) REFERENCES `dte_details`(`id`)
+);
+
+CREATE TABLE `dte_balance_transactions` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `balance_control_id` bigint unsigned NOT NULL,
+    `adjustment_document_id` varchar(191) NOT NULL,
+    `transaction_type` varchar(20) NOT NULL,
+    `taxed_amount` decimal(18,2) NOT NULL,
+    `exempt_amount` decimal(18,2) NOT NULL,
+    `not_subject_amount` decimal(18,2) NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_dte_balance_transaction` (`id`),
+    INDEX `idx_dte_balance_control` (`balance_control_id`),
+    INDEX `idx_dte_adjustment_document` (`adjustment_document_id`),
+    INDEX `idx_dte_transaction_type` (`transaction_type`),
+    CONSTRAINT `fk_dte_balance_transactions_adjustment_document` FOREIGN KEY (`adjustment_document_id`) REFERENCES `dte_details`(`id`),
+    CONSTRAINT `fk_dte_balance_control_transactions` FOREIGN KEY (`balance_control_id`) REFERENCES `dte_balance_control`(`id`)
+);
@@ -0,0 +1,4 @@
+DROP INDEX `idx_notification_document` ON `user_notifications`;
+ALTER TABLE `user_notifications` DROP COLUMN `error_message`;
+ALTER TABLE `user_notifications` DROP COLUMN `document_id`;
+ALTER TABLE `user_notifications` DROP COLUMN `recipient`;
@@ -0,0 +1,8 @@
+DROP INDEX `idx_dte_total_amount` ON `dte_details`;
+ALTER TABLE `dte_details` DROP COLUMN `total_amount`;
+DROP INDEX `idx_dte_receiver_name` ON `dte_details`;
+ALTER TABLE `dte_details` DROP COLUMN `receiver_name`;
+DROP INDEX `idx_dte_receiver_nrc` ON `dte_details`;
+ALTER TABLE `dte_details` DROP COLUMN `receiver_nrc`;
+DROP INDEX `idx_dte_receiver_nit` ON `dte_details`;
+ALTER TABLE `dte_details` DROP COLUMN `receiver_nit`;
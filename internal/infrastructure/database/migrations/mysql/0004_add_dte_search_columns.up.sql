@@ -0,0 +1,9 @@
+-- Columnas generadas de dte_details utilizadas en la búsqueda de documentos y en las métricas de negocio
+ALTER TABLE `dte_details` ADD COLUMN `receiver_nit` VARCHAR(20) GENERATED ALWAYS AS (COALESCE(JSON_VALUE(json_data, '$.receptor.nit'), JSON_VALUE(json_data, '$.receptor.numDocumento'))) STORED;
+CREATE INDEX `idx_dte_receiver_nit` ON `dte_details` (`receiver_nit`);
+ALTER TABLE `dte_details` ADD COLUMN `receiver_nrc` VARCHAR(10) GENERATED ALWAYS AS (JSON_VALUE(json_data, '$.receptor.nrc')) STORED;
+CREATE INDEX `idx_dte_receiver_nrc` ON `dte_details` (`receiver_nrc`);
+ALTER TABLE `dte_details` ADD COLUMN `receiver_name` VARCHAR(250) GENERATED ALWAYS AS (JSON_VALUE(json_data, '$.receptor.nombre')) STORED;
+CREATE INDEX `idx_dte_receiver_name` ON `dte_details` (`receiver_name`);
+ALTER TABLE `dte_details` ADD COLUMN `total_amount` DECIMAL(18,2) GENERATED ALWAYS AS (CAST(COALESCE(JSON_VALUE(json_data, '$.resumen.totalPagar'), JSON_VALUE(json_data, '$.resumen.montoTotalOperacion'), JSON_VALUE(json_data, '$.resumen.totalIVAretenido')) AS DECIMAL(18,2))) STORED;
+CREATE INDEX `idx_dte_total_amount` ON `dte_details` (`total_amount`);
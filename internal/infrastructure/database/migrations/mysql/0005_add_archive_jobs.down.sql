@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `archive_jobs`;
+DROP TABLE IF EXISTS `dte_artifacts`;
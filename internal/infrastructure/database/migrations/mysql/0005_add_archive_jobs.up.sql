@@ -0,0 +1,34 @@
+-- Documentos firmados y respuestas de Hacienda conservados para el archivo, y los trabajos de exportación ZIP
+
+CREATE TABLE `dte_artifacts` (
+    `document_id` varchar(191) NOT NULL,
+    `signed_document` text,
+    `mh_response` text,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`document_id`),
+    CONSTRAINT `fk_dte_artifacts_document` FOREIGN KEY (`document_id`) REFERENCES `dte_details`(`id`)
+);
+
+CREATE TABLE `archive_jobs` (
+    `id` varchar(36) NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `scope` varchar(10) NOT NULL,
+    `start_date` timestamp NOT NULL,
+    `end_date` timestamp NOT NULL,
+    `include_pdf` boolean NOT NULL DEFAULT false,
+    `status` varchar(15) NOT NULL,
+    `document_count` bigint NOT NULL DEFAULT 0,
+    `file_size` bigint NOT NULL DEFAULT 0,
+    `file_hash` varchar(64),
+    `file_path` varchar(255),
+    `error_message` varchar(500),
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `completed_at` timestamp,
+    PRIMARY KEY (`id`),
+    INDEX `idx_archive_branch` (`branch_id`),
+    INDEX `idx_archive_jobs_status` (`status`),
+    CONSTRAINT `fk_archive_jobs_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`),
+    CONSTRAINT `fk_archive_jobs_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
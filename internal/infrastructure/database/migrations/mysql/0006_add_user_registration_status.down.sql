@@ -0,0 +1,2 @@
+DROP INDEX `idx_user_registration_status` ON `users`;
+ALTER TABLE `users` DROP COLUMN `registration_status`;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `hacienda_credentials`;
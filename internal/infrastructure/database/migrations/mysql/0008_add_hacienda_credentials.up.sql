@@ -0,0 +1,15 @@
+-- Credenciales de Hacienda cifradas con la llave de datos de cada contribuyente y la versión de la llave maestra
+
+CREATE TABLE `hacienda_credentials` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `key_version` varchar(20) NOT NULL,
+    `wrapped_key` varchar(255) NOT NULL,
+    `ciphertext` text NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_hacienda_credentials_user_id` (`user_id`),
+    INDEX `idx_hacienda_credentials_key_version` (`key_version`),
+    CONSTRAINT `fk_hacienda_credentials_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
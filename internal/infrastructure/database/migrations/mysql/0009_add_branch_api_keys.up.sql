@@ -0,0 +1,16 @@
+-- Llaves de acceso adicionales de las sucursales con los scopes que pueden utilizar
+
+CREATE TABLE `branch_api_keys` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `branch_id` bigint unsigned NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `api_key` varchar(255) NOT NULL,
+    `api_secret` varchar(255) NOT NULL,
+    `scopes` varchar(255) NOT NULL,
+    `is_active` tinyint(1) NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_branch_api_keys_branch` (`branch_id`),
+    UNIQUE INDEX `idx_branch_api_keys_api_key` (`api_key`),
+    CONSTRAINT `fk_branch_api_keys_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`)
+);
@@ -0,0 +1,14 @@
+-- Los eventos sin contribuyente o sucursal no pueden conservarse en el esquema anterior, se eliminan junto con sus
+-- notificaciones
+DELETE FROM `user_notifications` WHERE `event_id` IN (SELECT `id` FROM `domain_events` WHERE `user_id` IS NULL OR `branch_id` IS NULL);
+DELETE FROM `domain_events` WHERE `user_id` IS NULL OR `branch_id` IS NULL;
+
+DROP INDEX `idx_domain_events_request_id` ON `domain_events`;
+DROP INDEX `idx_domain_events_actor_type` ON `domain_events`;
+ALTER TABLE `domain_events` DROP COLUMN `payload_digest`;
+ALTER TABLE `domain_events` DROP COLUMN `request_id`;
+ALTER TABLE `domain_events` DROP COLUMN `ip_address`;
+ALTER TABLE `domain_events` DROP COLUMN `actor_id`;
+ALTER TABLE `domain_events` DROP COLUMN `actor_type`;
+ALTER TABLE `domain_events` MODIFY `user_id` bigint unsigned NOT NULL;
+ALTER TABLE `domain_events` MODIFY `branch_id` bigint unsigned NOT NULL;
@@ -0,0 +1,3 @@
+-- Límite de DTE por minuto de cada sucursal, sin valor se utiliza el límite global
+
+ALTER TABLE `branch_offices` ADD COLUMN `rate_limit_per_minute` bigint;
@@ -0,0 +1,28 @@
+-- Operadores que administran varios contribuyentes y los permisos que tienen sobre cada uno
+
+CREATE TABLE `operators` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `name` varchar(150) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `api_key` varchar(255) NOT NULL,
+    `api_secret` varchar(255) NOT NULL,
+    `is_active` tinyint(1) NOT NULL,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_operators_email` (`email`),
+    UNIQUE INDEX `idx_operators_api_key` (`api_key`)
+);
+
+CREATE TABLE `operator_grants` (
+    `id` bigint unsigned AUTO_INCREMENT NOT NULL,
+    `operator_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `branch_id` bigint unsigned,
+    `created_at` timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `idx_operator_grants_operator` (`operator_id`),
+    INDEX `idx_operator_grants_user` (`user_id`),
+    CONSTRAINT `fk_operator_grants_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_operator_grants_branch` FOREIGN KEY (`branch_id`) REFERENCES `branch_offices`(`id`),
+    CONSTRAINT `fk_operators_grants` FOREIGN KEY (`operator_id`) REFERENCES `operators`(`id`)
+);
@@ -0,0 +1,5 @@
+DROP INDEX `idx_notification_next_attempt` ON `user_notifications`;
+ALTER TABLE `user_notifications` DROP COLUMN `next_attempt_at`;
+ALTER TABLE `user_notifications` DROP COLUMN `attempts`;
+DROP INDEX `idx_user_certificate_expiry` ON `users`;
+ALTER TABLE `users` DROP COLUMN `certificate_expires_at`;
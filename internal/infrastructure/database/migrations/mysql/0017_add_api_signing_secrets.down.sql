@@ -0,0 +1,2 @@
+ALTER TABLE `branch_api_keys` DROP COLUMN `api_signing_secret`;
+ALTER TABLE `branch_offices` DROP COLUMN `api_signing_secret`;
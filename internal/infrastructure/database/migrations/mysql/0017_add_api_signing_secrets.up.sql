@@ -0,0 +1,5 @@
+-- API secret cifrado con la llave maestra del vault, es la llave con la que se verifican las solicitudes firmadas con
+-- HMAC. Las llaves emitidas antes de esta versión no lo tienen y deben rotarse para utilizar HMAC
+
+ALTER TABLE `branch_offices` ADD COLUMN `api_signing_secret` varchar(255);
+ALTER TABLE `branch_api_keys` ADD COLUMN `api_signing_secret` varchar(255);
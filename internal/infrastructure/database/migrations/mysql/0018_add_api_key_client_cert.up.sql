@@ -0,0 +1,6 @@
+-- Subject o SAN del certificado de cliente asignado a una llave de acceso adicional, las solicitudes autenticadas con
+-- ese certificado obtienen los scopes de la llave. Los certificados asignados solo a la sucursal obtienen el scope de
+-- consulta
+
+ALTER TABLE `branch_api_keys` ADD COLUMN `client_cert_subject` varchar(255);
+CREATE UNIQUE INDEX `idx_branch_api_keys_client_cert_subject` ON `branch_api_keys` (`client_cert_subject`);
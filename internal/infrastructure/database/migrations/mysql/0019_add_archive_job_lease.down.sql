@@ -0,0 +1 @@
+ALTER TABLE `archive_jobs` DROP COLUMN `lease_until`;
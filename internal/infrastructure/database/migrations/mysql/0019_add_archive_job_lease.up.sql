@@ -0,0 +1,4 @@
+-- Reserva de los trabajos de exportación, el proceso que reserva un trabajo lo genera hasta lease_until y los demás
+-- procesos no lo retoman mientras la reserva siga vigente
+
+ALTER TABLE `archive_jobs` ADD COLUMN `lease_until` timestamp;
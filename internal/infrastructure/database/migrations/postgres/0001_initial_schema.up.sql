@@ -0,0 +1,221 @@
+-- Esquema inicial del microservicio, corresponde al esquema que creaba AutoMigrate antes de las migraciones
+-- versionadas. Las bases de datos creadas con AutoMigrate se registran en esta versión con "migrate baseline 1"
+
+CREATE TABLE "users" (
+    "id" bigserial NOT NULL,
+    "nit" varchar(17) NOT NULL,
+    "nrc" varchar(10) NOT NULL,
+    "status" smallint NOT NULL,
+    "auth_type" varchar(15) NOT NULL,
+    "password_pri" varchar(255) NOT NULL,
+    "commercial_name" varchar(150) NOT NULL,
+    "economic_activity" varchar(6) NOT NULL,
+    "economic_activity_desc" varchar(150) NOT NULL,
+    "business_name" varchar(200) NOT NULL,
+    "email" varchar(100) NOT NULL,
+    "phone" varchar(30) NOT NULL,
+    "year_in_dte" smallint NOT NULL,
+    "token_lifetime" bigint NOT NULL DEFAULT 14,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_user_phone" ON "users" ("phone");
+CREATE UNIQUE INDEX "idx_user_email" ON "users" ("email");
+CREATE INDEX "idx_user_status" ON "users" ("status");
+CREATE UNIQUE INDEX "idx_users_nrc" ON "users" ("nrc");
+CREATE UNIQUE INDEX "idx_users_nit" ON "users" ("nit");
+
+CREATE TABLE "branch_offices" (
+    "id" bigserial NOT NULL,
+    "user_id" bigint NOT NULL,
+    "establishment_code" varchar(10),
+    "establishment_code_mh" varchar(4),
+    "email" varchar(255),
+    "api_key" varchar(255) NOT NULL,
+    "api_secret" varchar(255) NOT NULL,
+    "phone" varchar(30),
+    "establishment_type" varchar(2) NOT NULL,
+    "pos_code" varchar(15),
+    "pos_code_mh" varchar(4),
+    "is_active" boolean NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_branch_offices_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_branch_est_type" ON "branch_offices" ("establishment_type");
+CREATE UNIQUE INDEX "idx_branch_offices_api_key" ON "branch_offices" ("api_key");
+CREATE INDEX "idx_branch_offices_user" ON "branch_offices" ("user_id");
+CREATE INDEX "idx_branch_offices_active" ON "branch_offices" ("is_active");
+
+CREATE TABLE "addresses" (
+    "id" bigserial NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "municipality" varchar(2) NOT NULL,
+    "department" varchar(2) NOT NULL,
+    "complement" varchar(200) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_branch_offices_address" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+CREATE INDEX "idx_address_branch" ON "addresses" ("branch_id");
+
+CREATE TABLE "dte_details" (
+    "id" text NOT NULL,
+    "dte_type" text NOT NULL,
+    "control_number" text NOT NULL,
+    "reception_stamp" text,
+    "transmission" text NOT NULL,
+    "status" text NOT NULL,
+    "json_data" json NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_dte_details_status" ON "dte_details" ("status");
+CREATE INDEX "idx_dte_details_control_number" ON "dte_details" ("control_number");
+CREATE INDEX "idx_dte_type" ON "dte_details" ("dte_type");
+CREATE INDEX "idx_dte_details" ON "dte_details" ("id");
+
+CREATE TABLE "dte_documents" (
+    "document_id" text NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("document_id"),
+    CONSTRAINT "fk_dte_documents_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id"),
+    CONSTRAINT "fk_dte_documents_document" FOREIGN KEY ("document_id") REFERENCES "dte_details"("id")
+);
+CREATE INDEX "idx_dte_date" ON "dte_documents" ("created_at");
+CREATE INDEX "idx_dte_branch" ON "dte_documents" ("branch_id");
+CREATE INDEX "idx_dte_document" ON "dte_documents" ("document_id");
+
+CREATE TABLE "contingency_documents" (
+    "id" varchar(36) NOT NULL,
+    "document_id" text NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "type" smallint NOT NULL,
+    "reason" varchar(150) NOT NULL,
+    "batch_id" varchar(36),
+    "mh_batch_id" varchar(36),
+    "observations" text,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_contingency_documents_document" FOREIGN KEY ("document_id") REFERENCES "dte_details"("id"),
+    CONSTRAINT "fk_contingency_documents_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+CREATE INDEX "idx_contingency_documents_batch_id" ON "contingency_documents" ("batch_id");
+CREATE INDEX "idx_contingency_documents_reason" ON "contingency_documents" ("reason");
+CREATE INDEX "idx_contingency_documents_contingency_type" ON "contingency_documents" ("type");
+CREATE INDEX "idx_contingency_branch" ON "contingency_documents" ("branch_id");
+CREATE INDEX "idx_contingency_documents_document_id" ON "contingency_documents" ("document_id");
+CREATE INDEX "idx_contingency_date" ON "contingency_documents" ("created_at");
+
+CREATE TABLE "control_number_sequences" (
+    "id" bigserial NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "dte_type" varchar(2) NOT NULL,
+    "year" bigint NOT NULL,
+    "last_number" bigint NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_control_number_sequences_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+CREATE INDEX "idx_sequence_year" ON "control_number_sequences" ("year");
+CREATE UNIQUE INDEX "idx_branch_dte_type" ON "control_number_sequences" ("branch_id","dte_type");
+
+CREATE TABLE "failed_sequence_numbers" (
+    "id" bigserial NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "dte_type" varchar(2) NOT NULL,
+    "sequence_number" bigint NOT NULL,
+    "year" bigint NOT NULL,
+    "failure_reason" text NOT NULL,
+    "response_code" varchar(10),
+    "original_request_data" json NOT NULL,
+    "mh_response" text,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_failed_seq" ON "failed_sequence_numbers" ("branch_id","dte_type","sequence_number","year");
+
+CREATE TABLE "domain_events" (
+    "id" bigserial NOT NULL,
+    "user_id" bigint NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "event_type" varchar(50) NOT NULL,
+    "payload" json NOT NULL,
+    "occurred_at" timestamp NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_domain_events_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_domain_events_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+CREATE INDEX "idx_domain_events_occurred_at" ON "domain_events" ("occurred_at");
+CREATE INDEX "idx_domain_events_event_type" ON "domain_events" ("event_type");
+CREATE INDEX "idx_event_branch" ON "domain_events" ("branch_id");
+CREATE INDEX "idx_event_user" ON "domain_events" ("user_id");
+
+CREATE TABLE "user_notifications" (
+    "id" bigserial NOT NULL,
+    "user_id" bigint NOT NULL,
+    "event_id" bigint NOT NULL,
+    "notification_type" varchar(15) NOT NULL,
+    "message" text NOT NULL,
+    "delivery_status" varchar(15) NOT NULL,
+    "delivery_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_user_notifications_event" FOREIGN KEY ("event_id") REFERENCES "domain_events"("id")
+);
+CREATE INDEX "idx_user_notifications_delivery_status" ON "user_notifications" ("delivery_status");
+CREATE INDEX "idx_user_notifications_notification_type" ON "user_notifications" ("notification_type");
+CREATE INDEX "idx_notification_event" ON "user_notifications" ("event_id");
+CREATE INDEX "idx_notification_user" ON "user_notifications" ("user_id");
+
+CREATE TABLE "notification_users" (
+    "id" bigserial NOT NULL,
+    "user_id" bigint NOT NULL,
+    "entity_type" varchar(10) NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "enabled_push" smallint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_push_enabled" ON "notification_users" ("enabled_push");
+CREATE INDEX "idx_notifiable_email" ON "notification_users" ("email");
+CREATE INDEX "idx_entity" ON "notification_users" ("user_id","entity_type");
+
+CREATE TABLE "dte_balance_control" (
+    "id" bigserial NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "original_dte_id" text NOT NULL,
+    "original_taxed_amount" decimal(18,2) NOT NULL,
+    "original_exempt_amount" decimal(18,2) NOT NULL,
+    "original_not_subject_amount" decimal(18,2) NOT NULL,
+    "remaining_taxed_amount" decimal(18,2) NOT NULL,
+    "remaining_exempt_amount" decimal(18,2) NOT NULL,
+    "remaining_not_subject_amount" decimal(18,2) NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_dte_balance_control_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id"),
+    CONSTRAINT "fk_dte_details_balance_control" FOREIGN KEY ("original_dte_id") REFERENCES "dte_details"("id")
+);
+CREATE INDEX "idx_dte_original" ON "dte_balance_control" ("original_dte_id");
+CREATE INDEX "idx_dte_balance_branch" ON "dte_balance_control" ("branch_id");
+CREATE INDEX "idx_dte_balance_control" ON "dte_balance_control" ("id");
+
+CREATE TABLE "dte_balance_transactions" (
+    "id" bigserial NOT NULL,
+    "balance_control_id" bigint NOT NULL,
+    "adjustment_document_id" text NOT NULL,
+    "transaction_type" varchar(20) NOT NULL,
+    "taxed_amount" decimal(18,2) NOT NULL,
+    "exempt_amount" decimal(18,2) NOT NULL,
+    "not_subject_amount" decimal(18,2) NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_dte_balance_transactions_adjustment_document" FOREIGN KEY ("adjustment_document_id") REFERENCES "dte_details"("id"),
+    CONSTRAINT "fk_dte_balance_control_transactions" FOREIGN KEY ("balance_control_id") REFERENCES "dte_balance_control"("id")
+);
+CREATE INDEX "idx_dte_adjustment_document" ON "dte_balance_transactions" ("adjustment_document_id");
+CREATE INDEX "idx_dte_balance_transaction_control" ON "dte_balance_transactions" ("balance_control_id");
+CREATE INDEX "idx_dte_balance_transaction" ON "dte_balance_transactions" ("id");
+CREATE INDEX "idx_dte_transaction_type" ON "dte_balance_transactions" ("transaction_type");
@@ -0,0 +1,15 @@
+-- Personalización de la representación gráfica de los DTE por sucursal: logo, color principal y pie de página
+
+CREATE TABLE "branch_brandings" (
+    "id" bigserial NOT NULL,
+    "branch_id" bigint NOT NULL,
+    "logo" bytea,
+    "logo_format" varchar(5),
+    "primary_color" varchar(7) NOT NULL,
+    "footer_text" varchar(255),
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_branch_brandings_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+CREATE UNIQUE INDEX "idx_branch_brandings_branch_id" ON "branch_brandings" ("branch_id");
@@ -0,0 +1,6 @@
+-- Destinatario, documento y error de las notificaciones de envío de DTE por correo al receptor
+
+ALTER TABLE "user_notifications" ADD COLUMN "recipient" varchar(255);
+ALTER TABLE "user_notifications" ADD COLUMN "document_id" varchar(36);
+ALTER TABLE "user_notifications" ADD COLUMN "error_message" text;
+CREATE INDEX "idx_notification_document" ON "user_notifications" ("document_id");
@@ -0,0 +1,8 @@
+DROP INDEX "idx_dte_total_amount";
+ALTER TABLE "dte_details" DROP COLUMN "total_amount";
+DROP INDEX "idx_dte_receiver_name";
+ALTER TABLE "dte_details" DROP COLUMN "receiver_name";
+DROP INDEX "idx_dte_receiver_nrc";
+ALTER TABLE "dte_details" DROP COLUMN "receiver_nrc";
+DROP INDEX "idx_dte_receiver_nit";
+ALTER TABLE "dte_details" DROP COLUMN "receiver_nit";
@@ -0,0 +1,12 @@
+-- Auditoría de las acciones que modifican el estado: actor, IP y solicitud de origen y el digest del payload. El
+-- contribuyente y la sucursal pasan a ser opcionales para conservar la auditoría de los registros rechazados
+
+ALTER TABLE "domain_events" ADD COLUMN "actor_type" varchar(20) NOT NULL DEFAULT 'SYSTEM';
+ALTER TABLE "domain_events" ADD COLUMN "actor_id" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "domain_events" ADD COLUMN "ip_address" varchar(45);
+ALTER TABLE "domain_events" ADD COLUMN "request_id" varchar(64);
+ALTER TABLE "domain_events" ADD COLUMN "payload_digest" varchar(64);
+CREATE INDEX "idx_domain_events_actor_type" ON "domain_events" ("actor_type");
+CREATE INDEX "idx_domain_events_request_id" ON "domain_events" ("request_id");
+ALTER TABLE "domain_events" ALTER COLUMN "user_id" DROP NOT NULL;
+ALTER TABLE "domain_events" ALTER COLUMN "branch_id" DROP NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE "branch_offices" DROP COLUMN "rate_limit_per_minute";
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "operator_grants";
+DROP TABLE IF EXISTS "operators";
@@ -0,0 +1,28 @@
+-- Operadores que administran varios contribuyentes y los permisos que tienen sobre cada uno
+
+CREATE TABLE "operators" (
+    "id" bigserial NOT NULL,
+    "name" varchar(150) NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "api_key" varchar(255) NOT NULL,
+    "api_secret" varchar(255) NOT NULL,
+    "is_active" boolean NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_operators_email" ON "operators" ("email");
+CREATE UNIQUE INDEX "idx_operators_api_key" ON "operators" ("api_key");
+
+CREATE TABLE "operator_grants" (
+    "id" bigserial NOT NULL,
+    "operator_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "branch_id" bigint,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_operator_grants_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_operator_grants_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id"),
+    CONSTRAINT "fk_operators_grants" FOREIGN KEY ("operator_id") REFERENCES "operators"("id")
+);
+CREATE INDEX "idx_operator_grants_user" ON "operator_grants" ("user_id");
+CREATE INDEX "idx_operator_grants_operator" ON "operator_grants" ("operator_id");
@@ -0,0 +1,2 @@
+DROP INDEX "idx_branch_offices_client_cert_subject";
+ALTER TABLE "branch_offices" DROP COLUMN "client_cert_subject";
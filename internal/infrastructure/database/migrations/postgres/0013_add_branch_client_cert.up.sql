@@ -0,0 +1,4 @@
+-- Subject o SAN del certificado de cliente con el que se autentica una sucursal mediante mTLS
+
+ALTER TABLE "branch_offices" ADD COLUMN "client_cert_subject" varchar(255);
+CREATE UNIQUE INDEX "idx_branch_offices_client_cert_subject" ON "branch_offices" ("client_cert_subject");
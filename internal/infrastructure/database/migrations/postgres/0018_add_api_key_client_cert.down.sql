@@ -0,0 +1,2 @@
+DROP INDEX "idx_branch_api_keys_client_cert_subject";
+ALTER TABLE "branch_api_keys" DROP COLUMN "client_cert_subject";
@@ -0,0 +1,10 @@
+-- Columnas generadas de dte_details utilizadas en la búsqueda de documentos y en las métricas de negocio
+-- SQLite no permite agregar columnas generadas STORED con ALTER TABLE, las columnas de búsqueda son VIRTUAL e indexadas
+ALTER TABLE "dte_details" ADD COLUMN "receiver_nit" VARCHAR(20) GENERATED ALWAYS AS (COALESCE(json_extract(json_data, '$.receptor.nit'), json_extract(json_data, '$.receptor.numDocumento'))) VIRTUAL;
+CREATE INDEX "idx_dte_receiver_nit" ON "dte_details" ("receiver_nit");
+ALTER TABLE "dte_details" ADD COLUMN "receiver_nrc" VARCHAR(10) GENERATED ALWAYS AS (json_extract(json_data, '$.receptor.nrc')) VIRTUAL;
+CREATE INDEX "idx_dte_receiver_nrc" ON "dte_details" ("receiver_nrc");
+ALTER TABLE "dte_details" ADD COLUMN "receiver_name" VARCHAR(250) GENERATED ALWAYS AS (json_extract(json_data, '$.receptor.nombre')) VIRTUAL;
+CREATE INDEX "idx_dte_receiver_name" ON "dte_details" ("receiver_name");
+ALTER TABLE "dte_details" ADD COLUMN "total_amount" NUMERIC(18,2) GENERATED ALWAYS AS (ROUND(CAST(COALESCE(json_extract(json_data, '$.resumen.totalPagar'), json_extract(json_data, '$.resumen.montoTotalOperacion'), json_extract(json_data, '$.resumen.totalIVAretenido')) AS REAL), 2)) VIRTUAL;
+CREATE INDEX "idx_dte_total_amount" ON "dte_details" ("total_amount");
@@ -0,0 +1,2 @@
+DROP INDEX "idx_user_registration_status";
+ALTER TABLE "users" DROP COLUMN "registration_status";
@@ -0,0 +1,4 @@
+-- Estado de aprobación del registro de los contribuyentes, los registrados antes de esta versión quedan aprobados
+
+ALTER TABLE "users" ADD COLUMN "registration_status" varchar(10) NOT NULL DEFAULT 'APPROVED';
+CREATE INDEX "idx_user_registration_status" ON "users" ("registration_status");
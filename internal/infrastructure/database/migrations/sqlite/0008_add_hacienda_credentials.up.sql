@@ -0,0 +1,14 @@
+-- Credenciales de Hacienda cifradas con la llave de datos de cada contribuyente y la versión de la llave maestra
+
+CREATE TABLE "hacienda_credentials" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "user_id" bigint NOT NULL,
+    "key_version" varchar(20) NOT NULL,
+    "wrapped_key" varchar(255) NOT NULL,
+    "ciphertext" text NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT "fk_hacienda_credentials_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX "idx_hacienda_credentials_user_id" ON "hacienda_credentials" ("user_id");
+CREATE INDEX "idx_hacienda_credentials_key_version" ON "hacienda_credentials" ("key_version");
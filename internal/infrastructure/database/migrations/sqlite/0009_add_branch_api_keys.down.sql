@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "branch_api_keys";
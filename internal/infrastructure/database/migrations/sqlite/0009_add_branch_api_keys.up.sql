@@ -0,0 +1,15 @@
+-- Llaves de acceso adicionales de las sucursales con los scopes que pueden utilizar
+
+CREATE TABLE "branch_api_keys" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "branch_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "api_key" varchar(255) NOT NULL,
+    "api_secret" varchar(255) NOT NULL,
+    "scopes" varchar(255) NOT NULL,
+    "is_active" boolean NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT "fk_branch_api_keys_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+CREATE UNIQUE INDEX "idx_branch_api_keys_api_key" ON "branch_api_keys" ("api_key");
+CREATE INDEX "idx_branch_api_keys_branch" ON "branch_api_keys" ("branch_id");
@@ -0,0 +1,54 @@
+-- Auditoría de las acciones que modifican el estado: actor, IP y solicitud de origen y el digest del payload. El
+-- contribuyente y la sucursal pasan a ser opcionales para conservar la auditoría de los registros rechazados
+-- SQLite no permite modificar la nulabilidad de una columna y al renombrar una tabla actualiza las llaves foráneas que la
+-- referencian, por lo que domain_events se reconstruye junto con user_notifications
+
+CREATE TABLE "domain_events_new" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "user_id" bigint,
+    "branch_id" bigint,
+    "event_type" varchar(50) NOT NULL,
+    "actor_type" varchar(20) NOT NULL DEFAULT 'SYSTEM',
+    "actor_id" bigint NOT NULL DEFAULT 0,
+    "ip_address" varchar(45),
+    "request_id" varchar(64),
+    "payload" json NOT NULL,
+    "payload_digest" varchar(64),
+    "occurred_at" timestamp NOT NULL,
+    CONSTRAINT "fk_domain_events_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_domain_events_branch" FOREIGN KEY ("branch_id") REFERENCES "branch_offices"("id")
+);
+INSERT INTO "domain_events_new" ("id", "user_id", "branch_id", "event_type", "payload", "occurred_at") SELECT "id", "user_id", "branch_id", "event_type", "payload", "occurred_at" FROM "domain_events";
+
+CREATE TABLE "user_notifications_new" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "user_id" bigint NOT NULL,
+    "event_id" bigint NOT NULL,
+    "notification_type" varchar(15) NOT NULL,
+    "message" text NOT NULL,
+    "delivery_status" varchar(15) NOT NULL,
+    "delivery_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "recipient" varchar(255),
+    "document_id" varchar(36),
+    "error_message" text,
+    CONSTRAINT "fk_user_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_user_notifications_event" FOREIGN KEY ("event_id") REFERENCES "domain_events_new"("id")
+);
+INSERT INTO "user_notifications_new" ("id", "user_id", "event_id", "notification_type", "message", "delivery_status", "delivery_at", "recipient", "document_id", "error_message") SELECT "id", "user_id", "event_id", "notification_type", "message", "delivery_status", "delivery_at", "recipient", "document_id", "error_message" FROM "user_notifications";
+
+DROP TABLE "user_notifications";
+DROP TABLE "domain_events";
+ALTER TABLE "domain_events_new" RENAME TO "domain_events";
+ALTER TABLE "user_notifications_new" RENAME TO "user_notifications";
+
+CREATE INDEX "idx_domain_events_occurred_at" ON "domain_events" ("occurred_at");
+CREATE INDEX "idx_domain_events_event_type" ON "domain_events" ("event_type");
+CREATE INDEX "idx_event_branch" ON "domain_events" ("branch_id");
+CREATE INDEX "idx_event_user" ON "domain_events" ("user_id");
+CREATE INDEX "idx_domain_events_request_id" ON "domain_events" ("request_id");
+CREATE INDEX "idx_domain_events_actor_type" ON "domain_events" ("actor_type");
+CREATE INDEX "idx_user_notifications_delivery_status" ON "user_notifications" ("delivery_status");
+CREATE INDEX "idx_user_notifications_notification_type" ON "user_notifications" ("notification_type");
+CREATE INDEX "idx_notification_event" ON "user_notifications" ("event_id");
+CREATE INDEX "idx_notification_user" ON "user_notifications" ("user_id");
+CREATE INDEX "idx_notification_document" ON "user_notifications" ("document_id");
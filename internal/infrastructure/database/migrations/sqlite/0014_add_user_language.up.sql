@@ -0,0 +1,3 @@
+-- Idioma preferido del contribuyente para las respuestas y notificaciones
+
+ALTER TABLE "users" ADD COLUMN "language" varchar(5);
@@ -0,0 +1,46 @@
+-- Suscripciones a webhooks de los eventos de los DTE, sus entregas y el registro de cada intento
+
+CREATE TABLE "webhook_subscriptions" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "user_id" bigint NOT NULL,
+    "url" varchar(500) NOT NULL,
+    "events" varchar(255) NOT NULL,
+    "secret" varchar(255) NOT NULL,
+    "is_active" boolean NOT NULL,
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT "fk_webhook_subscriptions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_webhook_subscription_user" ON "webhook_subscriptions" ("user_id");
+
+CREATE TABLE "webhook_deliveries" (
+    "id" varchar(36) NOT NULL,
+    "subscription_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "event_id" varchar(36) NOT NULL,
+    "event_type" varchar(50) NOT NULL,
+    "payload" json NOT NULL,
+    "status" varchar(15) NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamp,
+    "last_status_code" bigint,
+    "last_error" varchar(500),
+    "created_at" timestamp DEFAULT CURRENT_TIMESTAMP,
+    "delivered_at" timestamp,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_webhook_deliveries_subscription" FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions"("id")
+);
+CREATE INDEX "idx_webhook_delivery_due" ON "webhook_deliveries" ("status","next_attempt_at");
+CREATE INDEX "idx_webhook_delivery_user" ON "webhook_deliveries" ("user_id");
+CREATE INDEX "idx_webhook_delivery_subscription" ON "webhook_deliveries" ("subscription_id");
+
+CREATE TABLE "webhook_delivery_attempts" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "delivery_id" varchar(36) NOT NULL,
+    "attempt" bigint NOT NULL,
+    "status_code" bigint,
+    "error_message" varchar(500),
+    "duration_ms" bigint NOT NULL DEFAULT 0,
+    "attempted_at" timestamp NOT NULL,
+    CONSTRAINT "fk_webhook_deliveries_logs" FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries"("id")
+);
+CREATE INDEX "idx_webhook_attempt_delivery" ON "webhook_delivery_attempts" ("delivery_id");
@@ -0,0 +1,7 @@
+-- Vencimiento del certificado de firma del contribuyente y los reintentos de las alertas del motor de notificaciones
+
+ALTER TABLE "users" ADD COLUMN "certificate_expires_at" timestamp;
+CREATE INDEX "idx_user_certificate_expiry" ON "users" ("certificate_expires_at");
+ALTER TABLE "user_notifications" ADD COLUMN "attempts" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "user_notifications" ADD COLUMN "next_attempt_at" timestamp;
+CREATE INDEX "idx_notification_next_attempt" ON "user_notifications" ("next_attempt_at");
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	errPackage "github.com/MarlonG1/api-facturacion-sv/config/error"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// createSchemaMigrationsTable crea la tabla de versiones con SQL compatible con todos los drivers soportados
+const createSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	dirty BOOLEAN NOT NULL DEFAULT FALSE,
+	applied_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (version)
+)`
+
+// MigrationStatus estado de una migración en la base de datos
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+// Migrator aplica y revierte las migraciones del driver de la base de datos, registrando cada versión aplicada en la
+// tabla schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator crea el migrador con las migraciones del driver de la conexión
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LatestVersion retorna la versión del esquema que espera la aplicación
+func (m *Migrator) LatestVersion() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// CurrentVersion retorna la versión más alta aplicada en la base de datos y si alguna migración quedó incompleta
+func (m *Migrator) CurrentVersion() (uint, bool, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, false, err
+	}
+
+	var version uint
+	var dirty bool
+	for _, migration := range applied {
+		if migration.Version > version {
+			version = migration.Version
+		}
+		dirty = dirty || migration.Dirty
+	}
+
+	return version, dirty, nil
+}
+
+// Up aplica en orden las migraciones pendientes y retorna la cantidad aplicada
+func (m *Migrator) Up() (int, error) {
+	// 1. Verificar que ninguna migración haya quedado incompleta
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if err = checkDirty(applied); err != nil {
+		return 0, err
+	}
+
+	// 2. Aplicar las migraciones que no están registradas
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		logs.Info("Applying database migration", map[string]interface{}{
+			"version": migration.Version,
+			"name":    migration.Name,
+		})
+
+		if err = m.run(migration, migration.Up, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	logs.Info("Database migrations completed", map[string]interface{}{
+		"applied": count,
+		"version": m.LatestVersion(),
+	})
+	return count, nil
+}
+
+// Down revierte las últimas steps migraciones aplicadas y retorna la cantidad revertida
+func (m *Migrator) Down(steps int) (int, error) {
+	// 1. Verificar que ninguna migración haya quedado incompleta
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if err = checkDirty(applied); err != nil {
+		return 0, err
+	}
+
+	// 2. Revertir las migraciones aplicadas de la más reciente a la más antigua
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		logs.Info("Reverting database migration", map[string]interface{}{
+			"version": migration.Version,
+			"name":    migration.Name,
+		})
+
+		if err = m.run(migration, migration.Down, false); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Status retorna el estado de cada migración conocida por la aplicación
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		item := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			item.Applied = true
+			item.Dirty = record.Dirty
+			item.AppliedAt = &appliedAt
+		}
+		status = append(status, item)
+	}
+
+	return status, nil
+}
+
+// Baseline registra como aplicadas, sin ejecutarlas, las migraciones hasta la versión indicada. Permite adoptar el
+// control de versiones en una base de datos creada antes de las migraciones versionadas, solo si aún no tiene versiones
+// y tiene las tablas y columnas que crean esas migraciones
+func (m *Migrator) Baseline(version uint) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		return fmt.Errorf("baseline requires an unversioned database, %d migrations are already registered", len(applied))
+	}
+
+	found := false
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("migration version %d does not exist", version)
+	}
+
+	if err = m.verifySchema(version); err != nil {
+		return err
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+
+			record := &db_models.SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: utils.TimeNow(),
+			}
+			if err := tx.Create(record).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CheckVersion verifica que la versión del esquema sea la que espera la aplicación, retorna ErrDirtySchema si una
+// migración quedó incompleta y ErrSchemaVersionMismatch si la base de datos está atrasada o adelantada
+func (m *Migrator) CheckVersion() error {
+	current, dirty, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("%w: a migration did not finish, fix the schema manually and remove the dirty version from schema_migrations", errPackage.ErrDirtySchema)
+	}
+
+	if current != m.LatestVersion() {
+		return fmt.Errorf("%w: database is at version %d and the application expects version %d, run 'migrate up' or 'migrate baseline' for databases created before versioned migrations",
+			errPackage.ErrSchemaVersionMismatch, current, m.LatestVersion())
+	}
+
+	return nil
+}
+
+// verifySchema verifica que la base de datos tenga las tablas y columnas que crean las migraciones hasta la versión
+// indicada, retorna ErrSchemaVersionMismatch con los elementos faltantes para no registrar una versión que no se aplicó
+func (m *Migrator) verifySchema(version uint) error {
+	expected := expectedSchema(m.migrations, version)
+
+	tables := make([]string, 0, len(expected))
+	for table := range expected {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	missing := make([]string, 0)
+	for _, table := range tables {
+		if !m.db.Migrator().HasTable(table) {
+			missing = append(missing, table)
+			continue
+		}
+
+		for _, column := range expected[table] {
+			if !m.db.Migrator().HasColumn(table, column) {
+				missing = append(missing, table+"."+column)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: the database does not match schema version %d, missing %s",
+			errPackage.ErrSchemaVersionMismatch, version, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// run ejecuta una migración en una transacción. La versión se marca como incompleta antes de ejecutarla para detectar
+// los cambios que el driver no puede revertir, como las sentencias DDL en MySQL
+func (m *Migrator) run(migration Migration, fn func(tx *gorm.DB) error, up bool) error {
+	// 1. Marcar la versión como incompleta
+	record := &db_models.SchemaMigration{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Dirty:     true,
+		AppliedAt: utils.TimeNow(),
+	}
+	if up {
+		if err := m.db.Create(record).Error; err != nil {
+			return err
+		}
+	} else if err := m.db.Model(record).Update("dirty", true).Error; err != nil {
+		return err
+	}
+
+	// 2. Ejecutar la migración
+	if err := m.db.Transaction(fn); err != nil {
+		logs.Error("Database migration failed", map[string]interface{}{
+			"version": migration.Version,
+			"name":    migration.Name,
+			"up":      up,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	// 3. Registrar el resultado, la versión aplicada queda completa y la revertida se elimina
+	if up {
+		return m.db.Model(record).Update("dirty", false).Error
+	}
+	return m.db.Delete(record).Error
+}
+
+// applied obtiene las versiones registradas en la base de datos, crea la tabla de versiones si no existe
+func (m *Migrator) applied() (map[uint]db_models.SchemaMigration, error) {
+	if err := m.db.Exec(createSchemaMigrationsTable).Error; err != nil {
+		return nil, err
+	}
+
+	var records []db_models.SchemaMigration
+	if err := m.db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]db_models.SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// checkDirty retorna ErrDirtySchema si alguna versión quedó incompleta
+func checkDirty(applied map[uint]db_models.SchemaMigration) error {
+	for _, record := range applied {
+		if record.Dirty {
+			return fmt.Errorf("%w: migration %d_%s did not finish, fix the schema manually and remove it from schema_migrations",
+				errPackage.ErrDirtySchema, record.Version, record.Name)
+		}
+	}
+	return nil
+}
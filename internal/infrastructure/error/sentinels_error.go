@@ -10,8 +10,12 @@ var (
 	ErrBranchOfficeNotFound    = errors.New("branch office not found or actually inactive")
 	ErrUserNotFound            = errors.New("user not found or actually inactive")
 	ErrBranchDoesNotBelong     = errors.New("branch office does not belong to the user")
+	ErrAPIKeyNotFound          = errors.New("api key not found")
 	ErrExpiredToken            = fmt.Errorf("token has expired, please login again")
 	ErrDTEDocumentNotFound     = errors.New("dte document not found")
 	ErrHaciendaTokenGeneration = fmt.Errorf("failed to generate Hacienda token")
 	ErrInvalidDocumentJSON     = fmt.Errorf("invalid document JSON")
+	ErrOperatorNotFound        = errors.New("operator not found")
+	ErrOperatorGrantExists     = errors.New("operator already has access to the taxpayer or branch office")
+	ErrOperatorGrantNotFound   = errors.New("operator grant not found")
 )
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+)
+
+type ArchiveCleanupJob struct {
+	ArchiveService   archive.ArchiveManager
+	IsRunning        atomic.Bool
+	MaxExecutionTime time.Duration
+}
+
+func NewArchiveCleanupJob(archiveService archive.ArchiveManager) *ArchiveCleanupJob {
+	return &ArchiveCleanupJob{
+		ArchiveService:   archiveService,
+		MaxExecutionTime: 10 * time.Minute,
+	}
+}
+
+// Execute ejecuta el trabajo de eliminación de los archivos de exportación que superaron su tiempo de retención.
+func (j *ArchiveCleanupJob) Execute() {
+	// Evitar ejecuciones concurrentes
+	if !j.IsRunning.CompareAndSwap(false, true) {
+		logs.Warn("Archive cleanup job already running, skipping execution")
+		return
+	}
+	defer j.IsRunning.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.MaxExecutionTime)
+	defer cancel()
+
+	ctx, span := tracing.StartRoot(ctx, "ArchiveCleanupJob.Execute")
+	err := j.ArchiveService.CleanupExpired(ctx)
+	tracing.End(span, err)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			logs.Warn("Archive cleanup job timed out, remaining archives will be removed in the next execution", map[string]interface{}{
+				"MaxExecutionTime": j.MaxExecutionTime,
+			})
+			return
+		}
+
+		logs.Error("Archive cleanup job failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
 	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
 )
 
@@ -53,7 +54,10 @@ func (j *RetransmissionJob) Execute() {
 	}
 	sqlDb.Ping()
 
-	if err := j.ContingencyService.RetransmitPendingDocuments(ctx); err != nil {
+	ctx, span := tracing.StartRoot(ctx, "RetransmissionJob.Execute")
+	err = j.ContingencyService.RetransmitPendingDocuments(ctx)
+	tracing.End(span, err)
+	if err != nil {
 		j.handleExecutionError(err)
 		return
 	}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+)
+
+type NotificationDeliveryJob struct {
+	NotificationService notification.NotificationManager
+	IsRunning           atomic.Bool
+	MaxExecutionTime    time.Duration
+}
+
+func NewNotificationDeliveryJob(notificationService notification.NotificationManager) *NotificationDeliveryJob {
+	return &NotificationDeliveryJob{
+		NotificationService: notificationService,
+		MaxExecutionTime:    5 * time.Minute,
+	}
+}
+
+// Execute ejecuta el trabajo de reintento de las notificaciones pendientes.
+func (j *NotificationDeliveryJob) Execute() {
+	// Evitar ejecuciones concurrentes
+	if !j.IsRunning.CompareAndSwap(false, true) {
+		logs.Warn("Notification delivery job already running, skipping execution")
+		return
+	}
+	defer j.IsRunning.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.MaxExecutionTime)
+	defer cancel()
+
+	ctx, span := tracing.StartRoot(ctx, "NotificationDeliveryJob.Execute")
+	err := j.NotificationService.ProcessPendingNotifications(ctx)
+	tracing.End(span, err)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			logs.Warn("Notification delivery job timed out, remaining notifications will be retried", map[string]interface{}{
+				"MaxExecutionTime": j.MaxExecutionTime,
+			})
+			return
+		}
+
+		logs.Error("Notification delivery job failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+)
+
+type NotificationScanJob struct {
+	NotificationService notification.NotificationManager
+	IsRunning           atomic.Bool
+	MaxExecutionTime    time.Duration
+}
+
+func NewNotificationScanJob(notificationService notification.NotificationManager) *NotificationScanJob {
+	return &NotificationScanJob{
+		NotificationService: notificationService,
+		MaxExecutionTime:    10 * time.Minute,
+	}
+}
+
+// Execute ejecuta la revisión de las alertas que dependen del tiempo: vencimiento de certificados, plazo de
+// contingencia y saltos en la numeración.
+func (j *NotificationScanJob) Execute() {
+	// Evitar ejecuciones concurrentes
+	if !j.IsRunning.CompareAndSwap(false, true) {
+		logs.Warn("Notification scan job already running, skipping execution")
+		return
+	}
+	defer j.IsRunning.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.MaxExecutionTime)
+	defer cancel()
+
+	ctx, span := tracing.StartRoot(ctx, "NotificationScanJob.Execute")
+	err := j.NotificationService.ScanScheduledAlerts(ctx)
+	tracing.End(span, err)
+	if err != nil {
+		logs.Error("Notification scan job failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
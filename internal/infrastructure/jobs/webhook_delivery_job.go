@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/logs"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+)
+
+type WebhookDeliveryJob struct {
+	WebhookService   webhook.WebhookManager
+	IsRunning        atomic.Bool
+	MaxExecutionTime time.Duration
+}
+
+func NewWebhookDeliveryJob(webhookService webhook.WebhookManager) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		WebhookService:   webhookService,
+		MaxExecutionTime: 5 * time.Minute,
+	}
+}
+
+// Execute ejecuta el trabajo de reintento de las entregas de webhooks pendientes.
+func (j *WebhookDeliveryJob) Execute() {
+	// Evitar ejecuciones concurrentes
+	if !j.IsRunning.CompareAndSwap(false, true) {
+		logs.Warn("Webhook delivery job already running, skipping execution")
+		return
+	}
+	defer j.IsRunning.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.MaxExecutionTime)
+	defer cancel()
+
+	ctx, span := tracing.StartRoot(ctx, "WebhookDeliveryJob.Execute")
+	err := j.WebhookService.ProcessPendingDeliveries(ctx)
+	tracing.End(span, err)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			logs.Warn("Webhook delivery job timed out, remaining deliveries will be retried", map[string]interface{}{
+				"MaxExecutionTime": j.MaxExecutionTime,
+			})
+			return
+		}
+
+		logs.Error("Webhook delivery job failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package structs
+
+// CreateArchiveRequest solicitud para generar el archivo de los DTE de un período. Las fechas tienen el formato
+// YYYY-MM-DD y ambas se incluyen en el período. El alcance puede ser "branch" (por defecto) o "nit".
+type CreateArchiveRequest struct {
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	Scope      string `json:"scope,omitempty"`
+	IncludePDF bool   `json:"include_pdf,omitempty"`
+}
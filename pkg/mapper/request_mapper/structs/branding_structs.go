@@ -0,0 +1,11 @@
+package structs
+
+// UpdateBrandingRequest estructura para mapear la configuración de marca de una sucursal
+// El logo se recibe codificado en base64, si RemoveLogo es verdadero se elimina el logo almacenado
+type UpdateBrandingRequest struct {
+	Logo         *string `json:"logo,omitempty"`
+	LogoFormat   string  `json:"logo_format,omitempty"`
+	PrimaryColor string  `json:"primary_color,omitempty"`
+	FooterText   string  `json:"footer_text,omitempty"`
+	RemoveLogo   bool    `json:"remove_logo,omitempty"`
+}
@@ -0,0 +1,7 @@
+package structs
+
+// ResendDTERequest solicitud para reenviar un DTE por correo electrónico,
+// si no se indican destinatarios se utiliza el correo del receptor del documento
+type ResendDTERequest struct {
+	Recipients []string `json:"recipients,omitempty"`
+}
@@ -0,0 +1,14 @@
+package structs
+
+// CreateNotificationRecipientRequest solicitud para registrar un destinatario de las alertas, si no se indica
+// enabled_push el destinatario queda habilitado
+type CreateNotificationRecipientRequest struct {
+	Email       string `json:"email"`
+	EnabledPush *bool  `json:"enabled_push,omitempty"`
+}
+
+// SetCertificateExpiryRequest solicitud para registrar la fecha de vencimiento del certificado de firma en formato
+// YYYY-MM-DD, una fecha vacía desactiva la alerta de vencimiento
+type SetCertificateExpiryRequest struct {
+	ExpiresAt string `json:"expires_at"`
+}
@@ -0,0 +1,9 @@
+package structs
+
+// CreateWebhookSubscriptionRequest solicitud para suscribir una URL a los eventos del ciclo de vida de los DTE del
+// contribuyente, los eventos disponibles son dte.issued, dte.received, dte.rejected, dte.contingency_stored,
+// dte.retransmitted, dte.invalidated, batch.completed y notification.created
+type CreateWebhookSubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
@@ -0,0 +1,83 @@
+package logs
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DebugContext registra un mensaje de depuración con los campos de correlación del contexto
+func DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	logWithContext(ctx, logrus.DebugLevel, msg, fields...)
+}
+
+// InfoContext registra un mensaje informativo con los campos de correlación del contexto
+func InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	logWithContext(ctx, logrus.InfoLevel, msg, fields...)
+}
+
+// WarnContext registra una advertencia con los campos de correlación del contexto
+func WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	logWithContext(ctx, logrus.WarnLevel, msg, fields...)
+}
+
+// ErrorContext registra un error con los campos de correlación del contexto
+func ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	logWithContext(ctx, logrus.ErrorLevel, msg, fields...)
+}
+
+// logWithContext asocia el contexto a la entrada, ContextHook agrega los campos de correlación antes de escribirla
+func logWithContext(ctx context.Context, level logrus.Level, msg string, fields ...map[string]interface{}) {
+	entry := logrus.NewEntry(Logger)
+	if ctx != nil {
+		entry = entry.WithContext(ctx)
+	}
+	if len(fields) > 0 && fields[0] != nil {
+		entry = entry.WithFields(fields[0])
+	}
+
+	entry.Log(level, msg)
+}
+
+// ContextHook agrega a cada entrada asociada a un contexto (logs.InfoContext o Logger.WithContext) el ID de la
+// solicitud (request_id) almacenado por el middleware de contexto y los identificadores de la traza activa (trace_id y
+// span_id). Los campos enviados por el llamador tienen prioridad sobre los del contexto
+type ContextHook struct{}
+
+func (h *ContextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *ContextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	for key, value := range contextFields(entry.Context) {
+		if _, exists := entry.Data[key]; !exists {
+			entry.Data[key] = value
+		}
+	}
+
+	return nil
+}
+
+// contextFields obtiene los campos de correlación del contexto: el ID de la solicitud y los identificadores de la traza
+func contextFields(ctx context.Context) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if ctx == nil {
+		return fields
+	}
+
+	if requestID, ok := ctx.Value("request_id").(string); ok && requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields["trace_id"] = spanContext.TraceID().String()
+		fields["span_id"] = spanContext.SpanID().String()
+	}
+
+	return fields
+}
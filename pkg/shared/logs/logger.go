@@ -9,6 +9,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+)
+
+const (
+	// FormatText formato de texto con colores, pensado para lectura en consola durante el desarrollo
+	FormatText = "text"
+	// FormatJSON formato JSON de una línea por entrada, pensado para agregadores de logs
+	FormatJSON = "json"
 )
 
 var Logger *logrus.Logger
@@ -55,19 +63,22 @@ func (f *CustomFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return []byte(fmt.Sprintf("%s%s\n", baseMessage, fields)), nil
 }
 
-func InitLogger(logLevel, logPath string) error {
+// InitLogger inicializa el logger global con el nivel y formato indicados (FormatText o FormatJSON). Todas las entradas
+// pasan por ContextHook, que agrega los campos de correlación de la solicitud, y por RedactionHook antes de escribirse,
+// por lo que los campos sensibles nunca llegan a la consola ni al archivo.
+// Si logPath está vacío las entradas solo se escriben en la consola
+func InitLogger(logLevel, logPath, logFormat string) error {
 	Logger = logrus.New()
 
-	formatter := &CustomFormatter{
-		TextFormatter: logrus.TextFormatter{
-			DisableColors: false,
-			FullTimestamp: true,
-		},
-	}
-
-	Logger.SetFormatter(formatter)
+	Logger.SetFormatter(determineFormatter(logFormat))
 	Logger.SetLevel(determineLogLevel(logLevel))
+	Logger.AddHook(&ContextHook{})
+	Logger.AddHook(&RedactionHook{})
 	Logger.SetOutput(os.Stdout)
+	if logPath == "" {
+		return nil
+	}
+
 	logDir := utils.FindProjectRoot()
 
 	// Asegurarse de que el directorio de logs exista
@@ -127,6 +138,21 @@ func determineLogLevel(logLevel string) logrus.Level {
 	return logrus.InfoLevel
 }
 
+func determineFormatter(logFormat string) logrus.Formatter {
+	if logFormat == FormatJSON {
+		return &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		}
+	}
+
+	return &CustomFormatter{
+		TextFormatter: logrus.TextFormatter{
+			DisableColors: false,
+			FullTimestamp: true,
+		},
+	}
+}
+
 type WriteHook struct {
 	Writer    io.Writer
 	Formatter logrus.Formatter
@@ -0,0 +1,144 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue valor con el que se reemplazan los datos sensibles en los logs
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeyFragments fragmentos que identifican una llave con secretos, se comparan contra la llave normalizada
+// (minúsculas, sin guiones ni guiones bajos) por lo que "passwordPri", "api_secret" o "haciendaCreds" coinciden
+var sensitiveKeyFragments = []string{
+	"password", "passwd", "pwd", "secret", "token", "authorization", "credential", "creds",
+	"privatekey", "masterkey",
+}
+
+// personalDataKeys llaves exactas (normalizadas) que contienen datos personales de emisores y receptores
+var personalDataKeys = map[string]bool{
+	"email":          true,
+	"correo":         true,
+	"telefono":       true,
+	"phone":          true,
+	"numdocumento":   true,
+	"documentnumber": true,
+	"dui":            true,
+	"direccion":      true,
+	"address":        true,
+	"complemento":    true,
+}
+
+var (
+	// jwtPattern identifica tokens JWT incluidos en mensajes o valores de texto
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// bearerPattern identifica credenciales con esquema Bearer incluidas en un texto
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9._~+/=-]+`)
+)
+
+// RedactionHook enmascara los datos sensibles de cada entrada antes de que se formatee y escriba. Se reemplazan los
+// valores de las llaves con secretos o datos personales, incluso dentro de estructuras, mapas y textos JSON, y los
+// tokens JWT o Bearer que aparezcan en el mensaje o en cualquier valor de texto
+type RedactionHook struct{}
+
+func (h *RedactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactionHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redactString(entry.Message)
+	for key, value := range entry.Data {
+		entry.Data[key] = redactField(key, value)
+	}
+	return nil
+}
+
+// redactField enmascara el valor completo si la llave es sensible, en caso contrario se inspecciona el valor
+func redactField(key string, value interface{}) interface{} {
+	if isSensitiveKey(key) {
+		return RedactedValue
+	}
+	return redactValue(value)
+}
+
+// isSensitiveKey indica si la llave contiene secretos o datos personales
+func isSensitiveKey(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(key))
+	if personalDataKeys[normalized] {
+		return true
+	}
+
+	for _, fragment := range sensitiveKeyFragments {
+		if strings.Contains(normalized, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue recorre el valor enmascarando los campos sensibles que contenga. Los tipos compuestos (estructuras,
+// punteros, mapas y slices) se convierten a su representación JSON para respetar las etiquetas json de sus campos
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time,
+		time.Duration:
+		return v
+	case string:
+		return redactJSONString(v)
+	case []byte:
+		return redactJSONString(string(v))
+	case error:
+		return redactString(v.Error())
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			redacted[key] = redactField(key, item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item)
+		}
+		return redacted
+	case fmt.Stringer:
+		return redactString(v.String())
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return RedactedValue
+	}
+
+	var decoded interface{}
+	if err = json.Unmarshal(raw, &decoded); err != nil {
+		return RedactedValue
+	}
+	return redactValue(decoded)
+}
+
+// redactJSONString enmascara un texto, si el texto es un documento JSON (por ejemplo un DTE serializado) se enmascaran
+// sus campos sensibles conservando el resto del documento
+func redactJSONString(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(trimmed), &decoded); err == nil {
+			if raw, err := json.Marshal(redactValue(decoded)); err == nil {
+				return string(raw)
+			}
+		}
+	}
+	return redactString(value)
+}
+
+// redactString enmascara los tokens JWT y las credenciales Bearer incluidas en un texto
+func redactString(value string) string {
+	value = bearerPattern.ReplaceAllString(value, "$1 "+RedactedValue)
+	return jwtPattern.ReplaceAllString(value, RedactedValue)
+}
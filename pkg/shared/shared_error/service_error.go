@@ -14,6 +14,7 @@ type ServiceError struct {
 	Operation string
 	Message   string
 	Code      string
+	Args      []interface{} // Parámetros del mensaje, permiten traducirlo al idioma de la solicitud
 	Err       error
 }
 
@@ -36,12 +37,26 @@ func (e *ServiceError) Error() string {
 	return fmt.Sprintf("%s -> %s", e.Message, e.Err)
 }
 
+// Localize retorna el mensaje del error en el idioma indicado, los errores sin código conservan su mensaje original
+func (e *ServiceError) Localize(lang string) string {
+	if e.Code == "" {
+		return e.Message
+	}
+
+	return i18n.TranslateServiceArgsIn(lang, e.Code, e.Args...)
+}
+
 func (e *ServiceError) GetErrError() []string {
+	return e.GetErrErrorIn(i18n.DefaultLanguage())
+}
+
+// GetErrErrorIn retorna los detalles del error en el idioma indicado
+func (e *ServiceError) GetErrErrorIn(lang string) []string {
 	if e.Err == nil {
-		return []string{i18n.Translate("service_errors.NoDetailsAvailable")}
+		return []string{i18n.TranslateIn(lang, "service_errors.NoDetailsAvailable")}
 	}
 
-	rawDetails := strings.Split(e.Err.Error(), ";")
+	rawDetails := strings.Split(i18n.LocalizeError(e.Err, lang), ";")
 	var details []string
 	for _, detail := range rawDetails {
 		trimmed := strings.TrimSpace(detail)
@@ -63,22 +78,24 @@ func NewGeneralServiceError(serviceType, op, msg string, err error) *ServiceErro
 }
 
 func NewFormattedGeneralServiceError(serviceType, op, code string, args ...interface{}) *ServiceError {
-	message := i18n.Translate(fmt.Sprintf("service_errors.%s", strings.ToLower(code)), args)
+	message := i18n.TranslateServiceArgs(code, args...)
 	return &ServiceError{
 		Type:      serviceType,
 		Operation: op,
 		Message:   message,
 		Code:      code,
+		Args:      args,
 	}
 }
 
 func NewFormattedGeneralServiceWithError(serviceType, op string, err error, code string, args ...interface{}) *ServiceError {
-	message := i18n.Translate(fmt.Sprintf("service_errors.%s", strings.ToLower(code)), args)
+	message := i18n.TranslateServiceArgs(code, args...)
 	return &ServiceError{
 		Type:      serviceType,
 		Operation: op,
 		Message:   message,
 		Code:      code,
+		Args:      args,
 		Err:       err,
 	}
 }
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook crea un span por cada comando o pipeline ejecutado en Redis, se registra con client.AddHook. Solo se crean
+// spans para los comandos ejecutados dentro de una traza, los comandos sin traza (por ejemplo los de la caché de tokens
+// con su contexto propio) no generan trazas nuevas
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+
+	ctx, _ = Start(ctx, "redis."+cmd.Name(),
+		attribute.String("db.system", "redis"),
+		attribute.String("db.operation", cmd.Name()),
+	)
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd.Err())
+	return nil
+}
+
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+
+	ctx, _ = Start(ctx, "redis.pipeline",
+		attribute.String("db.system", "redis"),
+		attribute.Int("db.redis.commands", len(cmds)),
+	)
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	endRedisSpan(ctx, err)
+	return nil
+}
+
+// endRedisSpan finaliza el span del comando, una llave inexistente (redis.Nil) no se considera un error
+func endRedisSpan(ctx context.Context, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	End(trace.SpanFromContext(ctx), err)
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName nombre del tracer con el que se crean los spans de la aplicación
+const tracerName = "github.com/MarlonG1/api-facturacion-sv"
+
+// InitTracer configura el proveedor global de trazas con un exportador OTLP/HTTP hacia endpoint (por ejemplo
+// http://localhost:4318). Si endpoint está vacío el tracing queda deshabilitado: los spans no se registran pero el
+// contexto de traza recibido en las solicitudes se sigue propagando. Retorna la función que envía los spans pendientes
+// al apagar la aplicación
+func InitTracer(ctx context.Context, endpoint, serviceName string, sampleRatio float64) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// 1. Crear el exportador OTLP, el esquema del endpoint define si la conexión utiliza TLS
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Identificar el servicio en las trazas exportadas
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Registrar el proveedor, las trazas iniciadas por otro servicio respetan su decisión de muestreo
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start inicia un span hijo del span del contexto, el span debe finalizarse con End
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartRoot inicia un span sin padre, se utiliza en los jobs en segundo plano donde cada ejecución es una traza nueva
+func StartRoot(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithNewRoot(), trace.WithAttributes(attrs...))
+}
+
+// End finaliza un span registrando el error si la operación falló
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach retorna un contexto sin cancelación ni valores que conserva el span de ctx, se utiliza en las goroutines que
+// continúan después de finalizar la solicitud que las origina
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// NewTransport envuelve un http.RoundTripper para crear un span por cada solicitud saliente y propagar el contexto de
+// traza en sus encabezados. Si base es nil se utiliza http.DefaultTransport
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -9,6 +9,8 @@ type AuxiliarIdentificationExtractor struct {
 		DTEType        string `json:"tipoDte"`
 		ControlNumber  string `json:"numeroControl"`
 		GenerationCode string `json:"codigoGeneracion"`
+		Ambient        string `json:"ambiente"`
+		EmissionDate   string `json:"fecEmi"`
 	} `json:"identificacion"`
 	Issuer struct {
 		NIT string `json:"nit"`
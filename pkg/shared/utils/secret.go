@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// apiSecretHashPrefix identifica los API secrets almacenados como hash
+const apiSecretHashPrefix = "sha256$"
+
+// HashAPISecret calcula el hash con el que se almacena un API secret. Los API secrets son valores aleatorios de alta
+// entropía, por lo que un hash SHA-256 es suficiente y no requiere un algoritmo lento como bcrypt.
+func HashAPISecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return apiSecretHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// IsHashedAPISecret indica si un API secret almacenado ya se encuentra como hash
+func IsHashedAPISecret(value string) bool {
+	return strings.HasPrefix(value, apiSecretHashPrefix)
+}
+
+// SignRequest calcula la firma HMAC-SHA256 en hexadecimal del contenido de una solicitud con el secret como llave. El
+// hash almacenado del secret no sirve como llave, de modo que quien lea la base de datos no puede firmar solicitudes
+func SignRequest(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
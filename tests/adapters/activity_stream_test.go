@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/activity"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/activity/models"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/circuit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryActivityManager entrega en memoria los eventos publicados después del último ID indicado
+type memoryActivityManager struct {
+	events      []models.Event
+	branchID    uint
+	lastEventID string
+}
+
+func (m *memoryActivityManager) Publish(_ context.Context, event *models.Event) {
+	m.events = append(m.events, *event)
+}
+
+func (m *memoryActivityManager) Subscribe(_ context.Context, branchID uint, lastEventID string) (<-chan models.Event, error) {
+	m.branchID = branchID
+	m.lastEventID = lastEventID
+
+	events := make(chan models.Event, len(m.events))
+	for _, event := range m.events {
+		events <- event
+	}
+	close(events)
+
+	return events, nil
+}
+
+func TestStreamID(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name     string
+		id       string
+		wantErr  bool
+		wantNext string
+	}{
+		{name: "Valid ID", id: "1700000000000-0", wantNext: "1700000000000-1"},
+		{name: "Max sequence", id: "1700000000000-18446744073709551615", wantNext: "1700000000001-0"},
+		{name: "Without sequence", id: "1700000000000", wantErr: true},
+		{name: "Not a number", id: "abc-0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := models.ParseStreamID(tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.id, id.String())
+			assert.Equal(t, tt.wantNext, id.Next().String())
+			assert.True(t, id.Next().After(id))
+			assert.False(t, id.After(id))
+		})
+	}
+}
+
+func TestCircuitBreakerStateChanges(t *testing.T) {
+	test.TestMain(t)
+
+	type change struct{ from, to constants.State }
+	var changes []change
+
+	cb := circuit.NewCircuitBreaker(2, 10*time.Millisecond)
+	cb.OnStateChange(func(from, to constants.State) {
+		changes = append(changes, change{from, to})
+	})
+
+	cb.RecordFailure()
+	assert.Empty(t, changes, "a failure below the threshold keeps the circuit closed")
+
+	cb.RecordFailure()
+	assert.False(t, cb.AllowRequest())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, cb.AllowRequest())
+	cb.RecordSuccess()
+	cb.RecordSuccess()
+
+	assert.Equal(t, []change{
+		{constants.StateClosed, constants.StateOpen},
+		{constants.StateOpen, constants.StateHalfOpen},
+		{constants.StateHalfOpen, constants.StateClosed},
+	}, changes)
+
+	event := models.NewCircuitBreakerEvent(constants.StateClosed, constants.StateOpen)
+	assert.True(t, event.IsSystem())
+	assert.Equal(t, "open", event.Data["state"])
+}
+
+func TestActivityStreamHandler(t *testing.T) {
+	test.TestMain(t)
+
+	manager := &memoryActivityManager{events: []models.Event{
+		{ID: "1700000000000-0", Type: "dte.issued", BranchID: 7, Data: map[string]interface{}{"generation_code": "ABC"}},
+		{ID: "1700000000001-0", Type: models.EventCircuitBreakerStateChanged, Data: map[string]interface{}{"state": "open"}},
+	}}
+	handler := handlers.NewActivityHandler(activity.NewActivityUseCase(manager))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "1699999999999-0")
+	req = req.WithContext(context.WithValue(req.Context(), "claims", &authModels.AuthClaims{BranchID: 7}))
+	rec := httptest.NewRecorder()
+
+	handler.Stream(rec, req)
+
+	body := rec.Body.String()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, uint(7), manager.branchID)
+	assert.Equal(t, "1699999999999-0", manager.lastEventID)
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+	assert.Contains(t, body, "id: 1700000000000-0\nevent: dte.issued\ndata: {")
+	assert.Contains(t, body, "id: 1700000000001-0\nevent: circuit_breaker.state_changed\ndata: {")
+	assert.Less(t, strings.Index(body, "dte.issued"), strings.Index(body, "circuit_breaker.state_changed"))
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestArchiveJobValidate(t *testing.T) {
+	test.TestMain(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		job     *models.ArchiveJob
+		wantErr bool
+	}{
+		{name: "Valid branch archive", job: &models.ArchiveJob{Scope: models.ArchiveScopeBranch, StartDate: start, EndDate: start.AddDate(0, 1, 0)}},
+		{name: "Valid NIT archive", job: &models.ArchiveJob{Scope: models.ArchiveScopeNIT, StartDate: start, EndDate: start.AddDate(1, 0, 0)}},
+		{name: "Invalid scope", job: &models.ArchiveJob{Scope: "company", StartDate: start, EndDate: start}, wantErr: true},
+		{name: "End date before start date", job: &models.ArchiveJob{Scope: models.ArchiveScopeBranch, StartDate: start, EndDate: start.AddDate(0, 0, -1)}, wantErr: true},
+		{name: "Period too long", job: &models.ArchiveJob{Scope: models.ArchiveScopeBranch, StartDate: start, EndDate: start.AddDate(2, 0, 0)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.job.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestArchiveManifestTotals(t *testing.T) {
+	test.TestMain(t)
+
+	job := &models.ArchiveJob{ID: "JOB", BranchID: 1, Scope: models.ArchiveScopeBranch}
+	manifest := models.NewArchiveManifest(job, time.Now())
+
+	manifest.AddDocument(models.ManifestDocument{DTEType: "01", Status: "RECEIVED", Files: make([]models.ManifestFile, 3)})
+	manifest.AddDocument(models.ManifestDocument{DTEType: "01", Status: "INVALIDATED", Files: make([]models.ManifestFile, 1)})
+	manifest.AddDocument(models.ManifestDocument{DTEType: "03", Status: "RECEIVED", Files: make([]models.ManifestFile, 2)})
+
+	assert.Equal(t, uint(1), manifest.BranchID)
+	assert.Equal(t, 3, manifest.Totals.Documents)
+	assert.Equal(t, 6, manifest.Totals.Files)
+	assert.Equal(t, map[string]int{"01": 2, "03": 1}, manifest.Totals.ByType)
+	assert.Equal(t, map[string]int{"RECEIVED": 2, "INVALIDATED": 1}, manifest.Totals.ByStatus)
+}
@@ -0,0 +1,211 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+	dteModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/archive"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryArchiveRepository almacena los trabajos de exportación en memoria, los trabajos se actualizan desde la
+// goroutine que genera el archivo. leases contiene las reservas vigentes de los trabajos en proceso
+type memoryArchiveRepository struct {
+	mu     sync.Mutex
+	jobs   map[string]models.ArchiveJob
+	leases map[string]time.Time
+}
+
+func (m *memoryArchiveRepository) ClaimJob(_ context.Context, id string, now, leaseUntil time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || (job.Status != models.ArchivePending && job.Status != models.ArchiveProcessing) {
+		return false, nil
+	}
+	if lease, held := m.leases[id]; job.Status == models.ArchiveProcessing && held && lease.After(now) {
+		return false, nil
+	}
+	if m.leases == nil {
+		m.leases = make(map[string]time.Time)
+	}
+	job.Status = models.ArchiveProcessing
+	m.jobs[id] = job
+	m.leases[id] = leaseUntil
+	return true, nil
+}
+
+func (m *memoryArchiveRepository) Create(_ context.Context, job *models.ArchiveJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *memoryArchiveRepository) Update(_ context.Context, job *models.ArchiveJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *memoryArchiveRepository) GetByID(_ context.Context, _ uint, id string) (*models.ArchiveJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, shared_error.NewFormattedGeneralServiceError("ArchiveRepository", "GetByID", "ArchiveNotFound", id)
+	}
+	return &job, nil
+}
+
+func (m *memoryArchiveRepository) GetByStatus(_ context.Context, statuses ...string) ([]models.ArchiveJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []models.ArchiveJob
+	for _, job := range m.jobs {
+		for _, status := range statuses {
+			if job.Status == status {
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	return jobs, nil
+}
+
+func (m *memoryArchiveRepository) GetCompletedBefore(_ context.Context, before time.Time, limit int) ([]models.ArchiveJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []models.ArchiveJob
+	for _, job := range m.jobs {
+		if job.Status == models.ArchiveCompleted && job.CompletedAt.Before(before) && len(jobs) < limit {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *memoryArchiveRepository) status(id string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jobs[id].Status
+}
+
+// emptyExportManager exporta un período sin documentos, el archivo solo contiene el manifiesto
+type emptyExportManager struct {
+	dte_documents.DTEManager
+}
+
+func (m *emptyExportManager) ExportDTEs(context.Context, *dteModels.DTEFilters, func(*dteModels.DTEExportRecord) error) error {
+	return nil
+}
+
+func TestArchiveRecoverJobs(t *testing.T) {
+	test.TestMain(t)
+
+	now := utils.TimeNow()
+	repo := &memoryArchiveRepository{jobs: map[string]models.ArchiveJob{
+		"PENDING":    {ID: "PENDING", Scope: models.ArchiveScopeBranch, Status: models.ArchivePending, CreatedAt: now.Add(-time.Minute)},
+		"PROCESSING": {ID: "PROCESSING", Scope: models.ArchiveScopeBranch, Status: models.ArchiveProcessing, CreatedAt: now.Add(-10 * time.Minute)},
+		"STALE":      {ID: "STALE", Scope: models.ArchiveScopeBranch, Status: models.ArchiveProcessing, CreatedAt: now.Add(-3 * time.Hour)},
+		"DONE":       {ID: "DONE", Scope: models.ArchiveScopeBranch, Status: models.ArchiveCompleted, CreatedAt: now.Add(-time.Hour)},
+	}}
+	service := archive.NewArchiveService(&emptyExportManager{}, nil, repo, t.TempDir(), 24*time.Hour)
+
+	require.NoError(t, service.RecoverJobs(context.Background()))
+
+	// 1. Los trabajos interrumpidos recientes se vuelven a procesar
+	for _, id := range []string{"PENDING", "PROCESSING"} {
+		assert.Eventually(t, func() bool { return repo.status(id) == models.ArchiveCompleted }, 5*time.Second, 10*time.Millisecond, id)
+		job, err := repo.GetByID(context.Background(), 0, id)
+		require.NoError(t, err)
+		assert.FileExists(t, job.FilePath)
+	}
+
+	// 2. Los trabajos que superaron el tiempo máximo se marcan como fallidos y los demás no cambian
+	stale, err := repo.GetByID(context.Background(), 0, "STALE")
+	require.NoError(t, err)
+	assert.Equal(t, models.ArchiveFailed, stale.Status)
+	assert.NotNil(t, stale.ErrorMessage)
+	assert.NotNil(t, stale.CompletedAt)
+	assert.Equal(t, models.ArchiveCompleted, repo.status("DONE"))
+}
+
+func TestArchiveRecoverJobsSkipsClaimedJobs(t *testing.T) {
+	test.TestMain(t)
+
+	now := utils.TimeNow()
+	repo := &memoryArchiveRepository{
+		jobs: map[string]models.ArchiveJob{
+			"CLAIMED": {ID: "CLAIMED", Scope: models.ArchiveScopeBranch, Status: models.ArchiveProcessing, CreatedAt: now.Add(-10 * time.Minute)},
+			"EXPIRED": {ID: "EXPIRED", Scope: models.ArchiveScopeBranch, Status: models.ArchiveProcessing, CreatedAt: now.Add(-10 * time.Minute)},
+			"STALE":   {ID: "STALE", Scope: models.ArchiveScopeBranch, Status: models.ArchiveProcessing, CreatedAt: now.Add(-3 * time.Hour)},
+		},
+		leases: map[string]time.Time{
+			"CLAIMED": now.Add(time.Hour),
+			"EXPIRED": now.Add(-time.Minute),
+			"STALE":   now.Add(time.Hour),
+		},
+	}
+	service := archive.NewArchiveService(&emptyExportManager{}, nil, repo, t.TempDir(), 24*time.Hour)
+
+	require.NoError(t, service.RecoverJobs(context.Background()))
+
+	// 1. El trabajo con la reserva vencida se retoma
+	assert.Eventually(t, func() bool { return repo.status("EXPIRED") == models.ArchiveCompleted }, 5*time.Second, 10*time.Millisecond)
+
+	// 2. Los trabajos que otra instancia tiene reservados no se procesan ni se marcan como fallidos
+	assert.Never(t, func() bool { return repo.status("CLAIMED") != models.ArchiveProcessing }, 200*time.Millisecond, 10*time.Millisecond)
+	assert.Equal(t, models.ArchiveProcessing, repo.status("STALE"))
+}
+
+func TestArchiveCleanupExpired(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	storage := t.TempDir()
+	now := utils.TimeNow()
+
+	completedJob := func(id string, completedAt time.Time) models.ArchiveJob {
+		path := filepath.Join(storage, id+".zip")
+		require.NoError(t, os.WriteFile(path, []byte("zip"), 0644))
+		return models.ArchiveJob{ID: id, Status: models.ArchiveCompleted, FilePath: path, CompletedAt: &completedAt}
+	}
+
+	missingAt := now.Add(-10 * 24 * time.Hour)
+	repo := &memoryArchiveRepository{jobs: map[string]models.ArchiveJob{
+		"EXPIRED": completedJob("EXPIRED", now.Add(-8*24*time.Hour)),
+		"RECENT":  completedJob("RECENT", now.Add(-24*time.Hour)),
+		"MISSING": {ID: "MISSING", Status: models.ArchiveCompleted, FilePath: filepath.Join(storage, "MISSING.zip"), CompletedAt: &missingAt},
+	}}
+	service := archive.NewArchiveService(&emptyExportManager{}, nil, repo, storage, 7*24*time.Hour)
+
+	require.NoError(t, service.CleanupExpired(ctx))
+
+	// 1. El archivo expirado se elimina y su descarga indica que expiró
+	assert.NoFileExists(t, filepath.Join(storage, "EXPIRED.zip"))
+	assert.Equal(t, models.ArchiveExpired, repo.status("EXPIRED"))
+	_, _, err := service.OpenArchive(ctx, 0, "EXPIRED")
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("ArchiveService", "OpenArchive", "ArchiveExpired", "EXPIRED").Error())
+
+	// 2. Un archivo que ya no existe no impide expirar el trabajo
+	assert.Equal(t, models.ArchiveExpired, repo.status("MISSING"))
+
+	// 3. Los archivos dentro del tiempo de retención se conservan
+	assert.FileExists(t, filepath.Join(storage, "RECENT.zip"))
+	file, _, err := service.OpenArchive(ctx, 0, "RECENT")
+	require.NoError(t, err)
+	assert.NoError(t, file.Close())
+}
@@ -0,0 +1,273 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	auditUseCase "github.com/MarlonG1/api-facturacion-sv/internal/application/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/audit/models"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryAuditRepository almacena los eventos de auditoría en memoria
+type memoryAuditRepository struct {
+	events []models.AuditEvent
+}
+
+func (m *memoryAuditRepository) Create(_ context.Context, event *models.AuditEvent) error {
+	event.ID = uint(len(m.events) + 1)
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *memoryAuditRepository) GetPaged(_ context.Context, _ *models.AuditFilters) ([]models.AuditEvent, int64, error) {
+	return m.events, int64(len(m.events)), nil
+}
+
+func (m *memoryAuditRepository) Stream(_ context.Context, _ *models.AuditFilters, _ int, fn func(*models.AuditEvent) error) error {
+	for i := range m.events {
+		if err := fn(&m.events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAuditServiceRecord(t *testing.T) {
+	test.TestMain(t)
+
+	trustedProxies := config.Server.TrustedProxies
+	config.Server.TrustedProxies = "10.0.0.0/24"
+	t.Cleanup(func() { config.Server.TrustedProxies = trustedProxies })
+
+	// Contexto de una solicitud HTTP con su ID y la IP del cliente, recibida a través de un proxy de confianza
+	requestContext := func(headers map[string]string) context.Context {
+		var ctx context.Context
+		handler := middleware.NewRequestContextMiddleware().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx = r.Context()
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/dte/invoices", nil)
+		req.RemoteAddr = "10.0.0.8:51234"
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return ctx
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		entry         *models.AuditEntry
+		wantActorType string
+		wantActorID   uint
+		wantIP        string
+		wantRequestID string
+	}{
+		{
+			name:          "Primary branch key",
+			ctx:           context.WithValue(requestContext(map[string]string{"X-Request-ID": "req-1"}), "claims", &authModels.AuthClaims{ClientID: 7, BranchID: 3}),
+			entry:         &models.AuditEntry{EventType: models.EventDTEIssued, UserID: 7, BranchID: 3, Payload: map[string]interface{}{"generation_code": "ABC"}},
+			wantActorType: models.ActorUser,
+			wantActorID:   7,
+			wantIP:        "10.0.0.8",
+			wantRequestID: "req-1",
+		},
+		{
+			name:          "Additional branch key",
+			ctx:           context.WithValue(requestContext(map[string]string{"X-Request-ID": "req-2", "X-Forwarded-For": "203.0.113.9, 10.0.0.1"}), "claims", &authModels.AuthClaims{ClientID: 7, BranchID: 3, KeyID: 12}),
+			entry:         &models.AuditEntry{EventType: models.EventDTEInvalidated, UserID: 7, BranchID: 3},
+			wantActorType: models.ActorAPIKey,
+			wantActorID:   12,
+			wantIP:        "203.0.113.9",
+			wantRequestID: "req-2",
+		},
+		{
+			name:          "Operator acting as a branch",
+			ctx:           context.WithValue(requestContext(map[string]string{"X-Request-ID": "req-7"}), "claims", &authModels.AuthClaims{ClientID: 7, BranchID: 3, OperatorID: 5}),
+			entry:         &models.AuditEntry{EventType: models.EventDTEIssued, UserID: 7, BranchID: 3},
+			wantActorType: models.ActorOperator,
+			wantActorID:   5,
+			wantIP:        "10.0.0.8",
+			wantRequestID: "req-7",
+		},
+		{
+			name:          "Admin key",
+			ctx:           context.WithValue(requestContext(map[string]string{"X-Request-ID": "req-3"}), "admin_key", true),
+			entry:         &models.AuditEntry{EventType: models.EventRegistrationApproved, UserID: 7},
+			wantActorType: models.ActorAdmin,
+			wantIP:        "10.0.0.8",
+			wantRequestID: "req-3",
+		},
+		{
+			name:          "Public route",
+			ctx:           requestContext(map[string]string{"X-Request-ID": "req-4"}),
+			entry:         &models.AuditEntry{EventType: models.EventUserRegistered},
+			wantActorType: models.ActorAnonymous,
+			wantIP:        "10.0.0.8",
+			wantRequestID: "req-4",
+		},
+		{
+			name:          "Background job",
+			ctx:           context.Background(),
+			entry:         &models.AuditEntry{EventType: models.EventBatchRetransmitted, UserID: 7, BranchID: 3},
+			wantActorType: models.ActorSystem,
+		},
+		{
+			name:          "Explicit actor on login",
+			ctx:           requestContext(map[string]string{"X-Request-ID": "req-6"}),
+			entry:         &models.AuditEntry{EventType: models.EventAuthLogin, UserID: 7, BranchID: 3, ActorType: models.ActorAPIKey, ActorID: 12},
+			wantActorType: models.ActorAPIKey,
+			wantActorID:   12,
+			wantIP:        "10.0.0.8",
+			wantRequestID: "req-6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryAuditRepository{}
+			audit.NewAuditService(repo).Record(tt.ctx, tt.entry)
+
+			require.Len(t, repo.events, 1)
+			event := repo.events[0]
+			assert.Equal(t, tt.entry.EventType, event.EventType)
+			assert.Equal(t, tt.wantActorType, event.ActorType)
+			assert.Equal(t, tt.wantActorID, event.ActorID)
+			assert.Equal(t, tt.wantIP, event.IPAddress)
+			assert.Equal(t, tt.wantRequestID, event.RequestID)
+			assert.True(t, event.VerifyDigest())
+			assert.True(t, json.Valid(event.Payload))
+		})
+	}
+}
+
+func TestAuditPayloadDigest(t *testing.T) {
+	test.TestMain(t)
+
+	payload := []byte(`{"generation_code":"ABC","amount":10.50,"documents":["A","B"]}`)
+	digest := models.PayloadDigest(payload)
+
+	// Los motores de base de datos reordenan las llaves y agregan espacios en las columnas JSON
+	stored := &models.AuditEvent{
+		Payload:       json.RawMessage(`{"amount": 10.50, "documents": ["A", "B"], "generation_code": "ABC"}`),
+		PayloadDigest: digest,
+	}
+	assert.True(t, stored.VerifyDigest())
+
+	tampered := &models.AuditEvent{
+		Payload:       json.RawMessage(`{"amount": 99.50, "documents": ["A", "B"], "generation_code": "ABC"}`),
+		PayloadDigest: digest,
+	}
+	assert.False(t, tampered.VerifyDigest())
+}
+
+func TestParseAuditFilters(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    *models.AuditFilters
+		wantErr bool
+	}{
+		{
+			name:  "Defaults",
+			query: "",
+			want:  &models.AuditFilters{Page: 1, PageSize: 20},
+		},
+		{
+			name:  "Every filter",
+			query: "?user_id=7&branch_id=3&event_type=dte_issued&actor_type=api_key&request_id=req-1&page=2&page_size=500",
+			want: &models.AuditFilters{
+				UserID: 7, BranchID: 3, EventType: models.EventDTEIssued, ActorType: models.ActorAPIKey,
+				RequestID: "req-1", Page: 2, PageSize: 100,
+			},
+		},
+		{name: "Invalid user", query: "?user_id=abc", wantErr: true},
+		{name: "Invalid actor type", query: "?actor_type=robot", wantErr: true},
+		{name: "Invalid date", query: "?startDate=2025-01-01", wantErr: true},
+		{name: "Start after end", query: "?startDate=2025-02-01T00:00:00Z&endDate=2025-01-01T00:00:00Z", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit"+tt.query, nil)
+
+			filters, err := auditUseCase.ParseAuditFilters(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filters)
+		})
+	}
+}
+
+// pausingAuditManager exporta los eventos de auditoría en dos bloques
+type pausingAuditManager struct {
+	*chunkPauser
+}
+
+func (m *pausingAuditManager) Record(context.Context, *models.AuditEntry) {}
+
+func (m *pausingAuditManager) GetEvents(context.Context, *models.AuditFilters) (*models.AuditListResponse, error) {
+	return &models.AuditListResponse{}, nil
+}
+
+func (m *pausingAuditManager) ExportEvents(ctx context.Context, _ *models.AuditFilters, fn func(*models.AuditEvent) error) error {
+	return m.emit(ctx, func(i int) error {
+		return fn(&models.AuditEvent{
+			ID:         uint(i + 1),
+			EventType:  models.EventDTEIssued,
+			ActorType:  models.ActorSystem,
+			OccurredAt: time.Now(),
+		})
+	})
+}
+
+func TestAuditExportStreamsChunks(t *testing.T) {
+	test.TestMain(t)
+
+	adminKey := config.Server.AdminAPIKey
+	config.Server.AdminAPIKey = "test-admin-key"
+	t.Cleanup(func() { config.Server.AdminAPIKey = adminKey })
+
+	manager := &pausingAuditManager{chunkPauser: newChunkPauser()}
+	handler := handlers.NewAuditHandler(auditUseCase.NewAuditUseCase(manager))
+	server := newTestServer(t, func(router *mux.Router) {
+		admin := router.PathPrefix("/api/v1/admin").Subrouter()
+		admin.Use(middleware.NewAdminMiddleware(nil, nil).Handle)
+		middleware.ExportRoute(admin.HandleFunc("/audit/export", handler.Export).Methods(http.MethodGet), "audit")
+	})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/admin/audit/export?format=ndjson", nil)
+	require.NoError(t, err)
+	req.Header.Set(middleware.AdminKeyHeader, "test-admin-key")
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	// El primer bloque llega mientras la exportación sigue en curso, el resto después de superar el WriteTimeout
+	assert.Equal(t, manager.total, manager.readLines(t, resp.Body))
+}
@@ -0,0 +1,279 @@
+package adapters
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	authUseCase "github.com/MarlonG1/api-facturacion-sv/internal/application/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/service/strategies"
+	coreErrors "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/error"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	dteConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	vaultModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/tokens"
+	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// branchStoreRepository agrega a memoryBranchRepository la administración de sucursales, las sucursales se indexan por su API key
+type branchStoreRepository struct {
+	*memoryBranchRepository
+	nextID uint
+}
+
+func (r *branchStoreRepository) GetAuthTypeByApiKey(_ context.Context, apiKey string) (string, error) {
+	branch, ok := r.branches[apiKey]
+	if !ok || !branch.IsActive {
+		return "", errPackage.ErrUserNotFound
+	}
+	return r.owner.AuthType, nil
+}
+
+func (r *branchStoreRepository) GetBranchOffices(_ context.Context, userID uint) ([]user.BranchOffice, error) {
+	branches := make([]user.BranchOffice, 0, len(r.branches))
+	for id := uint(1); id < r.nextID; id++ {
+		if branch := r.findBranch(id); branch != nil && branch.UserID == userID {
+			branches = append(branches, *branch)
+		}
+	}
+	return branches, nil
+}
+
+func (r *branchStoreRepository) CreateBranchOffice(_ context.Context, userID uint, branch *user.BranchOffice) error {
+	branch.ID = r.nextID
+	branch.UserID = userID
+	r.nextID++
+	r.branches[branch.APIKey] = branch
+	return nil
+}
+
+func (r *branchStoreRepository) UpdateBranchOffices(_ context.Context, userID uint, branches []user.BranchOffice) error {
+	for i := range branches {
+		current := r.findBranch(branches[i].ID)
+		if current == nil || current.UserID != userID {
+			return errPackage.ErrBranchDoesNotBelong
+		}
+		current.EstablishmentType = branches[i].EstablishmentType
+		current.POSCode = branches[i].POSCode
+		current.RateLimitPerMinute = branches[i].RateLimitPerMinute
+		current.ClientCertSubject = branches[i].ClientCertSubject
+	}
+	return nil
+}
+
+func (r *branchStoreRepository) SetBranchOfficeStatus(_ context.Context, userID, branchID uint, isActive bool) error {
+	branch := r.findBranch(branchID)
+	if branch == nil || branch.UserID != userID {
+		return errPackage.ErrBranchDoesNotBelong
+	}
+	branch.IsActive = isActive
+	return nil
+}
+
+func (r *branchStoreRepository) UpdateBranchCredentials(_ context.Context, userID, branchID uint, apiKey, apiSecret, signingSecret string) error {
+	branch := r.findBranch(branchID)
+	if branch == nil || branch.UserID != userID {
+		return errPackage.ErrBranchDoesNotBelong
+	}
+	delete(r.branches, branch.APIKey)
+	branch.APIKey = apiKey
+	branch.APISecret = apiSecret
+	branch.APISigningSecret = &signingSecret
+	r.branches[apiKey] = branch
+	return nil
+}
+
+func (r *branchStoreRepository) GetBranchByBranchID(_ context.Context, branchID uint) (*user.BranchOffice, error) {
+	branch := r.findBranch(branchID)
+	if branch == nil {
+		return nil, errPackage.ErrBranchOfficeNotFound
+	}
+	result := *branch
+	result.User = r.owner
+	return &result, nil
+}
+
+func (r *branchStoreRepository) findBranch(id uint) *user.BranchOffice {
+	for _, branch := range r.branches {
+		if branch.ID == id {
+			return branch
+		}
+	}
+	return nil
+}
+
+// branchTestSetup contiene el caso de uso de sucursales con el servicio de autenticación real y el contexto del usuario autenticado
+type branchTestSetup struct {
+	useCase    *authUseCase.BranchUseCase
+	manager    auth.AuthManager
+	jwtService *tokens.JWTService
+	ctx        context.Context
+}
+
+func newBranchTestSetup(t *testing.T) *branchTestSetup {
+	cacheManager := newMemoryRedisCache(t)
+	jwtService := tokens.NewJWTService("branch-test-secret", cacheManager)
+	cryptService := crypt.NewCryptService()
+
+	keys, _, err := config.ParseVaultMasterKeys("v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	require.NoError(t, err)
+	credentialVault, err := crypt.NewCredentialVaultService(&memoryVaultRepository{records: make(map[uint]vaultModels.SealedCredentials)}, keys, "v1")
+	require.NoError(t, err)
+
+	// 1. El usuario 1 posee la casa matriz, el usuario 2 posee una sucursal
+	repo := &branchStoreRepository{
+		memoryBranchRepository: &memoryBranchRepository{
+			branches: map[string]*user.BranchOffice{
+				"matrix-key":  {ID: 1, UserID: 1, EstablishmentType: dteConstants.CasaMatriz, IsActive: true},
+				"foreign-key": {ID: 2, UserID: 2, EstablishmentType: dteConstants.Sucursal, IsActive: true},
+			},
+			owner: &user.User{ID: 1, NIT: "06142803901121", Status: true, AuthType: constants.StandardAuthType, TokenLifetime: 1},
+		},
+		nextID: 3,
+	}
+
+	manager := strategies.NewAuthService(jwtService, repo, cacheManager, cryptService, credentialVault, audit.NewAuditService(&memoryAuditRepository{}))
+	return &branchTestSetup{
+		useCase:    authUseCase.NewBranchUseCase(manager, cryptService, credentialVault),
+		manager:    manager,
+		jwtService: jwtService,
+		ctx:        context.WithValue(context.Background(), "claims", &authModels.AuthClaims{ClientID: 1, BranchID: 1}),
+	}
+}
+
+// login inicia sesión con las llaves de una sucursal y retorna su access token
+func (s *branchTestSetup) login(t *testing.T, credentials *user.BranchCredentialsResponse) string {
+	session, err := s.manager.Login(context.Background(), &authModels.AuthCredentials{
+		APIKey:        credentials.APIKey,
+		APISecret:     credentials.APISecret,
+		MHCredentials: &authModels.HaciendaCredentials{Username: "06142803901121", Password: "MH-password"},
+	})
+	require.NoError(t, err)
+	return session.Token
+}
+
+func TestBranchUseCaseRules(t *testing.T) {
+	test.TestMain(t)
+
+	setup := newBranchTestSetup(t)
+	branchNotFound := func(operation, id string) string {
+		return shared_error.NewFormattedGeneralServiceError("BranchUseCase", operation, "BranchNotFound", id).Error()
+	}
+
+	// 1. Solo puede existir una casa matriz por usuario
+	_, err := setup.useCase.CreateBranch(setup.ctx, &user.BranchOffice{EstablishmentType: dteConstants.CasaMatriz}, nil)
+	assert.EqualError(t, err, dte_errors.NewFormattedValidationError(coreErrors.ErrMoreThanOneBranchMatrix).Error())
+
+	// 2. La casa matriz no puede desactivarse ni cambiar de tipo
+	assert.EqualError(t, setup.useCase.DeactivateBranch(setup.ctx, "1"), dte_errors.NewFormattedValidationError(coreErrors.ErrBranchMatrixDeactivation).Error())
+	_, err = setup.useCase.UpdateBranch(setup.ctx, "1", &user.BranchOffice{EstablishmentType: dteConstants.Sucursal}, nil)
+	assert.EqualError(t, err, dte_errors.NewFormattedValidationError(coreErrors.ErrBranchMatrixTypeChange).Error())
+
+	// 3. Las sucursales desconocidas o de otro usuario no se encuentran
+	assert.EqualError(t, setup.useCase.DeactivateBranch(setup.ctx, "2"), branchNotFound("GetBranch", "2"))
+	_, err = setup.useCase.RotateBranchKeys(setup.ctx, "99")
+	assert.EqualError(t, err, branchNotFound("GetBranch", "99"))
+
+	// 4. Las llaves de una sucursal desactivada no pueden rotarse
+	created, err := setup.useCase.CreateBranch(setup.ctx, &user.BranchOffice{EstablishmentType: dteConstants.Sucursal}, nil)
+	require.NoError(t, err)
+	id := strconv.FormatUint(uint64(created.ID), 10)
+	require.NoError(t, setup.useCase.DeactivateBranch(setup.ctx, id))
+
+	_, err = setup.useCase.RotateBranchKeys(setup.ctx, id)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("BranchUseCase", "RotateBranchKeys", "BranchNotActive", id).Error())
+}
+
+func TestBranchUseCaseClientCert(t *testing.T) {
+	test.TestMain(t)
+
+	setup := newBranchTestSetup(t)
+	subject := "CN=sucursal-01,O=Empresa"
+	presented := []string{subject, "sucursal-01.empresa.com"}
+	notPresented := func(operation, subject string) string {
+		return shared_error.NewFormattedGeneralServiceError("BranchUseCase", operation, "ClientCertNotPresented", subject).Error()
+	}
+
+	// 1. Asignar un certificado requiere presentarlo en la solicitud, su subject o alguno de sus SAN
+	_, err := setup.useCase.CreateBranch(setup.ctx, &user.BranchOffice{EstablishmentType: dteConstants.Sucursal, ClientCertSubject: &subject}, nil)
+	assert.EqualError(t, err, notPresented("CreateBranch", subject))
+
+	created, err := setup.useCase.CreateBranch(setup.ctx, &user.BranchOffice{EstablishmentType: dteConstants.Sucursal, ClientCertSubject: &subject}, presented)
+	require.NoError(t, err)
+	id := strconv.FormatUint(uint64(created.ID), 10)
+
+	san := "sucursal-01.empresa.com"
+	_, err = setup.useCase.UpdateBranch(setup.ctx, id, &user.BranchOffice{ClientCertSubject: &san}, []string{"CN=otro"})
+	assert.EqualError(t, err, notPresented("UpdateBranch", san))
+
+	_, err = setup.useCase.CreateAPIKey(setup.ctx, id, &user.BranchAPIKey{Name: "contabilidad", Scopes: []string{constants.ScopeDTERead}, ClientCertSubject: &subject}, nil)
+	assert.EqualError(t, err, notPresented("CreateAPIKey", subject))
+
+	// 2. Conservar el certificado asignado no requiere presentarlo
+	rateLimit := 30
+	updated, err := setup.useCase.UpdateBranch(setup.ctx, id, &user.BranchOffice{ClientCertSubject: &subject, RateLimitPerMinute: &rateLimit}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, subject, *updated.ClientCertSubject)
+	assert.Equal(t, 30, *updated.RateLimitPerMinute)
+
+	// 3. Un subject vacío retira el certificado y una cuota de -1 restablece la cuota por defecto
+	empty, defaultQuota := "", -1
+	updated, err = setup.useCase.UpdateBranch(setup.ctx, id, &user.BranchOffice{ClientCertSubject: &empty, RateLimitPerMinute: &defaultQuota}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, updated.ClientCertSubject)
+	assert.Nil(t, updated.RateLimitPerMinute)
+
+	branches, err := setup.useCase.ListBranches(setup.ctx)
+	require.NoError(t, err)
+	for _, branch := range branches {
+		if branch.ID == created.ID {
+			assert.Nil(t, branch.ClientCertSubject)
+			assert.Nil(t, branch.RateLimitPerMinute)
+		}
+	}
+}
+
+func TestBranchUseCaseRevokesTokens(t *testing.T) {
+	test.TestMain(t)
+
+	setup := newBranchTestSetup(t)
+	created, err := setup.useCase.CreateBranch(setup.ctx, &user.BranchOffice{EstablishmentType: dteConstants.Sucursal}, nil)
+	require.NoError(t, err)
+	id := strconv.FormatUint(uint64(created.ID), 10)
+
+	// 1. Al rotar las llaves los tokens emitidos con las anteriores dejan de ser válidos
+	token := setup.login(t, created)
+	_, err = setup.jwtService.ValidateToken(context.Background(), token)
+	require.NoError(t, err)
+
+	rotated, err := setup.useCase.RotateBranchKeys(setup.ctx, id)
+	require.NoError(t, err)
+	assert.NotEqual(t, created.APIKey, rotated.APIKey)
+	assert.NotEqual(t, created.APISecret, rotated.APISecret)
+
+	_, err = setup.jwtService.ValidateToken(context.Background(), token)
+	assert.Error(t, err)
+	_, err = setup.manager.Login(context.Background(), &authModels.AuthCredentials{APIKey: created.APIKey, APISecret: created.APISecret})
+	assert.Error(t, err)
+
+	// 2. Al desactivar la sucursal sus tokens dejan de ser válidos y no puede iniciar sesión
+	token = setup.login(t, rotated)
+	require.NoError(t, setup.useCase.DeactivateBranch(setup.ctx, id))
+
+	_, err = setup.jwtService.ValidateToken(context.Background(), token)
+	assert.Error(t, err)
+	_, err = setup.manager.Login(context.Background(), &authModels.AuthCredentials{APIKey: rotated.APIKey, APISecret: rotated.APISecret})
+	assert.Error(t, err)
+}
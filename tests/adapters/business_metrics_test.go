@@ -0,0 +1,196 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metricsUseCase "github.com/MarlonG1/api-facturacion-sv/internal/application/metrics"
+	authConstants "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryBusinessMetricsRepository retorna agregados fijos como si los hubiera calculado la base de datos
+type memoryBusinessMetricsRepository struct {
+	groups  []models.DTEMetricGroup
+	pending []models.ContingencyAgeGroup
+	codes   []models.RejectionCode
+}
+
+func (m *memoryBusinessMetricsRepository) GetDTEGroups(_ context.Context, _ *models.BusinessMetricsFilters) ([]models.DTEMetricGroup, error) {
+	return m.groups, nil
+}
+
+func (m *memoryBusinessMetricsRepository) GetPendingContingency(_ context.Context, _ *models.BusinessMetricsFilters, _ time.Time) ([]models.ContingencyAgeGroup, error) {
+	return m.pending, nil
+}
+
+func (m *memoryBusinessMetricsRepository) GetTopRejectionCodes(_ context.Context, _ *models.BusinessMetricsFilters, limit int) ([]models.RejectionCode, error) {
+	if len(m.codes) > limit {
+		return m.codes[:limit], nil
+	}
+	return m.codes, nil
+}
+
+func TestBusinessMetricsService(t *testing.T) {
+	test.TestMain(t)
+
+	oldest := utils.TimeNow().Add(-100 * time.Hour)
+	repo := &memoryBusinessMetricsRepository{
+		groups: []models.DTEMetricGroup{
+			{BranchID: 1, DTEType: constants.FacturaElectronica, Status: constants.DocumentReceived, Transmission: constants.TransmissionNormal, Count: 6, Amount: 100.10},
+			{BranchID: 1, DTEType: constants.CCFElectronico, Status: constants.DocumentRejected, Transmission: constants.TransmissionContingency, Count: 2, Amount: 50},
+			{BranchID: 2, DTEType: constants.FacturaElectronica, Status: constants.DocumentReceived, Transmission: constants.TransmissionNormal, Count: 2, Amount: 0.20},
+		},
+		pending: []models.ContingencyAgeGroup{
+			{BranchID: 1, Pending: 5, LessThanHour: 1, LessThanDay: 3, LessThanThreeDays: 4, OldestPendingAt: &oldest},
+			{BranchID: 3, Pending: 1, LessThanHour: 1, LessThanDay: 1, LessThanThreeDays: 1},
+		},
+		codes: []models.RejectionCode{{Code: "004", Description: "RECHAZADO", Count: 2}},
+	}
+
+	service := metrics.NewBusinessMetricsService(repo)
+	result, err := service.GetBusinessMetrics(context.Background(), &models.BusinessMetricsFilters{ClientID: 7, Period: models.PeriodToday})
+	require.NoError(t, err)
+
+	// Totales del contribuyente, los grupos iguales de distintas sucursales se suman en el detalle
+	assert.Equal(t, int64(10), result.ProcessedDTEs.Total)
+	assert.Equal(t, 150.30, result.ProcessedDTEs.TotalAmount)
+	assert.Equal(t, int64(8), result.ProcessedDTEs.ByType[constants.FacturaElectronica])
+	assert.Equal(t, 100.30, result.ProcessedDTEs.AmountByType[constants.FacturaElectronica])
+	assert.Equal(t, int64(2), result.ProcessedDTEs.ByTransmission[constants.TransmissionContingency])
+	assert.Len(t, result.ProcessedDTEs.Breakdown, 2)
+
+	// Rechazos sobre el total procesado
+	assert.Equal(t, int64(2), result.Rejections.Total)
+	assert.InDelta(t, 0.2, result.Rejections.Rate, 0.0001)
+	assert.Equal(t, "004", result.Rejections.TopCodes[0].Code)
+
+	// Cola de contingencia por antigüedad
+	assert.Equal(t, int64(6), result.Contingency.PendingDTEs)
+	assert.Equal(t, map[string]int64{
+		metrics.AgeLessThanHour: 2,
+		metrics.AgeLessThanDay:  2,
+		metrics.AgeLessThan72h:  1,
+		metrics.AgeOver72h:      1,
+	}, result.Contingency.ByAge)
+	assert.Equal(t, &oldest, result.Contingency.OldestPendingAt)
+	assert.GreaterOrEqual(t, result.Contingency.OldestAgeSeconds, int64(100*3600))
+
+	// Detalle por sucursal, incluye las sucursales que solo tienen documentos pendientes
+	require.Len(t, result.Branches, 3)
+	assert.Equal(t, uint(1), result.Branches[0].BranchID)
+	assert.Equal(t, int64(8), result.Branches[0].ProcessedDTEs.Total)
+	assert.InDelta(t, 0.25, result.Branches[0].RejectionRate, 0.0001)
+	assert.Equal(t, int64(5), result.Branches[0].PendingContingency)
+	assert.Equal(t, int64(0), result.Branches[2].ProcessedDTEs.Total)
+	assert.Equal(t, int64(1), result.Branches[2].PendingContingency)
+}
+
+func TestParseBusinessMetricsFilters(t *testing.T) {
+	test.TestMain(t)
+
+	now := utils.TimeNow()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	branchClaims := &authModels.AuthClaims{ClientID: 7, BranchID: 3, Scopes: []string{authConstants.ScopeReports}}
+	fullClaims := &authModels.AuthClaims{ClientID: 7, BranchID: 3}
+
+	tests := []struct {
+		name         string
+		query        string
+		claims       *authModels.AuthClaims
+		wantErr      string
+		wantPeriod   string
+		wantStart    time.Time
+		wantEnd      time.Time
+		wantClientID uint
+		wantBranchID uint
+	}{
+		{
+			name:         "Default period is today for the authenticated branch",
+			claims:       branchClaims,
+			wantPeriod:   models.PeriodToday,
+			wantStart:    startOfDay,
+			wantBranchID: 3,
+		},
+		{
+			name:         "Current month",
+			query:        "period=month",
+			claims:       branchClaims,
+			wantPeriod:   models.PeriodMonth,
+			wantStart:    time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+			wantBranchID: 3,
+		},
+		{
+			name:         "Custom period for every branch",
+			query:        "period=custom&start_date=2026-01-01&end_date=2026-01-31&all=true",
+			claims:       fullClaims,
+			wantPeriod:   models.PeriodCustom,
+			wantStart:    time.Date(2026, 1, 1, 0, 0, 0, 0, now.Location()),
+			wantEnd:      time.Date(2026, 1, 31, 23, 59, 59, 0, now.Location()),
+			wantClientID: 7,
+		},
+		{
+			name:    "Every branch requires the all branches scope",
+			query:   "all=true",
+			claims:  branchClaims,
+			wantErr: "InsufficientScope",
+		},
+		{
+			name:    "Custom period requires dates",
+			query:   "period=custom&end_date=2026-01-31",
+			claims:  branchClaims,
+			wantErr: "InvalidQueryParam",
+		},
+		{
+			name:    "Custom period with end before start",
+			query:   "period=custom&start_date=2026-02-01&end_date=2026-01-31",
+			claims:  branchClaims,
+			wantErr: "InvalidMetricsPeriod",
+		},
+		{
+			name:    "Custom period too long",
+			query:   "period=custom&start_date=2024-01-01&end_date=2026-01-31",
+			claims:  branchClaims,
+			wantErr: "InvalidMetricsPeriod",
+		},
+		{
+			name:    "Unknown period",
+			query:   "period=week",
+			claims:  branchClaims,
+			wantErr: "InvalidQueryParam",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/metrics/business?"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), "claims", tt.claims))
+
+			filters, err := metricsUseCase.ParseBusinessMetricsFilters(req)
+			if tt.wantErr != "" {
+				test.AssertErrorCode(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPeriod, filters.Period)
+			assert.True(t, tt.wantStart.Equal(filters.StartDate), "start date %s", filters.StartDate)
+			if !tt.wantEnd.IsZero() {
+				assert.True(t, tt.wantEnd.Equal(filters.EndDate), "end date %s", filters.EndDate)
+			}
+			assert.Equal(t, tt.wantClientID, filters.ClientID)
+			assert.Equal(t, tt.wantBranchID, filters.BranchID)
+		})
+	}
+}
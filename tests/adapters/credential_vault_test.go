@@ -0,0 +1,268 @@
+package adapters
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryVaultRepository almacena en memoria las credenciales y los secrets cifrados del vault
+type memoryVaultRepository struct {
+	records map[uint]models.SealedCredentials
+	secrets map[models.SealedSecretSource]map[uint]string
+}
+
+func (r *memoryVaultRepository) Save(_ context.Context, sealed *models.SealedCredentials) error {
+	r.records[sealed.UserID] = *sealed
+	return nil
+}
+
+func (r *memoryVaultRepository) GetByUserID(_ context.Context, userID uint) (*models.SealedCredentials, error) {
+	sealed, ok := r.records[userID]
+	if !ok {
+		return nil, shared_error.NewFormattedGeneralServiceError("CredentialVaultRepository", "GetByUserID", "HaciendaCredentialsNotFound")
+	}
+	return &sealed, nil
+}
+
+func (r *memoryVaultRepository) GetByOutdatedKey(_ context.Context, activeVersion string, limit int) ([]models.SealedCredentials, error) {
+	result := make([]models.SealedCredentials, 0)
+	for _, sealed := range r.records {
+		if sealed.KeyVersion != activeVersion && len(result) < limit {
+			result = append(result, sealed)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryVaultRepository) UpdateWrappedKey(_ context.Context, userID uint, keyVersion, wrappedKey string) error {
+	sealed := r.records[userID]
+	sealed.KeyVersion = keyVersion
+	sealed.WrappedKey = wrappedKey
+	r.records[userID] = sealed
+	return nil
+}
+
+func (r *memoryVaultRepository) GetOutdatedSecrets(_ context.Context, source models.SealedSecretSource, activeVersion string, afterID uint, limit int) ([]models.SealedSecret, error) {
+	ids := make([]uint, 0)
+	for id, value := range r.secrets[source] {
+		if id > afterID && !strings.HasPrefix(value, activeVersion+models.SealedSecretSeparator) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	result := make([]models.SealedSecret, 0)
+	for _, id := range ids {
+		if len(result) < limit {
+			result = append(result, models.SealedSecret{Source: source, ID: id, Value: r.secrets[source][id]})
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryVaultRepository) UpdateSecret(_ context.Context, secret *models.SealedSecret, value string) (bool, error) {
+	if r.secrets[secret.Source][secret.ID] != secret.Value {
+		return false, nil
+	}
+	r.secrets[secret.Source][secret.ID] = value
+	return true, nil
+}
+
+func TestCredentialVault(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	repo := &memoryVaultRepository{records: make(map[uint]models.SealedCredentials)}
+	creds := &authModels.HaciendaCredentials{Username: "06142803901121", Password: "MH-password"}
+
+	keys, _, err := config.ParseVaultMasterKeys(
+		"v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=,v2:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=",
+	)
+	require.NoError(t, err)
+
+	// 1. Almacenar con la versión v1, el registro no contiene las credenciales en texto plano
+	vaultV1, err := crypt.NewCredentialVaultService(repo, keys, "v1")
+	require.NoError(t, err)
+	require.NoError(t, vaultV1.Store(ctx, 1, creds))
+
+	sealed := repo.records[1]
+	assert.Equal(t, "v1", sealed.KeyVersion)
+	assert.False(t, strings.Contains(sealed.Ciphertext, creds.Password))
+
+	stored, err := vaultV1.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, creds, stored)
+
+	_, err = vaultV1.Get(ctx, 2)
+	assert.Error(t, err)
+
+	// 2. Rotar a la versión v2, solo cambia la llave de datos cifrada
+	vaultV2, err := crypt.NewCredentialVaultService(repo, keys, "v2")
+	require.NoError(t, err)
+
+	rotated, err := vaultV2.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotated)
+	assert.Equal(t, "v2", repo.records[1].KeyVersion)
+	assert.Equal(t, sealed.Ciphertext, repo.records[1].Ciphertext)
+
+	rotated, err = vaultV2.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rotated)
+
+	// 3. Las credenciales se leen sin la llave maestra anterior
+	onlyV2, err := crypt.NewCredentialVaultService(repo, map[string][]byte{"v2": keys["v2"]}, "v2")
+	require.NoError(t, err)
+
+	stored, err = onlyV2.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, creds, stored)
+
+	// 4. Una llave maestra distinta no puede descifrar las credenciales
+	wrongKey, err := crypt.NewCredentialVaultService(repo, map[string][]byte{"v2": keys["v1"]}, "v2")
+	require.NoError(t, err)
+
+	_, err = wrongKey.Get(ctx, 1)
+	assert.Error(t, err)
+}
+
+func TestCredentialVaultSealSecret(t *testing.T) {
+	test.TestMain(t)
+
+	repo := &memoryVaultRepository{records: make(map[uint]models.SealedCredentials)}
+	keys, _, err := config.ParseVaultMasterKeys(
+		"v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=,v2:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=",
+	)
+	require.NoError(t, err)
+
+	vaultV1, err := crypt.NewCredentialVaultService(repo, keys, "v1")
+	require.NoError(t, err)
+
+	// 1. El secret cifrado incluye la versión de la llave maestra y no contiene el secret en texto plano
+	secret, err := crypt.NewCryptService().GenerateAPISecret()
+	require.NoError(t, err)
+	sealed, err := vaultV1.SealSecret(secret)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, "v1$"))
+	assert.False(t, strings.Contains(sealed, secret))
+	assert.LessOrEqual(t, len(sealed), 255, "the sealed secret must fit in the varchar(255) columns")
+
+	opened, err := vaultV1.OpenSecret(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, secret, opened)
+
+	// 2. Después de rotar la llave activa se sigue descifrando mientras la versión anterior esté configurada
+	vaultV2, err := crypt.NewCredentialVaultService(repo, keys, "v2")
+	require.NoError(t, err)
+
+	opened, err = vaultV2.OpenSecret(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, secret, opened)
+
+	onlyV2, err := crypt.NewCredentialVaultService(repo, map[string][]byte{"v2": keys["v2"]}, "v2")
+	require.NoError(t, err)
+	_, err = onlyV2.OpenSecret(sealed)
+	assert.Error(t, err)
+
+	// 3. Los valores que no fueron cifrados con el vault se rechazan
+	for _, value := range []string{"", secret, "sha256$" + strings.Repeat("a", 64), "v1$" + strings.Repeat("A", 40)} {
+		_, err = vaultV1.OpenSecret(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCredentialVaultRotateSecrets(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	keys, _, err := config.ParseVaultMasterKeys(
+		"v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=,v2:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=",
+	)
+	require.NoError(t, err)
+
+	// 1. Cifrar con la versión v1 un secret de cada ubicación y un valor que no fue cifrado con el vault
+	repo := &memoryVaultRepository{
+		records: make(map[uint]models.SealedCredentials),
+		secrets: make(map[models.SealedSecretSource]map[uint]string),
+	}
+	vaultV1, err := crypt.NewCredentialVaultService(repo, keys, "v1")
+	require.NoError(t, err)
+
+	plain := make(map[models.SealedSecretSource]string)
+	for _, source := range models.SealedSecretSources {
+		plain[source] = "secret-" + string(source)
+		sealed, err := vaultV1.SealSecret(plain[source])
+		require.NoError(t, err)
+		repo.secrets[source] = map[uint]string{1: sealed}
+	}
+	repo.secrets[models.WebhookSecret][2] = "v1$invalid"
+	require.NoError(t, vaultV1.Store(ctx, 1, &authModels.HaciendaCredentials{Username: "06142803901121", Password: "MH-password"}))
+
+	// 2. Rotar a la versión v2 vuelve a cifrar las credenciales y los secrets, el valor inválido se omite
+	vaultV2, err := crypt.NewCredentialVaultService(repo, keys, "v2")
+	require.NoError(t, err)
+
+	rotated, err := vaultV2.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1+len(models.SealedSecretSources), rotated)
+
+	rotated, err = vaultV2.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rotated)
+
+	// 3. Al retirar la llave v1 los secrets y las credenciales se siguen descifrando
+	onlyV2, err := crypt.NewCredentialVaultService(repo, map[string][]byte{"v2": keys["v2"]}, "v2")
+	require.NoError(t, err)
+
+	for _, source := range models.SealedSecretSources {
+		assert.True(t, strings.HasPrefix(repo.secrets[source][1], "v2$"), source)
+		opened, err := onlyV2.OpenSecret(repo.secrets[source][1])
+		require.NoError(t, err, source)
+		assert.Equal(t, plain[source], opened)
+	}
+
+	_, err = onlyV2.Get(ctx, 1)
+	assert.NoError(t, err)
+}
+
+func TestParseVaultMasterKeys(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Single key", raw: "v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", want: []string{"v1"}},
+		{name: "Multiple keys keep order", raw: "v2:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=, v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", want: []string{"v2", "v1"}},
+		{name: "Missing version", raw: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", wantErr: true},
+		{name: "Short key", raw: "v1:c2hvcnQ=", wantErr: true},
+		{name: "Duplicated version", raw: "v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=,v1:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=", wantErr: true},
+		{name: "Empty", raw: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, order, err := config.ParseVaultMasterKeys(tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, order)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestVerifyAPISecret(t *testing.T) {
+	test.TestMain(t)
+
+	cryptService := crypt.NewCryptService()
+	secret, err := cryptService.GenerateAPISecret()
+	require.NoError(t, err)
+
+	hashed := cryptService.HashAPISecret(secret)
+	assert.NotContains(t, hashed, secret)
+	assert.True(t, strings.HasPrefix(hashed, "sha256$"))
+
+	tests := []struct {
+		name   string
+		secret string
+		stored string
+		want   bool
+	}{
+		{name: "Matching secret", secret: secret, stored: hashed, want: true},
+		{name: "Wrong secret", secret: secret + "x", stored: hashed},
+		{name: "Empty secret", secret: "", stored: cryptService.HashAPISecret("")},
+		{name: "Plain text stored secret", secret: secret, stored: secret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cryptService.VerifyAPISecret(tt.secret, tt.stored))
+		})
+	}
+}
+
+func TestVerifyRequestSignature(t *testing.T) {
+	test.TestMain(t)
+
+	cryptService := crypt.NewCryptService()
+	secret, err := cryptService.GenerateAPISecret()
+	require.NoError(t, err)
+	hashed := cryptService.HashAPISecret(secret)
+
+	request := &models.RequestCredentials{
+		Method:    "post",
+		Path:      "/api/v1/dte/invoices?async=true",
+		Timestamp: "1760000000",
+		BodyHash:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}
+	payload := request.CanonicalRequest()
+	assert.Equal(t, "POST\n/api/v1/dte/invoices?async=true\n1760000000\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", payload)
+
+	// El cliente firma con el API secret que recibió
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	// El hash almacenado del API secret no sirve como llave de firma
+	hashMAC := hmac.New(sha256.New, []byte(strings.TrimPrefix(hashed, "sha256$")))
+	hashMAC.Write([]byte(payload))
+	hashSignature := hex.EncodeToString(hashMAC.Sum(nil))
+
+	tests := []struct {
+		name      string
+		stored    string
+		payload   string
+		signature string
+		want      bool
+	}{
+		{name: "Valid signature", stored: secret, payload: payload, signature: signature, want: true},
+		{name: "Uppercase signature", stored: secret, payload: payload, signature: strings.ToUpper(signature)},
+		{name: "Tampered payload", stored: secret, payload: payload + "x", signature: signature},
+		{name: "Other secret", stored: secret + "x", payload: payload, signature: signature},
+		{name: "Signed with the stored hash", stored: secret, payload: payload, signature: hashSignature},
+		{name: "Hash as verification key", stored: hashed, payload: payload, signature: signature},
+		{name: "Empty secret", stored: "", payload: payload, signature: signature},
+		{name: "Empty signature", stored: secret, payload: payload, signature: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cryptService.VerifyRequestSignature(tt.stored, tt.payload, tt.signature))
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	dteModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/dte_documents"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// exportServerWriteTimeout WriteTimeout del servidor de prueba, menor que la pausa entre bloques de la exportación
+const exportServerWriteTimeout = 200 * time.Millisecond
+
+// memoryCache caché en memoria con las operaciones que utiliza el middleware de métricas
+type memoryCache struct {
+	ports.CacheManager
+	values map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string]string)}
+}
+
+func (c *memoryCache) Set(key string, value []byte, _ time.Duration) error {
+	c.values[key] = string(value)
+	return nil
+}
+
+func (c *memoryCache) Get(key string) (string, error) {
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (c *memoryCache) LPush(string, []byte) error {
+	return nil
+}
+
+func (c *memoryCache) LTrim(string, int64, int64) error {
+	return nil
+}
+
+// GetRedisClient retorna un cliente sin servidor, el registro del endpoint falla y solo se registra en los logs
+func (c *memoryCache) GetRedisClient() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: "127.0.0.1:0", MaxRetries: -1})
+}
+
+// chunkPauser genera los registros de una exportación en dos bloques y se detiene entre ellos hasta que el cliente
+// recibe el primero, más tiempo que el WriteTimeout del servidor
+type chunkPauser struct {
+	total      int
+	pauseAfter int
+	received   chan struct{}
+}
+
+func newChunkPauser() *chunkPauser {
+	return &chunkPauser{total: 150, pauseAfter: 100, received: make(chan struct{})}
+}
+
+// emit invoca fn por cada registro, deteniéndose después del primer bloque
+func (p *chunkPauser) emit(ctx context.Context, fn func(i int) error) error {
+	for i := 0; i < p.total; i++ {
+		if i == p.pauseAfter {
+			select {
+			case <-p.received:
+			case <-time.After(5 * time.Second):
+				return fmt.Errorf("the client did not receive the first chunk before the export finished")
+			}
+			time.Sleep(2 * exportServerWriteTimeout)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLines cuenta las líneas de la respuesta, avisa a la exportación cuando llega el primer bloque
+func (p *chunkPauser) readLines(t *testing.T, body io.Reader) int {
+	lines := 0
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		lines++
+		if lines == p.pauseAfter {
+			close(p.received)
+		}
+	}
+
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+// pausingExportManager exporta los DTEs en dos bloques
+type pausingExportManager struct {
+	dte_documents.DTEManager
+	*chunkPauser
+}
+
+func (m *pausingExportManager) ExportDTEs(ctx context.Context, _ *dteModels.DTEFilters, fn func(*dteModels.DTEExportRecord) error) error {
+	return m.emit(ctx, func(i int) error {
+		return fn(&dteModels.DTEExportRecord{
+			GenerationCode: fmt.Sprintf("A0000000-0000-0000-0000-%012d", i),
+			Status:         constants.DocumentReceived,
+			Transmission:   constants.TransmissionNormal,
+			JSONData:       fmt.Sprintf(`{"identificacion": {"numeroControl": "DTE-01-M001P001-%015d"}}`, i),
+		})
+	})
+}
+
+// newTestServer inicia un servidor con los middlewares globales del servidor de la API y las rutas indicadas
+func newTestServer(t *testing.T, register func(router *mux.Router)) *httptest.Server {
+	router := mux.NewRouter()
+	router.Use(middleware.NewRequestContextMiddleware().Handler)
+	router.Use(middleware.NewLanguageMiddleware().Handler)
+	router.Use(middleware.NewErrorMiddleware().Handler)
+	router.Use(middleware.NewTimeoutMiddleware().Handler)
+	register(router)
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = exportServerWriteTimeout
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newProtectedTestServer inicia un servidor con los middlewares globales y protegidos del servidor de la API, la
+// autenticación se reemplaza por los claims indicados
+func newProtectedTestServer(t *testing.T, claims *authModels.AuthClaims, register func(router *mux.Router)) *httptest.Server {
+	return newTestServer(t, func(router *mux.Router) {
+		protected := router.PathPrefix("/api/v1").Subrouter()
+		protected.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "claims", claims)))
+			})
+		})
+		protected.Use(middleware.NewMetricsMiddleware(newMemoryCache()).Handle)
+		register(protected)
+	})
+}
+
+func TestDTEExportStreamsChunks(t *testing.T) {
+	test.TestMain(t)
+
+	manager := &pausingExportManager{chunkPauser: newChunkPauser()}
+	handler := handlers.NewDTEHandler(dte.NewDTEConsultUseCase(manager), nil, nil)
+	server := newProtectedTestServer(t, &authModels.AuthClaims{NIT: "06140101001011", BranchID: 1}, func(router *mux.Router) {
+		middleware.ExportRoute(router.HandleFunc("/dte/export", handler.Export).Methods(http.MethodGet), "dte")
+	})
+
+	resp, err := server.Client().Get(server.URL + "/api/v1/dte/export?format=ndjson")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	// El primer bloque llega mientras la exportación sigue en curso, el resto después de superar el WriteTimeout
+	assert.Equal(t, manager.total, manager.readLines(t, resp.Body))
+}
+
+func TestTimeoutMiddlewareRouteMarkers(t *testing.T) {
+	test.TestMain(t)
+
+	// Cada ruta registra el tiempo restante del contexto de la solicitud, cero si no tiene tiempo máximo
+	remaining := make(map[string]time.Duration)
+	probe := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if deadline, ok := r.Context().Deadline(); ok {
+				remaining[name] = time.Until(deadline)
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewTimeoutMiddleware().Handler)
+	middleware.StreamingRoute(router.HandleFunc("/api/v1/events/live", probe("streaming")).Methods(http.MethodGet), "live")
+	middleware.ExportRoute(router.HandleFunc("/api/v1/reports/export", probe("export")).Methods(http.MethodGet), "reports")
+	router.HandleFunc("/api/v1/dte/export", probe("unmarked")).Methods(http.MethodGet)
+
+	for _, path := range []string{"/api/v1/events/live", "/api/v1/reports/export", "/api/v1/dte/export"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, path)
+	}
+
+	// La marca de la ruta define el tiempo máximo, no la URL de la solicitud
+	assert.NotContains(t, remaining, "streaming")
+	assert.Greater(t, remaining["export"], time.Minute)
+	assert.LessOrEqual(t, remaining["unmarked"], 15*time.Second)
+}
@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	dteModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestParseDTESearchFilters(t *testing.T) {
+	test.TestMain(t)
+
+	minAmount, maxAmount := 10.5, 100.0
+	invalidParam := func(param, expected string) string {
+		return shared_error.NewFormattedGeneralServiceError("ListDTEsUseCase", "ParseDTEQueryFilters", "InvalidQueryParam", param, expected).Error()
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected dteModels.DTEFilters
+		err      string
+	}{
+		{
+			name:  "Receiver fields",
+			query: "receiverNit=0614-010100-101-2&receiverNrc=76543-2&receiverName=%20ACME%20&controlNumber=000000000000001&receptionStamp=2024ABCD",
+			expected: dteModels.DTEFilters{
+				ReceiverNIT:    "06140101001012",
+				ReceiverNRC:    "765432",
+				ReceiverName:   "ACME",
+				ControlNumber:  "000000000000001",
+				ReceptionStamp: "2024ABCD",
+			},
+		},
+		{
+			name:     "Items",
+			query:    "itemCode=P-001&itemDescription=caf%C3%A9",
+			expected: dteModels.DTEFilters{ItemCode: "P-001", ItemDescription: "café"},
+		},
+		{
+			name:     "Amount range",
+			query:    "minAmount=10.5&maxAmount=100",
+			expected: dteModels.DTEFilters{MinAmount: &minAmount, MaxAmount: &maxAmount},
+		},
+		{
+			name:     "Sort options are case insensitive",
+			query:    "sortBy=RECEIVER_NAME&sortOrder=ASC",
+			expected: dteModels.DTEFilters{SortBy: dteModels.SortByReceiverName, SortOrder: dteModels.SortAsc},
+		},
+		{
+			name:  "Invalid minimum amount",
+			query: "minAmount=abc",
+			err:   invalidParam("minAmount", "a positive number"),
+		},
+		{
+			name:  "Negative maximum amount",
+			query: "maxAmount=-1",
+			err:   invalidParam("maxAmount", "a positive number"),
+		},
+		{
+			name:  "Minimum amount greater than maximum",
+			query: "minAmount=200&maxAmount=100",
+			err:   invalidParam("minAmount", "less than or equal to maxAmount"),
+		},
+		{
+			name:  "Unknown sort field",
+			query: "sortBy=receiver_nit",
+			err:   invalidParam("sortBy", "'date', 'amount', 'control_number', 'receiver_name'"),
+		},
+		{
+			name:  "Unknown sort order",
+			query: "sortOrder=up",
+			err:   invalidParam("sortOrder", "'asc', 'desc'"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := dte.ParseDTEQueryFilters(httptest.NewRequest(http.MethodGet, "/api/v1/dte?"+tt.query, nil))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected.ReceiverNIT, filters.ReceiverNIT)
+			assert.Equal(t, tt.expected.ReceiverNRC, filters.ReceiverNRC)
+			assert.Equal(t, tt.expected.ReceiverName, filters.ReceiverName)
+			assert.Equal(t, tt.expected.ControlNumber, filters.ControlNumber)
+			assert.Equal(t, tt.expected.ReceptionStamp, filters.ReceptionStamp)
+			assert.Equal(t, tt.expected.ItemCode, filters.ItemCode)
+			assert.Equal(t, tt.expected.ItemDescription, filters.ItemDescription)
+			assert.Equal(t, tt.expected.MinAmount, filters.MinAmount)
+			assert.Equal(t, tt.expected.MaxAmount, filters.MaxAmount)
+			assert.Equal(t, tt.expected.SortBy, filters.SortBy)
+			assert.Equal(t, tt.expected.SortOrder, filters.SortOrder)
+		})
+	}
+}
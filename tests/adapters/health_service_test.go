@@ -0,0 +1,150 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/application/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/health/models"
+	adapterHealth "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/health"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// stubChecker retorna un estado fijo después de una demora y cuenta las verificaciones ejecutadas
+type stubChecker struct {
+	name   string
+	status string
+	delay  time.Duration
+	calls  int32
+}
+
+func (c *stubChecker) Name() string {
+	return c.name
+}
+
+func (c *stubChecker) Check() models.Health {
+	atomic.AddInt32(&c.calls, 1)
+	time.Sleep(c.delay)
+	return models.Health{Status: c.status}
+}
+
+// stubMonitor reporta como caídos los componentes indicados
+type stubMonitor map[string]bool
+
+func (m stubMonitor) IsAvailable(component string) bool {
+	return !m[component]
+}
+
+func TestHealthServiceStatus(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name       string
+		database   string
+		hacienda   string
+		redis      string
+		wantStatus string
+	}{
+		{name: "All components up", database: constants.StatusUp, hacienda: constants.StatusUp, redis: constants.StatusUp, wantStatus: constants.StatusUp},
+		{name: "Hacienda down degrades the service", database: constants.StatusUp, hacienda: constants.StatusDown, redis: constants.StatusUp, wantStatus: constants.StatusDegraded},
+		{name: "Redis down degrades the service", database: constants.StatusUp, hacienda: constants.StatusUp, redis: constants.StatusDown, wantStatus: constants.StatusDegraded},
+		{name: "Database down takes the service down", database: constants.StatusDown, hacienda: constants.StatusDown, redis: constants.StatusUp, wantStatus: constants.StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := adapterHealth.NewHealthServiceWithComponents([]adapterHealth.Component{
+				{Checker: &stubChecker{name: constants.ComponentDatabase, status: tt.database}, Critical: true},
+				{Checker: &stubChecker{name: constants.ComponentHacienda, status: tt.hacienda}},
+				{Checker: &stubChecker{name: constants.ComponentRedis, status: tt.redis}},
+			}, time.Second, time.Minute)
+
+			status, err := service.CheckHealth(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, status.Status)
+			assert.True(t, status.Components[constants.ComponentDatabase].Critical)
+			assert.False(t, status.Components[constants.ComponentHacienda].Critical)
+			assert.Equal(t, tt.hacienda != constants.StatusDown, service.IsAvailable(constants.ComponentHacienda))
+		})
+	}
+}
+
+func TestHealthServiceTimeoutAndCache(t *testing.T) {
+	test.TestMain(t)
+
+	database := &stubChecker{name: constants.ComponentDatabase, status: constants.StatusUp, delay: 50 * time.Millisecond}
+	signer := &stubChecker{name: constants.ComponentSigner, status: constants.StatusUp, delay: 50 * time.Millisecond}
+	hacienda := &stubChecker{name: constants.ComponentHacienda, status: constants.StatusUp, delay: time.Second}
+	service := adapterHealth.NewHealthServiceWithComponents([]adapterHealth.Component{
+		{Checker: database, Critical: true},
+		{Checker: signer},
+		{Checker: hacienda},
+	}, 200*time.Millisecond, time.Minute)
+
+	// Los componentes se verifican de forma concurrente y el que excede el tiempo máximo se considera caído
+	start := time.Now()
+	status, err := service.CheckHealth(context.Background())
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, constants.StatusDegraded, status.Status)
+	assert.Equal(t, constants.StatusDown, status.Components[constants.ComponentHacienda].Status)
+	assert.Contains(t, status.Components[constants.ComponentHacienda].Details, "did not respond")
+
+	// Mientras el resultado está vigente no se vuelve a verificar
+	_, err = service.CheckHealth(context.Background())
+	require.NoError(t, err)
+	assert.False(t, service.IsAvailable(constants.ComponentHacienda))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&database.calls))
+
+	// La sonda de liveness no verifica dependencias
+	assert.Equal(t, constants.StatusUp, service.CheckLiveness().Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&signer.calls))
+}
+
+func TestHealthServiceAvailabilityBeforeFirstCheck(t *testing.T) {
+	test.TestMain(t)
+
+	hacienda := &stubChecker{name: constants.ComponentHacienda, status: constants.StatusDown}
+	service := adapterHealth.NewHealthServiceWithComponents([]adapterHealth.Component{
+		{Checker: hacienda},
+	}, time.Second, time.Minute)
+
+	// Sin una verificación previa se asume disponible y la verificación se inicia en segundo plano
+	assert.True(t, service.IsAvailable(constants.ComponentHacienda))
+	assert.Eventually(t, func() bool {
+		return !service.IsAvailable(constants.ComponentHacienda)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBaseTransmitterDegradedMode(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name          string
+		down          stubMonitor
+		wantComponent string
+	}{
+		{name: "Hacienda unavailable", down: stubMonitor{constants.ComponentHacienda: true}, wantComponent: constants.ComponentHacienda},
+		{name: "Signer unavailable", down: stubMonitor{constants.ComponentSigner: true}, wantComponent: constants.ComponentSigner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transmitter := dte.NewBaseTransmitter(nil, nil, tt.down)
+
+			_, err := transmitter.RetryTransmission(context.Background(), map[string]string{}, "token", "06141234567890")
+
+			var dependencyErr *health.DependencyUnavailableError
+			require.True(t, errors.As(err, &dependencyErr))
+			assert.Equal(t, tt.wantComponent, dependencyErr.Component)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/service/strategies"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	vaultModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryBranchRepository obtiene las sucursales y las llaves adicionales por su API key o certificado desde memoria
+type memoryBranchRepository struct {
+	auth.AuthRepositoryPort
+	branches     map[string]*user.BranchOffice
+	certBranches map[string]*user.BranchOffice
+	certKeys     map[string]*user.BranchAPIKey
+	owner        *user.User
+}
+
+func (r *memoryBranchRepository) GetBranchByBranchApiKey(_ context.Context, apiKey string) (*user.BranchOffice, error) {
+	branch, ok := r.branches[apiKey]
+	if !ok {
+		return nil, errPackage.ErrBranchOfficeNotFound
+	}
+	return branch, nil
+}
+
+func (r *memoryBranchRepository) GetBranchAPIKeyByKey(context.Context, string) (*user.BranchAPIKey, error) {
+	return nil, errPackage.ErrAPIKeyNotFound
+}
+
+func (r *memoryBranchRepository) GetByBranchID(context.Context, uint) (*user.User, error) {
+	return r.owner, nil
+}
+
+func (r *memoryBranchRepository) GetBranchByClientCertSubject(_ context.Context, subject string) (*user.BranchOffice, error) {
+	branch, ok := r.certBranches[subject]
+	if !ok {
+		return nil, errPackage.ErrBranchOfficeNotFound
+	}
+	return branch, nil
+}
+
+func (r *memoryBranchRepository) GetBranchAPIKeyByClientCertSubject(_ context.Context, subject string) (*user.BranchAPIKey, error) {
+	key, ok := r.certKeys[subject]
+	if !ok {
+		return nil, errPackage.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *memoryBranchRepository) GetBranchByBranchID(_ context.Context, branchID uint) (*user.BranchOffice, error) {
+	for _, branch := range r.certBranches {
+		if branch.ID == branchID {
+			return branch, nil
+		}
+	}
+	return nil, errPackage.ErrBranchOfficeNotFound
+}
+
+// recordingAuthManager cuenta las solicitudes firmadas que llegan a autenticarse y las acepta
+type recordingAuthManager struct {
+	auth.AuthManager
+	calls int
+}
+
+func (m *recordingAuthManager) AuthenticateRequest(context.Context, *authModels.RequestCredentials) (*authModels.AuthClaims, error) {
+	m.calls++
+	return &authModels.AuthClaims{ClientID: 1, BranchID: 1}, nil
+}
+
+// signCanonicalRequest firma una solicitud como lo hace el cliente
+func signCanonicalRequest(key string, credentials *authModels.RequestCredentials) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(credentials.CanonicalRequest()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACAuthSigningKey(t *testing.T) {
+	test.TestMain(t)
+
+	keys, _, err := config.ParseVaultMasterKeys("v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	require.NoError(t, err)
+	credentialVault, err := crypt.NewCredentialVaultService(&memoryVaultRepository{records: make(map[uint]vaultModels.SealedCredentials)}, keys, "v1")
+	require.NoError(t, err)
+
+	cryptService := crypt.NewCryptService()
+	secret, err := cryptService.GenerateAPISecret()
+	require.NoError(t, err)
+	signingSecret, err := credentialVault.SealSecret(secret)
+	require.NoError(t, err)
+
+	// La llave emitida antes de almacenar su API secret cifrado solo tiene el hash
+	repo := &memoryBranchRepository{
+		branches: map[string]*user.BranchOffice{
+			"sealed-key": {ID: 1, APISecret: cryptService.HashAPISecret(secret), APISigningSecret: &signingSecret},
+			"legacy-key": {ID: 2, APISecret: cryptService.HashAPISecret(secret)},
+		},
+		owner: &user.User{ID: 1, Status: true, AuthType: constants.HMACAuthType},
+	}
+	cache := newMemoryCache()
+	strategy := strategies.NewHMACAuthStrategy(repo, cache, cryptService, credentialVault)
+
+	invalidCredentials := shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "InvalidCredentials").Error()
+	// El registro de la firma en Redis ocurre después de verificarla, sin Redis la firma válida falla en ese paso
+	signatureAccepted := shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "AuthServiceUnavailable").Error()
+
+	tests := []struct {
+		name   string
+		apiKey string
+		key    string
+		want   string
+	}{
+		{name: "Signed with the API secret", apiKey: "sealed-key", key: secret, want: signatureAccepted},
+		{name: "Signed with the stored hash", apiKey: "sealed-key", key: strings.TrimPrefix(cryptService.HashAPISecret(secret), "sha256$"), want: invalidCredentials},
+		{name: "Signed with the sealed secret", apiKey: "sealed-key", key: signingSecret, want: invalidCredentials},
+		{name: "Key without sealed secret", apiKey: "legacy-key", key: secret, want: invalidCredentials},
+		{name: "Unknown key", apiKey: "unknown-key", key: secret, want: invalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials := &authModels.RequestCredentials{
+				AuthType:  constants.HMACAuthType,
+				APIKey:    tt.apiKey,
+				Timestamp: strconv.FormatInt(utils.TimeNow().Unix(), 10),
+				Method:    "GET",
+				Path:      "/api/v1/dte",
+				BodyHash:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			}
+			credentials.Signature = signCanonicalRequest(tt.key, credentials)
+
+			_, err := strategy.AuthenticateRequest(context.Background(), credentials)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
+
+func TestMTLSAuthScopes(t *testing.T) {
+	test.TestMain(t)
+
+	owner := &user.User{ID: 1, NIT: "06140101001011", Status: true, AuthType: constants.MTLSAuthType}
+	branch := &user.BranchOffice{ID: 1, IsActive: true, User: owner}
+	repo := &memoryBranchRepository{
+		certBranches: map[string]*user.BranchOffice{"CN=sucursal-01,O=Empresa": branch},
+		certKeys: map[string]*user.BranchAPIKey{
+			"CN=emision,O=Empresa":        {ID: 5, BranchID: 1, IsActive: true, Scopes: []string{constants.ScopeDTEIssue, constants.ScopeDTERead}},
+			"spiffe://empresa/contable":   {ID: 6, BranchID: 1, IsActive: true, Scopes: []string{constants.ScopeReports}},
+			"CN=revocada,O=Empresa":       {ID: 7, BranchID: 1, IsActive: false, Scopes: []string{constants.ScopeAdmin}},
+			"CN=sucursal-inactiva,O=Otra": {ID: 8, BranchID: 2, IsActive: true, Scopes: []string{constants.ScopeAdmin}},
+		},
+		owner: owner,
+	}
+	strategy := strategies.NewMTLSAuthStrategy(repo)
+
+	tests := []struct {
+		name       string
+		subject    string
+		sans       []string
+		wantErr    bool
+		wantKeyID  uint
+		wantScopes []string
+	}{
+		{name: "Branch certificate gets least privilege", subject: "CN=sucursal-01,O=Empresa", wantScopes: []string{constants.ScopeDTERead}},
+		{name: "Key certificate gets the key scopes", subject: "CN=emision,O=Empresa", wantKeyID: 5, wantScopes: []string{constants.ScopeDTEIssue, constants.ScopeDTERead}},
+		{name: "Key mapped by SAN", subject: "CN=desconocido", sans: []string{"contable.empresa.sv", "spiffe://empresa/contable"}, wantKeyID: 6, wantScopes: []string{constants.ScopeReports}},
+		{name: "Key takes precedence over the branch", subject: "CN=sucursal-01,O=Empresa", sans: []string{"CN=emision,O=Empresa"}, wantKeyID: 5, wantScopes: []string{constants.ScopeDTEIssue, constants.ScopeDTERead}},
+		{name: "Revoked key", subject: "CN=revocada,O=Empresa", wantErr: true},
+		{name: "Key of a missing branch", subject: "CN=sucursal-inactiva,O=Otra", wantErr: true},
+		{name: "Unknown certificate", subject: "CN=desconocido", sans: []string{"otro.empresa.sv"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := strategy.AuthenticateRequest(context.Background(), &authModels.RequestCredentials{
+				AuthType:    constants.MTLSAuthType,
+				CertSubject: tt.subject,
+				CertSANs:    tt.sans,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, shared_error.NewFormattedGeneralServiceError("MTLSAuth", "AuthenticateRequest", "InvalidCredentials").Error(), err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), claims.BranchID)
+			assert.Equal(t, tt.wantKeyID, claims.KeyID)
+			assert.Equal(t, tt.wantScopes, claims.Scopes)
+			assert.False(t, claims.HasScope(constants.ScopeAdmin))
+		})
+	}
+}
+
+func TestAuthMiddlewareSignedBodyLimit(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name      string
+		size      int
+		wantCode  int
+		wantCalls int
+	}{
+		{name: "Body within the limit", size: 1 << 20, wantCode: http.StatusOK, wantCalls: 1},
+		{name: "Body over the limit", size: 10<<20 + 1, wantCode: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &recordingAuthManager{}
+			var received int
+			handler := middleware.NewAuthMiddleware(nil, manager).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				received = len(body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/dte/invoices", strings.NewReader(strings.Repeat("a", tt.size)))
+			req.Header.Set(middleware.APIKeyHeader, "api-key")
+			req.Header.Set(middleware.TimestampHeader, strconv.FormatInt(utils.TimeNow().Unix(), 10))
+			req.Header.Set(middleware.SignatureHeader, "signature")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantCalls, manager.calls, "the body size is checked before authenticating the request")
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.size, received, "the body is restored for the next handlers")
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package adapters
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/cache"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+)
+
+// memoryRedisValue valor de una llave del servidor Redis en memoria, las llaves sin expiración tienen expiresAt en cero
+type memoryRedisValue struct {
+	value     string
+	expiresAt time.Time
+}
+
+// memoryRedis servidor Redis en memoria con los comandos que utilizan la caché de tokens y el servicio de JWT
+type memoryRedis struct {
+	mu     sync.Mutex
+	values map[string]memoryRedisValue
+}
+
+// newMemoryRedisCache inicia un servidor Redis en memoria y retorna la caché de tokens conectada a él
+func newMemoryRedisCache(t *testing.T) ports.CacheManager {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &memoryRedis{values: make(map[string]memoryRedisValue)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	cacheManager, err := cache.NewRedisTokenCache(&config.RedisConfig{Host: host, Port: port}, crypt.NewCryptService())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cacheManager.GetRedisClient().Close() })
+
+	return cacheManager
+}
+
+// serve atiende los comandos de una conexión, las transacciones se ejecutan al recibir EXEC
+func (m *memoryRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	var queued [][]string
+	inTransaction := false
+
+	for {
+		args, err := readRESPCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "MULTI":
+			inTransaction, queued, reply = true, nil, "+OK\r\n"
+		case "EXEC":
+			replies := make([]string, len(queued))
+			m.mu.Lock()
+			for i, command := range queued {
+				replies[i] = m.execute(command)
+			}
+			m.mu.Unlock()
+			inTransaction = false
+			reply = fmt.Sprintf("*%d\r\n%s", len(replies), strings.Join(replies, ""))
+		case "DISCARD":
+			inTransaction, queued, reply = false, nil, "+OK\r\n"
+		default:
+			if inTransaction {
+				queued = append(queued, args)
+				reply = "+QUEUED\r\n"
+				break
+			}
+			m.mu.Lock()
+			reply = m.execute(args)
+			m.mu.Unlock()
+		}
+
+		if _, err = io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// execute ejecuta un comando y retorna su respuesta en formato RESP
+func (m *memoryRedis) execute(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := m.get(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulkString(value.value)
+	case "GETDEL":
+		value, ok := m.get(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		delete(m.values, args[1])
+		return bulkString(value.value)
+	case "SET":
+		if !m.set(args[1], args[2], args[3:]) {
+			return "$-1\r\n"
+		}
+		return "+OK\r\n"
+	case "SETNX":
+		if !m.set(args[1], args[2], []string{"NX"}) {
+			return ":0\r\n"
+		}
+		return ":1\r\n"
+	case "DEL", "EXISTS":
+		count := 0
+		for _, key := range args[1:] {
+			if _, ok := m.get(key); ok {
+				count++
+				if strings.EqualFold(args[0], "DEL") {
+					delete(m.values, key)
+				}
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	case "PTTL":
+		value, ok := m.get(args[1])
+		switch {
+		case !ok:
+			return ":-2\r\n"
+		case value.expiresAt.IsZero():
+			return ":-1\r\n"
+		}
+		return fmt.Sprintf(":%d\r\n", time.Until(value.expiresAt).Milliseconds())
+	}
+
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+// get obtiene una llave vigente, las llaves expiradas se eliminan
+func (m *memoryRedis) get(key string) (memoryRedisValue, bool) {
+	value, ok := m.values[key]
+	if ok && !value.expiresAt.IsZero() && time.Now().After(value.expiresAt) {
+		delete(m.values, key)
+		return memoryRedisValue{}, false
+	}
+	return value, ok
+}
+
+// set guarda una llave con las opciones EX, PX y NX de SET, retorna falso si NX impidió reemplazar la llave
+func (m *memoryRedis) set(key, value string, options []string) bool {
+	entry := memoryRedisValue{value: value}
+	onlyNew := false
+
+	for i := 0; i < len(options); i++ {
+		switch strings.ToUpper(options[i]) {
+		case "EX", "PX":
+			amount, _ := strconv.ParseInt(options[i+1], 10, 64)
+			unit := time.Second
+			if strings.EqualFold(options[i], "PX") {
+				unit = time.Millisecond
+			}
+			entry.expiresAt = time.Now().Add(time.Duration(amount) * unit)
+			i++
+		case "NX":
+			onlyNew = true
+		}
+	}
+
+	if _, exists := m.get(key); exists && onlyNew {
+		return false
+	}
+
+	m.values[key] = entry
+	return true
+}
+
+// readRESPCommand lee un comando enviado por el cliente como un arreglo de cadenas
+func readRESPCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected RESP line %q", line)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+
+	return args, nil
+}
+
+func bulkString(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
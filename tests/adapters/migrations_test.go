@@ -0,0 +1,40 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	test.TestMain(t)
+
+	mysql, err := database.LoadMigrations("mysql")
+	require.NoError(t, err)
+	postgres, err := database.LoadMigrations("postgres")
+	require.NoError(t, err)
+	sqlite, err := database.LoadMigrations("sqlite")
+	require.NoError(t, err)
+
+	// Todos los drivers deben tener las mismas versiones para que la versión del esquema signifique lo mismo
+	require.Equal(t, len(mysql), len(postgres))
+	require.Equal(t, len(mysql), len(sqlite))
+	for i := range mysql {
+		assert.Equal(t, uint(i+1), mysql[i].Version, "versions must be contiguous")
+		assert.Equal(t, mysql[i].Version, postgres[i].Version)
+		assert.Equal(t, mysql[i].Name, postgres[i].Name)
+		assert.Equal(t, mysql[i].Name, sqlite[i].Name)
+		assert.NotNil(t, mysql[i].Up)
+		assert.NotNil(t, mysql[i].Down)
+		assert.NotNil(t, postgres[i].Up)
+		assert.NotNil(t, postgres[i].Down)
+	}
+	assert.Equal(t, "initial_schema", mysql[0].Name)
+
+	_, err = database.LoadMigrations("oracle")
+	assert.Error(t, err)
+}
@@ -0,0 +1,269 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	deliveryModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/notification/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/notification"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryNotificationRepository almacena en memoria los destinatarios y las notificaciones del motor
+type memoryNotificationRepository struct {
+	mu            sync.Mutex
+	recipients    []models.Recipient
+	notifications []models.Notification
+	certificates  []models.CertificateExpiry
+}
+
+func (r *memoryNotificationRepository) GetRecipients(_ context.Context, userID uint, entityType string) ([]models.Recipient, error) {
+	var recipients []models.Recipient
+	for _, recipient := range r.recipients {
+		if recipient.UserID == userID && recipient.EntityType == entityType {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients, nil
+}
+
+func (r *memoryNotificationRepository) GetAlertRecipients(_ context.Context, userID uint, entityTypes []string) ([]models.Recipient, error) {
+	var recipients []models.Recipient
+	for _, recipient := range r.recipients {
+		for _, entityType := range entityTypes {
+			if recipient.EnabledPush && recipient.EntityType == entityType &&
+				(entityType == models.EntityAdmin || recipient.UserID == userID) {
+				recipients = append(recipients, recipient)
+			}
+		}
+	}
+	return recipients, nil
+}
+
+func (r *memoryNotificationRepository) CreateRecipient(_ context.Context, recipient *models.Recipient) error {
+	recipient.ID = uint(len(r.recipients) + 1)
+	r.recipients = append(r.recipients, *recipient)
+	return nil
+}
+
+func (r *memoryNotificationRepository) DeleteRecipient(context.Context, uint, string, uint) error {
+	return nil
+}
+
+func (r *memoryNotificationRepository) GetTaxpayer(_ context.Context, userID uint) (*models.Taxpayer, error) {
+	return &models.Taxpayer{UserID: userID}, nil
+}
+
+func (r *memoryNotificationRepository) GetBranchTaxpayer(context.Context, uint) (*models.Taxpayer, error) {
+	return &models.Taxpayer{UserID: 1, NIT: "06140101001011", BusinessName: "Empresa de Prueba"}, nil
+}
+
+func (r *memoryNotificationRepository) CreateNotifications(_ context.Context, alert *models.Alert, notifications []models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payload, _ := json.Marshal(alert)
+	for i := range notifications {
+		notifications[i].ID = uint(len(r.notifications) + 1)
+		notifications[i].Payload = payload
+		r.notifications = append(r.notifications, notifications[i])
+	}
+	return nil
+}
+
+func (r *memoryNotificationRepository) GetNotifications(context.Context, uint, *models.NotificationFilters) ([]models.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.Notification(nil), r.notifications...), nil
+}
+
+func (r *memoryNotificationRepository) GetDueNotifications(_ context.Context, now time.Time, _ int) ([]models.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []models.Notification
+	for _, n := range r.notifications {
+		if n.Status == models.NotificationPending && n.NextAttemptAt != nil && !n.NextAttemptAt.After(now) {
+			due = append(due, n)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryNotificationRepository) ClaimNotification(_ context.Context, id uint, now, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := &r.notifications[id-1]
+	if n.Status != models.NotificationPending || n.NextAttemptAt == nil || n.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	n.NextAttemptAt = &leaseUntil
+	return true, nil
+}
+
+func (r *memoryNotificationRepository) UpdateNotification(_ context.Context, n *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := &r.notifications[n.ID-1]
+	stored.Status = n.Status
+	stored.Attempts = n.Attempts
+	stored.NextAttemptAt = n.NextAttemptAt
+	stored.ErrorMessage = n.ErrorMessage
+	return nil
+}
+
+func (r *memoryNotificationRepository) SetCertificateExpiry(context.Context, uint, *time.Time) error {
+	return nil
+}
+
+func (r *memoryNotificationRepository) GetExpiringCertificates(context.Context, time.Time) ([]models.CertificateExpiry, error) {
+	return r.certificates, nil
+}
+
+func (r *memoryNotificationRepository) GetContingencyBacklog(context.Context, time.Time) ([]models.ContingencyBacklog, error) {
+	return nil, nil
+}
+
+func (r *memoryNotificationRepository) GetSequenceGaps(context.Context, time.Time) ([]models.SequenceGap, error) {
+	return nil, nil
+}
+
+// memoryMailTransport registra los correos enviados, falla mientras failures sea mayor a cero
+type memoryMailTransport struct {
+	mu       sync.Mutex
+	sent     []deliveryModels.EmailMessage
+	failures int
+}
+
+func (t *memoryMailTransport) Name() string {
+	return "memory"
+}
+
+func (t *memoryMailTransport) Send(_ context.Context, message *deliveryModels.EmailMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.failures > 0 {
+		t.failures--
+		return errors.New("mailbox unavailable")
+	}
+	t.sent = append(t.sent, *message)
+	return nil
+}
+
+func (t *memoryMailTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.sent)
+}
+
+func TestNotificationRetryDelay(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "No failed attempts", attempts: 0, want: 0},
+		{name: "First retry", attempts: 1, want: 5 * time.Minute},
+		{name: "Last retry", attempts: models.MaxNotificationAttempts - 1, want: 40 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.RetryDelay(tt.attempts))
+		})
+	}
+}
+
+func TestNotificationRecipientValidate(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name      string
+		recipient *models.Recipient
+		wantEmail string
+		wantErr   bool
+	}{
+		{name: "Client recipient", recipient: &models.Recipient{EntityType: models.EntityClient, Email: " contabilidad@empresa.test "}, wantEmail: "contabilidad@empresa.test"},
+		{name: "Admin recipient with name", recipient: &models.Recipient{EntityType: models.EntityAdmin, Email: "Soporte <soporte@empresa.test>"}, wantEmail: "soporte@empresa.test"},
+		{name: "Invalid email", recipient: &models.Recipient{EntityType: models.EntityClient, Email: "contabilidad"}, wantErr: true},
+		{name: "Unknown entity type", recipient: &models.Recipient{EntityType: "BRANCH", Email: "contabilidad@empresa.test"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.recipient.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEmail, tt.recipient.Email)
+		})
+	}
+}
+
+func TestNotificationEngine(t *testing.T) {
+	test.TestMain(t)
+
+	repo := &memoryNotificationRepository{recipients: []models.Recipient{
+		{UserID: 1, EntityType: models.EntityClient, Email: "contabilidad@empresa.test", EnabledPush: true},
+		{UserID: 1, EntityType: models.EntityClient, Email: "gerencia@empresa.test", EnabledPush: false},
+		{UserID: 2, EntityType: models.EntityClient, Email: "otro@cliente.test", EnabledPush: true},
+		{EntityType: models.EntityAdmin, Email: "soporte@facturacion.test", EnabledPush: true},
+	}}
+	transport := &memoryMailTransport{failures: 1}
+	service := notification.NewNotificationService(repo, transport, "alertas@facturacion.test", "Alertas", nil, nil)
+
+	// El certificado del contribuyente vence en 10 días, la alerta se envía al cliente habilitado y a la administración
+	repo.certificates = []models.CertificateExpiry{{
+		Taxpayer:  models.Taxpayer{UserID: 1, NIT: "06140101001011", BusinessName: "Empresa de Prueba"},
+		ExpiresAt: utils.TimeNow().Add(10 * 24 * time.Hour),
+	}}
+	assert.NoError(t, service.ScanScheduledAlerts(context.Background()))
+
+	notifications, _ := repo.GetNotifications(context.Background(), 1, &models.NotificationFilters{})
+	assert.Len(t, notifications, 2)
+	for _, n := range notifications {
+		assert.Equal(t, models.ChannelEmail, n.Channel)
+		assert.Equal(t, uint(1), n.UserID)
+	}
+
+	// El primer envío falla y se reprograma con espera exponencial
+	assert.Eventually(t, func() bool {
+		notifications, _ = repo.GetNotifications(context.Background(), 1, &models.NotificationFilters{})
+		return transport.count() == 1 && notifications[0].Attempts == 1 && notifications[1].Attempts == 1
+	}, time.Second, 10*time.Millisecond)
+
+	var failed models.Notification
+	for _, n := range notifications {
+		if n.Status == models.NotificationPending {
+			failed = n
+		} else {
+			assert.Equal(t, models.NotificationSent, n.Status)
+		}
+	}
+	assert.NotNil(t, failed.ErrorMessage)
+	assert.WithinDuration(t, utils.TimeNow().Add(models.RetryDelay(1)), *failed.NextAttemptAt, time.Minute)
+
+	sent := transport.sent[0]
+	assert.Equal(t, "Certificado de firma próximo a vencer - Empresa de Prueba", sent.Subject)
+	assert.Contains(t, sent.TextBody, "NIT 06140101001011")
+
+	// El reintento no se realiza antes de su siguiente intento programado
+	assert.NoError(t, service.ProcessPendingNotifications(context.Background()))
+	assert.Equal(t, 1, transport.count())
+}
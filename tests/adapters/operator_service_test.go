@@ -0,0 +1,328 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	operatorUseCase "github.com/MarlonG1/api-facturacion-sv/internal/application/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	vaultModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/tokens"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/routes"
+	errPackage "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryOperatorRepository almacena en memoria los operadores y sus accesos, las sucursales asignadas se obtienen de
+// los accesos como lo hace el repositorio
+type memoryOperatorRepository struct {
+	operators []models.Operator
+	grants    []models.OperatorGrant
+	branches  []models.ManagedBranch
+}
+
+func (m *memoryOperatorRepository) Create(_ context.Context, operator *models.Operator) error {
+	operator.ID = uint(len(m.operators) + 1)
+	m.operators = append(m.operators, *operator)
+	return nil
+}
+
+func (m *memoryOperatorRepository) GetAll(_ context.Context) ([]models.Operator, error) {
+	return m.operators, nil
+}
+
+func (m *memoryOperatorRepository) GetByAPIKey(_ context.Context, apiKey string) (*models.Operator, error) {
+	for i := range m.operators {
+		if m.operators[i].APIKey == apiKey {
+			return &m.operators[i], nil
+		}
+	}
+	return nil, errPackage.ErrOperatorNotFound
+}
+
+func (m *memoryOperatorRepository) CreateGrant(_ context.Context, grant *models.OperatorGrant) error {
+	grant.ID = uint(len(m.grants) + 1)
+	m.grants = append(m.grants, *grant)
+	return nil
+}
+
+func (m *memoryOperatorRepository) DeleteGrant(_ context.Context, operatorID, grantID uint) (*models.OperatorGrant, error) {
+	for i, grant := range m.grants {
+		if grant.ID == grantID && grant.OperatorID == operatorID {
+			m.grants = append(m.grants[:i], m.grants[i+1:]...)
+			return &grant, nil
+		}
+	}
+	return nil, errPackage.ErrOperatorGrantNotFound
+}
+
+func (m *memoryOperatorRepository) Deactivate(_ context.Context, operatorID uint) error {
+	for i := range m.operators {
+		if m.operators[i].ID == operatorID {
+			m.operators[i].IsActive = false
+			return nil
+		}
+	}
+	return errPackage.ErrOperatorNotFound
+}
+
+func (m *memoryOperatorRepository) GetManagedBranches(_ context.Context, operatorID uint) ([]models.ManagedBranch, error) {
+	var managed []models.ManagedBranch
+	for _, branch := range m.branches {
+		for _, grant := range m.grants {
+			if grant.OperatorID == operatorID && grant.UserID == branch.UserID && (grant.BranchID == nil || *grant.BranchID == branch.BranchID) {
+				managed = append(managed, branch)
+				break
+			}
+		}
+	}
+	return managed, nil
+}
+
+// operatorTestSetup contiene el servicio de operadores con las dependencias reales y un operador registrado
+type operatorTestSetup struct {
+	manager      operator.OperatorManager
+	cacheManager ports.CacheManager
+	jwtService   *tokens.JWTService
+	credentials  *models.OperatorCredentials
+	hacienda     *authModels.HaciendaCredentials
+}
+
+// newOperatorTestSetup registra un operador con acceso a la sucursal 10 del contribuyente 1. La sucursal 20 pertenece
+// al contribuyente 2, que no está asignado al operador
+func newOperatorTestSetup(t *testing.T) *operatorTestSetup {
+	ctx := context.Background()
+	cacheManager := newMemoryRedisCache(t)
+	jwtService := tokens.NewJWTService("operator-test-secret", cacheManager)
+	cryptService := crypt.NewCryptService()
+
+	keys, _, err := config.ParseVaultMasterKeys("v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	require.NoError(t, err)
+	credentialVault, err := crypt.NewCredentialVaultService(&memoryVaultRepository{records: make(map[uint]vaultModels.SealedCredentials)}, keys, "v1")
+	require.NoError(t, err)
+
+	hacienda := &authModels.HaciendaCredentials{Username: "06142803901121", Password: "MH-password"}
+	require.NoError(t, credentialVault.Store(ctx, 1, hacienda))
+
+	secret, err := cryptService.GenerateAPISecret()
+	require.NoError(t, err)
+
+	repo := &memoryOperatorRepository{
+		branches: []models.ManagedBranch{
+			{UserID: 1, BranchID: 10, NIT: "06142803901121", AuthType: "TOKEN"},
+			{UserID: 2, BranchID: 20, NIT: "06142803901122", AuthType: "TOKEN"},
+		},
+	}
+	manager := operator.NewOperatorService(repo, jwtService, cacheManager, cryptService, credentialVault, audit.NewAuditService(&memoryAuditRepository{}))
+
+	require.NoError(t, manager.Create(ctx, &models.Operator{
+		Name:      "Firma Contable",
+		Email:     "firma@example.com",
+		APIKey:    "operator-key",
+		APISecret: cryptService.HashAPISecret(secret),
+		IsActive:  true,
+	}))
+	require.NoError(t, manager.Grant(ctx, &models.OperatorGrant{OperatorID: 1, UserID: 1}))
+
+	return &operatorTestSetup{
+		manager:      manager,
+		cacheManager: cacheManager,
+		jwtService:   jwtService,
+		credentials:  &models.OperatorCredentials{APIKey: "operator-key", APISecret: secret},
+		hacienda:     hacienda,
+	}
+}
+
+// login inicia sesión con el operador registrado y retorna los claims de su token
+func (s *operatorTestSetup) login(t *testing.T) (*authModels.TokenResponse, *authModels.AuthClaims) {
+	token, err := s.manager.Login(context.Background(), s.credentials)
+	require.NoError(t, err)
+
+	claims, err := s.jwtService.ValidateToken(context.Background(), token.Token)
+	require.NoError(t, err)
+	return token, claims
+}
+
+func TestOperatorSwitchIssuer(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	setup := newOperatorTestSetup(t)
+
+	// 1. El token de operador solo tiene el scope de operador y no pertenece a ninguna sucursal
+	operatorToken, operatorClaims := setup.login(t)
+	assert.Equal(t, []string{constants.ScopeOperator}, operatorClaims.Scopes)
+	assert.Equal(t, uint(1), operatorClaims.OperatorID)
+	assert.Zero(t, operatorClaims.ClientID)
+	assert.Zero(t, operatorClaims.BranchID)
+
+	// 2. El operador no puede actuar como una sucursal de un contribuyente que no tiene asignado
+	_, err := setup.manager.SwitchIssuer(ctx, operatorClaims, 20)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("OperatorService", "SwitchIssuer", "BranchNotGranted", uint(20)).Error())
+
+	// 3. El token de la sucursal asignada tiene los scopes de emisión, el contribuyente y las credenciales de Hacienda
+	switched, err := setup.manager.SwitchIssuer(ctx, operatorClaims, 10)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, switched.ExpiresIn, int64(operator.OperatorTokenLifetime.Seconds()))
+
+	branchClaims, err := setup.jwtService.ValidateToken(ctx, switched.Token)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), branchClaims.ClientID)
+	assert.Equal(t, uint(10), branchClaims.BranchID)
+	assert.Equal(t, uint(1), branchClaims.OperatorID)
+	assert.Equal(t, constants.OperatorIssuerScopes, branchClaims.Scopes)
+
+	creds, err := setup.cacheManager.GetCredentials(switched.Token)
+	require.NoError(t, err)
+	assert.Equal(t, setup.hacienda, creds)
+
+	// 4. Al revocar el acceso el operador ya no puede cambiar a la sucursal y el token emitido deja de ser válido, el
+	// token de operador sigue vigente
+	require.NoError(t, setup.manager.RevokeGrant(ctx, 1, 1))
+	_, err = setup.manager.SwitchIssuer(ctx, operatorClaims, 10)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("OperatorService", "SwitchIssuer", "BranchNotGranted", uint(10)).Error())
+
+	_, err = setup.jwtService.ValidateToken(ctx, switched.Token)
+	assert.Error(t, err)
+	_, err = setup.jwtService.ValidateToken(ctx, operatorToken.Token)
+	assert.NoError(t, err)
+
+	// 5. Un token de operador expirado no puede cambiar de emisor
+	require.NoError(t, setup.manager.Grant(ctx, &models.OperatorGrant{OperatorID: 1, UserID: 1}))
+	expiredClaims := *operatorClaims
+	expiredClaims.IssuedAt = utils.TimeNow().Add(-operator.OperatorTokenLifetime - time.Minute)
+	_, err = setup.manager.SwitchIssuer(ctx, &expiredClaims, 10)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("OperatorService", "SwitchIssuer", "Unauthorized").Error())
+}
+
+func TestOperatorRevokeBranchGrant(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	setup := newOperatorTestSetup(t)
+	branchID := uint(10)
+	require.NoError(t, setup.manager.Grant(ctx, &models.OperatorGrant{OperatorID: 1, UserID: 1, BranchID: &branchID}))
+
+	_, operatorClaims := setup.login(t)
+	switched, err := setup.manager.SwitchIssuer(ctx, operatorClaims, 10)
+	require.NoError(t, err)
+
+	// Revocar el acceso a la sucursal invalida el token emitido aunque el acceso al contribuyente siga vigente
+	require.NoError(t, setup.manager.RevokeGrant(ctx, 1, 2))
+	_, err = setup.jwtService.ValidateToken(ctx, switched.Token)
+	assert.Error(t, err)
+
+	// Un token emitido después de la revocación es válido
+	switched, err = setup.manager.SwitchIssuer(ctx, operatorClaims, 10)
+	require.NoError(t, err)
+	_, err = setup.jwtService.ValidateToken(ctx, switched.Token)
+	assert.NoError(t, err)
+}
+
+func TestOperatorDeactivate(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	setup := newOperatorTestSetup(t)
+	operatorToken, operatorClaims := setup.login(t)
+	switched, err := setup.manager.SwitchIssuer(ctx, operatorClaims, 10)
+	require.NoError(t, err)
+
+	// 1. Desactivar el operador invalida su token de operador y los tokens que emitió
+	require.NoError(t, setup.manager.Deactivate(ctx, 1))
+	_, err = setup.jwtService.ValidateToken(ctx, operatorToken.Token)
+	assert.Error(t, err)
+	_, err = setup.jwtService.ValidateToken(ctx, switched.Token)
+	assert.Error(t, err)
+
+	// 2. El operador desactivado no puede iniciar sesión
+	_, err = setup.manager.Login(ctx, setup.credentials)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("OperatorService", "Login", "OperatorNotActive").Error())
+
+	// 3. Desactivar un operador inexistente falla
+	err = setup.manager.Deactivate(ctx, 99)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("OperatorService", "Deactivate", "OperatorNotFound", uint(99)).Error())
+}
+
+func TestOperatorTokenScopes(t *testing.T) {
+	test.TestMain(t)
+
+	setup := newOperatorTestSetup(t)
+	operatorToken, _ := setup.login(t)
+
+	// Las rutas de operador y las rutas de la sucursal se protegen con los mismos middlewares que el servidor
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(setup.jwtService, nil).Handle)
+	scopes := middleware.NewScopeMiddleware()
+	handler := handlers.NewOperatorHandler(operatorUseCase.NewOperatorUseCase(setup.manager, nil, crypt.NewCryptService()))
+	routes.RegisterOperatorRoutes(router, handler, scopes)
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.Handle("/dte/invoice", scopes.Require(constants.ScopeDTEIssue, ok)).Methods(http.MethodPost)
+	router.Handle("/dte/all-branches", scopes.Require(constants.ScopeDTEReadAllBranches, ok)).Methods(http.MethodGet)
+	router.Handle("/branches/keys", scopes.Require(constants.ScopeAdmin, ok)).Methods(http.MethodPost)
+
+	send := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// 1. Cambiar de emisor a una sucursal asignada retorna el token de la sucursal
+	rec := send(http.MethodPost, "/operator/switch", operatorToken.Token, `{"branch_id":10}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var switched struct {
+		Data authModels.TokenResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&switched))
+	require.NotEmpty(t, switched.Data.Token)
+
+	// 2. Cambiar a una sucursal que no está asignada falla
+	assert.NotEqual(t, http.StatusOK, send(http.MethodPost, "/operator/switch", operatorToken.Token, `{"branch_id":20}`).Code)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		want   int
+	}{
+		{name: "Operator token cannot issue", method: http.MethodPost, path: "/dte/invoice", token: operatorToken.Token, want: http.StatusForbidden},
+		{name: "Operator token cannot administer", method: http.MethodPost, path: "/branches/keys", token: operatorToken.Token, want: http.StatusForbidden},
+		{name: "Operator token lists managed branches", method: http.MethodGet, path: "/operator/branches", token: operatorToken.Token, want: http.StatusOK},
+		{name: "Switched token can issue", method: http.MethodPost, path: "/dte/invoice", token: switched.Data.Token, want: http.StatusOK},
+		{name: "Switched token cannot administer", method: http.MethodPost, path: "/branches/keys", token: switched.Data.Token, want: http.StatusForbidden},
+		{name: "Switched token cannot read other branches", method: http.MethodGet, path: "/dte/all-branches", token: switched.Data.Token, want: http.StatusForbidden},
+		{name: "Switched token cannot switch again", method: http.MethodPost, path: "/operator/switch", token: switched.Data.Token, body: `{"branch_id":10}`, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, send(tt.method, tt.path, tt.token, tt.body).Code)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"context"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/delivery/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/delivery"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestOutboxTransportSend(t *testing.T) {
+	test.TestMain(t)
+
+	dir := t.TempDir()
+	transport := delivery.NewOutboxTransport(dir)
+
+	message := &models.EmailMessage{
+		From:     "facturacion@empresa.com",
+		FromName: "Empresa de Prueba",
+		To:       []string{"cliente@test.com"},
+		Subject:  "Factura electrónica DTE-01-00000000-000000000000001",
+		TextBody: "Se adjunta su factura electrónica",
+		HTMLBody: "<p>Se adjunta su factura electrónica</p>",
+		Attachments: []models.Attachment{
+			{Filename: "DTE-01-00000000-000000000000001.json", ContentType: "application/json", Content: []byte(`{"identificacion":{}}`)},
+			{Filename: "DTE-01-00000000-000000000000001.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.3")},
+		},
+	}
+
+	err := transport.Send(context.Background(), message)
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	content, err := os.Open(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	defer content.Close()
+
+	parsed, err := mail.ReadMessage(content)
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, message.Subject, subject)
+	assert.Equal(t, "cliente@test.com", parsed.Header.Get("To"))
+	assert.True(t, strings.HasPrefix(parsed.Header.Get("Content-Type"), "multipart/mixed"))
+}
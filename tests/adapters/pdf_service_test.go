@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/pdf/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/pdf"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+const invoiceJSON = `{
+	"identificacion": {"version": 1, "ambiente": "00", "tipoDte": "01", "numeroControl": "DTE-01-00000000-000000000000001",
+		"codigoGeneracion": "6A2B1C3D-4E5F-4A6B-8C7D-9E0F1A2B3C4D", "tipoModelo": 1, "tipoOperacion": 1, "fecEmi": "2024-01-15", "horEmi": "10:30:00", "tipoMoneda": "USD"},
+	"emisor": {"nit": "06141234567890", "nrc": "1234567", "nombre": "Empresa de Prueba S.A. de C.V.", "descActividad": "Venta al por menor",
+		"direccion": {"departamento": "06", "municipio": "14", "complemento": "Calle Principal #123"}, "telefono": "22223333", "correo": "emisor@test.com"},
+	"receptor": {"nombre": "Cliente de Prueba", "correo": "cliente@test.com"},
+	"cuerpoDocumento": [{"numItem": 1, "tipoItem": 1, "cantidad": 2, "codigo": "P001", "uniMedida": 59, "descripcion": "Producto de prueba",
+		"precioUni": 11.30, "montoDescu": 0, "ventaNoSuj": 0, "ventaExenta": 0, "ventaGravada": 22.60, "ivaItem": 2.60}],
+	"resumen": {"totalGravada": 22.60, "subTotalVentas": 22.60, "subTotal": 22.60, "montoTotalOperacion": 22.60, "totalPagar": 22.60,
+		"totalLetras": "VEINTIDOS 60/100 USD", "totalIva": 2.60, "condicionOperacion": 1}
+}`
+
+func TestPDFServiceGenerateDTE(t *testing.T) {
+	test.TestMain(t)
+
+	service := pdf.NewPDFService(nil)
+	stamp := "2024ABCDEF1234567890ABCDEF1234567890ABCD"
+
+	tests := []struct {
+		name     string
+		document *models.PDFDocument
+		wantErr  bool
+	}{
+		{
+			name: "Valid invoice document",
+			document: &models.PDFDocument{
+				DTEType:        constants.FacturaElectronica,
+				ReceiptType:    models.ReceiptDocument,
+				ControlNumber:  "DTE-01-00000000-000000000000001",
+				GenerationCode: "6A2B1C3D-4E5F-4A6B-8C7D-9E0F1A2B3C4D",
+				ReceptionStamp: &stamp,
+				Status:         constants.DocumentReceived,
+				QRLink:         "https://admin.factura.gob.sv/consultaPublica?ambiente=00",
+				EmissionDate:   utils.TimeNow(),
+				JSONData:       []byte(invoiceJSON),
+				Branding:       models.NewDefaultBranding(1),
+			},
+		},
+		{
+			name: "Invalidation receipt for invalidated document",
+			document: &models.PDFDocument{
+				DTEType:        constants.FacturaElectronica,
+				ReceiptType:    models.ReceiptInvalidation,
+				ControlNumber:  "DTE-01-00000000-000000000000001",
+				GenerationCode: "6A2B1C3D-4E5F-4A6B-8C7D-9E0F1A2B3C4D",
+				Status:         constants.DocumentInvalid,
+				QRLink:         "https://admin.factura.gob.sv/consultaPublica?ambiente=00",
+				EmissionDate:   utils.TimeNow(),
+				UpdatedAt:      utils.TimeNow().Format(time.DateTime),
+				JSONData:       []byte(invoiceJSON),
+			},
+		},
+		{
+			name: "Invalidation receipt for active document",
+			document: &models.PDFDocument{
+				DTEType:        constants.FacturaElectronica,
+				ReceiptType:    models.ReceiptInvalidation,
+				GenerationCode: "6A2B1C3D-4E5F-4A6B-8C7D-9E0F1A2B3C4D",
+				Status:         constants.DocumentReceived,
+				JSONData:       []byte(invoiceJSON),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unsupported DTE type",
+			document: &models.PDFDocument{
+				DTEType:     "99",
+				ReceiptType: models.ReceiptDocument,
+				JSONData:    []byte(invoiceJSON),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := service.GenerateDTE(context.Background(), tt.document)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, content)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(content, []byte("%PDF")))
+		})
+	}
+}
+
+func TestBrandingValidate(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name     string
+		branding *models.Branding
+		wantErr  bool
+	}{
+		{name: "Default branding", branding: models.NewDefaultBranding(1)},
+		{name: "Invalid color", branding: &models.Branding{BranchID: 1, PrimaryColor: "blue"}, wantErr: true},
+		{name: "Invalid logo format", branding: &models.Branding{BranchID: 1, Logo: []byte{0x1}, LogoFormat: "GIF"}, wantErr: true},
+		{name: "Logo too large", branding: &models.Branding{BranchID: 1, Logo: make([]byte, models.MaxLogoSize+1), LogoFormat: "PNG"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.branding.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
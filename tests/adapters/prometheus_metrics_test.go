@@ -0,0 +1,158 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	webhookModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/metrics"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// metricValue obtiene el valor de un contador o la cantidad de observaciones de un histograma con las etiquetas indicadas
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			matches := 0
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] == label.GetValue() {
+					matches++
+				}
+			}
+			if matches != len(labels) {
+				continue
+			}
+
+			if metric.GetHistogram() != nil {
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestPrometheusRouteTemplateLabels(t *testing.T) {
+	test.TestMain(t)
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewPrometheusMiddleware().Handler)
+	router.HandleFunc("/api/v1/dte/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+
+	labels := map[string]string{"method": "GET", "route": "/api/v1/dte/{id}", "status": "404"}
+	before := metricValue(t, "http_request_duration_seconds", labels)
+
+	// Cada identificador se registra en la misma serie de la plantilla de la ruta
+	for _, id := range []string{"0B9F7E1C-2D3A-4B5C-8D9E-0F1A2B3C4D5E", "6A7B8C9D-0E1F-4A2B-9C3D-4E5F6A7B8C9D"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dte/"+id, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+
+	assert.Equal(t, before+2, metricValue(t, "http_request_duration_seconds", labels))
+}
+
+func TestDTEEventRecorder(t *testing.T) {
+	test.TestMain(t)
+
+	recorder := metrics.NewDTEEventRecorder()
+
+	tests := []struct {
+		name   string
+		event  *webhookModels.Event
+		labels map[string]string
+	}{
+		{
+			name:   "Issued online",
+			event:  &webhookModels.Event{Type: webhookModels.EventDTEIssued, Data: map[string]interface{}{"dte_type": "01"}},
+			labels: map[string]string{"dte_type": "01", "status": "RECEIVED", "transmission": "NORMAL"},
+		},
+		{
+			name:   "Received in contingency batch",
+			event:  &webhookModels.Event{Type: webhookModels.EventDTEReceived, Data: map[string]interface{}{"dte_type": "03", "batch_id": "batch-1"}},
+			labels: map[string]string{"dte_type": "03", "status": "RECEIVED", "transmission": "CONTINGENCY"},
+		},
+		{
+			name:   "Rejected online",
+			event:  &webhookModels.Event{Type: webhookModels.EventDTERejected, Data: map[string]interface{}{"dte_type": "05"}},
+			labels: map[string]string{"dte_type": "05", "status": "REJECTED", "transmission": "NORMAL"},
+		},
+		{
+			name:   "Rejected in contingency batch",
+			event:  &webhookModels.Event{Type: webhookModels.EventDTERejected, Data: map[string]interface{}{"dte_type": "05", "batch_id": "batch-1"}},
+			labels: map[string]string{"dte_type": "05", "status": "REJECTED", "transmission": "CONTINGENCY"},
+		},
+		{
+			name:   "Stored in contingency",
+			event:  &webhookModels.Event{Type: webhookModels.EventDTEContingencyStored, Data: map[string]interface{}{"dte_type": "07"}},
+			labels: map[string]string{"dte_type": "07", "status": "PENDING", "transmission": "CONTINGENCY"},
+		},
+		{
+			name:   "Invalidated",
+			event:  &webhookModels.Event{Type: webhookModels.EventDTEInvalidated, Data: map[string]interface{}{"dte_type": "01"}},
+			labels: map[string]string{"dte_type": "01", "status": "INVALIDATED", "transmission": "NORMAL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := metricValue(t, "dte_documents_total", tt.labels)
+			recorder.HandleEvent(context.Background(), tt.event)
+			assert.Equal(t, before+1, metricValue(t, "dte_documents_total", tt.labels))
+		})
+	}
+
+	// Los eventos que no cambian el estado de un DTE no se cuentan
+	before := metricValue(t, "dte_documents_total", map[string]string{"dte_type": "unknown"})
+	recorder.HandleEvent(context.Background(), &webhookModels.Event{Type: webhookModels.EventBatchCompleted, Data: map[string]interface{}{}})
+	assert.Equal(t, before, metricValue(t, "dte_documents_total", map[string]string{"dte_type": "unknown"}))
+}
+
+func TestPrometheusHandlerToken(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "Without token configured", wantStatus: http.StatusOK},
+		{name: "Valid token", token: "scrape-token", authorization: "Bearer scrape-token", wantStatus: http.StatusOK},
+		{name: "Missing token", token: "scrape-token", wantStatus: http.StatusUnauthorized},
+		{name: "Invalid token", token: "scrape-token", authorization: "Bearer other-token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			metrics.PrometheusHandler(tt.token).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), "contingency_queue_depth")
+			}
+		})
+	}
+}
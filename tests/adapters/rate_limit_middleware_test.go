@@ -0,0 +1,244 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// memoryRateLimiter implementa la ventana deslizante en memoria
+type memoryRateLimiter struct {
+	events map[string][]time.Time
+	locks  map[string]time.Time
+}
+
+func newMemoryRateLimiter() *memoryRateLimiter {
+	return &memoryRateLimiter{
+		events: make(map[string][]time.Time),
+		locks:  make(map[string]time.Time),
+	}
+}
+
+func (m *memoryRateLimiter) Allow(_ context.Context, key string, limit int, window time.Duration) (*ports.RateLimitResult, error) {
+	now := time.Now()
+	events := make([]time.Time, 0, len(m.events[key]))
+	for _, event := range m.events[key] {
+		if now.Sub(event) < window {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) >= limit {
+		m.events[key] = events
+		return &ports.RateLimitResult{Limit: limit, RetryAfter: events[0].Add(window).Sub(now)}, nil
+	}
+
+	m.events[key] = append(events, now)
+	return &ports.RateLimitResult{Allowed: true, Limit: limit, Remaining: limit - len(events) - 1}, nil
+}
+
+func (m *memoryRateLimiter) Reset(_ context.Context, key string) error {
+	delete(m.events, key)
+	return nil
+}
+
+func (m *memoryRateLimiter) Lock(_ context.Context, key string, ttl time.Duration) error {
+	m.locks[key] = time.Now().Add(ttl)
+	return nil
+}
+
+func (m *memoryRateLimiter) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	if remaining := time.Until(m.locks[key]); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+// quotaAuthManager retorna la sucursal con la cuota indicada
+type quotaAuthManager struct {
+	auth.AuthManager
+	quota *int
+}
+
+func (q *quotaAuthManager) GetBranchByBranchID(_ context.Context, branchID uint) (*user.BranchOffice, error) {
+	return &user.BranchOffice{ID: branchID, RateLimitPerMinute: q.quota}, nil
+}
+
+func TestLoginThrottleMiddleware(t *testing.T) {
+	test.TestMain(t)
+
+	limiter := newMemoryRateLimiter()
+	throttle := middleware.NewLoginThrottleMiddleware(limiter)
+	requestContext := middleware.NewRequestContextMiddleware()
+
+	// El inicio de sesión solo es exitoso con el secreto correcto
+	login := requestContext.Handler(throttle.Handle(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"api_secret":"valid"`) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	attempt := func(apiKey, secret, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"api_key":"`+apiKey+`","api_secret":"`+secret+`"}`))
+		req.RemoteAddr = ip + ":51234"
+		rec := httptest.NewRecorder()
+		login.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// 1. Un inicio de sesión exitoso reinicia el conteo de la API key
+	for i := 0; i < config.RateLimit.LoginMaxAttemptsPerKey-1; i++ {
+		assert.Equal(t, http.StatusBadRequest, attempt("key-1", "wrong", "10.0.0.1").Code)
+	}
+	assert.Equal(t, http.StatusOK, attempt("key-1", "valid", "10.0.0.1").Code)
+
+	// 2. Al alcanzar el máximo de intentos fallidos la API key se bloquea, incluso con el secreto correcto
+	for i := 0; i < config.RateLimit.LoginMaxAttemptsPerKey; i++ {
+		assert.Equal(t, http.StatusBadRequest, attempt("key-1", "wrong", "10.0.0.2").Code)
+	}
+
+	rec := attempt("key-1", "valid", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// 3. La IP se bloquea al probar distintas API keys
+	for i := 0; i < config.RateLimit.LoginMaxAttemptsPerIP; i++ {
+		attempt(fmt.Sprintf("guess-%d", i), "wrong", "10.0.0.9")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, attempt("key-2", "valid", "10.0.0.9").Code)
+	assert.Equal(t, http.StatusOK, attempt("key-2", "valid", "10.0.0.10").Code)
+}
+
+func TestLoginThrottleBodyTooLarge(t *testing.T) {
+	test.TestMain(t)
+
+	called := false
+	login := middleware.NewLoginThrottleMiddleware(newMemoryRateLimiter()).Handle(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// El cuerpo que supera el límite se rechaza en lugar de truncarse y entregarse incompleto al inicio de sesión
+	body := `{"api_key":"key-1","api_secret":"valid","padding":"` + strings.Repeat("a", 64<<10) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	login.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.False(t, called)
+}
+
+func TestLoginThrottleForwardedFor(t *testing.T) {
+	test.TestMain(t)
+
+	trustedProxies := config.Server.TrustedProxies
+	config.Server.TrustedProxies = "10.0.0.100, 192.168.0.0/16"
+	t.Cleanup(func() { config.Server.TrustedProxies = trustedProxies })
+
+	throttle := middleware.NewLoginThrottleMiddleware(newMemoryRateLimiter())
+	login := middleware.NewRequestContextMiddleware().Handler(throttle.Handle(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	attempt := func(apiKey, remoteIP string, headers map[string]string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"api_key":"`+apiKey+`","api_secret":"wrong"}`))
+		req.RemoteAddr = remoteIP + ":51234"
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		login.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	lockIP := func(remoteIP string, headers map[string]string) {
+		for i := 0; i < config.RateLimit.LoginMaxAttemptsPerIP; i++ {
+			assert.Equal(t, http.StatusBadRequest, attempt(fmt.Sprintf("guess-%s-%d", remoteIP, i), remoteIP, headers))
+		}
+	}
+
+	// 1. Un cliente directo no puede cambiar su IP con las cabeceras del proxy
+	lockIP("10.0.0.20", nil)
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{name: "Without headers"},
+		{name: "Spoofed X-Forwarded-For", headers: map[string]string{"X-Forwarded-For": "203.0.113.77"}},
+		{name: "Spoofed X-Real-IP", headers: map[string]string{"X-Real-IP": "203.0.113.78"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusTooManyRequests, attempt("other-key", "10.0.0.20", tt.headers))
+		})
+	}
+
+	// 2. Detrás de un proxy de confianza se utiliza la IP que agregó el proxy, no las que antepone el cliente
+	lockIP("10.0.0.100", map[string]string{"X-Forwarded-For": "203.0.113.5"})
+	assert.Equal(t, http.StatusTooManyRequests, attempt("other-key", "10.0.0.100", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.5"}))
+	assert.Equal(t, http.StatusTooManyRequests, attempt("other-key", "10.0.0.100", map[string]string{"X-Forwarded-For": "203.0.113.5, 192.168.1.10"}))
+	assert.Equal(t, http.StatusBadRequest, attempt("other-key", "10.0.0.100", map[string]string{"X-Forwarded-For": "203.0.113.6"}))
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	test.TestMain(t)
+
+	quota := func(limit int) *int { return &limit }
+
+	tests := []struct {
+		name         string
+		quota        *int
+		requests     int
+		wantAccepted int
+	}{
+		{name: "Default quota", quota: nil, requests: config.RateLimit.DTERequestsPerMinute + 2, wantAccepted: config.RateLimit.DTERequestsPerMinute},
+		{name: "Branch quota", quota: quota(3), requests: 5, wantAccepted: 3},
+		{name: "Unlimited branch", quota: quota(0), requests: 10, wantAccepted: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := middleware.NewRateLimitMiddleware(newMemoryRateLimiter(), &quotaAuthManager{quota: tt.quota})
+			handler := limits.Limit(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			accepted := 0
+			var last *httptest.ResponseRecorder
+			for i := 0; i < tt.requests; i++ {
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/dte/invoices", nil)
+				req = req.WithContext(context.WithValue(req.Context(), "claims", &authModels.AuthClaims{ClientID: 7, BranchID: 3}))
+				last = httptest.NewRecorder()
+				handler.ServeHTTP(last, req)
+				if last.Code == http.StatusOK {
+					accepted++
+				}
+			}
+
+			assert.Equal(t, tt.wantAccepted, accepted)
+			if tt.requests > tt.wantAccepted {
+				assert.Equal(t, http.StatusTooManyRequests, last.Code)
+				assert.NotEmpty(t, last.Header().Get("Retry-After"))
+				assert.Equal(t, "0", last.Header().Get("X-RateLimit-Remaining"))
+			}
+		})
+	}
+}
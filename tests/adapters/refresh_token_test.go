@@ -0,0 +1,237 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	authUseCase "github.com/MarlonG1/api-facturacion-sv/internal/application/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/service/strategies"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/ports"
+	vaultModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/audit"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/crypt"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/tokens"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/handlers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/routes"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// loginBranchRepository agrega a memoryBranchRepository el tipo de autenticación de las llaves
+type loginBranchRepository struct {
+	*memoryBranchRepository
+}
+
+func (r *loginBranchRepository) GetAuthTypeByApiKey(context.Context, string) (string, error) {
+	return r.owner.AuthType, nil
+}
+
+// sessionTestSetup contiene el servicio de autenticación con las dependencias reales y las credenciales de una sucursal
+type sessionTestSetup struct {
+	manager      auth.AuthManager
+	jwtService   *tokens.JWTService
+	cacheManager ports.CacheManager
+	credentials  *authModels.AuthCredentials
+}
+
+func newSessionTestSetup(t *testing.T) *sessionTestSetup {
+	cacheManager := newMemoryRedisCache(t)
+	jwtService := tokens.NewJWTService("session-test-secret", cacheManager)
+	cryptService := crypt.NewCryptService()
+
+	keys, _, err := config.ParseVaultMasterKeys("v1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	require.NoError(t, err)
+	credentialVault, err := crypt.NewCredentialVaultService(&memoryVaultRepository{records: make(map[uint]vaultModels.SealedCredentials)}, keys, "v1")
+	require.NoError(t, err)
+
+	secret, err := cryptService.GenerateAPISecret()
+	require.NoError(t, err)
+
+	repo := &loginBranchRepository{&memoryBranchRepository{
+		branches: map[string]*user.BranchOffice{
+			"branch-key": {ID: 1, UserID: 1, APISecret: cryptService.HashAPISecret(secret), IsActive: true},
+		},
+		owner: &user.User{ID: 1, NIT: "06142803901121", Status: true, AuthType: constants.StandardAuthType, TokenLifetime: 1},
+	}}
+
+	return &sessionTestSetup{
+		manager:      strategies.NewAuthService(jwtService, repo, cacheManager, cryptService, credentialVault, audit.NewAuditService(&memoryAuditRepository{})),
+		jwtService:   jwtService,
+		cacheManager: cacheManager,
+		credentials: &authModels.AuthCredentials{
+			APIKey:        "branch-key",
+			APISecret:     secret,
+			MHCredentials: &authModels.HaciendaCredentials{Username: "06142803901121", Password: "MH-password"},
+		},
+	}
+}
+
+func (s *sessionTestSetup) login(t *testing.T) *authModels.TokenResponse {
+	session, err := s.manager.Login(context.Background(), s.credentials)
+	require.NoError(t, err)
+	require.NotEmpty(t, session.RefreshToken)
+	return session
+}
+
+func TestConsumeRefreshToken(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	jwtService := tokens.NewJWTService("session-test-secret", newMemoryRedisCache(t))
+	session := &authModels.RefreshSession{FamilyID: "family-1", AccessToken: "access-1", Claims: authModels.AuthClaims{ClientID: 1, BranchID: 1}}
+	refreshToken, err := jwtService.GenerateRefreshToken(ctx, session, time.Duration(config.DefaultRefreshLifetime)*24*time.Hour)
+	require.NoError(t, err)
+
+	invalidRefreshToken := shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "InvalidRefreshToken").Error()
+
+	// 1. Un refresh token desconocido se rechaza
+	_, err = jwtService.ConsumeRefreshToken(ctx, "unknown-refresh-token")
+	assert.EqualError(t, err, invalidRefreshToken)
+
+	// 2. El primer uso retorna la sesión, los siguientes la marcan como reutilizada
+	consumed, err := jwtService.ConsumeRefreshToken(ctx, refreshToken)
+	require.NoError(t, err)
+	assert.False(t, consumed.Reused)
+	assert.Equal(t, "family-1", consumed.FamilyID)
+	assert.Equal(t, "access-1", consumed.AccessToken)
+
+	consumed, err = jwtService.ConsumeRefreshToken(ctx, refreshToken)
+	require.NoError(t, err)
+	assert.True(t, consumed.Reused)
+
+	// 3. Al revocar la familia se retorna su último access token y sus refresh tokens dejan de aceptarse
+	latest, err := jwtService.RevokeRefreshFamily(ctx, "family-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", latest)
+
+	_, err = jwtService.ConsumeRefreshToken(ctx, refreshToken)
+	assert.EqualError(t, err, invalidRefreshToken)
+}
+
+func TestAuthRefreshRotation(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	setup := newSessionTestSetup(t)
+	first := setup.login(t)
+	other := setup.login(t)
+
+	// 1. Al renovar la sesión se emiten tokens nuevos y el access token anterior deja de ser válido
+	rotated, err := setup.manager.Refresh(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Token, rotated.Token)
+	assert.NotEqual(t, first.RefreshToken, rotated.RefreshToken)
+
+	_, err = setup.jwtService.ValidateToken(ctx, first.Token)
+	assert.Error(t, err)
+	_, err = setup.cacheManager.GetCredentials(first.Token)
+	assert.Error(t, err)
+
+	claims, err := setup.jwtService.ValidateToken(ctx, rotated.Token)
+	require.NoError(t, err)
+	otherClaims, err := setup.jwtService.ValidateToken(ctx, other.Token)
+	require.NoError(t, err)
+	assert.NotEqual(t, otherClaims.SessionID, claims.SessionID)
+
+	creds, err := setup.cacheManager.GetCredentials(rotated.Token)
+	require.NoError(t, err)
+	assert.Equal(t, setup.credentials.MHCredentials, creds)
+
+	// 2. Reutilizar un refresh token revoca la sesión completa, incluidos los tokens emitidos al renovarla
+	_, err = setup.manager.Refresh(ctx, first.RefreshToken)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("AuthService", "Refresh", "RefreshTokenReused").Error())
+
+	_, err = setup.jwtService.ValidateToken(ctx, rotated.Token)
+	assert.Error(t, err)
+	_, err = setup.manager.Refresh(ctx, rotated.RefreshToken)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "InvalidRefreshToken").Error())
+
+	// 3. Las demás sesiones de la sucursal no se ven afectadas
+	_, err = setup.jwtService.ValidateToken(ctx, other.Token)
+	assert.NoError(t, err)
+	_, err = setup.manager.Refresh(ctx, other.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthLogout(t *testing.T) {
+	test.TestMain(t)
+
+	ctx := context.Background()
+	setup := newSessionTestSetup(t)
+	session := setup.login(t)
+
+	handler := middleware.NewAuthMiddleware(setup.jwtService, setup.manager).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	authorize := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/dte", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusOK, authorize(session.Token))
+
+	// 1. Cerrar sesión revoca el access token, AuthMiddleware deja de aceptarlo
+	claims, err := setup.jwtService.ValidateToken(ctx, session.Token)
+	require.NoError(t, err)
+	require.NoError(t, setup.manager.Logout(ctx, session.Token, claims))
+	assert.Equal(t, http.StatusUnauthorized, authorize(session.Token))
+
+	// 2. Las credenciales de Hacienda de la sesión y su refresh token se eliminan
+	_, err = setup.cacheManager.GetCredentials(session.Token)
+	assert.Error(t, err)
+	_, err = setup.manager.Refresh(ctx, session.RefreshToken)
+	assert.EqualError(t, err, shared_error.NewFormattedGeneralServiceError("JWTService", "ConsumeRefreshToken", "InvalidRefreshToken").Error())
+}
+
+func TestAuthLoginResponse(t *testing.T) {
+	test.TestMain(t)
+
+	setup := newSessionTestSetup(t)
+	router := mux.NewRouter()
+	handler := handlers.NewAuthHandler(authUseCase.NewAuthUseCase(setup.manager, crypt.NewCryptService()))
+	routes.RegisterPublicAuthRoutes(router, handler, middleware.NewLoginThrottleMiddleware(newMemoryRateLimiter()))
+
+	body, err := json.Marshal(setup.credentials)
+	require.NoError(t, err)
+	login := func(path string) []byte {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.Bytes()
+	}
+
+	// 1. El inicio de sesión conserva el formato anterior, data contiene solo el access token
+	var legacy struct {
+		Data string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(login("/auth/login"), &legacy))
+	_, err = setup.jwtService.ValidateToken(context.Background(), legacy.Data)
+	assert.NoError(t, err)
+
+	// 2. El inicio de sesión con refresh token retorna ambos tokens
+	var session struct {
+		Data authModels.TokenResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(login("/auth/token"), &session))
+	_, err = setup.jwtService.ValidateToken(context.Background(), session.Data.Token)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session.Data.RefreshToken)
+	assert.Equal(t, "Bearer", session.Data.TokenType)
+}
@@ -0,0 +1,78 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestScopeMiddleware(t *testing.T) {
+	test.TestMain(t)
+
+	handler := middleware.NewScopeMiddleware().Require(constants.ScopeDTEIssue, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		claims *models.AuthClaims
+		want   int
+	}{
+		{name: "Primary key has every scope", claims: &models.AuthClaims{Scopes: constants.AllScopes}, want: http.StatusOK},
+		{name: "Legacy token without scopes", claims: &models.AuthClaims{}, want: http.StatusOK},
+		{name: "Read-only key", claims: &models.AuthClaims{KeyID: 1, Scopes: []string{constants.ScopeDTERead}}, want: http.StatusForbidden},
+		{name: "Key with empty scopes", claims: &models.AuthClaims{KeyID: 2, Scopes: []string{}}, want: http.StatusForbidden},
+		{name: "Missing claims", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/dte/invoice", nil)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "claims", tt.claims))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestBranchAPIKeyValidate(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name       string
+		key        *user.BranchAPIKey
+		wantScopes []string
+		wantErr    bool
+	}{
+		{name: "Read-only key", key: &user.BranchAPIKey{Name: "Accounting", Scopes: []string{constants.ScopeDTERead, constants.ScopeDTEReadAllBranches}}, wantScopes: []string{constants.ScopeDTERead, constants.ScopeDTEReadAllBranches}},
+		{name: "Duplicated scopes are removed", key: &user.BranchAPIKey{Name: "POS", Scopes: []string{constants.ScopeDTEIssue, constants.ScopeDTEIssue}}, wantScopes: []string{constants.ScopeDTEIssue}},
+		{name: "Missing name", key: &user.BranchAPIKey{Name: "  ", Scopes: []string{constants.ScopeDTERead}}, wantErr: true},
+		{name: "Missing scopes", key: &user.BranchAPIKey{Name: "Accounting"}, wantErr: true},
+		{name: "Unknown scope", key: &user.BranchAPIKey{Name: "Accounting", Scopes: []string{"dte:delete"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.key.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantScopes, tt.key.Scopes)
+		})
+	}
+}
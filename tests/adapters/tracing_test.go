@@ -0,0 +1,130 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/tracing"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// setupSpanRecorder registra un proveedor de trazas que almacena los spans finalizados en memoria
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return recorder
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	test.TestMain(t)
+	recorder := setupSpanRecorder(t)
+
+	tracingMiddleware := middleware.NewTracingMiddleware()
+	router := mux.NewRouter()
+	router.Use(tracingMiddleware.Handler)
+	router.HandleFunc("/api/v1/dte/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "GenericDTEUseCase.Create")
+		span.End()
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	handler := tracingMiddleware.Wrap(router)
+
+	// La solicitud continúa la traza del encabezado traceparent
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dte/0B9F7E1C-2D3A-4B5C-8D9E-0F1A2B3C4D5E", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	useCase, server := spans[0], spans[1]
+	assert.Equal(t, "GET /api/v1/dte/{id}", server.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), useCase.Parent().SpanID())
+}
+
+func TestTracingSkipsStreamingRoutes(t *testing.T) {
+	test.TestMain(t)
+	recorder := setupSpanRecorder(t)
+
+	tracingMiddleware := middleware.NewTracingMiddleware()
+	router := mux.NewRouter()
+	router.Use(tracingMiddleware.Handler)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	middleware.StreamingRoute(router.HandleFunc("/api/v1/events/stream", ok).Methods(http.MethodGet), "events")
+	router.HandleFunc("/api/v1/dte", ok).Methods(http.MethodGet)
+	handler := tracingMiddleware.Wrap(router)
+
+	// Solo la ruta que no mantiene la conexión abierta genera el span de la solicitud
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/events/stream", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/dte", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/v1/dte", spans[0].Name())
+}
+
+func TestTracingRedisHook(t *testing.T) {
+	test.TestMain(t)
+	recorder := setupSpanRecorder(t)
+	hook := tracing.RedisHook{}
+
+	// Los comandos fuera de una traza no generan spans
+	cmd := redis.NewStringCmd(context.Background(), "get", "token")
+	ctx, err := hook.BeforeProcess(context.Background(), cmd)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcess(ctx, cmd))
+	assert.Empty(t, recorder.Ended())
+
+	// Dentro de una traza el comando se registra como hijo, una llave inexistente no es un error
+	parentCtx, parent := tracing.Start(context.Background(), "parent")
+	cmd.SetErr(redis.Nil)
+	ctx, err = hook.BeforeProcess(parentCtx, cmd)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcess(ctx, cmd))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "redis.get", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Empty(t, spans[0].Events())
+}
+
+func TestTracingDetach(t *testing.T) {
+	test.TestMain(t)
+	setupSpanRecorder(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := tracing.Start(ctx, "request")
+	defer span.End()
+
+	detached := tracing.Detach(ctx)
+	cancel()
+
+	assert.NoError(t, detached.Err())
+	assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(detached))
+}
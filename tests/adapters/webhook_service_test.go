@@ -0,0 +1,138 @@
+package adapters
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/webhook/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/webhook"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "No failed attempts", attempts: 0, want: 0},
+		{name: "First retry", attempts: 1, want: time.Minute},
+		{name: "Second retry", attempts: 2, want: 2 * time.Minute},
+		{name: "Last retry", attempts: models.MaxDeliveryAttempts - 1, want: 16 * time.Minute},
+		{name: "Capped delay", attempts: 20, want: models.MaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.RetryDelay(tt.attempts))
+		})
+	}
+}
+
+func TestWebhookSignPayload(t *testing.T) {
+	test.TestMain(t)
+
+	secret := "webhook-secret"
+	body := []byte(`{"id":"EVENT","event":"dte.issued","branch_id":1,"data":{}}`)
+	timestamp := int64(1700000000)
+
+	// El receptor calcula la firma con el secret que recibió al crear la suscripción
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("1700000000\n"))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	signature := webhook.SignPayload(secret, timestamp, body)
+
+	assert.Equal(t, expected, signature)
+	assert.NotEqual(t, signature, webhook.SignPayload(secret, timestamp+1, body))
+	assert.NotEqual(t, signature, webhook.SignPayload("other-secret", timestamp, body))
+	assert.NotEqual(t, signature, webhook.SignPayload(utils.HashAPISecret(secret), timestamp, body))
+}
+
+func TestWebhookSubscriptionValidate(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name         string
+		subscription *models.Subscription
+		wantErr      bool
+	}{
+		{name: "Valid subscription", subscription: &models.Subscription{URL: "https://erp.test/hooks", Events: []string{models.EventDTEIssued, models.EventBatchCompleted}}},
+		{name: "Relative URL", subscription: &models.Subscription{URL: "/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Unsupported scheme", subscription: &models.Subscription{URL: "ftp://erp.test/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Without events", subscription: &models.Subscription{URL: "https://erp.test/hooks"}, wantErr: true},
+		{name: "Unknown event", subscription: &models.Subscription{URL: "https://erp.test/hooks", Events: []string{"dte.deleted"}}, wantErr: true},
+		{name: "Public address", subscription: &models.Subscription{URL: "https://8.8.8.8/hooks", Events: []string{models.EventDTEIssued}}},
+		{name: "Loopback address", subscription: &models.Subscription{URL: "https://127.0.0.1:8080/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "IPv6 loopback address", subscription: &models.Subscription{URL: "https://[::1]/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "IPv4-mapped loopback address", subscription: &models.Subscription{URL: "https://[::ffff:127.0.0.1]/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Metadata service", subscription: &models.Subscription{URL: "http://169.254.169.254/latest/meta-data", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Private address", subscription: &models.Subscription{URL: "https://10.0.0.5/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Shared address space", subscription: &models.Subscription{URL: "https://100.64.0.1/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Localhost", subscription: &models.Subscription{URL: "https://localhost/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Internal hostname", subscription: &models.Subscription{URL: "https://metadata.google.internal/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+		{name: "Hostname without domain", subscription: &models.Subscription{URL: "https://redis:6379/hooks", Events: []string{models.EventDTEIssued}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.subscription.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscriptionRequiresHTTPS(t *testing.T) {
+	test.TestMain(t)
+
+	subscription := &models.Subscription{URL: "http://erp.test/hooks", Events: []string{models.EventDTEIssued}}
+
+	// En modo de desarrollo se permite http para probar las entregas
+	assert.NoError(t, subscription.Validate())
+
+	config.Server.Debug = false
+	t.Cleanup(func() { config.Server.Debug = true })
+	test.AssertErrorCode(t, subscription.Validate(), "InvalidFormat")
+
+	subscription.URL = "https://erp.test/hooks"
+	assert.NoError(t, subscription.Validate())
+}
+
+func TestWebhookDeliveryClientRejectsInternalTargets(t *testing.T) {
+	test.TestMain(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := webhook.NewDeliveryClient()
+
+	// La dirección se verifica al conectarse, después de resolver el nombre del destino
+	for _, target := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://[::1]:9/hooks"} {
+		_, err := client.Post(target, "application/json", nil)
+		require.Error(t, err, target)
+		assert.ErrorIs(t, err, webhook.ErrTargetNotAllowed, target)
+	}
+
+	// Las redirecciones no se siguen, la respuesta 3xx se registra como un intento fallido
+	req := httptest.NewRequest(http.MethodPost, "https://erp.test/hooks", nil)
+	assert.Equal(t, http.ErrUseLastResponse, client.CheckRedirect(req, []*http.Request{req}))
+}
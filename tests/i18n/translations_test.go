@@ -0,0 +1,109 @@
+package i18n_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/dte_errors"
+	"github.com/MarlonG1/api-facturacion-sv/internal/i18n"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/middleware"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/api/response"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/shared_error"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+func TestSpanishKeysExistInEnglish(t *testing.T) {
+	test.TestMain(t)
+
+	english := make(map[string]bool)
+	for _, key := range i18n.Keys("en") {
+		english[key] = true
+	}
+
+	var missing []string
+	for _, key := range i18n.Keys("es") {
+		if !english[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	assert.NotEmpty(t, english)
+	assert.Empty(t, missing, "keys defined in es.yaml but missing in en.yaml")
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Empty header", header: "", want: ""},
+		{name: "Single language", header: "es", want: "es"},
+		{name: "Region subtag", header: "es-SV", want: "es"},
+		{name: "Quality order", header: "en;q=0.5, es-SV;q=0.9", want: "es"},
+		{name: "Header order on equal quality", header: "en-US, es", want: "en"},
+		{name: "Unsupported languages skipped", header: "fr-FR, de;q=0.9, es;q=0.1", want: "es"},
+		{name: "Rejected language", header: "es;q=0, en;q=0.2", want: "en"},
+		{name: "Only unsupported languages", header: "fr, *", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.ParseAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestErrorsUseRequestLanguage(t *testing.T) {
+	test.TestMain(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want func(lang string) string
+	}{
+		{
+			name: "Validation error",
+			err:  dte_errors.NewValidationError("RequiredField", "api_key"),
+			want: func(lang string) string { return constants.GetErrorMessageIn(lang, "RequiredField", "api_key") },
+		},
+		{
+			name: "Service error",
+			err:  shared_error.NewFormattedGeneralServiceError("HMACAuth", "AuthenticateRequest", "ExpiredSignature", 300),
+			want: func(lang string) string { return i18n.TranslateServiceArgsIn(lang, "ExpiredSignature", 300) },
+		},
+	}
+
+	writer := response.NewResponseWriter()
+	for _, tt := range tests {
+		assert.NotEqual(t, tt.want("es"), tt.want("en"))
+		for _, lang := range []string{"es", "en"} {
+			t.Run(tt.name+" "+lang, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Accept-Language", lang)
+
+				middleware.NewLanguageMiddleware().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					writer.HandleError(w, tt.err)
+				})).ServeHTTP(rec, req)
+
+				var body response.APIResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				require.NotNil(t, body.Error)
+
+				assert.Equal(t, lang, rec.Header().Get("Content-Language"))
+				assert.Equal(t, tt.want(lang), body.Error.Message)
+			})
+		}
+	}
+}
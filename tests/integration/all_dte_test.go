@@ -492,6 +492,9 @@ func TestAllDTETypes(t *testing.T) {
 						dteConfig.MapperConfig.RequestMapperAdapter,
 						dteConfig.MapperConfig.ResponseMapper,
 						additionalOps,
+						nil,
+						nil,
+						nil,
 					)
 
 					// Configurar el handler
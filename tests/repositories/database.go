@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/MarlonG1/api-facturacion-sv/config"
+	"github.com/MarlonG1/api-facturacion-sv/config/drivers"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	"github.com/MarlonG1/api-facturacion-sv/tests"
+)
+
+// testDatabase conexión de prueba con una sucursal registrada
+type testDatabase struct {
+	DB       *gorm.DB
+	UserID   uint
+	BranchID uint
+}
+
+// forEachDriver ejecuta la prueba con cada driver soportado sobre un esquema recién migrado. SQLite se ejecuta siempre
+// en un archivo temporal, MySQL y Postgres solo si se indica la conexión en TEST_MYSQL_DSN y TEST_POSTGRES_DSN. Las
+// bases de datos indicadas se vacían revirtiendo todas sus migraciones
+func forEachDriver(t *testing.T, fn func(t *testing.T, tdb *testDatabase)) {
+	test.TestMain(t)
+
+	dialectors := map[string]func(t *testing.T) gorm.Dialector{
+		"sqlite": func(t *testing.T) gorm.Dialector {
+			config.Database.Name = filepath.Join(t.TempDir(), "dte.db")
+			return drivers.NewSqliteDriver().GetDSN()
+		},
+		"mysql": func(t *testing.T) gorm.Dialector {
+			return mysql.Open(envDSN(t, "TEST_MYSQL_DSN"))
+		},
+		"postgres": func(t *testing.T) gorm.Dialector {
+			return postgres.Open(envDSN(t, "TEST_POSTGRES_DSN"))
+		},
+	}
+
+	for _, name := range []string{"sqlite", "mysql", "postgres"} {
+		t.Run(name, func(t *testing.T) {
+			db, err := gorm.Open(dialectors[name](t), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				if sqlDB, err := db.DB(); err == nil {
+					_ = sqlDB.Close()
+				}
+			})
+
+			fn(t, &testDatabase{DB: db})
+		})
+	}
+}
+
+// forEachMigratedDriver ejecuta la prueba con cada driver después de aplicar las migraciones y registrar un
+// contribuyente con una sucursal
+func forEachMigratedDriver(t *testing.T, fn func(t *testing.T, tdb *testDatabase)) {
+	forEachDriver(t, func(t *testing.T, tdb *testDatabase) {
+		migrator, err := database.NewMigrator(tdb.DB)
+		require.NoError(t, err)
+
+		_, err = migrator.Down(int(migrator.LatestVersion()))
+		require.NoError(t, err)
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		user := &db_models.User{
+			NIT:                  "06140101001011",
+			NRC:                  "1234567",
+			Status:               true,
+			AuthType:             "password",
+			PasswordPri:          "secret",
+			CommercialName:       "Comercial de Prueba",
+			EconomicActivity:     "46900",
+			EconomicActivityDesc: "Venta al por mayor",
+			Business:             "Comercial de Prueba S.A. de C.V.",
+			Email:                "contribuyente@example.com",
+			Phone:                "22223333",
+			TokenLifetime:        14,
+			RegistrationStatus:   "APPROVED",
+		}
+		require.NoError(t, tdb.DB.Create(user).Error)
+
+		branch := &db_models.BranchOffice{
+			UserID:            user.ID,
+			APIKey:            "test-api-key",
+			APISecret:         "test-api-secret",
+			EstablishmentType: "02",
+			IsActive:          true,
+		}
+		require.NoError(t, tdb.DB.Create(branch).Error)
+
+		tdb.UserID = user.ID
+		tdb.BranchID = branch.ID
+		fn(t, tdb)
+	})
+}
+
+// envDSN obtiene la conexión de la variable de entorno, si no está definida la prueba del driver se omite
+func envDSN(t *testing.T, name string) string {
+	dsn := os.Getenv(name)
+	if dsn == "" {
+		t.Skipf("%s is not set", name)
+	}
+	return dsn
+}
@@ -0,0 +1,566 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errPackage "github.com/MarlonG1/api-facturacion-sv/config/error"
+	archiveModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/archive/models"
+	authModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/auth/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/dte"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/core/user"
+	"github.com/MarlonG1/api-facturacion-sv/internal/domain/dte/common/constants"
+	metricsModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/metrics/models"
+	operatorModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/operator/models"
+	vaultModels "github.com/MarlonG1/api-facturacion-sv/internal/domain/vault/models"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/adapters/repositories"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database"
+	"github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/database/db_models"
+	infraErrors "github.com/MarlonG1/api-facturacion-sv/internal/infrastructure/error"
+	"github.com/MarlonG1/api-facturacion-sv/pkg/shared/utils"
+)
+
+// testDocument DTE mínimo con los campos que leen las columnas generadas y las búsquedas JSON
+type testDocument struct {
+	GenerationCode string
+	ControlNumber  string
+	Receiver       map[string]interface{}
+	Summary        map[string]interface{}
+	Items          []map[string]interface{}
+	Status         string
+	Transmission   string
+	ReceptionStamp *string
+}
+
+var testDocuments = []testDocument{
+	{
+		GenerationCode: "A0000000-0000-0000-0000-000000000001",
+		ControlNumber:  "DTE-01-M001P001-000000000000001",
+		Receiver:       map[string]interface{}{"nit": "06140101001012", "nrc": "765432", "nombre": "Comercial ACME"},
+		Summary:        map[string]interface{}{"totalPagar": 113.00},
+		Items:          []map[string]interface{}{{"codigo": "P-001", "descripcion": "Café molido 100%"}},
+		Status:         constants.DocumentReceived,
+		Transmission:   constants.TransmissionNormal,
+		ReceptionStamp: utils.ToStringPointer("2024A1B2C3D4E5F6A7B8C9D0E1F2A3B4C5D6E7F8"),
+	},
+	{
+		GenerationCode: "A0000000-0000-0000-0000-000000000002",
+		ControlNumber:  "DTE-01-M001P001-000000000000002",
+		Receiver:       map[string]interface{}{"numDocumento": "012345678", "nombre": "Maria Lopez"},
+		Summary:        map[string]interface{}{"totalPagar": 25.50},
+		Items:          []map[string]interface{}{{"codigo": "P-002", "descripcion": "Azucar"}},
+		Status:         constants.DocumentRejected,
+		Transmission:   constants.TransmissionNormal,
+	},
+	{
+		GenerationCode: "A0000000-0000-0000-0000-000000000003",
+		ControlNumber:  "DTE-01-M001P001-000000000000003",
+		Receiver:       map[string]interface{}{"nombre": "Distribuidora_Norte"},
+		Summary:        map[string]interface{}{"montoTotalOperacion": 300.00},
+		Items:          []map[string]interface{}{{"codigo": "P-003", "descripcion": "Te"}, {"codigo": "P-001", "descripcion": "Café en grano"}},
+		Status:         constants.DocumentPending,
+		Transmission:   constants.TransmissionContingency,
+	},
+}
+
+// createDocuments registra los documentos de prueba en la sucursal con el repositorio de DTE
+func createDocuments(t *testing.T, tdb *testDatabase) context.Context {
+	ctx := context.WithValue(context.Background(), "claims", &authModels.AuthClaims{ClientID: tdb.UserID, BranchID: tdb.BranchID})
+	repo := repositories.NewDTERepository(tdb.DB)
+
+	for _, doc := range testDocuments {
+		document := map[string]interface{}{
+			"identificacion": map[string]interface{}{
+				"tipoDte":          constants.FacturaElectronica,
+				"numeroControl":    doc.ControlNumber,
+				"codigoGeneracion": doc.GenerationCode,
+			},
+			"receptor":        doc.Receiver,
+			"resumen":         doc.Summary,
+			"cuerpoDocumento": doc.Items,
+		}
+		require.NoError(t, repo.Create(ctx, document, doc.Transmission, doc.Status, doc.ReceptionStamp))
+	}
+
+	return ctx
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		migrator, err := database.NewMigrator(tdb.DB)
+		require.NoError(t, err)
+		require.NoError(t, migrator.CheckVersion())
+
+		// Revertir el esquema completo elimina las tablas y la versión queda atrasada
+		reverted, err := migrator.Down(int(migrator.LatestVersion()))
+		require.NoError(t, err)
+		assert.Equal(t, int(migrator.LatestVersion()), reverted)
+		assert.False(t, tdb.DB.Migrator().HasTable(&db_models.DTEDetails{}))
+		assert.Error(t, migrator.CheckVersion())
+
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		assert.Equal(t, int(migrator.LatestVersion()), applied)
+		assert.NoError(t, migrator.CheckVersion())
+	})
+}
+
+func TestMigrationsBaseline(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		migrator, err := database.NewMigrator(tdb.DB)
+		require.NoError(t, err)
+
+		// Una base de datos creada con AutoMigrate tiene el esquema de la versión 1 sin versiones registradas
+		_, err = migrator.Down(int(migrator.LatestVersion()) - 1)
+		require.NoError(t, err)
+		require.NoError(t, tdb.DB.Exec("DELETE FROM schema_migrations").Error)
+
+		// No se registra una versión cuyas tablas y columnas no existen
+		err = migrator.Baseline(migrator.LatestVersion())
+		require.ErrorIs(t, err, errPackage.ErrSchemaVersionMismatch)
+		assert.Contains(t, err.Error(), "branch_brandings")
+		assert.Contains(t, err.Error(), "users.registration_status")
+		current, _, err := migrator.CurrentVersion()
+		require.NoError(t, err)
+		assert.Zero(t, current)
+
+		require.NoError(t, migrator.Baseline(1))
+		applied, err := migrator.Up()
+		require.NoError(t, err)
+		assert.Equal(t, int(migrator.LatestVersion())-1, applied)
+		assert.NoError(t, migrator.CheckVersion())
+	})
+}
+
+func TestMigrationsKeepDomainEvents(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		migrator, err := database.NewMigrator(tdb.DB)
+		require.NoError(t, err)
+
+		// Los eventos y sus notificaciones registrados antes de la auditoría se conservan al hacer opcionales el
+		// contribuyente y la sucursal
+		_, err = migrator.Down(int(migrator.LatestVersion()) - 9)
+		require.NoError(t, err)
+		require.NoError(t, tdb.DB.Exec("INSERT INTO domain_events (id, user_id, branch_id, event_type, payload, occurred_at) VALUES (?, ?, ?, ?, ?, ?)",
+			1, tdb.UserID, tdb.BranchID, "CONTINGENCY", "{}", utils.TimeNow().Format("2006-01-02 15:04:05")).Error)
+		require.NoError(t, tdb.DB.Exec("INSERT INTO user_notifications (user_id, event_id, notification_type, message, delivery_status) VALUES (?, ?, ?, ?, ?)",
+			tdb.UserID, 1, "PUSH", "contingencia", "PENDING").Error)
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		var event db_models.DomainEvent
+		require.NoError(t, tdb.DB.First(&event, 1).Error)
+		assert.Equal(t, "SYSTEM", event.ActorType)
+		var notifications int64
+		require.NoError(t, tdb.DB.Model(&db_models.UserNotification{}).Where("event_id = ?", 1).Count(&notifications).Error)
+		assert.Equal(t, int64(1), notifications)
+
+		audit := &db_models.DomainEvent{EventType: "BRANCH_REJECTED", Payload: "{}", OccurredAt: utils.TimeNow().Format("2006-01-02 15:04:05")}
+		assert.NoError(t, tdb.DB.Create(audit).Error)
+	})
+}
+
+func TestControlNumberRepositoryGetNext(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := context.Background()
+		repo := repositories.NewControlNumberRepository(tdb.DB)
+
+		// Cada tipo de DTE tiene su propia secuencia
+		for want := 1; want <= 3; want++ {
+			number, err := repo.GetNext(ctx, constants.FacturaElectronica, tdb.BranchID)
+			require.NoError(t, err)
+			assert.Equal(t, want, number)
+		}
+		number, err := repo.GetNext(ctx, constants.CCFElectronico, tdb.BranchID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, number)
+
+		// Las solicitudes concurrentes nunca obtienen el mismo número
+		const workers = 10
+		numbers := make([]int, workers)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				number, err := repo.GetNext(ctx, constants.CCFElectronico, tdb.BranchID)
+				assert.NoError(t, err)
+				numbers[i] = number
+			}(i)
+		}
+		wg.Wait()
+
+		sort.Ints(numbers)
+		for i, number := range numbers {
+			assert.Equal(t, i+2, number)
+		}
+	})
+}
+
+func TestDTERepositorySearchFilters(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := createDocuments(t, tdb)
+		repo := repositories.NewDTERepository(tdb.DB)
+		minAmount, maxAmount := 100.0, 30.0
+
+		tests := []struct {
+			name    string
+			filters dte.DTEFilters
+			want    int64
+		}{
+			{name: "Receiver NIT", filters: dte.DTEFilters{ReceiverNIT: "06140101001012"}, want: 1},
+			{name: "Receiver document number", filters: dte.DTEFilters{ReceiverNIT: "012345678"}, want: 1},
+			{name: "Receiver NRC", filters: dte.DTEFilters{ReceiverNRC: "765432"}, want: 1},
+			{name: "Receiver name is case insensitive", filters: dte.DTEFilters{ReceiverName: "acme"}, want: 1},
+			{name: "Receiver name escapes wildcards", filters: dte.DTEFilters{ReceiverName: "_"}, want: 1},
+			{name: "Control number", filters: dte.DTEFilters{ControlNumber: "000000000000002"}, want: 1},
+			{name: "Reception stamp", filters: dte.DTEFilters{ReceptionStamp: "2024A1B2C3D4E5F6A7B8C9D0E1F2A3B4C5D6E7F8"}, want: 1},
+			{name: "Item code", filters: dte.DTEFilters{ItemCode: "P-001"}, want: 2},
+			{name: "Item description", filters: dte.DTEFilters{ItemDescription: "CAFÉ"}, want: 2},
+			{name: "Item description escapes wildcards", filters: dte.DTEFilters{ItemDescription: "100%"}, want: 1},
+			{name: "Minimum amount", filters: dte.DTEFilters{MinAmount: &minAmount}, want: 2},
+			{name: "Maximum amount", filters: dte.DTEFilters{MaxAmount: &maxAmount}, want: 1},
+			{name: "Status and transmission", filters: dte.DTEFilters{Status: constants.DocumentPending, Transmission: constants.TransmissionContingency}, want: 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				filters := tt.filters
+				filters.BranchID = tdb.BranchID
+
+				count, err := repo.GetTotalCount(ctx, &filters)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, count)
+			})
+		}
+
+		// Ordenamiento por las columnas generadas, los índices corresponden a testDocuments
+		sorts := []struct {
+			name      string
+			sortBy    string
+			sortOrder string
+			want      []int
+		}{
+			{name: "Amount ascending", sortBy: dte.SortByAmount, sortOrder: dte.SortAsc, want: []int{1, 0, 2}},
+			{name: "Control number descending", sortBy: dte.SortByControlNumber, sortOrder: dte.SortDesc, want: []int{2, 1, 0}},
+			{name: "Receiver name ascending", sortBy: dte.SortByReceiverName, sortOrder: dte.SortAsc, want: []int{0, 2, 1}},
+		}
+
+		for _, tt := range sorts {
+			t.Run(tt.name, func(t *testing.T) {
+				documents, err := repo.GetPagedDocuments(ctx, &dte.DTEFilters{BranchID: tdb.BranchID, SortBy: tt.sortBy, SortOrder: tt.sortOrder})
+				require.NoError(t, err)
+				require.Len(t, documents, len(tt.want))
+
+				for i, document := range documents {
+					var extractor utils.AuxiliarIdentificationExtractor
+					require.NoError(t, json.Unmarshal(document.Document, &extractor))
+					assert.Equal(t, testDocuments[tt.want[i]].GenerationCode, extractor.Identification.GenerationCode, i)
+				}
+			})
+		}
+	})
+}
+
+func TestBusinessMetricsRepository(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := createDocuments(t, tdb)
+		now := utils.TimeNow()
+		filters := &metricsModels.BusinessMetricsFilters{BranchID: tdb.BranchID, StartDate: now.Add(-time.Hour), EndDate: now.Add(time.Hour)}
+
+		// Respuesta de Hacienda del documento rechazado, registrada dos veces para reemplazar la primera
+		dteRepo := repositories.NewDTERepository(tdb.DB)
+		first, response := `{"codigoMsg":"001"}`, `{"codigoMsg":"004","descripcionMsg":"RECHAZADO"}`
+		require.NoError(t, dteRepo.SaveArtifacts(ctx, &dte.DTEArtifacts{GenerationCode: testDocuments[1].GenerationCode, MHResponse: &first}))
+		require.NoError(t, dteRepo.SaveArtifacts(ctx, &dte.DTEArtifacts{GenerationCode: testDocuments[1].GenerationCode, MHResponse: &response}))
+
+		// Documento pendiente en la cola de contingencia desde hace dos horas
+		createdAt := now.Add(-2 * time.Hour)
+		require.NoError(t, tdb.DB.Create(&db_models.ContingencyDocument{
+			ID:              "C0000000-0000-0000-0000-000000000001",
+			DocumentID:      testDocuments[2].GenerationCode,
+			BranchID:        tdb.BranchID,
+			ContingencyType: 1,
+			Reason:          "No disponibilidad de sistema del MH",
+			CreatedAt:       createdAt,
+			UpdatedAt:       createdAt,
+		}).Error)
+
+		repo := repositories.NewBusinessMetricsRepository(tdb.DB)
+
+		groups, err := repo.GetDTEGroups(ctx, filters)
+		require.NoError(t, err)
+		var total int64
+		var amount float64
+		for _, group := range groups {
+			total += group.Count
+			amount += group.Amount
+		}
+		assert.Equal(t, int64(3), total)
+		assert.InDelta(t, 438.50, amount, 0.001)
+
+		pending, err := repo.GetPendingContingency(ctx, filters, now)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, int64(1), pending[0].Pending)
+		assert.Equal(t, int64(0), pending[0].LessThanHour)
+		assert.Equal(t, int64(1), pending[0].LessThanDay)
+		require.NotNil(t, pending[0].OldestPendingAt)
+		assert.WithinDuration(t, createdAt, *pending[0].OldestPendingAt, time.Second)
+
+		codes, err := repo.GetTopRejectionCodes(ctx, filters, 10)
+		require.NoError(t, err)
+		require.Len(t, codes, 1)
+		assert.Equal(t, "004", codes[0].Code)
+		assert.Equal(t, "RECHAZADO", codes[0].Description)
+		assert.Equal(t, int64(1), codes[0].Count)
+	})
+}
+
+func TestArchiveRepositoryMaintenanceQueries(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := context.Background()
+		repo := repositories.NewArchiveRepository(tdb.DB)
+		now := utils.TimeNow()
+
+		create := func(id, status string, completedAt *time.Time) {
+			job := &archiveModels.ArchiveJob{
+				ID:        id,
+				BranchID:  tdb.BranchID,
+				ClientID:  tdb.UserID,
+				Scope:     archiveModels.ArchiveScopeBranch,
+				StartDate: now.AddDate(0, -1, 0),
+				EndDate:   now,
+				Status:    status,
+				CreatedAt: now,
+			}
+			require.NoError(t, repo.Create(ctx, job))
+			if completedAt != nil {
+				job.CompletedAt = completedAt
+				job.FilePath = "/archives/" + id + ".zip"
+				require.NoError(t, repo.Update(ctx, job))
+			}
+		}
+
+		old, recent := now.Add(-8*24*time.Hour), now.Add(-time.Hour)
+		create("PENDING", archiveModels.ArchivePending, nil)
+		create("PROCESSING", archiveModels.ArchiveProcessing, nil)
+		create("OLD", archiveModels.ArchiveCompleted, &old)
+		create("RECENT", archiveModels.ArchiveCompleted, &recent)
+		create("FAILED", archiveModels.ArchiveFailed, &old)
+
+		// Los trabajos interrumpidos de todas las sucursales
+		interrupted, err := repo.GetByStatus(ctx, archiveModels.ArchivePending, archiveModels.ArchiveProcessing)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(interrupted))
+		for _, job := range interrupted {
+			ids = append(ids, job.ID)
+		}
+		assert.ElementsMatch(t, []string{"PENDING", "PROCESSING"}, ids)
+
+		// Solo los trabajos completados antes de la fecha indicada
+		expired, err := repo.GetCompletedBefore(ctx, now.Add(-7*24*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		assert.Equal(t, "OLD", expired[0].ID)
+		assert.Equal(t, "/archives/OLD.zip", expired[0].FilePath)
+
+		// Solo un proceso reserva un trabajo hasta que su reserva vence
+		claimed, err := repo.ClaimJob(ctx, "PENDING", now, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		claimed, err = repo.ClaimJob(ctx, "PENDING", now.Add(time.Minute), now.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		claimed, err = repo.ClaimJob(ctx, "PENDING", now.Add(2*time.Hour), now.Add(4*time.Hour))
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		// Los trabajos en proceso sin reserva se pueden reservar y los finalizados no
+		claimed, err = repo.ClaimJob(ctx, "PROCESSING", now, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		claimed, err = repo.ClaimJob(ctx, "FAILED", now, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		job, err := repo.GetByID(ctx, tdb.BranchID, "PENDING")
+		require.NoError(t, err)
+		assert.Equal(t, archiveModels.ArchiveProcessing, job.Status)
+	})
+}
+
+func TestAuthRepositoryBranchOffices(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := context.Background()
+		repo := repositories.NewAuthRepository(tdb.DB)
+
+		// 1. Crear una sucursal con dirección para el contribuyente
+		branch := &user.BranchOffice{
+			APIKey:            "new-api-key",
+			APISecret:         "new-api-secret-hash",
+			APISigningSecret:  utils.ToStringPointer("sealed-secret"),
+			EstablishmentType: constants.Sucursal,
+			POSCode:           utils.ToStringPointer("P001"),
+			IsActive:          true,
+			Address:           &user.Address{Department: "06", Municipality: "14", Complement: "Colonia Escalón"},
+		}
+		require.NoError(t, repo.CreateBranchOffice(ctx, tdb.UserID, branch))
+		require.NotZero(t, branch.ID)
+
+		branches, err := repo.GetBranchOffices(ctx, tdb.UserID)
+		require.NoError(t, err)
+		require.Len(t, branches, 2)
+		assert.Equal(t, branch.ID, branches[1].ID)
+		require.NotNil(t, branches[1].Address)
+		assert.Equal(t, "Colonia Escalón", branches[1].Address.Complement)
+
+		// 2. Actualizar la sucursal conserva las llaves que no se envían
+		update := user.BranchOffice{ID: branch.ID, EstablishmentType: constants.Sucursal, POSCode: utils.ToStringPointer("P002")}
+		require.NoError(t, repo.UpdateBranchOffices(ctx, tdb.UserID, []user.BranchOffice{update}))
+
+		updated, err := repo.GetBranchByBranchID(ctx, branch.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "P002", *updated.POSCode)
+		assert.Equal(t, "new-api-key", updated.APIKey)
+		assert.Equal(t, "new-api-secret-hash", updated.APISecret)
+
+		// 2.1 Los campos opcionales pueden asignarse y retirarse
+		rateLimit := 30
+		update.RateLimitPerMinute = &rateLimit
+		update.ClientCertSubject = utils.ToStringPointer("CN=sucursal-01,O=Empresa")
+		require.NoError(t, repo.UpdateBranchOffices(ctx, tdb.UserID, []user.BranchOffice{update}))
+		updated, err = repo.GetBranchByBranchID(ctx, branch.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 30, *updated.RateLimitPerMinute)
+		assert.Equal(t, "CN=sucursal-01,O=Empresa", *updated.ClientCertSubject)
+
+		update.RateLimitPerMinute, update.ClientCertSubject = nil, nil
+		require.NoError(t, repo.UpdateBranchOffices(ctx, tdb.UserID, []user.BranchOffice{update}))
+		updated, err = repo.GetBranchByBranchID(ctx, branch.ID)
+		require.NoError(t, err)
+		assert.Nil(t, updated.RateLimitPerMinute)
+		assert.Nil(t, updated.ClientCertSubject)
+		assert.True(t, updated.IsActive)
+
+		// 3. Las sucursales de otro contribuyente no pueden modificarse
+		otherUserID := tdb.UserID + 100
+		assert.ErrorIs(t, repo.UpdateBranchOffices(ctx, otherUserID, []user.BranchOffice{update}), infraErrors.ErrBranchDoesNotBelong)
+		assert.ErrorIs(t, repo.SetBranchOfficeStatus(ctx, otherUserID, branch.ID, false), infraErrors.ErrBranchDoesNotBelong)
+		assert.ErrorIs(t, repo.UpdateBranchCredentials(ctx, otherUserID, branch.ID, "key", "secret", "sealed"), infraErrors.ErrBranchDoesNotBelong)
+
+		// 4. Rotar las llaves reemplaza el API key, las llaves anteriores dejan de identificar a la sucursal
+		require.NoError(t, repo.UpdateBranchCredentials(ctx, tdb.UserID, branch.ID, "rotated-api-key", "rotated-secret-hash", "rotated-sealed"))
+
+		_, err = repo.GetBranchByBranchApiKey(ctx, "new-api-key")
+		assert.ErrorIs(t, err, infraErrors.ErrBranchOfficeNotFound)
+		rotated, err := repo.GetBranchByBranchApiKey(ctx, "rotated-api-key")
+		require.NoError(t, err)
+		assert.Equal(t, branch.ID, rotated.ID)
+		assert.Equal(t, "rotated-secret-hash", rotated.APISecret)
+		assert.Equal(t, "rotated-sealed", *rotated.APISigningSecret)
+
+		// 5. Una sucursal desactivada no puede iniciar sesión
+		authType, err := repo.GetAuthTypeByApiKey(ctx, "rotated-api-key")
+		require.NoError(t, err)
+		assert.Equal(t, "password", authType)
+
+		require.NoError(t, repo.SetBranchOfficeStatus(ctx, tdb.UserID, branch.ID, false))
+		deactivated, err := repo.GetBranchByBranchID(ctx, branch.ID)
+		require.NoError(t, err)
+		assert.False(t, deactivated.IsActive)
+		_, err = repo.GetAuthTypeByApiKey(ctx, "rotated-api-key")
+		assert.Error(t, err)
+	})
+}
+
+func TestCredentialVaultRepositorySecrets(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := context.Background()
+		repo := repositories.NewCredentialVaultRepository(tdb.DB)
+
+		// 1. Registrar secrets cifrados con distintas versiones, las sucursales sin secret de firma se omiten
+		require.NoError(t, tdb.DB.Model(&db_models.BranchOffice{}).Where("id = ?", tdb.BranchID).
+			Update("api_signing_secret", "v1$branch").Error)
+		require.NoError(t, tdb.DB.Create(&db_models.BranchOffice{UserID: tdb.UserID, APIKey: "other-api-key", APISecret: "hash", EstablishmentType: constants.Sucursal, IsActive: true}).Error)
+
+		webhooks := []db_models.WebhookSubscription{
+			{UserID: tdb.UserID, URL: "https://example.com/1", Events: "dte.received", Secret: "v1$first", IsActive: true},
+			{UserID: tdb.UserID, URL: "https://example.com/2", Events: "dte.received", Secret: "v2$second", IsActive: true},
+			{UserID: tdb.UserID, URL: "https://example.com/3", Events: "dte.received", Secret: "v10$third", IsActive: true},
+		}
+		require.NoError(t, tdb.DB.Create(&webhooks).Error)
+
+		// 2. Solo se obtienen los secrets que no están cifrados con la versión activa, a partir del ID indicado
+		branches, err := repo.GetOutdatedSecrets(ctx, vaultModels.BranchSigningSecret, "v2", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, branches, 1)
+		assert.Equal(t, vaultModels.SealedSecret{Source: vaultModels.BranchSigningSecret, ID: tdb.BranchID, Value: "v1$branch"}, branches[0])
+
+		outdated, err := repo.GetOutdatedSecrets(ctx, vaultModels.WebhookSecret, "v2", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, outdated, 2)
+		assert.Equal(t, webhooks[0].ID, outdated[0].ID)
+		assert.Equal(t, "v10$third", outdated[1].Value)
+
+		outdated, err = repo.GetOutdatedSecrets(ctx, vaultModels.WebhookSecret, "v2", webhooks[0].ID, 10)
+		require.NoError(t, err)
+		require.Len(t, outdated, 1)
+		assert.Equal(t, webhooks[2].ID, outdated[0].ID)
+
+		keys, err := repo.GetOutdatedSecrets(ctx, vaultModels.APIKeySigningSecret, "v2", 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+
+		// 3. El secret solo se reemplaza si conserva el valor con el que se obtuvo
+		updated, err := repo.UpdateSecret(ctx, &branches[0], "v2$branch")
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		updated, err = repo.UpdateSecret(ctx, &branches[0], "v2$stale")
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		var branch db_models.BranchOffice
+		require.NoError(t, tdb.DB.First(&branch, tdb.BranchID).Error)
+		assert.Equal(t, "v2$branch", *branch.APISigningSecret)
+	})
+}
+
+func TestOperatorRepositoryRevocation(t *testing.T) {
+	forEachMigratedDriver(t, func(t *testing.T, tdb *testDatabase) {
+		ctx := context.Background()
+		repo := repositories.NewOperatorRepository(tdb.DB)
+
+		op := &operatorModels.Operator{Name: "Firma Contable", Email: "firma@example.com", APIKey: "operator-key", APISecret: "hash", IsActive: true}
+		require.NoError(t, repo.Create(ctx, op))
+		grant := &operatorModels.OperatorGrant{OperatorID: op.ID, UserID: tdb.UserID, BranchID: &tdb.BranchID}
+		require.NoError(t, repo.CreateGrant(ctx, grant))
+
+		// 1. Eliminar un acceso retorna el contribuyente y la sucursal del acceso
+		deleted, err := repo.DeleteGrant(ctx, op.ID, grant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tdb.UserID, deleted.UserID)
+		require.NotNil(t, deleted.BranchID)
+		assert.Equal(t, tdb.BranchID, *deleted.BranchID)
+
+		_, err = repo.DeleteGrant(ctx, op.ID, grant.ID)
+		assert.ErrorIs(t, err, infraErrors.ErrOperatorGrantNotFound)
+
+		// 2. Desactivar el operador
+		require.NoError(t, repo.Deactivate(ctx, op.ID))
+		stored, err := repo.GetByAPIKey(ctx, "operator-key")
+		require.NoError(t, err)
+		assert.False(t, stored.IsActive)
+
+		assert.ErrorIs(t, repo.Deactivate(ctx, op.ID+100), infraErrors.ErrOperatorNotFound)
+	})
+}
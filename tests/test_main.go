@@ -31,7 +31,7 @@ func TestMain(m *testing.T) {
 	}
 
 	// Inicializar logger
-	err = logs.InitLogger(logrus.DebugLevel.String(), config.Log.Path)
+	err = logs.InitLogger(logrus.DebugLevel.String(), config.Log.Path, logs.FormatText)
 	if err != nil {
 		panic("Error initializing logger: " + err.Error())
 	}